@@ -0,0 +1,8 @@
+package astiencoder
+
+// LockOSThreadToCPUs locks the current goroutine to its OS thread and pins the thread to the provided CPUs
+// Threads created by the OS thread while it's locked (e.g. codec threads) inherit its CPU affinity
+// unlock restores the previous CPU affinity and unlocks the OS thread
+func LockOSThreadToCPUs(cpus []int) (unlock func(), err error) {
+	return lockOSThreadToCPUs(cpus)
+}
@@ -0,0 +1,78 @@
+//go:build linux
+// +build linux
+
+package astiencoder
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const cpuSetMax = 1024
+
+type cpuSet [cpuSetMax / 64]uint64
+
+func newCPUSet(cpus []int) (s cpuSet, err error) {
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= cpuSetMax {
+			err = fmt.Errorf("astiencoder: invalid cpu %d", cpu)
+			return
+		}
+		s[cpu/64] |= 1 << uint(cpu%64)
+	}
+	return
+}
+
+func threadCPUSet() (s cpuSet, err error) {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0, unsafe.Sizeof(s), uintptr(unsafe.Pointer(&s))); errno != 0 {
+		err = fmt.Errorf("astiencoder: sched_getaffinity failed: %w", errno)
+	}
+	return
+}
+
+func setThreadCPUSet(s cpuSet) (err error) {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, unsafe.Sizeof(s), uintptr(unsafe.Pointer(&s))); errno != 0 {
+		err = fmt.Errorf("astiencoder: sched_setaffinity failed: %w", errno)
+	}
+	return
+}
+
+func lockOSThreadToCPUs(cpus []int) (unlock func(), err error) {
+	// Create cpu set
+	var s cpuSet
+	if s, err = newCPUSet(cpus); err != nil {
+		err = fmt.Errorf("astiencoder: creating cpu set failed: %w", err)
+		return
+	}
+
+	// Lock OS thread
+	runtime.LockOSThread()
+
+	// Get previous cpu set
+	var p cpuSet
+	if p, err = threadCPUSet(); err != nil {
+		runtime.UnlockOSThread()
+		err = fmt.Errorf("astiencoder: getting thread cpu set failed: %w", err)
+		return
+	}
+
+	// Set cpu set
+	if err = setThreadCPUSet(s); err != nil {
+		runtime.UnlockOSThread()
+		err = fmt.Errorf("astiencoder: setting thread cpu set failed: %w", err)
+		return
+	}
+
+	// Create unlock func
+	unlock = func() {
+		// If the previous cpu set can't be restored, the thread stays locked so that it's terminated with the
+		// goroutine instead of being reused
+		if err := setThreadCPUSet(p); err != nil {
+			return
+		}
+		runtime.UnlockOSThread()
+	}
+	return
+}
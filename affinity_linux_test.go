@@ -0,0 +1,46 @@
+//go:build linux
+// +build linux
+
+package astiencoder
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockOSThreadToCPUs(t *testing.T) {
+	// Lock the OS thread during the whole test so that cpu sets are read on the same thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Get first allowed cpu
+	p, err := threadCPUSet()
+	assert.NoError(t, err)
+	cpu := -1
+	for i := 0; i < cpuSetMax && cpu < 0; i++ {
+		if p[i/64]&(1<<uint(i%64)) > 0 {
+			cpu = i
+		}
+	}
+	assert.True(t, cpu >= 0)
+
+	// Lock
+	unlock, err := LockOSThreadToCPUs([]int{cpu})
+	assert.NoError(t, err)
+	s, err := threadCPUSet()
+	assert.NoError(t, err)
+	e, _ := newCPUSet([]int{cpu})
+	assert.Equal(t, e, s)
+
+	// Unlock
+	unlock()
+	s, err = threadCPUSet()
+	assert.NoError(t, err)
+	assert.Equal(t, p, s)
+
+	// Invalid cpu
+	_, err = LockOSThreadToCPUs([]int{cpuSetMax})
+	assert.Error(t, err)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package astiencoder
+
+import "errors"
+
+func lockOSThreadToCPUs(cpus []int) (unlock func(), err error) {
+	err = errors.New("astiencoder: cpu affinity is not supported on this platform")
+	return
+}
@@ -0,0 +1,256 @@
+package astiencoder
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alert rule operators
+const (
+	AlertOperatorAbove = ">"
+	AlertOperatorBelow = "<"
+)
+
+// AlertRule represents a condition on a node stat
+// e.g. a rule with Stat "Outgoing rate", Operator "<", Threshold 20 and For 5s fires when the fps of a node drops
+// below 20 for 5s
+type AlertRule struct {
+	// Duration during which the condition must be met before the alert is fired, and must not be met before it is
+	// cleared, which prevents alerts from flapping
+	For  time.Duration
+	Name string
+	// If set, only the node with this name is checked
+	Node     string
+	Operator string
+	// Label of the stat, e.g. "Outgoing rate" or "Queue length"
+	Stat string
+	// If set, only nodes with this tag are checked
+	Tag       string
+	Threshold float64
+}
+
+func (r AlertRule) matches(md NodeMetadata) bool {
+	// Invalid name
+	if r.Node != "" && r.Node != md.Name {
+		return false
+	}
+
+	// No tag
+	if r.Tag == "" {
+		return true
+	}
+
+	// Loop through tags
+	for _, t := range md.Tags {
+		if t == r.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (r AlertRule) met(v float64) bool {
+	if r.Operator == AlertOperatorAbove {
+		return v > r.Threshold
+	}
+	return v < r.Threshold
+}
+
+// Alert represents an alert
+// It is the payload of the EventNameAlertFired and EventNameAlertCleared events whose target is the node
+type Alert struct {
+	Node string
+	Rule AlertRule
+	// Time at which the condition started to be met (fired) or stopped being met (cleared)
+	Since time.Time
+	// Last value of the stat
+	Value    float64
+	Workflow string
+}
+
+// Alerter represents an object capable of firing and clearing alerts based on rules over node stats, so that users
+// don't have to write their own comparison loops in event handlers
+type Alerter struct {
+	eh  *EventHandler
+	m   *sync.Mutex
+	now func() time.Time
+	rs  []AlertRule
+	ss  map[alerterKey]*alerterState
+}
+
+type alerterKey struct {
+	n    Node
+	rule int
+}
+
+type alerterState struct {
+	a       Alert
+	firing  bool
+	pending time.Time
+}
+
+// NewAlerter creates a new alerter fed by the stats events of the event handler
+func NewAlerter(eh *EventHandler) (a *Alerter) {
+	// Create alerter
+	a = &Alerter{
+		eh:  eh,
+		m:   &sync.Mutex{},
+		now: time.Now,
+		ss:  make(map[alerterKey]*alerterState),
+	}
+
+	// Handle events
+	eh.AddForEventName(EventNameNodeStats, func(e Event) bool {
+		if n, ok := e.Target.(Node); ok {
+			a.emit(a.handleStats(n, e.Payload.([]EventStat)))
+		}
+		return false
+	})
+	eh.AddForEventName(EventNameNodeStopped, func(e Event) bool {
+		if n, ok := e.Target.(Node); ok {
+			a.emit(a.handleStopped(n))
+		}
+		return false
+	})
+	return
+}
+
+// AddRule adds a rule
+func (a *Alerter) AddRule(r AlertRule) error {
+	// Check operator
+	if r.Operator != AlertOperatorAbove && r.Operator != AlertOperatorBelow {
+		return fmt.Errorf("astiencoder: invalid operator %s for rule %s", r.Operator, r.Name)
+	}
+
+	// Lock
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	// Append
+	a.rs = append(a.rs, r)
+	return nil
+}
+
+func (a *Alerter) handleStats(n Node, ss []EventStat) (es []Event) {
+	// Lock
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	// Loop through rules
+	now := a.now()
+	md := n.Metadata()
+	for idx, r := range a.rs {
+		// Node doesn't match
+		if !r.matches(md) {
+			continue
+		}
+
+		// Get value
+		var v float64
+		var found bool
+		for _, s := range ss {
+			if s.Label == r.Stat {
+				v, found = s.Value.(float64)
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		// Get state
+		k := alerterKey{n: n, rule: idx}
+		s, ok := a.ss[k]
+		if !ok {
+			s = &alerterState{a: Alert{
+				Node:     md.Name,
+				Rule:     r,
+				Workflow: nodeWorkflowName(n),
+			}}
+			a.ss[k] = s
+		}
+		s.a.Value = v
+
+		// State is unchanged
+		if r.met(v) == s.firing {
+			s.pending = time.Time{}
+			continue
+		}
+
+		// State has just started changing
+		if s.pending.IsZero() {
+			s.pending = now
+		}
+
+		// State has not changed for long enough
+		if now.Sub(s.pending) < r.For {
+			continue
+		}
+
+		// Update state
+		s.a.Since = s.pending
+		s.firing = !s.firing
+		s.pending = time.Time{}
+
+		// Create event
+		name := EventNameAlertCleared
+		if s.firing {
+			name = EventNameAlertFired
+		}
+		es = append(es, Event{Name: name, Payload: s.a, Target: n})
+	}
+	return
+}
+
+func (a *Alerter) handleStopped(n Node) (es []Event) {
+	// Lock
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	// Loop through states
+	for k, s := range a.ss {
+		// State is about another node
+		if k.n != n {
+			continue
+		}
+
+		// Alerts are cleared since the node won't report stats anymore
+		if s.firing {
+			s.a.Since = a.now()
+			es = append(es, Event{Name: EventNameAlertCleared, Payload: s.a, Target: n})
+		}
+		delete(a.ss, k)
+	}
+	return
+}
+
+func (a *Alerter) emit(es []Event) {
+	for _, e := range es {
+		a.eh.Emit(e)
+	}
+}
+
+// Alerts returns the alerts currently firing, sorted by node and rule
+func (a *Alerter) Alerts() (as []Alert) {
+	// Lock
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	// Loop through states
+	for _, s := range a.ss {
+		if s.firing {
+			as = append(as, s.a)
+		}
+	}
+
+	// Sort
+	sort.Slice(as, func(i, j int) bool {
+		if as[i].Node != as[j].Node {
+			return as[i].Node < as[j].Node
+		}
+		return as[i].Rule.Name < as[j].Rule.Name
+	})
+	return
+}
@@ -0,0 +1,86 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlerter(t *testing.T) {
+	eh := NewEventHandler()
+	a := NewAlerter(eh)
+	now := time.Unix(100, 0)
+	a.now = func() time.Time { return now }
+	assert.Error(t, a.AddRule(AlertRule{Name: "invalid", Operator: "="}))
+	assert.NoError(t, a.AddRule(AlertRule{For: 5 * time.Second, Name: "fps", Operator: AlertOperatorBelow, Stat: "Outgoing rate", Threshold: 20}))
+	assert.NoError(t, a.AddRule(AlertRule{Name: "queue", Operator: AlertOperatorAbove, Stat: "Queue length", Tag: "t", Threshold: 10}))
+
+	var es []Event
+	eh.AddForEventName(EventNameAlertCleared, func(e Event) bool {
+		es = append(es, e)
+		return false
+	})
+	eh.AddForEventName(EventNameAlertFired, func(e Event) bool {
+		es = append(es, e)
+		return false
+	})
+
+	n1 := newMockedStatsNode("n1", eh)
+	n1.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+	n2 := newMockedStatsNode("n2", eh)
+	n2.o.Metadata.Tags = []string{"t"}
+	stats := func(n Node, fps, queue float64) {
+		eh.Emit(Event{Name: EventNameNodeStats, Payload: []EventStat{
+			{Label: "Outgoing rate", Value: fps},
+			{Label: "Queue length", Value: queue},
+		}, Target: n})
+	}
+
+	// Condition must be met long enough
+	stats(n1, 25, 20)
+	stats(n1, 10, 20)
+	now = now.Add(3 * time.Second)
+	stats(n1, 25, 20)
+	now = now.Add(time.Second)
+	stats(n1, 10, 20)
+	now = now.Add(4 * time.Second)
+	stats(n1, 10, 20)
+	assert.Len(t, es, 0)
+	now = now.Add(time.Second)
+	stats(n1, 10, 20)
+	assert.Len(t, es, 1)
+	assert.Equal(t, EventNameAlertFired, es[0].Name)
+	assert.Equal(t, n1, es[0].Target)
+	assert.Equal(t, Alert{
+		Node:     "n1",
+		Rule:     AlertRule{For: 5 * time.Second, Name: "fps", Operator: AlertOperatorBelow, Stat: "Outgoing rate", Threshold: 20},
+		Since:    time.Unix(104, 0),
+		Value:    10,
+		Workflow: "w",
+	}, es[0].Payload)
+
+	// Tags are taken into account
+	stats(n2, 30, 20)
+	assert.Len(t, es, 2)
+	assert.Equal(t, EventNameAlertFired, es[1].Name)
+	assert.Equal(t, "queue", es[1].Payload.(Alert).Rule.Name)
+	as := a.Alerts()
+	assert.Len(t, as, 2)
+	assert.Equal(t, "n1", as[0].Node)
+	assert.Equal(t, "n2", as[1].Node)
+
+	// Alert is cleared
+	stats(n2, 30, 5)
+	assert.Len(t, es, 3)
+	assert.Equal(t, EventNameAlertCleared, es[2].Name)
+	assert.Equal(t, 5.0, es[2].Payload.(Alert).Value)
+
+	// Alerts are cleared when the node stops
+	eh.Emit(Event{Name: EventNameNodeStopped, Target: n1})
+	assert.Len(t, es, 4)
+	assert.Equal(t, EventNameAlertCleared, es[3].Name)
+	assert.Equal(t, "fps", es[3].Payload.(Alert).Rule.Name)
+	assert.Len(t, a.Alerts(), 0)
+}
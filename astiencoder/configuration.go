@@ -16,16 +16,89 @@ type Configuration struct {
 }
 
 type ConfigurationEncoder struct {
-	Exec   ConfigurationExec   `toml:"exec"`
-	Server ConfigurationServer `toml:"server"`
+	// Alerts are fired and cleared based on these rules over node stats
+	Alerts []ConfigurationAlertRule `toml:"alerts"`
+	// If set, error events are forwarded to an HTTP collector
+	ErrorReporting *ConfigurationErrorReporting `toml:"error_reporting"`
+	Exec           ConfigurationExec            `toml:"exec"`
+	// If set, libav logs are routed into the event stream instead of stderr
+	LibavLogs *ConfigurationLibavLogs `toml:"libav_logs"`
+	Server    ConfigurationServer     `toml:"server"`
+	// Events are posted to those webhooks
+	Webhooks []ConfigurationWebhook `toml:"webhooks"`
+}
+
+type ConfigurationAlertRule struct {
+	// In seconds
+	For       int     `toml:"for"`
+	Name      string  `toml:"name"`
+	Node      string  `toml:"node"`
+	Operator  string  `toml:"operator"`
+	Stat      string  `toml:"stat"`
+	Tag       string  `toml:"tag"`
+	Threshold float64 `toml:"threshold"`
+}
+
+type ConfigurationErrorReporting struct {
+	BatchSize int `toml:"batch_size"`
+	// In milliseconds
+	FlushPeriod int               `toml:"flush_period"`
+	Headers     map[string]string `toml:"headers"`
+	URL         string            `toml:"url"`
 }
 
 type ConfigurationExec struct {
+	// Max duration, in seconds, during which workflows are drained and events are flushed once a term signal is
+	// received. Default is 30
+	ShutdownTimeout             int  `toml:"shutdown_timeout"`
 	StopWhenWorkflowsAreStopped bool `toml:"stop_when_workflows_are_stopped"`
 }
 
+type ConfigurationLibavLogs struct {
+	// One of the AV_LOG_* values, default is 24 (warning)
+	Level int `toml:"level"`
+	// Max number of messages per node and per second
+	RateLimit int `toml:"rate_limit"`
+}
+
 type ConfigurationServer struct {
 	Addr string `toml:"addr"`
+	// If set, the health report is served under /health
+	Health *ConfigurationHealth `toml:"health"`
+	// If true, Prometheus metrics are served under /metrics
+	Metrics bool `toml:"metrics"`
+	// If set, pprof endpoints are served and profiles can be requested through events
+	Profiling *ConfigurationProfiling `toml:"profiling"`
+	// If set, the server is served over HTTPS
+	TLS *ConfigurationTLS `toml:"tls"`
+}
+
+type ConfigurationHealth struct {
+	// In seconds
+	ErrorWindow int `toml:"error_window"`
+	// In seconds
+	OutputWindow int      `toml:"output_window"`
+	OutputTags   []string `toml:"output_tags"`
+}
+
+type ConfigurationProfiling struct {
+	BlockProfileRate     int    `toml:"block_profile_rate"`
+	Dir                  string `toml:"dir"`
+	MutexProfileFraction int    `toml:"mutex_profile_fraction"`
+}
+
+type ConfigurationTLS struct {
+	CertFile string `toml:"cert_file"`
+	// If set, clients must present a certificate signed by one of its CAs
+	ClientCAFile string `toml:"client_ca_file"`
+	KeyFile      string `toml:"key_file"`
+}
+
+type ConfigurationWebhook struct {
+	EventNames []string          `toml:"event_names"`
+	Headers    map[string]string `toml:"headers"`
+	Secret     string            `toml:"secret"`
+	URL        string            `toml:"url"`
 }
 
 func newConfiguration() (c Configuration, err error) {
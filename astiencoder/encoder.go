@@ -4,11 +4,13 @@ import (
 	"sync"
 
 	"github.com/asticode/go-astiencoder"
+	astilibav "github.com/asticode/go-astiencoder/libav"
 	"github.com/asticode/go-astikit"
 )
 
 type encoder struct {
 	c         *ConfigurationEncoder
+	ec        *astilibav.EncoderCache
 	eh        *astiencoder.EventHandler
 	m         *sync.Mutex
 	w         *astikit.Worker
@@ -19,6 +21,7 @@ type encoder struct {
 func newEncoder(c *ConfigurationEncoder, eh *astiencoder.EventHandler, ws *astiencoder.Server, l astikit.StdLogger) (e *encoder) {
 	e = &encoder{
 		c:         c,
+		ec:        astilibav.NewEncoderCache(astilibav.EncoderCacheOptions{}),
 		eh:        eh,
 		m:         &sync.Mutex{},
 		w:         astikit.NewWorker(astikit.WorkerOptions{Logger: l}),
@@ -1,21 +1,78 @@
 package main
 
-import "github.com/asticode/go-astikit"
+import (
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// Job pacings
+const (
+	// Inputs are read as fast as possible, which is what batch transcodes want
+	JobPacingUnpaced = "unpaced"
+	// Inputs are read at their native rate, which is what live workflows want
+	JobPacingRealtime = "realtime"
+)
 
 // Job represents a job
 type Job struct {
-	Inputs     map[string]JobInput     `json:"inputs"`
-	Operations map[string]JobOperation `json:"operations"`
-	Outputs    map[string]JobOutput    `json:"outputs"`
+	// In milliseconds. If > 0, the workflow emits heartbeat events at this period
+	HeartbeatPeriod int                     `json:"heartbeat_period,omitempty"`
+	Inputs          map[string]JobInput     `json:"inputs"`
+	MemoryBudget    *JobMemoryBudget        `json:"memory_budget,omitempty"`
+	Operations      map[string]JobOperation `json:"operations"`
+	Outputs         map[string]JobOutput    `json:"outputs"`
+	// Possible values are "unpaced" and "realtime". Default is "unpaced"
+	Pacing string    `json:"pacing,omitempty"`
+	Stats  *JobStats `json:"stats,omitempty"`
+}
+
+// JobStats represents job stats
+type JobStats struct {
+	// If > 0, this number of stats samples is kept per node and served under /stats/history
+	HistorySize int `json:"history_size,omitempty"`
+	// In milliseconds
+	Period int `json:"period"`
+}
+
+func (j Job) workflowStartOptions() (o astiencoder.WorkflowStartOptions) {
+	o.HeartbeatPeriod = time.Duration(j.HeartbeatPeriod) * time.Millisecond
+	if j.Stats != nil {
+		o.StatsPeriod = time.Duration(j.Stats.Period) * time.Millisecond
+	}
+	return
+}
+
+// JobMemoryBudget represents a job memory budget
+type JobMemoryBudget struct {
+	// Max number of bytes buffered by the workflow
+	Budget int64 `json:"budget"`
+	// Number of bytes under which the budget is restored once exceeded. Default is 90% of the budget
+	LowWaterMark int64 `json:"low_water_mark,omitempty"`
+	// Possible values are "drop", "pause" and "stop". Default is "drop"
+	Policy string `json:"policy,omitempty"`
 }
 
 // JobInput represents a job input
 type JobInput struct {
-	Dict        string `json:"dict"`
+	// If set, packets are sent to the next nodes in batches
+	Batch *JobInputBatch `json:"batch,omitempty"`
+	// If true, the input is tagged as critical for the health check
+	Critical bool   `json:"critical,omitempty"`
+	Dict     string `json:"dict"`
+	// Inputs are always emulated when the job pacing is "realtime"
 	EmulateRate bool   `json:"emulate_rate"`
 	URL         string `json:"url"`
 }
 
+// JobInputBatch represents a job input batch
+type JobInputBatch struct {
+	// In milliseconds
+	MaxLatency int `json:"max_latency,omitempty"`
+	MaxSize    int `json:"max_size"`
+}
+
 // Job output types
 const (
 	// The packet data is dumped directly to the url without any mux
@@ -24,6 +81,11 @@ const (
 
 // JobOutput represents a job output
 type JobOutput struct {
+	// If true, the output is tagged as critical for the health check
+	Critical bool `json:"critical,omitempty"`
+	// If true, the output is only opened once it receives data. Encoders only feeding lazy outputs are opened lazily
+	// as well
+	Lazy bool `json:"lazy,omitempty"`
 	// Possible values are "default" and "pkt_dump"
 	Type string `json:"type,omitempty"`
 	URL  string `json:"url"`
@@ -41,7 +103,9 @@ type JobOperation struct {
 	BitRate *int `json:"bit_rate,omitempty"`
 	// Possible values are "copy" and all libav codec names.
 	Codec string `json:"codec,omitempty"`
-	Dict  string `json:"dict,omitempty"`
+	// CPUs the operation's filterer and encoder are pinned to, so that channels don't fight for the same cores
+	CPUs []int  `json:"cpus,omitempty"`
+	Dict string `json:"dict,omitempty"`
 	// Frame rate is a per-operation value since we may have different frame rate operations for a similar output
 	FrameRate   *astikit.Rational    `json:"frame_rate,omitempty"`
 	GopSize     *int                 `json:"gop_size,omitempty"`
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/asticode/go-astiencoder"
 	astilibav "github.com/asticode/go-astiencoder/libav"
 	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
 )
 
 // Flags
@@ -39,25 +46,168 @@ func main() {
 
 	// Create event handler
 	eh := astiencoder.NewEventHandler()
+	defer eh.Close()
+
+	// Create profiler
+	var p *astiencoder.Profiler
+	if c.Encoder.Server.Profiling != nil {
+		p = astiencoder.NewProfiler(astiencoder.ProfilerOptions{
+			BlockProfileRate:     c.Encoder.Server.Profiling.BlockProfileRate,
+			Dir:                  c.Encoder.Server.Profiling.Dir,
+			MutexProfileFraction: c.Encoder.Server.Profiling.MutexProfileFraction,
+		}, eh)
+	}
+
+	// Create health
+	var h *astiencoder.Health
+	if c.Encoder.Server.Health != nil {
+		h = astiencoder.NewHealth(astiencoder.HealthOptions{
+			ErrorWindow:  time.Duration(c.Encoder.Server.Health.ErrorWindow) * time.Second,
+			OutputTags:   c.Encoder.Server.Health.OutputTags,
+			OutputWindow: time.Duration(c.Encoder.Server.Health.OutputWindow) * time.Second,
+		}, eh)
+	}
+
+	// Create alerter
+	var a *astiencoder.Alerter
+	if len(c.Encoder.Alerts) > 0 {
+		a = astiencoder.NewAlerter(eh)
+		for _, r := range c.Encoder.Alerts {
+			if err = a.AddRule(astiencoder.AlertRule{
+				For:       time.Duration(r.For) * time.Second,
+				Name:      r.Name,
+				Node:      r.Node,
+				Operator:  r.Operator,
+				Stat:      r.Stat,
+				Tag:       r.Tag,
+				Threshold: r.Threshold,
+			}); err != nil {
+				l.Fatal(fmt.Errorf("main: adding alert rule failed: %w", err))
+			}
+		}
+	}
+
+	// Create metrics
+	var m *astiencoder.Metrics
+	if c.Encoder.Server.Metrics {
+		m = astiencoder.NewMetrics(eh)
+	}
 
 	// Create workflow server
-	ws := astiencoder.NewServer(astiencoder.ServerOptions{Logger: l})
+	ws := astiencoder.NewServer(astiencoder.ServerOptions{
+		Alerter:  a,
+		AuditLog: astiencoder.NewAuditLog(astiencoder.AuditLogOptions{}, eh),
+		Health:   h,
+		Logger:   astiencoder.AdaptStdLogger(l),
+		Metrics:  m,
+		Profiler: p,
+	})
 
 	// Adapt event handler
 	astiencoder.LoggerEventHandlerAdapter(l, eh)
 	ws.EventHandlerAdapter(eh)
 
+	// Route libav logs
+	if c.Encoder.LibavLogs != nil {
+		astilibav.RouteLogs(astilibav.LogOptions{
+			Level:     c.Encoder.LibavLogs.Level,
+			RateLimit: c.Encoder.LibavLogs.RateLimit,
+		}, eh)
+		defer astilibav.ResetLogs()
+		sl := astiencoder.AdaptStdLogger(l)
+		eh.AddForEventName(astilibav.LogMessageEmitted, func(e astiencoder.Event) bool {
+			// Get fields
+			m := e.Payload.(astilibav.LogMessage)
+			fs := []astiencoder.LogField{{Key: "dropped", Value: m.Dropped}}
+			if n, ok := e.Target.(astiencoder.Node); ok {
+				fs = append(fs, astiencoder.LogField{Key: astiencoder.LogFieldNode, Value: n.Metadata().Name})
+			}
+
+			// Log
+			switch {
+			case m.Level <= avutil.AV_LOG_ERROR:
+				sl.Error("libav: "+m.Message, fs...)
+			case m.Level <= avutil.AV_LOG_WARNING:
+				sl.Warn("libav: "+m.Message, fs...)
+			case m.Level <= avutil.AV_LOG_INFO:
+				sl.Info("libav: "+m.Message, fs...)
+			default:
+				sl.Debug("libav: "+m.Message, fs...)
+			}
+			return false
+		})
+	}
+
 	// Create encoder
 	e := newEncoder(c.Encoder, eh, ws, l)
+	defer e.ec.Close()
 
-	// Handle signals
-	e.w.HandleSignals()
+	// Shutdowners are shut down in order once a term signal is received, producers first
+	var producers, sinks []astiencoder.Shutdowner
+
+	// Report errors
+	if c.Encoder.ErrorReporting != nil {
+		// Create headers
+		hs := make(http.Header)
+		for k, v := range c.Encoder.ErrorReporting.Headers {
+			hs.Set(k, v)
+		}
+
+		// Create reporter
+		r := astiencoder.NewErrorReporter(astiencoder.ErrorReporterOptions{
+			BatchSize:   c.Encoder.ErrorReporting.BatchSize,
+			FlushPeriod: time.Duration(c.Encoder.ErrorReporting.FlushPeriod) * time.Millisecond,
+			Logger:      astiencoder.AdaptStdLogger(l),
+			Sender: astiencoder.NewHTTPErrorReportSender(astiencoder.HTTPErrorReportSenderOptions{
+				Headers: hs,
+				URL:     c.Encoder.ErrorReporting.URL,
+			}),
+		}, eh)
+
+		// Start reporter
+		e.w.NewTask().Do(func() { r.Start(e.w.Context()) })
+		sinks = append(sinks, r)
+	}
+
+	// Post events to webhooks
+	if len(c.Encoder.Webhooks) > 0 {
+		// Create webhooks
+		var whs []astiencoder.Webhook
+		for _, wh := range c.Encoder.Webhooks {
+			hs := make(http.Header)
+			for k, v := range wh.Headers {
+				hs.Set(k, v)
+			}
+			whs = append(whs, astiencoder.Webhook{
+				EventNames: wh.EventNames,
+				Headers:    hs,
+				Secret:     wh.Secret,
+				URL:        wh.URL,
+			})
+		}
+
+		// Create dispatcher
+		d := astiencoder.NewWebhookDispatcher(astiencoder.WebhookDispatcherOptions{
+			Logger:   astiencoder.AdaptStdLogger(l),
+			Webhooks: whs,
+		}, eh)
+
+		// Start dispatcher
+		e.w.NewTask().Do(func() { d.Start(e.w.Context()) })
+		sinks = append(sinks, d)
+	}
 
 	// Serve
-	astikit.ServeHTTP(e.w, astikit.ServeHTTPOptions{
-		Addr:    c.Encoder.Server.Addr,
-		Handler: ws.Handler(),
-	})
+	if c.Encoder.Server.TLS != nil {
+		if err = serveHTTPS(e.w, c.Encoder.Server.Addr, ws.Handler(), *c.Encoder.Server.TLS, l); err != nil {
+			l.Fatal(fmt.Errorf("main: serving https failed: %w", err))
+		}
+	} else {
+		astikit.ServeHTTP(e.w, astikit.ServeHTTPOptions{
+			Addr:    c.Encoder.Server.Addr,
+			Handler: ws.Handler(),
+		})
+	}
 
 	// Job has been provided
 	if len(*job) > 0 {
@@ -83,9 +233,83 @@ func main() {
 		c.Encoder.Exec.StopWhenWorkflowsAreStopped = true
 
 		// Start workflow
-		w.Start()
+		w.StartWithOptions(j.workflowStartOptions())
+		producers = append(producers, w)
+	}
+
+	// Handle signals
+	shutdownTimeout := 30 * time.Second
+	if c.Encoder.Exec.ShutdownTimeout > 0 {
+		shutdownTimeout = time.Duration(c.Encoder.Exec.ShutdownTimeout) * time.Second
 	}
+	e.w.NewTask().Do(func() { handleSignals(e.w, shutdownTimeout, append(producers, sinks...), l) })
 
 	// Wait
 	e.w.Wait()
 }
+
+// handleSignals shuts down gracefully before stopping the worker once a term signal is received
+func handleSignals(w *astikit.Worker, timeout time.Duration, ss []astiencoder.Shutdowner, l *log.Logger) {
+	// Notify
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(ch)
+
+	// Wait for a signal
+	select {
+	case <-w.Context().Done():
+		return
+	case s := <-ch:
+		l.Printf("main: received %s signal, shutting down\n", s)
+	}
+
+	// Shut down
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := astiencoder.Shutdown(ctx, ss...); err != nil {
+		l.Println(fmt.Errorf("main: shutting down failed: %w", err))
+	}
+
+	// Stop worker
+	w.Stop()
+}
+
+func serveHTTPS(w *astikit.Worker, addr string, h http.Handler, c ConfigurationTLS, l *log.Logger) (err error) {
+	// Get TLS config
+	var t *tls.Config
+	if t, err = (astiencoder.TLSServerOptions{
+		CertFile:     c.CertFile,
+		ClientCAFile: c.ClientCAFile,
+		KeyFile:      c.KeyFile,
+	}).Config(); err != nil {
+		err = fmt.Errorf("main: getting tls config failed: %w", err)
+		return
+	}
+
+	// Create server
+	srv := &http.Server{
+		Addr:      addr,
+		Handler:   h,
+		TLSConfig: t,
+	}
+
+	// Serve
+	w.NewTask().Do(func() {
+		l.Printf("main: serving on https://%s\n", addr)
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			l.Println(fmt.Errorf("main: serving on https://%s failed: %w", addr, err))
+			w.Stop()
+		}
+	})
+
+	// Shut down once the worker is stopped
+	w.NewTask().Do(func() {
+		<-w.Context().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			l.Println(fmt.Errorf("main: shutting down server failed: %w", err))
+		}
+	})
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/asticode/go-astiencoder"
 	astilibav "github.com/asticode/go-astiencoder/libav"
@@ -20,8 +21,22 @@ func addWorkflow(name string, j Job, e *encoder) (w *astiencoder.Workflow, err e
 	// Create workflow
 	w = astiencoder.NewWorkflow(e.w.Context(), name, e.eh, e.w.NewTask, c)
 
+	// Set memory budget
+	if j.MemoryBudget != nil {
+		w.SetMemoryBudget(astiencoder.MemoryBudgetOptions{
+			Budget:       j.MemoryBudget.Budget,
+			LowWaterMark: j.MemoryBudget.LowWaterMark,
+			Policy:       j.MemoryBudget.Policy,
+		})
+	}
+
+	// Set stats history
+	if j.Stats != nil && j.Stats.HistorySize > 0 {
+		w.SetStatsHistory(astiencoder.StatsHistoryOptions{Size: j.Stats.HistorySize})
+	}
+
 	// Build workflow
-	b := newBuilder()
+	b := newBuilder(e.ec)
 	if err = b.buildWorkflow(j, w, e.eh, c); err != nil {
 		err = fmt.Errorf("main: building workflow failed: %w", err)
 		return
@@ -32,10 +47,12 @@ func addWorkflow(name string, j Job, e *encoder) (w *astiencoder.Workflow, err e
 	return
 }
 
-type builder struct{}
+type builder struct {
+	ec *astilibav.EncoderCache
+}
 
-func newBuilder() *builder {
-	return &builder{}
+func newBuilder(ec *astilibav.EncoderCache) *builder {
+	return &builder{ec: ec}
 }
 
 type openedInput struct {
@@ -76,6 +93,14 @@ func (b *builder) buildWorkflow(j Job, w *astiencoder.Workflow, eh *astiencoder.
 		return
 	}
 
+	// Invalid pacing
+	switch j.Pacing {
+	case "", JobPacingRealtime, JobPacingUnpaced:
+	default:
+		err = fmt.Errorf("main: invalid pacing %s", j.Pacing)
+		return
+	}
+
 	// Open inputs
 	if bd.inputs, err = b.openInputs(j, bd); err != nil {
 		err = fmt.Errorf("main: opening inputs failed: %w", err)
@@ -115,11 +140,22 @@ func (b *builder) openInputs(j Job, bd *buildData) (is map[string]openedInput, e
 	// Loop through inputs
 	is = make(map[string]openedInput)
 	for n, cfg := range j.Inputs {
+		// Get batch options
+		var batch astilibav.PktBatchOptions
+		if cfg.Batch != nil {
+			batch = astilibav.PktBatchOptions{
+				MaxLatency: time.Duration(cfg.Batch.MaxLatency) * time.Millisecond,
+				MaxSize:    cfg.Batch.MaxSize,
+			}
+		}
+
 		// Create demuxer
 		var d *astilibav.Demuxer
 		if d, err = astilibav.NewDemuxer(astilibav.DemuxerOptions{
+			Batch:       batch,
 			Dict:        astilibav.NewDefaultDict(cfg.Dict),
-			EmulateRate: cfg.EmulateRate,
+			EmulateRate: cfg.EmulateRate || j.Pacing == JobPacingRealtime,
+			Node:        nodeOptions(cfg.Critical),
 			URL:         cfg.URL,
 		}, bd.eh, bd.c); err != nil {
 			err = fmt.Errorf("main: creating demuxer failed: %w", err)
@@ -151,7 +187,11 @@ func (b *builder) openOutputs(j Job, bd *buildData) (os map[string]openedOutput,
 			// The writer is created afterwards
 		default:
 			// Create muxer
-			if oo.m, err = astilibav.NewMuxer(astilibav.MuxerOptions{URL: cfg.URL}, bd.eh, bd.c); err != nil {
+			if oo.m, err = astilibav.NewMuxer(astilibav.MuxerOptions{
+				Lazy: cfg.Lazy,
+				Node: nodeOptions(cfg.Critical),
+				URL:  cfg.URL,
+			}, bd.eh, bd.c); err != nil {
 				err = fmt.Errorf("main: creating muxer failed: %w", err)
 				return
 			}
@@ -163,6 +203,13 @@ func (b *builder) openOutputs(j Job, bd *buildData) (os map[string]openedOutput,
 	return
 }
 
+func nodeOptions(critical bool) (o astiencoder.NodeOptions) {
+	if critical {
+		o.Metadata.Tags = []string{astiencoder.HealthDefaultCriticalTag}
+	}
+	return
+}
+
 type operationInput struct {
 	c JobOperationInput
 	o openedInput
@@ -231,14 +278,28 @@ func (b *builder) addOperationToWorkflow(name string, o JobOperation, bd *buildD
 
 			// Create filterer
 			var f *astilibav.Filterer
-			if f, err = b.createFilterer(bd, outCtx, d); err != nil {
+			if f, err = b.createFilterer(bd, outCtx, d, o.CPUs); err != nil {
 				err = fmt.Errorf("main: creating filterer for stream 0x%x(%d) of input %s failed: %w", is.Id(), is.Id(), i.c.Name, err)
 				return
 			}
 
+			// Encoders are opened lazily if all their outputs are lazy
+			lazy := len(oos) > 0
+			for _, o := range oos {
+				if !o.o.c.Lazy {
+					lazy = false
+					break
+				}
+			}
+
 			// Create encoder
 			var e *astilibav.Encoder
-			if e, err = astilibav.NewEncoder(astilibav.EncoderOptions{Ctx: outCtx}, bd.eh, bd.c); err != nil {
+			if e, err = astilibav.NewEncoder(astilibav.EncoderOptions{
+				Cache: b.ec,
+				Ctx:   outCtx,
+				Lazy:  lazy,
+				Node:  astiencoder.NodeOptions{CPUs: o.CPUs},
+			}, bd.eh, bd.c); err != nil {
 				err = fmt.Errorf("main: creating encoder for stream 0x%x(%d) of input %s failed: %w", is.Id(), is.Id(), i.c.Name, err)
 				return
 			}
@@ -423,7 +484,7 @@ func (b *builder) createDecoder(bd *buildData, i operationInput, is *avformat.St
 	return
 }
 
-func (b *builder) createFilterer(bd *buildData, outCtx astilibav.Context, n astiencoder.Node) (f *astilibav.Filterer, err error) {
+func (b *builder) createFilterer(bd *buildData, outCtx astilibav.Context, n astiencoder.Node, cpus []int) (f *astilibav.Filterer, err error) {
 	// Create filters
 	var filters []string
 
@@ -453,6 +514,7 @@ func (b *builder) createFilterer(bd *buildData, outCtx astilibav.Context, n asti
 			Inputs: map[string]astiencoder.Node{
 				"in": n,
 			},
+			Node:      astiencoder.NodeOptions{CPUs: cpus},
 			OutputCtx: outCtx,
 		}
 
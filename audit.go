@@ -0,0 +1,171 @@
+package astiencoder
+
+import (
+	"sync"
+	"time"
+)
+
+// Audit actions
+const (
+	AuditActionContinue    = "continue"
+	AuditActionCreate      = "create"
+	AuditActionDelete      = "delete"
+	AuditActionPatch       = "patch"
+	AuditActionPause       = "pause"
+	AuditActionReconfigure = "reconfigure"
+	AuditActionSeek        = "seek"
+	AuditActionSetBitRate  = "bit_rate.set"
+	AuditActionStart       = "start"
+	AuditActionStop        = "stop"
+	AuditActionSwap        = "swap"
+)
+
+// AuditEntry represents a control operation performed on a workflow or on one of its nodes
+// It is the payload of the EventNameAudit event whose target is the workflow or the node
+type AuditEntry struct {
+	Action string
+	// Empty if the operation has not been performed through a WorkflowController, e.g. when the workflow stops
+	// itself because its memory budget is exceeded
+	Actor string
+	At    time.Time
+	// Empty if the operation has succeeded
+	Error string
+	// Empty if the operation has been performed on the workflow
+	Node     string
+	Params   map[string]interface{}
+	Workflow string
+}
+
+// WorkflowController represents an object capable of performing control operations on a workflow on behalf of an
+// actor, e.g. the user of an API, so that they can be audited
+type WorkflowController struct {
+	actor string
+	w     *Workflow
+}
+
+// Controller creates a new controller performing control operations on behalf of the actor
+func (w *Workflow) Controller(actor string) *WorkflowController {
+	return &WorkflowController{
+		actor: actor,
+		w:     w,
+	}
+}
+
+// Start starts the workflow
+func (c *WorkflowController) Start() {
+	c.w.startWithActor(WorkflowStartOptions{}, c.actor)
+}
+
+// StartWithOptions starts the workflow with options
+func (c *WorkflowController) StartWithOptions(o WorkflowStartOptions) {
+	c.w.startWithActor(o, c.actor)
+}
+
+// Stop stops the workflow
+func (c *WorkflowController) Stop() {
+	c.w.stop(c.actor)
+}
+
+// Pause pauses the workflow
+func (c *WorkflowController) Pause() {
+	c.w.pause(c.actor)
+}
+
+// Continue continues the workflow
+func (c *WorkflowController) Continue() {
+	c.w.resume(c.actor)
+}
+
+// Do performs a control operation on a node of the workflow, such as a seek, a node swap or a bit rate change, and
+// audits it whether it has succeeded or not
+func (c *WorkflowController) Do(n Node, action string, params map[string]interface{}, fn func() error) (err error) {
+	err = fn()
+	c.w.audit(n, c.actor, action, params, err)
+	return
+}
+
+func (w *Workflow) audit(n Node, actor, action string, params map[string]interface{}, err error) {
+	// Create entry
+	e := AuditEntry{
+		Action:   action,
+		Actor:    actor,
+		At:       time.Now(),
+		Params:   params,
+		Workflow: w.name,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	// Get target
+	var t interface{} = w
+	if n != nil {
+		e.Node = n.Metadata().Name
+		t = n
+	}
+
+	// Emit
+	w.e.Emit(Event{
+		Name:    EventNameAudit,
+		Payload: e,
+		Target:  t,
+	})
+}
+
+const auditLogSizeDefault = 1000
+
+// AuditLogOptions represents audit log options
+type AuditLogOptions struct {
+	// Max number of entries kept per workflow. Default is 1000
+	Size int
+}
+
+// AuditLog represents an object keeping the last control operations of each workflow in memory so that they can
+// be queried
+type AuditLog struct {
+	es map[string][]AuditEntry
+	m  *sync.Mutex
+	o  AuditLogOptions
+}
+
+// NewAuditLog creates a new audit log fed by the audit events of the event handler
+func NewAuditLog(o AuditLogOptions, eh *EventHandler) (l *AuditLog) {
+	// Default options
+	if o.Size <= 0 {
+		o.Size = auditLogSizeDefault
+	}
+
+	// Create audit log
+	l = &AuditLog{
+		es: make(map[string][]AuditEntry),
+		m:  &sync.Mutex{},
+		o:  o,
+	}
+
+	// Handle events
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		l.add(e.Payload.(AuditEntry))
+		return false
+	})
+	return
+}
+
+func (l *AuditLog) add(e AuditEntry) {
+	// Lock
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	// Append
+	es := append(l.es[e.Workflow], e)
+	if len(es) > l.o.Size {
+		es = append([]AuditEntry{}, es[len(es)-l.o.Size:]...)
+	}
+	l.es[e.Workflow] = es
+}
+
+// Entries returns the entries of the workflow, oldest first
+func (l *AuditLog) Entries(workflow string) []AuditEntry {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return append([]AuditEntry{}, l.es[workflow]...)
+}
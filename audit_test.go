@@ -0,0 +1,48 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	eh := NewEventHandler()
+	l := NewAuditLog(AuditLogOptions{Size: 2}, eh)
+	var ts []interface{}
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		ts = append(ts, e.Target)
+		return false
+	})
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	n := newMockedStatsNode("n", eh)
+	w.AddChild(n)
+
+	// Operations are audited with their actor
+	w.Pause()
+	c := w.Controller("alice")
+	c.Continue()
+	err := c.Do(n, AuditActionSetBitRate, map[string]interface{}{"bit_rate": 1000}, func() error { return errors.New("test") })
+	assert.EqualError(t, err, "test")
+	assert.Equal(t, []interface{}{w, w, n}, ts)
+
+	// Entries are bounded and queryable per workflow
+	es := l.Entries("w")
+	assert.Len(t, es, 2)
+	assert.False(t, es[0].At.IsZero())
+	es[0].At = es[1].At
+	assert.Equal(t, AuditEntry{Action: AuditActionContinue, Actor: "alice", At: es[1].At, Workflow: "w"}, es[0])
+	assert.Equal(t, AuditEntry{
+		Action:   AuditActionSetBitRate,
+		Actor:    "alice",
+		At:       es[1].At,
+		Error:    "test",
+		Node:     "n",
+		Params:   map[string]interface{}{"bit_rate": 1000},
+		Workflow: "w",
+	}, es[1])
+	assert.Len(t, l.Entries("unknown"), 0)
+}
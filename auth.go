@@ -0,0 +1,266 @@
+package astiencoder
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Auth errors, wrapped by the errors returned when a request can't be authenticated or authorized so that
+// transports can map them to their own codes
+var (
+	ErrForbidden       = errors.New("astiencoder: forbidden")
+	ErrUnauthenticated = errors.New("astiencoder: unauthenticated")
+)
+
+// Role represents what an identity is allowed to do. Each role is allowed to do what the previous ones are
+type Role string
+
+// Roles
+const (
+	// Can list workflows and observe them (snapshots, stats, events)
+	RoleViewer Role = "viewer"
+	// Can control existing workflows and their nodes (start, stop, pause, seek, reconfigure, etc.)
+	RoleOperator Role = "operator"
+	// Can create and delete workflows
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Allows returns whether the role is allowed to do what the other role is. Unknown roles are allowed nothing
+func (r Role) Allows(o Role) bool {
+	l, ok := roleLevels[r]
+	return ok && l >= roleLevels[o]
+}
+
+// Identity represents an authenticated caller
+type Identity struct {
+	// Used as the actor of the audited operations
+	Name string
+	// If set, the identity can only see and control the workflows of this namespace
+	Namespace string
+	Role      Role
+}
+
+type identityContextKey struct{}
+
+// ContextWithIdentity returns a context holding the identity
+func ContextWithIdentity(ctx context.Context, i Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, i)
+}
+
+// IdentityFromContext returns the identity the context holds, if any
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	if ctx == nil {
+		return Identity{}, false
+	}
+	i, ok := ctx.Value(identityContextKey{}).(Identity)
+	return i, ok
+}
+
+// Authorize returns an error wrapping ErrForbidden if the identity is not allowed to do what the role is
+func (i Identity) Authorize(r Role) error {
+	if !i.Role.Allows(r) {
+		return fmt.Errorf("astiencoder: %s with role %s is not allowed to do what %s can: %w", i.Name, i.Role, r, ErrForbidden)
+	}
+	return nil
+}
+
+// Authenticator represents an object capable of authenticating a token, such as an API key or a JWT, provided by
+// a caller. Errors should wrap ErrUnauthenticated when the token is invalid
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+// AuthenticatorFunc is an adapter allowing to use a function as an authenticator
+type AuthenticatorFunc func(ctx context.Context, token string) (Identity, error)
+
+// Authenticate implements the Authenticator interface
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, token string) (Identity, error) {
+	return f(ctx, token)
+}
+
+// APIKeyAuthenticator authenticates static API keys
+type APIKeyAuthenticator struct {
+	ks map[string]Identity
+}
+
+// NewAPIKeyAuthenticator creates a new API key authenticator. Keys are indexed by their value
+func NewAPIKeyAuthenticator(keys map[string]Identity) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{ks: keys}
+}
+
+// Authenticate implements the Authenticator interface
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (i Identity, err error) {
+	// Loop through keys
+	// Keys are compared in constant time so that they can't be guessed by timing the requests
+	var ok bool
+	for k, ki := range a.ks {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(token)) == 1 {
+			i, ok = ki, true
+		}
+	}
+
+	// Invalid key
+	if !ok {
+		err = fmt.Errorf("astiencoder: invalid api key: %w", ErrUnauthenticated)
+		return
+	}
+	return
+}
+
+const (
+	jwtNameClaimDefault      = "sub"
+	jwtNamespaceClaimDefault = "namespace"
+	jwtRoleClaimDefault      = "role"
+)
+
+// JWTAuthenticatorOptions represents JWT authenticator options
+type JWTAuthenticatorOptions struct {
+	// Claim holding the name of the identity. Default is "sub"
+	NameClaim string
+	// Claim holding the namespace of the identity, if any. Default is "namespace"
+	NamespaceClaim string
+	// Claim holding the role of the identity. Default is "role"
+	RoleClaim string
+	// Validates the token (signature, expiration, audience, etc.), typically with the JWT library of your choice, and
+	// returns its claims. Mandatory
+	Validate func(ctx context.Context, token string) (claims map[string]interface{}, err error)
+}
+
+// JWTAuthenticator authenticates JWTs whose validation is delegated to a hook so that the library doesn't depend on
+// a JWT library
+type JWTAuthenticator struct {
+	o JWTAuthenticatorOptions
+}
+
+// NewJWTAuthenticator creates a new JWT authenticator
+// The Validate option is mandatory since tokens can't be trusted without being validated
+func NewJWTAuthenticator(o JWTAuthenticatorOptions) (*JWTAuthenticator, error) {
+	// No validation
+	if o.Validate == nil {
+		return nil, errors.New("astiencoder: jwt authenticator needs a Validate option")
+	}
+
+	// Default options
+	if o.NameClaim == "" {
+		o.NameClaim = jwtNameClaimDefault
+	}
+	if o.NamespaceClaim == "" {
+		o.NamespaceClaim = jwtNamespaceClaimDefault
+	}
+	if o.RoleClaim == "" {
+		o.RoleClaim = jwtRoleClaimDefault
+	}
+	return &JWTAuthenticator{o: o}, nil
+}
+
+// Authenticate implements the Authenticator interface
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (i Identity, err error) {
+	// Not a JWT
+	if strings.Count(token, ".") != 2 {
+		err = fmt.Errorf("astiencoder: token is not a jwt: %w", ErrUnauthenticated)
+		return
+	}
+
+	// Validate
+	var cs map[string]interface{}
+	if cs, err = a.o.Validate(ctx, token); err != nil {
+		err = fmt.Errorf("astiencoder: validating jwt failed: %v: %w", err, ErrUnauthenticated)
+		return
+	}
+
+	// Get identity
+	i.Name, _ = cs[a.o.NameClaim].(string)
+	i.Namespace, _ = cs[a.o.NamespaceClaim].(string)
+	r, _ := cs[a.o.RoleClaim].(string)
+	i.Role = Role(r)
+
+	// Invalid role
+	if _, ok := roleLevels[i.Role]; !ok {
+		err = fmt.Errorf("astiencoder: invalid role %q in claim %s: %w", r, a.o.RoleClaim, ErrUnauthenticated)
+		return
+	}
+	return
+}
+
+// MultiAuthenticator authenticates tokens with the first authenticator that succeeds, e.g. to accept both API keys
+// and JWTs
+type MultiAuthenticator []Authenticator
+
+// Authenticate implements the Authenticator interface
+func (as MultiAuthenticator) Authenticate(ctx context.Context, token string) (i Identity, err error) {
+	// Loop through authenticators
+	for _, a := range as {
+		if i, err = a.Authenticate(ctx, token); err == nil {
+			return
+		} else if !errors.Is(err, ErrUnauthenticated) {
+			return
+		}
+	}
+
+	// No authenticator has succeeded
+	err = fmt.Errorf("astiencoder: no authenticator accepted the token: %w", ErrUnauthenticated)
+	return
+}
+
+// AuthAccessTokenQueryParameter is the name of the query parameter providing the token when the Authorization header
+// can't be set, e.g. by browsers opening websockets
+const AuthAccessTokenQueryParameter = "access_token"
+
+// BearerToken returns the token of the request, provided either as a bearer token in the Authorization header or in
+// the access_token query parameter
+func BearerToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return r.URL.Query().Get(AuthAccessTokenQueryParameter)
+}
+
+func authenticate(ctx context.Context, a Authenticator, token, actor string) (i Identity, err error) {
+	// No authenticator
+	if a == nil {
+		return Identity{Name: actor, Role: RoleAdmin}, nil
+	}
+
+	// Missing token
+	if token == "" {
+		err = fmt.Errorf("astiencoder: missing token: %w", ErrUnauthenticated)
+		return
+	}
+
+	// Authenticate
+	return a.Authenticate(ctx, token)
+}
+
+// authorizeHandler authenticates the caller, makes sure its role is allowed to do what the role is and stores its
+// identity in the request's context. If the authenticator is nil, the caller is trusted and the actor is read from
+// the X-Astiencoder-Actor header
+func authorizeHandler(a Authenticator, role Role, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Authenticate
+		i, err := authenticate(r.Context(), a, BearerToken(r), r.Header.Get(ControlActorHeader))
+		if err != nil {
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			writeControlError(rw, err)
+			return
+		}
+
+		// Authorize
+		if err = i.Authorize(role); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+
+		// Serve
+		h.ServeHTTP(rw, r.WithContext(ContextWithIdentity(r.Context(), i)))
+	})
+}
@@ -0,0 +1,78 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole(t *testing.T) {
+	assert.True(t, RoleAdmin.Allows(RoleViewer))
+	assert.True(t, RoleOperator.Allows(RoleOperator))
+	assert.False(t, RoleOperator.Allows(RoleAdmin))
+	assert.False(t, RoleViewer.Allows(RoleOperator))
+	assert.False(t, Role("unknown").Allows(RoleViewer))
+	err := Identity{Name: "alice", Role: RoleViewer}.Authorize(RoleOperator)
+	assert.True(t, errors.Is(err, ErrForbidden))
+	assert.EqualError(t, err, "astiencoder: alice with role viewer is not allowed to do what operator can: astiencoder: forbidden")
+}
+
+func TestAuthenticators(t *testing.T) {
+	// API keys
+	ka := NewAPIKeyAuthenticator(map[string]Identity{"key": {Name: "alice", Role: RoleOperator}})
+	i, err := ka.Authenticate(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Name: "alice", Role: RoleOperator}, i)
+	_, err = ka.Authenticate(context.Background(), "invalid")
+	assert.True(t, errors.Is(err, ErrUnauthenticated))
+
+	// JWT
+	_, err = NewJWTAuthenticator(JWTAuthenticatorOptions{})
+	assert.Error(t, err)
+	ja, err := NewJWTAuthenticator(JWTAuthenticatorOptions{Validate: func(ctx context.Context, token string) (map[string]interface{}, error) {
+		switch token {
+		case "a.b.c":
+			return map[string]interface{}{"role": "admin", "sub": "bob"}, nil
+		case "d.e.f":
+			return map[string]interface{}{"role": "root", "sub": "bob"}, nil
+		}
+		return nil, errors.New("invalid signature")
+	}})
+	assert.NoError(t, err)
+	i, err = ja.Authenticate(context.Background(), "a.b.c")
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{Name: "bob", Role: RoleAdmin}, i)
+	_, err = ja.Authenticate(context.Background(), "d.e.f")
+	assert.EqualError(t, err, `astiencoder: invalid role "root" in claim role: astiencoder: unauthenticated`)
+	_, err = ja.Authenticate(context.Background(), "g.h.i")
+	assert.EqualError(t, err, "astiencoder: validating jwt failed: invalid signature: astiencoder: unauthenticated")
+	_, err = ja.Authenticate(context.Background(), "key")
+	assert.True(t, errors.Is(err, ErrUnauthenticated))
+
+	// Multi
+	ma := MultiAuthenticator{ka, ja}
+	i, err = ma.Authenticate(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", i.Name)
+	i, err = ma.Authenticate(context.Background(), "a.b.c")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", i.Name)
+	_, err = ma.Authenticate(context.Background(), "invalid")
+	assert.True(t, errors.Is(err, ErrUnauthenticated))
+	_, err = MultiAuthenticator{AuthenticatorFunc(func(ctx context.Context, token string) (Identity, error) {
+		return Identity{}, errors.New("unavailable")
+	}), ka}.Authenticate(context.Background(), "key")
+	assert.EqualError(t, err, "unavailable")
+}
+
+func TestBearerToken(t *testing.T) {
+	r := httptest.NewRequest("GET", "/?access_token=query", nil)
+	assert.Equal(t, "query", BearerToken(r))
+	r.Header.Set("Authorization", "Bearer header")
+	assert.Equal(t, "header", BearerToken(r))
+	r.Header.Set("Authorization", "Basic dXNlcg==")
+	assert.Equal(t, "query", BearerToken(r))
+}
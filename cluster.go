@@ -0,0 +1,624 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cluster workflow statuses
+const (
+	ClusterWorkflowStatusPending = "pending"
+	ClusterWorkflowStatusPlaced  = "placed"
+)
+
+const clusterHeartbeatTimeoutDefault = 15 * time.Second
+
+// ClusterResources represents resources of an encoder instance, either available or required by a workflow
+type ClusterResources struct {
+	// Number of CPU cores
+	CPU float64 `json:"cpu"`
+	// Number of concurrent GPU encoding/decoding sessions
+	GPUSessions int `json:"gpu_sessions"`
+}
+
+func (r ClusterResources) add(o ClusterResources) ClusterResources {
+	return ClusterResources{CPU: r.CPU + o.CPU, GPUSessions: r.GPUSessions + o.GPUSessions}
+}
+
+func (r ClusterResources) sub(o ClusterResources) ClusterResources {
+	return ClusterResources{CPU: r.CPU - o.CPU, GPUSessions: r.GPUSessions - o.GPUSessions}
+}
+
+func (r ClusterResources) fits(o ClusterResources) bool {
+	return o.CPU <= r.CPU && o.GPUSessions <= r.GPUSessions
+}
+
+// ClusterHeartbeat represents what an encoder instance periodically reports to the coordinator
+type ClusterHeartbeat struct {
+	// Base URL of the instance's control API
+	Addr string `json:"addr"`
+	// Total resources of the instance
+	Capacity ClusterResources `json:"capacity"`
+	// Host remote senders reach the instance's remote receivers at. Default is the host of Addr
+	Host string `json:"host,omitempty"`
+	// Unique name of the instance
+	Instance string `json:"instance"`
+	// Names of the workflows the instance is running
+	Workflows []string `json:"workflows"`
+}
+
+// ClusterWorkflow represents a workflow submitted to the coordinator
+type ClusterWorkflow struct {
+	Definition WorkflowDefinition `json:"definition"`
+	// Values set to the "<host>:<port>" address of remote receivers belonging to other workflows of the cluster,
+	// indexed by variable name. The workflow is only placed once those workflows are, and is placed again when they
+	// move to another instance
+	Remotes map[string]ClusterRemote `json:"remotes,omitempty"`
+	// Resources reserved on the instance the workflow is placed onto
+	Requirements ClusterResources       `json:"requirements"`
+	Values       map[string]interface{} `json:"values,omitempty"`
+}
+
+// ClusterRemote designates a remote receiver belonging to a workflow of the cluster
+type ClusterRemote struct {
+	// Port the receiver listens on
+	Port int `json:"port"`
+	// Name of the workflow the receiver belongs to
+	Workflow string `json:"workflow"`
+}
+
+// ClusterInstanceClient represents an object capable of driving an encoder instance, e.g. a ControlClient
+type ClusterInstanceClient interface {
+	// CreateWorkflow creates and starts a workflow
+	CreateWorkflow(ctx context.Context, d WorkflowDefinition, values map[string]interface{}, start bool) error
+	DeleteWorkflow(ctx context.Context, name string) error
+}
+
+// ClusterCoordinatorOptions represents cluster coordinator options
+type ClusterCoordinatorOptions struct {
+	// If set, the coordinator's handler authenticates its callers with it. Instances sending heartbeats need the
+	// operator role
+	Authenticator Authenticator
+	// Creates the client driving an instance. Default creates a ControlClient using the instance's address and
+	// InstanceToken
+	Client func(h ClusterHeartbeat) ClusterInstanceClient
+	// Emits the EventNameClusterInstanceLost, EventNameClusterWorkflowPlaced and EventNameError events if set
+	EventHandler *EventHandler
+	// Instances that haven't sent a heartbeat for this duration are considered lost and their workflows are
+	// rescheduled. Default is 15s
+	HeartbeatTimeout time.Duration
+	// Token used by the default client to authenticate against the instances
+	InstanceToken string
+}
+
+// ClusterCoordinator places workflow definitions onto a pool of encoder instances based on the capacity they report
+// through heartbeats, and reschedules them when an instance is lost
+// Instances are driven through their control API: workflows are named after their definition, whose name must
+// therefore be unique within the cluster
+type ClusterCoordinator struct {
+	is  map[string]*clusterInstance
+	m   *sync.Mutex
+	now func() time.Time
+	o   ClusterCoordinatorOptions
+	seq int
+	ws  map[string]*clusterWorkflow
+}
+
+type clusterInstance struct {
+	c          ClusterInstanceClient
+	h          ClusterHeartbeat
+	lastSeenAt time.Time
+	used       ClusterResources
+}
+
+type clusterWorkflow struct {
+	instance string
+	placing  bool
+	seq      int
+	w        ClusterWorkflow
+}
+
+// ClusterInstanceStatus represents the status of an instance of the cluster
+type ClusterInstanceStatus struct {
+	Addr       string           `json:"addr"`
+	Capacity   ClusterResources `json:"capacity"`
+	LastSeenAt time.Time        `json:"last_seen_at"`
+	Name       string           `json:"name"`
+	// Resources reserved by the workflows placed onto the instance
+	Used      ClusterResources `json:"used"`
+	Workflows []string         `json:"workflows"`
+}
+
+// ClusterWorkflowStatus represents the status of a workflow of the cluster
+type ClusterWorkflowStatus struct {
+	// Empty while the workflow is pending
+	Instance string `json:"instance,omitempty"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+}
+
+// ClusterPlacement is the payload of the EventNameClusterWorkflowPlaced event
+type ClusterPlacement struct {
+	Instance string `json:"instance"`
+	Workflow string `json:"workflow"`
+}
+
+// NewClusterCoordinator creates a new cluster coordinator
+func NewClusterCoordinator(o ClusterCoordinatorOptions) *ClusterCoordinator {
+	// Default options
+	if o.Client == nil {
+		token := o.InstanceToken
+		o.Client = func(h ClusterHeartbeat) ClusterInstanceClient {
+			return NewControlClient(ControlClientOptions{Addr: h.Addr, Token: token})
+		}
+	}
+	if o.HeartbeatTimeout <= 0 {
+		o.HeartbeatTimeout = clusterHeartbeatTimeoutDefault
+	}
+
+	// Create coordinator
+	return &ClusterCoordinator{
+		is:  make(map[string]*clusterInstance),
+		m:   &sync.Mutex{},
+		now: time.Now,
+		o:   o,
+		ws:  make(map[string]*clusterWorkflow),
+	}
+}
+
+// Submit adds a workflow to the cluster. It's placed as soon as an instance has enough resources, and stays pending
+// until then
+func (c *ClusterCoordinator) Submit(ctx context.Context, w ClusterWorkflow) error {
+	// Lock
+	c.m.Lock()
+
+	// Missing name
+	if w.Definition.Name == "" {
+		c.m.Unlock()
+		return fmt.Errorf("astiencoder: submitting workflow failed: %w", DefinitionErrors{newDefinitionError("name", errors.New("missing name"))})
+	}
+
+	// Workflow already exists
+	if _, ok := c.ws[w.Definition.Name]; ok {
+		c.m.Unlock()
+		return fmt.Errorf("astiencoder: submitting workflow %s failed: %w", w.Definition.Name, ErrWorkflowAlreadyExists)
+	}
+
+	// Store workflow
+	c.seq++
+	c.ws[w.Definition.Name] = &clusterWorkflow{
+		seq: c.seq,
+		w:   w,
+	}
+	c.m.Unlock()
+
+	// Schedule
+	c.schedule(ctx)
+	return nil
+}
+
+// Remove deletes a workflow from the instance it has been placed onto, if any, and removes it from the cluster
+func (c *ClusterCoordinator) Remove(ctx context.Context, name string) (err error) {
+	// Lock
+	c.m.Lock()
+
+	// Get workflow
+	cw, ok := c.ws[name]
+	if !ok {
+		c.m.Unlock()
+		return fmt.Errorf("astiencoder: removing workflow %s failed: %w", name, ErrWorkflowNotFound)
+	}
+
+	// Remove workflow
+	delete(c.ws, name)
+	var ci *clusterInstance
+	if cw.instance != "" {
+		if ci = c.is[cw.instance]; ci != nil {
+			ci.used = ci.used.sub(cw.w.Requirements)
+		}
+	}
+
+	// Unplace workflows depending on it
+	us := c.unplace()
+	c.m.Unlock()
+
+	// Delete unplaced workflows
+	c.deleteUnplaced(ctx, us)
+
+	// Delete workflow from instance
+	if ci != nil {
+		if err = ci.c.DeleteWorkflow(ctx, name); err != nil && !errors.Is(err, ErrWorkflowNotFound) {
+			err = fmt.Errorf("astiencoder: deleting workflow %s from instance %s failed: %w", name, ci.h.Instance, err)
+			return
+		}
+		err = nil
+	}
+	return
+}
+
+// Heartbeat registers the heartbeat of an instance. New instances join the cluster and pending workflows are
+// scheduled. When an instance considered lost comes back, the pending workflows it still runs are placed back onto
+// it whereas the ones rescheduled in the meantime are deleted from it so that they don't run twice
+func (c *ClusterCoordinator) Heartbeat(ctx context.Context, h ClusterHeartbeat) error {
+	// Missing instance
+	if h.Instance == "" {
+		return errors.New("astiencoder: heartbeat has no instance")
+	}
+
+	// Lock
+	c.m.Lock()
+
+	// Get instance
+	ci, ok := c.is[h.Instance]
+	if !ok || ci.h.Addr != h.Addr {
+		ci = &clusterInstance{c: c.o.Client(h)}
+		if ok {
+			ci.used = c.is[h.Instance].used
+		}
+		c.is[h.Instance] = ci
+	}
+
+	// Update instance
+	ci.h = h
+	ci.lastSeenAt = c.now()
+
+	// Loop through reported workflows
+	var adopted, dels []string
+	for _, name := range h.Workflows {
+		// Get workflow
+		cw, ok := c.ws[name]
+		if !ok || cw.instance == h.Instance || cw.placing {
+			continue
+		}
+
+		// Pending workflows already running on the instance are adopted unless the address of their remote receivers
+		// may have changed in the meantime, others shouldn't run on it
+		if cw.instance == "" && len(cw.w.Remotes) == 0 {
+			ci.used = ci.used.add(cw.w.Requirements)
+			cw.instance = h.Instance
+			adopted = append(adopted, name)
+		} else {
+			dels = append(dels, name)
+		}
+	}
+	c.m.Unlock()
+
+	// Emit
+	for _, name := range adopted {
+		c.emit(EventNameClusterWorkflowPlaced, ClusterPlacement{
+			Instance: h.Instance,
+			Workflow: name,
+		})
+	}
+
+	// Delete workflows
+	for _, name := range dels {
+		if err := ci.c.DeleteWorkflow(ctx, name); err != nil && !errors.Is(err, ErrWorkflowNotFound) {
+			c.emitError(fmt.Errorf("astiencoder: deleting duplicate workflow %s from instance %s failed: %w", name, h.Instance, err))
+		}
+	}
+
+	// Schedule
+	c.schedule(ctx)
+	return nil
+}
+
+// Start checks periodically whether instances have been lost until the context is done
+func (c *ClusterCoordinator) Start(ctx context.Context) {
+	t := time.NewTicker(c.o.HeartbeatTimeout / 3)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *ClusterCoordinator) check(ctx context.Context) {
+	// Lock
+	c.m.Lock()
+
+	// Loop through instances
+	var lost []string
+	for name, ci := range c.is {
+		// Instance is alive
+		if c.now().Sub(ci.lastSeenAt) < c.o.HeartbeatTimeout {
+			continue
+		}
+
+		// Remove instance
+		delete(c.is, name)
+		lost = append(lost, name)
+
+		// Reschedule its workflows
+		for _, cw := range c.ws {
+			if cw.instance == name {
+				cw.instance = ""
+			}
+		}
+	}
+
+	// Unplace workflows depending on them
+	var us []clusterAssignment
+	if len(lost) > 0 {
+		us = c.unplace()
+	}
+	c.m.Unlock()
+
+	// Emit
+	sort.Strings(lost)
+	for _, name := range lost {
+		c.emit(EventNameClusterInstanceLost, name)
+	}
+
+	// Delete unplaced workflows
+	c.deleteUnplaced(ctx, us)
+
+	// Schedule
+	if len(lost) > 0 {
+		c.schedule(ctx)
+	}
+}
+
+type clusterAssignment struct {
+	ci     *clusterInstance
+	cw     *clusterWorkflow
+	name   string
+	values map[string]interface{}
+}
+
+// remoteValues returns the workflow's values completed with the addresses of its remote receivers. It must be called
+// with the mutex locked and returns false if one of them is not placed
+func (c *ClusterCoordinator) remoteValues(cw *clusterWorkflow) (vs map[string]interface{}, ok bool) {
+	// No remotes
+	if len(cw.w.Remotes) == 0 {
+		return cw.w.Values, true
+	}
+
+	// Copy values
+	vs = make(map[string]interface{}, len(cw.w.Values)+len(cw.w.Remotes))
+	for k, v := range cw.w.Values {
+		vs[k] = v
+	}
+
+	// Loop through remotes
+	for k, r := range cw.w.Remotes {
+		// Get instance
+		rw, ok := c.ws[r.Workflow]
+		if !ok || rw.instance == "" || rw.placing {
+			return nil, false
+		}
+		ci, ok := c.is[rw.instance]
+		if !ok {
+			return nil, false
+		}
+
+		// Add address
+		vs[k] = net.JoinHostPort(ci.host(), strconv.Itoa(r.Port))
+	}
+	return vs, true
+}
+
+func (ci *clusterInstance) host() string {
+	if ci.h.Host != "" {
+		return ci.h.Host
+	}
+	if u, err := url.Parse(ci.h.Addr); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// unplace resets the placed workflows whose remote receivers are not placed anymore so that they're placed again
+// with their new addresses. It must be called with the mutex locked
+func (c *ClusterCoordinator) unplace() (us []clusterAssignment) {
+	for unplaced := true; unplaced; {
+		unplaced = false
+		for name, cw := range c.ws {
+			// Workflow is not placed or its remote receivers are placed
+			if cw.instance == "" || cw.placing {
+				continue
+			} else if _, ok := c.remoteValues(cw); ok {
+				continue
+			}
+
+			// Unplace
+			ci, ok := c.is[cw.instance]
+			cw.instance = ""
+			unplaced = true
+			if !ok {
+				continue
+			}
+			ci.used = ci.used.sub(cw.w.Requirements)
+			us = append(us, clusterAssignment{ci: ci, cw: cw, name: name})
+		}
+	}
+	return
+}
+
+func (c *ClusterCoordinator) deleteUnplaced(ctx context.Context, us []clusterAssignment) {
+	for _, u := range us {
+		if err := u.ci.c.DeleteWorkflow(ctx, u.name); err != nil && !errors.Is(err, ErrWorkflowNotFound) {
+			c.emitError(fmt.Errorf("astiencoder: deleting unplaced workflow %s from instance %s failed: %w", u.name, u.ci.h.Instance, err))
+		}
+	}
+}
+
+func (c *ClusterCoordinator) schedule(ctx context.Context) {
+	// Lock
+	c.m.Lock()
+
+	// Get pending workflows, oldest first
+	var pws []*clusterWorkflow
+	for _, cw := range c.ws {
+		if cw.instance == "" && !cw.placing {
+			pws = append(pws, cw)
+		}
+	}
+	sort.Slice(pws, func(i, j int) bool { return pws[i].seq < pws[j].seq })
+
+	// Loop through pending workflows
+	var as []clusterAssignment
+	for _, cw := range pws {
+		// Remote receivers are not placed yet
+		values, ok := c.remoteValues(cw)
+		if !ok {
+			continue
+		}
+
+		// Get the instance with the most free CPU that has enough resources
+		var name string
+		var free ClusterResources
+		for n, ci := range c.is {
+			f := ci.h.Capacity.sub(ci.used)
+			if !f.fits(cw.w.Requirements) {
+				continue
+			}
+			if name == "" || f.CPU > free.CPU || (f.CPU == free.CPU && n < name) {
+				name, free = n, f
+			}
+		}
+
+		// No instance has enough resources
+		if name == "" {
+			continue
+		}
+
+		// Reserve resources
+		ci := c.is[name]
+		ci.used = ci.used.add(cw.w.Requirements)
+		cw.instance = name
+		cw.placing = true
+		as = append(as, clusterAssignment{ci: ci, cw: cw, name: name, values: values})
+	}
+	c.m.Unlock()
+
+	// Loop through assignments
+	var placed bool
+	for _, a := range as {
+		// Create workflow
+		err := a.ci.c.CreateWorkflow(ctx, a.cw.w.Definition, a.values, true)
+		if errors.Is(err, ErrWorkflowAlreadyExists) {
+			err = nil
+		}
+
+		// Update workflow
+		c.m.Lock()
+		a.cw.placing = false
+		removed := c.ws[a.cw.w.Definition.Name] != a.cw
+		if err != nil {
+			if !removed {
+				a.ci.used = a.ci.used.sub(a.cw.w.Requirements)
+			}
+			a.cw.instance = ""
+		}
+		c.m.Unlock()
+
+		// Workflow has been removed while it was being placed
+		if err == nil && removed {
+			if err = a.ci.c.DeleteWorkflow(ctx, a.cw.w.Definition.Name); err != nil && !errors.Is(err, ErrWorkflowNotFound) {
+				c.emitError(fmt.Errorf("astiencoder: deleting removed workflow %s from instance %s failed: %w", a.cw.w.Definition.Name, a.name, err))
+			}
+			continue
+		}
+
+		// Emit
+		if err != nil {
+			c.emitError(fmt.Errorf("astiencoder: placing workflow %s onto instance %s failed: %w", a.cw.w.Definition.Name, a.name, err))
+			continue
+		}
+		c.emit(EventNameClusterWorkflowPlaced, ClusterPlacement{
+			Instance: a.name,
+			Workflow: a.cw.w.Definition.Name,
+		})
+		placed = true
+	}
+
+	// Workflows whose remote receivers have just been placed can now be placed as well
+	if placed {
+		c.schedule(ctx)
+	}
+}
+
+// Instances returns the instances of the cluster, sorted by name
+func (c *ClusterCoordinator) Instances() (is []ClusterInstanceStatus) {
+	// Lock
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Loop through instances
+	is = []ClusterInstanceStatus{}
+	for name, ci := range c.is {
+		s := ClusterInstanceStatus{
+			Addr:       ci.h.Addr,
+			Capacity:   ci.h.Capacity,
+			LastSeenAt: ci.lastSeenAt,
+			Name:       name,
+			Used:       ci.used,
+			Workflows:  []string{},
+		}
+		for wn, cw := range c.ws {
+			if cw.instance == name {
+				s.Workflows = append(s.Workflows, wn)
+			}
+		}
+		sort.Strings(s.Workflows)
+		is = append(is, s)
+	}
+
+	// Sort
+	sort.Slice(is, func(i, j int) bool { return is[i].Name < is[j].Name })
+	return
+}
+
+// Workflows returns the workflows of the cluster, sorted by name
+func (c *ClusterCoordinator) Workflows() (ws []ClusterWorkflowStatus) {
+	// Lock
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Loop through workflows
+	ws = []ClusterWorkflowStatus{}
+	for name, cw := range c.ws {
+		s := ClusterWorkflowStatus{
+			Instance: cw.instance,
+			Name:     name,
+			Status:   ClusterWorkflowStatusPending,
+		}
+		if cw.instance != "" && !cw.placing {
+			s.Status = ClusterWorkflowStatusPlaced
+		} else {
+			s.Instance = ""
+		}
+		ws = append(ws, s)
+	}
+
+	// Sort
+	sort.Slice(ws, func(i, j int) bool { return ws[i].Name < ws[j].Name })
+	return
+}
+
+func (c *ClusterCoordinator) emit(name string, payload interface{}) {
+	if c.o.EventHandler == nil {
+		return
+	}
+	c.o.EventHandler.Emit(Event{
+		Name:    name,
+		Payload: payload,
+		Target:  c,
+	})
+}
+
+func (c *ClusterCoordinator) emitError(err error) {
+	c.emit(EventNameError, err)
+}
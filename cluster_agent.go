@@ -0,0 +1,134 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const clusterAgentPeriodDefault = 5 * time.Second
+
+// ClusterAgentOptions represents cluster agent options
+type ClusterAgentOptions struct {
+	// Base URL of the instance's control API, as reachable by the coordinator
+	Addr string
+	// Default is the number of CPUs and no GPU sessions
+	Capacity *ClusterResources
+	// Base URL the coordinator's handler is served under
+	CoordinatorAddr string
+	// Host remote senders reach the instance's remote receivers at. Default is the host of Addr
+	Host string
+	// Default is http.DefaultClient
+	HTTPClient *http.Client
+	// Unique name of the instance
+	Instance string
+	// Default is 5s, it must be lower than the coordinator's heartbeat timeout
+	Period time.Duration
+	// Sent as a bearer token to the coordinator if set
+	Token string
+}
+
+// ClusterAgent makes an encoder instance join a cluster by periodically sending the heartbeats of its control
+// service to the coordinator
+type ClusterAgent struct {
+	c *http.Client
+	o ClusterAgentOptions
+	s *ControlService
+}
+
+// NewClusterAgent creates a new cluster agent
+func NewClusterAgent(s *ControlService, o ClusterAgentOptions) *ClusterAgent {
+	// Default options
+	if o.Capacity == nil {
+		o.Capacity = &ClusterResources{CPU: float64(runtime.NumCPU())}
+	}
+	if o.Period <= 0 {
+		o.Period = clusterAgentPeriodDefault
+	}
+
+	// Create agent
+	a := &ClusterAgent{
+		c: o.HTTPClient,
+		o: o,
+		s: s,
+	}
+	if a.c == nil {
+		a.c = http.DefaultClient
+	}
+	return a
+}
+
+// Heartbeat returns the current heartbeat of the instance
+func (a *ClusterAgent) Heartbeat() (h ClusterHeartbeat) {
+	h = ClusterHeartbeat{
+		Addr:      a.o.Addr,
+		Capacity:  *a.o.Capacity,
+		Host:      a.o.Host,
+		Instance:  a.o.Instance,
+		Workflows: []string{},
+	}
+	for _, w := range a.s.Workflows() {
+		h.Workflows = append(h.Workflows, w.Name)
+	}
+	return
+}
+
+// Start sends heartbeats until the context is done. Failed heartbeats are logged and the next ones are sent anyway
+func (a *ClusterAgent) Start(ctx context.Context) {
+	t := time.NewTicker(a.o.Period)
+	defer t.Stop()
+	for {
+		// Send heartbeat
+		if err := a.send(ctx); err != nil && ctx.Err() == nil {
+			a.s.l.Error("astiencoder: sending cluster heartbeat failed", LogField{Key: LogFieldError, Value: err})
+		}
+
+		// Wait
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (a *ClusterAgent) send(ctx context.Context) (err error) {
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(a.Heartbeat()); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling heartbeat failed: %w", err)
+		return
+	}
+
+	// Create request
+	var req *http.Request
+	if req, err = http.NewRequest(http.MethodPost, strings.TrimRight(a.o.CoordinatorAddr, "/")+"/heartbeats", bytes.NewReader(b)); err != nil {
+		err = fmt.Errorf("astiencoder: creating request failed: %w", err)
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if a.o.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.o.Token)
+	}
+
+	// Send request
+	var resp *http.Response
+	if resp, err = a.c.Do(req); err != nil {
+		err = fmt.Errorf("astiencoder: sending request failed: %w", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Invalid status code
+	if resp.StatusCode != http.StatusNoContent {
+		err = fmt.Errorf("astiencoder: invalid status code %d", resp.StatusCode)
+		return
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Handler returns an http.Handler exposing the coordinator as a REST API:
+//   - POST /heartbeats registers a ClusterHeartbeat (operator)
+//   - GET /instances lists the instances (viewer)
+//   - GET /workflows lists the workflows (viewer)
+//   - POST /workflows submits a ClusterWorkflow (admin)
+//   - DELETE /workflows/<name> removes a workflow (admin)
+//
+// Authentication and errors work the same way as with ControlService.Handler
+func (c *ClusterCoordinator) Handler() http.Handler {
+	// Create router
+	r := httprouter.New()
+
+	// Add routes
+	r.Handler(http.MethodPost, "/heartbeats", authorizeHandler(c.o.Authenticator, RoleOperator, c.heartbeat()))
+	r.Handler(http.MethodGet, "/instances", authorizeHandler(c.o.Authenticator, RoleViewer, c.serveInstances()))
+	r.Handler(http.MethodGet, "/workflows", authorizeHandler(c.o.Authenticator, RoleViewer, c.serveWorkflows()))
+	r.Handler(http.MethodPost, "/workflows", authorizeHandler(c.o.Authenticator, RoleAdmin, c.submitWorkflow()))
+	r.Handler(http.MethodDelete, "/workflows/:name", authorizeHandler(c.o.Authenticator, RoleAdmin, c.removeWorkflow()))
+	return r
+}
+
+func (c *ClusterCoordinator) heartbeat() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var h ClusterHeartbeat
+		if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Register heartbeat
+		if err := c.Heartbeat(r.Context(), h); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (c *ClusterCoordinator) serveInstances() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		writeControlJSON(rw, http.StatusOK, c.Instances())
+	})
+}
+
+func (c *ClusterCoordinator) serveWorkflows() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		writeControlJSON(rw, http.StatusOK, c.Workflows())
+	})
+}
+
+func (c *ClusterCoordinator) submitWorkflow() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var w ClusterWorkflow
+		if err := json.NewDecoder(r.Body).Decode(&w); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Submit
+		if err := c.Submit(r.Context(), w); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func (c *ClusterCoordinator) removeWorkflow() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := c.Remove(r.Context(), httprouter.ParamsFromContext(r.Context()).ByName("name")); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,267 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedClusterInstanceClient struct {
+	err error
+	m   *sync.Mutex
+	ws  map[string]map[string]interface{}
+}
+
+func newMockedClusterInstanceClient() *mockedClusterInstanceClient {
+	return &mockedClusterInstanceClient{
+		m:  &sync.Mutex{},
+		ws: make(map[string]map[string]interface{}),
+	}
+}
+
+func (c *mockedClusterInstanceClient) CreateWorkflow(ctx context.Context, d WorkflowDefinition, values map[string]interface{}, start bool) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.ws[d.Name] = values
+	return nil
+}
+
+func (c *mockedClusterInstanceClient) DeleteWorkflow(ctx context.Context, name string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.ws[name]; !ok {
+		return ErrWorkflowNotFound
+	}
+	delete(c.ws, name)
+	return nil
+}
+
+func (c *mockedClusterInstanceClient) workflows() (ws []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	ws = []string{}
+	for n := range c.ws {
+		ws = append(ws, n)
+	}
+	sort.Strings(ws)
+	return
+}
+
+func TestClusterCoordinator(t *testing.T) {
+	// Create coordinator
+	cs := map[string]*mockedClusterInstanceClient{
+		"i1": newMockedClusterInstanceClient(),
+		"i2": newMockedClusterInstanceClient(),
+	}
+	eh := NewEventHandler()
+	var es []string
+	eh.AddForAll(func(e Event) bool {
+		switch e.Name {
+		case EventNameClusterInstanceLost:
+			es = append(es, "lost "+e.Payload.(string))
+		case EventNameClusterWorkflowPlaced:
+			p := e.Payload.(ClusterPlacement)
+			es = append(es, "placed "+p.Workflow+" "+p.Instance)
+		case EventNameError:
+			es = append(es, "error")
+		}
+		return false
+	})
+	c := NewClusterCoordinator(ClusterCoordinatorOptions{
+		Client:           func(h ClusterHeartbeat) ClusterInstanceClient { return cs[h.Instance] },
+		EventHandler:     eh,
+		HeartbeatTimeout: 10 * time.Second,
+	})
+	now := time.Unix(100, 0)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	w := func(name string, cpu float64, gpu int) ClusterWorkflow {
+		return ClusterWorkflow{Definition: WorkflowDefinition{Name: name}, Requirements: ClusterResources{CPU: cpu, GPUSessions: gpu}}
+	}
+
+	// Workflows are pending until instances join
+	assert.NoError(t, c.Submit(ctx, w("w1", 2, 1)))
+	assert.True(t, errors.Is(c.Submit(ctx, w("w1", 2, 1)), ErrWorkflowAlreadyExists))
+	assert.Error(t, c.Submit(ctx, w("", 2, 1)))
+	assert.Equal(t, []ClusterWorkflowStatus{{Name: "w1", Status: ClusterWorkflowStatusPending}}, c.Workflows())
+
+	// Workflows are placed onto the instance with the most free CPU having enough resources
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a1", Capacity: ClusterResources{CPU: 4}, Instance: "i1"}))
+	assert.Equal(t, []ClusterWorkflowStatus{{Name: "w1", Status: ClusterWorkflowStatusPending}}, c.Workflows())
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a2", Capacity: ClusterResources{CPU: 3, GPUSessions: 2}, Instance: "i2"}))
+	assert.NoError(t, c.Submit(ctx, w("w2", 2, 0)))
+	assert.NoError(t, c.Submit(ctx, w("w3", 2, 0)))
+	assert.NoError(t, c.Submit(ctx, w("w4", 2, 0)))
+	assert.Equal(t, []string{"w1"}, cs["i2"].workflows())
+	assert.Equal(t, []string{"w2", "w3"}, cs["i1"].workflows())
+	assert.Equal(t, []ClusterWorkflowStatus{
+		{Instance: "i2", Name: "w1", Status: ClusterWorkflowStatusPlaced},
+		{Instance: "i1", Name: "w2", Status: ClusterWorkflowStatusPlaced},
+		{Instance: "i1", Name: "w3", Status: ClusterWorkflowStatusPlaced},
+		{Name: "w4", Status: ClusterWorkflowStatusPending},
+	}, c.Workflows())
+	is := c.Instances()
+	assert.Len(t, is, 2)
+	assert.Equal(t, ClusterResources{CPU: 4}, is[0].Used)
+	assert.Equal(t, []string{"w2", "w3"}, is[0].Workflows)
+
+	// Removing a workflow frees its resources
+	assert.NoError(t, c.Remove(ctx, "w2"))
+	assert.True(t, errors.Is(c.Remove(ctx, "w2"), ErrWorkflowNotFound))
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a1", Capacity: ClusterResources{CPU: 4}, Instance: "i1", Workflows: []string{"w3"}}))
+	assert.Equal(t, []string{"w3", "w4"}, cs["i1"].workflows())
+
+	// Lost instances' workflows are rescheduled
+	now = now.Add(5 * time.Second)
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a2", Capacity: ClusterResources{CPU: 3, GPUSessions: 2}, Instance: "i2", Workflows: []string{"w1"}}))
+	assert.NoError(t, c.Remove(ctx, "w1"))
+	assert.Equal(t, []string{}, cs["i2"].workflows())
+	now = now.Add(6 * time.Second)
+	c.check(ctx)
+	assert.Equal(t, []ClusterWorkflowStatus{
+		{Instance: "i2", Name: "w3", Status: ClusterWorkflowStatusPlaced},
+		{Name: "w4", Status: ClusterWorkflowStatusPending},
+	}, c.Workflows())
+	assert.Len(t, c.Instances(), 1)
+
+	// Instances coming back keep their pending workflows and get rid of the rescheduled ones
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a1", Capacity: ClusterResources{CPU: 4}, Instance: "i1", Workflows: []string{"w3", "w4"}}))
+	assert.Equal(t, []string{"w4"}, cs["i1"].workflows())
+	assert.Equal(t, []ClusterWorkflowStatus{
+		{Instance: "i2", Name: "w3", Status: ClusterWorkflowStatusPlaced},
+		{Instance: "i1", Name: "w4", Status: ClusterWorkflowStatusPlaced},
+	}, c.Workflows())
+
+	// Failed placements are retried
+	cs["i2"].err = errors.New("test")
+	assert.NoError(t, c.Submit(ctx, w("w5", 1, 1)))
+	assert.Equal(t, ClusterWorkflowStatus{Name: "w5", Status: ClusterWorkflowStatusPending}, c.Workflows()[2])
+	assert.Equal(t, ClusterResources{CPU: 2}, c.Instances()[1].Used)
+	cs["i2"].err = nil
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "a2", Capacity: ClusterResources{CPU: 3, GPUSessions: 2}, Instance: "i2", Workflows: []string{"w3"}}))
+	assert.Equal(t, ClusterWorkflowStatus{Instance: "i2", Name: "w5", Status: ClusterWorkflowStatusPlaced}, c.Workflows()[2])
+	assert.Equal(t, []string{"w3", "w5"}, cs["i2"].workflows())
+
+	// Events
+	assert.Equal(t, []string{"placed w1 i2", "placed w2 i1", "placed w3 i1", "placed w4 i1", "lost i1", "placed w3 i2", "placed w4 i1", "error", "placed w5 i2"}, es)
+}
+
+func TestClusterCoordinatorRemotes(t *testing.T) {
+	// Create coordinator
+	cs := map[string]*mockedClusterInstanceClient{
+		"i1": newMockedClusterInstanceClient(),
+		"i2": newMockedClusterInstanceClient(),
+	}
+	eh := NewEventHandler()
+	var es []string
+	eh.AddForEventName(EventNameClusterWorkflowPlaced, func(e Event) bool {
+		p := e.Payload.(ClusterPlacement)
+		es = append(es, p.Workflow+" "+p.Instance)
+		return false
+	})
+	c := NewClusterCoordinator(ClusterCoordinatorOptions{
+		Client:           func(h ClusterHeartbeat) ClusterInstanceClient { return cs[h.Instance] },
+		EventHandler:     eh,
+		HeartbeatTimeout: 10 * time.Second,
+	})
+	now := time.Unix(100, 0)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "http://host1:4000/api", Capacity: ClusterResources{CPU: 4}, Instance: "i1"}))
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "http://host2:4000/api", Capacity: ClusterResources{CPU: 4}, Host: "10.0.0.2", Instance: "i2"}))
+
+	// Senders wait for their receivers to be placed
+	assert.NoError(t, c.Submit(ctx, ClusterWorkflow{
+		Definition:   WorkflowDefinition{Name: "sender"},
+		Remotes:      map[string]ClusterRemote{"addr": {Port: 4001, Workflow: "receiver"}},
+		Requirements: ClusterResources{CPU: 1},
+		Values:       map[string]interface{}{"url": "rtmp://source"},
+	}))
+	assert.Equal(t, []ClusterWorkflowStatus{{Name: "sender", Status: ClusterWorkflowStatusPending}}, c.Workflows())
+	assert.NoError(t, c.Submit(ctx, ClusterWorkflow{
+		Definition:   WorkflowDefinition{Name: "receiver"},
+		Requirements: ClusterResources{CPU: 3},
+	}))
+	assert.Equal(t, map[string]interface{}{"addr": "host1:4001", "url": "rtmp://source"}, cs["i2"].ws["sender"])
+
+	// Senders are placed again when their receivers move
+	now = now.Add(5 * time.Second)
+	assert.NoError(t, c.Heartbeat(ctx, ClusterHeartbeat{Addr: "http://host2:4000/api", Capacity: ClusterResources{CPU: 4}, Host: "10.0.0.2", Instance: "i2", Workflows: []string{"sender"}}))
+	now = now.Add(6 * time.Second)
+	c.check(ctx)
+	assert.Equal(t, []ClusterWorkflowStatus{
+		{Instance: "i2", Name: "receiver", Status: ClusterWorkflowStatusPlaced},
+		{Instance: "i2", Name: "sender", Status: ClusterWorkflowStatusPlaced},
+	}, c.Workflows())
+	assert.Equal(t, map[string]interface{}{"addr": "10.0.0.2:4001", "url": "rtmp://source"}, cs["i2"].ws["sender"])
+
+	// Senders are unplaced when their receivers are removed
+	assert.NoError(t, c.Remove(ctx, "receiver"))
+	assert.Equal(t, []ClusterWorkflowStatus{{Name: "sender", Status: ClusterWorkflowStatusPending}}, c.Workflows())
+	assert.Equal(t, []string{}, cs["i2"].workflows())
+	assert.Equal(t, []string{"receiver i1", "sender i2", "receiver i2", "sender i2"}, es)
+}
+
+func TestClusterAgent(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// Create instance
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts}})
+	is := httptest.NewServer(s.Handler())
+	defer is.Close()
+
+	// Create coordinator
+	c := NewClusterCoordinator(ClusterCoordinatorOptions{})
+	cs := httptest.NewServer(c.Handler())
+	defer cs.Close()
+
+	// Submit workflow
+	assert.NoError(t, c.Submit(context.Background(), ClusterWorkflow{
+		Definition:   WorkflowDefinition{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion},
+		Requirements: ClusterResources{CPU: 1},
+	}))
+
+	// Start agent
+	a := NewClusterAgent(s, ClusterAgentOptions{
+		Addr:            is.URL,
+		Capacity:        &ClusterResources{CPU: 2},
+		CoordinatorAddr: cs.URL,
+		Instance:        "i",
+		Period:          10 * time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go a.Start(ctx)
+
+	// Workflow is placed onto the instance
+	for i := 0; ; i++ {
+		if ws := s.Workflows(); len(ws) == 1 && ws[0].Name == "w" {
+			break
+		}
+		if i > 100 {
+			t.Fatal("workflow has not been placed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []ClusterWorkflowStatus{{Instance: "i", Name: "w", Status: ClusterWorkflowStatusPlaced}}, c.Workflows())
+
+	// Workflow is deleted from the instance once removed
+	assert.NoError(t, c.Remove(context.Background(), "w"))
+	assert.Equal(t, []ControlWorkflow{}, s.Workflows())
+}
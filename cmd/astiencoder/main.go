@@ -0,0 +1,450 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	astilibav "github.com/asticode/go-astiencoder/libav"
+	"github.com/asticode/go-astikit"
+)
+
+// Flags
+var (
+	addr      = flag.String("addr", os.Getenv("ASTIENCODER_ADDR"), "the base URL of the control API, e.g. http://encoder-1:4000/api")
+	caFile    = flag.String("ca-file", os.Getenv("ASTIENCODER_CA_FILE"), "the PEM encoded CAs the control API's certificate must be signed by")
+	certFile  = flag.String("cert-file", os.Getenv("ASTIENCODER_CERT_FILE"), "the PEM encoded certificate presented to the control API")
+	keyFile   = flag.String("key-file", os.Getenv("ASTIENCODER_KEY_FILE"), "the PEM encoded private key of -cert-file")
+	namespace = flag.String("namespace", os.Getenv("ASTIENCODER_NAMESPACE"), "the namespace of the workflows of the control API")
+	timeout   = flag.Duration("timeout", 10*time.Second, "the timeout of control API calls")
+	token     = flag.String("token", os.Getenv("ASTIENCODER_TOKEN"), "the bearer token sent to the control API")
+	values    = flagValues{}
+)
+
+const usage = `Usage: astiencoder <command> [flags] [argument]
+
+Commands:
+  run <definition>       builds a definition with the libav node types and runs it until it stops
+  validate <definition>  validates a definition without instantiating its nodes
+  dot <definition>       prints the Graphviz representation of a definition
+  list                   lists the workflows of a control service
+  reload                 reloads the workflows of a control service from its definition source
+  status <workflow>      prints the status of a workflow of a control service
+  start <workflow>       starts a workflow of a control service
+  stop <workflow>        stops a workflow of a control service
+
+Flags:
+`
+
+func main() {
+	// Parse flags
+	flag.Var(values, "var", "the value of a definition variable in the key=value format, can be set several times")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	cmd := astikit.FlagCmd()
+	flag.Parse()
+
+	// Create logger
+	l := log.New(log.Writer(), log.Prefix(), log.Flags())
+
+	// Switch on command
+	var err error
+	switch cmd {
+	case "dot":
+		err = dot()
+	case "list":
+		err = list()
+	case "reload":
+		err = reload()
+	case "run":
+		err = run(l)
+	case "start":
+		err = control(func(ctx context.Context, c *astiencoder.ControlClient, name string) error {
+			return c.StartWorkflow(ctx, name)
+		})
+	case "status":
+		err = status()
+	case "stop":
+		err = control(func(ctx context.Context, c *astiencoder.ControlClient, name string) error {
+			return c.StopWorkflow(ctx, name)
+		})
+	case "validate":
+		err = validate(l)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Process error
+	if err != nil {
+		var es astiencoder.DefinitionErrors
+		if errors.As(err, &es) {
+			for _, e := range es {
+				l.Println(e)
+			}
+			os.Exit(1)
+		}
+		l.Fatal(err)
+	}
+}
+
+type flagValues map[string]interface{}
+
+// String implements the flag.Value interface
+func (f flagValues) String() string {
+	var ss []string
+	for k, v := range f {
+		ss = append(ss, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(ss, ",")
+}
+
+// Set implements the flag.Value interface
+// Values are decoded as JSON so that numbers and booleans keep their type, and are kept as strings otherwise
+func (f flagValues) Set(i string) error {
+	// Split
+	ps := strings.SplitN(i, "=", 2)
+	if len(ps) != 2 || ps[0] == "" {
+		return fmt.Errorf("main: %s is not in the key=value format", i)
+	}
+
+	// Decode
+	var v interface{}
+	if err := json.Unmarshal([]byte(ps[1]), &v); err != nil {
+		v = ps[1]
+	}
+	f[ps[0]] = v
+	return nil
+}
+
+func arg(name string) (string, error) {
+	if flag.NArg() != 1 {
+		return "", fmt.Errorf("main: expected exactly one %s argument, got %d", name, flag.NArg())
+	}
+	return flag.Arg(0), nil
+}
+
+func loadDefinition() (d astiencoder.WorkflowDefinition, err error) {
+	// Get path
+	var path string
+	if path, err = arg("definition"); err != nil {
+		return
+	}
+
+	// Load
+	if d, err = astiencoder.LoadWorkflowDefinitionFile(path); err != nil {
+		err = fmt.Errorf("main: loading definition %s failed: %w", path, err)
+		return
+	}
+	return
+}
+
+func nodeTypes() *astiencoder.NodeTypes {
+	ts := astiencoder.NewNodeTypes()
+	astilibav.RegisterNodeTypes(ts)
+	return ts
+}
+
+func dot() (err error) {
+	// Load definition
+	var d astiencoder.WorkflowDefinition
+	if d, err = loadDefinition(); err != nil {
+		return
+	}
+
+	// Write
+	if _, err = os.Stdout.Write(d.DOT()); err != nil {
+		err = fmt.Errorf("main: writing failed: %w", err)
+		return
+	}
+	return
+}
+
+func validate(l *log.Logger) (err error) {
+	// Load definition
+	var d astiencoder.WorkflowDefinition
+	if d, err = loadDefinition(); err != nil {
+		return
+	}
+
+	// Validate
+	var ws []astiencoder.DefinitionWarning
+	if ws, err = astiencoder.ValidateWorkflowDefinition(d, astiencoder.BuildWorkflowOptions{
+		Types:  nodeTypes(),
+		Values: values,
+	}); err != nil {
+		return
+	}
+
+	// Log warnings
+	for _, w := range ws {
+		l.Printf("%s: %s", w.Path, w.Message)
+	}
+	return
+}
+
+func run(l *log.Logger) (err error) {
+	// Load definition
+	var d astiencoder.WorkflowDefinition
+	if d, err = loadDefinition(); err != nil {
+		return
+	}
+
+	// Create worker
+	w := astikit.NewWorker(astikit.WorkerOptions{Logger: l})
+	defer w.Stop()
+
+	// Create event handler
+	eh := astiencoder.NewEventHandler()
+	defer eh.Close()
+	astiencoder.LoggerEventHandlerAdapter(l, eh)
+
+	// Make sure the worker stops when the workflow is stopped
+	eh.AddForEventName(astiencoder.EventNameWorkflowStopped, func(e astiencoder.Event) bool {
+		w.Stop()
+		return false
+	})
+
+	// Create closer
+	c := astikit.NewCloser()
+	defer c.Close()
+
+	// Build workflow
+	var wf *astiencoder.Workflow
+	if wf, err = astiencoder.BuildWorkflow(d, astiencoder.BuildWorkflowOptions{
+		Closer:       c,
+		Context:      w.Context(),
+		EventHandler: eh,
+		Substitution: &astiencoder.SubstitutionOptions{},
+		TaskFunc:     w.NewTask,
+		Types:        nodeTypes(),
+		Values:       values,
+	}); err != nil {
+		err = fmt.Errorf("main: building workflow failed: %w", err)
+		return
+	}
+
+	// Handle signals
+	// SIGHUP reloads the definition without dropping the streams that haven't changed
+	w.HandleSignals(func(s os.Signal) {
+		if s != syscall.SIGHUP {
+			return
+		}
+		if err := reloadWorkflow(wf, l); err != nil {
+			l.Println(fmt.Errorf("main: reloading workflow failed: %w", err))
+		}
+	})
+
+	// Start workflow
+	wf.Start()
+
+	// Wait
+	w.Wait()
+	return
+}
+
+func reloadWorkflow(wf *astiencoder.Workflow, l *log.Logger) (err error) {
+	// Load definition
+	var d astiencoder.WorkflowDefinition
+	if d, err = loadDefinition(); err != nil {
+		return
+	}
+
+	// Reload
+	var cs astiencoder.DefinitionChanges
+	if cs, err = wf.Reload(d); err != nil {
+		return
+	}
+
+	// Log
+	if len(cs) == 0 {
+		l.Println("main: definition hasn't changed")
+		return
+	}
+	for _, c := range cs {
+		switch {
+		case c.Node != nil:
+			l.Printf("main: %s %s\n", c.Type, c.Node.Name)
+		case c.Connection != nil:
+			l.Printf("main: %s %s -> %s\n", c.Type, c.Connection.From, c.Connection.To)
+		}
+	}
+	return
+}
+
+func newControlClient() (*astiencoder.ControlClient, context.Context, context.CancelFunc, error) {
+	// No address
+	if *addr == "" {
+		return nil, nil, nil, errors.New("main: -addr or ASTIENCODER_ADDR is mandatory")
+	}
+
+	// Create http client
+	var hc *http.Client
+	if *caFile != "" || *certFile != "" || *keyFile != "" {
+		t, err := astiencoder.TLSClientOptions{
+			CAFile:   *caFile,
+			CertFile: *certFile,
+			KeyFile:  *keyFile,
+		}.Config()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("main: getting tls config failed: %w", err)
+		}
+		hc = &http.Client{Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: t,
+		}}
+	}
+
+	// Create client
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	return astiencoder.NewControlClient(astiencoder.ControlClientOptions{
+		Addr:       *addr,
+		HTTPClient: hc,
+		Namespace:  *namespace,
+		Token:      *token,
+	}), ctx, cancel, nil
+}
+
+func control(fn func(ctx context.Context, c *astiencoder.ControlClient, name string) error) (err error) {
+	// Get name
+	var name string
+	if name, err = arg("workflow"); err != nil {
+		return
+	}
+
+	// Create client
+	c, ctx, cancel, err := newControlClient()
+	if err != nil {
+		return
+	}
+	defer cancel()
+
+	// Control
+	return fn(ctx, c, name)
+}
+
+func list() (err error) {
+	// Create client
+	c, ctx, cancel, err := newControlClient()
+	if err != nil {
+		return
+	}
+	defer cancel()
+
+	// List
+	var ws []astiencoder.ControlWorkflow
+	if ws, err = c.Workflows(ctx); err != nil {
+		return
+	}
+
+	// Write
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS")
+	for _, w := range ws {
+		fmt.Fprintf(tw, "%s\t%s\n", w.Name, w.Status)
+	}
+	return tw.Flush()
+}
+
+func reload() (err error) {
+	// Create client
+	c, ctx, cancel, err := newControlClient()
+	if err != nil {
+		return
+	}
+	defer cancel()
+
+	// Reload
+	var r astiencoder.ControlReloadResult
+	if r, err = c.Reload(ctx); err != nil {
+		return
+	}
+
+	// Write
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tRESULT")
+	for _, name := range r.Created {
+		fmt.Fprintf(tw, "%s\tcreated\n", name)
+	}
+	patched := make([]string, 0, len(r.Patched))
+	for name := range r.Patched {
+		patched = append(patched, name)
+	}
+	sort.Strings(patched)
+	for _, name := range patched {
+		fmt.Fprintf(tw, "%s\tpatched (%d changes)\n", name, len(r.Patched[name]))
+	}
+	for _, name := range r.Unchanged {
+		fmt.Fprintf(tw, "%s\tunchanged\n", name)
+	}
+	for _, name := range r.Deleted {
+		fmt.Fprintf(tw, "%s\tdeleted\n", name)
+	}
+	failed := make([]string, 0, len(r.Errors))
+	for name := range r.Errors {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+	for _, name := range failed {
+		fmt.Fprintf(tw, "%s\tfailed: %s\n", name, r.Errors[name])
+	}
+	if err = tw.Flush(); err != nil {
+		return
+	}
+
+	// Some workflows have failed
+	if len(r.Errors) > 0 {
+		err = fmt.Errorf("main: %d workflow(s) failed to reload", len(r.Errors))
+	}
+	return
+}
+
+func status() (err error) {
+	// Get name
+	var name string
+	if name, err = arg("workflow"); err != nil {
+		return
+	}
+
+	// Create client
+	c, ctx, cancel, err := newControlClient()
+	if err != nil {
+		return
+	}
+	defer cancel()
+
+	// Get snapshot
+	var s astiencoder.ServerSnapshot
+	if s, err = c.Workflow(ctx, name); err != nil {
+		return
+	}
+
+	// Write workflow
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Workflow:\t%s\n", s.Name)
+	fmt.Fprintf(tw, "Status:\t%s\n", s.Status)
+	if s.Progress != nil {
+		fmt.Fprintf(tw, "Progress:\t%.1f%%\n", s.Progress.Ratio*100)
+	}
+
+	// Write nodes
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "NODE\tSTATUS\tPARENTS")
+	for _, n := range s.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", n.Name, n.Status, strings.Join(n.Parents, ","))
+	}
+	return tw.Flush()
+}
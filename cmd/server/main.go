@@ -13,17 +13,28 @@ func main() {
 	// Set logger
 	log.SetFlags(0)
 
+	// Bind
+	bind("web", "server_bind.go", "Server", "serveHomepage")
+	bind("web/control", "control_bind.go", "ControlService", "serveWebUI")
+}
+
+func bind(dir, path, receiver, method string) {
 	// Read web dir
-	fs, err := ioutil.ReadDir("web")
+	fs, err := ioutil.ReadDir(dir)
 	if err != nil {
-		log.Fatal(fmt.Errorf("main: reading web dir failed: %w", err))
+		log.Fatal(fmt.Errorf("main: reading %s dir failed: %w", dir, err))
 	}
 
 	// Loop through files
 	cs := make(map[string][]byte)
 	for _, f := range fs {
+		// Skip dirs
+		if f.IsDir() {
+			continue
+		}
+
 		// Get content
-		b, err := ioutil.ReadFile("web/" + f.Name())
+		b, err := ioutil.ReadFile(dir + "/" + f.Name())
 		if err != nil {
 			log.Fatal(fmt.Errorf("main: reading %s file failed: %w", f.Name(), err))
 		}
@@ -43,7 +54,7 @@ func main() {
 	}
 
 	// Create bind file
-	f, err := os.Create("server_bind.go")
+	f, err := os.Create(path)
 	if err != nil {
 		log.Fatal(fmt.Errorf("main: creating bind file failed: %w", err))
 	}
@@ -54,11 +65,11 @@ func main() {
 
 import "net/http"
 
-func (s *Server) serveHomepage() http.Handler {
+func (s *` + receiver + `) ` + method + `() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		// Write
 		if _, err := rw.Write([]byte{` + strings.Join(bs, ",") + `}); err != nil {
-			s.l.Errorf("astiencoder: writing failed: %w")
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
 			rw.WriteHeader(http.StatusInternalServerError)
 			return
 		}
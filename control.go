@@ -0,0 +1,487 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astiws"
+)
+
+// Control errors, wrapped by the errors returned by the control service so that transports can map them to their
+// own codes
+var (
+	ErrNodeNotFound           = errors.New("astiencoder: node not found")
+	ErrQuotaExceeded          = errors.New("astiencoder: quota exceeded")
+	ErrRateLimited            = errors.New("astiencoder: rate limited")
+	ErrShuttingDown           = errors.New("astiencoder: shutting down")
+	ErrWorkflowAlreadyExists  = errors.New("astiencoder: workflow already exists")
+	ErrWorkflowAlreadyStarted = errors.New("astiencoder: workflow already started")
+	ErrWorkflowNotFound       = errors.New("astiencoder: workflow not found")
+)
+
+type nodeNotFoundError string
+
+func (e nodeNotFoundError) Error() string {
+	return fmt.Sprintf("astiencoder: node %s doesn't exist", string(e))
+}
+
+func (e nodeNotFoundError) Is(target error) bool {
+	return target == ErrNodeNotFound
+}
+
+const controlSubscriptionBufferSizeDefault = 100
+
+// ControlServiceOptions represents control service options
+type ControlServiceOptions struct {
+	// If set, transports authenticate their callers with it and authorize them according to their role, and the
+	// authenticated identity is the actor of the operations. Otherwise callers are trusted, are allowed everything and
+	// provide the actor themselves, e.g. in the X-Astiencoder-Actor header
+	Authenticator Authenticator
+	// Used to build the workflows. Its Closer, EventHandler and Values are set by the service and its Context is
+	// the parent of the workflows' context
+	Build BuildWorkflowOptions
+	// If set, the handler lists, adds and cancels its jobs under /jobs
+	JobQueue *JobQueue
+	// If set, the events of every workflow are forwarded to it
+	EventHandler *EventHandler
+	// Used by the transports, e.g. to log websocket errors
+	Logger Logger
+	// If true, every request served by the HTTP handler, the websocket commands and the gRPC server is logged with its
+	// method, path, status code, duration, actor and remote address
+	LogRequests bool
+	// Quotas of the namespaces, indexed by namespace. Namespaces without quota are not limited
+	Quotas map[string]ControlQuota
+	// If set, the requests of clients exceeding it are rejected by the HTTP handler, the websocket commands and the gRPC
+	// server
+	RateLimit *ControlRateLimit
+	// If set, Reload applies its definitions to the workflows
+	Source DefinitionSource
+	// Used to start the workflows, e.g. to emit stats periodically
+	Start WorkflowStartOptions
+	// If true, the handler serves a web UI under / rendering the graph of the workflows with their live statuses and
+	// stats
+	WebUI bool
+}
+
+// ControlService represents a transport agnostic API to create, control and observe workflows built from
+// definitions. It is meant to be wrapped by transports, e.g. the gRPC service described in proto/astiencoder.proto
+// Control operations are performed on behalf of an actor so that they're audited
+type ControlService struct {
+	closing bool
+	l       Logger
+	m       *sync.Mutex // Locks ws
+	mr      *sync.Mutex // Makes sure reloads are performed one at a time
+	ms      *sync.Mutex // Locks ss
+	o       ControlServiceOptions
+	rl      *controlRateLimiter
+	ss      map[*controlSubscription]bool
+	wm      *astiws.Manager
+	ws      map[string]*controlWorkflow
+}
+
+type controlWorkflow struct {
+	c       *astikit.Closer
+	cancel  context.CancelFunc
+	sourced bool // Created or reloaded from the source
+	started bool
+	w       *Workflow
+}
+
+// ControlWorkflow represents a workflow of a control service
+type ControlWorkflow struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// ControlEvent represents an event emitted by a workflow of a control service
+type ControlEvent struct {
+	Name string `json:"name"`
+	// Empty if the event has not been emitted by a node
+	Node string `json:"node,omitempty"`
+	// JSON friendly payload, as sent by the server's websocket
+	Payload  interface{} `json:"payload"`
+	Workflow string      `json:"workflow"`
+}
+
+// ControlSubscriptionOptions represents control subscription options
+type ControlSubscriptionOptions struct {
+	// Number of events buffered before events are dropped. Default is 100
+	BufferSize int
+	// If set, only events with those names are received
+	Names []string
+	// If set, only events of the workflows of this namespace are received and their workflow names are relative to it
+	Namespace string
+	// If set, only events of this workflow are received
+	Workflow string
+}
+
+type controlSubscription struct {
+	c     chan ControlEvent
+	names map[string]bool
+	o     ControlSubscriptionOptions
+}
+
+// NewControlService creates a new control service
+func NewControlService(o ControlServiceOptions) *ControlService {
+	// Default options
+	if o.Build.Context == nil {
+		o.Build.Context = context.Background()
+	}
+
+	// Create service
+	l := logger(o.Logger)
+	s := &ControlService{
+		l:  l,
+		m:  &sync.Mutex{},
+		mr: &sync.Mutex{},
+		ms: &sync.Mutex{},
+		o:  o,
+		ss: make(map[*controlSubscription]bool),
+		wm: astiws.NewManager(astiws.ManagerConfiguration{MaxMessageSize: 8192}, loggerStdLogger{l: l}),
+		ws: make(map[string]*controlWorkflow),
+	}
+
+	// Create rate limiter
+	if o.RateLimit != nil {
+		s.rl = newControlRateLimiter(*o.RateLimit)
+	}
+	return s
+}
+
+// Authenticate authenticates the token provided by a caller with the Authenticator option. If it's not set, the
+// caller is trusted and has the admin role, and the actor is returned as the identity's name
+// Transports such as the gRPC service call it before authorizing the identity with Identity.Authorize
+func (s *ControlService) Authenticate(ctx context.Context, token, actor string) (Identity, error) {
+	return authenticate(ctx, s.o.Authenticator, token, actor)
+}
+
+// CreateWorkflow builds a workflow from a definition. Its name must be unique within the service
+// Its name is either <name> or <namespace>/<name> (see NamespacedWorkflowName), in which case it belongs to the
+// namespace and counts towards its quota
+func (s *ControlService) CreateWorkflow(actor string, d WorkflowDefinition, values map[string]interface{}) (w *Workflow, err error) {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Service is shutting down
+	if s.closing {
+		err = fmt.Errorf("astiencoder: creating workflow %s failed: %w", d.Name, ErrShuttingDown)
+		return
+	}
+
+	// Invalid name
+	if err = validateControlWorkflowName(d.Name); err != nil {
+		err = fmt.Errorf("astiencoder: creating workflow failed: %w", DefinitionErrors{newDefinitionError("name", err)})
+		return
+	}
+
+	// Workflow already exists
+	if _, ok := s.ws[d.Name]; ok {
+		err = fmt.Errorf("astiencoder: creating workflow %s failed: %w", d.Name, ErrWorkflowAlreadyExists)
+		return
+	}
+
+	// Check quota
+	ns, _ := SplitNamespacedWorkflowName(d.Name)
+	if err = s.checkQuota(ns, s.o.Quotas[ns].MaxWorkflows, func(cw *controlWorkflow) bool { return true }); err != nil {
+		err = fmt.Errorf("astiencoder: creating workflow %s failed: %w", d.Name, err)
+		return
+	}
+
+	// Create event handler
+	name := d.Name
+	eh := NewEventHandler()
+	eh.AddForAll(func(e Event) bool {
+		s.dispatch(name, e)
+		if s.o.EventHandler != nil {
+			s.o.EventHandler.Emit(e)
+		}
+		return false
+	})
+
+	// Build workflow
+	cw := &controlWorkflow{c: astikit.NewCloser()}
+	o := s.o.Build
+	o.Closer = cw.c
+	o.Context, cw.cancel = context.WithCancel(s.o.Build.Context)
+	o.EventHandler = eh
+	o.Values = values
+	if cw.w, err = BuildWorkflow(d, o); err != nil {
+		cw.cancel()
+		cw.c.Close()
+		err = fmt.Errorf("astiencoder: building workflow %s failed: %w", d.Name, err)
+		return
+	}
+
+	// Store workflow
+	s.ws[name] = cw
+	w = cw.w
+
+	// Audit
+	w.audit(nil, actor, AuditActionCreate, nil, nil)
+	return
+}
+
+// DeleteWorkflow stops a workflow and removes it from the service
+func (s *ControlService) DeleteWorkflow(actor, name string) (err error) {
+	// Lock
+	s.m.Lock()
+
+	// Get workflow
+	cw, ok := s.ws[name]
+	if !ok {
+		s.m.Unlock()
+		return fmt.Errorf("astiencoder: deleting workflow %s failed: %w", name, ErrWorkflowNotFound)
+	}
+
+	// Delete workflow
+	delete(s.ws, name)
+	started := cw.started
+	s.m.Unlock()
+
+	// Stop workflow. Started workflows close themselves once stopped
+	if started {
+		cw.w.Controller(actor).Stop()
+	} else if err = cw.c.Close(); err != nil {
+		err = fmt.Errorf("astiencoder: closing workflow %s failed: %w", name, err)
+	}
+	cw.cancel()
+
+	// Audit
+	cw.w.audit(nil, actor, AuditActionDelete, nil, err)
+	return
+}
+
+// Workflow returns a workflow of the service
+func (s *ControlService) Workflow(name string) (*Workflow, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	cw, ok := s.ws[name]
+	if !ok {
+		return nil, fmt.Errorf("astiencoder: getting workflow %s failed: %w", name, ErrWorkflowNotFound)
+	}
+	return cw.w, nil
+}
+
+// Workflows returns the workflows of the service, sorted by name
+func (s *ControlService) Workflows() (ws []ControlWorkflow) {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Loop through workflows
+	ws = []ControlWorkflow{}
+	for name, cw := range s.ws {
+		ws = append(ws, ControlWorkflow{
+			Name:   name,
+			Status: cw.w.Status(),
+		})
+	}
+
+	// Sort
+	sort.Slice(ws, func(i, j int) bool { return ws[i].Name < ws[j].Name })
+	return
+}
+
+// StartWorkflow starts a workflow. A workflow can only be started once: once stopped, it must be deleted and created
+// again
+func (s *ControlService) StartWorkflow(actor, name string) error {
+	// Lock
+	s.m.Lock()
+
+	// Service is shutting down
+	if s.closing {
+		s.m.Unlock()
+		return fmt.Errorf("astiencoder: starting workflow %s failed: %w", name, ErrShuttingDown)
+	}
+
+	// Get workflow
+	cw, ok := s.ws[name]
+	if !ok {
+		s.m.Unlock()
+		return fmt.Errorf("astiencoder: starting workflow %s failed: %w", name, ErrWorkflowNotFound)
+	}
+
+	// Workflow has already been started
+	if cw.started {
+		s.m.Unlock()
+		return fmt.Errorf("astiencoder: starting workflow %s failed: %w", name, ErrWorkflowAlreadyStarted)
+	}
+
+	// Check quota
+	ns, _ := SplitNamespacedWorkflowName(name)
+	if err := s.checkQuota(ns, s.o.Quotas[ns].MaxStartedWorkflows, func(cw *controlWorkflow) bool {
+		return cw.started && cw.w.Status() != StatusStopped
+	}); err != nil {
+		s.m.Unlock()
+		return fmt.Errorf("astiencoder: starting workflow %s failed: %w", name, err)
+	}
+	cw.started = true
+	s.m.Unlock()
+
+	// Start
+	cw.w.Controller(actor).StartWithOptions(s.o.Start)
+	return nil
+}
+
+// StopWorkflow stops a workflow
+func (s *ControlService) StopWorkflow(actor, name string) error {
+	return s.do(name, "stopping", func(w *Workflow) error {
+		w.Controller(actor).Stop()
+		return nil
+	})
+}
+
+// PauseWorkflow pauses a workflow
+func (s *ControlService) PauseWorkflow(actor, name string) error {
+	return s.do(name, "pausing", func(w *Workflow) error {
+		w.Controller(actor).Pause()
+		return nil
+	})
+}
+
+// ContinueWorkflow continues a paused workflow
+func (s *ControlService) ContinueWorkflow(actor, name string) error {
+	return s.do(name, "continuing", func(w *Workflow) error {
+		w.Controller(actor).Continue()
+		return nil
+	})
+}
+
+// SeekWorkflow seeks the inputs of a workflow to a position relative to their start. If a node name is provided,
+// only this node is seeked
+func (s *ControlService) SeekWorkflow(actor, name, node string, position time.Duration) error {
+	return s.do(name, "seeking", func(w *Workflow) error {
+		return w.Controller(actor).Seek(node, position)
+	})
+}
+
+// ReconfigureNode applies changed options to a node of a workflow without restarting it
+func (s *ControlService) ReconfigureNode(actor, workflow, node string, options map[string]interface{}) error {
+	return s.do(workflow, "reconfiguring", func(w *Workflow) error {
+		return w.Controller(actor).Reconfigure(node, options)
+	})
+}
+
+// PauseNode pauses a node of a workflow
+func (s *ControlService) PauseNode(actor, workflow, node string) error {
+	return s.do(workflow, "pausing node of", func(w *Workflow) error {
+		return w.Controller(actor).PauseNode(node)
+	})
+}
+
+// ContinueNode continues a paused node of a workflow
+func (s *ControlService) ContinueNode(actor, workflow, node string) error {
+	return s.do(workflow, "continuing node of", func(w *Workflow) error {
+		return w.Controller(actor).ContinueNode(node)
+	})
+}
+
+// SetNodeBitRate changes the bit rate of a node of a workflow, e.g. an encoder
+func (s *ControlService) SetNodeBitRate(actor, workflow, node string, bitRate int) error {
+	return s.do(workflow, "setting bit rate of node of", func(w *Workflow) error {
+		return w.Controller(actor).SetBitRate(node, bitRate)
+	})
+}
+
+// SwitchNodeSource switches the source of a node of a workflow to one of its parents, e.g. to switch between inputs
+func (s *ControlService) SwitchNodeSource(actor, workflow, node, source string) error {
+	return s.do(workflow, "switching source of node of", func(w *Workflow) error {
+		return w.Controller(actor).Switch(node, source)
+	})
+}
+
+func (s *ControlService) do(name, verb string, fn func(w *Workflow) error) error {
+	// Get workflow
+	w, err := s.Workflow(name)
+	if err != nil {
+		return fmt.Errorf("astiencoder: %s workflow %s failed: %w", verb, name, ErrWorkflowNotFound)
+	}
+
+	// Do
+	return fn(w)
+}
+
+// Subscribe returns a channel receiving the events of the workflows matching the options until the context is done,
+// in which case the channel is closed
+// Events are dropped while the channel's buffer is full so that slow subscribers don't slow down the workflows
+func (s *ControlService) Subscribe(ctx context.Context, o ControlSubscriptionOptions) <-chan ControlEvent {
+	// Default options
+	if o.BufferSize <= 0 {
+		o.BufferSize = controlSubscriptionBufferSizeDefault
+	}
+
+	// Create subscription
+	cs := &controlSubscription{
+		c: make(chan ControlEvent, o.BufferSize),
+		o: o,
+	}
+	if len(o.Names) > 0 {
+		cs.names = make(map[string]bool)
+		for _, n := range o.Names {
+			cs.names[n] = true
+		}
+	}
+
+	// Store subscription
+	s.ms.Lock()
+	s.ss[cs] = true
+	s.ms.Unlock()
+
+	// Remove subscription once the context is done
+	go func() {
+		<-ctx.Done()
+		s.ms.Lock()
+		delete(s.ss, cs)
+		close(cs.c)
+		s.ms.Unlock()
+	}()
+	return cs.c
+}
+
+func (s *ControlService) dispatch(workflow string, e Event) {
+	// Lock
+	s.ms.Lock()
+	defer s.ms.Unlock()
+
+	// No subscriptions
+	if len(s.ss) == 0 {
+		return
+	}
+
+	// Create control event
+	ce := ControlEvent{
+		Name:     e.Name,
+		Payload:  serverEventPayload(e),
+		Workflow: workflow,
+	}
+	if n, ok := e.Target.(Node); ok {
+		ce.Node = n.Metadata().Name
+	}
+
+	// Loop through subscriptions
+	for cs := range s.ss {
+		// Subscription doesn't match
+		if (cs.o.Workflow != "" && cs.o.Workflow != workflow) || (cs.names != nil && !cs.names[e.Name]) {
+			continue
+		}
+
+		// Workflow doesn't belong to the subscription's namespace
+		sce := ce
+		var ok bool
+		if sce.Workflow, ok = relativeWorkflowName(cs.o.Namespace, workflow); !ok {
+			continue
+		}
+
+		// Send
+		select {
+		case cs.c <- sce:
+		default:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ControlClientOptions represents control client options
+type ControlClientOptions struct {
+	// Base URL the control service's handler is served under, e.g. http://encoder-1:4000/api
+	Addr string
+	// Default is http.DefaultClient
+	HTTPClient *http.Client
+	// If set, sent in the X-Astiencoder-Namespace header so that workflow names are relative to it
+	Namespace string
+	// Sent as a bearer token if set
+	Token string
+}
+
+// ControlClient represents a client of the REST API exposed by ControlService.Handler
+type ControlClient struct {
+	c *http.Client
+	o ControlClientOptions
+}
+
+// NewControlClient creates a new control client
+func NewControlClient(o ControlClientOptions) *ControlClient {
+	c := &ControlClient{
+		c: o.HTTPClient,
+		o: o,
+	}
+	if c.c == nil {
+		c.c = http.DefaultClient
+	}
+	return c
+}
+
+// ControlClientError represents an error returned by the control API. It wraps the control errors matching its code,
+// e.g. ErrWorkflowNotFound for the not_found code of a workflow route
+type ControlClientError struct {
+	ControlError
+	StatusCode int
+}
+
+// Error implements the error interface
+func (e *ControlClientError) Error() string {
+	return fmt.Sprintf("astiencoder: control api returned %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is allows using errors.Is with the control errors
+func (e *ControlClientError) Is(target error) bool {
+	switch e.Code {
+	case ControlErrorCodeAlreadyExists:
+		return target == ErrWorkflowAlreadyExists
+	case ControlErrorCodeAlreadyStarted:
+		return target == ErrWorkflowAlreadyStarted
+	case ControlErrorCodeForbidden:
+		return target == ErrForbidden
+	case ControlErrorCodeNotFound:
+		return target == ErrWorkflowNotFound || target == ErrNodeNotFound
+	case ControlErrorCodeQuotaExceeded:
+		return target == ErrQuotaExceeded
+	case ControlErrorCodeRateLimited:
+		return target == ErrRateLimited
+	case ControlErrorCodeShuttingDown:
+		return target == ErrShuttingDown
+	case ControlErrorCodeUnauthenticated:
+		return target == ErrUnauthenticated
+	}
+	return false
+}
+
+// CreateWorkflow creates a workflow and starts it if start is true
+func (c *ControlClient) CreateWorkflow(ctx context.Context, d WorkflowDefinition, values map[string]interface{}, start bool) error {
+	return c.do(ctx, http.MethodPost, "/workflows", ControlCreateWorkflowRequest{
+		Definition: d,
+		Start:      start,
+		Values:     values,
+	}, nil)
+}
+
+// ContinueWorkflow continues a paused workflow
+func (c *ControlClient) ContinueWorkflow(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/workflows/"+url.PathEscape(name)+"/continue", nil, nil)
+}
+
+// DeleteWorkflow deletes a workflow
+func (c *ControlClient) DeleteWorkflow(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/workflows/"+url.PathEscape(name), nil, nil)
+}
+
+// PauseWorkflow pauses a workflow
+func (c *ControlClient) PauseWorkflow(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/workflows/"+url.PathEscape(name)+"/pause", nil, nil)
+}
+
+// Reload reloads the workflows from the source of the control service
+func (c *ControlClient) Reload(ctx context.Context) (r ControlReloadResult, err error) {
+	err = c.do(ctx, http.MethodPost, "/reload", nil, &r)
+	return
+}
+
+// StartWorkflow starts a workflow
+func (c *ControlClient) StartWorkflow(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/workflows/"+url.PathEscape(name)+"/start", nil, nil)
+}
+
+// StopWorkflow stops a workflow
+func (c *ControlClient) StopWorkflow(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/workflows/"+url.PathEscape(name)+"/stop", nil, nil)
+}
+
+// Workflow returns a snapshot of a workflow
+func (c *ControlClient) Workflow(ctx context.Context, name string) (s ServerSnapshot, err error) {
+	err = c.do(ctx, http.MethodGet, "/workflows/"+url.PathEscape(name), nil, &s)
+	return
+}
+
+// Workflows lists the workflows
+func (c *ControlClient) Workflows(ctx context.Context) (ws []ControlWorkflow, err error) {
+	err = c.do(ctx, http.MethodGet, "/workflows", nil, &ws)
+	return
+}
+
+func (c *ControlClient) do(ctx context.Context, method, path string, reqPayload, respPayload interface{}) (err error) {
+	// Marshal request payload
+	var body io.Reader
+	if reqPayload != nil {
+		var b []byte
+		if b, err = json.Marshal(reqPayload); err != nil {
+			err = fmt.Errorf("astiencoder: marshaling request payload failed: %w", err)
+			return
+		}
+		body = bytes.NewReader(b)
+	}
+
+	// Create request
+	var req *http.Request
+	if req, err = http.NewRequest(method, strings.TrimRight(c.o.Addr, "/")+path, body); err != nil {
+		err = fmt.Errorf("astiencoder: creating request failed: %w", err)
+		return
+	}
+	req = req.WithContext(ctx)
+	if reqPayload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.o.Namespace != "" {
+		req.Header.Set(ControlNamespaceHeader, c.o.Namespace)
+	}
+	if c.o.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.o.Token)
+	}
+
+	// Send request
+	var resp *http.Response
+	if resp, err = c.c.Do(req); err != nil {
+		err = fmt.Errorf("astiencoder: sending %s request to %s failed: %w", method, req.URL, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Process error
+	if resp.StatusCode >= http.StatusBadRequest {
+		e := &ControlClientError{StatusCode: resp.StatusCode}
+		var r ControlErrorResponse
+		if errUnmarshal := json.NewDecoder(resp.Body).Decode(&r); errUnmarshal == nil {
+			e.ControlError = r.Error
+		}
+		err = e
+		return
+	}
+
+	// Unmarshal response payload
+	if respPayload != nil {
+		if err = json.NewDecoder(resp.Body).Decode(respPayload); err != nil {
+			err = fmt.Errorf("astiencoder: unmarshaling response payload failed: %w", err)
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,422 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ControlActorHeader is the header of the requests providing the actor of the control operations. It's ignored when
+// an authenticator is set, in which case the actor is the authenticated identity
+const ControlActorHeader = "X-Astiencoder-Actor"
+
+// Control error codes
+const (
+	ControlErrorCodeAlreadyExists     = "already_exists"
+	ControlErrorCodeAlreadyStarted    = "already_started"
+	ControlErrorCodeForbidden         = "forbidden"
+	ControlErrorCodeInternal          = "internal"
+	ControlErrorCodeInvalidDefinition = "invalid_definition"
+	ControlErrorCodeInvalidRequest    = "invalid_request"
+	ControlErrorCodeNotFound          = "not_found"
+	ControlErrorCodeQuotaExceeded     = "quota_exceeded"
+	ControlErrorCodeRateLimited       = "rate_limited"
+	ControlErrorCodeShuttingDown      = "shutting_down"
+	ControlErrorCodeUnauthenticated   = "unauthenticated"
+)
+
+// ControlCreateWorkflowRequest represents the body of a workflow creation request
+type ControlCreateWorkflowRequest struct {
+	Definition WorkflowDefinition `json:"definition"`
+	// If true, the workflow is started once created
+	Start  bool                   `json:"start,omitempty"`
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// ControlAddJobRequest represents the body of a job addition request
+type ControlAddJobRequest struct {
+	Definition WorkflowDefinition     `json:"definition"`
+	Priority   int                    `json:"priority"`
+	Values     map[string]interface{} `json:"values,omitempty"`
+}
+
+// ControlAddJobResponse represents the body of a job addition response
+type ControlAddJobResponse struct {
+	ID string `json:"id"`
+}
+
+// ControlSeekRequest represents the body of a seek request
+type ControlSeekRequest struct {
+	// If empty, every node that can seek is seeked
+	Node string `json:"node,omitempty"`
+	// In seconds
+	Position float64 `json:"position"`
+}
+
+// ControlErrorResponse represents the body of an error response
+type ControlErrorResponse struct {
+	Error ControlError `json:"error"`
+}
+
+// ControlError represents an error returned by the control API
+type ControlError struct {
+	Code string `json:"code"`
+	// Only set for invalid definitions
+	Details []ControlErrorDetail `json:"details,omitempty"`
+	Message string               `json:"message"`
+}
+
+// ControlErrorDetail represents an error located in a workflow definition
+type ControlErrorDetail struct {
+	Message    string `json:"message"`
+	Node       string `json:"node,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+func newControlError(err error) (status int, e ControlError) {
+	// Get code
+	e.Message = err.Error()
+	var es DefinitionErrors
+	switch {
+	case errors.Is(err, ErrJobNotFound), errors.Is(err, ErrNodeNotFound), errors.Is(err, ErrWorkflowNotFound):
+		status, e.Code = http.StatusNotFound, ControlErrorCodeNotFound
+	case errors.Is(err, ErrWorkflowAlreadyExists):
+		status, e.Code = http.StatusConflict, ControlErrorCodeAlreadyExists
+	case errors.Is(err, ErrWorkflowAlreadyStarted):
+		status, e.Code = http.StatusConflict, ControlErrorCodeAlreadyStarted
+	case errors.Is(err, ErrUnauthenticated):
+		status, e.Code = http.StatusUnauthorized, ControlErrorCodeUnauthenticated
+	case errors.Is(err, ErrForbidden):
+		status, e.Code = http.StatusForbidden, ControlErrorCodeForbidden
+	case errors.Is(err, ErrQuotaExceeded):
+		status, e.Code = http.StatusForbidden, ControlErrorCodeQuotaExceeded
+	case errors.Is(err, ErrRateLimited):
+		status, e.Code = http.StatusTooManyRequests, ControlErrorCodeRateLimited
+	case errors.Is(err, ErrShuttingDown):
+		status, e.Code = http.StatusServiceUnavailable, ControlErrorCodeShuttingDown
+	case errors.As(err, &es):
+		status, e.Code = http.StatusBadRequest, ControlErrorCodeInvalidDefinition
+		for _, de := range es {
+			e.Details = append(e.Details, ControlErrorDetail{
+				Message:    de.Err.Error(),
+				Node:       de.Node,
+				Path:       de.Path,
+				Suggestion: de.Suggestion,
+			})
+		}
+	default:
+		status, e.Code = http.StatusInternalServerError, ControlErrorCodeInternal
+	}
+	return
+}
+
+// Handler returns an http.Handler exposing the control service as a REST API:
+//   - GET /workflows lists the workflows (viewer)
+//   - POST /workflows creates a workflow from a ControlCreateWorkflowRequest (admin)
+//   - GET /workflows/<name> returns the snapshot of a workflow (viewer)
+//   - DELETE /workflows/<name> deletes a workflow (admin)
+//   - GET /workflows/<name>/stats returns the last stats of a workflow and of its nodes (viewer)
+//   - POST /workflows/<name>/<start|stop|pause|continue> controls a workflow (operator)
+//   - POST /workflows/<name>/seek seeks a workflow with a ControlSeekRequest (operator)
+//   - POST /workflows/<name>/nodes/<node>/reconfigure reconfigures a node with the changed options (operator)
+//   - POST /reload reloads the workflows from the source and returns a ControlReloadResult (admin, without namespace)
+//   - GET /jobs lists the jobs of the job queue as ServerJob (viewer, without namespace)
+//   - POST /jobs adds a job from a ControlAddJobRequest and returns a ControlAddJobResponse (admin, without namespace)
+//   - DELETE /jobs/<id> cancels a job (operator, without namespace)
+//   - GET /websocket opens a websocket streaming the events of the workflows and accepting ControlWebSocketCommand
+//     payloads, whose event names are the ControlWebSocketCommand* constants. Each command is answered with a
+//     ControlWebSocketEventNameResult message (viewer, operator for the commands controlling workflows)
+//   - GET / serves the web UI if the WebUI option is true
+//
+// If an authenticator is set, callers provide their token with BearerToken and must have the role written in
+// parentheses. Requests are scoped to the namespace of the identity or, if it has none, to the one provided in the
+// X-Astiencoder-Namespace header: workflow names are relative to it and other namespaces' workflows are invisible.
+// If a rate limit is set, callers exceeding it get a 429 with a Retry-After header. Errors are returned as a
+// ControlErrorResponse. Use http.StripPrefix to mount it under a prefix
+func (s *ControlService) Handler() http.Handler {
+	// Create router
+	r := httprouter.New()
+
+	// Add routes
+	r.Handler(http.MethodGet, "/workflows", s.authorize(RoleViewer, s.serveWorkflows()))
+	r.Handler(http.MethodPost, "/workflows", s.authorize(RoleAdmin, s.createWorkflow()))
+	r.Handler(http.MethodGet, "/workflows/:name", s.authorize(RoleViewer, s.serveWorkflow()))
+	r.Handler(http.MethodDelete, "/workflows/:name", s.authorize(RoleAdmin, s.controlWorkflow(s.DeleteWorkflow)))
+	r.Handler(http.MethodGet, "/workflows/:name/stats", s.authorize(RoleViewer, s.serveWorkflowStats()))
+	r.Handler(http.MethodPost, "/workflows/:name/continue", s.authorize(RoleOperator, s.controlWorkflow(s.ContinueWorkflow)))
+	r.Handler(http.MethodPost, "/workflows/:name/pause", s.authorize(RoleOperator, s.controlWorkflow(s.PauseWorkflow)))
+	r.Handler(http.MethodPost, "/workflows/:name/seek", s.authorize(RoleOperator, s.seekWorkflow()))
+	r.Handler(http.MethodPost, "/workflows/:name/start", s.authorize(RoleOperator, s.controlWorkflow(s.StartWorkflow)))
+	r.Handler(http.MethodPost, "/workflows/:name/stop", s.authorize(RoleOperator, s.controlWorkflow(s.StopWorkflow)))
+	r.Handler(http.MethodPost, "/workflows/:name/nodes/:node/reconfigure", s.authorize(RoleOperator, s.reconfigureNode()))
+	r.Handler(http.MethodPost, "/reload", s.authorize(RoleAdmin, s.reload()))
+	r.Handler(http.MethodGet, "/websocket", s.authorize(RoleViewer, s.serveWebSocket()))
+
+	// Add jobs routes
+	if s.o.JobQueue != nil {
+		r.Handler(http.MethodGet, "/jobs", s.authorize(RoleViewer, s.serveJobs()))
+		r.Handler(http.MethodPost, "/jobs", s.authorize(RoleAdmin, s.addJob()))
+		r.Handler(http.MethodDelete, "/jobs/:id", s.authorize(RoleOperator, s.cancelJob()))
+	}
+
+	// Add web UI route
+	// It doesn't need to be authorized since it only contains static content
+	if s.o.WebUI {
+		r.Handler(http.MethodGet, "/", s.serveWebUI())
+	}
+
+	// Log requests
+	if s.o.LogRequests {
+		return s.logRequests(r)
+	}
+	return r
+}
+
+func (s *ControlService) authorize(role Role, h http.Handler) http.Handler {
+	return s.limitRemoteAddr(authorizeHandler(s.o.Authenticator, role, s.scope(s.limitIdentity(h))))
+}
+
+func controlActor(r *http.Request) string {
+	i, _ := IdentityFromContext(r.Context())
+	return i.Name
+}
+
+func writeControlJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(v)
+}
+
+func writeControlError(rw http.ResponseWriter, err error) {
+	status, e := newControlError(err)
+	writeControlJSON(rw, status, ControlErrorResponse{Error: e})
+}
+
+func writeControlRequestError(rw http.ResponseWriter, err error) {
+	writeControlJSON(rw, http.StatusBadRequest, ControlErrorResponse{Error: ControlError{
+		Code:    ControlErrorCodeInvalidRequest,
+		Message: err.Error(),
+	}})
+}
+
+func (s *ControlService) serveWorkflows() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Loop through workflows
+		ns := controlNamespace(r)
+		ws := []ControlWorkflow{}
+		for _, w := range s.Workflows() {
+			var ok bool
+			if w.Name, ok = relativeWorkflowName(ns, w.Name); ok {
+				ws = append(ws, w)
+			}
+		}
+
+		// Write
+		writeControlJSON(rw, http.StatusOK, ws)
+	})
+}
+
+func (s *ControlService) createWorkflow() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var b ControlCreateWorkflowRequest
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Names are relative to the namespace
+		if strings.Contains(b.Definition.Name, "/") {
+			writeControlError(rw, DefinitionErrors{newDefinitionError("name", fmt.Errorf("name %s can't contain /", b.Definition.Name))})
+			return
+		}
+		name := b.Definition.Name
+		b.Definition.Name = controlWorkflowName(r, name)
+
+		// Create workflow
+		actor := controlActor(r)
+		w, err := s.CreateWorkflow(actor, b.Definition, b.Values)
+		if err != nil {
+			writeControlError(rw, err)
+			return
+		}
+
+		// Start workflow
+		if b.Start {
+			if err = s.StartWorkflow(actor, w.Name()); err != nil {
+				writeControlError(rw, err)
+				return
+			}
+		}
+
+		// Write
+		writeControlJSON(rw, http.StatusCreated, ControlWorkflow{
+			Name:   name,
+			Status: w.Status(),
+		})
+	})
+}
+
+func (s *ControlService) serveWorkflow() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get workflow
+		name := httprouter.ParamsFromContext(r.Context()).ByName("name")
+		w, err := s.Workflow(controlWorkflowName(r, name))
+		if err != nil {
+			writeControlError(rw, err)
+			return
+		}
+
+		// Write
+		ss := newServerSnapshot(w.Snapshot())
+		ss.Name = name
+		writeControlJSON(rw, http.StatusOK, ss)
+	})
+}
+
+func (s *ControlService) serveWorkflowStats() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get workflow
+		w, err := s.Workflow(controlWorkflowName(r, httprouter.ParamsFromContext(r.Context()).ByName("name")))
+		if err != nil {
+			writeControlError(rw, err)
+			return
+		}
+
+		// Write
+		writeControlJSON(rw, http.StatusOK, newServerAggregatedStats(Event{
+			Name:    EventNameWorkflowStatsAggregated,
+			Payload: w.aggregatedStats(),
+			Target:  w,
+		}))
+	})
+}
+
+func (s *ControlService) controlWorkflow(fn func(actor, name string) error) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := fn(controlActor(r), controlWorkflowName(r, httprouter.ParamsFromContext(r.Context()).ByName("name"))); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *ControlService) seekWorkflow() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var b ControlSeekRequest
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Seek
+		if err := s.SeekWorkflow(controlActor(r), controlWorkflowName(r, httprouter.ParamsFromContext(r.Context()).ByName("name")), b.Node, time.Duration(b.Position*float64(time.Second))); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *ControlService) reconfigureNode() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var b map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Reconfigure
+		ps := httprouter.ParamsFromContext(r.Context())
+		if err := s.ReconfigureNode(controlActor(r), controlWorkflowName(r, ps.ByName("name")), ps.ByName("node"), b); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *ControlService) reload() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Reloading touches the workflows of every namespace
+		if ns := controlNamespace(r); ns != "" {
+			writeControlError(rw, fmt.Errorf("astiencoder: namespace %q is not allowed to reload: %w", ns, ErrForbidden))
+			return
+		}
+
+		// Reload
+		res, err := s.Reload(controlActor(r))
+		if err != nil {
+			writeControlError(rw, err)
+			return
+		}
+
+		// Write
+		writeControlJSON(rw, http.StatusOK, res)
+	})
+}
+
+// unnamespaced makes sure the caller has no namespace since jobs don't belong to any
+func (s *ControlService) unnamespaced(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if ns := controlNamespace(r); ns != "" {
+			writeControlError(rw, fmt.Errorf("astiencoder: namespace %q is not allowed to access jobs: %w", ns, ErrForbidden))
+			return
+		}
+		h.ServeHTTP(rw, r)
+	})
+}
+
+func (s *ControlService) serveJobs() http.Handler {
+	return s.unnamespaced(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Loop through jobs
+		js := []ServerJob{}
+		for _, j := range s.o.JobQueue.Jobs() {
+			js = append(js, newServerJob(j))
+		}
+
+		// Write
+		writeControlJSON(rw, http.StatusOK, js)
+	}))
+}
+
+func (s *ControlService) addJob() http.Handler {
+	return s.unnamespaced(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Decode body
+		var b ControlAddJobRequest
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			writeControlRequestError(rw, err)
+			return
+		}
+
+		// Add job
+		id := s.o.JobQueue.Add(Job{
+			Definition: b.Definition,
+			Priority:   b.Priority,
+			Values:     b.Values,
+		})
+
+		// Write
+		writeControlJSON(rw, http.StatusCreated, ControlAddJobResponse{ID: id})
+	}))
+}
+
+func (s *ControlService) cancelJob() http.Handler {
+	return s.unnamespaced(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Cancel job
+		if err := s.o.JobQueue.Cancel(httprouter.ParamsFromContext(r.Context()).ByName("id")); err != nil {
+			writeControlError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+}
@@ -0,0 +1,362 @@
+package astiencoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControlServiceHandler(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return &mockedSeekerNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler())}, nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts}})
+	h := s.Handler()
+	do := func(method, path, body string) (*httptest.ResponseRecorder, ControlErrorResponse) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set(ControlActorHeader, "alice")
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		var e ControlErrorResponse
+		if rw.Code >= http.StatusBadRequest {
+			assert.NoError(t, json.NewDecoder(rw.Body).Decode(&e))
+		}
+		return rw, e
+	}
+
+	// Create
+	rw, _ := do(http.MethodPost, "/workflows", `{"definition":{"name":"w","nodes":[{"name":"n","type":"t"}],"version":1}}`)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "{\"name\":\"w\",\"status\":\"stopped\"}\n", rw.Body.String())
+	rw, e := do(http.MethodPost, "/workflows", `{"definition":{"name":"w","nodes":[{"name":"n","type":"t"}],"version":1}}`)
+	assert.Equal(t, http.StatusConflict, rw.Code)
+	assert.Equal(t, ControlErrorCodeAlreadyExists, e.Error.Code)
+	rw, e = do(http.MethodPost, "/workflows", `{"definition":{"name":"w2","nodes":[{"name":"n","type":"u"}],"version":1}}`)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, ControlError{
+		Code:    ControlErrorCodeInvalidDefinition,
+		Details: []ControlErrorDetail{{Message: "unknown node type u", Node: "n", Path: "nodes[0].type"}},
+		Message: "astiencoder: building workflow w2 failed: astiencoder: nodes[0].type: unknown node type u",
+	}, e.Error)
+	rw, e = do(http.MethodPost, "/workflows", `{`)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, ControlErrorCodeInvalidRequest, e.Error.Code)
+
+	// Read
+	rw, _ = do(http.MethodGet, "/workflows", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "[{\"name\":\"w\",\"status\":\"stopped\"}]\n", rw.Body.String())
+	rw, _ = do(http.MethodGet, "/workflows/w", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var ss ServerSnapshot
+	assert.NoError(t, json.NewDecoder(rw.Body).Decode(&ss))
+	assert.Equal(t, "w", ss.Name)
+	assert.Len(t, ss.Nodes, 1)
+	rw, _ = do(http.MethodGet, "/workflows/w/stats", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	rw, e = do(http.MethodGet, "/workflows/unknown", "")
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, ControlErrorCodeNotFound, e.Error.Code)
+
+	// Control
+	rw, _ = do(http.MethodPost, "/workflows/w/seek", `{"position":1.5}`)
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	w, err := s.Workflow("w")
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, w.indexedNodes()["n"].(*mockedSeekerNode).position)
+	rw, e = do(http.MethodPost, "/workflows/w/seek", `{"node":"unknown","position":1}`)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	assert.Equal(t, ControlErrorCodeNotFound, e.Error.Code)
+	rw, e = do(http.MethodPost, "/workflows/w/nodes/n/reconfigure", `{"bit_rate":1}`)
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, ControlError{Code: ControlErrorCodeInternal, Message: "astiencoder: node n can't be reconfigured"}, e.Error)
+	rw, _ = do(http.MethodPost, "/workflows/w/start", "")
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	rw, e = do(http.MethodPost, "/workflows/w/start", "")
+	assert.Equal(t, http.StatusConflict, rw.Code)
+	assert.Equal(t, ControlErrorCodeAlreadyStarted, e.Error.Code)
+	for _, a := range []string{"pause", "continue", "stop"} {
+		rw, _ = do(http.MethodPost, "/workflows/w/"+a, "")
+		assert.Equal(t, http.StatusNoContent, rw.Code, a)
+	}
+
+	// Delete
+	rw, _ = do(http.MethodDelete, "/workflows/w", "")
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	rw, _ = do(http.MethodDelete, "/workflows/w", "")
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestControlClient(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts}})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	c := NewControlClient(ControlClientOptions{Addr: srv.URL})
+	ctx := context.Background()
+
+	// Create
+	assert.NoError(t, c.CreateWorkflow(ctx, WorkflowDefinition{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: 1}, nil, false))
+
+	// Read
+	ws, err := c.Workflows(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []ControlWorkflow{{Name: "w", Status: StatusStopped}}, ws)
+	ss, err := c.Workflow(ctx, "w")
+	assert.NoError(t, err)
+	assert.Equal(t, "w", ss.Name)
+	assert.Len(t, ss.Nodes, 1)
+	_, err = c.Workflow(ctx, "unknown")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+
+	// Control
+	assert.NoError(t, c.StartWorkflow(ctx, "w"))
+	assert.True(t, errors.Is(c.StartWorkflow(ctx, "w"), ErrWorkflowAlreadyStarted))
+	assert.NoError(t, c.PauseWorkflow(ctx, "w"))
+	assert.NoError(t, c.ContinueWorkflow(ctx, "w"))
+	assert.NoError(t, c.StopWorkflow(ctx, "w"))
+	assert.True(t, errors.Is(c.StopWorkflow(ctx, "unknown"), ErrWorkflowNotFound))
+}
+
+func TestControlServiceWebUI(t *testing.T) {
+	// Web UI is disabled by default
+	rw := httptest.NewRecorder()
+	NewControlService(ControlServiceOptions{}).Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+
+	// Web UI is enabled
+	rw = httptest.NewRecorder()
+	NewControlService(ControlServiceOptions{WebUI: true}).Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.True(t, strings.Contains(rw.Body.String(), "<title>Astiencoder</title>"))
+	assert.False(t, strings.Contains(rw.Body.String(), "/* script */"))
+}
+
+func TestControlServiceHandlerAuth(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	eh := NewEventHandler()
+	var actors []string
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		actors = append(actors, e.Payload.(AuditEntry).Actor)
+		return false
+	})
+	s := NewControlService(ControlServiceOptions{
+		Authenticator: NewAPIKeyAuthenticator(map[string]Identity{
+			"admin":    {Name: "alice", Role: RoleAdmin},
+			"operator": {Name: "bob", Role: RoleOperator},
+			"viewer":   {Name: "carol", Role: RoleViewer},
+		}),
+		Build:        BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		EventHandler: eh,
+	})
+	h := s.Handler()
+	do := func(method, path, body, token string) (*httptest.ResponseRecorder, ControlErrorResponse) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set(ControlActorHeader, "mallory")
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		var e ControlErrorResponse
+		if rw.Code >= http.StatusBadRequest {
+			assert.NoError(t, json.NewDecoder(rw.Body).Decode(&e))
+		}
+		return rw, e
+	}
+
+	// Unauthenticated
+	rw, e := do(http.MethodGet, "/workflows", "", "")
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	assert.Equal(t, "Bearer", rw.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, ControlErrorCodeUnauthenticated, e.Error.Code)
+	rw, _ = do(http.MethodGet, "/workflows", "", "invalid")
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	// Roles
+	const d = `{"definition":{"name":"w","nodes":[{"name":"n","type":"t"}],"version":1}}`
+	rw, e = do(http.MethodPost, "/workflows", d, "operator")
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Equal(t, ControlErrorCodeForbidden, e.Error.Code)
+	rw, _ = do(http.MethodPost, "/workflows", d, "admin")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	rw, _ = do(http.MethodGet, "/workflows", "", "viewer")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	rw, _ = do(http.MethodPost, "/workflows/w/pause", "", "viewer")
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	rw, _ = do(http.MethodPost, "/workflows/w/pause", "", "operator")
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	rw, _ = do(http.MethodDelete, "/workflows/w", "", "operator")
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+
+	// Actor is the authenticated identity
+	assert.Equal(t, []string{"alice", "bob"}, actors)
+}
+
+func TestControlServiceHandlerNamespaces(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{
+		Authenticator: NewAPIKeyAuthenticator(map[string]Identity{
+			"a":    {Name: "alice", Namespace: "team-a", Role: RoleAdmin},
+			"b":    {Name: "bob", Namespace: "team-b", Role: RoleAdmin},
+			"root": {Name: "root", Role: RoleAdmin},
+		}),
+		Build:  BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		Quotas: map[string]ControlQuota{"team-a": {MaxWorkflows: 1}},
+	})
+	h := s.Handler()
+	do := func(method, path, body, token, namespace string) (*httptest.ResponseRecorder, ControlErrorResponse) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		if namespace != "" {
+			r.Header.Set(ControlNamespaceHeader, namespace)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		var e ControlErrorResponse
+		if rw.Code >= http.StatusBadRequest {
+			assert.NoError(t, json.NewDecoder(rw.Body).Decode(&e))
+		}
+		return rw, e
+	}
+
+	// Create
+	const d = `{"definition":{"name":"w","nodes":[{"name":"n","type":"t"}],"version":1}}`
+	rw, _ := do(http.MethodPost, "/workflows", d, "a", "")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "{\"name\":\"w\",\"status\":\"stopped\"}\n", rw.Body.String())
+	rw, e := do(http.MethodPost, "/workflows", `{"definition":{"name":"w2","nodes":[{"name":"n","type":"t"}],"version":1}}`, "a", "")
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Equal(t, ControlErrorCodeQuotaExceeded, e.Error.Code)
+	rw, _ = do(http.MethodPost, "/workflows", d, "b", "")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	rw, _ = do(http.MethodPost, "/workflows", d, "root", "")
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	rw, e = do(http.MethodPost, "/workflows", `{"definition":{"name":"team-b/w2","nodes":[{"name":"n","type":"t"}],"version":1}}`, "root", "")
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, ControlErrorCodeInvalidDefinition, e.Error.Code)
+
+	// List
+	rw, _ = do(http.MethodGet, "/workflows", "", "a", "")
+	assert.Equal(t, "[{\"name\":\"w\",\"status\":\"stopped\"}]\n", rw.Body.String())
+	rw, _ = do(http.MethodGet, "/workflows", "", "root", "team-b")
+	assert.Equal(t, "[{\"name\":\"w\",\"status\":\"stopped\"}]\n", rw.Body.String())
+	rw, _ = do(http.MethodGet, "/workflows", "", "root", "")
+	assert.Equal(t, "[{\"name\":\"team-a/w\",\"status\":\"stopped\"},{\"name\":\"team-b/w\",\"status\":\"stopped\"},{\"name\":\"w\",\"status\":\"stopped\"}]\n", rw.Body.String())
+	rw, e = do(http.MethodGet, "/workflows", "", "a", "team-b")
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Equal(t, ControlErrorCodeForbidden, e.Error.Code)
+
+	// Control
+	rw, _ = do(http.MethodGet, "/workflows/w", "", "b", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var ss ServerSnapshot
+	assert.NoError(t, json.NewDecoder(rw.Body).Decode(&ss))
+	assert.Equal(t, "w", ss.Name)
+	rw, _ = do(http.MethodDelete, "/workflows/w", "", "b", "")
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	rw, _ = do(http.MethodDelete, "/workflows/w", "", "b", "")
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	_, err := s.Workflow("team-a/w")
+	assert.NoError(t, err)
+	_, err = s.Workflow("w")
+	assert.NoError(t, err)
+}
+
+func TestControlServiceHandlerJobs(t *testing.T) {
+	// Create service
+	q := NewJobQueue(JobQueueOptions{}, NewEventHandler())
+	s := NewControlService(ControlServiceOptions{
+		Authenticator: NewAPIKeyAuthenticator(map[string]Identity{
+			"admin":  {Name: "alice", Role: RoleAdmin},
+			"team-a": {Name: "bob", Namespace: "a", Role: RoleAdmin},
+		}),
+		JobQueue: q,
+	})
+	h := s.Handler()
+	do := func(token, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		return rw
+	}
+
+	// Add
+	rw := do("admin", http.MethodPost, "/jobs", `{"definition":{"name":"j"},"priority":1}`)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	var r ControlAddJobResponse
+	assert.NoError(t, json.NewDecoder(rw.Body).Decode(&r))
+	assert.Equal(t, "1", r.ID)
+
+	// List
+	rw = do("admin", http.MethodGet, "/jobs", "")
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var js []ServerJob
+	assert.NoError(t, json.NewDecoder(rw.Body).Decode(&js))
+	if assert.Len(t, js, 1) {
+		assert.Equal(t, JobStatusPending, js[0].Status)
+	}
+
+	// Cancel
+	assert.Equal(t, http.StatusNoContent, do("admin", http.MethodDelete, "/jobs/1", "").Code)
+	assert.Equal(t, http.StatusNotFound, do("admin", http.MethodDelete, "/jobs/1", "").Code)
+
+	// Jobs don't belong to any namespace
+	assert.Equal(t, http.StatusForbidden, do("team-a", http.MethodGet, "/jobs", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do("", http.MethodGet, "/jobs", "").Code)
+
+	// The unauthenticated server only serves jobs
+	sh := NewServer(ServerOptions{JobQueue: q}).Handler()
+	for _, v := range []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: http.MethodGet, path: "/jobs", status: http.StatusOK},
+		{method: http.MethodPost, path: "/jobs", status: http.StatusMethodNotAllowed},
+		{method: http.MethodDelete, path: "/jobs/1", status: http.StatusNotFound},
+	} {
+		rw = httptest.NewRecorder()
+		sh.ServeHTTP(rw, httptest.NewRequest(v.method, v.path, strings.NewReader(`{"definition":{"name":"j"}}`)))
+		assert.Equal(t, v.status, rw.Code, v.method)
+	}
+}
@@ -0,0 +1,246 @@
+package astiencoder
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ControlRateLimit represents the rate limit of the clients of the control API
+// Each client has a bucket refilled with Rate requests per second and holding up to Burst requests. Requests are
+// limited by the IP of the client before it's authenticated, so that failed authentications are throttled as well, and,
+// when an authenticator is set, by its identity's name once it's authenticated
+type ControlRateLimit struct {
+	// Default is Rate rounded up
+	Burst int
+	// In requests per second
+	Rate float64
+}
+
+type controlRateLimiter struct {
+	bs       map[string]*controlRateLimiterBucket
+	m        *sync.Mutex
+	now      func() time.Time
+	o        ControlRateLimit
+	purgedAt time.Time
+}
+
+type controlRateLimiterBucket struct {
+	at     time.Time
+	tokens float64
+}
+
+func newControlRateLimiter(o ControlRateLimit) *controlRateLimiter {
+	// Default options
+	if o.Burst <= 0 {
+		o.Burst = int(math.Ceil(o.Rate))
+	}
+
+	// Create rate limiter
+	return &controlRateLimiter{
+		bs:  make(map[string]*controlRateLimiterBucket),
+		m:   &sync.Mutex{},
+		now: time.Now,
+		o:   o,
+	}
+}
+
+// allow returns whether the client can send a request, and if not, how long it has to wait
+func (l *controlRateLimiter) allow(client string) (ok bool, retryAfter time.Duration) {
+	// Lock
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	// Purge
+	now := l.now()
+	if now.Sub(l.purgedAt) >= time.Minute {
+		l.purge(now)
+	}
+
+	// Get bucket
+	b, exists := l.bs[client]
+	if !exists {
+		b = &controlRateLimiterBucket{tokens: float64(l.o.Burst)}
+		l.bs[client] = b
+	} else {
+		b.tokens = math.Min(float64(l.o.Burst), b.tokens+now.Sub(b.at).Seconds()*l.o.Rate)
+	}
+	b.at = now
+
+	// Bucket is empty
+	if b.tokens < 1 {
+		if l.o.Rate > 0 {
+			retryAfter = time.Duration((1 - b.tokens) / l.o.Rate * float64(time.Second))
+		}
+		return
+	}
+
+	// Consume
+	b.tokens--
+	ok = true
+	return
+}
+
+func (l *controlRateLimiter) purge(now time.Time) {
+	// Buckets that would be full again are the same as missing ones
+	l.purgedAt = now
+	for k, b := range l.bs {
+		if b.tokens+now.Sub(b.at).Seconds()*l.o.Rate >= float64(l.o.Burst) {
+			delete(l.bs, k)
+		}
+	}
+}
+
+// LimitRemoteAddr returns an error wrapping ErrRateLimited, and how long the client has to wait, if the client
+// identified by its remote address has exceeded the rate limit
+// It must be called before the client is authenticated so that failed authentications are throttled as well
+func (s *ControlService) LimitRemoteAddr(remoteAddr string) (retryAfter time.Duration, err error) {
+	if h, _, errSplit := net.SplitHostPort(remoteAddr); errSplit == nil {
+		remoteAddr = h
+	}
+	return s.allow("ip:" + remoteAddr)
+}
+
+// LimitIdentity returns an error wrapping ErrRateLimited, and how long the client has to wait, if the authenticated
+// client has exceeded the rate limit
+// Without authenticator, clients are only identified by their remote address and it does nothing
+func (s *ControlService) LimitIdentity(i Identity) (retryAfter time.Duration, err error) {
+	if s.o.Authenticator == nil {
+		return
+	}
+	return s.allow("identity:" + i.Name)
+}
+
+func (s *ControlService) allow(client string) (retryAfter time.Duration, err error) {
+	// No rate limit
+	if s.rl == nil {
+		return
+	}
+
+	// Rate limit
+	var ok bool
+	if ok, retryAfter = s.rl.allow(client); !ok {
+		err = fmt.Errorf("astiencoder: %s has exceeded the rate limit: %w", client, ErrRateLimited)
+	}
+	return
+}
+
+func (s *ControlService) limitRemoteAddr(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Rate limit
+		if retryAfter, err := s.LimitRemoteAddr(r.RemoteAddr); err != nil {
+			writeControlRateLimitError(rw, retryAfter, err)
+			return
+		}
+
+		// Serve
+		h.ServeHTTP(rw, r)
+	})
+}
+
+func (s *ControlService) limitIdentity(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Store actor for the request log
+		i, _ := IdentityFromContext(r.Context())
+		if cr, ok := r.Context().Value(controlRequestContextKey{}).(*controlRequest); ok {
+			cr.actor = i.Name
+		}
+
+		// Rate limit
+		if retryAfter, err := s.LimitIdentity(i); err != nil {
+			writeControlRateLimitError(rw, retryAfter, err)
+			return
+		}
+
+		// Serve
+		h.ServeHTTP(rw, r)
+	})
+}
+
+func writeControlRateLimitError(rw http.ResponseWriter, retryAfter time.Duration, err error) {
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	writeControlError(rw, err)
+}
+
+type controlRequestContextKey struct{}
+
+type controlRequest struct {
+	actor string
+}
+
+type controlResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements the http.ResponseWriter interface
+func (rw *controlResponseWriter) WriteHeader(status int) {
+	rw.status = status
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack implements the http.Hijacker interface, which websockets need
+func (rw *controlResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("astiencoder: response writer is not a hijacker")
+	}
+	rw.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+// ControlRequestLog represents a request served by one of the transports of the control service
+type ControlRequestLog struct {
+	// Empty if the request failed before its client was authenticated
+	Actor    string
+	Duration time.Duration
+	// HTTP method, "grpc" or "websocket"
+	Method string
+	// URL path, gRPC full method name or websocket command
+	Path       string
+	RemoteAddr string
+	// HTTP status code, gRPC code or websocket error code
+	Status interface{}
+}
+
+// LogRequest logs a request served by one of the transports of the control service if the LogRequests option is true
+func (s *ControlService) LogRequest(r ControlRequestLog) {
+	if !s.o.LogRequests {
+		return
+	}
+	s.l.Info("astiencoder: control request served",
+		LogField{Key: "method", Value: r.Method},
+		LogField{Key: "path", Value: r.Path},
+		LogField{Key: "status", Value: r.Status},
+		LogField{Key: "duration", Value: r.Duration},
+		LogField{Key: "actor", Value: r.Actor},
+		LogField{Key: "remote_addr", Value: r.RemoteAddr},
+	)
+}
+
+func (s *ControlService) logRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Serve
+		cr := &controlRequest{}
+		crw := &controlResponseWriter{ResponseWriter: rw, status: http.StatusOK}
+		start := time.Now()
+		h.ServeHTTP(crw, r.WithContext(context.WithValue(r.Context(), controlRequestContextKey{}, cr)))
+
+		// Log
+		s.LogRequest(ControlRequestLog{
+			Actor:      cr.actor,
+			Duration:   time.Since(start),
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			RemoteAddr: r.RemoteAddr,
+			Status:     crw.status,
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControlRateLimiter(t *testing.T) {
+	l := newControlRateLimiter(ControlRateLimit{Burst: 2, Rate: 4})
+	now := time.Unix(100, 0)
+	l.now = func() time.Time { return now }
+
+	// Burst
+	ok, _ := l.allow("a")
+	assert.True(t, ok)
+	ok, _ = l.allow("a")
+	assert.True(t, ok)
+	ok, retryAfter := l.allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+	ok, _ = l.allow("b")
+	assert.True(t, ok)
+
+	// Refill
+	now = now.Add(250 * time.Millisecond)
+	ok, _ = l.allow("a")
+	assert.True(t, ok)
+	ok, _ = l.allow("a")
+	assert.False(t, ok)
+
+	// Purge
+	now = now.Add(time.Minute)
+	ok, _ = l.allow("a")
+	assert.True(t, ok)
+	assert.Len(t, l.bs, 1)
+}
+
+func TestControlServiceHandlerRateLimit(t *testing.T) {
+	// Create service
+	ml := newMockedLogger()
+	s := NewControlService(ControlServiceOptions{
+		Authenticator: NewAPIKeyAuthenticator(map[string]Identity{
+			"k1": {Name: "alice", Role: RoleViewer},
+			"k2": {Name: "bob", Role: RoleViewer},
+		}),
+		Logger:      ml,
+		LogRequests: true,
+		RateLimit:   &ControlRateLimit{Rate: 1},
+	})
+	h := s.Handler()
+	do := func(token, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/workflows", nil)
+		r.RemoteAddr = remoteAddr
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, r)
+		return rw
+	}
+
+	// Rate limit by identity
+	assert.Equal(t, http.StatusOK, do("k1", "192.0.2.1:1234").Code)
+	rw := do("k1", "192.0.2.2:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "1", rw.Header().Get("Retry-After"))
+	var e ControlErrorResponse
+	assert.NoError(t, json.NewDecoder(rw.Body).Decode(&e))
+	assert.Equal(t, ControlErrorCodeRateLimited, e.Error.Code)
+	assert.Equal(t, http.StatusOK, do("k2", "192.0.2.3:1234").Code)
+
+	// Rate limit by IP, before authenticating
+	assert.Equal(t, http.StatusUnauthorized, do("", "192.0.2.4:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("invalid", "192.0.2.4:1234").Code)
+
+	// Request log
+	ml.m.Lock()
+	defer ml.m.Unlock()
+	assert.Len(t, ml.logs, 5)
+	for idx, v := range []struct {
+		actor  string
+		status int
+	}{
+		{actor: "alice", status: http.StatusOK},
+		{actor: "alice", status: http.StatusTooManyRequests},
+		{actor: "bob", status: http.StatusOK},
+		{status: http.StatusUnauthorized},
+		{status: http.StatusTooManyRequests},
+	} {
+		l := ml.logs[idx]
+		assert.Equal(t, "info", l.level)
+		assert.Equal(t, "astiencoder: control request served", l.msg)
+		fs := make(map[string]interface{})
+		for _, f := range l.fields {
+			fs[f.Key] = f.Value
+		}
+		assert.Equal(t, http.MethodGet, fs["method"])
+		assert.Equal(t, "/workflows", fs["path"])
+		assert.Equal(t, v.status, fs["status"])
+		assert.Equal(t, v.actor, fs["actor"])
+	}
+}
@@ -0,0 +1,120 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ControlNamespaceHeader is the header of the requests providing the namespace of the workflows they're about. It's
+// ignored when the identity has a namespace, unless it's a different one in which case the request is forbidden
+const ControlNamespaceHeader = "X-Astiencoder-Namespace"
+
+// ControlQuota represents the resources a namespace can use. 0 means unlimited
+type ControlQuota struct {
+	// Max number of workflows whose status is running or paused
+	MaxStartedWorkflows int
+	MaxWorkflows        int
+}
+
+// NamespacedWorkflowName returns the name of a workflow of a namespace as known by the control service, e.g.
+// "team-a/channel-1". Workflows of the empty namespace keep their name
+func NamespacedWorkflowName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// SplitNamespacedWorkflowName returns the namespace and the name, relative to it, of a workflow of the control service
+func SplitNamespacedWorkflowName(n string) (namespace, name string) {
+	if i := strings.Index(n, "/"); i >= 0 {
+		return n[:i], n[i+1:]
+	}
+	return "", n
+}
+
+func validateControlWorkflowName(name string) error {
+	if name == "" {
+		return errors.New("missing name")
+	}
+	if ps := strings.Split(name, "/"); len(ps) > 2 || (len(ps) == 2 && (ps[0] == "" || ps[1] == "")) {
+		return fmt.Errorf("name %s must be either <name> or <namespace>/<name>", name)
+	}
+	return nil
+}
+
+// checkQuota returns an error wrapping ErrQuotaExceeded if adding a workflow matching the filter to the namespace
+// would exceed max. It must be called while holding the service's lock
+func (s *ControlService) checkQuota(namespace string, max int, filter func(cw *controlWorkflow) bool) error {
+	// No quota
+	if max <= 0 {
+		return nil
+	}
+
+	// Count
+	var count int
+	for name, cw := range s.ws {
+		if ns, _ := SplitNamespacedWorkflowName(name); ns == namespace && filter(cw) {
+			count++
+		}
+	}
+
+	// Quota exceeded
+	if count >= max {
+		return fmt.Errorf("astiencoder: namespace %q has reached its max of %d: %w", namespace, max, ErrQuotaExceeded)
+	}
+	return nil
+}
+
+type controlNamespaceContextKey struct{}
+
+// scope resolves the namespace of the request and stores it in the request's context
+func (s *ControlService) scope(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get namespace
+		i, _ := IdentityFromContext(r.Context())
+		ns := r.Header.Get(ControlNamespaceHeader)
+		if i.Namespace != "" {
+			// Namespace is different
+			if ns != "" && ns != i.Namespace {
+				writeControlError(rw, fmt.Errorf("astiencoder: %s is not allowed to access namespace %q: %w", i.Name, ns, ErrForbidden))
+				return
+			}
+			ns = i.Namespace
+		}
+
+		// Invalid namespace
+		if strings.Contains(ns, "/") {
+			writeControlRequestError(rw, fmt.Errorf("astiencoder: namespace %q can't contain /", ns))
+			return
+		}
+
+		// Serve
+		h.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), controlNamespaceContextKey{}, ns)))
+	})
+}
+
+func controlNamespace(r *http.Request) string {
+	ns, _ := r.Context().Value(controlNamespaceContextKey{}).(string)
+	return ns
+}
+
+// controlWorkflowName returns the name, as known by the control service, of a workflow of the request's namespace
+func controlWorkflowName(r *http.Request, name string) string {
+	return NamespacedWorkflowName(controlNamespace(r), name)
+}
+
+// relativeWorkflowName returns the name of a workflow relative to a namespace and whether it belongs to it
+// Every workflow belongs to the empty namespace
+func relativeWorkflowName(namespace, name string) (string, bool) {
+	if namespace == "" {
+		return name, true
+	}
+	if !strings.HasPrefix(name, namespace+"/") {
+		return "", false
+	}
+	return name[len(namespace)+1:], true
+}
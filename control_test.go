@@ -0,0 +1,167 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControlService(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return &mockedPatchNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler())}, nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	eh := NewEventHandler()
+	var forwarded int
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		forwarded++
+		return false
+	})
+	s := NewControlService(ControlServiceOptions{
+		Build:        BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		EventHandler: eh,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := s.Subscribe(ctx, ControlSubscriptionOptions{Names: []string{EventNameAudit}, Workflow: "w1"})
+
+	// Create workflows
+	d := func(name string) WorkflowDefinition {
+		return WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion}
+	}
+	_, err := s.CreateWorkflow("alice", d("w1"), nil)
+	assert.NoError(t, err)
+	_, err = s.CreateWorkflow("alice", d("w2"), nil)
+	assert.NoError(t, err)
+	_, err = s.CreateWorkflow("alice", d("w1"), nil)
+	assert.True(t, errors.Is(err, ErrWorkflowAlreadyExists))
+	_, err = s.CreateWorkflow("alice", d(""), nil)
+	assert.EqualError(t, err, "astiencoder: creating workflow failed: astiencoder: name: missing name")
+	_, err = s.CreateWorkflow("alice", WorkflowDefinition{Name: "w3", Nodes: []NodeDefinition{{Name: "n", Type: "unknown"}}, Version: DefinitionVersion}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, []ControlWorkflow{{Name: "w1", Status: StatusStopped}, {Name: "w2", Status: StatusStopped}}, s.Workflows())
+
+	// Control workflows
+	assert.NoError(t, s.ReconfigureNode("bob", "w1", "n", map[string]interface{}{"bit_rate": 2}))
+	w, err := s.Workflow("w1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, w.indexedNodes()["n"].(*mockedPatchNode).o.BitRate)
+	assert.Error(t, s.ReconfigureNode("bob", "w1", "unknown", nil))
+	assert.NoError(t, s.StartWorkflow("bob", "w1"))
+	assert.True(t, errors.Is(s.StartWorkflow("bob", "w1"), ErrWorkflowAlreadyStarted))
+	for _, fn := range []func(actor, name string) error{s.ContinueWorkflow, s.DeleteWorkflow, s.PauseWorkflow, s.StartWorkflow, s.StopWorkflow} {
+		assert.True(t, errors.Is(fn("bob", "unknown"), ErrWorkflowNotFound))
+	}
+	_, err = s.Workflow("unknown")
+	assert.True(t, errors.Is(err, ErrWorkflowNotFound))
+
+	// Delete workflows
+	assert.NoError(t, s.DeleteWorkflow("carol", "w1"))
+	assert.NoError(t, s.DeleteWorkflow("carol", "w2"))
+	assert.Equal(t, []ControlWorkflow{}, s.Workflows())
+
+	// Events have been received
+	var as []string
+	for len(as) < 6 {
+		select {
+		case e := <-ch:
+			assert.Equal(t, "w1", e.Workflow)
+			a := e.Payload.(ServerAuditEntry)
+			as = append(as, a.Actor+" "+a.Action)
+		case <-time.After(time.Second):
+			t.Fatalf("only %d events have been received", len(as))
+		}
+	}
+	assert.Equal(t, []string{"alice create", "bob reconfigure", "bob reconfigure", "bob start", "carol stop", "carol delete"}, as)
+	assert.Equal(t, 8, forwarded)
+
+	// Channel is closed once the context is done
+	cancel()
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Error("channel is not closed")
+	}
+}
+
+func TestControlServiceNamespaces(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{
+		Build:  BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		Quotas: map[string]ControlQuota{"a": {MaxStartedWorkflows: 1, MaxWorkflows: 2}},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := s.Subscribe(ctx, ControlSubscriptionOptions{Names: []string{EventNameAudit}, Namespace: "a"})
+
+	// Names
+	assert.Equal(t, "a/w", NamespacedWorkflowName("a", "w"))
+	assert.Equal(t, "w", NamespacedWorkflowName("", "w"))
+	ns, name := SplitNamespacedWorkflowName("a/w")
+	assert.Equal(t, "a", ns)
+	assert.Equal(t, "w", name)
+	ns, name = SplitNamespacedWorkflowName("w")
+	assert.Equal(t, "", ns)
+	assert.Equal(t, "w", name)
+
+	// Create workflows
+	d := func(name string) WorkflowDefinition {
+		return WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion}
+	}
+	for _, name := range []string{"a/w1", "a/w2", "b/w1", "b/w2", "b/w3"} {
+		_, err := s.CreateWorkflow("alice", d(name), nil)
+		assert.NoError(t, err, name)
+	}
+	_, err := s.CreateWorkflow("alice", d("a/w3"), nil)
+	assert.True(t, errors.Is(err, ErrQuotaExceeded))
+	for _, name := range []string{"/w", "a/", "a/b/c"} {
+		_, err = s.CreateWorkflow("alice", d(name), nil)
+		assert.Error(t, err, name)
+	}
+
+	// Start workflows
+	assert.NoError(t, s.StartWorkflow("alice", "a/w1"))
+	assert.True(t, errors.Is(s.StartWorkflow("alice", "a/w2"), ErrQuotaExceeded))
+	assert.NoError(t, s.StartWorkflow("alice", "b/w1"))
+	assert.NoError(t, s.StartWorkflow("alice", "b/w2"))
+
+	// Only events of the namespace have been received
+	select {
+	case e := <-ch:
+		assert.Equal(t, "w1", e.Workflow)
+		assert.Equal(t, "create", e.Payload.(ServerAuditEntry).Action)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	select {
+	case e := <-ch:
+		assert.Equal(t, "w2", e.Workflow)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	select {
+	case e := <-ch:
+		assert.Equal(t, "w1", e.Workflow)
+		assert.Equal(t, "start", e.Payload.(ServerAuditEntry).Action)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
@@ -0,0 +1,301 @@
+package astiencoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiws"
+	"github.com/gorilla/websocket"
+)
+
+// Control websocket commands, used as the event names of the messages sent by the clients
+// Unless stated otherwise, commands apply to the node if one is provided, or to the workflow otherwise
+const (
+	ControlWebSocketCommandContinue    = "continue"
+	ControlWebSocketCommandPause       = "pause"
+	ControlWebSocketCommandReconfigure = "reconfigure" // Node only
+	ControlWebSocketCommandSeek        = "seek"
+	ControlWebSocketCommandSetBitRate  = "bit_rate.set" // Node only
+	ControlWebSocketCommandStart       = "start"        // Workflow only
+	ControlWebSocketCommandStop        = "stop"         // Workflow only
+	ControlWebSocketCommandSubscribe   = "subscribe"
+	ControlWebSocketCommandSwitch      = "switch" // Node only
+)
+
+// ControlWebSocketEventNameResult is the event name of the messages answering the clients' commands
+const ControlWebSocketEventNameResult = "command.result"
+
+// ControlWebSocketCommand represents the payload of a command sent by a websocket client
+type ControlWebSocketCommand struct {
+	// Only used by the bit_rate.set command
+	BitRate int `json:"bit_rate,omitempty"`
+	// Returned as is in the result so that clients can match results with their commands
+	ID   string `json:"id,omitempty"`
+	Node string `json:"node,omitempty"`
+	// Only used by the reconfigure command
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Only used by the seek command, in seconds
+	Position float64 `json:"position,omitempty"`
+	// Only used by the switch command, name of the parent node to switch to
+	Source   string `json:"source,omitempty"`
+	Workflow string `json:"workflow"`
+}
+
+// ControlWebSocketSubscription represents the payload of a subscribe command. It replaces the current subscription
+type ControlWebSocketSubscription struct {
+	// Returned as is in the result
+	ID string `json:"id,omitempty"`
+	// If set, only events with those names are sent
+	Names []string `json:"names,omitempty"`
+	// If set, only events of this workflow are sent
+	Workflow string `json:"workflow,omitempty"`
+}
+
+// ControlWebSocketResult represents the payload of a command result
+type ControlWebSocketResult struct {
+	Command string `json:"command"`
+	// Only set if the command has failed
+	Error *ControlError `json:"error,omitempty"`
+	ID    string        `json:"id,omitempty"`
+}
+
+type controlWebSocketClient struct {
+	c          *astiws.Client
+	cancel     context.CancelFunc
+	i          Identity
+	m          *sync.Mutex // Locks cancel
+	ns         string
+	remoteAddr string
+	s          *ControlService
+}
+
+func (s *ControlService) serveWebSocket() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		i, _ := IdentityFromContext(r.Context())
+		ns := controlNamespace(r)
+		if err := s.wm.ServeHTTP(rw, r, func(c *astiws.Client) error {
+			return s.adaptWebSocketClient(c, i, ns, r.RemoteAddr)
+		}); err != nil {
+			var e *websocket.CloseError
+			if ok := errors.As(err, &e); !ok ||
+				(e.Code != websocket.CloseNoStatusReceived && e.Code != websocket.CloseNormalClosure) {
+				s.l.Error("astiencoder: handling control websocket failed", LogField{Key: LogFieldError, Value: err})
+			}
+			return
+		}
+	})
+}
+
+func (s *ControlService) adaptWebSocketClient(c *astiws.Client, i Identity, ns, remoteAddr string) (err error) {
+	// Create client
+	actor := i.Name
+	wc := &controlWebSocketClient{
+		c:          c,
+		i:          i,
+		m:          &sync.Mutex{},
+		ns:         ns,
+		remoteAddr: remoteAddr,
+		s:          s,
+	}
+
+	// Add listeners
+	c.AddListener(astiws.EventNameDisconnect, wc.disconnected)
+	c.AddListener("ping", wc.ping)
+	c.AddListener(ControlWebSocketCommandContinue, wc.command(func(cmd ControlWebSocketCommand) error {
+		if cmd.Node != "" {
+			return s.ContinueNode(actor, cmd.Workflow, cmd.Node)
+		}
+		return s.ContinueWorkflow(actor, cmd.Workflow)
+	}))
+	c.AddListener(ControlWebSocketCommandPause, wc.command(func(cmd ControlWebSocketCommand) error {
+		if cmd.Node != "" {
+			return s.PauseNode(actor, cmd.Workflow, cmd.Node)
+		}
+		return s.PauseWorkflow(actor, cmd.Workflow)
+	}))
+	c.AddListener(ControlWebSocketCommandReconfigure, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.ReconfigureNode(actor, cmd.Workflow, cmd.Node, cmd.Options)
+	}))
+	c.AddListener(ControlWebSocketCommandSeek, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.SeekWorkflow(actor, cmd.Workflow, cmd.Node, time.Duration(cmd.Position*float64(time.Second)))
+	}))
+	c.AddListener(ControlWebSocketCommandSetBitRate, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.SetNodeBitRate(actor, cmd.Workflow, cmd.Node, cmd.BitRate)
+	}))
+	c.AddListener(ControlWebSocketCommandStart, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.StartWorkflow(actor, cmd.Workflow)
+	}))
+	c.AddListener(ControlWebSocketCommandStop, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.StopWorkflow(actor, cmd.Workflow)
+	}))
+	c.AddListener(ControlWebSocketCommandSubscribe, wc.subscribe)
+	c.AddListener(ControlWebSocketCommandSwitch, wc.command(func(cmd ControlWebSocketCommand) error {
+		return s.SwitchNodeSource(actor, cmd.Workflow, cmd.Node, cmd.Source)
+	}))
+
+	// Subscribe to every event of the namespace until the client subscribes itself
+	wc.resubscribe(ControlSubscriptionOptions{Namespace: ns})
+	return
+}
+
+func (wc *controlWebSocketClient) disconnected(c *astiws.Client, eventName string, payload json.RawMessage) error {
+	wc.m.Lock()
+	defer wc.m.Unlock()
+	if wc.cancel != nil {
+		wc.cancel()
+		wc.cancel = nil
+	}
+	return nil
+}
+
+func (wc *controlWebSocketClient) ping(c *astiws.Client, eventName string, payload json.RawMessage) error {
+	if err := c.ExtendConnection(); err != nil {
+		wc.s.l.Error("astiencoder: extending control ws connection failed", LogField{Key: LogFieldError, Value: err})
+	}
+	return nil
+}
+
+func (wc *controlWebSocketClient) command(fn func(cmd ControlWebSocketCommand) error) astiws.ListenerFunc {
+	return func(c *astiws.Client, eventName string, payload json.RawMessage) error {
+		// Unmarshal
+		start := time.Now()
+		var cmd ControlWebSocketCommand
+		if err := json.Unmarshal(payload, &cmd); err != nil {
+			wc.writeResult(start, ControlWebSocketResult{
+				Command: eventName,
+				Error: &ControlError{
+					Code:    ControlErrorCodeInvalidRequest,
+					Message: fmt.Sprintf("astiencoder: unmarshaling payload failed: %s", err),
+				},
+			})
+			return nil
+		}
+
+		// Execute
+		// Commands are rate limited and control workflows, therefore they need the operator role
+		r := ControlWebSocketResult{
+			Command: eventName,
+			ID:      cmd.ID,
+		}
+		err := wc.limit()
+		if err == nil {
+			err = wc.i.Authorize(RoleOperator)
+		}
+		// Workflow names are relative to the client's namespace
+		if err == nil {
+			cmd.Workflow = NamespacedWorkflowName(wc.ns, cmd.Workflow)
+			err = fn(cmd)
+		}
+		if err != nil {
+			_, e := newControlError(err)
+			r.Error = &e
+		}
+
+		// Write
+		wc.writeResult(start, r)
+		return nil
+	}
+}
+
+func (wc *controlWebSocketClient) subscribe(c *astiws.Client, eventName string, payload json.RawMessage) error {
+	// Unmarshal
+	start := time.Now()
+	var s ControlWebSocketSubscription
+	if err := json.Unmarshal(payload, &s); err != nil {
+		wc.writeResult(start, ControlWebSocketResult{
+			Command: eventName,
+			Error: &ControlError{
+				Code:    ControlErrorCodeInvalidRequest,
+				Message: fmt.Sprintf("astiencoder: unmarshaling payload failed: %s", err),
+			},
+		})
+		return nil
+	}
+
+	// Rate limit
+	r := ControlWebSocketResult{
+		Command: eventName,
+		ID:      s.ID,
+	}
+	if err := wc.limit(); err != nil {
+		_, e := newControlError(err)
+		r.Error = &e
+		wc.writeResult(start, r)
+		return nil
+	}
+
+	// Subscribe
+	o := ControlSubscriptionOptions{
+		Names:     s.Names,
+		Namespace: wc.ns,
+	}
+	if s.Workflow != "" {
+		o.Workflow = NamespacedWorkflowName(wc.ns, s.Workflow)
+	}
+	wc.resubscribe(o)
+
+	// Write
+	wc.writeResult(start, r)
+	return nil
+}
+
+// limit applies the rate limit to commands the same way it's applied to HTTP requests
+func (wc *controlWebSocketClient) limit() (err error) {
+	if _, err = wc.s.LimitRemoteAddr(wc.remoteAddr); err != nil {
+		return
+	}
+	_, err = wc.s.LimitIdentity(wc.i)
+	return
+}
+
+func (wc *controlWebSocketClient) resubscribe(o ControlSubscriptionOptions) {
+	// Lock
+	wc.m.Lock()
+	defer wc.m.Unlock()
+
+	// Cancel previous subscription
+	if wc.cancel != nil {
+		wc.cancel()
+	}
+
+	// Subscribe
+	var ctx context.Context
+	ctx, wc.cancel = context.WithCancel(context.Background())
+	ch := wc.s.Subscribe(ctx, o)
+
+	// Forward events
+	go func() {
+		for e := range ch {
+			if err := wc.c.Write(e.Name, e); err != nil {
+				wc.s.l.Error(fmt.Sprintf("astiencoder: writing event %s to control websocket client %p failed", e.Name, wc.c), LogField{Key: LogFieldError, Value: err})
+			}
+		}
+	}()
+}
+
+// writeResult answers a command and logs it as a request
+func (wc *controlWebSocketClient) writeResult(start time.Time, r ControlWebSocketResult) {
+	// Write
+	if err := wc.c.Write(ControlWebSocketEventNameResult, r); err != nil {
+		wc.s.l.Error(fmt.Sprintf("astiencoder: writing result of command %s to control websocket client %p failed", r.Command, wc.c), LogField{Key: LogFieldError, Value: err})
+	}
+
+	// Log
+	status := "ok"
+	if r.Error != nil {
+		status = r.Error.Code
+	}
+	wc.s.LogRequest(ControlRequestLog{
+		Actor:      wc.i.Name,
+		Duration:   time.Since(start),
+		Method:     "websocket",
+		Path:       r.Command,
+		RemoteAddr: wc.remoteAddr,
+		Status:     status,
+	})
+}
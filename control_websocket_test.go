@@ -0,0 +1,198 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControlServiceWebSocket(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return &mockedBitRateNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler())}, nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts}})
+	_, err := s.CreateWorkflow("alice", WorkflowDefinition{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion}, nil)
+	assert.NoError(t, err)
+	w, err := s.Workflow("w")
+	assert.NoError(t, err)
+
+	// Connect
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/websocket", http.Header{ControlActorHeader: []string{"bob"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	// Create helpers
+	type message struct {
+		EventName string          `json:"event_name"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+	write := func(eventName string, payload interface{}) {
+		assert.NoError(t, c.WriteJSON(map[string]interface{}{"event_name": eventName, "payload": payload}))
+	}
+	read := func(eventName string, v interface{}) {
+		assert.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+		for {
+			var m message
+			if err := c.ReadJSON(&m); err != nil {
+				t.Fatalf("reading %s message failed: %s", eventName, err)
+			}
+			if m.EventName == eventName {
+				assert.NoError(t, json.Unmarshal(m.Payload, v))
+				return
+			}
+		}
+	}
+
+	// Subscribe
+	write(ControlWebSocketCommandSubscribe, ControlWebSocketSubscription{ID: "1", Names: []string{EventNameAudit}, Workflow: "w"})
+	var r ControlWebSocketResult
+	read(ControlWebSocketEventNameResult, &r)
+	assert.Equal(t, ControlWebSocketResult{Command: ControlWebSocketCommandSubscribe, ID: "1"}, r)
+
+	// Set bit rate
+	write(ControlWebSocketCommandSetBitRate, ControlWebSocketCommand{BitRate: 2, ID: "2", Node: "n", Workflow: "w"})
+	r = ControlWebSocketResult{}
+	read(ControlWebSocketEventNameResult, &r)
+	assert.Equal(t, ControlWebSocketResult{Command: ControlWebSocketCommandSetBitRate, ID: "2"}, r)
+	assert.Equal(t, 2, w.indexedNodes()["n"].(*mockedBitRateNode).bitRate)
+	var e struct {
+		Node     string           `json:"node"`
+		Payload  ServerAuditEntry `json:"payload"`
+		Workflow string           `json:"workflow"`
+	}
+	read(EventNameAudit, &e)
+	assert.Equal(t, "n", e.Node)
+	assert.Equal(t, "w", e.Workflow)
+	assert.Equal(t, "bob", e.Payload.Actor)
+	assert.Equal(t, AuditActionSetBitRate, e.Payload.Action)
+
+	// Errors
+	write(ControlWebSocketCommandPause, ControlWebSocketCommand{ID: "3", Workflow: "unknown"})
+	r = ControlWebSocketResult{}
+	read(ControlWebSocketEventNameResult, &r)
+	assert.Equal(t, "3", r.ID)
+	if assert.NotNil(t, r.Error) {
+		assert.Equal(t, ControlErrorCodeNotFound, r.Error.Code)
+	}
+	write(ControlWebSocketCommandSwitch, ControlWebSocketCommand{ID: "4", Node: "n", Source: "s", Workflow: "w"})
+	r = ControlWebSocketResult{}
+	read(ControlWebSocketEventNameResult, &r)
+	assert.Equal(t, "4", r.ID)
+	if assert.NotNil(t, r.Error) {
+		assert.Equal(t, ControlError{Code: ControlErrorCodeInternal, Message: "astiencoder: node n can't switch its source"}, *r.Error)
+	}
+	write(ControlWebSocketCommandStart, "invalid")
+	r = ControlWebSocketResult{}
+	read(ControlWebSocketEventNameResult, &r)
+	if assert.NotNil(t, r.Error) {
+		assert.Equal(t, ControlErrorCodeInvalidRequest, r.Error.Code)
+	}
+}
+
+func TestControlServiceWebSocketAuth(t *testing.T) {
+	// Create service
+	s := NewControlService(ControlServiceOptions{Authenticator: NewAPIKeyAuthenticator(map[string]Identity{
+		"viewer": {Name: "carol", Role: RoleViewer},
+	})})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	u := "ws" + strings.TrimPrefix(srv.URL, "http") + "/websocket"
+
+	// Unauthenticated
+	_, resp, err := websocket.DefaultDialer.Dial(u, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// Viewers can't send commands
+	c, _, err := websocket.DefaultDialer.Dial(u+"?access_token=viewer", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+	assert.NoError(t, c.WriteJSON(map[string]interface{}{"event_name": ControlWebSocketCommandStop, "payload": ControlWebSocketCommand{ID: "1", Workflow: "w"}}))
+	assert.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+	var m struct {
+		EventName string                 `json:"event_name"`
+		Payload   ControlWebSocketResult `json:"payload"`
+	}
+	assert.NoError(t, c.ReadJSON(&m))
+	assert.Equal(t, ControlWebSocketEventNameResult, m.EventName)
+	if assert.NotNil(t, m.Payload.Error) {
+		assert.Equal(t, ControlErrorCodeForbidden, m.Payload.Error.Code)
+	}
+}
+
+func TestControlServiceWebSocketRateLimit(t *testing.T) {
+	// Create service
+	// Opening the websocket uses the first request of the burst
+	ml := newMockedLogger()
+	s := NewControlService(ControlServiceOptions{
+		Logger:      ml,
+		LogRequests: true,
+		RateLimit:   &ControlRateLimit{Burst: 2, Rate: 0.01},
+	})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/websocket", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	// Commands are rate limited
+	for _, v := range []struct {
+		code    string
+		command string
+	}{
+		{command: ControlWebSocketCommandSubscribe},
+		{code: ControlErrorCodeRateLimited, command: ControlWebSocketCommandStop},
+	} {
+		assert.NoError(t, c.WriteJSON(map[string]interface{}{"event_name": v.command, "payload": ControlWebSocketCommand{Workflow: "w"}}))
+		assert.NoError(t, c.SetReadDeadline(time.Now().Add(time.Second)))
+		var m struct {
+			EventName string                 `json:"event_name"`
+			Payload   ControlWebSocketResult `json:"payload"`
+		}
+		assert.NoError(t, c.ReadJSON(&m))
+		assert.Equal(t, ControlWebSocketEventNameResult, m.EventName)
+		if v.code == "" {
+			assert.Nil(t, m.Payload.Error)
+		} else if assert.NotNil(t, m.Payload.Error) {
+			assert.Equal(t, v.code, m.Payload.Error.Code)
+		}
+	}
+
+	// Commands are logged
+	ml.m.Lock()
+	defer ml.m.Unlock()
+	var ss []interface{}
+	for _, l := range ml.logs {
+		fs := make(map[string]interface{})
+		for _, f := range l.fields {
+			fs[f.Key] = f.Value
+		}
+		if fs["method"] == "websocket" {
+			ss = append(ss, fs["status"])
+		}
+	}
+	assert.Equal(t, []interface{}{"ok", ControlErrorCodeRateLimited}, ss)
+}
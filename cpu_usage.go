@@ -0,0 +1,55 @@
+package astiencoder
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// CPUUsageStat represents a stat computing the approximate CPU usage of a node, in percentage of one core
+// It measures the CPU time of the OS thread executing the code between Begin and End, which is why the goroutine is
+// locked to its thread in the meantime. Threads created by libav (e.g. codec threads) are not taken into account
+type CPUUsageStat struct {
+	begin time.Duration
+	v     int64
+}
+
+// NewCPUUsageStat creates a new CPU usage stat
+func NewCPUUsageStat() *CPUUsageStat {
+	return &CPUUsageStat{}
+}
+
+// CPUUsageSupported returns whether CPU usage can be measured on this platform
+func CPUUsageSupported() bool {
+	_, ok := threadCPUTime()
+	return ok
+}
+
+// Begin starts measuring CPU time. It must be called in the goroutine that will call End
+func (s *CPUUsageStat) Begin() {
+	runtime.LockOSThread()
+	s.begin, _ = threadCPUTime()
+}
+
+// End stops measuring CPU time
+func (s *CPUUsageStat) End() {
+	if end, ok := threadCPUTime(); ok && end > s.begin {
+		atomic.AddInt64(&s.v, int64(end-s.begin))
+	}
+	runtime.UnlockOSThread()
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *CPUUsageStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *CPUUsageStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *CPUUsageStat) Value(delta time.Duration) interface{} {
+	v := atomic.SwapInt64(&s.v, 0)
+	if delta <= 0 {
+		return 0.0
+	}
+	return float64(v) / float64(delta) * 100
+}
@@ -0,0 +1,20 @@
+//go:build linux
+// +build linux
+
+package astiencoder
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const clockThreadCPUTimeID = 3
+
+func threadCPUTime() (time.Duration, bool) {
+	var ts syscall.Timespec
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CLOCK_GETTIME, clockThreadCPUTimeID, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0, false
+	}
+	return time.Duration(ts.Nano()), true
+}
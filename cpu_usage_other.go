@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package astiencoder
+
+import "time"
+
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
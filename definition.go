@@ -0,0 +1,486 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/asticode/go-astikit"
+)
+
+// WorkflowDefinition represents the declarative definition of a workflow
+// It is meant to be decoded from JSON, or from YAML using the same field names
+type WorkflowDefinition struct {
+	Connections []ConnectionDefinition `json:"connections,omitempty" yaml:"connections,omitempty"`
+	// Paths of the definitions this definition is merged onto, resolved by LoadWorkflowDefinitionFile
+	Include []string         `json:"include,omitempty" yaml:"include,omitempty"`
+	Name    string           `json:"name" yaml:"name"`
+	Nodes   []NodeDefinition `json:"nodes" yaml:"nodes"`
+	// Variables are resolved when the workflow is built
+	Variables map[string]VariableDefinition `json:"variables,omitempty" yaml:"variables,omitempty"`
+	// Version of the schema. Definitions with an older version are migrated when the workflow is built
+	Version int `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// NodeDefinition represents the declarative definition of a node
+type NodeDefinition struct {
+	// Must be unique within the workflow
+	Name string `json:"name" yaml:"name"`
+	// Options of the node type, decoded with DecodeDefinitionOptions
+	Options map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+	// Overlays only: removes the node and its connections
+	Remove bool     `json:"remove,omitempty" yaml:"remove,omitempty"`
+	Tags   []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Name of a type registered in the NodeTypes used to build the workflow
+	Type string `json:"type" yaml:"type"`
+}
+
+// ConnectionDefinition represents the declarative definition of a connection between 2 nodes
+type ConnectionDefinition struct {
+	// Name of the parent node
+	From string `json:"from" yaml:"from"`
+	// Options of the parent node type, e.g. the index of the stream to connect
+	Options map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+	// Overlays only: removes the connections between the nodes
+	Remove bool `json:"remove,omitempty" yaml:"remove,omitempty"`
+	// Name of the child node
+	To string `json:"to" yaml:"to"`
+}
+
+// LoadWorkflowDefinition decodes a JSON workflow definition
+func LoadWorkflowDefinition(r io.Reader) (d WorkflowDefinition, err error) {
+	if err = json.NewDecoder(r).Decode(&d); err != nil {
+		err = fmt.Errorf("astiencoder: decoding workflow definition failed: %w", err)
+		return
+	}
+	return
+}
+
+// DecodeDefinitionOptions decodes definition options into dst, which is usually a pointer to a struct with json tags
+// Struct fields with the `definition:"required"` tag must be provided. Unknown options, missing required options
+// and type mismatches are returned as DefinitionErrors whose paths, e.g. "options.url", are relative to the node or
+// connection definition
+func DecodeDefinitionOptions(src map[string]interface{}, dst interface{}) (err error) {
+	// Get fields
+	names, required := definitionOptionFields(dst)
+
+	// Check options
+	var es DefinitionErrors
+	if len(names) > 0 {
+		// Index names
+		known := make(map[string]bool)
+		for _, n := range names {
+			known[n] = true
+		}
+
+		// Loop through options
+		var ks []string
+		for k := range src {
+			ks = append(ks, k)
+		}
+		sort.Strings(ks)
+		for _, k := range ks {
+			if !known[k] {
+				e := newDefinitionError("options."+k, fmt.Errorf("unknown option %s", k))
+				e.Suggestion = suggest(k, names)
+				es = append(es, e)
+			}
+		}
+
+		// Loop through required options
+		for _, n := range names {
+			if v, ok := src[n]; required[n] && (!ok || v == nil) {
+				es = append(es, newDefinitionError("options."+n, fmt.Errorf("missing required option %s", n)))
+			}
+		}
+	}
+	if len(es) > 0 {
+		return es
+	}
+
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(src); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling options failed: %w", err)
+		return
+	}
+
+	// Unmarshal
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(dst); err != nil {
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) {
+			return DefinitionErrors{newDefinitionError("options."+te.Field, fmt.Errorf("invalid type %s, expected %s", te.Value, te.Type))}
+		}
+		err = fmt.Errorf("astiencoder: unmarshaling options failed: %w", err)
+		return
+	}
+	return
+}
+
+// NodeType represents a type of node that can be instantiated from a definition
+type NodeType struct {
+	// Connects a node of this type to one of its children. If nil, nodes of this type can't have children
+	Connect func(parent, child Node, options map[string]interface{}) error
+	// Disconnects a node of this type from one of its children when a patch is applied. If nil, connections whose
+	// parent is of this type can't be removed
+	Disconnect func(parent, child Node, options map[string]interface{}) error
+	// Migrates the options of nodes of this type defined with an older version. If nil, options are kept as is
+	Migrate NodeMigration
+	// Creates a node of this type. Parents are created and connected beforehand
+	New func(b NodeBuild) (Node, error)
+}
+
+// NodeBuild represents what's available to create a node from its definition
+type NodeBuild struct {
+	Closer       *astikit.Closer
+	Definition   NodeDefinition
+	EventHandler *EventHandler
+	// Node options whose metadata are filled with the name and tags of the definition
+	Node    NodeOptions
+	Parents []NodeBuildParent
+	// Used by nodes to resolve their ${secret:<ref>} references when they need them
+	SecretProvider SecretProvider
+}
+
+// NodeBuildParent represents a parent of a node being built
+type NodeBuildParent struct {
+	Node Node
+	// Options of the connection
+	Options map[string]interface{}
+}
+
+// NodeTypes represents the node types available to build workflows from definitions
+type NodeTypes struct {
+	m  *sync.Mutex
+	ts map[string]NodeType
+}
+
+// NewNodeTypes creates new node types
+func NewNodeTypes() *NodeTypes {
+	return &NodeTypes{
+		m:  &sync.Mutex{},
+		ts: make(map[string]NodeType),
+	}
+}
+
+// Register registers a node type, replacing the one with the same name if any
+func (ts *NodeTypes) Register(name string, t NodeType) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	ts.ts[name] = t
+}
+
+func (ts *NodeTypes) names() (ns []string) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	for n := range ts.ts {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return
+}
+
+func (ts *NodeTypes) get(name string) (t NodeType, ok bool) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	t, ok = ts.ts[name]
+	return
+}
+
+// BuildWorkflowOptions represents build workflow options
+type BuildWorkflowOptions struct {
+	Closer       *astikit.Closer
+	Context      context.Context
+	EventHandler *EventHandler
+	// Provided to the nodes
+	SecretProvider SecretProvider
+	// If set, references to environment variables and secrets are substituted once variables have been resolved
+	Substitution *SubstitutionOptions
+	TaskFunc     CreateTaskFunc
+	Types        *NodeTypes
+	// Values of the definition's variables
+	Values map[string]interface{}
+}
+
+// BuildWorkflow migrates the definition, resolves its variables, substitutes its references, instantiates its nodes
+// with their registered types, connects them and adds the ones without parents to a new workflow
+// Deprecation warnings are emitted once the workflow has been created
+func BuildWorkflow(d WorkflowDefinition, o BuildWorkflowOptions) (w *Workflow, err error) {
+	// Default options
+	if o.Context == nil {
+		o.Context = context.Background()
+	}
+	if o.Types == nil {
+		o.Types = NewNodeTypes()
+	}
+
+	// Prepare definition
+	var ws []DefinitionWarning
+	if d, ws, err = prepareDefinition(d, o); err != nil {
+		return
+	}
+
+	// Index definition
+	ds := make(map[string]NodeDefinition)
+	idxs := make(map[string]int)
+	var names []string
+	for idx, n := range d.Nodes {
+		ds[n.Name] = n
+		idxs[n.Name] = idx
+		names = append(names, n.Name)
+	}
+	parents := make(map[string][]int)
+	children := make(map[string][]string)
+	for idx, c := range d.Connections {
+		parents[c.To] = append(parents[c.To], idx)
+		children[c.From] = append(children[c.From], c.To)
+	}
+
+	// Sort nodes so that parents are created before their children
+	var sorted []string
+	if sorted, err = sortDefinitionNodes(names, parents, children); err != nil {
+		return
+	}
+
+	// Create workflow
+	w = NewWorkflow(o.Context, d.Name, o.EventHandler, o.TaskFunc, o.Closer)
+	w.d = &workflowDefinition{
+		d: d,
+		m: &sync.Mutex{},
+		o: o,
+	}
+
+	// Emit deprecation warnings
+	for _, dw := range ws {
+		o.EventHandler.Emit(Event{
+			Name:    EventNameDefinitionDeprecated,
+			Payload: dw,
+			Target:  w,
+		})
+	}
+
+	// Loop through nodes
+	ns := make(map[string]Node)
+	for _, name := range sorted {
+		// Get parents
+		nd := ds[name]
+		var ps []NodeBuildParent
+		for _, idx := range parents[name] {
+			c := d.Connections[idx]
+			ps = append(ps, NodeBuildParent{
+				Node:    ns[c.From],
+				Options: c.Options,
+			})
+		}
+
+		// Create node
+		var n Node
+		if n, err = newDefinitionNode(nd, ps, o); err != nil {
+			err = locateDefinitionErrors(fmt.Sprintf("nodes[%d]", idxs[name]), name, err)
+			return
+		}
+		ns[name] = n
+
+		// Connect parents
+		for _, idx := range parents[name] {
+			c := d.Connections[idx]
+			pt, _ := o.Types.get(ds[c.From].Type)
+			if pt.Connect == nil {
+				err = DefinitionErrors{{
+					Err:  fmt.Errorf("node %s of type %s can't have children", c.From, ds[c.From].Type),
+					Node: c.From,
+					Path: fmt.Sprintf("connections[%d].from", idx),
+				}}
+				return
+			}
+			if err = pt.Connect(ns[c.From], n, c.Options); err != nil {
+				err = locateDefinitionErrors(fmt.Sprintf("connections[%d]", idx), c.From, err)
+				return
+			}
+		}
+
+		// Add node without parents to the workflow
+		if len(ps) == 0 {
+			w.AddChild(n)
+		}
+	}
+	return
+}
+
+func newDefinitionNode(nd NodeDefinition, ps []NodeBuildParent, o BuildWorkflowOptions) (Node, error) {
+	t, _ := o.Types.get(nd.Type)
+	return t.New(NodeBuild{
+		Closer:         o.Closer,
+		Definition:     nd,
+		EventHandler:   o.EventHandler,
+		Node:           NodeOptions{Metadata: NodeMetadata{Name: nd.Name, Tags: nd.Tags}},
+		Parents:        ps,
+		SecretProvider: o.SecretProvider,
+	})
+}
+
+// validateDefinition checks names, types and connections of the definition
+func validateDefinition(d WorkflowDefinition, ts *NodeTypes) (es DefinitionErrors) {
+	// Check overlay fields
+	es = validateDefinitionOverlay(d)
+
+	// Get types
+	types := ts.names()
+
+	// Loop through nodes
+	names := make(map[string]bool)
+	var ns []string
+	for idx, n := range d.Nodes {
+		// Check name
+		p := fmt.Sprintf("nodes[%d]", idx)
+		if n.Name == "" {
+			es = append(es, newDefinitionError(p+".name", errors.New("missing name")))
+		} else if names[n.Name] {
+			es = append(es, &DefinitionError{Err: fmt.Errorf("node %s is defined more than once", n.Name), Node: n.Name, Path: p + ".name"})
+		} else {
+			names[n.Name] = true
+			ns = append(ns, n.Name)
+		}
+
+		// Check type
+		if n.Type == "" {
+			es = append(es, &DefinitionError{Err: errors.New("missing type"), Node: n.Name, Path: p + ".type"})
+		} else if _, ok := ts.get(n.Type); !ok {
+			es = append(es, &DefinitionError{
+				Err:        fmt.Errorf("unknown node type %s", n.Type),
+				Node:       n.Name,
+				Path:       p + ".type",
+				Suggestion: suggest(n.Type, types),
+			})
+		}
+	}
+
+	// Loop through connections
+	for idx, c := range d.Connections {
+		for _, v := range []struct {
+			field string
+			name  string
+		}{
+			{field: "from", name: c.From},
+			{field: "to", name: c.To},
+		} {
+			if !names[v.name] {
+				es = append(es, &DefinitionError{
+					Err:        fmt.Errorf("unknown node %s", v.name),
+					Path:       fmt.Sprintf("connections[%d].%s", idx, v.field),
+					Suggestion: suggest(v.name, ns),
+				})
+			}
+		}
+	}
+	return
+}
+
+// ValidateWorkflowDefinition migrates the definition, resolves its variables, substitutes its references and checks
+// that its nodes and connections can be built with the provided types, without instantiating them
+// Node options are only decoded when nodes are instantiated and are therefore not checked
+func ValidateWorkflowDefinition(d WorkflowDefinition, o BuildWorkflowOptions) (ws []DefinitionWarning, err error) {
+	// Default options
+	if o.Types == nil {
+		o.Types = NewNodeTypes()
+	}
+
+	// Prepare definition
+	if d, ws, err = prepareDefinition(d, o); err != nil {
+		return
+	}
+
+	// Index definition
+	var names []string
+	for _, n := range d.Nodes {
+		names = append(names, n.Name)
+	}
+	parents := make(map[string][]int)
+	children := make(map[string][]string)
+	for idx, c := range d.Connections {
+		parents[c.To] = append(parents[c.To], idx)
+		children[c.From] = append(children[c.From], c.To)
+	}
+
+	// Check cycles
+	_, err = sortDefinitionNodes(names, parents, children)
+	return
+}
+
+func prepareDefinition(d WorkflowDefinition, o BuildWorkflowOptions) (r WorkflowDefinition, ws []DefinitionWarning, err error) {
+	// Migrate
+	if r, ws, err = d.Migrate(o.Types); err != nil {
+		return
+	}
+
+	// Resolve variables
+	if len(r.Variables) > 0 || len(o.Values) > 0 {
+		if r, err = r.Resolve(o.Values); err != nil {
+			return
+		}
+	}
+
+	// Substitute references
+	if o.Substitution != nil {
+		if r, err = r.Substitute(*o.Substitution); err != nil {
+			return
+		}
+	}
+
+	// Validate definition
+	if es := validateDefinition(r, o.Types); len(es) > 0 {
+		err = es
+		return
+	}
+	return
+}
+
+func sortDefinitionNodes(names []string, parents map[string][]int, children map[string][]string) (sorted []string, err error) {
+	// Count parents
+	counts := make(map[string]int)
+	var queue []string
+	for _, n := range names {
+		counts[n] = len(parents[n])
+		if counts[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	// Loop
+	for len(queue) > 0 {
+		// Shift
+		n := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, n)
+
+		// Loop through children
+		for _, c := range children[n] {
+			counts[c]--
+			if counts[c] == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	// Cycle
+	if len(sorted) < len(names) {
+		var cs []string
+		for _, n := range names {
+			if counts[n] > 0 {
+				cs = append(cs, n)
+			}
+		}
+		sort.Strings(cs)
+		err = DefinitionErrors{newDefinitionError("connections", fmt.Errorf("nodes %s are part of a cycle", strings.Join(cs, ", ")))}
+		return
+	}
+	return
+}
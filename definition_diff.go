@@ -0,0 +1,524 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Definition change types
+const (
+	DefinitionChangeTypeConnectionAdded   = "connection.added"
+	DefinitionChangeTypeConnectionRemoved = "connection.removed"
+	DefinitionChangeTypeNodeAdded         = "node.added"
+	DefinitionChangeTypeNodeModified      = "node.modified"
+	DefinitionChangeTypeNodeRemoved       = "node.removed"
+)
+
+// DefinitionChange represents a change between 2 workflow definitions
+type DefinitionChange struct {
+	// Set for connection changes
+	Connection *ConnectionDefinition `json:"connection,omitempty"`
+	// Set for node changes. It's the new definition of added and modified nodes and the old definition of removed
+	// nodes
+	Node *NodeDefinition `json:"node,omitempty"`
+	// Options of a modified node whose value has changed. Removed options have a nil value
+	Options map[string]interface{} `json:"options,omitempty"`
+	Type    string                 `json:"type"`
+}
+
+// DefinitionChanges represents the changes between 2 workflow definitions, in the order they must be applied
+type DefinitionChanges []DefinitionChange
+
+// DiffDefinitions returns the changes turning definition a into definition b
+// Both definitions are expected to be migrated and resolved, e.g. a is the result of Workflow.ExportDefinition. Nodes
+// whose type has changed are removed and added again. Changes are ordered as follows: removed connections, removed
+// nodes, added nodes, modified nodes and added connections
+func DiffDefinitions(a, b WorkflowDefinition) (cs DefinitionChanges) {
+	// Index nodes
+	ans := make(map[string]NodeDefinition)
+	for _, n := range a.Nodes {
+		ans[n.Name] = n
+	}
+	bns := make(map[string]NodeDefinition)
+	for _, n := range b.Nodes {
+		bns[n.Name] = n
+	}
+
+	// Index connections
+	acs := make(map[string]bool)
+	for _, c := range a.Connections {
+		acs[connectionDefinitionKey(c)] = true
+	}
+	bcs := make(map[string]bool)
+	for _, c := range b.Connections {
+		bcs[connectionDefinitionKey(c)] = true
+	}
+
+	// Removed connections
+	for idx := range a.Connections {
+		if c := a.Connections[idx]; !bcs[connectionDefinitionKey(c)] {
+			cs = append(cs, DefinitionChange{Connection: &c, Type: DefinitionChangeTypeConnectionRemoved})
+		}
+	}
+
+	// Removed nodes
+	for idx := range a.Nodes {
+		if n := a.Nodes[idx]; bns[n.Name].Type != n.Type {
+			cs = append(cs, DefinitionChange{Node: &n, Type: DefinitionChangeTypeNodeRemoved})
+		}
+	}
+
+	// Added nodes
+	for idx := range b.Nodes {
+		if n := b.Nodes[idx]; ans[n.Name].Type != n.Type {
+			cs = append(cs, DefinitionChange{Node: &n, Type: DefinitionChangeTypeNodeAdded})
+		}
+	}
+
+	// Modified nodes
+	for idx := range b.Nodes {
+		// Get old node
+		n := b.Nodes[idx]
+		an, ok := ans[n.Name]
+		if !ok || an.Type != n.Type {
+			continue
+		}
+
+		// Diff
+		os := diffDefinitionOptions(an.Options, n.Options)
+		if len(os) > 0 || !definitionValuesEqual(an.Tags, n.Tags) {
+			cs = append(cs, DefinitionChange{
+				Node:    &n,
+				Options: os,
+				Type:    DefinitionChangeTypeNodeModified,
+			})
+		}
+	}
+
+	// Added connections
+	for idx := range b.Connections {
+		if c := b.Connections[idx]; !acs[connectionDefinitionKey(c)] {
+			cs = append(cs, DefinitionChange{Connection: &c, Type: DefinitionChangeTypeConnectionAdded})
+		}
+	}
+	return
+}
+
+func connectionDefinitionKey(c ConnectionDefinition) string {
+	var o []byte
+	if len(c.Options) > 0 {
+		o, _ = json.Marshal(c.Options)
+	}
+	return c.From + "\x00" + c.To + "\x00" + string(o)
+}
+
+// definitionValuesEqual compares the JSON representation of values so that 1 and 1.0 are equal and so that nil and
+// empty values are equal
+func definitionValuesEqual(a, b interface{}) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	for _, v := range []string{"null", "[]", "{}"} {
+		if string(ab) == v {
+			ab = []byte("null")
+		}
+		if string(bb) == v {
+			bb = []byte("null")
+		}
+	}
+	return string(ab) == string(bb)
+}
+
+func diffDefinitionOptions(a, b map[string]interface{}) (os map[string]interface{}) {
+	os = make(map[string]interface{})
+	for k, v := range b {
+		if av, ok := a[k]; !ok || !definitionValuesEqual(av, v) {
+			os[k] = v
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			os[k] = nil
+		}
+	}
+	if len(os) == 0 {
+		os = nil
+	}
+	return
+}
+
+// patch returns a copy of the definition with the changes applied
+func (d WorkflowDefinition) patch(cs DefinitionChanges) (r WorkflowDefinition, err error) {
+	// Copy
+	if r, err = d.mapStrings(func(path, s string) (interface{}, error) { return s, nil }); err != nil {
+		return
+	}
+
+	// Loop through changes
+	var es DefinitionErrors
+	for idx, c := range cs {
+		// Check change
+		p := fmt.Sprintf("changes[%d]", idx)
+		if (c.Type == DefinitionChangeTypeConnectionAdded || c.Type == DefinitionChangeTypeConnectionRemoved) && c.Connection == nil {
+			es = append(es, newDefinitionError(p+".connection", errors.New("missing connection")))
+			continue
+		} else if (c.Type == DefinitionChangeTypeNodeAdded || c.Type == DefinitionChangeTypeNodeModified || c.Type == DefinitionChangeTypeNodeRemoved) && c.Node == nil {
+			es = append(es, newDefinitionError(p+".node", errors.New("missing node")))
+			continue
+		}
+
+		// Get node index
+		ni := -1
+		if c.Node != nil {
+			for i, n := range r.Nodes {
+				if n.Name == c.Node.Name {
+					ni = i
+					break
+				}
+			}
+		}
+
+		// Switch on type
+		switch c.Type {
+		case DefinitionChangeTypeConnectionAdded:
+			r.Connections = append(r.Connections, *c.Connection)
+		case DefinitionChangeTypeConnectionRemoved:
+			// Get connection index
+			ci := -1
+			k := connectionDefinitionKey(*c.Connection)
+			for i, dc := range r.Connections {
+				if connectionDefinitionKey(dc) == k {
+					ci = i
+					break
+				}
+			}
+
+			// Remove
+			if ci < 0 {
+				es = append(es, newDefinitionError(p+".connection", fmt.Errorf("connection %s -> %s doesn't exist", c.Connection.From, c.Connection.To)))
+				continue
+			}
+			r.Connections = append(r.Connections[:ci], r.Connections[ci+1:]...)
+		case DefinitionChangeTypeNodeAdded:
+			if ni >= 0 {
+				es = append(es, &DefinitionError{Err: fmt.Errorf("node %s already exists", c.Node.Name), Node: c.Node.Name, Path: p + ".node"})
+				continue
+			}
+			r.Nodes = append(r.Nodes, *c.Node)
+		case DefinitionChangeTypeNodeModified, DefinitionChangeTypeNodeRemoved:
+			// Node doesn't exist
+			if ni < 0 {
+				es = append(es, &DefinitionError{Err: fmt.Errorf("node %s doesn't exist", c.Node.Name), Node: c.Node.Name, Path: p + ".node"})
+				continue
+			}
+
+			// Update
+			if c.Type == DefinitionChangeTypeNodeModified {
+				r.Nodes[ni] = *c.Node
+			} else {
+				r.Nodes = append(r.Nodes[:ni], r.Nodes[ni+1:]...)
+			}
+		default:
+			es = append(es, newDefinitionError(p+".type", fmt.Errorf("unknown change type %s", c.Type)))
+		}
+	}
+	if len(es) > 0 {
+		err = es
+	}
+	return
+}
+
+// workflowDefinition represents the definition a workflow has been built from, updated each time a patch is applied
+type workflowDefinition struct {
+	d WorkflowDefinition
+	m *sync.Mutex
+	o BuildWorkflowOptions
+}
+
+// ApplyPatch applies changes, usually returned by DiffDefinitions, to a workflow built with BuildWorkflow without
+// restarting it. Only the nodes affected by the changes are touched:
+//   - modified nodes implementing Reconfigurer are reconfigured with their changed options. Other modified nodes, e.g.
+//     the ones whose tags have changed or whose options can't all be reconfigured, are replaced
+//   - removed connections are disconnected with the Disconnect function of their parent's type
+//   - removed nodes are disconnected and stopped
+//   - added nodes are created, connected and, if the workflow is running, started
+//
+// Changes are checked against the definition the workflow has been built from, with the patches applied so far.
+// Operations already performed when an error occurs are not rolled back. Once applied, the EventNameWorkflowPatched
+// event is emitted
+func (w *Workflow) ApplyPatch(cs DefinitionChanges) error {
+	return w.applyPatch(cs, "")
+}
+
+// ApplyPatch applies changes to the workflow without restarting it
+func (c *WorkflowController) ApplyPatch(cs DefinitionChanges) error {
+	return c.w.applyPatch(cs, c.actor)
+}
+
+func (w *Workflow) applyPatch(cs DefinitionChanges, actor string) (err error) {
+	// Apply
+	if err = w.applyPatchFunc(cs); err != nil {
+		err = fmt.Errorf("astiencoder: applying patch failed: %w", err)
+	}
+	w.audit(nil, actor, AuditActionPatch, map[string]interface{}{"changes": cs}, err)
+	if err != nil {
+		return
+	}
+
+	// Emit
+	w.e.Emit(Event{
+		Name:    EventNameWorkflowPatched,
+		Payload: cs,
+		Target:  w,
+	})
+	return
+}
+
+func (w *Workflow) applyPatchFunc(cs DefinitionChanges) (err error) {
+	// Workflow has not been built from a definition
+	if w.d == nil {
+		return errors.New("astiencoder: workflow has not been built from a definition")
+	}
+
+	// Lock
+	w.d.m.Lock()
+	defer w.d.m.Unlock()
+
+	// Patch definition
+	var d WorkflowDefinition
+	if d, err = w.d.d.patch(cs); err != nil {
+		return
+	}
+
+	// Validate definition
+	if es := validateDefinition(d, w.d.o.Types); len(es) > 0 {
+		return es
+	}
+
+	// Index definitions
+	ods := make(map[string]NodeDefinition)
+	for _, n := range w.d.d.Nodes {
+		ods[n.Name] = n
+	}
+	ds := make(map[string]NodeDefinition)
+	var names []string
+	for _, n := range d.Nodes {
+		ds[n.Name] = n
+		names = append(names, n.Name)
+	}
+	parents := make(map[string][]int)
+	children := make(map[string][]string)
+	for idx, c := range d.Connections {
+		parents[c.To] = append(parents[c.To], idx)
+		children[c.From] = append(children[c.From], c.To)
+	}
+
+	// Sort nodes so that parents are created before their children
+	var sorted []string
+	if sorted, err = sortDefinitionNodes(names, parents, children); err != nil {
+		return
+	}
+
+	// Index nodes
+	ns := w.indexedNodes()
+	for _, n := range w.d.d.Nodes {
+		if _, ok := ns[n.Name]; !ok {
+			return fmt.Errorf("astiencoder: node %s is not part of the workflow anymore", n.Name)
+		}
+	}
+
+	// Get nodes to reconfigure, to remove and to add
+	reconfigured := make(map[string]map[string]interface{})
+	removed := make(map[string]bool)
+	added := make(map[string]bool)
+	for _, c := range cs {
+		switch c.Type {
+		case DefinitionChangeTypeNodeAdded:
+			added[c.Node.Name] = true
+		case DefinitionChangeTypeNodeModified:
+			if os, ok := definitionNodeReconfigureOptions(ns[c.Node.Name], ods[c.Node.Name], c); !ok {
+				removed[c.Node.Name] = true
+				added[c.Node.Name] = true
+			} else if len(os) > 0 {
+				reconfigured[c.Node.Name] = os
+			}
+		case DefinitionChangeTypeNodeRemoved:
+			removed[c.Node.Name] = true
+		}
+	}
+
+	// Get connections to remove
+	ocs := make(map[string]bool)
+	var rcs []ConnectionDefinition
+	for _, c := range d.Connections {
+		ocs[connectionDefinitionKey(c)] = true
+	}
+	for _, c := range w.d.d.Connections {
+		if !ocs[connectionDefinitionKey(c)] || removed[c.From] || removed[c.To] {
+			// Parent can't be disconnected
+			if t, _ := w.d.o.Types.get(ods[c.From].Type); t.Disconnect == nil {
+				return fmt.Errorf("astiencoder: node %s of type %s can't be disconnected", c.From, ods[c.From].Type)
+			}
+			rcs = append(rcs, c)
+		}
+	}
+
+	// Get connections to add
+	ocs = make(map[string]bool)
+	acs := make(map[int]bool)
+	for _, c := range w.d.d.Connections {
+		ocs[connectionDefinitionKey(c)] = true
+	}
+	for idx, c := range d.Connections {
+		if !ocs[connectionDefinitionKey(c)] || added[c.From] || added[c.To] {
+			// Parent can't have children
+			if t, _ := w.d.o.Types.get(ds[c.From].Type); t.Connect == nil {
+				return fmt.Errorf("astiencoder: node %s of type %s can't have children", c.From, ds[c.From].Type)
+			}
+			acs[idx] = true
+		}
+	}
+
+	// Reconfigure nodes now that the patch is valid
+	var rcns []string
+	for name := range reconfigured {
+		rcns = append(rcns, name)
+	}
+	sort.Strings(rcns)
+	for idx, name := range rcns {
+		if err = w.reconfigureDefinitionNode(ns[name], reconfigured[name]); err != nil {
+			// Keep the definition in sync with the nodes that have already been reconfigured
+			done := make(map[string]bool)
+			for _, v := range rcns[:idx] {
+				done[v] = true
+			}
+			nds := make([]NodeDefinition, len(w.d.d.Nodes))
+			for i, n := range w.d.d.Nodes {
+				if done[n.Name] {
+					n = ds[n.Name]
+				}
+				nds[i] = n
+			}
+			w.d.d.Nodes = nds
+			return fmt.Errorf("astiencoder: reconfiguring node %s failed: %w", name, err)
+		}
+	}
+
+	// Disconnect
+	for _, c := range rcs {
+		t, _ := w.d.o.Types.get(ods[c.From].Type)
+		if err = t.Disconnect(ns[c.From], ns[c.To], c.Options); err != nil {
+			return fmt.Errorf("astiencoder: disconnecting %s -> %s failed: %w", c.From, c.To, err)
+		}
+	}
+
+	// Remove nodes
+	var rns []string
+	for name := range removed {
+		rns = append(rns, name)
+	}
+	sort.Strings(rns)
+	for _, name := range rns {
+		w.DelChild(ns[name])
+		ns[name].Stop()
+		delete(ns, name)
+	}
+
+	// Loop through nodes
+	var created []Node
+	for _, name := range sorted {
+		// Create node
+		if added[name] {
+			// Get parents
+			var ps []NodeBuildParent
+			for _, idx := range parents[name] {
+				ps = append(ps, NodeBuildParent{
+					Node:    ns[d.Connections[idx].From],
+					Options: d.Connections[idx].Options,
+				})
+			}
+
+			// Create
+			var n Node
+			if n, err = newDefinitionNode(ds[name], ps, w.d.o); err != nil {
+				return fmt.Errorf("astiencoder: creating node %s failed: %w", name, err)
+			}
+			ns[name] = n
+			created = append(created, n)
+
+			// Add node without parents to the workflow
+			if len(ps) == 0 {
+				w.AddChild(n)
+			}
+		}
+
+		// Connect parents
+		for _, idx := range parents[name] {
+			if !acs[idx] {
+				continue
+			}
+			c := d.Connections[idx]
+			t, _ := w.d.o.Types.get(ds[c.From].Type)
+			if err = t.Connect(ns[c.From], ns[c.To], c.Options); err != nil {
+				return fmt.Errorf("astiencoder: connecting %s -> %s failed: %w", c.From, c.To, err)
+			}
+		}
+	}
+
+	// Start nodes, children first so that they don't miss what their parents send
+	if s := w.Status(); s == StatusPaused || s == StatusRunning {
+		for idx := len(created) - 1; idx >= 0; idx-- {
+			w.StartNodes(created[idx])
+		}
+	}
+
+	// Update definition
+	w.d.d = d
+	return
+}
+
+// definitionNodeReconfigureOptions returns the options to reconfigure the node with, or false if the node must be
+// replaced
+func definitionNodeReconfigureOptions(n Node, od NodeDefinition, c DefinitionChange) (os map[string]interface{}, ok bool) {
+	// Type or tags have changed
+	if od.Type != c.Node.Type || !definitionValuesEqual(od.Tags, c.Node.Tags) {
+		return
+	}
+
+	// Get options
+	if os = diffDefinitionOptions(od.Options, c.Node.Options); len(os) == 0 {
+		ok = true
+		return
+	}
+
+	// Node can't be reconfigured
+	if _, ok = n.(Reconfigurer); !ok {
+		return
+	}
+
+	// Removed options can't be reconfigured since zero values are left unchanged
+	for _, v := range os {
+		if v == nil {
+			ok = false
+			return
+		}
+	}
+	return
+}
+
+func (w *Workflow) reconfigureDefinitionNode(n Node, os map[string]interface{}) (err error) {
+	// Reconfigure
+	if err = n.(Reconfigurer).Reconfigure(os); err != nil {
+		return
+	}
+
+	// Emit
+	w.e.Emit(Event{
+		Name:    EventNameNodeReconfigured,
+		Payload: os,
+		Target:  n,
+	})
+	return
+}
@@ -0,0 +1,192 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	a := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "a", Options: map[string]interface{}{"stream": 0.0}, To: "b"},
+			{From: "a", Options: map[string]interface{}{"stream": 1.0}, To: "c"},
+		},
+		Nodes: []NodeDefinition{
+			{Name: "a", Options: map[string]interface{}{"value": 1.0}, Type: "t"},
+			{Name: "b", Options: map[string]interface{}{"value": 2.0, "other": "o"}, Type: "t"},
+			{Name: "c", Type: "t"},
+			{Name: "d", Type: "t"},
+		},
+	}
+	b := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "a", Options: map[string]interface{}{"stream": 0}, To: "b"},
+			{From: "a", Options: map[string]interface{}{"stream": 1}, To: "e"},
+		},
+		Nodes: []NodeDefinition{
+			{Name: "a", Options: map[string]interface{}{"value": 1}, Tags: []string{}, Type: "t"},
+			{Name: "b", Options: map[string]interface{}{"value": 3}, Type: "t"},
+			{Name: "d", Type: "leaf"},
+			{Name: "e", Type: "t"},
+		},
+	}
+	assert.Equal(t, DefinitionChanges{
+		{Connection: &a.Connections[1], Type: DefinitionChangeTypeConnectionRemoved},
+		{Node: &a.Nodes[2], Type: DefinitionChangeTypeNodeRemoved},
+		{Node: &a.Nodes[3], Type: DefinitionChangeTypeNodeRemoved},
+		{Node: &b.Nodes[2], Type: DefinitionChangeTypeNodeAdded},
+		{Node: &b.Nodes[3], Type: DefinitionChangeTypeNodeAdded},
+		{Node: &b.Nodes[1], Options: map[string]interface{}{"other": nil, "value": 3}, Type: DefinitionChangeTypeNodeModified},
+		{Connection: &b.Connections[1], Type: DefinitionChangeTypeConnectionAdded},
+	}, DiffDefinitions(a, b))
+	assert.Len(t, DiffDefinitions(a, a), 0)
+}
+
+type mockedPatchNode struct {
+	*mockedStatsNode
+	o mockedReconfigureOptions
+}
+
+func (n *mockedPatchNode) Reconfigure(opts interface{}) error {
+	return DecodeReconfigureOptions(opts, &n.o)
+}
+
+func TestWorkflowApplyPatch(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	var created []string
+	var disconnected []string
+	ts.Register("t", NodeType{
+		Connect: func(parent, child Node, options map[string]interface{}) error {
+			ConnectNodes(parent, child)
+			return nil
+		},
+		Disconnect: func(parent, child Node, options map[string]interface{}) error {
+			disconnected = append(disconnected, parent.Metadata().Name+" -> "+child.Metadata().Name)
+			DisconnectNodes(parent, child)
+			return nil
+		},
+		New: func(b NodeBuild) (Node, error) {
+			var o mockedReconfigureOptions
+			if err := DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+				return nil, err
+			}
+			created = append(created, b.Node.Metadata.Name)
+			n := &mockedPatchNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, eh), o: o}
+			n.mockedStatsNode.o.Metadata.Tags = b.Node.Metadata.Tags
+			return n, nil
+		},
+	})
+	ts.Register("leaf", NodeType{New: func(b NodeBuild) (Node, error) {
+		created = append(created, b.Node.Metadata.Name)
+		return newMockedStatsNode(b.Node.Metadata.Name, eh), nil
+	}})
+
+	// Build
+	d := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "a", To: "b"},
+			{From: "b", To: "c"},
+		},
+		Name: "w",
+		Nodes: []NodeDefinition{
+			{Name: "a", Type: "t"},
+			{Name: "b", Options: map[string]interface{}{"bit_rate": 1.0}, Type: "t"},
+			{Name: "c", Type: "leaf"},
+		},
+		Version: DefinitionVersion,
+	}
+	w, err := BuildWorkflow(d, BuildWorkflowOptions{Closer: astikit.NewCloser(), EventHandler: eh, Types: ts})
+	assert.NoError(t, err)
+	created = []string{}
+	var es []Event
+	eh.AddForEventName(EventNameWorkflowPatched, func(e Event) bool {
+		es = append(es, e)
+		return false
+	})
+	var as []AuditEntry
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		as = append(as, e.Payload.(AuditEntry))
+		return false
+	})
+
+	// Reconfigurable options are reconfigured
+	b := w.indexedNodes()["b"].(*mockedPatchNode)
+	d2 := d
+	d2.Nodes = []NodeDefinition{d.Nodes[0], {Name: "b", Options: map[string]interface{}{"bit_rate": 2.0}, Type: "t"}, d.Nodes[2]}
+	err = w.Controller("alice").ApplyPatch(DiffDefinitions(d, d2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, b.o.BitRate)
+	assert.Equal(t, b, w.indexedNodes()["b"])
+	assert.Len(t, created, 0)
+	assert.Len(t, es, 1)
+	assert.Equal(t, "alice", as[0].Actor)
+	assert.Equal(t, AuditActionPatch, as[0].Action)
+
+	// Other changes replace the node and only the affected connections are touched
+	d3 := d2
+	d3.Nodes = []NodeDefinition{d.Nodes[0], {Name: "b", Options: map[string]interface{}{"bit_rate": 2.0}, Tags: []string{"tag"}, Type: "t"}, d.Nodes[2], {Name: "d", Type: "leaf"}}
+	d3.Connections = []ConnectionDefinition{d.Connections[0], d.Connections[1], {From: "a", To: "d"}}
+	err = w.ApplyPatch(DiffDefinitions(d2, d3))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "d"}, created)
+	assert.Equal(t, []string{"a -> b", "b -> c"}, disconnected)
+	ns := w.indexedNodes()
+	assert.NotEqual(t, b, ns["b"])
+	assert.Equal(t, []string{"tag"}, ns["b"].Metadata().Tags)
+	assert.Equal(t, []Node{ns["b"], ns["d"]}, ns["a"].Children())
+	assert.Equal(t, []Node{ns["c"]}, ns["b"].Children())
+	assert.Len(t, b.Parents(), 0)
+	assert.Len(t, b.Children(), 0)
+
+	// Removed nodes are disconnected and stopped
+	created = []string{}
+	disconnected = []string{}
+	d4 := d3
+	d4.Nodes = d3.Nodes[:3]
+	d4.Connections = d3.Connections[:2]
+	err = w.ApplyPatch(DiffDefinitions(d3, d4))
+	assert.NoError(t, err)
+	assert.Len(t, created, 0)
+	assert.Equal(t, []string{"a -> d"}, disconnected)
+	assert.Len(t, w.indexedNodes(), 3)
+	assert.Equal(t, d4, w.d.d)
+	assert.Len(t, es, 3)
+
+	// Errors
+	err = w.ApplyPatch(DefinitionChanges{{Node: &NodeDefinition{Name: "d"}, Type: DefinitionChangeTypeNodeRemoved}})
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: changes[0].node: node d doesn't exist")
+	err = w.ApplyPatch(DefinitionChanges{{Node: &NodeDefinition{Name: "d", Type: "unknown"}, Type: DefinitionChangeTypeNodeAdded}})
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: nodes[3].type: unknown node type unknown")
+	err = w.ApplyPatch(DefinitionChanges{{Connection: &ConnectionDefinition{From: "c", To: "a"}, Type: DefinitionChangeTypeConnectionAdded}})
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: connections: nodes a, b, c are part of a cycle")
+
+	// Nodes are not reconfigured when the patch is rejected
+	b = w.indexedNodes()["b"].(*mockedPatchNode)
+	nb := d4.Nodes[1]
+	nb.Options = map[string]interface{}{"bit_rate": 3.0}
+	d5 := d4
+	d5.Nodes = []NodeDefinition{d4.Nodes[0], nb, d4.Nodes[2], {Name: "e", Type: "t"}}
+	d5.Connections = []ConnectionDefinition{d4.Connections[0], d4.Connections[1], {From: "c", To: "e"}}
+	err = w.ApplyPatch(DiffDefinitions(d4, d5))
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: node c of type leaf can't have children")
+	assert.Equal(t, 2, b.o.BitRate)
+	assert.Equal(t, d4, w.d.d)
+
+	// Reconfigure errors are returned
+	nb.Options = map[string]interface{}{"bit_rate": "invalid"}
+	d5 = d4
+	d5.Nodes = []NodeDefinition{d4.Nodes[0], nb, d4.Nodes[2]}
+	err = w.ApplyPatch(DiffDefinitions(d4, d5))
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: reconfiguring node b failed: astiencoder: options.bit_rate: invalid type string, expected int")
+	assert.Equal(t, b, w.indexedNodes()["b"])
+	assert.Equal(t, d4, w.d.d)
+	err = NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser()).ApplyPatch(nil)
+	assert.EqualError(t, err, "astiencoder: applying patch failed: astiencoder: workflow has not been built from a definition")
+	assert.Len(t, es, 3)
+	assert.Equal(t, "astiencoder: applying patch failed: astiencoder: changes[0].node: node d doesn't exist", as[3].Error)
+}
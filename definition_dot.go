@@ -0,0 +1,47 @@
+package astiencoder
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOT returns the Graphviz representation of the definition, e.g. to be rendered with `dot -Tsvg`
+// Nodes are labeled with their name and type, and connections with their options
+func (d WorkflowDefinition) DOT() []byte {
+	// Open graph
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "digraph %s {\n", strconv.Quote(d.Name))
+
+	// Loop through nodes
+	for _, n := range d.Nodes {
+		fmt.Fprintf(buf, "\t%s [label=%s];\n", strconv.Quote(n.Name), strconv.Quote(n.Name+"\n("+n.Type+")"))
+	}
+
+	// Loop through connections
+	for _, c := range d.Connections {
+		fmt.Fprintf(buf, "\t%s -> %s", strconv.Quote(c.From), strconv.Quote(c.To))
+		if len(c.Options) > 0 {
+			// Sort options
+			var ks []string
+			for k := range c.Options {
+				ks = append(ks, k)
+			}
+			sort.Strings(ks)
+
+			// Label
+			var ls []string
+			for _, k := range ks {
+				ls = append(ls, fmt.Sprintf("%s=%v", k, c.Options[k]))
+			}
+			fmt.Fprintf(buf, " [label=%s]", strconv.Quote(strings.Join(ls, ", ")))
+		}
+		buf.WriteString(";\n")
+	}
+
+	// Close graph
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
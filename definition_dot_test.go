@@ -0,0 +1,31 @@
+package astiencoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowDefinitionDOT(t *testing.T) {
+	d := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "demuxer", Options: map[string]interface{}{"stream": 0.0, "codec": "h264"}, To: "decoder"},
+			{From: "decoder", To: "encoder"},
+		},
+		Name: "w",
+		Nodes: []NodeDefinition{
+			{Name: "demuxer", Type: "demuxer"},
+			{Name: "decoder", Type: "decoder"},
+			{Name: `en"coder`, Type: "encoder"},
+		},
+	}
+	d.Connections[1].To = `en"coder`
+	assert.Equal(t, `digraph "w" {
+	"demuxer" [label="demuxer\n(demuxer)"];
+	"decoder" [label="decoder\n(decoder)"];
+	"en\"coder" [label="en\"coder\n(encoder)"];
+	"demuxer" -> "decoder" [label="codec=h264, stream=0"];
+	"decoder" -> "en\"coder";
+}
+`, string(d.DOT()))
+}
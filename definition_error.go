@@ -0,0 +1,177 @@
+package astiencoder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefinitionError represents an error located in a workflow definition
+type DefinitionError struct {
+	Err error
+	// Name of the offending node, if any
+	Node string
+	// Path of the offending value in the definition, e.g. "nodes[2].options.url"
+	Path string
+	// e.g. `did you mean "demuxer"?`
+	Suggestion string
+}
+
+func newDefinitionError(path string, err error) *DefinitionError {
+	return &DefinitionError{
+		Err:  err,
+		Path: path,
+	}
+}
+
+// Error implements the error interface
+func (e *DefinitionError) Error() string {
+	s := "astiencoder: "
+	if e.Path != "" {
+		s += e.Path + ": "
+	}
+	s += e.Err.Error()
+	if e.Suggestion != "" {
+		s += ", " + e.Suggestion
+	}
+	return s
+}
+
+// Unwrap implements the errors.Wrapper interface
+func (e *DefinitionError) Unwrap() error {
+	return e.Err
+}
+
+// DefinitionErrors represents all the errors found in a workflow definition
+type DefinitionErrors []*DefinitionError
+
+// Error implements the error interface
+func (es DefinitionErrors) Error() string {
+	var ss []string
+	for _, e := range es {
+		ss = append(ss, e.Error())
+	}
+	return strings.Join(ss, "; ")
+}
+
+// locateDefinitionErrors returns definition errors located in the definition at path for the node
+// If err contains definition errors, which is the case when options can't be decoded, their paths are appended to
+// the new one
+func locateDefinitionErrors(path, node string, err error) (es DefinitionErrors) {
+	// Get errors
+	var is DefinitionErrors
+	var i *DefinitionError
+	if errors.As(err, &is) {
+		es = append(es, is...)
+	} else if errors.As(err, &i) {
+		es = append(es, i)
+	} else {
+		return DefinitionErrors{{Err: err, Node: node, Path: path}}
+	}
+
+	// Locate errors
+	for idx, e := range es {
+		r := *e
+		r.Node = node
+		r.Path = path
+		if e.Path != "" {
+			r.Path += "." + e.Path
+		}
+		es[idx] = &r
+	}
+	return
+}
+
+// suggest returns a suggestion based on the candidate closest to s, if any is close enough
+func suggest(s string, candidates []string) string {
+	// Get closest candidate
+	var closest string
+	min := -1
+	for _, c := range candidates {
+		if d := levenshtein(s, c); min < 0 || d < min {
+			closest = c
+			min = d
+		}
+	}
+
+	// Candidate is too far
+	if min < 0 || min > len(s)/3 {
+		return ""
+	}
+	return fmt.Sprintf("did you mean %q?", closest)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr := make([]int, len(rb)+1)
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// definitionOptionFields returns the json names of the fields of the struct dst points to, and whether they're
+// required, which is the case when they have the `definition:"required"` tag
+func definitionOptionFields(dst interface{}) (names []string, required map[string]bool) {
+	// Get struct type
+	required = make(map[string]bool)
+	t := reflect.TypeOf(dst)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	// Loop through fields
+	for i := 0; i < t.NumField(); i++ {
+		// Get name
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+
+		// Fields of embedded structs are promoted
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			ns, rs := definitionOptionFields(reflect.New(f.Type).Interface())
+			names = append(names, ns...)
+			for k, v := range rs {
+				required[k] = v
+			}
+			continue
+		}
+
+		// Invalid field
+		if name == "-" || f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		// Index
+		names = append(names, name)
+		if f.Tag.Get("definition") == "required" {
+			required[name] = true
+		}
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package astiencoder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeDefinitionOptions(t *testing.T) {
+	type options struct {
+		BitRate int    `json:"bit_rate"`
+		URL     string `json:"url" definition:"required"`
+	}
+
+	// Valid
+	var o options
+	err := DecodeDefinitionOptions(map[string]interface{}{"bit_rate": 1.0, "url": "u"}, &o)
+	assert.NoError(t, err)
+	assert.Equal(t, options{BitRate: 1, URL: "u"}, o)
+
+	// Invalid
+	err = DecodeDefinitionOptions(map[string]interface{}{"bitrate": 1.0}, &o)
+	var es DefinitionErrors
+	assert.True(t, errors.As(err, &es))
+	assert.Equal(t, DefinitionErrors{
+		{Err: errors.New("unknown option bitrate"), Path: "options.bitrate", Suggestion: `did you mean "bit_rate"?`},
+		{Err: errors.New("missing required option url"), Path: "options.url"},
+	}, es)
+	err = DecodeDefinitionOptions(map[string]interface{}{"bit_rate": "1", "url": "u"}, &o)
+	assert.EqualError(t, err, "astiencoder: options.bit_rate: invalid type string, expected int")
+
+	// Fields of embedded structs are promoted
+	var e struct {
+		options
+		Lazy bool `json:"lazy"`
+	}
+	err = DecodeDefinitionOptions(map[string]interface{}{"lazy": true, "url": "u"}, &e)
+	assert.NoError(t, err)
+	assert.Equal(t, "u", e.URL)
+	err = DecodeDefinitionOptions(map[string]interface{}{"lazy": true}, &e)
+	assert.EqualError(t, err, "astiencoder: options.url: missing required option url")
+}
+
+func TestBuildWorkflowDefinitionErrors(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("demuxer", NodeType{New: func(b NodeBuild) (Node, error) {
+		var o struct {
+			URL string `json:"url" definition:"required"`
+		}
+		if err := DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+			return nil, errors.New("wrapped: " + err.Error())
+		}
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+
+	// All validation errors are returned at once
+	_, err := BuildWorkflow(WorkflowDefinition{
+		Connections: []ConnectionDefinition{{From: "in", To: "out"}},
+		Nodes: []NodeDefinition{
+			{Name: "in", Type: "demuxr"},
+			{Name: "inn", Type: "muxer"},
+		},
+	}, BuildWorkflowOptions{Closer: astikit.NewCloser(), Types: ts})
+	var es DefinitionErrors
+	assert.True(t, errors.As(err, &es))
+	assert.Len(t, es, 3)
+	assert.Equal(t, "in", es[0].Node)
+	assert.Equal(t, "nodes[0].type", es[0].Path)
+	assert.Equal(t, `did you mean "demuxer"?`, es[0].Suggestion)
+	assert.Equal(t, "nodes[1].type", es[1].Path)
+	assert.Equal(t, "", es[1].Suggestion)
+	assert.Equal(t, "connections[0].to", es[2].Path)
+	assert.Equal(t, "", es[2].Suggestion)
+
+	// Errors of nodes are located
+	_, err = BuildWorkflow(WorkflowDefinition{Nodes: []NodeDefinition{
+		{Name: "a", Type: "demuxer", Options: map[string]interface{}{"url": "u"}},
+		{Name: "b", Type: "demuxer"},
+	}}, BuildWorkflowOptions{Closer: astikit.NewCloser(), EventHandler: NewEventHandler(), Types: ts})
+	assert.EqualError(t, err, "astiencoder: nodes[1]: wrapped: astiencoder: options.url: missing required option url")
+}
+
+func TestSuggest(t *testing.T) {
+	assert.Equal(t, `did you mean "demuxer"?`, suggest("demuxr", []string{"decoder", "demuxer", "muxer"}))
+	assert.Equal(t, "", suggest("filter", []string{"decoder", "demuxer", "muxer"}))
+	assert.Equal(t, "", suggest("b", []string{"a"}))
+	assert.Equal(t, "", suggest("a", nil))
+}
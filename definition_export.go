@@ -0,0 +1,94 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DefinitionExporter represents a node capable of exporting its definition, which is required to export the
+// workflows it belongs to
+type DefinitionExporter interface {
+	// ExportDefinition returns the type and the current options of the node. Its name and tags are filled by the
+	// workflow
+	ExportDefinition() (NodeDefinition, error)
+}
+
+// ConnectionDefinitionExporter represents a node capable of exporting the options of its connections
+// Connections of nodes not implementing it are exported without options
+type ConnectionDefinitionExporter interface {
+	// ExportConnectionDefinitions returns the options of each connection between the node and the child
+	ExportConnectionDefinitions(child Node) ([]map[string]interface{}, error)
+}
+
+// EncodeDefinitionOptions encodes src, which is usually a struct with json tags, into definition options
+func EncodeDefinitionOptions(src interface{}) (dst map[string]interface{}, err error) {
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(src); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling options failed: %w", err)
+		return
+	}
+
+	// Unmarshal
+	if err = json.Unmarshal(b, &dst); err != nil {
+		err = fmt.Errorf("astiencoder: unmarshaling options failed: %w", err)
+		return
+	}
+	return
+}
+
+// ExportDefinition exports the workflow's current nodes, including the ones added while it's running, and their
+// connections, so that it can be rebuilt later on with BuildWorkflow
+// Nodes and connections are sorted by name
+func (w *Workflow) ExportDefinition() (d WorkflowDefinition, err error) {
+	// Get nodes
+	ns := w.nodes()
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Metadata().Name < ns[j].Metadata().Name })
+
+	// Loop through nodes
+	d.Name = w.name
+	d.Version = DefinitionVersion
+	for _, n := range ns {
+		// Export node
+		e, ok := n.(DefinitionExporter)
+		if !ok {
+			err = fmt.Errorf("astiencoder: node %s can't export its definition", n.Metadata().Name)
+			return
+		}
+		var nd NodeDefinition
+		if nd, err = e.ExportDefinition(); err != nil {
+			err = fmt.Errorf("astiencoder: exporting definition of node %s failed: %w", n.Metadata().Name, err)
+			return
+		}
+		nd.Name = n.Metadata().Name
+		nd.Tags = n.Metadata().Tags
+		d.Nodes = append(d.Nodes, nd)
+
+		// Sort children
+		cs := n.Children()
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Metadata().Name < cs[j].Metadata().Name })
+
+		// Loop through children
+		for _, c := range cs {
+			// Get options
+			os := []map[string]interface{}{nil}
+			if e, ok := n.(ConnectionDefinitionExporter); ok {
+				if os, err = e.ExportConnectionDefinitions(c); err != nil {
+					err = fmt.Errorf("astiencoder: exporting definition of connection %s -> %s failed: %w", n.Metadata().Name, c.Metadata().Name, err)
+					return
+				}
+			}
+
+			// Export connections
+			for _, o := range os {
+				d.Connections = append(d.Connections, ConnectionDefinition{
+					From:    n.Metadata().Name,
+					Options: o,
+					To:      c.Metadata().Name,
+				})
+			}
+		}
+	}
+	return
+}
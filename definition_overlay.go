@@ -0,0 +1,259 @@
+package astiencoder
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeDefinitions composes a definition from a base and overlays, applied in order, without modifying them
+// Merge semantics are:
+//   - name and version are replaced when set in the overlay
+//   - variables are merged by name, overlay's declarations win
+//   - nodes are merged by name. Nodes unknown to the base are appended and must have a type. For the others, type
+//     and tags are replaced when set in the overlay and options are merged recursively: objects are merged key by key,
+//     a null value deletes the key and any other value, including arrays, replaces the base's one. A node with
+//     "remove": true is removed, as well as its connections
+//   - connections are grouped by "from" and "to": connections of the overlay replace all the base's connections
+//     between the same nodes. A connection with "remove": true removes them without adding any
+//
+// Errors are returned as DefinitionErrors whose paths are relative to the overlay
+func MergeDefinitions(base WorkflowDefinition, overlays ...WorkflowDefinition) (r WorkflowDefinition, err error) {
+	// Copy
+	if r, err = base.mapStrings(func(path, s string) (interface{}, error) { return s, nil }); err != nil {
+		return
+	}
+	r.Include = nil
+
+	// Loop through overlays
+	for _, o := range overlays {
+		if r, err = mergeDefinition(r, o); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func mergeDefinition(d, o WorkflowDefinition) (r WorkflowDefinition, err error) {
+	// Name and version
+	r = d
+	if o.Name != "" {
+		r.Name = o.Name
+	}
+	if o.Version != 0 {
+		r.Version = o.Version
+	}
+
+	// Variables
+	if len(o.Variables) > 0 {
+		r.Variables = make(map[string]VariableDefinition, len(d.Variables)+len(o.Variables))
+		for k, v := range d.Variables {
+			r.Variables[k] = v
+		}
+		for k, v := range o.Variables {
+			r.Variables[k] = v
+		}
+	}
+
+	// Index nodes
+	idxs := make(map[string]int)
+	for idx, n := range d.Nodes {
+		idxs[n.Name] = idx
+	}
+
+	// Loop through overlay nodes
+	var es DefinitionErrors
+	ns := append([]NodeDefinition{}, d.Nodes...)
+	removed := make(map[string]bool)
+	for idx, on := range o.Nodes {
+		// Get node
+		p := fmt.Sprintf("nodes[%d]", idx)
+		i, ok := idxs[on.Name]
+		if !ok {
+			if on.Remove {
+				es = append(es, &DefinitionError{Err: fmt.Errorf("node %s doesn't exist", on.Name), Node: on.Name, Path: p + ".remove"})
+			} else if on.Type == "" {
+				es = append(es, &DefinitionError{Err: fmt.Errorf("node %s doesn't exist and has no type", on.Name), Node: on.Name, Path: p + ".type"})
+			} else {
+				idxs[on.Name] = len(ns)
+				ns = append(ns, NodeDefinition{
+					Name:    on.Name,
+					Options: mergeDefinitionOptions(nil, on.Options),
+					Tags:    on.Tags,
+					Type:    on.Type,
+				})
+			}
+			continue
+		}
+
+		// Remove
+		if on.Remove {
+			removed[on.Name] = true
+			continue
+		}
+
+		// Merge
+		n := ns[i]
+		if on.Type != "" {
+			n.Type = on.Type
+		}
+		if on.Tags != nil {
+			n.Tags = on.Tags
+		}
+		n.Options = mergeDefinitionOptions(n.Options, on.Options)
+		ns[i] = n
+	}
+
+	// Errors
+	if len(es) > 0 {
+		err = es
+		return
+	}
+
+	// Nodes
+	r.Nodes = nil
+	for _, n := range ns {
+		if !removed[n.Name] {
+			r.Nodes = append(r.Nodes, n)
+		}
+	}
+
+	// Index overlay connections
+	pairs := make(map[string]bool)
+	for _, c := range o.Connections {
+		pairs[c.From+"\x00"+c.To] = true
+	}
+
+	// Connections
+	r.Connections = nil
+	for _, c := range d.Connections {
+		if !pairs[c.From+"\x00"+c.To] && !removed[c.From] && !removed[c.To] {
+			r.Connections = append(r.Connections, c)
+		}
+	}
+	for _, c := range o.Connections {
+		if !c.Remove {
+			c.Options = mergeDefinitionOptions(nil, c.Options)
+			r.Connections = append(r.Connections, c)
+		}
+	}
+	return
+}
+
+// mergeDefinitionOptions returns a copy of a merged with b, b's values winning
+func mergeDefinitionOptions(a, b map[string]interface{}) map[string]interface{} {
+	if a == nil && b == nil {
+		return nil
+	}
+	r := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		r[k] = v
+	}
+	for k, v := range b {
+		if v == nil {
+			delete(r, k)
+			continue
+		}
+		bm, ok := v.(map[string]interface{})
+		if !ok {
+			r[k] = v
+			continue
+		}
+		am, _ := r[k].(map[string]interface{})
+		r[k] = mergeDefinitionOptions(am, bm)
+	}
+	if len(r) == 0 {
+		r = nil
+	}
+	return r
+}
+
+// LoadWorkflowDefinitionFile decodes a JSON workflow definition file and composes it with the definitions it
+// includes
+// Included definitions are loaded recursively and merged in order, the including definition being merged last, which
+// allows organizing a fleet of similar channels as a base definition, an overlay per environment and a small file
+// per channel including both. Relative include paths are relative to the directory of the including file
+func LoadWorkflowDefinitionFile(path string) (WorkflowDefinition, error) {
+	return loadWorkflowDefinitionFile(path, nil)
+}
+
+func loadWorkflowDefinitionFile(path string, stack []string) (d WorkflowDefinition, err error) {
+	// Check cycle
+	var abs string
+	if abs, err = filepath.Abs(path); err != nil {
+		err = fmt.Errorf("astiencoder: getting absolute path of %s failed: %w", path, err)
+		return
+	}
+	for idx, p := range stack {
+		if p == abs {
+			err = fmt.Errorf("astiencoder: include cycle detected: %s", strings.Join(append(stack[idx:], abs), " -> "))
+			return
+		}
+	}
+	stack = append(stack, abs)
+
+	// Open file
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		err = fmt.Errorf("astiencoder: opening %s failed: %w", path, err)
+		return
+	}
+	defer f.Close()
+
+	// Load
+	var o WorkflowDefinition
+	if o, err = LoadWorkflowDefinition(f); err != nil {
+		err = fmt.Errorf("astiencoder: loading %s failed: %w", path, err)
+		return
+	}
+
+	// No includes
+	if len(o.Include) == 0 {
+		d = o
+		return
+	}
+
+	// Loop through includes
+	var ds []WorkflowDefinition
+	for _, i := range o.Include {
+		// Get path
+		if !filepath.IsAbs(i) {
+			i = filepath.Join(filepath.Dir(path), i)
+		}
+
+		// Load
+		var id WorkflowDefinition
+		if id, err = loadWorkflowDefinitionFile(i, stack); err != nil {
+			return
+		}
+		ds = append(ds, id)
+	}
+	ds = append(ds, o)
+
+	// Merge
+	if d, err = MergeDefinitions(ds[0], ds[1:]...); err != nil {
+		err = fmt.Errorf("astiencoder: merging %s failed: %w", path, err)
+		return
+	}
+	return
+}
+
+// validateDefinitionOverlay checks that the definition doesn't use overlay-only fields
+func validateDefinitionOverlay(d WorkflowDefinition) (es DefinitionErrors) {
+	if len(d.Include) > 0 {
+		es = append(es, newDefinitionError("include", errors.New("includes must be loaded with LoadWorkflowDefinitionFile")))
+	}
+	for idx, n := range d.Nodes {
+		if n.Remove {
+			es = append(es, &DefinitionError{Err: errors.New("remove can only be used in overlays"), Node: n.Name, Path: fmt.Sprintf("nodes[%d].remove", idx)})
+		}
+	}
+	for idx, c := range d.Connections {
+		if c.Remove {
+			es = append(es, newDefinitionError(fmt.Sprintf("connections[%d].remove", idx), errors.New("remove can only be used in overlays")))
+		}
+	}
+	return
+}
@@ -0,0 +1,117 @@
+package astiencoder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeDefinitions(t *testing.T) {
+	base := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "demuxer", Options: map[string]interface{}{"stream": 0.0}, To: "encoder"},
+			{From: "demuxer", Options: map[string]interface{}{"stream": 1.0}, To: "muxer"},
+			{From: "encoder", To: "muxer"},
+			{From: "demuxer", To: "probe"},
+		},
+		Name: "base",
+		Nodes: []NodeDefinition{
+			{Name: "demuxer", Options: map[string]interface{}{"url": "in.ts"}, Type: "demuxer"},
+			{Name: "encoder", Options: map[string]interface{}{"bit_rate": 1.0, "dict": map[string]interface{}{"preset": "fast", "tune": "film"}}, Tags: []string{"video"}, Type: "encoder"},
+			{Name: "muxer", Options: map[string]interface{}{"url": "out.ts"}, Type: "muxer"},
+			{Name: "probe", Type: "probe"},
+		},
+		Variables: map[string]VariableDefinition{
+			"host": {Default: "dev", Type: VariableTypeString},
+			"port": {Default: 1.0, Type: VariableTypeInt},
+		},
+		Version: DefinitionVersion,
+	}
+	env := WorkflowDefinition{
+		Nodes: []NodeDefinition{
+			{Name: "encoder", Options: map[string]interface{}{"dict": map[string]interface{}{"preset": "slow", "tune": nil}}},
+			{Name: "probe", Remove: true},
+		},
+		Variables: map[string]VariableDefinition{"host": {Default: "prod", Type: VariableTypeString}},
+	}
+	channel := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "demuxer", Options: map[string]interface{}{"stream": 2.0}, To: "muxer"},
+			{From: "encoder", To: "muxer", Remove: true},
+			{From: "encoder", To: "thumbnailer"},
+		},
+		Name: "channel",
+		Nodes: []NodeDefinition{
+			{Name: "encoder", Options: map[string]interface{}{"bit_rate": 2.0}},
+			{Name: "thumbnailer", Type: "thumbnailer"},
+		},
+	}
+	d, err := MergeDefinitions(base, env, channel)
+	assert.NoError(t, err)
+	assert.Equal(t, WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "demuxer", Options: map[string]interface{}{"stream": 0.0}, To: "encoder"},
+			{From: "demuxer", Options: map[string]interface{}{"stream": 2.0}, To: "muxer"},
+			{From: "encoder", To: "thumbnailer"},
+		},
+		Name: "channel",
+		Nodes: []NodeDefinition{
+			{Name: "demuxer", Options: map[string]interface{}{"url": "in.ts"}, Type: "demuxer"},
+			{Name: "encoder", Options: map[string]interface{}{"bit_rate": 2.0, "dict": map[string]interface{}{"preset": "slow"}}, Tags: []string{"video"}, Type: "encoder"},
+			{Name: "muxer", Options: map[string]interface{}{"url": "out.ts"}, Type: "muxer"},
+			{Name: "thumbnailer", Type: "thumbnailer"},
+		},
+		Variables: map[string]VariableDefinition{
+			"host": {Default: "prod", Type: VariableTypeString},
+			"port": {Default: 1.0, Type: VariableTypeInt},
+		},
+		Version: DefinitionVersion,
+	}, d)
+	assert.Equal(t, "fast", base.Nodes[1].Options["dict"].(map[string]interface{})["preset"])
+	assert.Equal(t, "dev", base.Variables["host"].Default)
+
+	// Errors
+	_, err = MergeDefinitions(base, WorkflowDefinition{Nodes: []NodeDefinition{{Name: "unknown", Remove: true}, {Name: "new"}}})
+	assert.EqualError(t, err, "astiencoder: nodes[0].remove: node unknown doesn't exist; astiencoder: nodes[1].type: node new doesn't exist and has no type")
+}
+
+func TestLoadWorkflowDefinitionFile(t *testing.T) {
+	// Create files
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for p, c := range map[string]string{
+		"base.json":           `{"name":"base","nodes":[{"name":"n","options":{"a":1,"b":2},"type":"t"}],"version":1}`,
+		"env/prod.json":       `{"include":["../base.json"],"nodes":[{"name":"n","options":{"b":3}}]}`,
+		"channels/c1.json":    `{"include":["../env/prod.json"],"name":"c1","nodes":[{"name":"n","options":{"a":null}}]}`,
+		"cycle/a.json":        `{"include":["b.json"]}`,
+		"cycle/b.json":        `{"include":["a.json"]}`,
+		"channels/error.json": `{"include":["../base.json"],"nodes":[{"name":"m"}]}`,
+	} {
+		p = filepath.Join(dir, p)
+		err = os.MkdirAll(filepath.Dir(p), 0755)
+		assert.NoError(t, err)
+		err = ioutil.WriteFile(p, []byte(c), 0600)
+		assert.NoError(t, err)
+	}
+
+	// Load
+	d, err := LoadWorkflowDefinitionFile(filepath.Join(dir, "channels", "c1.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, WorkflowDefinition{
+		Name:    "c1",
+		Nodes:   []NodeDefinition{{Name: "n", Options: map[string]interface{}{"b": 3.0}, Type: "t"}},
+		Version: DefinitionVersion,
+	}, d)
+
+	// Errors
+	_, err = LoadWorkflowDefinitionFile(filepath.Join(dir, "cycle", "a.json"))
+	assert.EqualError(t, err, "astiencoder: include cycle detected: "+filepath.Join(dir, "cycle", "a.json")+" -> "+filepath.Join(dir, "cycle", "b.json")+" -> "+filepath.Join(dir, "cycle", "a.json"))
+	_, err = LoadWorkflowDefinitionFile(filepath.Join(dir, "channels", "error.json"))
+	assert.EqualError(t, err, "astiencoder: merging "+filepath.Join(dir, "channels", "error.json")+" failed: astiencoder: nodes[0].type: node m doesn't exist and has no type")
+	_, err = BuildWorkflow(WorkflowDefinition{Include: []string{"base.json"}, Version: DefinitionVersion}, BuildWorkflowOptions{})
+	assert.EqualError(t, err, "astiencoder: include: includes must be loaded with LoadWorkflowDefinitionFile")
+}
@@ -0,0 +1,261 @@
+package astiencoder
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Variable types
+const (
+	VariableTypeBool   = "bool"
+	VariableTypeFloat  = "float"
+	VariableTypeInt    = "int"
+	VariableTypeString = "string"
+)
+
+// VariableDefinition represents the declaration of a variable that can be used in the strings of a workflow
+// definition with {{.<name>}}
+// A string only made of a variable, e.g. "{{.bitrate}}", is replaced with the typed value of the variable, which
+// allows using variables in options that are not strings
+type VariableDefinition struct {
+	// Used when no value is provided. If nil, a value must be provided
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	// Possible values are "bool", "float", "int" and "string"
+	Type string `json:"type" yaml:"type"`
+}
+
+var definitionVariableRegexp = regexp.MustCompile(`^\{\{\s*\.([a-zA-Z0-9_]+)\s*\}\}$`)
+
+// Resolve returns a copy of the definition whose variables have been replaced with the values, after checking that
+// their types match the declarations
+// Errors are returned as DefinitionErrors
+func (d WorkflowDefinition) Resolve(values map[string]interface{}) (r WorkflowDefinition, err error) {
+	// Get declared variables
+	var names []string
+	for k := range d.Variables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	// Check values
+	var es DefinitionErrors
+	var ks []string
+	for k := range values {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	for _, k := range ks {
+		if _, ok := d.Variables[k]; !ok {
+			e := newDefinitionError("variables", fmt.Errorf("variable %s is not declared", k))
+			e.Suggestion = suggest(k, names)
+			es = append(es, e)
+		}
+	}
+
+	// Loop through declared variables
+	vs := make(map[string]interface{})
+	for _, k := range names {
+		// Get value
+		vd := d.Variables[k]
+		v, ok := values[k]
+		if !ok {
+			if vd.Default == nil {
+				es = append(es, newDefinitionError("variables."+k, fmt.Errorf("variable %s has no value", k)))
+				continue
+			}
+			v = vd.Default
+		}
+
+		// Check type
+		var errType error
+		if vs[k], errType = checkVariableType(vd.Type, v); errType != nil {
+			es = append(es, newDefinitionError("variables."+k, errType))
+		}
+	}
+	if len(es) > 0 {
+		err = es
+		return
+	}
+
+	// Resolve
+	if r, err = d.mapStrings(func(path, s string) (interface{}, error) { return resolveDefinitionString(s, vs) }); err != nil {
+		return
+	}
+	r.Variables = nil
+	return
+}
+
+func checkVariableType(typ string, v interface{}) (interface{}, error) {
+	switch typ {
+	case VariableTypeBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case VariableTypeFloat:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case float32:
+			return float64(n), nil
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		}
+	case VariableTypeInt:
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		case float64:
+			// Numbers decoded from JSON are float64
+			if n == math.Trunc(n) {
+				return int(n), nil
+			}
+		}
+	case VariableTypeString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	default:
+		return nil, fmt.Errorf("invalid type %s", typ)
+	}
+	return nil, fmt.Errorf("%v is not of type %s", v, typ)
+}
+
+func resolveDefinitionString(s string, vs map[string]interface{}) (interface{}, error) {
+	// No variable
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+
+	// String is only made of a variable
+	if m := definitionVariableRegexp.FindStringSubmatch(s); len(m) > 1 {
+		v, ok := vs[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("variable %s is not declared", m[1])
+		}
+		return v, nil
+	}
+
+	// Parse template
+	t, err := template.New("").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s failed: %w", s, err)
+	}
+
+	// Execute template
+	buf := &bytes.Buffer{}
+	if err = t.Execute(buf, vs); err != nil {
+		return nil, fmt.Errorf("executing template %s failed: %w", s, err)
+	}
+	return buf.String(), nil
+}
+
+// mapStrings returns a copy of the definition where fn has been applied to its names, tags and string options
+// The path of the string in the definition, e.g. "nodes[0].options.url", is provided to fn and used to locate its
+// errors, which are returned as DefinitionErrors
+func (d WorkflowDefinition) mapStrings(fn func(path, s string) (interface{}, error)) (r WorkflowDefinition, err error) {
+	// Name
+	r = d
+	if r.Name, err = mapDefinitionString("name", d.Name, fn); err != nil {
+		return
+	}
+
+	// Nodes
+	r.Nodes = nil
+	for i, n := range d.Nodes {
+		p := fmt.Sprintf("nodes[%d]", i)
+		if n.Name, err = mapDefinitionString(p+".name", n.Name, fn); err != nil {
+			return
+		}
+		if n.Tags != nil {
+			ts := n.Tags
+			n.Tags = nil
+			for j, t := range ts {
+				var v string
+				if v, err = mapDefinitionString(fmt.Sprintf("%s.tags[%d]", p, j), t, fn); err != nil {
+					return
+				}
+				n.Tags = append(n.Tags, v)
+			}
+		}
+		if n.Options, err = mapDefinitionOptions(p+".options", n.Options, fn); err != nil {
+			return
+		}
+		r.Nodes = append(r.Nodes, n)
+	}
+
+	// Connections
+	r.Connections = nil
+	for i, c := range d.Connections {
+		p := fmt.Sprintf("connections[%d]", i)
+		if c.From, err = mapDefinitionString(p+".from", c.From, fn); err != nil {
+			return
+		}
+		if c.To, err = mapDefinitionString(p+".to", c.To, fn); err != nil {
+			return
+		}
+		if c.Options, err = mapDefinitionOptions(p+".options", c.Options, fn); err != nil {
+			return
+		}
+		r.Connections = append(r.Connections, c)
+	}
+	return
+}
+
+func mapDefinitionString(path, s string, fn func(path, s string) (interface{}, error)) (string, error) {
+	v, err := fn(path, s)
+	if err != nil {
+		return "", DefinitionErrors{newDefinitionError(path, err)}
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+func mapDefinitionOptions(path string, o map[string]interface{}, fn func(path, s string) (interface{}, error)) (map[string]interface{}, error) {
+	if o == nil {
+		return nil, nil
+	}
+	v, err := mapDefinitionValue(path, o, fn)
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]interface{}), nil
+}
+
+func mapDefinitionValue(path string, v interface{}, fn func(path, s string) (interface{}, error)) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		r, err := fn(path, t)
+		if err != nil {
+			return nil, DefinitionErrors{newDefinitionError(path, err)}
+		}
+		return r, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			var err error
+			if m[k], err = mapDefinitionValue(path+"."+k, v, fn); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, 0, len(t))
+		for i, v := range t {
+			r, err := mapDefinitionValue(fmt.Sprintf("%s[%d]", path, i), v, fn)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, r)
+		}
+		return s, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,70 @@
+package astiencoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowDefinitionResolve(t *testing.T) {
+	d := WorkflowDefinition{
+		Connections: []ConnectionDefinition{{From: "{{.channel}}_in", To: "{{.channel}}_out"}},
+		Name:        "{{.channel}}",
+		Nodes: []NodeDefinition{
+			{Name: "{{.channel}}_in", Options: map[string]interface{}{
+				"loop": "{{.loop}}",
+				"url":  "rtmp://host/{{ .channel }}",
+			}, Tags: []string{"{{.channel}}"}, Type: "demuxer"},
+			{Name: "{{.channel}}_out", Options: map[string]interface{}{
+				"bit_rate": "{{ .bitrate }}",
+				"list":     []interface{}{"{{.ratio}}", 1.0},
+			}, Type: "muxer"},
+		},
+		Variables: map[string]VariableDefinition{
+			"bitrate": {Type: VariableTypeInt},
+			"channel": {Type: VariableTypeString},
+			"loop":    {Default: false, Type: VariableTypeBool},
+			"ratio":   {Default: 1.5, Type: VariableTypeFloat},
+		},
+	}
+
+	// Variables are resolved with their typed values
+	r, err := d.Resolve(map[string]interface{}{"bitrate": 2e6, "channel": "c1"})
+	assert.NoError(t, err)
+	assert.Equal(t, WorkflowDefinition{
+		Connections: []ConnectionDefinition{{From: "c1_in", To: "c1_out"}},
+		Name:        "c1",
+		Nodes: []NodeDefinition{
+			{Name: "c1_in", Options: map[string]interface{}{
+				"loop": false,
+				"url":  "rtmp://host/c1",
+			}, Tags: []string{"c1"}, Type: "demuxer"},
+			{Name: "c1_out", Options: map[string]interface{}{
+				"bit_rate": 2000000,
+				"list":     []interface{}{1.5, 1.0},
+			}, Type: "muxer"},
+		},
+	}, r)
+
+	// Template is not modified
+	assert.Equal(t, "{{.loop}}", d.Nodes[0].Options["loop"])
+
+	// Invalid values
+	for _, c := range []struct {
+		err    string
+		values map[string]interface{}
+	}{
+		{err: "astiencoder: variables.channel: variable channel has no value", values: map[string]interface{}{"bitrate": 1}},
+		{err: "astiencoder: variables: variable unknown is not declared", values: map[string]interface{}{"bitrate": 1, "channel": "c1", "unknown": 1}},
+		{err: "astiencoder: variables.bitrate: 1.5 is not of type int", values: map[string]interface{}{"bitrate": 1.5, "channel": "c1"}},
+		{err: "astiencoder: variables.channel: 1 is not of type string", values: map[string]interface{}{"bitrate": 1, "channel": 1}},
+	} {
+		_, err = d.Resolve(c.values)
+		assert.EqualError(t, err, c.err)
+	}
+
+	// Undeclared variable
+	d.Nodes[0].Options["url"] = "{{.unknown}}"
+	_, err = d.Resolve(map[string]interface{}{"bitrate": 1, "channel": "c1"})
+	assert.EqualError(t, err, "astiencoder: nodes[0].options.url: variable unknown is not declared")
+}
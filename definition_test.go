@@ -0,0 +1,168 @@
+package astiencoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedDefinitionNode struct {
+	*mockedStatsNode
+	value int
+}
+
+func (n *mockedDefinitionNode) ExportDefinition() (NodeDefinition, error) {
+	o, err := EncodeDefinitionOptions(map[string]interface{}{"value": n.value})
+	return NodeDefinition{Options: o, Type: "t"}, err
+}
+
+func (n *mockedDefinitionNode) ExportConnectionDefinitions(child Node) ([]map[string]interface{}, error) {
+	return []map[string]interface{}{{"stream": 0.0}, {"stream": 1.0}}, nil
+}
+
+func TestWorkflowExportDefinition(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{
+		Connect: func(parent, child Node, options map[string]interface{}) error {
+			ConnectNodes(parent, child)
+			return nil
+		},
+		New: func(b NodeBuild) (Node, error) {
+			var o struct {
+				Value int `json:"value"`
+			}
+			if err := DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+				return nil, err
+			}
+			n := &mockedDefinitionNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, eh), value: o.Value}
+			n.o.Metadata.Tags = b.Node.Metadata.Tags
+			return n, nil
+		},
+	})
+
+	// Build
+	d := WorkflowDefinition{
+		Connections: []ConnectionDefinition{
+			{From: "a", Options: map[string]interface{}{"stream": 0.0}, To: "b"},
+			{From: "a", Options: map[string]interface{}{"stream": 1.0}, To: "b"},
+		},
+		Name: "w",
+		Nodes: []NodeDefinition{
+			{Name: "a", Options: map[string]interface{}{"value": 1.0}, Tags: []string{"tag"}, Type: "t"},
+			{Name: "b", Options: map[string]interface{}{"value": 2.0}, Type: "t"},
+		},
+		Version: DefinitionVersion,
+	}
+	w, err := BuildWorkflow(d, BuildWorkflowOptions{Closer: astikit.NewCloser(), EventHandler: eh, Types: ts})
+	assert.NoError(t, err)
+
+	// Workflow is exported the way it's been defined
+	e, err := w.ExportDefinition()
+	assert.NoError(t, err)
+	assert.Equal(t, d, e)
+
+	// Nodes added at runtime and current options are exported as well
+	w.Children()[0].(*mockedDefinitionNode).value = 3
+	n := &mockedDefinitionNode{mockedStatsNode: newMockedStatsNode("c", eh), value: 4}
+	ConnectNodes(w.Children()[0].Children()[0], n)
+	e, err = w.ExportDefinition()
+	assert.NoError(t, err)
+	assert.Len(t, e.Nodes, 3)
+	assert.Equal(t, map[string]interface{}{"value": 3.0}, e.Nodes[0].Options)
+	assert.Equal(t, NodeDefinition{Name: "c", Options: map[string]interface{}{"value": 4.0}, Type: "t"}, e.Nodes[2])
+	assert.Len(t, e.Connections, 4)
+	assert.Equal(t, ConnectionDefinition{From: "b", Options: map[string]interface{}{"stream": 1.0}, To: "c"}, e.Connections[3])
+
+	// Nodes must be able to export their definition
+	ConnectNodes(n, newMockedStatsNode("d", eh))
+	_, err = w.ExportDefinition()
+	assert.EqualError(t, err, "astiencoder: node d can't export its definition")
+}
+
+func TestBuildWorkflow(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	var bs []NodeBuild
+	ts.Register("t", NodeType{
+		Connect: func(parent, child Node, options map[string]interface{}) error {
+			ConnectNodes(parent, child)
+			return nil
+		},
+		New: func(b NodeBuild) (Node, error) {
+			var o struct {
+				Value int `json:"value"`
+			}
+			if err := DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+				return nil, err
+			}
+			bs = append(bs, b)
+			n := newMockedStatsNode(b.Node.Metadata.Name, eh)
+			n.o.Metadata.Tags = b.Node.Metadata.Tags
+			return n, nil
+		},
+	})
+	ts.Register("leaf", NodeType{New: func(b NodeBuild) (Node, error) { return newMockedStatsNode(b.Node.Metadata.Name, eh), nil }})
+
+	// Load definition
+	d, err := LoadWorkflowDefinition(strings.NewReader(`{
+		"name": "w",
+		"nodes": [
+			{"name": "c", "type": "t", "options": {"value": 2}},
+			{"name": "b", "type": "t", "tags": ["tag"]},
+			{"name": "a", "type": "t"}
+		],
+		"connections": [
+			{"from": "b", "to": "c", "options": {"stream": 1}},
+			{"from": "a", "to": "b"}
+		]
+	}`))
+	assert.NoError(t, err)
+
+	// Build
+	o := BuildWorkflowOptions{Closer: astikit.NewCloser(), EventHandler: eh, Types: ts}
+	w, err := BuildWorkflow(d, o)
+	assert.NoError(t, err)
+	assert.Equal(t, "w", w.Name())
+	assert.Len(t, bs, 3)
+	assert.Equal(t, "a", bs[0].Definition.Name)
+	assert.Equal(t, "b", bs[1].Definition.Name)
+	assert.Equal(t, []string{"tag"}, bs[1].Node.Metadata.Tags)
+	assert.Equal(t, "c", bs[2].Definition.Name)
+	assert.Equal(t, map[string]interface{}{"stream": 1.0}, bs[2].Parents[0].Options)
+	assert.Equal(t, bs[1].Definition.Name, bs[2].Parents[0].Node.Metadata().Name)
+	cs := w.Children()
+	assert.Len(t, cs, 1)
+	assert.Equal(t, "a", cs[0].Metadata().Name)
+	assert.Equal(t, "b", cs[0].Children()[0].Metadata().Name)
+
+	// Invalid definitions
+	for _, c := range []struct {
+		d   WorkflowDefinition
+		err string
+	}{
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Type: "t"}}}, err: "astiencoder: nodes[0].name: missing name"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "t"}, {Name: "a", Type: "t"}}}, err: "astiencoder: nodes[1].name: node a is defined more than once"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "unknown"}}}, err: "astiencoder: nodes[0].type: unknown node type unknown"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "leaf"}, {Name: "b"}}}, err: "astiencoder: nodes[1].type: missing type"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "t"}}, Connections: []ConnectionDefinition{{From: "a", To: "b"}}}, err: "astiencoder: connections[0].to: unknown node b"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "t"}, {Name: "b", Type: "t"}}, Connections: []ConnectionDefinition{{From: "a", To: "b"}, {From: "b", To: "a"}}}, err: "astiencoder: connections: nodes a, b are part of a cycle"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "t", Options: map[string]interface{}{"unknown": 1}}}}, err: "astiencoder: nodes[0].options.unknown: unknown option unknown"},
+		{d: WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "leaf"}, {Name: "b", Type: "t"}}, Connections: []ConnectionDefinition{{From: "a", To: "b"}}}, err: "astiencoder: connections[0].from: node a of type leaf can't have children"},
+	} {
+		_, err = BuildWorkflow(c.d, o)
+		assert.EqualError(t, err, c.err)
+	}
+
+	// Validate
+	_, err = ValidateWorkflowDefinition(d, o)
+	assert.NoError(t, err)
+	_, err = ValidateWorkflowDefinition(WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "t"}, {Name: "b", Type: "t"}}, Connections: []ConnectionDefinition{{From: "a", To: "b"}, {From: "b", To: "a"}}}, o)
+	assert.EqualError(t, err, "astiencoder: connections: nodes a, b are part of a cycle")
+	_, err = ValidateWorkflowDefinition(WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Type: "unknown"}}}, o)
+	assert.EqualError(t, err, "astiencoder: nodes[0].type: unknown node type unknown")
+}
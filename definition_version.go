@@ -0,0 +1,82 @@
+package astiencoder
+
+import (
+	"fmt"
+)
+
+// DefinitionVersion is the current version of the workflow definition schema
+// Definitions with an older version are migrated when the workflow is built
+const DefinitionVersion = 1
+
+// DefinitionWarning represents a deprecation warning raised while migrating a definition
+// It is the payload of the EventNameDefinitionDeprecated event whose target is the workflow built from the definition
+type DefinitionWarning struct {
+	Message string
+	// Name of the offending node, if any
+	Node string
+	// Path of the deprecated value in the definition
+	Path string
+}
+
+// NodeMigration migrates the options of a node from version from to version from+1 and returns deprecation
+// warnings. It must not modify options in place
+type NodeMigration func(from int, options map[string]interface{}) (map[string]interface{}, []string, error)
+
+// definitionMigrations migrate the definition schema, indexed by the version they migrate from
+var definitionMigrations = map[int]func(d *WorkflowDefinition) []DefinitionWarning{
+	// Definitions written before versions were introduced have no version
+	0: func(d *WorkflowDefinition) []DefinitionWarning {
+		return []DefinitionWarning{{
+			Message: fmt.Sprintf("definitions without version are deprecated, set version to %d", DefinitionVersion),
+			Path:    "version",
+		}}
+	},
+}
+
+// Migrate returns a copy of the definition migrated to the current version as well as the deprecation warnings
+// Options of nodes whose type has a migration are migrated at each version
+func (d WorkflowDefinition) Migrate(ts *NodeTypes) (r WorkflowDefinition, ws []DefinitionWarning, err error) {
+	// Invalid version
+	if d.Version < 0 || d.Version > DefinitionVersion {
+		err = DefinitionErrors{newDefinitionError("version", fmt.Errorf("version %d is not supported, max version is %d", d.Version, DefinitionVersion))}
+		return
+	}
+
+	// Copy
+	if r, err = d.mapStrings(func(path, s string) (interface{}, error) { return s, nil }); err != nil {
+		return
+	}
+
+	// Loop through versions
+	for v := d.Version; v < DefinitionVersion; v++ {
+		// Migrate schema
+		if fn, ok := definitionMigrations[v]; ok {
+			ws = append(ws, fn(&r)...)
+		}
+
+		// Loop through nodes
+		for idx, n := range r.Nodes {
+			// Get migration
+			t, ok := ts.get(n.Type)
+			if !ok || t.Migrate == nil {
+				continue
+			}
+
+			// Migrate options
+			var nws []string
+			if r.Nodes[idx].Options, nws, err = t.Migrate(v, n.Options); err != nil {
+				err = locateDefinitionErrors(fmt.Sprintf("nodes[%d].options", idx), n.Name, fmt.Errorf("migrating from version %d failed: %w", v, err))
+				return
+			}
+			for _, w := range nws {
+				ws = append(ws, DefinitionWarning{
+					Message: w,
+					Node:    n.Name,
+					Path:    fmt.Sprintf("nodes[%d].options", idx),
+				})
+			}
+		}
+		r.Version = v + 1
+	}
+	return
+}
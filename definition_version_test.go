@@ -0,0 +1,77 @@
+package astiencoder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowDefinitionMigrate(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	var migrated []int
+	ts.Register("t", NodeType{
+		Migrate: func(from int, options map[string]interface{}) (map[string]interface{}, []string, error) {
+			migrated = append(migrated, from)
+			if _, ok := options["fail"]; ok {
+				return nil, nil, errors.New("test")
+			}
+			o := make(map[string]interface{})
+			for k, v := range options {
+				o[k] = v
+			}
+			if v, ok := o["old"]; ok {
+				delete(o, "old")
+				o["new"] = v
+				return o, []string{"old is deprecated, use new"}, nil
+			}
+			return o, nil, nil
+		},
+		New: func(b NodeBuild) (Node, error) { return newMockedStatsNode(b.Node.Metadata.Name, eh), nil },
+	})
+
+	// Migrate
+	d := WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Options: map[string]interface{}{"old": 1.0}, Type: "t"}}}
+	r, ws, err := d.Migrate(ts)
+	assert.NoError(t, err)
+	assert.Equal(t, DefinitionVersion, r.Version)
+	assert.Equal(t, map[string]interface{}{"new": 1.0}, r.Nodes[0].Options)
+	assert.Equal(t, map[string]interface{}{"old": 1.0}, d.Nodes[0].Options)
+	assert.Equal(t, []int{0}, migrated)
+	assert.Equal(t, []DefinitionWarning{
+		{Message: "definitions without version are deprecated, set version to 1", Path: "version"},
+		{Message: "old is deprecated, use new", Node: "a", Path: "nodes[0].options"},
+	}, ws)
+
+	// Current version is not migrated
+	migrated = []int{}
+	d.Version = DefinitionVersion
+	_, ws, err = d.Migrate(ts)
+	assert.NoError(t, err)
+	assert.Empty(t, ws)
+	assert.Empty(t, migrated)
+
+	// Errors
+	d.Version = DefinitionVersion + 1
+	_, _, err = d.Migrate(ts)
+	assert.EqualError(t, err, "astiencoder: version: version 2 is not supported, max version is 1")
+	d = WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Options: map[string]interface{}{"fail": true}, Type: "t"}}}
+	_, _, err = d.Migrate(ts)
+	assert.EqualError(t, err, "astiencoder: nodes[0].options: migrating from version 0 failed: test")
+
+	// Warnings are emitted when building the workflow
+	var es []Event
+	eh.AddForEventName(EventNameDefinitionDeprecated, func(e Event) bool {
+		es = append(es, e)
+		return false
+	})
+	d = WorkflowDefinition{Nodes: []NodeDefinition{{Name: "a", Options: map[string]interface{}{"old": 1.0}, Type: "t"}}}
+	w, err := BuildWorkflow(d, BuildWorkflowOptions{Closer: astikit.NewCloser(), EventHandler: eh, Types: ts})
+	assert.NoError(t, err)
+	assert.Len(t, es, 2)
+	assert.Equal(t, w, es[1].Target)
+	assert.Equal(t, "a", es[1].Payload.(DefinitionWarning).Node)
+}
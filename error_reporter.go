@@ -0,0 +1,272 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrorReport represents the report of an error event
+type ErrorReport struct {
+	Error string           `json:"error"`
+	Node  *ErrorReportNode `json:"node,omitempty"`
+	// Stack of the goroutine that emitted the error
+	Stack    string    `json:"stack"`
+	Time     time.Time `json:"time"`
+	Workflow string    `json:"workflow,omitempty"`
+}
+
+// ErrorReportNode represents the node an error report is about
+type ErrorReportNode struct {
+	Description string   `json:"description,omitempty"`
+	Label       string   `json:"label,omitempty"`
+	Name        string   `json:"name"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// ErrorReportSender represents an object capable of forwarding error reports to a collector (e.g. Sentry)
+type ErrorReportSender interface {
+	SendErrorReports(ctx context.Context, rs []ErrorReport) error
+}
+
+// ErrorReportSenderFunc is an adapter to allow using a function as an ErrorReportSender
+type ErrorReportSenderFunc func(ctx context.Context, rs []ErrorReport) error
+
+// SendErrorReports implements the ErrorReportSender interface
+func (f ErrorReportSenderFunc) SendErrorReports(ctx context.Context, rs []ErrorReport) error {
+	return f(ctx, rs)
+}
+
+// ErrorReporter represents an object capable of batching error events and forwarding them to a sender
+type ErrorReporter struct {
+	full    chan struct{}
+	l       Logger
+	m       *sync.Mutex
+	o       ErrorReporterOptions
+	pending []ErrorReport
+}
+
+// ErrorReporterOptions represents error reporter options
+type ErrorReporterOptions struct {
+	// Max number of reports sent at once. Default is 50
+	BatchSize int
+	// Max duration during which a report waits before being sent. Default is 5s
+	FlushPeriod time.Duration
+	Logger      Logger
+	// Max number of reports waiting to be sent. Once reached, new reports are dropped. Default is 1000
+	MaxPending int
+	Sender     ErrorReportSender
+}
+
+// NewErrorReporter creates a new error reporter fed by the error events of the event handler
+// Reports are only sent once Start has been called
+func NewErrorReporter(o ErrorReporterOptions, eh *EventHandler) (r *ErrorReporter) {
+	// Default options
+	if o.BatchSize <= 0 {
+		o.BatchSize = 50
+	}
+	if o.FlushPeriod <= 0 {
+		o.FlushPeriod = 5 * time.Second
+	}
+	if o.MaxPending <= 0 {
+		o.MaxPending = 1000
+	}
+
+	// Create reporter
+	r = &ErrorReporter{
+		full: make(chan struct{}, 1),
+		l:    logger(o.Logger),
+		m:    &sync.Mutex{},
+		o:    o,
+	}
+
+	// Handle events
+	// The callback is executed in the goroutine emitting the event, which makes its stack meaningful
+	eh.AddForEventName(EventNameError, func(e Event) bool {
+		r.handleEvent(e)
+		return false
+	})
+	return
+}
+
+func (r *ErrorReporter) handleEvent(e Event) {
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Too many pending reports
+	if len(r.pending) >= r.o.MaxPending {
+		return
+	}
+
+	// Create report
+	rp := ErrorReport{
+		Stack: string(debug.Stack()),
+		Time:  time.Now(),
+	}
+	if err, ok := e.Payload.(error); ok {
+		rp.Error = err.Error()
+	} else {
+		rp.Error = fmt.Sprintf("%v", e.Payload)
+	}
+
+	// Add context
+	switch t := e.Target.(type) {
+	case *Workflow:
+		rp.Workflow = t.Name()
+	case Node:
+		md := t.Metadata()
+		rp.Node = &ErrorReportNode{
+			Description: md.Description,
+			Label:       md.Label,
+			Name:        md.Name,
+			Tags:        md.Tags,
+		}
+		rp.Workflow = nodeWorkflowName(t)
+	}
+
+	// Append
+	r.pending = append(r.pending, rp)
+
+	// Batch is full
+	if len(r.pending) >= r.o.BatchSize {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start sends pending reports periodically or as soon as a batch is full, until the context is done
+// Remaining reports are sent before returning
+func (r *ErrorReporter) Start(ctx context.Context) {
+	// Make sure to send remaining reports
+	defer r.flush(context.Background())
+
+	// Create ticker
+	t := time.NewTicker(r.o.FlushPeriod)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.full:
+		case <-t.C:
+		}
+
+		// Context may have been cancelled in the meantime, in which case reports are sent before returning
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Flush
+		r.flush(ctx)
+	}
+}
+
+// Shutdown sends the pending reports within the context's deadline. Errors are logged, like when reports are sent by
+// Start
+func (r *ErrorReporter) Shutdown(ctx context.Context) error {
+	r.flush(ctx)
+	return nil
+}
+
+func (r *ErrorReporter) flush(ctx context.Context) {
+	// Get pending reports
+	r.m.Lock()
+	rs := r.pending
+	r.pending = nil
+	r.m.Unlock()
+
+	// No sender
+	if r.o.Sender == nil {
+		return
+	}
+
+	// Loop through batches
+	for len(rs) > 0 {
+		// Get batch
+		n := r.o.BatchSize
+		if n > len(rs) {
+			n = len(rs)
+		}
+
+		// Send
+		if err := r.o.Sender.SendErrorReports(ctx, rs[:n]); err != nil {
+			r.l.Error("astiencoder: sending error reports failed", LogField{Key: LogFieldError, Value: err})
+		}
+		rs = rs[n:]
+	}
+}
+
+// HTTPErrorReportSender represents an object capable of posting error reports in JSON to a generic HTTP collector
+type HTTPErrorReportSender struct {
+	c *http.Client
+	o HTTPErrorReportSenderOptions
+}
+
+// HTTPErrorReportSenderOptions represents HTTP error report sender options
+type HTTPErrorReportSenderOptions struct {
+	// Default client has a 10s timeout
+	Client  *http.Client
+	Headers http.Header
+	URL     string
+}
+
+// NewHTTPErrorReportSender creates a new HTTP error report sender
+func NewHTTPErrorReportSender(o HTTPErrorReportSenderOptions) *HTTPErrorReportSender {
+	s := &HTTPErrorReportSender{
+		c: o.Client,
+		o: o,
+	}
+	if s.c == nil {
+		s.c = &http.Client{Timeout: 10 * time.Second}
+	}
+	return s
+}
+
+// SendErrorReports implements the ErrorReportSender interface
+// Reports are posted as a JSON array
+func (s *HTTPErrorReportSender) SendErrorReports(ctx context.Context, rs []ErrorReport) (err error) {
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(rs); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling failed: %w", err)
+		return
+	}
+
+	// Create request
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.o.URL, bytes.NewReader(b)); err != nil {
+		err = fmt.Errorf("astiencoder: creating request failed: %w", err)
+		return
+	}
+	for k, vs := range s.o.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send
+	var resp *http.Response
+	if resp, err = s.c.Do(req); err != nil {
+		err = fmt.Errorf("astiencoder: sending request failed: %w", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("astiencoder: invalid status code %d", resp.StatusCode)
+		return
+	}
+	return
+}
@@ -0,0 +1,74 @@
+package astiencoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorReporter(t *testing.T) {
+	// Create collector
+	var rs []ErrorReport
+	var h string
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		h = r.Header.Get("X-Test")
+		var b []ErrorReport
+		if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rs = append(rs, b...)
+	}))
+	defer s.Close()
+
+	// Create reporter
+	eh := NewEventHandler()
+	r := NewErrorReporter(ErrorReporterOptions{
+		BatchSize: 2,
+		Sender: NewHTTPErrorReportSender(HTTPErrorReportSenderOptions{
+			Headers: http.Header{"X-Test": []string{"test"}},
+			URL:     s.URL,
+		}),
+	}, eh)
+
+	// Emit errors
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	n := newMockedStatsNode("n", eh)
+	n.o.Metadata.Tags = []string{"t"}
+	n.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+	eh.Emit(EventError(n, errors.New("test 1")))
+	eh.Emit(EventError(w, errors.New("test 2")))
+	eh.Emit(EventError("unknown", errors.New("test 3")))
+
+	// Start reporter
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Start(ctx)
+	}()
+	cancel()
+	<-done
+
+	// Assert
+	assert.Equal(t, "test", h)
+	assert.Len(t, rs, 3)
+	for _, r := range rs {
+		assert.NotEqual(t, "", r.Stack)
+		assert.False(t, r.Time.IsZero())
+	}
+	assert.Equal(t, "test 1", rs[0].Error)
+	assert.Equal(t, &ErrorReportNode{Name: "n", Tags: []string{"t"}}, rs[0].Node)
+	assert.Equal(t, "w", rs[0].Workflow)
+	assert.Equal(t, "test 2", rs[1].Error)
+	assert.Nil(t, rs[1].Node)
+	assert.Equal(t, "w", rs[1].Workflow)
+	assert.Equal(t, "test 3", rs[2].Error)
+	assert.Equal(t, "", rs[2].Workflow)
+}
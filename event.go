@@ -4,28 +4,46 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/asticode/go-astikit"
 )
 
 // Default event names
 var (
-	EventNameError             = "astiencoder.error"
-	EventNameNodeContinued     = "astiencoder.node.continued"
-	EventNameNodePaused        = "astiencoder.node.paused"
-	EventNameNodeStarted       = "astiencoder.node.started"
-	EventNameNodeStats         = "astiencoder.node.stats"
-	EventNameNodeStopped       = "astiencoder.node.stopped"
-	EventNameWorkflowContinued = "astiencoder.workflow.continued"
-	EventNameWorkflowPaused    = "astiencoder.workflow.paused"
-	EventNameWorkflowStarted   = "astiencoder.workflow.started"
-	EventNameWorkflowStats     = "astiencoder.workflow.stats"
-	EventNameWorkflowStopped   = "astiencoder.workflow.stopped"
-	EventTypeContinued         = "continued"
-	EventTypePaused            = "paused"
-	EventTypeStarted           = "started"
-	EventTypeStats             = "stats"
-	EventTypeStopped           = "stopped"
+	EventNameAlertCleared                 = "astiencoder.alert.cleared"
+	EventNameAlertFired                   = "astiencoder.alert.fired"
+	EventNameAudit                        = "astiencoder.audit"
+	EventNameClusterInstanceLost          = "astiencoder.cluster.instance.lost"
+	EventNameClusterWorkflowPlaced        = "astiencoder.cluster.workflow.placed"
+	EventNameDefinitionDeprecated         = "astiencoder.definition.deprecated"
+	EventNameError                        = "astiencoder.error"
+	EventNameJobUpdated                   = "astiencoder.job.updated"
+	EventNameNodeContinued                = "astiencoder.node.continued"
+	EventNameNodePaused                   = "astiencoder.node.paused"
+	EventNameNodeReconfigured             = "astiencoder.node.reconfigured"
+	EventNameNodeStarted                  = "astiencoder.node.started"
+	EventNameNodeStats                    = "astiencoder.node.stats"
+	EventNameNodeStopped                  = "astiencoder.node.stopped"
+	EventNameProfileCaptured              = "astiencoder.profile.captured"
+	EventNameProfileRequested             = "astiencoder.profile.requested"
+	EventNameWebhookDeliveryFailed        = "astiencoder.webhook.delivery.failed"
+	EventNameWorkflowContinued            = "astiencoder.workflow.continued"
+	EventNameWorkflowHeartbeat            = "astiencoder.workflow.heartbeat"
+	EventNameWorkflowMemoryBudgetExceeded = "astiencoder.workflow.memory.budget.exceeded"
+	EventNameWorkflowMemoryBudgetRestored = "astiencoder.workflow.memory.budget.restored"
+	EventNameWorkflowPatched              = "astiencoder.workflow.patched"
+	EventNameWorkflowPaused               = "astiencoder.workflow.paused"
+	EventNameWorkflowProgress             = "astiencoder.workflow.progress"
+	EventNameWorkflowStarted              = "astiencoder.workflow.started"
+	EventNameWorkflowStats                = "astiencoder.workflow.stats"
+	EventNameWorkflowStatsAggregated      = "astiencoder.workflow.stats.aggregated"
+	EventNameWorkflowStopped              = "astiencoder.workflow.stopped"
+	EventTypeContinued                    = "continued"
+	EventTypePaused                       = "paused"
+	EventTypeStarted                      = "started"
+	EventTypeStats                        = "stats"
+	EventTypeStopped                      = "stopped"
 )
 
 // Event defaults
@@ -51,37 +69,54 @@ func EventError(target interface{}, err error) Event {
 }
 
 // EventHandler represents an event handler
+// Listeners are stored in an immutable snapshot that is replaced whenever a listener is added or deleted, therefore
+// emitting an event never contends with other emitters
 type EventHandler struct {
-	// Indexed by target then by event name then by listener idx
-	// We use a map[int]Listener so that deletion is as smooth as possible
-	cs  map[interface{}]map[string]map[int]EventCallback
 	idx int
 	m   *sync.Mutex
+	s   atomic.Value
 }
 
 // EventCallback represents an event callback
 type EventCallback func(e Event) (deleteListener bool)
 
-// NewEventHandler creates a new event handler
-func NewEventHandler() *EventHandler {
-	return &EventHandler{
-		cs: make(map[interface{}]map[string]map[int]EventCallback),
-		m:  &sync.Mutex{},
+type eventHandlerKey struct {
+	eventName string
+	target    interface{}
+}
+
+type eventHandlerListener struct {
+	c   EventCallback
+	idx int
+	key eventHandlerKey
+	// Only set for buffered listeners
+	r *eventRingBuffer
+}
+
+type eventHandlerSnapshot struct {
+	// Merged listeners indexed by event key
+	cache *sync.Map
+	// Listeners sorted by idx and indexed by listener key
+	ls map[eventHandlerKey][]*eventHandlerListener
+}
+
+func newEventHandlerSnapshot(ls map[eventHandlerKey][]*eventHandlerListener) *eventHandlerSnapshot {
+	return &eventHandlerSnapshot{
+		cache: &sync.Map{},
+		ls:    ls,
 	}
 }
 
+// NewEventHandler creates a new event handler
+func NewEventHandler() (h *EventHandler) {
+	h = &EventHandler{m: &sync.Mutex{}}
+	h.s.Store(newEventHandlerSnapshot(make(map[eventHandlerKey][]*eventHandlerListener)))
+	return
+}
+
 // Add adds a new callback for a specific target and event name
 func (h *EventHandler) Add(target interface{}, eventName string, c EventCallback) {
-	h.m.Lock()
-	defer h.m.Unlock()
-	if _, ok := h.cs[target]; !ok {
-		h.cs[target] = make(map[string]map[int]EventCallback)
-	}
-	if _, ok := h.cs[target][eventName]; !ok {
-		h.cs[target][eventName] = make(map[int]EventCallback)
-	}
-	h.idx++
-	h.cs[target][eventName][h.idx] = c
+	h.add(eventHandlerKey{eventName: eventName, target: target}, c, 0)
 }
 
 // AddForEventName adds a new callback for a specific event name
@@ -99,113 +134,387 @@ func (h *EventHandler) AddForAll(c EventCallback) {
 	h.Add(eventDefaultTarget, eventDefaultEventName, c)
 }
 
-func (h *EventHandler) del(target interface{}, eventName string, idx int) {
+// AddBuffered adds a new callback for a specific target and event name that is executed in its own goroutine
+// Events are stored in a ring buffer of the provided size so that emitters never wait for the callback. Once the
+// buffer is full, the oldest events are dropped
+// It should be used for slow callbacks handling high-frequency events such as stats
+func (h *EventHandler) AddBuffered(target interface{}, eventName string, size int, c EventCallback) {
+	h.add(eventHandlerKey{eventName: eventName, target: target}, c, size)
+}
+
+// AddForAllBuffered adds a new buffered callback for all events
+func (h *EventHandler) AddForAllBuffered(size int, c EventCallback) {
+	h.AddBuffered(eventDefaultTarget, eventDefaultEventName, size, c)
+}
+
+func (h *EventHandler) add(key eventHandlerKey, c EventCallback, size int) (l *eventHandlerListener) {
+	// Lock
 	h.m.Lock()
 	defer h.m.Unlock()
-	if _, ok := h.cs[target]; !ok {
-		return
+
+	// Create listener
+	h.idx++
+	l = &eventHandlerListener{
+		c:   c,
+		idx: h.idx,
+		key: key,
 	}
-	if _, ok := h.cs[target][eventName]; !ok {
-		return
+
+	// Listener is buffered
+	if size > 0 {
+		l.r = newEventRingBuffer(size)
+		go h.listen(l)
 	}
-	delete(h.cs[target][eventName], idx)
+
+	// Copy listeners
+	ls := h.copyListeners()
+
+	// Append listener
+	// Idxs are increasing, therefore listeners stay sorted
+	ls[key] = append(append([]*eventHandlerListener{}, ls[key]...), l)
+
+	// Store snapshot
+	h.s.Store(newEventHandlerSnapshot(ls))
+	return
 }
 
-type eventHandlerCallback struct {
-	c         EventCallback
-	eventName string
-	idx       int
-	target    interface{}
+func (h *EventHandler) copyListeners() (ls map[eventHandlerKey][]*eventHandlerListener) {
+	s := h.snapshot()
+	ls = make(map[eventHandlerKey][]*eventHandlerListener, len(s.ls))
+	for k, v := range s.ls {
+		ls[k] = v
+	}
+	return
+}
+
+func (h *EventHandler) snapshot() *eventHandlerSnapshot {
+	return h.s.Load().(*eventHandlerSnapshot)
 }
 
-func (h *EventHandler) callbacks(target interface{}, eventName string) (cs []eventHandlerCallback) {
+func (h *EventHandler) del(l *eventHandlerListener) {
 	// Lock
 	h.m.Lock()
 	defer h.m.Unlock()
 
-	// Index callbacks
-	ics := make(map[int]eventHandlerCallback)
-	var idxs []int
+	// Copy listeners
+	ls := h.copyListeners()
+
+	// Remove listener
+	var found bool
+	var nls []*eventHandlerListener
+	for _, v := range ls[l.key] {
+		if v == l {
+			found = true
+			continue
+		}
+		nls = append(nls, v)
+	}
+
+	// Listener has already been deleted
+	if !found {
+		return
+	}
+
+	// Update listeners
+	if len(nls) > 0 {
+		ls[l.key] = nls
+	} else {
+		delete(ls, l.key)
+	}
+
+	// Store snapshot
+	h.s.Store(newEventHandlerSnapshot(ls))
+
+	// Close ring buffer
+	if l.r != nil {
+		l.r.close()
+	}
+}
+
+func (h *EventHandler) listeners(target interface{}, eventName string) []*eventHandlerListener {
+	// Get snapshot
+	s := h.snapshot()
+
+	// Check cache
+	k := eventHandlerKey{eventName: eventName, target: target}
+	if v, ok := s.cache.Load(k); ok {
+		return v.([]*eventHandlerListener)
+	}
+
+	// Merge listeners
+	var ls []*eventHandlerListener
 	for _, target := range []interface{}{eventDefaultTarget, target} {
-		if _, ok := h.cs[target]; ok {
-			for _, eventName := range []string{eventDefaultEventName, eventName} {
-				if _, ok := h.cs[target][eventName]; ok {
-					for idx, c := range h.cs[target][eventName] {
-						ics[idx] = eventHandlerCallback{
-							c:         c,
-							eventName: eventName,
-							idx:       idx,
-							target:    target,
-						}
-						idxs = append(idxs, idx)
+		for _, eventName := range []string{eventDefaultEventName, eventName} {
+			for _, l := range s.ls[eventHandlerKey{eventName: eventName, target: target}] {
+				var found bool
+				for _, v := range ls {
+					if v == l {
+						found = true
+						break
 					}
 				}
+				if !found {
+					ls = append(ls, l)
+				}
 			}
 		}
 	}
 
 	// Sort
-	sort.Ints(idxs)
+	sort.Slice(ls, func(i, j int) bool { return ls[i].idx < ls[j].idx })
 
-	// Append
-	for _, idx := range idxs {
-		cs = append(cs, ics[idx])
+	// Store in cache
+	s.cache.Store(k, ls)
+	return ls
+}
+
+func (h *EventHandler) listen(l *eventHandlerListener) {
+	for {
+		// Pop event
+		e, ok := l.r.pop()
+		if !ok {
+			return
+		}
+
+		// Callback
+		if l.c(e) {
+			h.del(l)
+			return
+		}
 	}
-	return
 }
 
 // Emit emits an event
 func (h *EventHandler) Emit(e Event) {
-	for _, c := range h.callbacks(e.Target, e.Name) {
-		if c.c(e) {
-			h.del(c.target, c.eventName, c.idx)
+	for _, l := range h.listeners(e.Target, e.Name) {
+		// Listener is buffered
+		if l.r != nil {
+			l.r.push(e)
+			continue
+		}
+
+		// Callback
+		if l.c(e) {
+			h.del(l)
 		}
 	}
 }
 
+// Close stops buffered callbacks once they have handled the events left in their buffer
+func (h *EventHandler) Close() {
+	for _, ls := range h.snapshot().ls {
+		for _, l := range ls {
+			if l.r != nil {
+				l.r.close()
+			}
+		}
+	}
+}
+
+// EventsDropped returns the number of events dropped by buffered callbacks because their buffer was full
+func (h *EventHandler) EventsDropped() (n uint64) {
+	for _, ls := range h.snapshot().ls {
+		for _, l := range ls {
+			if l.r != nil {
+				n += l.r.droppedCount()
+			}
+		}
+	}
+	return
+}
+
+type eventRingBuffer struct {
+	c       *sync.Cond
+	closed  bool
+	dropped uint64
+	es      []Event
+	head    int
+	n       int
+}
+
+func newEventRingBuffer(size int) *eventRingBuffer {
+	return &eventRingBuffer{
+		c:  sync.NewCond(&sync.Mutex{}),
+		es: make([]Event, size),
+	}
+}
+
+func (r *eventRingBuffer) push(e Event) {
+	// Lock
+	r.c.L.Lock()
+	defer r.c.L.Unlock()
+
+	// Ring buffer is closed
+	if r.closed {
+		return
+	}
+
+	// Ring buffer is full
+	if r.n == len(r.es) {
+		r.es[r.head] = Event{}
+		r.head = (r.head + 1) % len(r.es)
+		r.n--
+		r.dropped++
+	}
+
+	// Append
+	r.es[(r.head+r.n)%len(r.es)] = e
+	r.n++
+	r.c.Signal()
+}
+
+func (r *eventRingBuffer) pop() (e Event, ok bool) {
+	// Lock
+	r.c.L.Lock()
+	defer r.c.L.Unlock()
+
+	// Wait for an event
+	for r.n == 0 && !r.closed {
+		r.c.Wait()
+	}
+
+	// Ring buffer is closed and empty
+	if r.n == 0 {
+		return
+	}
+
+	// Shift
+	e = r.es[r.head]
+	r.es[r.head] = Event{}
+	r.head = (r.head + 1) % len(r.es)
+	r.n--
+	ok = true
+	return
+}
+
+func (r *eventRingBuffer) close() {
+	r.c.L.Lock()
+	defer r.c.L.Unlock()
+	r.closed = true
+	r.c.Broadcast()
+}
+
+func (r *eventRingBuffer) droppedCount() uint64 {
+	r.c.L.Lock()
+	defer r.c.L.Unlock()
+	return r.dropped
+}
+
 // LoggerEventHandlerAdapter adapts the event handler so that it logs the events properly
 func LoggerEventHandlerAdapter(i astikit.StdLogger, h *EventHandler) {
-	// Create logger
-	l := astikit.AdaptStdLogger(i)
+	StructuredLoggerEventHandlerAdapter(AdaptStdLogger(i), h)
+}
 
+// StructuredLoggerEventHandlerAdapter adapts the event handler so that it logs the events properly, with their
+// workflow, node and error as fields
+func StructuredLoggerEventHandlerAdapter(l Logger, h *EventHandler) {
 	// Error
 	h.AddForEventName(EventNameError, func(e Event) bool {
-		var t string
+		fs := []LogField{{Key: LogFieldError, Value: e.Payload.(error)}}
 		if v, ok := e.Target.(Node); ok {
-			t = v.Metadata().Name
+			fs = append(fs, nodeLogFields(v)...)
 		} else if v, ok := e.Target.(*Workflow); ok {
-			t = v.Name()
+			fs = append(fs, LogField{Key: LogFieldWorkflow, Value: v.Name()})
 		} else if e.Target != nil {
-			t = fmt.Sprintf("%p", e.Target)
+			fs = append(fs, LogField{Key: "target", Value: fmt.Sprintf("%p", e.Target)})
+		}
+		l.Error("astiencoder: an error occurred", fs...)
+		return false
+	})
+
+	// Alert
+	h.AddForEventName(EventNameAlertCleared, func(e Event) bool {
+		l.Info("astiencoder: alert is cleared", alertLogFields(e)...)
+		return false
+	})
+	h.AddForEventName(EventNameAlertFired, func(e Event) bool {
+		l.Warn("astiencoder: alert is fired", alertLogFields(e)...)
+		return false
+	})
+
+	// Audit
+	h.AddForEventName(EventNameAudit, func(e Event) bool {
+		a := e.Payload.(AuditEntry)
+		fs := []LogField{
+			{Key: LogFieldWorkflow, Value: a.Workflow},
+			{Key: "action", Value: a.Action},
+			{Key: "actor", Value: a.Actor},
+		}
+		if a.Node != "" {
+			fs = append(fs, LogField{Key: LogFieldNode, Value: a.Node})
+		}
+		for k, v := range a.Params {
+			fs = append(fs, LogField{Key: k, Value: v})
 		}
-		if len(t) > 0 {
-			t = "(" + t + ")"
+		if a.Error != "" {
+			fs = append(fs, LogField{Key: LogFieldError, Value: a.Error})
 		}
-		l.Errorf("%s%s", e.Payload.(error), t)
+		l.Info("astiencoder: control operation is performed", fs...)
+		return false
+	})
+
+	// Definition
+	h.AddForEventName(EventNameDefinitionDeprecated, func(e Event) bool {
+		dw := e.Payload.(DefinitionWarning)
+		fs := []LogField{
+			{Key: LogFieldWorkflow, Value: e.Target.(*Workflow).Name()},
+			{Key: "path", Value: dw.Path},
+		}
+		if dw.Node != "" {
+			fs = append(fs, LogField{Key: LogFieldNode, Value: dw.Node})
+		}
+		l.Warn("astiencoder: definition is deprecated: "+dw.Message, fs...)
 		return false
 	})
 
 	// Node
 	h.AddForEventName(EventNameNodeStarted, func(e Event) bool {
-		l.Debugf("astiencoder: node %s (%s) is started", e.Target.(Node).Metadata().Name, e.Target.(Node).Metadata().Label)
+		l.Debug("astiencoder: node is started", nodeLogFields(e.Target.(Node))...)
+		return false
+	})
+	h.AddForEventName(EventNameNodeReconfigured, func(e Event) bool {
+		l.Info("astiencoder: node is reconfigured", nodeLogFields(e.Target.(Node))...)
 		return false
 	})
 	h.AddForEventName(EventNameNodeStopped, func(e Event) bool {
-		l.Debugf("astiencoder: node %s (%s) is stopped", e.Target.(Node).Metadata().Name, e.Target.(Node).Metadata().Label)
+		l.Debug("astiencoder: node is stopped", nodeLogFields(e.Target.(Node))...)
 		return false
 	})
 
 	// Workflow
+	h.AddForEventName(EventNameWorkflowPatched, func(e Event) bool {
+		l.Info(fmt.Sprintf("astiencoder: workflow is patched with %d changes", len(e.Payload.(DefinitionChanges))), LogField{Key: LogFieldWorkflow, Value: e.Target.(*Workflow).Name()})
+		return false
+	})
 	h.AddForEventName(EventNameWorkflowStarted, func(e Event) bool {
-		l.Debugf("astiencoder: workflow %s is started", e.Target.(*Workflow).Name())
+		l.Debug("astiencoder: workflow is started", LogField{Key: LogFieldWorkflow, Value: e.Target.(*Workflow).Name()})
 		return false
 	})
 	h.AddForEventName(EventNameWorkflowStopped, func(e Event) bool {
-		l.Debugf("astiencoder: workflow %s is stopped", e.Target.(*Workflow).Name())
+		l.Debug("astiencoder: workflow is stopped", LogField{Key: LogFieldWorkflow, Value: e.Target.(*Workflow).Name()})
 		return false
 	})
 }
 
+func alertLogFields(e Event) []LogField {
+	a := e.Payload.(Alert)
+	return append(nodeLogFields(e.Target.(Node)),
+		LogField{Key: "rule", Value: a.Rule.Name},
+		LogField{Key: "value", Value: a.Value},
+	)
+}
+
+func nodeLogFields(n Node) (fs []LogField) {
+	fs = []LogField{
+		{Key: LogFieldNode, Value: n.Metadata().Name},
+		{Key: LogFieldLabel, Value: n.Metadata().Label},
+	}
+	if w := nodeWorkflowName(n); w != "" {
+		fs = append(fs, LogField{Key: LogFieldWorkflow, Value: w})
+	}
+	return
+}
+
 // EventGenerator represents an object capable of generating an event based on its type
 type EventGenerator interface {
 	Event(eventType string, payload interface{}) Event
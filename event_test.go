@@ -2,6 +2,7 @@ package astiencoder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -48,3 +49,66 @@ func TestEvent(t *testing.T) {
 	})
 	assert.Equal(t, []string{"2", "4", "5"}, es)
 }
+
+func TestEventHandlerBuffered(t *testing.T) {
+	// Setup
+	eh := NewEventHandler()
+	defer eh.Close()
+	done := make(chan struct{})
+	unblock := make(chan struct{})
+	var es []string
+
+	// Callbacks
+	eh.AddForAllBuffered(2, func(evt Event) bool {
+		if evt.Name == "1" {
+			<-unblock
+		}
+		es = append(es, evt.Name)
+		if evt.Name == "4" {
+			close(done)
+			return true
+		}
+		return false
+	})
+
+	// Emit without waiting for the callback
+	for _, n := range []string{"1", "2", "3", "4"} {
+		eh.Emit(Event{Name: n})
+		if n == "1" {
+			r := eh.snapshot().ls[eventHandlerKey{target: eventDefaultTarget}][0].r
+			for eventRingBufferLen(r) > 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	close(unblock)
+	<-done
+	assert.Equal(t, []string{"1", "3", "4"}, es)
+	for len(eh.snapshot().ls) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func eventRingBufferLen(r *eventRingBuffer) int {
+	r.c.L.Lock()
+	defer r.c.L.Unlock()
+	return r.n
+}
+
+func TestEventRingBuffer(t *testing.T) {
+	r := newEventRingBuffer(2)
+	r.push(Event{Name: "1"})
+	r.push(Event{Name: "2"})
+	r.push(Event{Name: "3"})
+	assert.Equal(t, uint64(1), r.droppedCount())
+	r.close()
+	r.push(Event{Name: "4"})
+	e, ok := r.pop()
+	assert.True(t, ok)
+	assert.Equal(t, "2", e.Name)
+	e, ok = r.pop()
+	assert.True(t, ok)
+	assert.Equal(t, "3", e.Name)
+	_, ok = r.pop()
+	assert.False(t, ok)
+}
@@ -0,0 +1,232 @@
+package astiencoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FFmpeg node kinds
+const (
+	FFmpegNodeKindDecoder = "decoder"
+	FFmpegNodeKindEncoder = "encoder"
+	FFmpegNodeKindFilter  = "filter"
+	FFmpegNodeKindInput   = "input"
+	FFmpegNodeKindOutput  = "output"
+)
+
+// FFmpegOption represents an ffmpeg option, e.g. "-c libx264"
+type FFmpegOption struct {
+	// Without the leading "-", e.g. "c"
+	Name string
+	// Empty for options without value, e.g. "-re"
+	Value string
+}
+
+// FFmpegNode represents the ffmpeg equivalent of a node
+type FFmpegNode struct {
+	// Filters, as you would use them in ffmpeg. Filters only
+	Filter string
+	Kind   string
+	// Inputs' options are placed before their -i, outputs' options before their URL, and encoders' options are
+	// stream options whose stream specifier is added by the workflow
+	Options []FFmpegOption
+	// Indexes of the streams sent to each child, indexed by child name. Inputs only
+	Streams map[string][]int
+	// Inputs and outputs only
+	URL string
+}
+
+// FFmpegDescriber represents a node capable of describing its ffmpeg equivalent
+type FFmpegDescriber interface {
+	FFmpegNode() (FFmpegNode, error)
+}
+
+// FFmpegCommand represents an ffmpeg command approximating a workflow
+type FFmpegCommand struct {
+	// Without the leading "ffmpeg"
+	Args []string
+	// Nodes whose processing is not part of the command
+	Unsupported []FFmpegUnsupportedNode
+}
+
+// FFmpegUnsupportedNode represents a node whose processing is not part of an ffmpeg command
+type FFmpegUnsupportedNode struct {
+	Name   string
+	Reason string
+}
+
+// String returns the command as it would be typed in a shell
+func (c FFmpegCommand) String() string {
+	ss := []string{"ffmpeg"}
+	for _, a := range c.Args {
+		ss = append(ss, quoteFFmpegArg(a))
+	}
+	return strings.Join(ss, " ")
+}
+
+func quoteFFmpegArg(a string) string {
+	if a != "" && strings.IndexFunc(a, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r))
+	}) < 0 {
+		return a
+	}
+	return "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+}
+
+// FFmpegCommand approximates the workflow's current graph as an ffmpeg command so that issues can be reproduced
+// outside the app. Nodes implementing FFmpegDescriber are mapped to inputs, outputs and stream options. Output
+// streams are the chains of 1-parent nodes going from an input to an output, optionally through a decoder, filters
+// and an encoder. Nodes that can't be described, as well as the ones breaking a chain, are listed as unsupported and
+// the streams going through them are left out of the command
+func (w *Workflow) FFmpegCommand() (c FFmpegCommand) {
+	// Get nodes
+	ns := w.nodes()
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Metadata().Name < ns[j].Metadata().Name })
+
+	// Describe nodes
+	fns := make(map[string]FFmpegNode)
+	unsupported := make(map[string]string)
+	for _, n := range ns {
+		d, ok := n.(FFmpegDescriber)
+		if !ok {
+			unsupported[n.Metadata().Name] = "node has no ffmpeg equivalent"
+			continue
+		}
+		fn, err := d.FFmpegNode()
+		if err != nil {
+			unsupported[n.Metadata().Name] = fmt.Sprintf("describing node failed: %s", err)
+			continue
+		}
+		fns[n.Metadata().Name] = fn
+	}
+
+	// Loop through inputs
+	inputs := make(map[string]int)
+	for _, n := range ns {
+		fn, ok := fns[n.Metadata().Name]
+		if !ok || fn.Kind != FFmpegNodeKindInput {
+			continue
+		}
+		inputs[n.Metadata().Name] = len(inputs)
+		c.Args = append(c.Args, ffmpegOptionArgs(fn.Options, "")...)
+		c.Args = append(c.Args, "-i", fn.URL)
+	}
+
+	// Loop through outputs
+	for _, n := range ns {
+		// Get node
+		fn, ok := fns[n.Metadata().Name]
+		if !ok || fn.Kind != FFmpegNodeKindOutput {
+			continue
+		}
+
+		// Loop through parents
+		var idx int
+		var args []string
+		for _, p := range n.Parents() {
+			// Get chain
+			chain, input, reason := ffmpegChain(p, fns, unsupported)
+			if reason != "" {
+				if _, ok := unsupported[chain[len(chain)-1].Metadata().Name]; !ok {
+					unsupported[chain[len(chain)-1].Metadata().Name] = reason
+				}
+				continue
+			}
+
+			// Get stream indexes, input streams are either copied or decoded
+			child := n
+			if len(chain) > 1 {
+				child = chain[len(chain)-2]
+			}
+			sis := fns[input.Metadata().Name].Streams[child.Metadata().Name]
+
+			// Get stream options
+			var filters []string
+			var options []FFmpegOption
+			for i := len(chain) - 2; i >= 0; i-- {
+				switch cfn := fns[chain[i].Metadata().Name]; cfn.Kind {
+				case FFmpegNodeKindEncoder:
+					options = append(options, cfn.Options...)
+				case FFmpegNodeKindFilter:
+					filters = append(filters, cfn.Filter)
+				}
+			}
+			if len(chain) == 1 {
+				options = append(options, FFmpegOption{Name: "c", Value: "copy"})
+			}
+			if len(filters) > 0 {
+				options = append(options, FFmpegOption{Name: "filter", Value: strings.Join(filters, ",")})
+			}
+
+			// Loop through streams
+			for _, si := range sis {
+				args = append(args, "-map", fmt.Sprintf("%d:%d", inputs[input.Metadata().Name], si))
+				args = append(args, ffmpegOptionArgs(options, fmt.Sprintf(":%d", idx))...)
+				idx++
+			}
+		}
+
+		// Append
+		c.Args = append(c.Args, args...)
+		c.Args = append(c.Args, ffmpegOptionArgs(fn.Options, "")...)
+		c.Args = append(c.Args, fn.URL)
+	}
+
+	// Unsupported nodes
+	var names []string
+	for name := range unsupported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c.Unsupported = append(c.Unsupported, FFmpegUnsupportedNode{
+			Name:   name,
+			Reason: unsupported[name],
+		})
+	}
+	return
+}
+
+// ffmpegChain returns the nodes going from n up to an input, starting with n. If the chain is broken, the reason is
+// returned and the last node of the chain is the one breaking it
+func ffmpegChain(n Node, fns map[string]FFmpegNode, unsupported map[string]string) (chain []Node, input Node, reason string) {
+	for {
+		// Append
+		chain = append(chain, n)
+
+		// Node is not described
+		fn, ok := fns[n.Metadata().Name]
+		if !ok {
+			reason = unsupported[n.Metadata().Name]
+			return
+		}
+
+		// Switch on kind
+		switch fn.Kind {
+		case FFmpegNodeKindInput:
+			input = n
+			return
+		case FFmpegNodeKindDecoder, FFmpegNodeKindEncoder, FFmpegNodeKindFilter:
+			ps := n.Parents()
+			if len(ps) != 1 {
+				reason = fmt.Sprintf("%s with %d parents is not supported", fn.Kind, len(ps))
+				return
+			}
+			n = ps[0]
+		default:
+			reason = fmt.Sprintf("%s can't be the parent of an output", fn.Kind)
+			return
+		}
+	}
+}
+
+func ffmpegOptionArgs(os []FFmpegOption, specifier string) (args []string) {
+	for _, o := range os {
+		args = append(args, "-"+o.Name+specifier)
+		if o.Value != "" {
+			args = append(args, o.Value)
+		}
+	}
+	return
+}
@@ -0,0 +1,66 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedFFmpegNode struct {
+	*mockedStatsNode
+	err error
+	fn  FFmpegNode
+}
+
+func newMockedFFmpegNode(name string, eh *EventHandler, fn FFmpegNode) *mockedFFmpegNode {
+	return &mockedFFmpegNode{
+		fn:              fn,
+		mockedStatsNode: newMockedStatsNode(name, eh),
+	}
+}
+
+func (n *mockedFFmpegNode) FFmpegNode() (FFmpegNode, error) {
+	return n.fn, n.err
+}
+
+func TestWorkflowFFmpegCommand(t *testing.T) {
+	// Create workflow
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	in := newMockedFFmpegNode("in", eh, FFmpegNode{
+		Kind:    FFmpegNodeKindInput,
+		Options: []FFmpegOption{{Name: "re"}},
+		Streams: map[string][]int{"dec": {0}, "out": {1, 2}},
+		URL:     "in.mp4",
+	})
+	dec := newMockedFFmpegNode("dec", eh, FFmpegNode{Kind: FFmpegNodeKindDecoder})
+	flt := newMockedFFmpegNode("flt", eh, FFmpegNode{Filter: "scale=1280:720", Kind: FFmpegNodeKindFilter})
+	enc1 := newMockedFFmpegNode("enc1", eh, FFmpegNode{Kind: FFmpegNodeKindEncoder, Options: []FFmpegOption{{Name: "c", Value: "libx264"}, {Name: "b", Value: "3000000"}}})
+	other := newMockedStatsNode("other", eh)
+	enc2 := newMockedFFmpegNode("enc2", eh, FFmpegNode{Kind: FFmpegNodeKindEncoder})
+	broken := newMockedFFmpegNode("broken", eh, FFmpegNode{})
+	broken.err = errors.New("test")
+	out := newMockedFFmpegNode("out", eh, FFmpegNode{Kind: FFmpegNodeKindOutput, Options: []FFmpegOption{{Name: "f", Value: "mp4"}}, URL: "my out.mp4"})
+	w.AddChild(in)
+	ConnectNodes(in, dec)
+	ConnectNodes(dec, flt)
+	ConnectNodes(flt, enc1)
+	ConnectNodes(enc1, out)
+	ConnectNodes(in, out)
+	ConnectNodes(dec, other)
+	ConnectNodes(other, enc2)
+	ConnectNodes(enc2, out)
+	ConnectNodes(in, broken)
+
+	// Command
+	c := w.FFmpegCommand()
+	assert.Equal(t, []string{"-re", "-i", "in.mp4", "-map", "0:0", "-c:0", "libx264", "-b:0", "3000000", "-filter:0", "scale=1280:720", "-map", "0:1", "-c:1", "copy", "-map", "0:2", "-c:2", "copy", "-f", "mp4", "my out.mp4"}, c.Args)
+	assert.Equal(t, []FFmpegUnsupportedNode{
+		{Name: "broken", Reason: "describing node failed: test"},
+		{Name: "other", Reason: "node has no ffmpeg equivalent"},
+	}, c.Unsupported)
+	assert.Equal(t, "ffmpeg -re -i in.mp4 -map 0:0 -c:0 libx264 -b:0 3000000 -filter:0 scale=1280:720 -map 0:1 -c:1 copy -map 0:2 -c:2 copy -f mp4 'my out.mp4'", c.String())
+}
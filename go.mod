@@ -10,4 +10,5 @@ require (
 	github.com/gorilla/websocket v1.4.1
 	github.com/julienschmidt/httprouter v1.3.0
 	github.com/stretchr/testify v1.4.0
+	go.etcd.io/bbolt v1.3.5
 )
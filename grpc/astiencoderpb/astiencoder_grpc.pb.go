@@ -0,0 +1,582 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: proto/astiencoder.proto
+
+package astiencoderpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Encoder_CreateWorkflow_FullMethodName   = "/astiencoder.v1.Encoder/CreateWorkflow"
+	Encoder_DeleteWorkflow_FullMethodName   = "/astiencoder.v1.Encoder/DeleteWorkflow"
+	Encoder_GetWorkflow_FullMethodName      = "/astiencoder.v1.Encoder/GetWorkflow"
+	Encoder_ListWorkflows_FullMethodName    = "/astiencoder.v1.Encoder/ListWorkflows"
+	Encoder_StartWorkflow_FullMethodName    = "/astiencoder.v1.Encoder/StartWorkflow"
+	Encoder_StopWorkflow_FullMethodName     = "/astiencoder.v1.Encoder/StopWorkflow"
+	Encoder_PauseWorkflow_FullMethodName    = "/astiencoder.v1.Encoder/PauseWorkflow"
+	Encoder_ContinueWorkflow_FullMethodName = "/astiencoder.v1.Encoder/ContinueWorkflow"
+	Encoder_ReconfigureNode_FullMethodName  = "/astiencoder.v1.Encoder/ReconfigureNode"
+	Encoder_Reload_FullMethodName           = "/astiencoder.v1.Encoder/Reload"
+	Encoder_StreamStats_FullMethodName      = "/astiencoder.v1.Encoder/StreamStats"
+	Encoder_StreamEvents_FullMethodName     = "/astiencoder.v1.Encoder/StreamEvents"
+)
+
+// EncoderClient is the client API for Encoder service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EncoderClient interface {
+	// Workflows
+	CreateWorkflow(ctx context.Context, in *CreateWorkflowRequest, opts ...grpc.CallOption) (*Workflow, error)
+	DeleteWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*WorkflowSnapshot, error)
+	ListWorkflows(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWorkflowsResponse, error)
+	// Control
+	StartWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	StopWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PauseWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ContinueWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ReconfigureNode(ctx context.Context, in *ReconfigureNodeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Reloads the workflows from the service's definition source
+	Reload(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadResponse, error)
+	// Streaming
+	// Streams the "astiencoder.node.stats" and "astiencoder.workflow.stats" events of a workflow
+	StreamStats(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (Encoder_StreamStatsClient, error)
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Encoder_StreamEventsClient, error)
+}
+
+type encoderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEncoderClient(cc grpc.ClientConnInterface) EncoderClient {
+	return &encoderClient{cc}
+}
+
+func (c *encoderClient) CreateWorkflow(ctx context.Context, in *CreateWorkflowRequest, opts ...grpc.CallOption) (*Workflow, error) {
+	out := new(Workflow)
+	err := c.cc.Invoke(ctx, Encoder_CreateWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) DeleteWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_DeleteWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) GetWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*WorkflowSnapshot, error) {
+	out := new(WorkflowSnapshot)
+	err := c.cc.Invoke(ctx, Encoder_GetWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) ListWorkflows(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWorkflowsResponse, error) {
+	out := new(ListWorkflowsResponse)
+	err := c.cc.Invoke(ctx, Encoder_ListWorkflows_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) StartWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_StartWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) StopWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_StopWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) PauseWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_PauseWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) ContinueWorkflow(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_ContinueWorkflow_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) ReconfigureNode(ctx context.Context, in *ReconfigureNodeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Encoder_ReconfigureNode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) Reload(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ReloadResponse, error) {
+	out := new(ReloadResponse)
+	err := c.cc.Invoke(ctx, Encoder_Reload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *encoderClient) StreamStats(ctx context.Context, in *WorkflowRequest, opts ...grpc.CallOption) (Encoder_StreamStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Encoder_ServiceDesc.Streams[0], Encoder_StreamStats_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &encoderStreamStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Encoder_StreamStatsClient interface {
+	Recv() (*Stats, error)
+	grpc.ClientStream
+}
+
+type encoderStreamStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *encoderStreamStatsClient) Recv() (*Stats, error) {
+	m := new(Stats)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *encoderClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (Encoder_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Encoder_ServiceDesc.Streams[1], Encoder_StreamEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &encoderStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Encoder_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type encoderStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *encoderStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EncoderServer is the server API for Encoder service.
+// All implementations must embed UnimplementedEncoderServer
+// for forward compatibility
+type EncoderServer interface {
+	// Workflows
+	CreateWorkflow(context.Context, *CreateWorkflowRequest) (*Workflow, error)
+	DeleteWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error)
+	GetWorkflow(context.Context, *WorkflowRequest) (*WorkflowSnapshot, error)
+	ListWorkflows(context.Context, *emptypb.Empty) (*ListWorkflowsResponse, error)
+	// Control
+	StartWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error)
+	StopWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error)
+	PauseWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error)
+	ContinueWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error)
+	ReconfigureNode(context.Context, *ReconfigureNodeRequest) (*emptypb.Empty, error)
+	// Reloads the workflows from the service's definition source
+	Reload(context.Context, *emptypb.Empty) (*ReloadResponse, error)
+	// Streaming
+	// Streams the "astiencoder.node.stats" and "astiencoder.workflow.stats" events of a workflow
+	StreamStats(*WorkflowRequest, Encoder_StreamStatsServer) error
+	StreamEvents(*StreamEventsRequest, Encoder_StreamEventsServer) error
+	mustEmbedUnimplementedEncoderServer()
+}
+
+// UnimplementedEncoderServer must be embedded to have forward compatible implementations.
+type UnimplementedEncoderServer struct {
+}
+
+func (UnimplementedEncoderServer) CreateWorkflow(context.Context, *CreateWorkflowRequest) (*Workflow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) DeleteWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) GetWorkflow(context.Context, *WorkflowRequest) (*WorkflowSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) ListWorkflows(context.Context, *emptypb.Empty) (*ListWorkflowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflows not implemented")
+}
+func (UnimplementedEncoderServer) StartWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) StopWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) PauseWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) ContinueWorkflow(context.Context, *WorkflowRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ContinueWorkflow not implemented")
+}
+func (UnimplementedEncoderServer) ReconfigureNode(context.Context, *ReconfigureNodeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReconfigureNode not implemented")
+}
+func (UnimplementedEncoderServer) Reload(context.Context, *emptypb.Empty) (*ReloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
+func (UnimplementedEncoderServer) StreamStats(*WorkflowRequest, Encoder_StreamStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedEncoderServer) StreamEvents(*StreamEventsRequest, Encoder_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedEncoderServer) mustEmbedUnimplementedEncoderServer() {}
+
+// UnsafeEncoderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EncoderServer will
+// result in compilation errors.
+type UnsafeEncoderServer interface {
+	mustEmbedUnimplementedEncoderServer()
+}
+
+func RegisterEncoderServer(s grpc.ServiceRegistrar, srv EncoderServer) {
+	s.RegisterService(&Encoder_ServiceDesc, srv)
+}
+
+func _Encoder_CreateWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).CreateWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_CreateWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).CreateWorkflow(ctx, req.(*CreateWorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_DeleteWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).DeleteWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_DeleteWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).DeleteWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_GetWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).GetWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_GetWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).GetWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_ListWorkflows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).ListWorkflows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_ListWorkflows_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).ListWorkflows(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_StartWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).StartWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_StartWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).StartWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_StopWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).StopWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_StopWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).StopWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_PauseWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).PauseWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_PauseWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).PauseWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_ContinueWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).ContinueWorkflow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_ContinueWorkflow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).ContinueWorkflow(ctx, req.(*WorkflowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_ReconfigureNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconfigureNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).ReconfigureNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_ReconfigureNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).ReconfigureNode(ctx, req.(*ReconfigureNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EncoderServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Encoder_Reload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EncoderServer).Reload(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Encoder_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WorkflowRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EncoderServer).StreamStats(m, &encoderStreamStatsServer{stream})
+}
+
+type Encoder_StreamStatsServer interface {
+	Send(*Stats) error
+	grpc.ServerStream
+}
+
+type encoderStreamStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *encoderStreamStatsServer) Send(m *Stats) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Encoder_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EncoderServer).StreamEvents(m, &encoderStreamEventsServer{stream})
+}
+
+type Encoder_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type encoderStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *encoderStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Encoder_ServiceDesc is the grpc.ServiceDesc for Encoder service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Encoder_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "astiencoder.v1.Encoder",
+	HandlerType: (*EncoderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWorkflow",
+			Handler:    _Encoder_CreateWorkflow_Handler,
+		},
+		{
+			MethodName: "DeleteWorkflow",
+			Handler:    _Encoder_DeleteWorkflow_Handler,
+		},
+		{
+			MethodName: "GetWorkflow",
+			Handler:    _Encoder_GetWorkflow_Handler,
+		},
+		{
+			MethodName: "ListWorkflows",
+			Handler:    _Encoder_ListWorkflows_Handler,
+		},
+		{
+			MethodName: "StartWorkflow",
+			Handler:    _Encoder_StartWorkflow_Handler,
+		},
+		{
+			MethodName: "StopWorkflow",
+			Handler:    _Encoder_StopWorkflow_Handler,
+		},
+		{
+			MethodName: "PauseWorkflow",
+			Handler:    _Encoder_PauseWorkflow_Handler,
+		},
+		{
+			MethodName: "ContinueWorkflow",
+			Handler:    _Encoder_ContinueWorkflow_Handler,
+		},
+		{
+			MethodName: "ReconfigureNode",
+			Handler:    _Encoder_ReconfigureNode_Handler,
+		},
+		{
+			MethodName: "Reload",
+			Handler:    _Encoder_Reload_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStats",
+			Handler:       _Encoder_StreamStats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _Encoder_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/astiencoder.proto",
+}
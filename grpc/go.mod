@@ -0,0 +1,28 @@
+module github.com/asticode/go-astiencoder/grpc
+
+go 1.19
+
+require (
+	github.com/asticode/go-astiencoder v0.0.0
+	github.com/asticode/go-astikit v0.7.1
+	github.com/stretchr/testify v1.4.0
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/asticode/go-astiws v1.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/gorilla/websocket v1.4.1 // indirect
+	github.com/julienschmidt/httprouter v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.etcd.io/bbolt v1.3.5 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+// The gRPC server lives in its own module so that the encoder doesn't depend on gRPC
+replace github.com/asticode/go-astiencoder => ../
@@ -0,0 +1,609 @@
+// Package astigrpc serves an astiencoder.ControlService as the Encoder gRPC service described in
+// proto/astiencoder.proto so that non-Go control planes can drive the encoder
+package astigrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astiencoder/grpc/astiencoderpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata keys
+const (
+	MetadataKeyActor         = "x-astiencoder-actor"
+	MetadataKeyAuthorization = "authorization"
+	MetadataKeyNamespace     = "x-astiencoder-namespace"
+	MetadataKeyRetryAfter    = "retry-after"
+)
+
+// Roles of the rpcs, indexed by full method name
+var roles = map[string]astiencoder.Role{
+	astiencoderpb.Encoder_CreateWorkflow_FullMethodName:   astiencoder.RoleAdmin,
+	astiencoderpb.Encoder_DeleteWorkflow_FullMethodName:   astiencoder.RoleAdmin,
+	astiencoderpb.Encoder_GetWorkflow_FullMethodName:      astiencoder.RoleViewer,
+	astiencoderpb.Encoder_ListWorkflows_FullMethodName:    astiencoder.RoleViewer,
+	astiencoderpb.Encoder_StartWorkflow_FullMethodName:    astiencoder.RoleOperator,
+	astiencoderpb.Encoder_StopWorkflow_FullMethodName:     astiencoder.RoleOperator,
+	astiencoderpb.Encoder_PauseWorkflow_FullMethodName:    astiencoder.RoleOperator,
+	astiencoderpb.Encoder_ContinueWorkflow_FullMethodName: astiencoder.RoleOperator,
+	astiencoderpb.Encoder_ReconfigureNode_FullMethodName:  astiencoder.RoleOperator,
+	astiencoderpb.Encoder_Reload_FullMethodName:           astiencoder.RoleAdmin,
+	astiencoderpb.Encoder_StreamStats_FullMethodName:      astiencoder.RoleViewer,
+	astiencoderpb.Encoder_StreamEvents_FullMethodName:     astiencoder.RoleViewer,
+}
+
+// ServerOptions represents server options
+type ServerOptions struct {
+	// Appended to the options of the gRPC server, e.g. to set keepalive parameters
+	GRPC []grpc.ServerOption
+	// If set, the server is served over TLS, and clients must present a certificate signed by its client CAs if
+	// they're set (mTLS)
+	TLS *astiencoder.TLSServerOptions
+}
+
+// NewServer creates a gRPC server serving the control service
+// Callers are rate limited and authenticated with the service's options, and authorized according to the role written
+// next to each rpc in proto/astiencoder.proto, see NewEncoderServer
+func NewServer(s *astiencoder.ControlService, o ServerOptions) (gs *grpc.Server, err error) {
+	// Add credentials
+	var gos []grpc.ServerOption
+	if o.TLS != nil {
+		var c *tls.Config
+		if c, err = o.TLS.Config(); err != nil {
+			err = fmt.Errorf("astigrpc: getting tls config failed: %w", err)
+			return
+		}
+		gos = append(gos, grpc.Creds(credentials.NewTLS(c)))
+	}
+
+	// Create server
+	gs = grpc.NewServer(append(gos, o.GRPC...)...)
+
+	// Register service
+	astiencoderpb.RegisterEncoderServer(gs, NewEncoderServer(s))
+	return
+}
+
+type encoderServer struct {
+	astiencoderpb.UnimplementedEncoderServer
+	s *astiencoder.ControlService
+}
+
+// NewEncoderServer returns the implementation of the Encoder service, for applications registering it on their own
+// gRPC server. Every rpc rate limits, authenticates and authorizes its caller before delegating to the control service,
+// and is logged if the control service logs requests, therefore it doesn't rely on interceptors
+// Rate limited callers get a RESOURCE_EXHAUSTED code and the number of seconds they have to wait in the retry-after
+// trailer
+func NewEncoderServer(s *astiencoder.ControlService) astiencoderpb.EncoderServer {
+	return &encoderServer{s: s}
+}
+
+// caller represents the caller of an rpc
+type caller struct {
+	actor      string
+	method     string
+	namespace  string
+	remoteAddr string
+	startedAt  time.Time
+}
+
+// authorize rate limits and authenticates the caller of the rpc, makes sure its role is allowed to call it and resolves
+// its namespace
+// The caller is returned even if it's not authorized so that the rpc can be logged
+func (s *encoderServer) authorize(ctx context.Context, method string) (c caller, err error) {
+	// Create caller
+	c = caller{
+		method:    method,
+		startedAt: time.Now(),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		c.remoteAddr = p.Addr.String()
+	}
+
+	// Rate limit before authenticating so that failed authentications are throttled as well
+	if retryAfter, errLimit := s.s.LimitRemoteAddr(c.remoteAddr); errLimit != nil {
+		err = newRateLimitedError(ctx, retryAfter, errLimit)
+		return
+	}
+
+	// Authenticate
+	md, _ := metadata.FromIncomingContext(ctx)
+	i, err := s.s.Authenticate(ctx, bearerToken(md), metadataValue(md, MetadataKeyActor))
+	if err != nil {
+		err = newStatusError(err)
+		return
+	}
+	c.actor = i.Name
+
+	// Authorize
+	if err = i.Authorize(roles[method]); err != nil {
+		err = newStatusError(err)
+		return
+	}
+
+	// Rate limit
+	if retryAfter, errLimit := s.s.LimitIdentity(i); errLimit != nil {
+		err = newRateLimitedError(ctx, retryAfter, errLimit)
+		return
+	}
+
+	// Get namespace
+	ns := metadataValue(md, MetadataKeyNamespace)
+	if i.Namespace != "" {
+		// Namespace is different
+		if ns != "" && ns != i.Namespace {
+			err = newStatusError(fmt.Errorf("astigrpc: %s is not allowed to access namespace %q: %w", i.Name, ns, astiencoder.ErrForbidden))
+			return
+		}
+		ns = i.Namespace
+	}
+
+	// Invalid namespace
+	if strings.Contains(ns, "/") {
+		err = status.Errorf(codes.InvalidArgument, "astigrpc: namespace %q can't contain /", ns)
+		return
+	}
+	c.namespace = ns
+	return
+}
+
+// newRateLimitedError tells the caller how long it has to wait in the retry-after trailer, in seconds
+func newRateLimitedError(ctx context.Context, retryAfter time.Duration, err error) error {
+	grpc.SetTrailer(ctx, metadata.Pairs(MetadataKeyRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+	return newStatusError(err)
+}
+
+// log logs the rpc once it's done
+func (s *encoderServer) log(c caller, err error) {
+	s.s.LogRequest(astiencoder.ControlRequestLog{
+		Actor:      c.actor,
+		Duration:   time.Since(c.startedAt),
+		Method:     "grpc",
+		Path:       c.method,
+		RemoteAddr: c.remoteAddr,
+		Status:     status.Code(err).String(),
+	})
+}
+
+// workflowName returns the name, as known by the control service, of a workflow of the caller's namespace
+func (c caller) workflowName(name string) string {
+	return astiencoder.NamespacedWorkflowName(c.namespace, name)
+}
+
+// relativeWorkflowName returns the name of a workflow relative to the caller's namespace and whether it belongs to it
+func (c caller) relativeWorkflowName(name string) (string, bool) {
+	if c.namespace == "" {
+		return name, true
+	}
+	ns, n := astiencoder.SplitNamespacedWorkflowName(name)
+	return n, ns == c.namespace
+}
+
+func bearerToken(md metadata.MD) string {
+	if v := metadataValue(md, MetadataKeyAuthorization); len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+		return strings.TrimSpace(v[7:])
+	}
+	return ""
+}
+
+func metadataValue(md metadata.MD, k string) string {
+	if vs := md.Get(k); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// newStatusError maps the errors of the control service to gRPC codes
+func newStatusError(err error) error {
+	var c codes.Code
+	var es astiencoder.DefinitionErrors
+	switch {
+	case errors.Is(err, astiencoder.ErrNodeNotFound), errors.Is(err, astiencoder.ErrWorkflowNotFound):
+		c = codes.NotFound
+	case errors.Is(err, astiencoder.ErrWorkflowAlreadyExists):
+		c = codes.AlreadyExists
+	case errors.Is(err, astiencoder.ErrWorkflowAlreadyStarted):
+		c = codes.FailedPrecondition
+	case errors.Is(err, astiencoder.ErrUnauthenticated):
+		c = codes.Unauthenticated
+	case errors.Is(err, astiencoder.ErrForbidden):
+		c = codes.PermissionDenied
+	case errors.Is(err, astiencoder.ErrQuotaExceeded), errors.Is(err, astiencoder.ErrRateLimited):
+		c = codes.ResourceExhausted
+	case errors.Is(err, astiencoder.ErrShuttingDown):
+		c = codes.Unavailable
+	case errors.As(err, &es):
+		c = codes.InvalidArgument
+	default:
+		c = codes.Internal
+	}
+	return status.Error(c, err.Error())
+}
+
+// CreateWorkflow implements the EncoderServer interface
+func (s *encoderServer) CreateWorkflow(ctx context.Context, r *astiencoderpb.CreateWorkflowRequest) (_ *astiencoderpb.Workflow, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, astiencoderpb.Encoder_CreateWorkflow_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode definition
+	var d astiencoder.WorkflowDefinition
+	if err = decodeStruct(r.GetDefinition(), &d); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "astigrpc: decoding definition failed: %s", err)
+	}
+
+	// Names are relative to the namespace
+	if strings.Contains(d.Name, "/") {
+		return nil, status.Errorf(codes.InvalidArgument, "astigrpc: name %s can't contain /", d.Name)
+	}
+	name := d.Name
+	d.Name = c.workflowName(name)
+
+	// Create workflow
+	w, err := s.s.CreateWorkflow(c.actor, d, r.GetValues().AsMap())
+	if err != nil {
+		return nil, newStatusError(err)
+	}
+	return &astiencoderpb.Workflow{
+		Name:   name,
+		Status: w.Status(),
+	}, nil
+}
+
+// DeleteWorkflow implements the EncoderServer interface
+func (s *encoderServer) DeleteWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (*emptypb.Empty, error) {
+	return s.controlWorkflow(ctx, astiencoderpb.Encoder_DeleteWorkflow_FullMethodName, r, s.s.DeleteWorkflow)
+}
+
+// GetWorkflow implements the EncoderServer interface
+func (s *encoderServer) GetWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (_ *astiencoderpb.WorkflowSnapshot, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, astiencoderpb.Encoder_GetWorkflow_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get workflow
+	w, err := s.s.Workflow(c.workflowName(r.GetName()))
+	if err != nil {
+		return nil, newStatusError(err)
+	}
+
+	// Create snapshot
+	ws, err := newWorkflowSnapshot(w.Snapshot())
+	if err != nil {
+		return nil, newStatusError(err)
+	}
+	ws.Name = r.GetName()
+	return ws, nil
+}
+
+// ListWorkflows implements the EncoderServer interface
+func (s *encoderServer) ListWorkflows(ctx context.Context, _ *emptypb.Empty) (_ *astiencoderpb.ListWorkflowsResponse, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, astiencoderpb.Encoder_ListWorkflows_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Loop through workflows
+	res := &astiencoderpb.ListWorkflowsResponse{}
+	for _, w := range s.s.Workflows() {
+		if name, ok := c.relativeWorkflowName(w.Name); ok {
+			res.Workflows = append(res.Workflows, &astiencoderpb.Workflow{
+				Name:   name,
+				Status: w.Status,
+			})
+		}
+	}
+	return res, nil
+}
+
+// StartWorkflow implements the EncoderServer interface
+func (s *encoderServer) StartWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (*emptypb.Empty, error) {
+	return s.controlWorkflow(ctx, astiencoderpb.Encoder_StartWorkflow_FullMethodName, r, s.s.StartWorkflow)
+}
+
+// StopWorkflow implements the EncoderServer interface
+func (s *encoderServer) StopWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (*emptypb.Empty, error) {
+	return s.controlWorkflow(ctx, astiencoderpb.Encoder_StopWorkflow_FullMethodName, r, s.s.StopWorkflow)
+}
+
+// PauseWorkflow implements the EncoderServer interface
+func (s *encoderServer) PauseWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (*emptypb.Empty, error) {
+	return s.controlWorkflow(ctx, astiencoderpb.Encoder_PauseWorkflow_FullMethodName, r, s.s.PauseWorkflow)
+}
+
+// ContinueWorkflow implements the EncoderServer interface
+func (s *encoderServer) ContinueWorkflow(ctx context.Context, r *astiencoderpb.WorkflowRequest) (*emptypb.Empty, error) {
+	return s.controlWorkflow(ctx, astiencoderpb.Encoder_ContinueWorkflow_FullMethodName, r, s.s.ContinueWorkflow)
+}
+
+func (s *encoderServer) controlWorkflow(ctx context.Context, method string, r *astiencoderpb.WorkflowRequest, fn func(actor, name string) error) (_ *emptypb.Empty, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, method)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Control
+	if err = fn(c.actor, c.workflowName(r.GetName())); err != nil {
+		return nil, newStatusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ReconfigureNode implements the EncoderServer interface
+func (s *encoderServer) ReconfigureNode(ctx context.Context, r *astiencoderpb.ReconfigureNodeRequest) (_ *emptypb.Empty, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, astiencoderpb.Encoder_ReconfigureNode_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconfigure
+	if err = s.s.ReconfigureNode(c.actor, c.workflowName(r.GetWorkflow()), r.GetNode(), r.GetOptions().AsMap()); err != nil {
+		return nil, newStatusError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Reload implements the EncoderServer interface
+func (s *encoderServer) Reload(ctx context.Context, _ *emptypb.Empty) (_ *astiencoderpb.ReloadResponse, err error) {
+	// Authorize
+	c, err := s.authorize(ctx, astiencoderpb.Encoder_Reload_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reloading touches the workflows of every namespace
+	if c.namespace != "" {
+		return nil, newStatusError(fmt.Errorf("astigrpc: namespace %q is not allowed to reload: %w", c.namespace, astiencoder.ErrForbidden))
+	}
+
+	// Reload
+	r, err := s.s.Reload(c.actor)
+	if err != nil {
+		return nil, newStatusError(err)
+	}
+
+	// Create response
+	res := &astiencoderpb.ReloadResponse{
+		Created:   r.Created,
+		Deleted:   r.Deleted,
+		Errors:    r.Errors,
+		Unchanged: r.Unchanged,
+	}
+	for name, cs := range r.Patched {
+		v, err := newValue(cs)
+		if err != nil {
+			return nil, newStatusError(err)
+		}
+		if res.Patched == nil {
+			res.Patched = make(map[string]*structpb.ListValue)
+		}
+		res.Patched[name] = v.GetListValue()
+	}
+	return res, nil
+}
+
+// StreamStats implements the EncoderServer interface
+func (s *encoderServer) StreamStats(r *astiencoderpb.WorkflowRequest, ss astiencoderpb.Encoder_StreamStatsServer) (err error) {
+	// Authorize
+	c, err := s.authorize(ss.Context(), astiencoderpb.Encoder_StreamStats_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return err
+	}
+
+	// Workflow doesn't exist
+	name := c.workflowName(r.GetName())
+	if _, err = s.s.Workflow(name); err != nil {
+		return newStatusError(err)
+	}
+
+	// Subscribe
+	return s.stream(ss, astiencoder.ControlSubscriptionOptions{
+		Names:     []string{astiencoder.EventNameNodeStats, astiencoder.EventNameWorkflowStats},
+		Namespace: c.namespace,
+		Workflow:  name,
+	}, func(e astiencoder.ControlEvent) error {
+		// Create stats
+		st := &astiencoderpb.Stats{
+			Node:     e.Node,
+			Workflow: e.Workflow,
+		}
+		if p, ok := e.Payload.(astiencoder.ServerStats); ok {
+			if st.Stats, err = newStats(p.Stats); err != nil {
+				return newStatusError(err)
+			}
+		}
+
+		// Send
+		return ss.Send(st)
+	})
+}
+
+// StreamEvents implements the EncoderServer interface
+func (s *encoderServer) StreamEvents(r *astiencoderpb.StreamEventsRequest, ss astiencoderpb.Encoder_StreamEventsServer) (err error) {
+	// Authorize
+	c, err := s.authorize(ss.Context(), astiencoderpb.Encoder_StreamEvents_FullMethodName)
+	defer func() { s.log(c, err) }()
+	if err != nil {
+		return err
+	}
+
+	// Create options
+	o := astiencoder.ControlSubscriptionOptions{
+		Names:     r.GetNames(),
+		Namespace: c.namespace,
+	}
+	if r.GetWorkflow() != "" {
+		o.Workflow = c.workflowName(r.GetWorkflow())
+	}
+
+	// Subscribe
+	return s.stream(ss, o, func(e astiencoder.ControlEvent) error {
+		// Create payload
+		p, err := newValue(e.Payload)
+		if err != nil {
+			return newStatusError(err)
+		}
+
+		// Send
+		return ss.Send(&astiencoderpb.Event{
+			Name:     e.Name,
+			Node:     e.Node,
+			Payload:  p,
+			Workflow: e.Workflow,
+		})
+	})
+}
+
+// stream sends the events of the subscription until the stream is done
+// Headers are sent once the subscription is ready so that clients know from when on they receive events
+func (s *encoderServer) stream(ss grpc.ServerStream, o astiencoder.ControlSubscriptionOptions, fn func(e astiencoder.ControlEvent) error) error {
+	// Subscribe
+	ch := s.s.Subscribe(ss.Context(), o)
+
+	// Send headers
+	if err := ss.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	// Loop through events
+	// The channel is closed once the stream's context is done
+	for e := range ch {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newValue converts a JSON friendly value to a protobuf value
+func newValue(i interface{}) (*structpb.Value, error) {
+	// Marshal
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil, fmt.Errorf("astigrpc: marshaling failed: %w", err)
+	}
+
+	// Unmarshal
+	var v structpb.Value
+	if err = v.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("astigrpc: unmarshaling failed: %w", err)
+	}
+	return &v, nil
+}
+
+// decodeStruct converts a protobuf struct to the JSON friendly value it represents
+func decodeStruct(s *structpb.Struct, v interface{}) error {
+	// Marshal
+	b, err := s.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("astigrpc: marshaling failed: %w", err)
+	}
+
+	// Unmarshal
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("astigrpc: unmarshaling failed: %w", err)
+	}
+	return nil
+}
+
+func newStats(ss []astiencoder.ServerStat) (ps []*astiencoderpb.Stat, err error) {
+	for _, s := range ss {
+		var v *structpb.Value
+		if v, err = newValue(s.Value); err != nil {
+			return
+		}
+		ps = append(ps, &astiencoderpb.Stat{
+			Description: s.Description,
+			Label:       s.Label,
+			Unit:        s.Unit,
+			Value:       v,
+		})
+	}
+	return
+}
+
+func newEventStats(es []astiencoder.EventStat) ([]*astiencoderpb.Stat, error) {
+	var ss []astiencoder.ServerStat
+	for _, e := range es {
+		ss = append(ss, astiencoder.ServerStat{
+			Description: e.Description,
+			Label:       e.Label,
+			Unit:        e.Unit,
+			Value:       e.Value,
+		})
+	}
+	return newStats(ss)
+}
+
+func newWorkflowSnapshot(s astiencoder.WorkflowSnapshot) (ws *astiencoderpb.WorkflowSnapshot, err error) {
+	// Create snapshot
+	ws = &astiencoderpb.WorkflowSnapshot{
+		At:     timestamppb.New(s.At),
+		Name:   s.Name,
+		Status: s.Status,
+	}
+	if ws.Stats, err = newEventStats(s.Stats); err != nil {
+		return
+	}
+
+	// Add progress
+	if s.Progress != nil {
+		ws.Progress = &astiencoderpb.Progress{
+			Duration:   s.Progress.Duration.Seconds(),
+			Eta:        s.Progress.ETA.Seconds(),
+			Position:   s.Progress.Position.Seconds(),
+			Ratio:      s.Progress.Ratio,
+			Throughput: s.Progress.Throughput,
+		}
+	}
+
+	// Loop through nodes
+	for _, n := range s.Nodes {
+		ns := &astiencoderpb.NodeSnapshot{
+			Children:    n.Children,
+			Description: n.Metadata.Description,
+			Label:       n.Metadata.Label,
+			Name:        n.Metadata.Name,
+			Parents:     n.Parents,
+			Status:      n.Status,
+			Tags:        n.Metadata.Tags,
+		}
+		if ns.Stats, err = newEventStats(n.Stats); err != nil {
+			return
+		}
+		ws.Nodes = append(ws.Nodes, ns)
+	}
+	return
+}
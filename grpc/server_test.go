@@ -0,0 +1,275 @@
+package astigrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astiencoder/grpc/astiencoderpb"
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type testTLSCertificate struct {
+	c        *x509.Certificate
+	certFile string
+	k        *ecdsa.PrivateKey
+	keyFile  string
+}
+
+// newTestTLSCertificate creates a certificate signed by parent, or a CA if parent is nil
+func newTestTLSCertificate(t *testing.T, dir, name string, parent *testTLSCertificate) testTLSCertificate {
+	// Create key
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	// Create template
+	tpl := &x509.Certificate{
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(time.Hour),
+		NotBefore:    time.Now().Add(-time.Hour),
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+	}
+	signer, signerKey := tpl, k
+	if parent == nil {
+		tpl.BasicConstraintsValid = true
+		tpl.IsCA = true
+		tpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.c, parent.k
+	}
+
+	// Create certificate
+	b, err := x509.CreateCertificate(rand.Reader, tpl, signer, &k.PublicKey, signerKey)
+	assert.NoError(t, err)
+	c, err := x509.ParseCertificate(b)
+	assert.NoError(t, err)
+
+	// Write files
+	kb, err := x509.MarshalECPrivateKey(k)
+	assert.NoError(t, err)
+	tc := testTLSCertificate{
+		c:        c,
+		certFile: filepath.Join(dir, name+".crt"),
+		k:        k,
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	assert.NoError(t, ioutil.WriteFile(tc.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}), 0600))
+	assert.NoError(t, ioutil.WriteFile(tc.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0600))
+	return tc
+}
+
+type mockedNode struct {
+	*astiencoder.BaseNode
+}
+
+func (n *mockedNode) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), MetadataKeyAuthorization, "Bearer "+token)
+}
+
+func TestServer(t *testing.T) {
+	// Create certificates
+	dir, err := ioutil.TempDir("", "astigrpc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca := newTestTLSCertificate(t, dir, "ca", nil)
+	server := newTestTLSCertificate(t, dir, "server", &ca)
+	client := newTestTLSCertificate(t, dir, "client", &ca)
+
+	// Register types
+	ts := astiencoder.NewNodeTypes()
+	ts.Register("t", astiencoder.NodeType{New: func(b astiencoder.NodeBuild) (astiencoder.Node, error) {
+		n := &mockedNode{}
+		n.BaseNode = astiencoder.NewBaseNode(b.Node, astiencoder.NewEventGeneratorNode(n), b.EventHandler)
+		return n, nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	cs := astiencoder.NewControlService(astiencoder.ControlServiceOptions{
+		Authenticator: astiencoder.NewAPIKeyAuthenticator(map[string]astiencoder.Identity{
+			"admin":  {Name: "alice", Role: astiencoder.RoleAdmin},
+			"team-a": {Name: "bob", Namespace: "a", Role: astiencoder.RoleOperator},
+			"viewer": {Name: "carol", Role: astiencoder.RoleViewer},
+		}),
+		Build: astiencoder.BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+	})
+
+	// Invalid TLS options
+	_, err = NewServer(cs, ServerOptions{TLS: &astiencoder.TLSServerOptions{CertFile: server.certFile}})
+	assert.Error(t, err)
+
+	// Serve
+	s, err := NewServer(cs, ServerOptions{TLS: &astiencoder.TLSServerOptions{
+		CertFile:     server.certFile,
+		ClientCAFile: ca.certFile,
+		KeyFile:      server.keyFile,
+	}})
+	assert.NoError(t, err)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go s.Serve(l)
+	defer s.Stop()
+
+	// Clients must present a certificate
+	dial := func(o astiencoder.TLSClientOptions) astiencoderpb.EncoderClient {
+		c, err := o.Config()
+		assert.NoError(t, err)
+		cc, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(c)))
+		assert.NoError(t, err)
+		t.Cleanup(func() { cc.Close() })
+		return astiencoderpb.NewEncoderClient(cc)
+	}
+	_, err = dial(astiencoder.TLSClientOptions{CAFile: ca.certFile}).ListWorkflows(withToken("admin"), &emptypb.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	c := dial(astiencoder.TLSClientOptions{CAFile: ca.certFile, CertFile: client.certFile, KeyFile: client.keyFile})
+
+	// Callers must be authenticated and authorized
+	_, err = c.ListWorkflows(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = c.ListWorkflows(withToken("invalid"), &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	d, err := structpb.NewStruct(map[string]interface{}{
+		"name":    "w",
+		"nodes":   []interface{}{map[string]interface{}{"name": "n", "type": "t"}},
+		"version": astiencoder.DefinitionVersion,
+	})
+	assert.NoError(t, err)
+	_, err = c.CreateWorkflow(withToken("viewer"), &astiencoderpb.CreateWorkflowRequest{Definition: d})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Workflows
+	w, err := c.CreateWorkflow(withToken("admin"), &astiencoderpb.CreateWorkflowRequest{Definition: d})
+	assert.NoError(t, err)
+	assert.Equal(t, "w", w.Name)
+	_, err = c.CreateWorkflow(withToken("admin"), &astiencoderpb.CreateWorkflowRequest{Definition: d})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	ws, err := c.ListWorkflows(withToken("viewer"), &emptypb.Empty{})
+	assert.NoError(t, err)
+	assert.Len(t, ws.Workflows, 1)
+	assert.Equal(t, "w", ws.Workflows[0].Name)
+	ss, err := c.GetWorkflow(withToken("viewer"), &astiencoderpb.WorkflowRequest{Name: "w"})
+	assert.NoError(t, err)
+	assert.Len(t, ss.Nodes, 1)
+	assert.Equal(t, "n", ss.Nodes[0].Name)
+
+	// Callers are scoped to their namespace
+	ws, err = c.ListWorkflows(withToken("team-a"), &emptypb.Empty{})
+	assert.NoError(t, err)
+	assert.Len(t, ws.Workflows, 0)
+	_, err = c.PauseWorkflow(withToken("team-a"), &astiencoderpb.WorkflowRequest{Name: "w"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = c.ListWorkflows(metadata.AppendToOutgoingContext(withToken("team-a"), MetadataKeyNamespace, "b"), &emptypb.Empty{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Events are streamed once the headers have been received
+	ctx, cancel := context.WithCancel(withToken("viewer"))
+	defer cancel()
+	es, err := c.StreamEvents(ctx, &astiencoderpb.StreamEventsRequest{Names: []string{astiencoder.EventNameAudit}, Workflow: "w"})
+	assert.NoError(t, err)
+	_, err = es.Header()
+	assert.NoError(t, err)
+	_, err = c.PauseWorkflow(withToken("admin"), &astiencoderpb.WorkflowRequest{Name: "w"})
+	assert.NoError(t, err)
+	e, err := es.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, astiencoder.EventNameAudit, e.Name)
+	assert.Equal(t, "w", e.Workflow)
+	assert.Equal(t, "alice", e.Payload.GetStructValue().AsMap()["actor"])
+
+	// Nodes
+	_, err = c.ReconfigureNode(withToken("admin"), &astiencoderpb.ReconfigureNodeRequest{Workflow: "w", Node: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type mockedLogger struct {
+	m    *sync.Mutex
+	rpcs []string
+}
+
+func (l *mockedLogger) Debug(msg string, fields ...astiencoder.LogField) {}
+func (l *mockedLogger) Error(msg string, fields ...astiencoder.LogField) {}
+func (l *mockedLogger) Warn(msg string, fields ...astiencoder.LogField)  {}
+
+func (l *mockedLogger) Info(msg string, fields ...astiencoder.LogField) {
+	fs := make(map[string]interface{})
+	for _, f := range fields {
+		fs[f.Key] = f.Value
+	}
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.rpcs = append(l.rpcs, fmt.Sprintf("%s %s %s", fs["path"], fs["status"], fs["actor"]))
+}
+
+func TestServerRateLimit(t *testing.T) {
+	// Create service
+	ml := &mockedLogger{m: &sync.Mutex{}}
+	cs := astiencoder.NewControlService(astiencoder.ControlServiceOptions{
+		Authenticator: astiencoder.NewAPIKeyAuthenticator(map[string]astiencoder.Identity{
+			"viewer": {Name: "carol", Role: astiencoder.RoleViewer},
+		}),
+		Logger:      ml,
+		LogRequests: true,
+		RateLimit:   &astiencoder.ControlRateLimit{Burst: 2, Rate: 0.01},
+	})
+
+	// Serve
+	s, err := NewServer(cs, ServerOptions{})
+	assert.NoError(t, err)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go s.Serve(l)
+	defer s.Stop()
+	cc, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer cc.Close()
+	c := astiencoderpb.NewEncoderClient(cc)
+
+	// Failed authentications are rate limited as well
+	_, err = c.ListWorkflows(withToken("viewer"), &emptypb.Empty{})
+	assert.NoError(t, err)
+	_, err = c.ListWorkflows(withToken("invalid"), &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	var md metadata.MD
+	_, err = c.ListWorkflows(withToken("invalid"), &emptypb.Empty{}, grpc.Trailer(&md))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"100"}, md.Get(MetadataKeyRetryAfter))
+
+	// Rpcs are logged
+	ml.m.Lock()
+	defer ml.m.Unlock()
+	assert.Equal(t, []string{
+		astiencoderpb.Encoder_ListWorkflows_FullMethodName + " OK carol",
+		astiencoderpb.Encoder_ListWorkflows_FullMethodName + " Unauthenticated ",
+		astiencoderpb.Encoder_ListWorkflows_FullMethodName + " ResourceExhausted ",
+	}, ml.rpcs)
+}
@@ -0,0 +1,224 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Health default options
+const (
+	HealthDefaultCriticalTag = "critical"
+	HealthDefaultOutputTag   = "muxer"
+)
+
+// Label of the stat used to know whether an output node is writing
+const healthOutputStatLabel = "Incoming rate"
+
+// HealthOptions represents health options
+type HealthOptions struct {
+	// Nodes with this tag must not be stopped while their workflow is running. Default is "critical"
+	CriticalTag string
+	// If > 0, the health check fails if an error has been emitted in this window
+	ErrorWindow time.Duration
+	// If > 0, the health check fails if a running node with one of the output tags hasn't received anything in this
+	// window
+	OutputWindow time.Duration
+	// Default is "muxer"
+	OutputTags []string
+}
+
+// Health represents an object capable of checking the health of workflows and nodes based on their events
+// Its handler is suitable for Kubernetes liveness and readiness probes
+type Health struct {
+	lastErrorAt time.Time
+	m           *sync.Mutex
+	now         func() time.Time
+	ns          map[Node]*healthNode
+	o           HealthOptions
+	ws          map[string]string
+}
+
+type healthNode struct {
+	critical     bool
+	lastOutputAt time.Time
+	name         string
+	output       bool
+	status       string
+	workflow     string
+}
+
+// HealthReport represents a health report
+type HealthReport struct {
+	Healthy bool     `json:"healthy"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// NewHealth creates a new health fed by the events of the event handler
+func NewHealth(o HealthOptions, eh *EventHandler) (h *Health) {
+	// Default options
+	if o.CriticalTag == "" {
+		o.CriticalTag = HealthDefaultCriticalTag
+	}
+	if len(o.OutputTags) == 0 {
+		o.OutputTags = []string{HealthDefaultOutputTag}
+	}
+
+	// Create health
+	h = &Health{
+		m:   &sync.Mutex{},
+		now: time.Now,
+		ns:  make(map[Node]*healthNode),
+		o:   o,
+		ws:  make(map[string]string),
+	}
+
+	// Handle events
+	eh.AddForAll(func(e Event) bool {
+		h.handleEvent(e)
+		return false
+	})
+	return
+}
+
+func (h *Health) handleEvent(e Event) {
+	// Lock
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	// Errors are taken into account whatever their target
+	if e.Name == EventNameError {
+		h.lastErrorAt = h.now()
+		return
+	}
+
+	// Switch on target
+	switch t := e.Target.(type) {
+	case *Workflow:
+		switch e.Name {
+		case EventNameWorkflowContinued, EventNameWorkflowStarted:
+			h.ws[t.Name()] = StatusRunning
+		case EventNameWorkflowPaused:
+			h.ws[t.Name()] = StatusPaused
+		case EventNameWorkflowStopped:
+			h.ws[t.Name()] = StatusStopped
+		}
+	case Node:
+		// Get node
+		n, ok := h.ns[t]
+		if !ok {
+			// Node is neither critical nor an output
+			if n = h.newNode(t); n == nil {
+				return
+			}
+			h.ns[t] = n
+		}
+
+		// Switch on event name
+		switch e.Name {
+		case EventNameNodeContinued:
+			n.status = StatusRunning
+		case EventNameNodePaused:
+			n.status = StatusPaused
+		case EventNameNodeStarted:
+			// Starting counts as output activity so that the output window is a grace period
+			n.lastOutputAt = h.now()
+			n.status = StatusRunning
+			n.workflow = nodeWorkflowName(t)
+		case EventNameNodeStats:
+			for _, s := range e.Payload.([]EventStat) {
+				if v, ok := s.Value.(float64); ok && s.Label == healthOutputStatLabel && v > 0 {
+					n.lastOutputAt = h.now()
+				}
+			}
+		case EventNameNodeStopped:
+			n.status = StatusStopped
+		}
+	}
+}
+
+func (h *Health) newNode(t Node) *healthNode {
+	// Get tags
+	n := &healthNode{name: t.Metadata().Name}
+	for _, tag := range t.Metadata().Tags {
+		if tag == h.o.CriticalTag {
+			n.critical = true
+		}
+		for _, o := range h.o.OutputTags {
+			if tag == o {
+				n.output = true
+			}
+		}
+	}
+
+	// Node is irrelevant
+	if !n.critical && !n.output {
+		return nil
+	}
+	return n
+}
+
+// Check checks the health
+func (h *Health) Check() (r HealthReport) {
+	// Lock
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	// Check errors
+	now := h.now()
+	if h.o.ErrorWindow > 0 && !h.lastErrorAt.IsZero() && now.Sub(h.lastErrorAt) < h.o.ErrorWindow {
+		r.Reasons = append(r.Reasons, fmt.Sprintf("an error occurred %s ago", now.Sub(h.lastErrorAt).Round(time.Millisecond)))
+	}
+
+	// Loop through nodes
+	var reasons []string
+	for _, n := range h.ns {
+		// Workflow is not running
+		if ws, ok := h.ws[n.workflow]; n.workflow != "" && (!ok || ws != StatusRunning) {
+			continue
+		}
+
+		// Check critical node
+		if n.critical && n.status == StatusStopped {
+			reasons = append(reasons, fmt.Sprintf("critical node %s is stopped", h.nodeName(n)))
+		}
+
+		// Check output node
+		if n.output && n.status == StatusRunning && h.o.OutputWindow > 0 && now.Sub(n.lastOutputAt) >= h.o.OutputWindow {
+			reasons = append(reasons, fmt.Sprintf("output node %s hasn't received anything for %s", h.nodeName(n), now.Sub(n.lastOutputAt).Round(time.Millisecond)))
+		}
+	}
+
+	// Sort reasons so that reports are deterministic
+	sort.Strings(reasons)
+	r.Reasons = append(r.Reasons, reasons...)
+	r.Healthy = len(r.Reasons) == 0
+	return
+}
+
+func (h *Health) nodeName(n *healthNode) string {
+	if n.workflow == "" {
+		return n.name
+	}
+	return n.workflow + "/" + n.name
+}
+
+// Handler returns the handler writing the health report with a 200 status code if healthy and a 503 status code
+// otherwise
+func (h *Health) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Check
+		hr := h.Check()
+
+		// Write
+		rw.Header().Set("Content-Type", "application/json")
+		if !hr.Healthy {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+		// There's nothing left to do if writing fails since the status code has already been written
+		json.NewEncoder(rw).Encode(hr)
+	})
+}
@@ -0,0 +1,68 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	eh := NewEventHandler()
+	h := NewHealth(HealthOptions{
+		ErrorWindow:  10 * time.Second,
+		OutputWindow: 5 * time.Second,
+	}, eh)
+	now := time.Unix(100, 0)
+	h.now = func() time.Time { return now }
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	ctx := withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+	n1 := newMockedStatsNode("n1", eh)
+	n1.o.Metadata.Tags = []string{HealthDefaultCriticalTag}
+	n1.ctx = ctx
+	n2 := newMockedStatsNode("n2", eh)
+	n2.o.Metadata.Tags = []string{HealthDefaultOutputTag}
+	n2.ctx = ctx
+	n3 := newMockedStatsNode("n3", eh)
+	n3.ctx = ctx
+
+	h.handleEvent(Event{Name: EventNameWorkflowStarted, Target: w})
+	h.handleEvent(Event{Name: EventNameNodeStarted, Target: n1})
+	h.handleEvent(Event{Name: EventNameNodeStarted, Target: n2})
+	h.handleEvent(Event{Name: EventNameNodeStarted, Target: n3})
+	h.handleEvent(Event{Name: EventNameNodeStopped, Target: n3})
+	assert.Equal(t, HealthReport{Healthy: true}, h.Check())
+
+	now = now.Add(4 * time.Second)
+	h.handleEvent(Event{Name: EventNameNodeStats, Payload: []EventStat{{Label: healthOutputStatLabel, Value: 25.0}}, Target: n2})
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, HealthReport{Healthy: true}, h.Check())
+
+	h.handleEvent(Event{Name: EventNameNodeStats, Payload: []EventStat{{Label: healthOutputStatLabel, Value: 0.0}}, Target: n2})
+	h.handleEvent(EventError(n3, errors.New("test")))
+	h.handleEvent(Event{Name: EventNameNodeStopped, Target: n1})
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, HealthReport{Reasons: []string{
+		"an error occurred 2s ago",
+		"critical node w/n1 is stopped",
+		"output node w/n2 hasn't received anything for 6s",
+	}}, h.Check())
+
+	rw := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	h.handleEvent(Event{Name: EventNameWorkflowStopped, Target: w})
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, HealthReport{Healthy: true}, h.Check())
+
+	rw = httptest.NewRecorder()
+	h.Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "{\"healthy\":true}\n", rw.Body.String())
+}
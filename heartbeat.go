@@ -0,0 +1,40 @@
+package astiencoder
+
+import (
+	"context"
+	"time"
+)
+
+// WorkflowHeartbeat represents a workflow heartbeat
+// It is the payload of the EventNameWorkflowHeartbeat event
+type WorkflowHeartbeat struct {
+	At     time.Time
+	Status string
+	// Time elapsed since the workflow has been started, pauses included
+	Uptime time.Duration
+}
+
+func (w *Workflow) startHeartbeat(ctx context.Context, period time.Duration) {
+	// Create ticker
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			w.e.Emit(Event{Name: EventNameWorkflowHeartbeat, Payload: w.heartbeat(), Target: w})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Workflow) heartbeat() WorkflowHeartbeat {
+	now := time.Now()
+	return WorkflowHeartbeat{
+		At:     now,
+		Status: w.Status(),
+		Uptime: now.Sub(w.ck.started()),
+	}
+}
@@ -0,0 +1,40 @@
+package astiencoder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowHeartbeat(t *testing.T) {
+	eh := NewEventHandler()
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	w := NewWorkflow(wk.Context(), "w", eh, wk.NewTask, astikit.NewCloser())
+	n := &mockedTracedNode{
+		mockedStatsNode: newMockedStatsNode("n", eh),
+		started:         make(chan struct{}),
+	}
+	w.AddChild(n)
+	ch := make(chan WorkflowHeartbeat, 1)
+	eh.AddForEventName(EventNameWorkflowHeartbeat, func(e Event) bool {
+		select {
+		case ch <- e.Payload.(WorkflowHeartbeat):
+		default:
+		}
+		return false
+	})
+	w.StartWithOptions(WorkflowStartOptions{HeartbeatPeriod: time.Millisecond})
+	select {
+	case h := <-ch:
+		assert.False(t, h.At.IsZero())
+		assert.Equal(t, StatusRunning, h.Status)
+		assert.True(t, h.Uptime > 0)
+	case <-time.After(time.Second):
+		t.Error("no heartbeat emitted")
+	}
+	w.Stop()
+	wk.Stop()
+	wk.Wait()
+}
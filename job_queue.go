@@ -0,0 +1,755 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Job statuses
+const (
+	JobStatusCancelled = "cancelled"
+	JobStatusFailed    = "failed"
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+)
+
+// Job represents a workflow definition waiting to be run by a job queue
+type Job struct {
+	Definition WorkflowDefinition
+	// Jobs with the highest priority run first. Jobs with the same priority run in the order they've been added
+	Priority int
+	// Values of the definition's variables
+	Values map[string]interface{}
+}
+
+// JobState represents the state of a job
+// It is the payload of the EventNameJobUpdated event whose target is the job queue
+type JobState struct {
+	AddedAt time.Time
+	// Number of times the job has been run
+	Attempts int
+	// Last error
+	Error    string
+	ID       string
+	Name     string
+	Priority int
+	// Zero if the job has not been run yet
+	StartedAt time.Time
+	Status    string
+	// Zero if the job is not done yet
+	StoppedAt time.Time
+}
+
+func (s JobState) done() bool {
+	return s.Status == JobStatusCancelled || s.Status == JobStatusFailed || s.Status == JobStatusSucceeded
+}
+
+// JobRetryPolicy represents a job retry policy
+type JobRetryPolicy struct {
+	// Delay before a failed job is run again
+	Delay time.Duration
+	// Max number of times a failed job is run again. 0 means failed jobs are not run again
+	MaxRetries int
+}
+
+const jobQueueHistorySizeDefault = 100
+
+// JobQueueOptions represents job queue options
+type JobQueueOptions struct {
+	// If set, called when a running job is interrupted by Shutdown. The data it returns is saved as the job's
+	// checkpoint, which requires a store
+	Checkpoint func(s JobState, w *Workflow) (map[string]interface{}, error)
+	// Max number of jobs running at the same time. Default is 1
+	Concurrency int
+	// Max number of done jobs whose state is kept. Default is 100
+	HistorySize int
+	Retry       JobRetryPolicy
+	// Provided to the nodes
+	SecretProvider SecretProvider
+	// If set, jobs and their checkpoints are persisted every time they change and can be recovered with Restore
+	Store Store
+	// Used to substitute references to environment variables and secrets in the definitions
+	Substitution *SubstitutionOptions
+	// Used to create the workflows' tasks. Required
+	TaskFunc CreateTaskFunc
+	// Used to build the workflows
+	Types *NodeTypes
+}
+
+// JobQueue represents an object capable of running workflow definitions as jobs, up to a number of them at the same
+// time and by priority
+// A job fails if its workflow can't be built or if an error is emitted while it's running
+// Jobs are persisted without the lock held so that a slow store doesn't stall the queue
+type JobQueue struct {
+	c       *sync.Cond
+	closing bool
+	eh      *EventHandler
+	idx     int
+	js      []*jobQueueJob
+	mp      *sync.Mutex // Makes sure store operations are performed one at a time
+	o       JobQueueOptions
+	now     func() time.Time
+	ops     []jobQueueStoreOperation // Locked by c.L
+	runs    int
+}
+
+// jobQueueStoreOperation represents a store operation queued with the lock held and performed without it, in the order
+// it has been queued
+type jobQueueStoreOperation struct {
+	// Only set when the job is deleted
+	delID string
+	j     StoredJob
+}
+
+type jobQueueJob struct {
+	cancel      context.CancelFunc
+	cancelled   bool
+	interrupted bool
+	j           Job
+	notBefore   time.Time
+	s           JobState
+	seq         int
+	w           *Workflow
+}
+
+// NewJobQueue creates a new job queue
+func NewJobQueue(o JobQueueOptions, eh *EventHandler) *JobQueue {
+	// Default options
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.HistorySize <= 0 {
+		o.HistorySize = jobQueueHistorySizeDefault
+	}
+
+	// Create queue
+	return &JobQueue{
+		c:   sync.NewCond(&sync.Mutex{}),
+		eh:  eh,
+		mp:  &sync.Mutex{},
+		o:   o,
+		now: time.Now,
+	}
+}
+
+// Add adds a job to the queue and returns its id
+func (q *JobQueue) Add(j Job) string {
+	// Lock
+	q.c.L.Lock()
+
+	// Create job
+	q.idx++
+	qj := &jobQueueJob{
+		j: j,
+		s: JobState{
+			AddedAt:  q.now(),
+			ID:       strconv.Itoa(q.idx),
+			Name:     j.Definition.Name,
+			Priority: j.Priority,
+			Status:   JobStatusPending,
+		},
+		seq: q.idx,
+	}
+	q.js = append(q.js, qj)
+	s := qj.s
+	q.put(qj)
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Persist
+	errs := q.persist()
+
+	// Emit
+	q.emit(s, errs)
+	return s.ID
+}
+
+// ErrJobNotFound is wrapped by the error returned when cancelling a job that doesn't exist or is done
+var ErrJobNotFound = errors.New("astiencoder: job not found")
+
+// Cancel cancels a pending job or stops a running job
+func (q *JobQueue) Cancel(id string) (err error) {
+	// Lock
+	q.c.L.Lock()
+
+	// Get job
+	var qj *jobQueueJob
+	for _, v := range q.js {
+		if v.s.ID == id {
+			qj = v
+			break
+		}
+	}
+
+	// Job doesn't exist
+	if qj == nil {
+		q.c.L.Unlock()
+		return fmt.Errorf("astiencoder: job %s doesn't exist: %w", id, ErrJobNotFound)
+	}
+
+	// Job is done
+	if qj.s.done() {
+		q.c.L.Unlock()
+		return fmt.Errorf("astiencoder: job %s is %s: %w", id, qj.s.Status, ErrJobNotFound)
+	}
+
+	// Job is running
+	qj.cancelled = true
+	if qj.s.Status == JobStatusRunning {
+		qj.cancel()
+		q.c.L.Unlock()
+		return
+	}
+
+	// Job is pending
+	qj.s.Status = JobStatusCancelled
+	qj.s.StoppedAt = q.now()
+	s := qj.s
+	q.put(qj)
+	q.trim()
+	q.c.L.Unlock()
+
+	// Persist
+	errs := q.persist()
+
+	// Emit
+	q.emit(s, errs)
+	return
+}
+
+// Jobs returns the state of the jobs, in the order they've been added
+func (q *JobQueue) Jobs() (ss []JobState) {
+	q.c.L.Lock()
+	defer q.c.L.Unlock()
+	for _, qj := range q.js {
+		ss = append(ss, qj.s)
+	}
+	return
+}
+
+// Restore loads the jobs persisted in the store so that a restarted process can recover its job list. It must be
+// called before jobs are added and before the queue is started
+// Jobs that were running when the process stopped are pending again and run like the other pending jobs
+func (q *JobQueue) Restore() (err error) {
+	// No store
+	if q.o.Store == nil {
+		err = errors.New("astiencoder: job queue has no store")
+		return
+	}
+
+	// Get jobs
+	var sjs []StoredJob
+	if sjs, err = q.o.Store.Jobs(); err != nil {
+		err = fmt.Errorf("astiencoder: getting jobs from store failed: %w", err)
+		return
+	}
+
+	// Lock
+	q.c.L.Lock()
+
+	// Queue is not empty
+	if len(q.js) > 0 {
+		q.c.L.Unlock()
+		err = errors.New("astiencoder: job queue is not empty")
+		return
+	}
+
+	// Loop through jobs
+	var ss []JobState
+	for _, sj := range sjs {
+		// Create job
+		seq, _ := strconv.Atoi(sj.State.ID)
+		qj := &jobQueueJob{
+			j:   sj.Job,
+			s:   sj.State,
+			seq: seq,
+		}
+		if seq > q.idx {
+			q.idx = seq
+		}
+		q.js = append(q.js, qj)
+
+		// Job was running
+		if qj.s.Status == JobStatusRunning {
+			qj.s.Status = JobStatusPending
+			ss = append(ss, qj.s)
+			q.put(qj)
+		}
+	}
+	q.trim()
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Persist
+	errs := q.persist()
+
+	// Emit
+	for idx, s := range ss {
+		if idx > 0 {
+			errs = nil
+		}
+		q.emit(s, errs)
+	}
+	return
+}
+
+// SaveCheckpoint persists the progress of a job so that it can be resumed after a restart. It requires a store
+func (q *JobQueue) SaveCheckpoint(id string, data map[string]interface{}) (err error) {
+	// No store
+	if q.o.Store == nil {
+		return errors.New("astiencoder: job queue has no store")
+	}
+
+	// Lock
+	q.c.L.Lock()
+
+	// Get job
+	var qj *jobQueueJob
+	for _, v := range q.js {
+		if v.s.ID == id {
+			qj = v
+			break
+		}
+	}
+
+	// Job doesn't exist
+	if qj == nil {
+		q.c.L.Unlock()
+		return fmt.Errorf("astiencoder: job %s doesn't exist", id)
+	}
+
+	// Job is done
+	if qj.s.done() {
+		q.c.L.Unlock()
+		return fmt.Errorf("astiencoder: job %s is %s", id, qj.s.Status)
+	}
+	q.c.L.Unlock()
+
+	// Put checkpoint
+	if err = q.o.Store.PutCheckpoint(JobCheckpoint{
+		Data:    data,
+		JobID:   id,
+		SavedAt: q.now(),
+	}); err != nil {
+		err = fmt.Errorf("astiencoder: putting checkpoint of job %s in store failed: %w", id, err)
+		return
+	}
+	return
+}
+
+// Checkpoint returns the last checkpoint saved for a job. ok is false if the job has none
+func (q *JobQueue) Checkpoint(id string) (c JobCheckpoint, ok bool, err error) {
+	// No store
+	if q.o.Store == nil {
+		err = errors.New("astiencoder: job queue has no store")
+		return
+	}
+
+	// Get checkpoint
+	if c, ok, err = q.o.Store.GetCheckpoint(id); err != nil {
+		err = fmt.Errorf("astiencoder: getting checkpoint of job %s from store failed: %w", id, err)
+		return
+	}
+	return
+}
+
+// Start runs jobs until the context is done, in which case running jobs are stopped, or until the queue is shut down
+func (q *JobQueue) Start(ctx context.Context) {
+	// Wake up when context is done
+	go func() {
+		<-ctx.Done()
+		q.c.L.Lock()
+		q.c.Broadcast()
+		q.c.L.Unlock()
+	}()
+
+	// Loop
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	for {
+		// Wait for a job
+		q.c.L.Lock()
+		var qj *jobQueueJob
+		for ctx.Err() == nil && !q.closing {
+			if q.runs < q.o.Concurrency {
+				var wakeAt time.Time
+				if qj, wakeAt = q.next(); qj != nil {
+					break
+				} else if !wakeAt.IsZero() {
+					// Wake up once the retry delay is over
+					t := time.AfterFunc(wakeAt.Sub(q.now()), func() {
+						q.c.L.Lock()
+						q.c.Broadcast()
+						q.c.L.Unlock()
+					})
+					q.c.Wait()
+					t.Stop()
+					continue
+				}
+			}
+			q.c.Wait()
+		}
+
+		// Context is done or queue is shutting down
+		if ctx.Err() != nil || q.closing {
+			q.c.L.Unlock()
+			return
+		}
+
+		// Update job
+		jobCtx, cancel := context.WithCancel(ctx)
+		qj.cancel = cancel
+		qj.s.Attempts++
+		qj.s.StartedAt = q.now()
+		qj.s.Status = JobStatusRunning
+		s := qj.s
+		q.put(qj)
+		q.runs++
+		q.c.L.Unlock()
+
+		// Persist
+		errs := q.persist()
+
+		// Emit
+		q.emit(s, errs)
+
+		// Run job
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+			q.done(qj, q.run(jobCtx, qj))
+		}()
+	}
+}
+
+// Shutdown stops running new jobs and waits for the running ones to be done within the context's deadline. Running
+// jobs that are not done by then are interrupted: their checkpoint is saved with the Checkpoint option, they're
+// stopped and they're pending again so that they're resumed once restored by the next process
+// Jobs added afterwards stay pending and are persisted if there's a store
+func (q *JobQueue) Shutdown(ctx context.Context) (err error) {
+	// Stop running new jobs
+	q.c.L.Lock()
+	q.closing = true
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Wake up when context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.c.L.Lock()
+			q.c.Broadcast()
+			q.c.L.Unlock()
+		case <-done:
+		}
+	}()
+
+	// Wait for running jobs to be done
+	q.c.L.Lock()
+	for q.runs > 0 && ctx.Err() == nil {
+		q.c.Wait()
+	}
+
+	// Running jobs are done
+	if q.runs == 0 {
+		q.c.L.Unlock()
+		return
+	}
+
+	// Get running jobs
+	var qjs []*jobQueueJob
+	for _, qj := range q.js {
+		if qj.s.Status == JobStatusRunning {
+			qjs = append(qjs, qj)
+		}
+	}
+	q.c.L.Unlock()
+
+	// Loop through running jobs
+	for _, qj := range qjs {
+		// Save checkpoint
+		q.c.L.Lock()
+		s, w := qj.s, qj.w
+		q.c.L.Unlock()
+		if q.o.Checkpoint != nil && w != nil {
+			if data, errCheckpoint := q.o.Checkpoint(s, w); errCheckpoint != nil {
+				q.eh.Emit(EventError(q, fmt.Errorf("astiencoder: getting checkpoint of job %s failed: %w", s.ID, errCheckpoint)))
+			} else if errCheckpoint = q.SaveCheckpoint(s.ID, data); errCheckpoint != nil {
+				q.eh.Emit(EventError(q, errCheckpoint))
+			}
+		}
+
+		// Interrupt job
+		q.c.L.Lock()
+		if qj.s.Status == JobStatusRunning && !qj.cancelled {
+			qj.interrupted = true
+			qj.cancel()
+		}
+		q.c.L.Unlock()
+	}
+
+	// Wait for interrupted jobs to be stopped
+	q.c.L.Lock()
+	for q.runs > 0 {
+		q.c.Wait()
+	}
+	q.c.L.Unlock()
+	err = fmt.Errorf("astiencoder: %d job(s) have been interrupted: %w", len(qjs), ctx.Err())
+	return
+}
+
+// next must be called with the lock held. It returns the pending job to run next or, if pending jobs are waiting
+// for their retry delay to be over, the time at which the first one can be run
+func (q *JobQueue) next() (qj *jobQueueJob, wakeAt time.Time) {
+	now := q.now()
+	for _, v := range q.js {
+		// Invalid status
+		if v.s.Status != JobStatusPending {
+			continue
+		}
+
+		// Retry delay is not over
+		if v.notBefore.After(now) {
+			if wakeAt.IsZero() || v.notBefore.Before(wakeAt) {
+				wakeAt = v.notBefore
+			}
+			continue
+		}
+
+		// Compare priorities
+		if qj == nil || v.s.Priority > qj.s.Priority || (v.s.Priority == qj.s.Priority && v.seq < qj.seq) {
+			qj = v
+		}
+	}
+	return
+}
+
+func (q *JobQueue) run(ctx context.Context, qj *jobQueueJob) (err error) {
+	// No task func
+	if q.o.TaskFunc == nil {
+		err = errors.New("astiencoder: no task func")
+		return
+	}
+
+	// Forward events and catch the first error
+	eh := NewEventHandler()
+	var m sync.Mutex
+	var errRun error
+	eh.AddForAll(func(e Event) bool {
+		q.eh.Emit(e)
+		return false
+	})
+	eh.AddForEventName(EventNameError, func(e Event) bool {
+		m.Lock()
+		defer m.Unlock()
+		if errRun == nil {
+			errRun = e.Payload.(error)
+		}
+		return false
+	})
+
+	// Build workflow
+	c := astikit.NewCloser()
+	defer c.Close()
+	j := qj.j
+	var w *Workflow
+	if w, err = BuildWorkflow(j.Definition, BuildWorkflowOptions{
+		Closer:         c,
+		Context:        ctx,
+		EventHandler:   eh,
+		SecretProvider: q.o.SecretProvider,
+		Substitution:   q.o.Substitution,
+		TaskFunc:       q.o.TaskFunc,
+		Types:          q.o.Types,
+		Values:         j.Values,
+	}); err != nil {
+		err = fmt.Errorf("astiencoder: building workflow failed: %w", err)
+		return
+	}
+
+	// Store workflow
+	q.c.L.Lock()
+	qj.w = w
+	q.c.L.Unlock()
+
+	// Wait for the workflow to be stopped
+	stopped := make(chan struct{})
+	eh.AddForEventName(EventNameWorkflowStopped, func(e Event) bool {
+		if e.Target == w {
+			close(stopped)
+			return true
+		}
+		return false
+	})
+
+	// Job has been cancelled while the workflow was being built
+	if err = ctx.Err(); err != nil {
+		err = fmt.Errorf("astiencoder: job has been cancelled: %w", err)
+		return
+	}
+
+	// Start workflow
+	w.Start()
+
+	// The workflow is not started and no stopped event is emitted if the job has been cancelled right before starting
+	// it. Once started, its status is only updated to stopped once it's done
+	if w.Status() == StatusStopped {
+		if err = ctx.Err(); err != nil {
+			err = fmt.Errorf("astiencoder: job has been cancelled: %w", err)
+			return
+		}
+	} else {
+		// Wait for the workflow to be stopped, stopping it if the job is cancelled
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			w.Stop()
+			<-stopped
+		}
+	}
+
+	// Get error
+	m.Lock()
+	defer m.Unlock()
+	err = errRun
+	return
+}
+
+func (q *JobQueue) done(qj *jobQueueJob, err error) {
+	// Lock
+	q.c.L.Lock()
+	q.runs--
+
+	// Update job
+	qj.s.Error = ""
+	if err != nil {
+		qj.s.Error = err.Error()
+	}
+	switch {
+	case qj.cancelled:
+		qj.s.Status = JobStatusCancelled
+	case qj.interrupted:
+		// Job is run again by the next process once restored
+		qj.interrupted = false
+		qj.s.Status = JobStatusPending
+	case err == nil:
+		qj.s.Status = JobStatusSucceeded
+	case qj.s.Attempts <= q.o.Retry.MaxRetries:
+		qj.s.Status = JobStatusPending
+		qj.notBefore = q.now().Add(q.o.Retry.Delay)
+	default:
+		qj.s.Status = JobStatusFailed
+	}
+	if qj.s.done() {
+		qj.s.StoppedAt = q.now()
+	}
+	s := qj.s
+	q.put(qj)
+	q.trim()
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Persist
+	errs := q.persist()
+
+	// Emit
+	q.emit(s, errs)
+}
+
+// trim must be called with the lock held. It removes the oldest done jobs exceeding the history size, and queues their
+// deletion from the store
+func (q *JobQueue) trim() {
+	// Get done jobs
+	var ds []*jobQueueJob
+	for _, qj := range q.js {
+		if qj.s.done() {
+			ds = append(ds, qj)
+		}
+	}
+
+	// Nothing to remove
+	if len(ds) <= q.o.HistorySize {
+		return
+	}
+
+	// Index jobs to remove
+	sort.Slice(ds, func(i, j int) bool { return ds[i].s.StoppedAt.Before(ds[j].s.StoppedAt) })
+	rs := make(map[*jobQueueJob]bool)
+	for _, qj := range ds[:len(ds)-q.o.HistorySize] {
+		rs[qj] = true
+	}
+
+	// Remove
+	var js []*jobQueueJob
+	for _, qj := range q.js {
+		if !rs[qj] {
+			js = append(js, qj)
+		} else if q.o.Store != nil {
+			q.ops = append(q.ops, jobQueueStoreOperation{delID: qj.s.ID})
+		}
+	}
+	q.js = js
+	return
+}
+
+// put must be called with the lock held. It queues a snapshot of the job to be persisted if there's a store
+func (q *JobQueue) put(qj *jobQueueJob) {
+	if q.o.Store == nil {
+		return
+	}
+	q.ops = append(q.ops, jobQueueStoreOperation{j: StoredJob{Job: qj.j, State: qj.s}})
+}
+
+// persist must be called without the lock held. It performs the queued store operations in the order they've been
+// queued, even if they've been queued by other callers
+func (q *JobQueue) persist() (errs []error) {
+	// No store
+	if q.o.Store == nil {
+		return
+	}
+
+	// Make sure operations are performed one at a time
+	q.mp.Lock()
+	defer q.mp.Unlock()
+
+	// Get operations
+	q.c.L.Lock()
+	ops := q.ops
+	q.ops = nil
+	q.c.L.Unlock()
+
+	// Loop through operations
+	for _, op := range ops {
+		if op.delID != "" {
+			if err := q.o.Store.DelJob(op.delID); err != nil {
+				errs = append(errs, fmt.Errorf("astiencoder: deleting job %s from store failed: %w", op.delID, err))
+			}
+		} else if err := q.o.Store.PutJob(op.j); err != nil {
+			errs = append(errs, fmt.Errorf("astiencoder: putting job %s in store failed: %w", op.j.State.ID, err))
+		}
+	}
+	return
+}
+
+// emit must be called without the lock held since handlers may call the queue
+func (q *JobQueue) emit(s JobState, errs []error) {
+	for _, err := range errs {
+		q.eh.Emit(EventError(q, err))
+	}
+	q.eh.Emit(Event{
+		Name:    EventNameJobUpdated,
+		Payload: s,
+		Target:  q,
+	})
+}
@@ -0,0 +1,266 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobQueue(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	m := &sync.Mutex{}
+	var names []string
+	var failures int
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		m.Lock()
+		defer m.Unlock()
+		names = append(names, b.Definition.Name)
+		if b.Definition.Name == "retry" && failures < 2 {
+			failures++
+			return nil, errors.New("test")
+		}
+		return newMockedStatsNode(b.Definition.Name, eh), nil
+	}})
+
+	// Create queue
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	q := NewJobQueue(JobQueueOptions{
+		Retry:    JobRetryPolicy{MaxRetries: 1},
+		TaskFunc: wk.NewTask,
+		Types:    ts,
+	}, eh)
+	done := make(chan struct{})
+	eh.AddForEventName(EventNameJobUpdated, func(e Event) bool {
+		if s := e.Payload.(JobState); s.Name == "last" && s.Status == JobStatusSucceeded {
+			close(done)
+		}
+		return false
+	})
+
+	// Add jobs
+	job := func(name string, priority int) Job {
+		return Job{Definition: WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: name, Type: "t"}}}, Priority: priority}
+	}
+	q.Add(job("low", 0))
+	q.Add(job("retry", 2))
+	id := q.Add(job("cancelled", 1))
+	q.Add(job("high", 1))
+	q.Add(job("last", -1))
+	assert.NoError(t, q.Cancel(id))
+	assert.Error(t, q.Cancel(id))
+	assert.Error(t, q.Cancel("unknown"))
+
+	// Run jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Start(ctx)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("jobs are not done")
+	}
+	cancel()
+	<-stopped
+
+	// Jobs have been run by priority and failed jobs have been retried
+	m.Lock()
+	assert.Equal(t, []string{"retry", "retry", "high", "low", "last"}, names)
+	m.Unlock()
+	ss := q.Jobs()
+	assert.Len(t, ss, 5)
+	assert.Equal(t, JobStatusSucceeded, ss[0].Status)
+	assert.Equal(t, JobStatusFailed, ss[1].Status)
+	assert.Equal(t, 2, ss[1].Attempts)
+	assert.Equal(t, "astiencoder: building workflow failed: astiencoder: nodes[0]: test", ss[1].Error)
+	assert.Equal(t, JobStatusCancelled, ss[2].Status)
+	assert.Equal(t, 0, ss[2].Attempts)
+	assert.Equal(t, JobStatusSucceeded, ss[3].Status)
+	assert.False(t, ss[3].StoppedAt.IsZero())
+}
+
+func TestJobQueueRetryDelay(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	var attempts []time.Time
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			return nil, errors.New("test")
+		}
+		return newMockedStatsNode(b.Definition.Name, eh), nil
+	}})
+
+	// Run job
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	q := NewJobQueue(JobQueueOptions{
+		Retry:    JobRetryPolicy{Delay: 20 * time.Millisecond, MaxRetries: 1},
+		TaskFunc: wk.NewTask,
+		Types:    ts,
+	}, eh)
+	done := make(chan struct{})
+	eh.AddForEventName(EventNameJobUpdated, func(e Event) bool {
+		if e.Payload.(JobState).Status == JobStatusSucceeded {
+			close(done)
+		}
+		return false
+	})
+	q.Add(Job{Definition: WorkflowDefinition{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Start(ctx)
+	select {
+	case <-done:
+		assert.Len(t, attempts, 2)
+		assert.True(t, attempts[1].Sub(attempts[0]) >= 20*time.Millisecond)
+	case <-time.After(time.Second):
+		t.Error("job is not done")
+	}
+}
+
+func TestJobQueueShutdown(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	release := make(chan struct{})
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		n := &mockedShutdownNode{mockedStatsNode: newMockedStatsNode(b.Definition.Name, eh)}
+		if b.Definition.Name == "drained" {
+			n.done = release
+		}
+		return n, nil
+	}})
+
+	// Create queue
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewMemoryStore()
+	q := NewJobQueue(JobQueueOptions{
+		Checkpoint: func(s JobState, w *Workflow) (map[string]interface{}, error) {
+			return map[string]interface{}{"name": s.Name, "workflow": w.Name()}, nil
+		},
+		Concurrency: 2,
+		Store:       s,
+		TaskFunc:    wk.NewTask,
+		Types:       ts,
+	}, eh)
+	running := make(chan struct{}, 2)
+	eh.AddForEventName(EventNameJobUpdated, func(e Event) bool {
+		if e.Payload.(JobState).Status == JobStatusRunning {
+			running <- struct{}{}
+		}
+		return false
+	})
+
+	// Run jobs
+	job := func(name string) Job {
+		return Job{Definition: WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: name, Type: "t"}}}}
+	}
+	q.Add(job("drained"))
+	id := q.Add(job("interrupted"))
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Start(context.Background())
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-running:
+		case <-time.After(time.Second):
+			t.Fatal("jobs are not running")
+		}
+	}
+
+	// Shut down
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := q.Shutdown(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("queue is not stopped")
+	}
+
+	// Drained jobs are done and interrupted jobs are pending with a checkpoint
+	q.Add(job("added"))
+	ss := q.Jobs()
+	assert.Len(t, ss, 3)
+	assert.Equal(t, JobStatusSucceeded, ss[0].Status)
+	assert.Equal(t, JobStatusPending, ss[1].Status)
+	assert.Equal(t, JobStatusPending, ss[2].Status)
+	c, ok, err := q.Checkpoint(id)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "interrupted", "workflow": "interrupted"}, c.Data)
+	sj, ok, err := s.GetJob(id)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, JobStatusPending, sj.State.Status)
+}
+
+func TestJobQueueCancelDuringBuild(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	building := make(chan struct{})
+	release := make(chan struct{})
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		close(building)
+		<-release
+		return newMockedStatsNode(b.Definition.Name, eh), nil
+	}})
+
+	// Create queue
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	q := NewJobQueue(JobQueueOptions{
+		TaskFunc: wk.NewTask,
+		Types:    ts,
+	}, eh)
+
+	// Run job
+	id := q.Add(Job{Definition: WorkflowDefinition{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}}})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Start(context.Background())
+	}()
+	select {
+	case <-building:
+	case <-time.After(time.Second):
+		t.Fatal("workflow is not being built")
+	}
+
+	// Cancel job while the workflow is being built
+	assert.NoError(t, q.Cancel(id))
+	close(release)
+
+	// Shut down
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("queue is not stopped")
+	}
+
+	// Job is cancelled
+	ss := q.Jobs()
+	assert.Len(t, ss, 1)
+	assert.Equal(t, JobStatusCancelled, ss[0].Status)
+}
@@ -0,0 +1,216 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+const adaptiveBitrateScalerName = "scale@abr"
+
+// AdaptiveBitrate represents an object capable of stepping the bit rate and the resolution of an encoder down when
+// its outputs report congestion, and back up once congestion has cleared
+// It must be added as a congestion handler to the outputs
+type AdaptiveBitrate struct {
+	apply           func(s AdaptiveBitrateStep) error
+	congested       map[astiencoder.Node]bool
+	eh              *astiencoder.EventHandler
+	idx             int
+	lastChangeAt    time.Time
+	lastCongestedAt time.Time
+	m               *sync.Mutex
+	notify          chan struct{}
+	o               AdaptiveBitrateOptions
+}
+
+// AdaptiveBitrateOptions represents adaptive bitrate options
+type AdaptiveBitrateOptions struct {
+	Encoder *Encoder
+	// If set, its content must contain the output of AdaptiveBitrateScalerContent so that the resolution is updated
+	// as well. In that case, all steps must have a size
+	Scaler *Filterer
+	// Min duration between 2 step downs so that the encoder's rate control has time to react. Default is 2s
+	StepDownDelay time.Duration
+	// Min duration without congestion before stepping up. Default is 10s
+	StepUpDelay time.Duration
+	// Ordered from the highest to the lowest bit rate. The first step is the one the encoder starts with
+	Steps []AdaptiveBitrateStep
+}
+
+// AdaptiveBitrateStep represents an adaptive bitrate step
+type AdaptiveBitrateStep struct {
+	BitRate int
+	Height  int
+	Width   int
+}
+
+// AdaptiveBitrateStepChange represents an adaptive bitrate step change
+// It is the payload of the AdaptiveBitrateStepped event
+type AdaptiveBitrateStepChange struct {
+	Congested bool
+	From      AdaptiveBitrateStep
+	To        AdaptiveBitrateStep
+}
+
+// NewAdaptiveBitrate creates a new adaptive bitrate
+func NewAdaptiveBitrate(o AdaptiveBitrateOptions, eh *astiencoder.EventHandler) (a *AdaptiveBitrate, err error) {
+	// No encoder
+	if o.Encoder == nil {
+		err = errors.New("astilibav: no encoder provided")
+		return
+	}
+
+	// Invalid steps
+	if len(o.Steps) < 2 {
+		err = errors.New("astilibav: at least 2 steps are required")
+		return
+	}
+	for idx, s := range o.Steps {
+		if s.BitRate <= 0 {
+			err = fmt.Errorf("astilibav: invalid bit rate %d for step %d", s.BitRate, idx)
+			return
+		}
+		if o.Scaler != nil && (s.Height <= 0 || s.Width <= 0) {
+			err = fmt.Errorf("astilibav: invalid size %dx%d for step %d", s.Width, s.Height, idx)
+			return
+		}
+	}
+
+	// Default options
+	if o.StepDownDelay <= 0 {
+		o.StepDownDelay = 2 * time.Second
+	}
+	if o.StepUpDelay <= 0 {
+		o.StepUpDelay = 10 * time.Second
+	}
+
+	// Create adaptive bitrate
+	a = &AdaptiveBitrate{
+		congested: make(map[astiencoder.Node]bool),
+		eh:        eh,
+		m:         &sync.Mutex{},
+		notify:    make(chan struct{}, 1),
+		o:         o,
+	}
+	a.apply = a.applyStep
+	return
+}
+
+// AdaptiveBitrateScalerContent returns the content of a filter that scales frames to the step's size and back to
+// the encoder's size so that the encoder's size never changes
+func AdaptiveBitrateScalerContent(s AdaptiveBitrateStep, encoderWidth, encoderHeight int) string {
+	return fmt.Sprintf("%s=w=%d:h=%d,scale=w=%d:h=%d", adaptiveBitrateScalerName, s.Width, s.Height, encoderWidth, encoderHeight)
+}
+
+// HandleCongestion implements the CongestionHandler interface
+func (a *AdaptiveBitrate) HandleCongestion(c Congestion) {
+	// Update congestion
+	a.m.Lock()
+	a.congested[c.Output] = c.Congested
+	a.m.Unlock()
+
+	// Notify
+	select {
+	case a.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Step returns the current step
+func (a *AdaptiveBitrate) Step() AdaptiveBitrateStep {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.o.Steps[a.idx]
+}
+
+// Start steps the bit rate until the context is done
+func (a *AdaptiveBitrate) Start(ctx context.Context) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.notify:
+		case <-t.C:
+		}
+		a.evaluate(time.Now())
+	}
+}
+
+func (a *AdaptiveBitrate) evaluate(now time.Time) {
+	// Lock
+	a.m.Lock()
+
+	// Get congestion
+	var congested bool
+	for _, v := range a.congested {
+		if v {
+			congested = true
+			break
+		}
+	}
+
+	// Get next step
+	idx := a.idx
+	if congested {
+		a.lastCongestedAt = now
+		if idx < len(a.o.Steps)-1 && now.Sub(a.lastChangeAt) >= a.o.StepDownDelay {
+			idx++
+		}
+	} else if idx > 0 && now.Sub(a.lastCongestedAt) >= a.o.StepUpDelay && now.Sub(a.lastChangeAt) >= a.o.StepUpDelay {
+		idx--
+	}
+
+	// Nothing to do
+	if idx == a.idx {
+		a.m.Unlock()
+		return
+	}
+
+	// Update step
+	c := AdaptiveBitrateStepChange{
+		Congested: congested,
+		From:      a.o.Steps[a.idx],
+		To:        a.o.Steps[idx],
+	}
+	a.idx = idx
+	a.lastChangeAt = now
+	a.m.Unlock()
+
+	// Apply
+	if err := a.apply(c.To); err != nil {
+		a.eh.Emit(astiencoder.EventError(a, fmt.Errorf("astilibav: applying adaptive bitrate step failed: %w", err)))
+		return
+	}
+
+	// Emit
+	a.eh.Emit(astiencoder.Event{
+		Name:    AdaptiveBitrateStepped,
+		Payload: c,
+		Target:  a,
+	})
+}
+
+func (a *AdaptiveBitrate) applyStep(s AdaptiveBitrateStep) (err error) {
+	// Update resolution
+	if a.o.Scaler != nil {
+		if err = a.o.Scaler.SendCommand(adaptiveBitrateScalerName, "w", strconv.Itoa(s.Width), 0); err != nil {
+			err = fmt.Errorf("astilibav: sending width command failed: %w", err)
+			return
+		}
+		if err = a.o.Scaler.SendCommand(adaptiveBitrateScalerName, "h", strconv.Itoa(s.Height), 0); err != nil {
+			err = fmt.Errorf("astilibav: sending height command failed: %w", err)
+			return
+		}
+	}
+
+	// Update bit rate
+	a.o.Encoder.SetBitRate(s.BitRate)
+	return
+}
@@ -0,0 +1,47 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveBitrate(t *testing.T) {
+	a, err := NewAdaptiveBitrate(AdaptiveBitrateOptions{
+		Encoder:       &Encoder{},
+		StepDownDelay: time.Second,
+		StepUpDelay:   5 * time.Second,
+		Steps:         []AdaptiveBitrateStep{{BitRate: 3}, {BitRate: 2}, {BitRate: 1}},
+	}, astiencoder.NewEventHandler())
+	assert.NoError(t, err)
+	var bs []int
+	a.apply = func(s AdaptiveBitrateStep) error {
+		bs = append(bs, s.BitRate)
+		return nil
+	}
+	n := time.Unix(0, 0)
+
+	// Step down
+	a.HandleCongestion(Congestion{Congested: true})
+	a.evaluate(n)
+	a.evaluate(n.Add(500 * time.Millisecond))
+	a.evaluate(n.Add(time.Second))
+	a.evaluate(n.Add(2 * time.Second))
+	assert.Equal(t, []int{2, 1}, bs)
+	assert.Equal(t, 1, a.Step().BitRate)
+
+	// Step up
+	a.HandleCongestion(Congestion{})
+	a.evaluate(n.Add(3 * time.Second))
+	a.evaluate(n.Add(7 * time.Second))
+	assert.Equal(t, []int{2, 1, 2}, bs)
+	a.evaluate(n.Add(8 * time.Second))
+	a.evaluate(n.Add(12 * time.Second))
+	assert.Equal(t, []int{2, 1, 2, 3}, bs)
+
+	// Invalid options
+	_, err = NewAdaptiveBitrate(AdaptiveBitrateOptions{Encoder: &Encoder{}, Steps: []AdaptiveBitrateStep{{BitRate: 1}}}, astiencoder.NewEventHandler())
+	assert.Error(t, err)
+}
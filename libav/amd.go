@@ -0,0 +1,147 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/pixfmt.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+const amdVAAPIPoolSize = 20
+
+var (
+	amdSessions         = newHardwareSessions()
+	amdVAAPIPixelFormat = avutil.PixelFormat(C.AV_PIX_FMT_VAAPI)
+)
+
+// AMDSessions returns the number of AMF and VAAPI sessions opened on each GPU
+func AMDSessions() map[int]int {
+	return amdSessions.count()
+}
+
+// AMDEncoderOptions represents AMD encoder options
+type AMDEncoderOptions struct {
+	// Ctx.CodecName must be the name of an AMF encoder (e.g. "h264_amf") on Windows or of a VAAPI encoder (e.g.
+	// "h264_vaapi") on Linux
+	// With VAAPI, Ctx.PixelFormat is the pixel format of the frames in system memory (e.g. AV_PIX_FMT_NV12) which are
+	// uploaded to the GPU before being encoded
+	Encoder EncoderOptions
+	// Name of the software encoder used when the GPU can't be used. If empty, an error is returned instead
+	FallbackCodecName string
+	// Index of the GPU
+	// With VAAPI, it is the index of the render node (e.g. 1 for "/dev/dri/renderD129")
+	GPU int
+	// Max number of sessions opened on the GPU by all nodes. 0 means no limit
+	MaxSessions int
+}
+
+// NewAMDEncoder creates a new encoder using AMF or VAAPI on the selected GPU and falls back to a software encoder when
+// sessions are exhausted or when the GPU fails to open
+// Options mirror NVENCEncoderOptions so that workflows can switch GPU vendors easily
+func NewAMDEncoder(o AMDEncoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (e *Encoder, err error) {
+	// Get device
+	deviceType, device, err := amdDevice(o.Encoder.Ctx.CodecName, o.GPU)
+	if err != nil {
+		err = fmt.Errorf("astilibav: getting device failed: %w", err)
+		return
+	}
+
+	// Create hardware encoder
+	var reason string
+	if amdSessions.acquire(o.GPU, o.MaxSessions) {
+		// Create encoder
+		if e, err = newAMDEncoder(o, deviceType, device, eh, c); err == nil {
+			c.Add(func() error {
+				amdSessions.release(o.GPU)
+				return nil
+			})
+			return
+		}
+
+		// Release session
+		amdSessions.release(o.GPU)
+		reason = err.Error()
+	} else {
+		reason = fmt.Sprintf("max sessions %d reached on gpu %d", o.MaxSessions, o.GPU)
+	}
+
+	// No fallback
+	if o.FallbackCodecName == "" {
+		err = fmt.Errorf("astilibav: creating amd encoder failed: %s", reason)
+		return
+	}
+
+	// Create software encoder
+	so := o.Encoder
+	so.Ctx.CodecName = o.FallbackCodecName
+	if e, err = NewEncoder(so, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating fallback encoder failed: %w", err)
+		return
+	}
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name: HardwareFallbackTriggered,
+		Payload: HardwareFallback{
+			From:   o.Encoder.Ctx.CodecName,
+			GPU:    o.GPU,
+			Reason: reason,
+			To:     o.FallbackCodecName,
+		},
+		Target: e,
+	})
+	return
+}
+
+// amdDevice returns the type and the name of the hardware device used by the encoder
+func amdDevice(codecName string, gpu int) (deviceType, device string, err error) {
+	switch {
+	case strings.HasSuffix(codecName, "_amf"):
+		deviceType, device = "d3d11va", strconv.Itoa(gpu)
+	case strings.HasSuffix(codecName, "_vaapi"):
+		deviceType, device = "vaapi", fmt.Sprintf("/dev/dri/renderD%d", 128+gpu)
+	default:
+		err = fmt.Errorf("astilibav: %s is neither an amf nor a vaapi encoder", codecName)
+	}
+	return
+}
+
+func newAMDEncoder(o AMDEncoderOptions, deviceType, device string, eh *astiencoder.EventHandler, c *astikit.Closer) (e *Encoder, err error) {
+	// Create device
+	ho := o.Encoder
+	if ho.HardwareDevice, err = NewHardwareDeviceContext(deviceType, device, c); err != nil {
+		err = fmt.Errorf("astilibav: creating hardware device failed: %w", err)
+		return
+	}
+
+	// VAAPI encoders only accept frames in VAAPI memory
+	if deviceType == "vaapi" && ho.Ctx.HardwareFramesCtx == nil {
+		// Create hardware frames
+		if ho.Ctx.HardwareFramesCtx, err = NewHardwareFramesContext(ho.HardwareDevice, HardwareFramesContextOptions{
+			Height:              ho.Ctx.Height,
+			InitialPoolSize:     amdVAAPIPoolSize,
+			PixelFormat:         amdVAAPIPixelFormat,
+			SoftwarePixelFormat: ho.Ctx.PixelFormat,
+			Width:               ho.Ctx.Width,
+		}, c); err != nil {
+			err = fmt.Errorf("astilibav: creating hardware frames failed: %w", err)
+			return
+		}
+		ho.Ctx.PixelFormat = amdVAAPIPixelFormat
+	}
+
+	// Create encoder
+	if e, err = NewEncoder(ho, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating encoder failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,20 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAMDDevice(t *testing.T) {
+	typ, d, err := amdDevice("h264_amf", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "d3d11va", typ)
+	assert.Equal(t, "1", d)
+	typ, d, err = amdDevice("hevc_vaapi", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "vaapi", typ)
+	assert.Equal(t, "/dev/dri/renderD129", d)
+	_, _, err = amdDevice("libx264", 0)
+	assert.Error(t, err)
+}
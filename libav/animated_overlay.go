@@ -0,0 +1,131 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// AnimatedOverlayFilterer represents an object capable of overlaying an animated asset with alpha (e.g. GIF, APNG
+// or WebM) on video frames, for animated bugs and lower-thirds
+// The asset is read by ffmpeg's movie filter. WebM alpha is only preserved when ffmpeg's vp8/vp9 decoders are the
+// libvpx ones (e.g. by configuring ffmpeg with --disable-decoder=vp8,vp9 --enable-libvpx)
+// The schedule can be updated while the filterer is running, in which case the asset restarts from its beginning
+type AnimatedOverlayFilterer struct {
+	*Filterer
+	m *sync.Mutex
+	o AnimatedOverlayFiltererOptions
+}
+
+// AnimatedOverlayFiltererOptions represents animated overlay filterer options
+type AnimatedOverlayFiltererOptions struct {
+	// Path of the asset
+	Asset string
+	// Position in the input after which the asset is hidden. If 0, the asset is never hidden
+	End   time.Duration
+	Input astiencoder.Node
+	// If true, the asset is looped until it's hidden
+	Loop      bool
+	Node      astiencoder.NodeOptions
+	OutputCtx Context
+	Restamper FrameRestamper
+	// Position in the input at which the asset starts being shown
+	Start time.Duration
+	// Expressions as used in ffmpeg's overlay filter. Default is "0"
+	X string
+	Y string
+}
+
+// NewAnimatedOverlayFilterer creates a new animated overlay filterer
+func NewAnimatedOverlayFilterer(o AnimatedOverlayFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *AnimatedOverlayFilterer, err error) {
+	// No asset
+	if o.Asset == "" {
+		err = errors.New("astilibav: no asset")
+		return
+	}
+
+	// Default options
+	if o.X == "" {
+		o.X = "0"
+	}
+	if o.Y == "" {
+		o.Y = "0"
+	}
+
+	// Create filterer
+	f = &AnimatedOverlayFilterer{
+		m: &sync.Mutex{},
+		o: o,
+	}
+
+	// Get content
+	var content string
+	if content, err = f.content(o.Start, o.End); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   content,
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *AnimatedOverlayFilterer) content(start, end time.Duration) (string, error) {
+	// Invalid schedule
+	if start < 0 || (end > 0 && end <= start) {
+		return "", fmt.Errorf("astilibav: invalid schedule from %s to %s", start, end)
+	}
+
+	// Asset is shifted so that it starts playing when it's shown
+	loop := 1
+	if f.o.Loop {
+		loop = 0
+	}
+	s := fmt.Sprintf("movie=filename=%s:loop=%d,setpts=PTS-STARTPTS+%s/TB[asset];[in][asset]overlay=x=%s:y=%s:eof_action=pass", escapeFilterOption(f.o.Asset), loop, formatFilterFloat(start.Seconds()), escapeFilterOption(f.o.X), escapeFilterOption(f.o.Y))
+
+	// Add schedule
+	if end > 0 {
+		s += ":enable=" + escapeFilterOption(fmt.Sprintf("between(t,%s,%s)", formatFilterFloat(start.Seconds()), formatFilterFloat(end.Seconds())))
+	} else if start > 0 {
+		s += ":enable=" + escapeFilterOption(fmt.Sprintf("gte(t,%s)", formatFilterFloat(start.Seconds())))
+	}
+	return s, nil
+}
+
+// Schedule shows the asset from start to end, which are positions in the input. If end is 0, the asset is never
+// hidden
+func (f *AnimatedOverlayFilterer) Schedule(start, end time.Duration) (err error) {
+	// Lock
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	// Get content
+	var content string
+	if content, err = f.content(start, end); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Set content
+	if err = f.SetContent(content); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+
+	// Update options
+	f.o.Start, f.o.End = start, end
+	return
+}
@@ -0,0 +1,21 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimatedOverlayContent(t *testing.T) {
+	f := &AnimatedOverlayFilterer{o: AnimatedOverlayFiltererOptions{Asset: "bug.gif", Loop: true, X: "W-w-10", Y: "10"}}
+	c, err := f.content(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "movie=filename=bug.gif:loop=0,setpts=PTS-STARTPTS+0/TB[asset];[in][asset]overlay=x=W-w-10:y=10:eof_action=pass", c)
+	f.o.Loop = false
+	c, err = f.content(2*time.Second, 7500*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, `movie=filename=bug.gif:loop=1,setpts=PTS-STARTPTS+2/TB[asset];[in][asset]overlay=x=W-w-10:y=10:eof_action=pass:enable=between(t\,2\,7.5)`, c)
+	_, err = f.content(2*time.Second, time.Second)
+	assert.Error(t, err)
+}
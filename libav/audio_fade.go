@@ -0,0 +1,223 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+// Audio fade curves
+const (
+	AudioFadeCurveEqualPower = "qsin"
+	AudioFadeCurveLinear     = "tri"
+)
+
+// AudioFade represents an audio fade
+type AudioFade struct {
+	// If 0, there's no fade
+	Duration time.Duration
+	// Position in the input at which the fade starts
+	Start time.Duration
+}
+
+// AudioFadeFilterer represents an object capable of fading audio frames in and out
+// Fades can be updated while the filterer is running, e.g. when the end of an item is only known during playout
+type AudioFadeFilterer struct {
+	*Filterer
+	curve string
+}
+
+// AudioFadeFiltererOptions represents audio fade filterer options
+type AudioFadeFiltererOptions struct {
+	// Default is linear
+	Curve     string
+	FadeIn    AudioFade
+	FadeOut   AudioFade
+	Input     astiencoder.Node
+	Node      astiencoder.NodeOptions
+	OutputCtx Context
+	Restamper FrameRestamper
+}
+
+// NewAudioFadeFilterer creates a new audio fade filterer
+func NewAudioFadeFilterer(o AudioFadeFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *AudioFadeFilterer, err error) {
+	// Default curve
+	if o.Curve == "" {
+		o.Curve = AudioFadeCurveLinear
+	}
+
+	// Create filterer
+	f = &AudioFadeFilterer{curve: o.Curve}
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   f.content(o.FadeIn, o.FadeOut),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *AudioFadeFilterer) content(in, out AudioFade) string {
+	var fs []string
+	for _, v := range []struct {
+		f AudioFade
+		t string
+	}{
+		{f: in, t: "in"},
+		{f: out, t: "out"},
+	} {
+		if v.f.Duration > 0 {
+			fs = append(fs, fmt.Sprintf("afade=t=%s:st=%s:d=%s:curve=%s", v.t, formatFilterFloat(v.f.Start.Seconds()), formatFilterFloat(v.f.Duration.Seconds()), f.curve))
+		}
+	}
+	if len(fs) == 0 {
+		return "anull"
+	}
+	return strings.Join(fs, ",")
+}
+
+// SetFades updates the fades
+func (f *AudioFadeFilterer) SetFades(in, out AudioFade) (err error) {
+	if err = f.SetContent(f.content(in, out)); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+	return
+}
+
+// AudioCrossfadeFilterer represents an object capable of mixing two audio inputs where only one of them is audible
+// at a time, and crossfading from one to the other, e.g. for playlist playout or ad transitions
+// Both inputs must be live, i.e. they must keep on sending frames during the crossfade
+type AudioCrossfadeFilterer struct {
+	*Filterer
+	a         astiencoder.Node
+	b         astiencoder.Node
+	curve     string
+	m         *sync.Mutex
+	positions map[astiencoder.Node]time.Duration
+}
+
+// AudioCrossfadeFiltererOptions represents audio crossfade filterer options
+type AudioCrossfadeFiltererOptions struct {
+	// Audible input at start
+	A astiencoder.Node
+	B astiencoder.Node
+	// Default is equal power
+	Curve     string
+	Node      astiencoder.NodeOptions
+	OutputCtx Context
+	Restamper FrameRestamper
+}
+
+// NewAudioCrossfadeFilterer creates a new audio crossfade filterer
+func NewAudioCrossfadeFilterer(o AudioCrossfadeFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *AudioCrossfadeFilterer, err error) {
+	// No inputs
+	if o.A == nil || o.B == nil {
+		err = errors.New("astilibav: a and b are mandatory")
+		return
+	}
+
+	// Default curve
+	if o.Curve == "" {
+		o.Curve = AudioFadeCurveEqualPower
+	}
+
+	// Create filterer
+	f = &AudioCrossfadeFilterer{
+		a:         o.A,
+		b:         o.B,
+		curve:     o.Curve,
+		m:         &sync.Mutex{},
+		positions: make(map[astiencoder.Node]time.Duration),
+	}
+
+	// amix divides each input by the number of active inputs, which is compensated
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content: "[a]volume@a=volume=1:eval=frame[va];[b]volume@b=volume=0:eval=frame[vb];[va][vb]amix=inputs=2:duration=longest:dropout_transition=0,volume=2",
+		Inputs: map[string]astiencoder.Node{
+			"a": o.A,
+			"b": o.B,
+		},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+// HandleFrame implements the FrameHandler interface
+func (f *AudioCrossfadeFilterer) HandleFrame(p *FrameHandlerPayload) {
+	// Store position
+	f.m.Lock()
+	f.positions[p.Node] = time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational))
+	f.m.Unlock()
+
+	// Handle frame
+	f.Filterer.HandleFrame(p)
+}
+
+// Crossfade crossfades to the input over the duration, starting with the last frame received
+func (f *AudioCrossfadeFilterer) Crossfade(to astiencoder.Node, d time.Duration) (err error) {
+	// Invalid input
+	var from astiencoder.Node
+	var fromName, toName string
+	switch to {
+	case f.a:
+		from, fromName, toName = f.b, "b", "a"
+	case f.b:
+		from, fromName, toName = f.a, "a", "b"
+	default:
+		err = errors.New("astilibav: input is neither a nor b")
+		return
+	}
+
+	// Invalid duration
+	if d <= 0 {
+		err = errors.New("astilibav: duration must be > 0")
+		return
+	}
+
+	// Get positions
+	f.m.Lock()
+	fromStart, toStart := f.positions[from], f.positions[to]
+	f.m.Unlock()
+
+	// Send commands
+	if err = f.SendCommand("volume@"+fromName, "volume", audioCrossfadeGain(f.curve, false, fromStart, d), 0); err != nil {
+		err = fmt.Errorf("astilibav: sending command to %s failed: %w", fromName, err)
+		return
+	}
+	if err = f.SendCommand("volume@"+toName, "volume", audioCrossfadeGain(f.curve, true, toStart, d), 0); err != nil {
+		err = fmt.Errorf("astilibav: sending command to %s failed: %w", toName, err)
+		return
+	}
+	return
+}
+
+// audioCrossfadeGain returns the volume expression of an input fading in or out from start over the duration
+func audioCrossfadeGain(curve string, in bool, start, d time.Duration) string {
+	x := fmt.Sprintf("clip((t-%s)/%s,0,1)", formatFilterFloat(start.Seconds()), formatFilterFloat(d.Seconds()))
+	switch {
+	case curve == AudioFadeCurveEqualPower && in:
+		return "sin(PI/2*" + x + ")"
+	case curve == AudioFadeCurveEqualPower:
+		return "cos(PI/2*" + x + ")"
+	case in:
+		return x
+	default:
+		return "1-" + x
+	}
+}
@@ -0,0 +1,19 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioFadeContent(t *testing.T) {
+	f := &AudioFadeFilterer{curve: AudioFadeCurveLinear}
+	assert.Equal(t, "anull", f.content(AudioFade{}, AudioFade{}))
+	assert.Equal(t, "afade=t=in:st=0:d=1.5:curve=tri,afade=t=out:st=58:d=2:curve=tri", f.content(AudioFade{Duration: 1500 * time.Millisecond}, AudioFade{Duration: 2 * time.Second, Start: 58 * time.Second}))
+}
+
+func TestAudioCrossfadeGain(t *testing.T) {
+	assert.Equal(t, "sin(PI/2*clip((t-10)/2,0,1))", audioCrossfadeGain(AudioFadeCurveEqualPower, true, 10*time.Second, 2*time.Second))
+	assert.Equal(t, "1-clip((t-10.5)/2,0,1)", audioCrossfadeGain(AudioFadeCurveLinear, false, 10500*time.Millisecond, 2*time.Second))
+}
@@ -0,0 +1,309 @@
+package astilibav
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+var countAudioMeter uint64
+
+// Levels below this value are reported as this value since silence is -Inf dBFS
+const audioMeterFloor = -120.0
+
+// AudioMeter represents an object capable of measuring the peak and RMS levels of each channel of audio frames at a
+// regular interval, so that silence, clipping and channel imbalance show up in monitoring
+type AudioMeter struct {
+	*astiencoder.BaseNode
+	buf              []float64
+	c                *astiencoder.Queue
+	eh               *astiencoder.EventHandler
+	m                *audioMeter
+	statIncomingRate *astikit.CounterRateStat
+	statPeaks        []*audioLevelStat
+	statRMSs         []*audioLevelStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// AudioMeterOptions represents audio meter options
+type AudioMeterOptions struct {
+	// Peak level in dBFS from which a channel is considered as clipping. Default is -0.1
+	ClippingThreshold float64
+	// Channels is used to add per-channel stats
+	Ctx Context
+	// Media duration of the interval at which levels are reported. Default is 1s
+	Interval time.Duration
+	Node     astiencoder.NodeOptions
+	// RMS level in dBFS below which a channel is considered as silent. Default is -60
+	SilenceThreshold float64
+}
+
+// AudioLevels represents the levels of an interval
+// It is the payload of the AudioMeterLevelsReported event
+type AudioLevels struct {
+	Channels []AudioChannelLevels
+	// Difference in dB between the RMS levels of the loudest and the quietest channels
+	Imbalance float64
+	// Position of the beginning of the interval based on frame timestamps
+	Position time.Duration
+}
+
+// AudioChannelLevels represents the levels of a channel
+type AudioChannelLevels struct {
+	Clipping bool
+	// In dBFS
+	Peak float64
+	// In dBFS
+	RMS    float64
+	Silent bool
+}
+
+// NewAudioMeter creates a new audio meter
+func NewAudioMeter(o AudioMeterOptions, eh *astiencoder.EventHandler) (m *AudioMeter) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countAudioMeter, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("audio_meter_%d", count), fmt.Sprintf("Audio Meter #%d", count), "Meters audio levels", "audio meter")
+
+	// Create meter
+	m = &AudioMeter{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               eh,
+		m:                newAudioMeter(o),
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	for i := 0; i < o.Ctx.Channels; i++ {
+		m.statPeaks = append(m.statPeaks, newAudioLevelStat())
+		m.statRMSs = append(m.statRMSs, newAudioLevelStat())
+	}
+	m.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(m), eh)
+	m.addStats()
+	return
+}
+
+func (m *AudioMeter) addStats() {
+	// Add incoming rate
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, m.statIncomingRate)
+
+	// Add levels
+	for i := range m.statPeaks {
+		m.Stater().AddStat(astikit.StatMetadata{
+			Description: fmt.Sprintf("Peak level of channel #%d during the last interval", i),
+			Label:       fmt.Sprintf("Channel %d peak", i),
+			Unit:        "dBFS",
+		}, m.statPeaks[i])
+		m.Stater().AddStat(astikit.StatMetadata{
+			Description: fmt.Sprintf("RMS level of channel #%d during the last interval", i),
+			Label:       fmt.Sprintf("Channel %d RMS", i),
+			Unit:        "dBFS",
+		}, m.statRMSs[i])
+	}
+
+	// Add work ratio
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, m.statWorkRatio)
+
+	// Add chan stats
+	m.c.AddStats(m.Stater())
+}
+
+// Start starts the meter
+func (m *AudioMeter) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	m.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to stop the chan properly
+		defer m.c.Stop()
+
+		// Start chan
+		m.c.Start(m.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (m *AudioMeter) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be measured
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, m); p == nil {
+		return
+	}
+	m.c.AddWithRelease(func() {
+		// Handle pause
+		defer m.HandlePause()
+
+		// Increment incoming rate
+		m.statIncomingRate.Add(1)
+
+		// Get samples
+		m.statWorkRatio.Begin()
+		var channels int
+		var err error
+		if m.buf, channels, err = frameChannelSamples(p.Frame, m.buf); err != nil {
+			m.statWorkRatio.End()
+			m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: getting samples failed: %w", err)))
+			return
+		}
+
+		// Meter
+		ls := m.m.process(m.buf, channels, frameSampleRate(p.Frame), time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational)))
+		m.statWorkRatio.End()
+
+		// Loop through levels
+		for _, l := range ls {
+			// Update stats
+			for i, c := range l.Channels {
+				if i < len(m.statPeaks) {
+					m.statPeaks[i].set(c.Peak)
+					m.statRMSs[i].set(c.RMS)
+				}
+			}
+
+			// Emit
+			m.eh.Emit(astiencoder.Event{
+				Name:    AudioMeterLevelsReported,
+				Payload: l,
+				Target:  m,
+			})
+		}
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+type audioMeter struct {
+	count    int
+	o        AudioMeterOptions
+	peaks    []float64
+	position time.Duration
+	squares  []float64
+}
+
+func newAudioMeter(o AudioMeterOptions) *audioMeter {
+	// Default options
+	if o.ClippingThreshold == 0 {
+		o.ClippingThreshold = -0.1
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.SilenceThreshold == 0 {
+		o.SilenceThreshold = -60
+	}
+	return &audioMeter{o: o}
+}
+
+// process processes samples stored channel after channel and returns the levels of the intervals that are over
+func (m *audioMeter) process(samples []float64, channels, sampleRate int, position time.Duration) (ls []AudioLevels) {
+	// Invalid frame
+	if channels <= 0 || sampleRate <= 0 {
+		return
+	}
+
+	// Number of channels has changed
+	if channels != len(m.peaks) {
+		m.peaks = make([]float64, channels)
+		m.squares = make([]float64, channels)
+		m.count = 0
+	}
+
+	// Loop through samples
+	n := len(samples) / channels
+	size := int(m.o.Interval.Seconds() * float64(sampleRate))
+	if size <= 0 {
+		size = 1
+	}
+	for i := 0; i < n; i++ {
+		// Interval starts
+		if m.count == 0 {
+			m.position = position + time.Duration(i)*time.Second/time.Duration(sampleRate)
+		}
+
+		// Loop through channels
+		for c := 0; c < channels; c++ {
+			v := samples[c*n+i]
+			if a := math.Abs(v); a > m.peaks[c] {
+				m.peaks[c] = a
+			}
+			m.squares[c] += v * v
+		}
+		m.count++
+
+		// Interval is over
+		if m.count >= size {
+			ls = append(ls, m.levels())
+		}
+	}
+	return
+}
+
+func (m *audioMeter) levels() (l AudioLevels) {
+	// Loop through channels
+	l.Position = m.position
+	min, max := 0.0, audioMeterFloor
+	for c := range m.peaks {
+		// Create levels
+		cl := AudioChannelLevels{
+			Peak: audioMeterDB(m.peaks[c]),
+			RMS:  audioMeterDB(math.Sqrt(m.squares[c] / float64(m.count))),
+		}
+		cl.Clipping = cl.Peak >= m.o.ClippingThreshold
+		cl.Silent = cl.RMS <= m.o.SilenceThreshold
+		l.Channels = append(l.Channels, cl)
+
+		// Update imbalance
+		if cl.RMS < min {
+			min = cl.RMS
+		}
+		if cl.RMS > max {
+			max = cl.RMS
+		}
+
+		// Reset
+		m.peaks[c] = 0
+		m.squares[c] = 0
+	}
+	l.Imbalance = max - min
+	m.count = 0
+	return
+}
+
+func audioMeterDB(v float64) float64 {
+	if v <= 0 {
+		return audioMeterFloor
+	}
+	return math.Max(20*math.Log10(v), audioMeterFloor)
+}
+
+// audioLevelStat reports the last level
+type audioLevelStat struct {
+	v uint64
+}
+
+func newAudioLevelStat() *audioLevelStat {
+	return &audioLevelStat{v: math.Float64bits(audioMeterFloor)}
+}
+
+func (s *audioLevelStat) set(v float64) {
+	atomic.StoreUint64(&s.v, math.Float64bits(v))
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *audioLevelStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *audioLevelStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *audioLevelStat) Value(delta time.Duration) interface{} {
+	return math.Float64frombits(atomic.LoadUint64(&s.v))
+}
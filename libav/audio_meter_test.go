@@ -0,0 +1,48 @@
+package astilibav
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioMeter(t *testing.T) {
+	m := newAudioMeter(AudioMeterOptions{Interval: 100 * time.Millisecond})
+	var ls []AudioLevels
+	for i := 0; i < 3; i++ {
+		// Channel #0 is a full scale square wave, channel #1 is a half scale sine and channel #2 is silent
+		s := make([]float64, 3*480)
+		for j := 0; j < 480; j++ {
+			s[j] = 1
+			if j%2 == 0 {
+				s[j] = -1
+			}
+			s[480+j] = 0.5 * math.Sin(2*math.Pi*1000*float64(i*480+j)/48000)
+		}
+		ls = append(ls, m.process(s, 3, 48000, time.Duration(i)*10*time.Millisecond)...)
+	}
+	assert.Len(t, ls, 0)
+	for i := 3; i < 12; i++ {
+		ls = append(ls, m.process(make([]float64, 3*480), 3, 48000, time.Duration(i)*10*time.Millisecond)...)
+	}
+	assert.Len(t, ls, 1)
+	assert.Equal(t, time.Duration(0), ls[0].Position)
+	assert.Len(t, ls[0].Channels, 3)
+	assert.True(t, ls[0].Channels[0].Clipping)
+	assert.InDelta(t, 0, ls[0].Channels[0].Peak, 0.01)
+	assert.InDelta(t, 20*math.Log10(math.Sqrt(0.3)), ls[0].Channels[0].RMS, 0.01)
+	assert.False(t, ls[0].Channels[1].Clipping)
+	assert.InDelta(t, 20*math.Log10(0.5), ls[0].Channels[1].Peak, 0.01)
+	assert.False(t, ls[0].Channels[1].Silent)
+	assert.True(t, ls[0].Channels[2].Silent)
+	assert.Equal(t, audioMeterFloor, ls[0].Channels[2].Peak)
+	assert.InDelta(t, ls[0].Channels[0].RMS-audioMeterFloor, ls[0].Imbalance, 0.01)
+
+	// Interval boundaries don't need to match frame boundaries
+	ls = m.process(make([]float64, 3*7200), 3, 48000, 120*time.Millisecond)
+	assert.Len(t, ls, 1)
+	assert.Equal(t, 100*time.Millisecond, ls[0].Position)
+	assert.Equal(t, 0.0, ls[0].Imbalance)
+}
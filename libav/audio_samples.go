@@ -0,0 +1,112 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/frame.h>
+#include <libavutil/samplefmt.h>
+
+static double astilibavSample(uint8_t *data, enum AVSampleFormat fmt, int idx) {
+	switch (fmt) {
+	case AV_SAMPLE_FMT_U8:  return (((uint8_t *)data)[idx] - 128) / 128.0;
+	case AV_SAMPLE_FMT_S16: return ((int16_t *)data)[idx] / 32768.0;
+	case AV_SAMPLE_FMT_S32: return ((int32_t *)data)[idx] / 2147483648.0;
+	case AV_SAMPLE_FMT_S64: return ((int64_t *)data)[idx] / 9223372036854775808.0;
+	case AV_SAMPLE_FMT_FLT: return ((float *)data)[idx];
+	case AV_SAMPLE_FMT_DBL: return ((double *)data)[idx];
+	default: return 0;
+	}
+}
+
+static int astilibavMonoSamples(AVFrame *f, double *dst) {
+	if (f->channels <= 0) return -1;
+	int planar = av_sample_fmt_is_planar(f->format);
+	enum AVSampleFormat fmt = av_get_packed_sample_fmt(f->format);
+	if (fmt != AV_SAMPLE_FMT_U8 && fmt != AV_SAMPLE_FMT_S16 && fmt != AV_SAMPLE_FMT_S32 && fmt != AV_SAMPLE_FMT_S64 && fmt != AV_SAMPLE_FMT_FLT && fmt != AV_SAMPLE_FMT_DBL) return -1;
+	for (int i = 0; i < f->nb_samples; i++) {
+		double sum = 0;
+		for (int c = 0; c < f->channels; c++) {
+			sum += planar ? astilibavSample(f->extended_data[c], fmt, i) : astilibavSample(f->extended_data[0], fmt, i * f->channels + c);
+		}
+		dst[i] = sum / f->channels;
+	}
+	return 0;
+}
+
+static int astilibavChannelSamples(AVFrame *f, double *dst) {
+	if (f->channels <= 0) return -1;
+	int planar = av_sample_fmt_is_planar(f->format);
+	enum AVSampleFormat fmt = av_get_packed_sample_fmt(f->format);
+	if (fmt != AV_SAMPLE_FMT_U8 && fmt != AV_SAMPLE_FMT_S16 && fmt != AV_SAMPLE_FMT_S32 && fmt != AV_SAMPLE_FMT_S64 && fmt != AV_SAMPLE_FMT_FLT && fmt != AV_SAMPLE_FMT_DBL) return -1;
+	for (int c = 0; c < f->channels; c++) {
+		for (int i = 0; i < f->nb_samples; i++) {
+			dst[c * f->nb_samples + i] = planar ? astilibavSample(f->extended_data[c], fmt, i) : astilibavSample(f->extended_data[0], fmt, i * f->channels + c);
+		}
+	}
+	return 0;
+}
+*/
+import "C"
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/asticode/goav/avutil"
+)
+
+// frameMonoSamples returns the samples of an audio frame averaged across channels and normalized between -1 and 1
+// The buffer is reused if it's big enough
+func frameMonoSamples(f *avutil.Frame, buf []float64) ([]float64, error) {
+	// Get frame
+	cf := (*C.AVFrame)(unsafe.Pointer(f))
+
+	// Get buffer
+	n := int(cf.nb_samples)
+	if cap(buf) < n {
+		buf = make([]float64, n)
+	}
+	buf = buf[:n]
+	if n == 0 {
+		return buf, nil
+	}
+
+	// Get samples
+	if ret := C.astilibavMonoSamples(cf, (*C.double)(unsafe.Pointer(&buf[0]))); ret < 0 {
+		return nil, errors.New("astilibav: sample format or channels are not supported")
+	}
+	return buf, nil
+}
+
+// frameChannelSamples returns the samples of an audio frame normalized between -1 and 1, channel after channel
+// The buffer is reused if it's big enough
+func frameChannelSamples(f *avutil.Frame, buf []float64) (samples []float64, channels int, err error) {
+	// Get frame
+	cf := (*C.AVFrame)(unsafe.Pointer(f))
+
+	// Get buffer
+	channels = int(cf.channels)
+	n := int(cf.nb_samples) * channels
+	if cap(buf) < n {
+		buf = make([]float64, n)
+	}
+	samples = buf[:n]
+	if n == 0 {
+		return
+	}
+
+	// Get samples
+	if ret := C.astilibavChannelSamples(cf, (*C.double)(unsafe.Pointer(&samples[0]))); ret < 0 {
+		err = errors.New("astilibav: sample format or channels are not supported")
+		return
+	}
+	return
+}
+
+// frameNbSamples returns the number of samples per channel of an audio frame
+func frameNbSamples(f *avutil.Frame) int {
+	return int((*C.AVFrame)(unsafe.Pointer(f)).nb_samples)
+}
+
+// frameSampleRate returns the sample rate of an audio frame
+func frameSampleRate(f *avutil.Frame) int {
+	return int((*C.AVFrame)(unsafe.Pointer(f)).sample_rate)
+}
@@ -0,0 +1,384 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+var countAVSyncCorrector uint64
+
+// AV sync modes
+const (
+	// Audio timestamps are shifted progressively. Audio frames should then go through a filterer using
+	// "aresample=async=1000" which stretches audio to match its timestamps
+	AVSyncModeAudio = "audio"
+	// Video frames are restamped continuously, and dropped or duplicated
+	AVSyncModeVideo = "video"
+)
+
+// AVSync represents an object capable of measuring the drift between an audio and a video stream sharing the same
+// clock, and of correcting it through an audio corrector and a video corrector
+type AVSync struct {
+	audioOffset time.Duration
+	audioPos    *time.Duration
+	dropped     int
+	duplicated  int
+	eh          *astiencoder.EventHandler
+	m           *sync.Mutex
+	o           AVSyncOptions
+	reportedAt  time.Time
+	videoPos    *time.Duration
+}
+
+// AVSyncOptions represents AV sync options
+type AVSyncOptions struct {
+	// Max audio timestamp shift per audio frame in audio mode. Default is 1ms
+	MaxAudioCorrection time.Duration
+	// Default is video
+	Mode string
+	// Period at which drift stats are emitted. Default is 1s
+	StatsPeriod time.Duration
+	// Drift above which correction starts. Default is 100ms
+	Threshold time.Duration
+}
+
+// AVSyncDrift represents AV sync drift stats
+// It is the payload of the AVSyncDriftReported event
+type AVSyncDrift struct {
+	AudioOffset time.Duration
+	// Positive when video is ahead of audio
+	Drift      time.Duration
+	Dropped    int
+	Duplicated int
+}
+
+// NewAVSync creates a new AV sync
+func NewAVSync(o AVSyncOptions, eh *astiencoder.EventHandler) (s *AVSync, err error) {
+	// Default options
+	if o.MaxAudioCorrection <= 0 {
+		o.MaxAudioCorrection = time.Millisecond
+	}
+	if o.Mode == "" {
+		o.Mode = AVSyncModeVideo
+	}
+	if o.StatsPeriod <= 0 {
+		o.StatsPeriod = time.Second
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 100 * time.Millisecond
+	}
+
+	// Invalid mode
+	if o.Mode != AVSyncModeAudio && o.Mode != AVSyncModeVideo {
+		err = fmt.Errorf("astilibav: invalid mode %s", o.Mode)
+		return
+	}
+
+	// Create sync
+	s = &AVSync{
+		eh: eh,
+		m:  &sync.Mutex{},
+		o:  o,
+	}
+	return
+}
+
+// drift must be called while holding the lock
+func (s *AVSync) drift() (time.Duration, bool) {
+	if s.audioPos == nil || s.videoPos == nil {
+		return 0, false
+	}
+	return *s.videoPos - *s.audioPos, true
+}
+
+// handleAudio stores the end position of an audio frame and returns the offset its timestamp must be shifted with
+func (s *AVSync) handleAudio(pos, duration time.Duration) time.Duration {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Store position
+	s.audioPos = astikit.DurationPtr(pos + s.audioOffset + duration)
+
+	// Correct
+	if d, ok := s.drift(); ok && s.o.Mode == AVSyncModeAudio {
+		if d > s.o.Threshold {
+			s.audioOffset += minDuration(d-s.o.Threshold, s.o.MaxAudioCorrection)
+		} else if d < -s.o.Threshold {
+			s.audioOffset -= minDuration(-d-s.o.Threshold, s.o.MaxAudioCorrection)
+		}
+		*s.audioPos = pos + s.audioOffset + duration
+	}
+	return s.audioOffset
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+type avSyncVideoAction int
+
+const (
+	avSyncVideoActionDispatch avSyncVideoAction = iota
+	avSyncVideoActionDrop
+	avSyncVideoActionDuplicate
+)
+
+// handleVideo stores the end position of a restamped video frame and returns what should be done with it
+func (s *AVSync) handleVideo(pos, duration time.Duration) (a avSyncVideoAction) {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Store position
+	s.videoPos = astikit.DurationPtr(pos + duration)
+
+	// Get action
+	if d, ok := s.drift(); ok && s.o.Mode == AVSyncModeVideo {
+		if d > s.o.Threshold {
+			// Frame is not dispatched therefore the video doesn't move forward
+			*s.videoPos = pos
+			s.dropped++
+			a = avSyncVideoActionDrop
+		} else if d < -s.o.Threshold {
+			*s.videoPos = pos + 2*duration
+			s.duplicated++
+			a = avSyncVideoActionDuplicate
+		}
+	}
+	return
+}
+
+func (s *AVSync) report(target interface{}) {
+	// Lock
+	s.m.Lock()
+
+	// Not time to report yet
+	d, ok := s.drift()
+	if !ok || time.Since(s.reportedAt) < s.o.StatsPeriod {
+		s.m.Unlock()
+		return
+	}
+	s.reportedAt = time.Now()
+
+	// Create payload
+	p := AVSyncDrift{
+		AudioOffset: s.audioOffset,
+		Drift:       d,
+		Dropped:     s.dropped,
+		Duplicated:  s.duplicated,
+	}
+	s.m.Unlock()
+
+	// Emit
+	s.eh.Emit(astiencoder.Event{
+		Name:    AVSyncDriftReported,
+		Payload: p,
+		Target:  target,
+	})
+}
+
+// AVSyncCorrector represents an object capable of correcting the frames of one of the streams of an AV sync
+type AVSyncCorrector struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *frameDispatcher
+	eh               *astiencoder.EventHandler
+	frameDuration    time.Duration
+	isAudio          bool
+	nextPts          *int64
+	o                AVSyncCorrectorOptions
+	s                *AVSync
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// AVSyncCorrectorOptions represents AV sync corrector options
+type AVSyncCorrectorOptions struct {
+	Node astiencoder.NodeOptions
+	// For video, frame rate must be set
+	OutputCtx Context
+}
+
+// NewAudioCorrector creates a new corrector for the audio stream
+func (s *AVSync) NewAudioCorrector(o AVSyncCorrectorOptions, c *astikit.Closer) *AVSyncCorrector {
+	return s.newCorrector(o, true, c)
+}
+
+// NewVideoCorrector creates a new corrector for the video stream
+func (s *AVSync) NewVideoCorrector(o AVSyncCorrectorOptions, c *astikit.Closer) (r *AVSyncCorrector, err error) {
+	// No frame rate
+	if o.OutputCtx.FrameRate.Num() <= 0 || o.OutputCtx.FrameRate.Den() <= 0 {
+		err = errors.New("astilibav: no frame rate")
+		return
+	}
+
+	// Create corrector
+	r = s.newCorrector(o, false, c)
+	r.frameDuration = time.Duration(1e9 * int64(o.OutputCtx.FrameRate.Den()) / int64(o.OutputCtx.FrameRate.Num()))
+	return
+}
+
+func (s *AVSync) newCorrector(o AVSyncCorrectorOptions, isAudio bool, c *astikit.Closer) (r *AVSyncCorrector) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countAVSyncCorrector, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("av_sync_corrector_%d", count), fmt.Sprintf("AV Sync Corrector #%d", count), "Corrects AV sync", "av sync corrector")
+
+	// Create corrector
+	r = &AVSyncCorrector{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               s.eh,
+		isAudio:          isAudio,
+		o:                o,
+		s:                s,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), s.eh)
+	r.d = newFrameDispatcher(r, s.eh)
+	r.addStats()
+	return
+}
+
+func (r *AVSyncCorrector) addStats() {
+	// Add incoming rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, r.statIncomingRate)
+
+	// Add work ratio
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, r.statWorkRatio)
+
+	// Add dispatcher stats
+	r.d.addStats(r.Stater())
+
+	// Add chan stats
+	r.c.AddStats(r.Stater())
+}
+
+// OutputCtx returns the output ctx
+func (r *AVSyncCorrector) OutputCtx() Context {
+	return r.o.OutputCtx
+}
+
+// Connect implements the FrameHandlerConnector interface
+func (r *AVSyncCorrector) Connect(h FrameHandler) {
+	// Add handler
+	r.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(r, h)
+}
+
+// Disconnect implements the FrameHandlerConnector interface
+func (r *AVSyncCorrector) Disconnect(h FrameHandler) {
+	// Delete handler
+	r.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(r, h)
+}
+
+// Start starts the corrector
+func (r *AVSyncCorrector) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer r.d.wait()
+
+		// Make sure to stop the chan properly
+		defer r.c.Stop()
+
+		// Start chan
+		r.c.Start(r.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (r *AVSyncCorrector) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, r); p == nil {
+		return
+	}
+	r.c.AddWithRelease(func() {
+		// Handle pause
+		defer r.HandlePause()
+
+		// Increment incoming rate
+		r.statIncomingRate.Add(1)
+
+		// Handle frame
+		if r.isAudio {
+			r.handleAudio(p)
+		} else {
+			r.handleVideo(p)
+		}
+
+		// Report
+		r.s.report(r)
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+func (r *AVSyncCorrector) handleAudio(p *FrameHandlerPayload) {
+	// Get position and duration
+	r.statWorkRatio.Begin()
+	pos := time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational))
+	var d time.Duration
+	if sr := frameSampleRate(p.Frame); sr > 0 {
+		d = time.Duration(frameNbSamples(p.Frame)) * time.Second / time.Duration(sr)
+	}
+
+	// Shift timestamp
+	if offset := r.s.handleAudio(pos, d); offset != 0 {
+		p.Frame.SetPts(p.Frame.Pts() + avutil.AvRescaleQ(int64(offset), nanosecondRational, p.Descriptor.TimeBase()))
+	}
+	r.statWorkRatio.End()
+
+	// Dispatch frame
+	r.d.dispatch(p.Frame, p.Descriptor)
+}
+
+func (r *AVSyncCorrector) handleVideo(p *FrameHandlerPayload) {
+	// Restamp continuously
+	r.statWorkRatio.Begin()
+	if r.nextPts == nil {
+		r.nextPts = astikit.Int64Ptr(p.Frame.Pts())
+	}
+	pos := time.Duration(avutil.AvRescaleQ(*r.nextPts, p.Descriptor.TimeBase(), nanosecondRational))
+	d := avutil.AvRescaleQ(int64(r.frameDuration), nanosecondRational, p.Descriptor.TimeBase())
+
+	// Get action
+	n := 1
+	switch r.s.handleVideo(pos, r.frameDuration) {
+	case avSyncVideoActionDrop:
+		n = 0
+	case avSyncVideoActionDuplicate:
+		n = 2
+	}
+	r.statWorkRatio.End()
+
+	// Dispatch frames
+	for i := 0; i < n; i++ {
+		p.Frame.SetPts(*r.nextPts)
+		*r.nextPts += d
+		r.d.dispatch(p.Frame, p.Descriptor)
+	}
+}
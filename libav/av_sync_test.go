@@ -0,0 +1,33 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAVSync(t *testing.T) {
+	s, err := NewAVSync(AVSyncOptions{}, astiencoder.NewEventHandler())
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), s.handleAudio(0, 20*time.Millisecond))
+	assert.Equal(t, avSyncVideoActionDispatch, s.handleVideo(0, 40*time.Millisecond))
+	s.handleAudio(300*time.Millisecond, 20*time.Millisecond)
+	assert.Equal(t, avSyncVideoActionDuplicate, s.handleVideo(40*time.Millisecond, 40*time.Millisecond))
+	s.handleAudio(320*time.Millisecond, 20*time.Millisecond)
+	assert.Equal(t, avSyncVideoActionDispatch, s.handleVideo(300*time.Millisecond, 40*time.Millisecond))
+	assert.Equal(t, avSyncVideoActionDrop, s.handleVideo(500*time.Millisecond, 40*time.Millisecond))
+	assert.Equal(t, 1, s.dropped)
+	assert.Equal(t, 1, s.duplicated)
+
+	s, err = NewAVSync(AVSyncOptions{Mode: AVSyncModeAudio}, astiencoder.NewEventHandler())
+	assert.NoError(t, err)
+	s.handleAudio(0, 20*time.Millisecond)
+	assert.Equal(t, avSyncVideoActionDispatch, s.handleVideo(500*time.Millisecond, 40*time.Millisecond))
+	assert.Equal(t, time.Millisecond, s.handleAudio(20*time.Millisecond, 20*time.Millisecond))
+	assert.Equal(t, 2*time.Millisecond, s.handleAudio(40*time.Millisecond, 20*time.Millisecond))
+
+	_, err = NewAVSync(AVSyncOptions{Mode: "invalid"}, astiencoder.NewEventHandler())
+	assert.Error(t, err)
+}
@@ -0,0 +1,258 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+var countCFRConverter uint64
+
+// CFRConverter represents an object capable of converting variable frame rate video frames to a constant frame rate
+// Frames are placed on a generated timeline: the last frame received is duplicated for every slot it covers, and
+// frames sharing the same slot are dropped
+type CFRConverter struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *frameDispatcher
+	descriptor       Descriptor
+	drops            *frameDrops
+	eh               *astiencoder.EventHandler
+	o                CFRConverterOptions
+	prev             *avutil.Frame
+	statDropped      *astikit.CounterRateStat
+	statDuplicated   *astikit.CounterRateStat
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+	t                *cfrTimeline
+}
+
+// CFRConverterOptions represents CFR converter options
+type CFRConverterOptions struct {
+	Drops FrameDropsOptions
+	Node  astiencoder.NodeOptions
+	// Frame rate must be set
+	OutputCtx Context
+}
+
+// NewCFRConverter creates a new CFR converter
+func NewCFRConverter(o CFRConverterOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (r *CFRConverter, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countCFRConverter, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("cfr_converter_%d", count), fmt.Sprintf("CFR Converter #%d", count), "Converts to constant frame rate", "cfr converter")
+
+	// No frame rate
+	if o.OutputCtx.FrameRate.Num() <= 0 || o.OutputCtx.FrameRate.Den() <= 0 {
+		err = errors.New("astilibav: no frame rate")
+		return
+	}
+
+	// Create converter
+	r = &CFRConverter{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               eh,
+		o:                o,
+		statDropped:      astikit.NewCounterRateStat(),
+		statDuplicated:   astikit.NewCounterRateStat(),
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+		t:                newCFRTimeline(time.Duration(1e9 * int64(o.OutputCtx.FrameRate.Den()) / int64(o.OutputCtx.FrameRate.Num()))),
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
+	r.d = newFrameDispatcher(r, eh)
+	r.drops = newFrameDrops(r, o.Drops, eh, []string{FrameDropReasonSameSlot}, []string{FrameDuplicateReasonMissing})
+	r.addStats()
+	return
+}
+
+func (r *CFRConverter) addStats() {
+	// Add incoming rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, r.statIncomingRate)
+
+	// Add dropped rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames dropped per second",
+		Label:       "Dropped rate",
+		Unit:        "fps",
+	}, r.statDropped)
+
+	// Add duplicated rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames duplicated per second",
+		Label:       "Duplicated rate",
+		Unit:        "fps",
+	}, r.statDuplicated)
+
+	// Add work ratio
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, r.statWorkRatio)
+
+	// Add frame drops stats
+	r.drops.addStats(r.Stater())
+
+	// Add dispatcher stats
+	r.d.addStats(r.Stater())
+
+	// Add chan stats
+	r.c.AddStats(r.Stater())
+}
+
+// OutputCtx returns the output ctx
+func (r *CFRConverter) OutputCtx() Context {
+	return r.o.OutputCtx
+}
+
+// Connect implements the FrameHandlerConnector interface
+func (r *CFRConverter) Connect(h FrameHandler) {
+	// Add handler
+	r.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(r, h)
+}
+
+// Disconnect implements the FrameHandlerConnector interface
+func (r *CFRConverter) Disconnect(h FrameHandler) {
+	// Delete handler
+	r.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(r, h)
+}
+
+// Start starts the converter
+func (r *CFRConverter) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer r.d.wait()
+
+		// Make sure the last frame is dispatched once the chan is stopped
+		defer r.flush()
+
+		// Make sure to stop the chan properly
+		defer r.c.Stop()
+
+		// Start chan
+		r.c.Start(r.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (r *CFRConverter) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, r); p == nil {
+		return
+	}
+	r.c.AddWithRelease(func() {
+		// Handle pause
+		defer r.HandlePause()
+
+		// Increment incoming rate
+		r.statIncomingRate.Add(1)
+		r.drops.incoming(time.Now())
+
+		// Place frame on the timeline
+		r.statWorkRatio.Begin()
+		first, count := r.t.add(time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational)))
+		r.statWorkRatio.End()
+
+		// Dispatch previous frame for every slot it covers
+		if r.prev != nil {
+			if count == 0 {
+				r.statDropped.Add(1)
+				r.drops.drop(FrameDropReasonSameSlot)
+			} else if count > 1 {
+				r.statDuplicated.Add(float64(count - 1))
+				r.drops.duplicate(FrameDuplicateReasonMissing, count-1)
+			}
+			r.dispatch(first, count)
+			r.d.p.put(r.prev)
+		}
+
+		// Store frame
+		r.prev = r.d.p.get()
+		if ret := avutil.AvFrameRef(r.prev, p.Frame); ret < 0 {
+			r.d.p.put(r.prev)
+			r.prev = nil
+			emitAvError(r, r.eh, ret, "avutil.AvFrameRef failed")
+			return
+		}
+
+		// Register frame with the memory budget
+		if !trackFrame(r.prev, nodeMemoryAccountant(r)) {
+			r.d.p.put(r.prev)
+			r.prev = nil
+			return
+		}
+		r.descriptor = p.Descriptor
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+func (r *CFRConverter) dispatch(first int64, count int) {
+	for i := 0; i < count; i++ {
+		r.prev.SetPts(avutil.AvRescaleQ(int64(r.t.position(first+int64(i))), nanosecondRational, r.descriptor.TimeBase()))
+		r.d.dispatch(r.prev, r.descriptor)
+	}
+}
+
+func (r *CFRConverter) flush() {
+	// No frame
+	if r.prev == nil {
+		return
+	}
+
+	// Dispatch
+	r.dispatch(r.t.next, 1)
+	r.d.p.put(r.prev)
+	r.prev = nil
+}
+
+type cfrTimeline struct {
+	d     time.Duration
+	next  int64
+	start *time.Duration
+}
+
+func newCFRTimeline(d time.Duration) *cfrTimeline {
+	return &cfrTimeline{d: d}
+}
+
+// add places a frame on the timeline and returns the slots the previous frame must be dispatched in
+func (t *cfrTimeline) add(pos time.Duration) (first int64, count int) {
+	// First frame
+	if t.start == nil {
+		t.start = astikit.DurationPtr(pos)
+	}
+
+	// Get slot
+	idx := int64(math.Round(float64(pos-*t.start) / float64(t.d)))
+
+	// Frame is in the current slot or in a past slot
+	first = t.next
+	if idx <= t.next {
+		return
+	}
+	count = int(idx - t.next)
+	t.next = idx
+	return
+}
+
+func (t *cfrTimeline) position(slot int64) time.Duration {
+	return *t.start + time.Duration(slot)*t.d
+}
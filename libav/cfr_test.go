@@ -0,0 +1,27 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCFRTimeline(t *testing.T) {
+	tl := newCFRTimeline(40 * time.Millisecond)
+	for _, v := range []struct {
+		count int
+		first int64
+		pos   time.Duration
+	}{
+		{pos: time.Second},
+		{count: 1, pos: time.Second + 35*time.Millisecond},
+		{first: 1, pos: time.Second + 50*time.Millisecond},
+		{count: 3, first: 1, pos: time.Second + 170*time.Millisecond},
+	} {
+		first, count := tl.add(v.pos)
+		assert.Equal(t, v.first, first)
+		assert.Equal(t, v.count, count)
+	}
+	assert.Equal(t, time.Second+160*time.Millisecond, tl.position(4))
+}
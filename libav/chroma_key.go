@@ -0,0 +1,128 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// ChromaKeyFilterer represents an object capable of compositing foreground video frames over background video frames
+// by keying out a color of the foreground (e.g. a green screen)
+// Similarity and blend can be updated while the filterer is running
+type ChromaKeyFilterer struct {
+	*Filterer
+	m *sync.Mutex
+	o ChromaKeyFiltererOptions
+}
+
+// ChromaKeyFiltererOptions represents chroma key filterer options
+type ChromaKeyFiltererOptions struct {
+	Background astiencoder.Node
+	// Between 0 and 1. 0 makes keyed pixels fully transparent, higher values make them partially transparent
+	Blend float64
+	// Color to key out. Default is "0x00FF00"
+	Color      string
+	Foreground astiencoder.Node
+	Node       astiencoder.NodeOptions
+	OutputCtx  Context
+	Restamper  FrameRestamper
+	// Between 0.01 and 1. 0.01 only matches the exact key color. Default is 0.1
+	Similarity float64
+	// Position of the foreground's top left corner in the background
+	X, Y int
+}
+
+// NewChromaKeyFilterer creates a new chroma key filterer
+func NewChromaKeyFilterer(o ChromaKeyFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *ChromaKeyFilterer, err error) {
+	// No background or foreground
+	if o.Background == nil || o.Foreground == nil {
+		err = errors.New("astilibav: background and foreground are mandatory")
+		return
+	}
+
+	// Default options
+	if o.Color == "" {
+		o.Color = "0x00FF00"
+	}
+	if o.Similarity == 0 {
+		o.Similarity = 0.1
+	}
+
+	// Create filterer
+	f = &ChromaKeyFilterer{
+		m: &sync.Mutex{},
+		o: o,
+	}
+
+	// Get content
+	var content string
+	if content, err = f.content(o.Similarity, o.Blend); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content: content,
+		Inputs: map[string]astiencoder.Node{
+			"bg": o.Background,
+			"fg": o.Foreground,
+		},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *ChromaKeyFilterer) content(similarity, blend float64) (string, error) {
+	// Invalid similarity
+	if similarity < 0.01 || similarity > 1 {
+		return "", fmt.Errorf("astilibav: similarity %v is not between 0.01 and 1", similarity)
+	}
+
+	// Invalid blend
+	if blend < 0 || blend > 1 {
+		return "", fmt.Errorf("astilibav: blend %v is not between 0 and 1", blend)
+	}
+	return fmt.Sprintf("[fg]chromakey=color=%s:similarity=%s:blend=%s[keyed];[bg][keyed]overlay=x=%d:y=%d:format=auto", escapeFilterOption(f.o.Color), formatFilterFloat(similarity), formatFilterFloat(blend), f.o.X, f.o.Y), nil
+}
+
+// SetSimilarity updates the similarity
+func (f *ChromaKeyFilterer) SetSimilarity(similarity float64) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.update(similarity, f.o.Blend)
+}
+
+// SetBlend updates the blend
+func (f *ChromaKeyFilterer) SetBlend(blend float64) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.update(f.o.Similarity, blend)
+}
+
+func (f *ChromaKeyFilterer) update(similarity, blend float64) (err error) {
+	// Get content
+	var content string
+	if content, err = f.content(similarity, blend); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Set content
+	if err = f.SetContent(content); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+
+	// Update options
+	f.o.Similarity, f.o.Blend = similarity, blend
+	return
+}
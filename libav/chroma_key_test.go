@@ -0,0 +1,18 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChromaKeyContent(t *testing.T) {
+	f := &ChromaKeyFilterer{o: ChromaKeyFiltererOptions{Color: "0x00FF00", X: 10, Y: 20}}
+	c, err := f.content(0.15, 0.05)
+	assert.NoError(t, err)
+	assert.Equal(t, "[fg]chromakey=color=0x00FF00:similarity=0.15:blend=0.05[keyed];[bg][keyed]overlay=x=10:y=20:format=auto", c)
+	_, err = f.content(0, 0)
+	assert.Error(t, err)
+	_, err = f.content(0.1, 2)
+	assert.Error(t, err)
+}
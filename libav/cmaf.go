@@ -0,0 +1,192 @@
+package astilibav
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// CMAFSegmenter represents an object capable of splitting the fragmented mp4 files rotated by a muxer into one
+// init segment and media segments, so that the same segments can be referenced by several packagers (e.g. HLS and
+// DASH) without encoding or segmenting twice
+// The muxer should use the "mp4" format with the "cmaf+frag_keyframe+empty_moov+default_base_moof" movflags
+// Timed metadata can be injected in media segments as emsg boxes
+type CMAFSegmenter struct {
+	eh          *astiencoder.EventHandler
+	initWritten bool
+	mx          *Muxer
+	o           CMAFSegmenterOptions
+	position    time.Duration
+	q           *timedMetadataQueue
+}
+
+// CMAFSegmenterOptions represents CMAF segmenter options
+type CMAFSegmenterOptions struct {
+	// Path of the init segment
+	InitURL string
+}
+
+// NewCMAFSegmenter creates a new CMAF segmenter
+// The muxer must have rotation enabled
+func NewCMAFSegmenter(o CMAFSegmenterOptions, m *Muxer, eh *astiencoder.EventHandler) (s *CMAFSegmenter, err error) {
+	// Create segmenter
+	s = &CMAFSegmenter{
+		eh: eh,
+		mx: m,
+		o:  o,
+		q:  newTimedMetadataQueue(),
+	}
+
+	// No rotation
+	if m.rotation == nil {
+		err = errors.New("astilibav: muxer rotation is disabled")
+		return
+	}
+
+	// Files are played back to back
+	m.rotation.o.KeepTimestamps = true
+
+	// No init url
+	if o.InitURL == "" {
+		err = errors.New("astilibav: no init url")
+		return
+	}
+
+	// Handle muxer files
+	eh.Add(m, MuxerFileCompleted, func(e astiencoder.Event) bool {
+		if err := s.handleFile(e.Payload.(MuxerFile)); err != nil {
+			eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: handling file in cmaf segmenter failed: %w", err)))
+		}
+		return false
+	})
+	return
+}
+
+// InitURL returns the path of the init segment
+func (s *CMAFSegmenter) InitURL() string {
+	return s.o.InitURL
+}
+
+// ScheduleEmsg schedules metadata to be injected as an emsg box in the media segment containing its position
+// Positions start at 0 with the first segment. Metadata whose segment has already been written is injected in the
+// next segment
+func (s *CMAFSegmenter) ScheduleEmsg(m TimedMetadata) {
+	s.q.add(m)
+}
+
+func (s *CMAFSegmenter) handleFile(f MuxerFile) (err error) {
+	// Read file
+	var b []byte
+	if b, err = ioutil.ReadFile(f.URL); err != nil {
+		err = fmt.Errorf("astilibav: reading %s failed: %w", f.URL, err)
+		return
+	}
+
+	// Split
+	var init, media []byte
+	if init, media, err = splitCMAFFile(b); err != nil {
+		err = fmt.Errorf("astilibav: splitting %s failed: %w", f.URL, err)
+		return
+	}
+
+	// Inject emsg boxes
+	s.position += f.Duration
+	var emsgs []byte
+	for _, m := range s.q.pop(s.position) {
+		emsgs = append(emsgs, emsgBox(m)...)
+	}
+	media = insertCMAFBoxes(media, emsgs)
+
+	// Write init segment
+	// Streams don't change between files, therefore it's only written once
+	if !s.initWritten {
+		if err = ioutil.WriteFile(s.o.InitURL, init, 0666); err != nil {
+			err = fmt.Errorf("astilibav: writing init segment to %s failed: %w", s.o.InitURL, err)
+			return
+		}
+		s.initWritten = true
+	}
+
+	// Write media segment
+	if err = ioutil.WriteFile(f.URL, media, 0666); err != nil {
+		err = fmt.Errorf("astilibav: writing media segment to %s failed: %w", f.URL, err)
+		return
+	}
+
+	// Emit event
+	s.eh.Emit(astiencoder.Event{
+		Name:    CMAFSegmenterSegmentCompleted,
+		Payload: f,
+		Target:  s,
+	})
+	return
+}
+
+// insertCMAFBoxes inserts boxes at the beginning of a media segment, after its styp box if any
+func insertCMAFBoxes(media, boxes []byte) []byte {
+	// No boxes
+	if len(boxes) == 0 {
+		return media
+	}
+
+	// Get offset
+	var offset int
+	if len(media) >= 8 && string(media[4:8]) == "styp" {
+		if size := int(binary.BigEndian.Uint32(media[:4])); size >= 8 && size <= len(media) {
+			offset = size
+		}
+	}
+
+	// Insert
+	b := make([]byte, 0, len(media)+len(boxes))
+	b = append(b, media[:offset]...)
+	b = append(b, boxes...)
+	return append(b, media[offset:]...)
+}
+
+// splitCMAFFile splits top level mp4 boxes between the init segment (ftyp and moov) and the media segment (everything
+// else, e.g. styp, sidx, moof and mdat)
+func splitCMAFFile(b []byte) (init, media []byte, err error) {
+	for len(b) > 0 {
+		// Invalid header
+		if len(b) < 8 {
+			err = fmt.Errorf("astilibav: invalid box header of size %d", len(b))
+			return
+		}
+
+		// Get size
+		size := uint64(binary.BigEndian.Uint32(b[:4]))
+		switch size {
+		case 0:
+			// Box extends to the end of the file
+			size = uint64(len(b))
+		case 1:
+			// Size is stored on 64 bits
+			if len(b) < 16 {
+				err = fmt.Errorf("astilibav: invalid large box header of size %d", len(b))
+				return
+			}
+			size = binary.BigEndian.Uint64(b[8:16])
+		}
+
+		// Invalid size
+		if size < 8 || size > uint64(len(b)) {
+			err = fmt.Errorf("astilibav: invalid box size %d", size)
+			return
+		}
+
+		// Dispatch box
+		switch string(b[4:8]) {
+		case "ftyp", "moov":
+			init = append(init, b[:size]...)
+		default:
+			media = append(media, b[:size]...)
+		}
+		b = b[size:]
+	}
+	return
+}
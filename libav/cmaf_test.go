@@ -0,0 +1,33 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCMAFFile(t *testing.T) {
+	ftyp := []byte{0, 0, 0, 9, 'f', 't', 'y', 'p', 1}
+	moov := []byte{0, 0, 0, 8, 'm', 'o', 'o', 'v'}
+	moof := []byte{0, 0, 0, 10, 'm', 'o', 'o', 'f', 2, 3}
+	mdat := []byte{0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0, 0, 0, 0, 0, 0, 17, 4}
+	var b []byte
+	for _, v := range [][]byte{ftyp, moov, moof, mdat} {
+		b = append(b, v...)
+	}
+	init, media, err := splitCMAFFile(b)
+	assert.NoError(t, err)
+	assert.Equal(t, append(append([]byte{}, ftyp...), moov...), init)
+	assert.Equal(t, append(append([]byte{}, moof...), mdat...), media)
+	_, _, err = splitCMAFFile([]byte{0, 0, 0, 20, 'm', 'o', 'o', 'f'})
+	assert.Error(t, err)
+}
+
+func TestInsertCMAFBoxes(t *testing.T) {
+	styp := []byte{0, 0, 0, 8, 's', 't', 'y', 'p'}
+	moof := []byte{0, 0, 0, 8, 'm', 'o', 'o', 'f'}
+	emsg := []byte{0, 0, 0, 8, 'e', 'm', 's', 'g'}
+	assert.Equal(t, append(append([]byte{}, emsg...), moof...), insertCMAFBoxes(moof, emsg))
+	assert.Equal(t, append(append(append([]byte{}, styp...), emsg...), moof...), insertCMAFBoxes(append(append([]byte{}, styp...), moof...), emsg))
+	assert.Equal(t, moof, insertCMAFBoxes(moof, nil))
+}
@@ -0,0 +1,122 @@
+package astilibav
+
+import (
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// Congestion represents the congestion status of an output
+type Congestion struct {
+	Congested bool
+	Output    astiencoder.Node
+	// Percentage of time spent writing to the output during the last period
+	WriteRatio float64
+}
+
+// CongestionHandler represents an object that can handle congestion reports of outputs
+// HandleCongestion is called by the output itself and must not block
+type CongestionHandler interface {
+	HandleCongestion(c Congestion)
+}
+
+// CongestionDetectorOptions represents congestion detector options
+// An output is considered congested when it spends too much time writing, which happens when the network can't keep
+// up with the stream
+type CongestionDetectorOptions struct {
+	// Percentage of time spent writing under which an output is not considered congested anymore. Default is 50
+	ClearThreshold float64
+	// Percentage of time spent writing above which an output is considered congested. Default is 80
+	CongestedThreshold float64
+	// Default is 1s
+	Period time.Duration
+}
+
+type congestionDetector struct {
+	congested bool
+	hs        []CongestionHandler
+	m         *sync.Mutex
+	n         astiencoder.Node
+	o         CongestionDetectorOptions
+	start     time.Time
+	writing   time.Duration
+}
+
+func newCongestionDetector(n astiencoder.Node, o CongestionDetectorOptions) *congestionDetector {
+	// Default options
+	if o.ClearThreshold <= 0 {
+		o.ClearThreshold = 50
+	}
+	if o.CongestedThreshold <= 0 {
+		o.CongestedThreshold = 80
+	}
+	if o.Period <= 0 {
+		o.Period = time.Second
+	}
+
+	// Create detector
+	return &congestionDetector{
+		m: &sync.Mutex{},
+		n: n,
+		o: o,
+	}
+}
+
+func (d *congestionDetector) addHandler(h CongestionHandler) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.hs = append(d.hs, h)
+}
+
+// add adds the duration of a write ending at t
+func (d *congestionDetector) add(t time.Time, writing time.Duration) {
+	// Lock
+	d.m.Lock()
+
+	// No handlers
+	if len(d.hs) == 0 {
+		d.m.Unlock()
+		return
+	}
+
+	// Start period
+	if d.start.IsZero() {
+		d.start = t.Add(-writing)
+	}
+
+	// Update writing duration
+	d.writing += writing
+
+	// Period is not over
+	elapsed := t.Sub(d.start)
+	if elapsed < d.o.Period {
+		d.m.Unlock()
+		return
+	}
+
+	// Get congestion
+	c := Congestion{
+		Congested:  d.congested,
+		Output:     d.n,
+		WriteRatio: float64(d.writing) / float64(elapsed) * 100,
+	}
+	if c.WriteRatio >= d.o.CongestedThreshold {
+		c.Congested = true
+	} else if c.WriteRatio < d.o.ClearThreshold {
+		c.Congested = false
+	}
+
+	// Reset period
+	d.congested = c.Congested
+	d.start = t
+	d.writing = 0
+	hs := make([]CongestionHandler, len(d.hs))
+	copy(hs, d.hs)
+	d.m.Unlock()
+
+	// Report
+	for _, h := range hs {
+		h.HandleCongestion(c)
+	}
+}
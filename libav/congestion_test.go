@@ -0,0 +1,41 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedCongestionHandler struct {
+	cs []Congestion
+}
+
+func (h *mockedCongestionHandler) HandleCongestion(c Congestion) {
+	h.cs = append(h.cs, c)
+}
+
+func TestCongestionDetector(t *testing.T) {
+	d := newCongestionDetector(nil, CongestionDetectorOptions{Period: time.Second})
+	h := &mockedCongestionHandler{}
+	d.addHandler(h)
+	n := time.Unix(0, 0)
+
+	// Congested
+	d.add(n.Add(500*time.Millisecond), 500*time.Millisecond)
+	assert.Len(t, h.cs, 0)
+	d.add(n.Add(time.Second), 400*time.Millisecond)
+	assert.Len(t, h.cs, 1)
+	assert.True(t, h.cs[0].Congested)
+	assert.Equal(t, 90.0, h.cs[0].WriteRatio)
+
+	// Between thresholds
+	d.add(n.Add(2*time.Second), 600*time.Millisecond)
+	assert.Len(t, h.cs, 2)
+	assert.True(t, h.cs[1].Congested)
+
+	// Cleared
+	d.add(n.Add(3*time.Second), 100*time.Millisecond)
+	assert.Len(t, h.cs, 3)
+	assert.False(t, h.cs[2].Congested)
+}
@@ -18,8 +18,11 @@ type Context struct {
 	CodecType    avcodec.MediaType
 	Dict         *Dict
 	GlobalHeader bool
-	ThreadCount  *int
-	TimeBase     avutil.Rational
+	// 0 means as many threads as there are cores. If nil and ThreadType is set, 0 is used
+	ThreadCount *int
+	// Encoders only. 0 means ffmpeg's default
+	ThreadType ThreadType
+	TimeBase   avutil.Rational
 
 	// Audio
 	ChannelLayout uint64
@@ -28,12 +31,17 @@ type Context struct {
 	SampleRate    int
 
 	// Video
-	FrameRate         avutil.Rational
-	GopSize           int
+	FrameRate avutil.Rational
+	GopSize   int
+	// If set, frames live in hardware memory and are exchanged between nodes without being copied to system memory
+	HardwareFramesCtx *HardwareFramesContext
 	Height            int
 	PixelFormat       avutil.PixelFormat
 	SampleAspectRatio avutil.Rational
-	Width             int
+	// Number of slices frames are split into when encoding. 0 means ffmpeg's default
+	// Tiles are codec specific and must be set through the dict (e.g. "tile-columns" for libvpx)
+	Slices int
+	Width  int
 }
 
 type OutputContexter interface {
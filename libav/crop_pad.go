@@ -0,0 +1,205 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+// CropPadFilterer represents an object capable of cropping a rectangle of video frames and of scaling it to the
+// output size. The rectangle can exceed the frame in which case the frame is padded. It can be changed while the
+// filterer is running, with an optional smooth transition
+type CropPadFilterer struct {
+	*Filterer
+	applied    Rect
+	from       Rect
+	inputCtx   Context
+	m          *sync.Mutex
+	o          CropPadFiltererOptions
+	start      *int64
+	to         Rect
+	transition time.Duration
+}
+
+// CropPadFiltererOptions represents crop/pad filterer options
+type CropPadFiltererOptions struct {
+	Input astiencoder.Node
+	Node  astiencoder.NodeOptions
+	// Width and height are used as the output size
+	OutputCtx Context
+	// Default is the whole frame
+	Rect      *Rect
+	Restamper FrameRestamper
+}
+
+// Rect represents a rectangle
+type Rect struct {
+	Height int
+	Width  int
+	X      int
+	Y      int
+}
+
+// NewCropPadFilterer creates a new crop/pad filterer
+func NewCropPadFilterer(o CropPadFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *CropPadFilterer, err error) {
+	// Get input ctx
+	v, ok := o.Input.(OutputContexter)
+	if !ok {
+		err = errors.New("astilibav: input is not an OutputContexter")
+		return
+	}
+
+	// Create filterer
+	f = &CropPadFilterer{
+		inputCtx: v.OutputCtx(),
+		m:        &sync.Mutex{},
+		o:        o,
+	}
+
+	// Get rect
+	r := Rect{Height: f.inputCtx.Height, Width: f.inputCtx.Width}
+	if o.Rect != nil {
+		r = *o.Rect
+	}
+	if err = r.validate(); err != nil {
+		err = fmt.Errorf("astilibav: validating rect failed: %w", err)
+		return
+	}
+	f.applied, f.from, f.to = r, r, r
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   cropPadContent(f.inputCtx, o.OutputCtx, r),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (r Rect) validate() error {
+	if r.Width <= 0 || r.Height <= 0 {
+		return fmt.Errorf("astilibav: invalid size %dx%d", r.Width, r.Height)
+	}
+	return nil
+}
+
+// cropPadContent pads the input so that it contains the rect, crops the rect and scales it to fit the output size
+func cropPadContent(in, out Context, r Rect) string {
+	// Round to even values since most pixel formats are subsampled
+	r = Rect{
+		Height: cropPadEven(r.Height),
+		Width:  cropPadEven(r.Width),
+		X:      cropPadEven(r.X),
+		Y:      cropPadEven(r.Y),
+	}
+
+	// Pad
+	var fs []string
+	left, top := cropPadMax(0, -r.X), cropPadMax(0, -r.Y)
+	w, h := cropPadMax(in.Width, r.X+r.Width)+left, cropPadMax(in.Height, r.Y+r.Height)+top
+	if w != in.Width || h != in.Height {
+		fs = append(fs, fmt.Sprintf("pad=%d:%d:%d:%d", w, h, left, top))
+	}
+
+	// Crop
+	fs = append(fs, fmt.Sprintf("crop=%d:%d:%d:%d", r.Width, r.Height, r.X+left, r.Y+top))
+
+	// Scale
+	fs = append(fs, fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease", out.Width, out.Height))
+	fs = append(fs, fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2", out.Width, out.Height))
+	fs = append(fs, "setsar=1")
+	return strings.Join(fs, ",")
+}
+
+func cropPadEven(v int) int {
+	return int(math.Round(float64(v)/2)) * 2
+}
+
+func cropPadMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// SetRect updates the rectangle. If transition is > 0, the rectangle is interpolated from the current one during
+// this duration, based on frame timestamps
+func (f *CropPadFilterer) SetRect(r Rect, transition time.Duration) (err error) {
+	// Validate
+	if err = r.validate(); err != nil {
+		err = fmt.Errorf("astilibav: validating rect failed: %w", err)
+		return
+	}
+
+	// Lock
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	// Update
+	f.from = f.applied
+	f.start = nil
+	f.to = r
+	f.transition = transition
+	return
+}
+
+// rect returns the rect that should be applied to the frame
+func (f *CropPadFilterer) rect(pts int64, timeBase avutil.Rational) Rect {
+	// No transition
+	if f.transition <= 0 || f.from == f.to {
+		return f.to
+	}
+
+	// Store start
+	if f.start == nil {
+		f.start = astikit.Int64Ptr(pts)
+	}
+
+	// Get progress
+	p := float64(avutil.AvRescaleQ(pts-*f.start, timeBase, nanosecondRational)) / float64(f.transition)
+	if p >= 1 {
+		return f.to
+	} else if p < 0 {
+		p = 0
+	}
+
+	// Interpolate
+	i := func(a, b int) int { return a + int(math.Round(float64(b-a)*p)) }
+	return Rect{
+		Height: i(f.from.Height, f.to.Height),
+		Width:  i(f.from.Width, f.to.Width),
+		X:      i(f.from.X, f.to.X),
+		Y:      i(f.from.Y, f.to.Y),
+	}
+}
+
+// HandleFrame implements the FrameHandler interface
+func (f *CropPadFilterer) HandleFrame(p *FrameHandlerPayload) {
+	// Get rect
+	f.m.Lock()
+	r := f.rect(p.Frame.Pts(), p.Descriptor.TimeBase())
+	if r != f.applied {
+		// Update content
+		if err := f.SetContent(cropPadContent(f.inputCtx, f.o.OutputCtx, r)); err != nil {
+			f.eh.Emit(astiencoder.EventError(f, fmt.Errorf("astilibav: setting content failed: %w", err)))
+		} else {
+			f.applied = r
+		}
+	}
+	f.m.Unlock()
+
+	// Handle frame
+	f.Filterer.HandleFrame(p)
+}
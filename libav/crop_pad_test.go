@@ -0,0 +1,14 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCropPadContent(t *testing.T) {
+	in := Context{Height: 1080, Width: 1920}
+	out := Context{Height: 720, Width: 1280}
+	assert.Equal(t, "crop=960:540:480:270,scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1", cropPadContent(in, out, Rect{Height: 540, Width: 960, X: 480, Y: 270}))
+	assert.Equal(t, "pad=2020:1080:100:0,crop=1002:1080:0:0,scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1", cropPadContent(in, out, Rect{Height: 1080, Width: 1001, X: -100, Y: 0}))
+}
@@ -0,0 +1,232 @@
+package astilibav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// DASHManifest represents an object capable of writing a live DASH manifest referencing the segments of a CMAF
+// segmenter
+type DASHManifest struct {
+	eh        *astiencoder.EventHandler
+	m         *sync.Mutex
+	o         DASHManifestOptions
+	s         *CMAFSegmenter
+	segments  []dashSegment
+	startedAt time.Time
+}
+
+// DASHManifestOptions represents DASH manifest options
+type DASHManifestOptions struct {
+	// Bandwidth of the representation in bits per second
+	Bandwidth int
+	// Codecs of the representation as defined in RFC 6381 (e.g. "avc1.64001f,mp4a.40.2")
+	Codecs string
+	// Default is "video/mp4"
+	MimeType string
+	// Default is 2s
+	MinBufferTime time.Duration
+	// Max number of segments in the manifest. 0 means no limit
+	Size int
+	// Path of the manifest
+	URL string
+}
+
+type dashSegment struct {
+	duration time.Duration
+	start    time.Duration
+	url      string
+}
+
+// NewDASHManifest creates a new DASH manifest
+func NewDASHManifest(o DASHManifestOptions, s *CMAFSegmenter, eh *astiencoder.EventHandler) (m *DASHManifest, err error) {
+	// No url
+	if o.URL == "" {
+		err = errors.New("astilibav: no url")
+		return
+	}
+
+	// Default options
+	if o.MimeType == "" {
+		o.MimeType = "video/mp4"
+	}
+	if o.MinBufferTime <= 0 {
+		o.MinBufferTime = 2 * time.Second
+	}
+
+	// Create manifest
+	m = &DASHManifest{
+		eh: eh,
+		m:  &sync.Mutex{},
+		o:  o,
+		s:  s,
+	}
+
+	// Handle segments
+	eh.Add(s, CMAFSegmenterSegmentCompleted, func(e astiencoder.Event) bool {
+		if err := m.handleSegment(e.Payload.(MuxerFile)); err != nil {
+			eh.Emit(astiencoder.EventError(s.mx, fmt.Errorf("astilibav: handling segment in dash manifest failed: %w", err)))
+		}
+		return false
+	})
+	return
+}
+
+func (m *DASHManifest) handleSegment(f MuxerFile) (err error) {
+	// Lock
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	// Add segment
+	var start time.Duration
+	if len(m.segments) == 0 {
+		m.startedAt = f.StartedAt
+	} else {
+		l := m.segments[len(m.segments)-1]
+		start = l.start + l.duration
+	}
+	m.segments = append(m.segments, dashSegment{
+		duration: f.Duration,
+		start:    start,
+		url:      f.URL,
+	})
+
+	// Slide window
+	if m.o.Size > 0 && len(m.segments) > m.o.Size {
+		m.segments = m.segments[len(m.segments)-m.o.Size:]
+	}
+
+	// Get bytes
+	var b []byte
+	if b, err = m.bytes(time.Now()); err != nil {
+		err = fmt.Errorf("astilibav: getting bytes failed: %w", err)
+		return
+	}
+
+	// Write
+	if err = writeFileAtomically(m.o.URL, b); err != nil {
+		err = fmt.Errorf("astilibav: writing manifest failed: %w", err)
+		return
+	}
+	return
+}
+
+type dashMPD struct {
+	XMLName               xml.Name   `xml:"urn:mpeg:dash:schema:mpd:2011 MPD"`
+	AvailabilityStartTime string     `xml:"availabilityStartTime,attr"`
+	MinBufferTime         string     `xml:"minBufferTime,attr"`
+	MinimumUpdatePeriod   string     `xml:"minimumUpdatePeriod,attr,omitempty"`
+	Period                dashPeriod `xml:"Period"`
+	Profiles              string     `xml:"profiles,attr"`
+	PublishTime           string     `xml:"publishTime,attr"`
+	TimeShiftBufferDepth  string     `xml:"timeShiftBufferDepth,attr,omitempty"`
+	Type                  string     `xml:"type,attr"`
+}
+
+type dashPeriod struct {
+	AdaptationSet dashAdaptationSet `xml:"AdaptationSet"`
+	ID            string            `xml:"id,attr"`
+	Start         string            `xml:"start,attr"`
+}
+
+type dashAdaptationSet struct {
+	Representation   dashRepresentation `xml:"Representation"`
+	SegmentAlignment bool               `xml:"segmentAlignment,attr"`
+}
+
+type dashRepresentation struct {
+	Bandwidth   int             `xml:"bandwidth,attr"`
+	Codecs      string          `xml:"codecs,attr,omitempty"`
+	ID          string          `xml:"id,attr"`
+	MimeType    string          `xml:"mimeType,attr"`
+	SegmentList dashSegmentList `xml:"SegmentList"`
+}
+
+type dashSegmentList struct {
+	Initialization  dashURL             `xml:"Initialization"`
+	SegmentTimeline dashSegmentTimeline `xml:"SegmentTimeline"`
+	SegmentURLs     []dashSegmentURL    `xml:"SegmentURL"`
+	Timescale       int                 `xml:"timescale,attr"`
+}
+
+type dashURL struct {
+	SourceURL string `xml:"sourceURL,attr"`
+}
+
+type dashSegmentTimeline struct {
+	S []dashS `xml:"S"`
+}
+
+type dashS struct {
+	D int64 `xml:"d,attr"`
+	T int64 `xml:"t,attr"`
+}
+
+type dashSegmentURL struct {
+	Media string `xml:"media,attr"`
+}
+
+func dashDuration(d time.Duration) string {
+	return fmt.Sprintf("PT%.3fS", d.Seconds())
+}
+
+func (m *DASHManifest) bytes(now time.Time) (b []byte, err error) {
+	// Create mpd
+	mpd := dashMPD{
+		AvailabilityStartTime: m.startedAt.UTC().Format(time.RFC3339Nano),
+		MinBufferTime:         dashDuration(m.o.MinBufferTime),
+		Period: dashPeriod{
+			AdaptationSet: dashAdaptationSet{
+				Representation: dashRepresentation{
+					Bandwidth: m.o.Bandwidth,
+					Codecs:    m.o.Codecs,
+					ID:        "0",
+					MimeType:  m.o.MimeType,
+					SegmentList: dashSegmentList{
+						Initialization: dashURL{SourceURL: relativeURI(m.o.URL, m.s.InitURL())},
+						Timescale:      1000,
+					},
+				},
+				SegmentAlignment: true,
+			},
+			ID:    "0",
+			Start: dashDuration(0),
+		},
+		Profiles:    "urn:mpeg:dash:profile:isoff-live:2011",
+		PublishTime: now.UTC().Format(time.RFC3339Nano),
+		Type:        "dynamic",
+	}
+
+	// Loop through segments
+	var total time.Duration
+	l := &mpd.Period.AdaptationSet.Representation.SegmentList
+	for _, s := range m.segments {
+		l.SegmentTimeline.S = append(l.SegmentTimeline.S, dashS{
+			D: s.duration.Milliseconds(),
+			T: s.start.Milliseconds(),
+		})
+		l.SegmentURLs = append(l.SegmentURLs, dashSegmentURL{Media: relativeURI(m.o.URL, s.url)})
+		total += s.duration
+	}
+
+	// Update period
+	if len(m.segments) > 0 {
+		mpd.MinimumUpdatePeriod = dashDuration(m.segments[len(m.segments)-1].duration)
+	}
+	if m.o.Size > 0 {
+		mpd.TimeShiftBufferDepth = dashDuration(total)
+	}
+
+	// Marshal
+	if b, err = xml.MarshalIndent(mpd, "", "  "); err != nil {
+		err = fmt.Errorf("astilibav: marshaling failed: %w", err)
+		return
+	}
+	b = append([]byte(xml.Header), b...)
+	return
+}
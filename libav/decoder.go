@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -16,20 +17,35 @@ var countDecoder uint64
 // Decoder represents an object capable of decoding packets
 type Decoder struct {
 	*astiencoder.BaseNode
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	ctxCodec         *avcodec.Context
 	d                *frameDispatcher
+	definition       DecoderDefinitionOptions
+	download         bool
 	eh               *astiencoder.EventHandler
 	outputCtx        Context
+	spans            *pendingSpans
 	statIncomingRate *astikit.CounterRateStat
 	statWorkRatio    *astikit.DurationPercentageStat
 }
 
 // DecoderOptions represents decoder options
 type DecoderOptions struct {
+	// If set, the decoder is found by name instead of by the codec params' codec id
+	CodecName   string
 	CodecParams *avcodec.CodecParameters
-	Node        astiencoder.NodeOptions
-	OutputCtx   Context
+	Dict        *Dict
+	// If true, frames decoded in hardware memory are always copied to system memory before being dispatched.
+	// Otherwise they're only copied for handlers that can't handle them (see HardwareFrameHandler)
+	DownloadHardwareFrames bool
+	// If set, the decoder uses the device's hardware acceleration when the codec supports it
+	HardwareDevice *HardwareDeviceContext
+	Node           astiencoder.NodeOptions
+	OutputCtx      Context
+	// Number of threads used by the codec. 0 means as many threads as there are cores. Default is libav's
+	ThreadCount *int
+	// Default is libav's
+	ThreadType ThreadType
 }
 
 // NewDecoder creates a new decoder
@@ -40,22 +56,31 @@ func NewDecoder(o DecoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 
 	// Create decoder
 	d = &Decoder{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c: astiencoder.NewQueue(o.Node.Queue),
+		definition: DecoderDefinitionOptions{
+			CodecName:   o.CodecName,
+			Dict:        o.Dict.String(),
+			ThreadCount: o.ThreadCount,
+		},
+		download:         o.DownloadHardwareFrames,
 		eh:               eh,
 		outputCtx:        o.OutputCtx,
+		spans:            newPendingSpans(),
 		statIncomingRate: astikit.NewCounterRateStat(),
 		statWorkRatio:    astikit.NewDurationPercentageStat(),
 	}
 	d.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(d), eh)
-	d.d = newFrameDispatcher(d, eh, c)
+	d.d = newFrameDispatcher(d, eh)
 	d.addStats()
 
 	// Find decoder
 	var cdc *avcodec.Codec
-	if cdc = avcodec.AvcodecFindDecoder(o.CodecParams.CodecId()); cdc == nil {
+	if o.CodecName != "" {
+		if cdc = avcodec.AvcodecFindDecoderByName(o.CodecName); cdc == nil {
+			err = fmt.Errorf("astilibav: no decoder with name %s", o.CodecName)
+			return
+		}
+	} else if cdc = avcodec.AvcodecFindDecoder(o.CodecParams.CodecId()); cdc == nil {
 		err = fmt.Errorf("astilibav: no decoder found for codec id %+v", o.CodecParams.CodecId())
 		return
 	}
@@ -66,14 +91,64 @@ func NewDecoder(o DecoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 		return
 	}
 
+	// Attribute libav logs to the decoder
+	registerLogOwner(unsafe.Pointer(d.ctxCodec), d)
+	c.Add(func() error {
+		unregisterLogOwner(unsafe.Pointer(d.ctxCodec))
+		return nil
+	})
+
 	// Copy codec parameters
 	if ret := avcodec.AvcodecParametersToContext(d.ctxCodec, o.CodecParams); ret < 0 {
 		err = fmt.Errorf("astilibav: avcodec.AvcodecParametersToContext failed: %w", NewAvError(ret))
 		return
 	}
 
+	// Set threading
+	if o.ThreadCount != nil {
+		d.ctxCodec.SetThreadCount(*o.ThreadCount)
+	}
+	if err = setCodecThreading(d.ctxCodec, cdc, Context{ThreadCount: o.ThreadCount, ThreadType: o.ThreadType}); err != nil {
+		err = fmt.Errorf("astilibav: setting codec threading failed: %w", err)
+		return
+	}
+
+	// Decode to hardware frames
+	if o.OutputCtx.HardwareFramesCtx != nil {
+		if err = setDecoderHardwareFrames(d.ctxCodec, o.OutputCtx.HardwareFramesCtx); err != nil {
+			err = fmt.Errorf("astilibav: setting decoder hardware frames failed: %w", err)
+			return
+		}
+	} else if o.HardwareDevice != nil {
+		if err = setDecoderHardwareDevice(d.ctxCodec, o.HardwareDevice); err != nil {
+			err = fmt.Errorf("astilibav: setting decoder hardware device failed: %w", err)
+			return
+		}
+	}
+
+	// Dict
+	var dict *avutil.Dictionary
+	if o.Dict != nil {
+		// Parse dict
+		if err = o.Dict.Parse(&dict); err != nil {
+			err = fmt.Errorf("astilibav: parsing dict failed: %w", err)
+			return
+		}
+
+		// Make sure the dict is freed
+		defer avutil.AvDictFree(&dict)
+	}
+
+	// Pin codec threads
+	var unlock func()
+	if unlock, err = lockOSThreadToNodeCPUs(o.Node); err != nil {
+		err = fmt.Errorf("astilibav: locking os thread to node cpus failed: %w", err)
+		return
+	}
+	defer unlock()
+
 	// Open codec
-	if ret := d.ctxCodec.AvcodecOpen2(cdc, nil); ret < 0 {
+	if ret := d.ctxCodec.AvcodecOpen2(cdc, &dict); ret < 0 {
 		err = fmt.Errorf("astilibav: d.ctxCodec.AvcodecOpen2 failed: %w", NewAvError(ret))
 		return
 	}
@@ -139,6 +214,9 @@ func (d *Decoder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
 		defer d.d.wait()
 
+		// Make sure to end pending spans
+		defer d.spans.close()
+
 		// Make sure to stop the chan properly
 		defer d.c.Stop()
 
@@ -149,7 +227,18 @@ func (d *Decoder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 
 // HandlePkt implements the PktHandler interface
 func (d *Decoder) HandlePkt(p *PktHandlerPayload) {
-	d.c.Add(func() {
+	// Trace
+	if p.traceCtx != nil {
+		d.spans.start(p.traceCtx, d, SpanNameDecode, p.Pkt.Pts())
+	}
+
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be decoded
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, d); p == nil {
+		return
+	}
+	d.c.AddWithRelease(func() {
 		// Handle pause
 		defer d.HandlePause()
 
@@ -172,7 +261,7 @@ func (d *Decoder) HandlePkt(p *PktHandlerPayload) {
 				return
 			}
 		}
-	})
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
 }
 
 func (d *Decoder) receiveFrame(descriptor Descriptor) (stop bool) {
@@ -192,7 +281,29 @@ func (d *Decoder) receiveFrame(descriptor Descriptor) (stop bool) {
 	}
 	d.statWorkRatio.End()
 
+	// Download hardware frame
+	if d.download {
+		// Get frame
+		sf := d.d.p.get()
+		defer d.d.p.put(sf)
+
+		// Download
+		d.statWorkRatio.Begin()
+		downloaded, err := downloadHardwareFrame(sf, f)
+		d.statWorkRatio.End()
+		if err != nil {
+			d.eh.Emit(astiencoder.EventError(d, fmt.Errorf("astilibav: downloading hardware frame failed: %w", err)))
+			return
+		} else if downloaded {
+			f = sf
+		}
+	}
+
+	// Get trace
+	traceCtx, endSpan := d.spans.take(f.Pts())
+	defer endSpan()
+
 	// Dispatch frame
-	d.d.dispatch(f, descriptor)
+	d.d.dispatchWithTrace(traceCtx, f, descriptor)
 	return
 }
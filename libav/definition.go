@@ -0,0 +1,676 @@
+package astilibav
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avformat"
+	"github.com/asticode/goav/avutil"
+)
+
+// Node type names
+const (
+	NodeTypeDecoder        = "decoder"
+	NodeTypeDemuxer        = "demuxer"
+	NodeTypeEncoder        = "encoder"
+	NodeTypeFilterer       = "filterer"
+	NodeTypeMuxer          = "muxer"
+	NodeTypeRemoteReceiver = "remote_receiver"
+	NodeTypeRemoteSender   = "remote_sender"
+)
+
+// DemuxerDefinitionOptions represents the options of the demuxer node type
+type DemuxerDefinitionOptions struct {
+	Dict        string `json:"dict,omitempty"`
+	EmulateRate bool   `json:"emulate_rate,omitempty"`
+	// Exact input format, e.g. "mpegts"
+	FormatName string `json:"format_name,omitempty"`
+	Loop       bool   `json:"loop,omitempty"`
+	LoopCount  int    `json:"loop_count,omitempty"`
+	URL        string `json:"url" definition:"required"`
+}
+
+// DecoderDefinitionOptions represents the options of the decoder node type
+// Its parent must be a demuxer connected with the stream option
+type DecoderDefinitionOptions struct {
+	CodecName   string `json:"codec_name,omitempty"`
+	Dict        string `json:"dict,omitempty"`
+	ThreadCount *int   `json:"thread_count,omitempty"`
+}
+
+// EncoderDefinitionOptions represents the options of the encoder node type
+// Its parent must provide an output context, e.g. a decoder or a filterer. The parent's context is used as a base on
+// top of which the named preset, then the other settings, are applied
+type EncoderDefinitionOptions struct {
+	Preset
+	GlobalHeader bool `json:"global_header,omitempty"`
+	Lazy         bool `json:"lazy,omitempty"`
+	// Name of a preset registered in DefaultPresets, e.g. "h264-1080p30-5M"
+	PresetName string `json:"preset,omitempty"`
+}
+
+// FiltererDefinitionOptions represents the options of the filterer node type
+// Its parents must provide an output context. With 1 parent, its input is named "in", otherwise inputs are named after
+// the parents. The output context is the first parent's, on top of which the output settings are applied
+type FiltererDefinitionOptions struct {
+	// Filters as you would use in ffmpeg, e.g. "scale=1280:720"
+	Content string `json:"content" definition:"required"`
+	// Settings of the output context the filters change, e.g. width and height when scaling
+	Output Preset `json:"output,omitempty"`
+}
+
+// MuxerDefinitionOptions represents the options of the muxer node type
+type MuxerDefinitionOptions struct {
+	Dict       string `json:"dict,omitempty"`
+	FormatName string `json:"format_name,omitempty"`
+	Lazy       bool   `json:"lazy,omitempty"`
+	URL        string `json:"url" definition:"required"`
+}
+
+// RemoteReceiverDefinitionOptions represents the options of the remote receiver node type
+// Its output settings describe the frames it receives, senders whose frames don't match them are disconnected
+type RemoteReceiverDefinitionOptions struct {
+	// Address to listen on, e.g. ":4001"
+	Addr string `json:"addr" definition:"required"`
+	// "audio" or "video"
+	MediaType string `json:"media_type" definition:"required"`
+	Output    Preset `json:"output,omitempty"`
+	// Time base of the frames' timestamps, e.g. "1/25". It must be the sender's
+	TimeBase string `json:"time_base" definition:"required"`
+	// If set, senders must connect using TLS
+	TLS *astiencoder.TLSServerOptions `json:"tls,omitempty"`
+}
+
+// RemoteSenderDefinitionOptions represents the options of the remote sender node type
+// Its parent must be a demuxer connected with the stream option or provide an output context
+type RemoteSenderDefinitionOptions struct {
+	// Address of the remote receiver, e.g. "{{.receiver_addr}}" when provided by the cluster coordinator
+	Addr string `json:"addr" definition:"required"`
+	// If set, the connection to the remote receiver uses TLS
+	TLS *astiencoder.TLSClientOptions `json:"tls,omitempty"`
+}
+
+// connectionDefinitionOptions represents the options of a connection whose parent is a libav node
+type connectionDefinitionOptions struct {
+	// Index of the demuxer stream to connect. Required when the parent is a demuxer
+	Stream *int `json:"stream,omitempty" definition:"required"`
+}
+
+// RegisterNodeTypes registers the libav node types so that workflows using them can be built from definitions
+// Encoders use the presets registered in DefaultPresets
+// Connections whose parent is a demuxer need the "stream" option, connecting a demuxer to a muxer copies the stream
+// and connecting an encoder to a muxer adds a stream to the muxer. Streams added to muxers are kept when their
+// connection is removed since they can't be removed once the header has been written
+func RegisterNodeTypes(ts *astiencoder.NodeTypes) {
+	ts.Register(NodeTypeDecoder, astiencoder.NodeType{
+		Connect:    ConnectDefinitionNodes,
+		Disconnect: DisconnectDefinitionNodes,
+		New:        newDecoderFromDefinition,
+	})
+	ts.Register(NodeTypeDemuxer, astiencoder.NodeType{
+		Connect:    ConnectDefinitionNodes,
+		Disconnect: DisconnectDefinitionNodes,
+		New:        newDemuxerFromDefinition,
+	})
+	ts.Register(NodeTypeEncoder, astiencoder.NodeType{
+		Connect:    ConnectDefinitionNodes,
+		Disconnect: DisconnectDefinitionNodes,
+		New:        newEncoderFromDefinition,
+	})
+	ts.Register(NodeTypeFilterer, astiencoder.NodeType{
+		Connect:    ConnectDefinitionNodes,
+		Disconnect: DisconnectDefinitionNodes,
+		New:        newFiltererFromDefinition,
+	})
+	ts.Register(NodeTypeMuxer, astiencoder.NodeType{
+		New: newMuxerFromDefinition,
+	})
+	ts.Register(NodeTypeRemoteReceiver, astiencoder.NodeType{
+		Connect:    ConnectDefinitionNodes,
+		Disconnect: DisconnectDefinitionNodes,
+		New:        newRemoteReceiverFromDefinition,
+	})
+	ts.Register(NodeTypeRemoteSender, astiencoder.NodeType{
+		New: newRemoteSenderFromDefinition,
+	})
+}
+
+func newDemuxerFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o DemuxerDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get format
+	var f *avformat.InputFormat
+	if o.FormatName != "" {
+		if f = avformat.AvFindInputFormat(o.FormatName); f == nil {
+			err = fmt.Errorf("astilibav: no input format named %s", o.FormatName)
+			return
+		}
+	}
+
+	// Create demuxer
+	var d *Demuxer
+	if d, err = NewDemuxer(DemuxerOptions{
+		Dict:        NewDefaultDict(o.Dict),
+		EmulateRate: o.EmulateRate,
+		Format:      f,
+		Loop:        o.Loop,
+		LoopCount:   o.LoopCount,
+		Node:        b.Node,
+		URL:         o.URL,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+
+	// Input formats can't be exported
+	d.definition.FormatName = o.FormatName
+	n = d
+	return
+}
+
+func newDecoderFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o DecoderDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get input stream
+	if len(b.Parents) != 1 {
+		err = fmt.Errorf("astilibav: decoder needs 1 parent, got %d", len(b.Parents))
+		return
+	}
+	var s *avformat.Stream
+	if s, err = definitionStream(b.Parents[0].Node, b.Parents[0].Options); err != nil {
+		err = fmt.Errorf("astilibav: getting input stream failed: %w", err)
+		return
+	}
+
+	// Create decoder
+	if n, err = NewDecoder(DecoderOptions{
+		CodecName:   o.CodecName,
+		CodecParams: s.CodecParameters(),
+		Dict:        NewDefaultDict(o.Dict),
+		Node:        b.Node,
+		OutputCtx:   NewContextFromStream(s),
+		ThreadCount: o.ThreadCount,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating decoder failed: %w", err)
+		return
+	}
+	return
+}
+
+func newEncoderFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o EncoderDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get input context
+	if len(b.Parents) != 1 {
+		err = fmt.Errorf("astilibav: encoder needs 1 parent, got %d", len(b.Parents))
+		return
+	}
+	p, ok := b.Parents[0].Node.(OutputContexter)
+	if !ok {
+		err = fmt.Errorf("astilibav: parent %s doesn't provide an output context", b.Parents[0].Node.Metadata().Name)
+		return
+	}
+	ctx := p.OutputCtx()
+	ctx.Dict = nil
+	ctx.GlobalHeader = o.GlobalHeader
+	ctx.ThreadCount = nil
+
+	// Apply presets
+	if o.PresetName != "" {
+		if ctx, err = DefaultPresets.Apply(o.PresetName, ctx); err != nil {
+			err = fmt.Errorf("astilibav: applying preset %s failed: %w", o.PresetName, err)
+			return
+		}
+	}
+	if ctx, err = o.Preset.Apply(ctx); err != nil {
+		err = fmt.Errorf("astilibav: applying options failed: %w", err)
+		return
+	}
+
+	// Create encoder
+	var e *Encoder
+	if e, err = NewEncoder(EncoderOptions{
+		Ctx:  ctx,
+		Lazy: o.Lazy,
+		Node: b.Node,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating encoder failed: %w", err)
+		return
+	}
+
+	// Export the options as they've been defined
+	e.definition = o
+	n = e
+	return
+}
+
+func newFiltererFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o FiltererDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get inputs
+	if len(b.Parents) == 0 {
+		err = errors.New("astilibav: filterer needs at least 1 parent")
+		return
+	}
+	ins := make(map[string]astiencoder.Node)
+	for _, p := range b.Parents {
+		if _, ok := p.Node.(OutputContexter); !ok {
+			err = fmt.Errorf("astilibav: parent %s doesn't provide an output context", p.Node.Metadata().Name)
+			return
+		}
+		ins[p.Node.Metadata().Name] = p.Node
+	}
+	if len(b.Parents) == 1 {
+		ins = map[string]astiencoder.Node{"in": b.Parents[0].Node}
+	}
+
+	// Get output context
+	var ctx Context
+	if ctx, err = o.Output.Apply(b.Parents[0].Node.(OutputContexter).OutputCtx()); err != nil {
+		err = fmt.Errorf("astilibav: applying output settings failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	var f *Filterer
+	if f, err = NewFilterer(FiltererOptions{
+		Content:   o.Content,
+		Inputs:    ins,
+		Node:      b.Node,
+		OutputCtx: ctx,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	f.definition = &o
+	n = f
+	return
+}
+
+func newMuxerFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o MuxerDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Create muxer
+	if n, err = NewMuxer(MuxerOptions{
+		Dict:           NewDefaultDict(o.Dict),
+		FormatName:     o.FormatName,
+		Lazy:           o.Lazy,
+		Node:           b.Node,
+		SecretProvider: b.SecretProvider,
+		URL:            o.URL,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating muxer failed: %w", err)
+		return
+	}
+	return
+}
+
+func newRemoteReceiverFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o RemoteReceiverDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get output context
+	var ctx Context
+	switch o.MediaType {
+	case "audio":
+		ctx.CodecType = avutil.AVMEDIA_TYPE_AUDIO
+	case "video":
+		ctx.CodecType = avutil.AVMEDIA_TYPE_VIDEO
+	default:
+		err = fmt.Errorf("astilibav: invalid media type %s", o.MediaType)
+		return
+	}
+	if ctx.TimeBase, err = parsePresetFrameRate(o.TimeBase); err != nil {
+		err = fmt.Errorf("astilibav: parsing time base failed: %w", err)
+		return
+	}
+	if ctx, err = o.Output.Apply(ctx); err != nil {
+		err = fmt.Errorf("astilibav: applying output settings failed: %w", err)
+		return
+	}
+
+	// Get TLS config
+	var t *tls.Config
+	if o.TLS != nil {
+		if t, err = o.TLS.Config(); err != nil {
+			err = fmt.Errorf("astilibav: getting tls config failed: %w", err)
+			return
+		}
+	}
+
+	// Create receiver
+	if n, err = NewRemoteFrameReceiver(RemoteReceiverOptions{
+		Addr:      o.Addr,
+		Node:      b.Node,
+		OutputCtx: ctx,
+		TLS:       t,
+	}, b.EventHandler, b.Closer); err != nil {
+		err = fmt.Errorf("astilibav: creating remote frame receiver failed: %w", err)
+		return
+	}
+	return
+}
+
+func newRemoteSenderFromDefinition(b astiencoder.NodeBuild) (n astiencoder.Node, err error) {
+	// Decode options
+	var o RemoteSenderDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(b.Definition.Options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get input context
+	if len(b.Parents) != 1 {
+		err = fmt.Errorf("astilibav: remote sender needs 1 parent, got %d", len(b.Parents))
+		return
+	}
+	var ctx Context
+	if _, ok := b.Parents[0].Node.(*Demuxer); ok {
+		var s *avformat.Stream
+		if s, err = definitionStream(b.Parents[0].Node, b.Parents[0].Options); err != nil {
+			err = fmt.Errorf("astilibav: getting input stream failed: %w", err)
+			return
+		}
+		ctx = NewContextFromStream(s)
+	} else if p, ok := b.Parents[0].Node.(OutputContexter); ok {
+		ctx = p.OutputCtx()
+	} else {
+		err = fmt.Errorf("astilibav: parent %s is not a demuxer and doesn't provide an output context", b.Parents[0].Node.Metadata().Name)
+		return
+	}
+
+	// Get TLS config
+	var t *tls.Config
+	if o.TLS != nil {
+		if t, err = o.TLS.Config(); err != nil {
+			err = fmt.Errorf("astilibav: getting tls config failed: %w", err)
+			return
+		}
+	}
+
+	// Create sender
+	n = NewRemoteSender(RemoteSenderOptions{
+		Addr: o.Addr,
+		Ctx:  ctx,
+		Node: b.Node,
+		TLS:  t,
+	}, b.EventHandler)
+	return
+}
+
+// definitionStream returns the demuxer stream designated by the connection options
+func definitionStream(parent astiencoder.Node, options map[string]interface{}) (s *avformat.Stream, err error) {
+	// Parent is not a demuxer
+	d, ok := parent.(*Demuxer)
+	if !ok {
+		err = fmt.Errorf("astilibav: parent %s is not a demuxer", parent.Metadata().Name)
+		return
+	}
+
+	// Decode options
+	var o connectionDefinitionOptions
+	if err = astiencoder.DecodeDefinitionOptions(options, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Get stream
+	ss := d.CtxFormat().Streams()
+	if *o.Stream < 0 || *o.Stream >= len(ss) {
+		err = fmt.Errorf("astilibav: stream %d doesn't exist in %s which has %d streams", *o.Stream, d.CtxFormat().Filename(), len(ss))
+		return
+	}
+	s = ss[*o.Stream]
+	return
+}
+
+// ConnectDefinitionNodes connects libav nodes the way the libav node types do, so that it can be used as the Connect
+// function of custom node types
+func ConnectDefinitionNodes(parent, child astiencoder.Node, options map[string]interface{}) (err error) {
+	// Demuxer
+	if d, ok := parent.(*Demuxer); ok {
+		// Get stream
+		var s *avformat.Stream
+		if s, err = definitionStream(d, options); err != nil {
+			err = fmt.Errorf("astilibav: getting input stream failed: %w", err)
+			return
+		}
+
+		// Switch on child
+		switch c := child.(type) {
+		case *Muxer:
+			// Clone stream
+			var o *avformat.Stream
+			if o, err = CloneStream(s, c.CtxFormat()); err != nil {
+				err = fmt.Errorf("astilibav: cloning stream failed: %w", err)
+				return
+			}
+			d.ConnectForStream(c.NewPktHandler(o), s)
+		case PktHandler:
+			d.ConnectForStream(c, s)
+		default:
+			err = fmt.Errorf("astilibav: %s doesn't handle packets", child.Metadata().Name)
+		}
+		return
+	}
+
+	// Encoder to muxer
+	if e, ok := parent.(*Encoder); ok {
+		if m, ok := child.(*Muxer); ok {
+			var o *avformat.Stream
+			if o, err = e.AddStream(m.CtxFormat()); err != nil {
+				err = fmt.Errorf("astilibav: adding stream failed: %w", err)
+				return
+			}
+			e.Connect(m.NewPktHandler(o))
+			return
+		}
+	}
+
+	// Frames
+	if p, ok := parent.(FrameHandlerConnector); ok {
+		c, ok := child.(FrameHandler)
+		if !ok {
+			err = fmt.Errorf("astilibav: %s doesn't handle frames", child.Metadata().Name)
+			return
+		}
+		p.Connect(c)
+		return
+	}
+
+	// Packets
+	if p, ok := parent.(PktHandlerConnector); ok {
+		c, ok := child.(PktHandler)
+		if !ok {
+			err = fmt.Errorf("astilibav: %s doesn't handle packets", child.Metadata().Name)
+			return
+		}
+		p.Connect(c)
+		return
+	}
+	return fmt.Errorf("astilibav: %s can't have children", parent.Metadata().Name)
+}
+
+// DisconnectDefinitionNodes disconnects libav nodes connected with ConnectDefinitionNodes, so that it can be used as
+// the Disconnect function of custom node types
+func DisconnectDefinitionNodes(parent, child astiencoder.Node, options map[string]interface{}) (err error) {
+	// Get packet handler
+	var ph PktHandler
+	switch c := child.(type) {
+	case *Muxer:
+		// Handlers are indexed by name
+		ph = &MuxerPktHandler{Muxer: c}
+	case PktHandler:
+		ph = c
+	}
+
+	// Demuxer
+	if d, ok := parent.(*Demuxer); ok {
+		// Get stream
+		var s *avformat.Stream
+		if s, err = definitionStream(d, options); err != nil {
+			err = fmt.Errorf("astilibav: getting input stream failed: %w", err)
+			return
+		}
+
+		// Child doesn't handle packets
+		if ph == nil {
+			err = fmt.Errorf("astilibav: %s doesn't handle packets", child.Metadata().Name)
+			return
+		}
+
+		// Delete handler
+		d.d.delHandler(newPktCond(s, ph))
+
+		// Disconnect nodes once no stream is connected anymore
+		var os []map[string]interface{}
+		if os, err = d.ExportConnectionDefinitions(child); err != nil {
+			err = fmt.Errorf("astilibav: exporting connection definitions failed: %w", err)
+			return
+		}
+		if len(os) == 0 {
+			astiencoder.DisconnectNodes(d, child)
+		}
+		return
+	}
+
+	// Frames
+	if p, ok := parent.(FrameHandlerConnector); ok {
+		c, ok := child.(FrameHandler)
+		if !ok {
+			err = fmt.Errorf("astilibav: %s doesn't handle frames", child.Metadata().Name)
+			return
+		}
+		p.Disconnect(c)
+		return
+	}
+
+	// Packets
+	if p, ok := parent.(PktHandlerConnector); ok {
+		if ph == nil {
+			err = fmt.Errorf("astilibav: %s doesn't handle packets", child.Metadata().Name)
+			return
+		}
+		p.Disconnect(ph)
+		return
+	}
+	return fmt.Errorf("astilibav: %s can't have children", parent.Metadata().Name)
+}
+
+// ExportDefinition implements the astiencoder.DefinitionExporter interface
+func (d *Demuxer) ExportDefinition() (nd astiencoder.NodeDefinition, err error) {
+	nd.Type = NodeTypeDemuxer
+	nd.Options, err = astiencoder.EncodeDefinitionOptions(d.definition)
+	return
+}
+
+// ExportConnectionDefinitions implements the astiencoder.ConnectionDefinitionExporter interface
+func (d *Demuxer) ExportConnectionDefinitions(child astiencoder.Node) (os []map[string]interface{}, err error) {
+	// Get streams
+	d.d.m.Lock()
+	var idxs []int
+	for _, h := range d.d.hs {
+		if c, ok := h.(*pktCond); ok && c.PktHandler.Metadata().Name == child.Metadata().Name {
+			idxs = append(idxs, c.i.Index())
+		}
+	}
+	d.d.m.Unlock()
+	sort.Ints(idxs)
+
+	// Loop through streams
+	for _, idx := range idxs {
+		os = append(os, map[string]interface{}{"stream": float64(idx)})
+	}
+	return
+}
+
+// ExportDefinition implements the astiencoder.DefinitionExporter interface
+func (d *Decoder) ExportDefinition() (nd astiencoder.NodeDefinition, err error) {
+	nd.Type = NodeTypeDecoder
+	nd.Options, err = astiencoder.EncodeDefinitionOptions(d.definition)
+	return
+}
+
+func newEncoderDefinitionOptions(o EncoderOptions) EncoderDefinitionOptions {
+	var frameRate string
+	if o.Ctx.FrameRate.Num() > 0 {
+		frameRate = fmt.Sprintf("%d/%d", o.Ctx.FrameRate.Num(), o.Ctx.FrameRate.Den())
+	}
+	return EncoderDefinitionOptions{
+		GlobalHeader: o.Ctx.GlobalHeader,
+		Lazy:         o.Lazy,
+		Preset: Preset{
+			BitRate:    o.Ctx.BitRate,
+			CodecName:  o.Ctx.CodecName,
+			Dict:       o.Ctx.Dict.String(),
+			FrameRate:  frameRate,
+			GopSize:    o.Ctx.GopSize,
+			Height:     o.Ctx.Height,
+			SampleRate: o.Ctx.SampleRate,
+			Width:      o.Ctx.Width,
+		},
+	}
+}
+
+// ExportDefinition implements the astiencoder.DefinitionExporter interface
+func (e *Encoder) ExportDefinition() (nd astiencoder.NodeDefinition, err error) {
+	nd.Type = NodeTypeEncoder
+	nd.Options, err = astiencoder.EncodeDefinitionOptions(e.definition)
+	return
+}
+
+// ExportDefinition implements the astiencoder.DefinitionExporter interface
+// Only filterers created from a definition can export it since their output settings are unknown otherwise
+func (f *Filterer) ExportDefinition() (nd astiencoder.NodeDefinition, err error) {
+	if f.definition == nil {
+		err = errors.New("astilibav: filterer has not been created from a definition")
+		return
+	}
+	nd.Type = NodeTypeFilterer
+	nd.Options, err = astiencoder.EncodeDefinitionOptions(f.definition)
+	return
+}
+
+// ExportDefinition implements the astiencoder.DefinitionExporter interface
+func (m *Muxer) ExportDefinition() (nd astiencoder.NodeDefinition, err error) {
+	nd.Type = NodeTypeMuxer
+	nd.Options, err = astiencoder.EncodeDefinitionOptions(MuxerDefinitionOptions{
+		Dict:       m.options.Dict.String(),
+		FormatName: m.options.FormatName,
+		Lazy:       m.options.Lazy,
+		URL:        m.url(),
+	})
+	return
+}
@@ -2,9 +2,13 @@ package astilibav
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -23,19 +27,28 @@ type Demuxer struct {
 	*astiencoder.BaseNode
 	ctxFormat     *avformat.Context
 	d             *pktDispatcher
+	definition    DemuxerDefinitionOptions
+	duration      time.Duration
 	eh            *astiencoder.EventHandler
 	emulateRate   bool
 	interruptRet  *int
-	loop          bool
-	restamper     PktRestamper
+	latencyPeriod time.Duration
+	loop          *demuxerLoop
+	progress      *demuxerProgress
+	seekM         *sync.Mutex // Locks seekTo
+	seekTo        *time.Duration
 	seekToLive    bool
 	ss            map[int]*demuxerStream
+	statCPU       *astiencoder.CPUUsageStat
+	statSpeed     *demuxerSpeedStat
 	statWorkRatio *astikit.DurationPercentageStat
 }
 
 type demuxerStream struct {
 	ctx               Context
 	emulateRateNextAt time.Time
+	gopCount          uint64
+	latencyProbeAt    time.Time
 	s                 *avformat.Stream
 	seekToLiveLastPkt *demuxerPkt
 }
@@ -54,19 +67,29 @@ func newDemuxerPkt(pkt *avcodec.Packet) *demuxerPkt {
 
 // DemuxerOptions represents demuxer options
 type DemuxerOptions struct {
+	// If set, packets are sent to handlers in batches, which is useful for high bitrate passthrough workloads
+	Batch PktBatchOptions
 	// String content of the demuxer as you would use in ffmpeg
 	Dict *Dict
 	// If true, the demuxer will sleep between packets for the exact duration of the packet
 	EmulateRate bool
 	// Exact input format
 	Format *avformat.InputFormat
+	// Period at which 1 pkt per stream is stamped to measure the latency of the media path. Default is 1s. A negative
+	// value disables latency measurement
+	LatencyProbePeriod time.Duration
 	// If true, at the end of the input the demuxer will seek to its beginning and start over
-	// In this case the packets are restamped
+	// In this case the packets of all streams are shifted by the same offset at each loop so that timestamps keep on
+	// increasing and streams stay in sync
 	Loop bool
+	// Number of times the input is played when looping. 0 means forever
+	LoopCount int
 	// Basic node options
 	Node astiencoder.NodeOptions
 	// Context used to cancel probing
 	ProbeCtx context.Context
+	// If set, data is read from it instead of the URL which is then only used for display purposes
+	Reader io.Reader
 	// If true, the demuxer will not dispatch packets until, for at least one stream, 2 consecutive packets are received
 	// at an interval >= to the first packet's duration
 	SeekToLive bool
@@ -80,22 +103,39 @@ func NewDemuxer(o DemuxerOptions, eh *astiencoder.EventHandler, c *astikit.Close
 	count := atomic.AddUint64(&countDemuxer, uint64(1))
 	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("demuxer_%d", count), fmt.Sprintf("Demuxer #%d", count), fmt.Sprintf("Demuxes %s", o.URL), "demuxer")
 
+	// Default options
+	if o.LatencyProbePeriod == 0 {
+		o.LatencyProbePeriod = time.Second
+	}
+
 	// Create demuxer
 	d = &Demuxer{
-		d:             newPktDispatcher(c),
+		d: newPktDispatcher(),
+		definition: DemuxerDefinitionOptions{
+			Dict:        o.Dict.String(),
+			EmulateRate: o.EmulateRate,
+			Loop:        o.Loop,
+			LoopCount:   o.LoopCount,
+			URL:         o.URL,
+		},
 		eh:            eh,
 		emulateRate:   o.EmulateRate,
-		loop:          o.Loop,
+		latencyPeriod: o.LatencyProbePeriod,
+		progress:      newDemuxerProgress(),
+		seekM:         &sync.Mutex{},
 		seekToLive:    o.SeekToLive,
 		ss:            make(map[int]*demuxerStream),
+		statCPU:       astiencoder.NewCPUUsageStat(),
+		statSpeed:     newDemuxerSpeedStat(),
 		statWorkRatio: astikit.NewDurationPercentageStat(),
 	}
 	d.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(d), eh)
+	d.d.setBatch(o.Batch)
 	d.addStats()
 
-	// If loop is enabled, we need to add a restamper
-	if d.loop {
-		d.restamper = NewPktRestamperWithPktDuration()
+	// If loop is enabled, we need to restamp packets
+	if o.Loop {
+		d.loop = newDemuxerLoop(o.LoopCount)
 	}
 
 	// Dict
@@ -117,6 +157,26 @@ func NewDemuxer(o DemuxerOptions, eh *astiencoder.EventHandler, c *astikit.Close
 	// Set interrupt callback
 	d.interruptRet = ctxFormat.SetInterruptCallback()
 
+	// Custom io
+	if o.Reader != nil {
+		// Create io context
+		var i *ioContext
+		if i, err = newIOContext(o.Reader, nil); err != nil {
+			ctxFormat.AvformatFreeContext()
+			err = fmt.Errorf("astilibav: creating io context failed: %w", err)
+			return
+		}
+
+		// Make sure the io context is freed once the input is closed
+		c.Add(func() error {
+			i.close()
+			return nil
+		})
+
+		// Set pb
+		ctxFormat.SetPb(i.avIOContext())
+	}
+
 	// Handle probe cancellation
 	if o.ProbeCtx != nil {
 		// Create context
@@ -145,8 +205,12 @@ func NewDemuxer(o DemuxerOptions, eh *astiencoder.EventHandler, c *astikit.Close
 	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
 	d.ctxFormat = ctxFormat
 
+	// Attribute libav logs to the demuxer
+	registerLogOwner(unsafe.Pointer(d.ctxFormat), d)
+
 	// Make sure the input is properly closed
 	c.Add(func() error {
+		unregisterLogOwner(unsafe.Pointer(d.ctxFormat))
 		avformat.AvformatCloseInput(d.ctxFormat)
 		return nil
 	})
@@ -176,6 +240,15 @@ func NewDemuxer(o DemuxerOptions, eh *astiencoder.EventHandler, c *astikit.Close
 			s:   s,
 		}
 	}
+
+	// Get duration, which is in AV_TIME_BASE units
+	// It is unknown for live inputs and when looping forever
+	if v := d.ctxFormat.Duration(); v != avutil.AV_NOPTS_VALUE && v > 0 && (!o.Loop || o.LoopCount > 0) {
+		d.duration = time.Duration(v) * time.Microsecond
+		if o.Loop {
+			d.duration *= time.Duration(o.LoopCount)
+		}
+	}
 	return
 }
 
@@ -187,6 +260,22 @@ func (d *Demuxer) addStats() {
 		Unit:        "%",
 	}, d.statWorkRatio)
 
+	// Add speed
+	d.Stater().AddStat(astikit.StatMetadata{
+		Description: "Media duration demuxed per second, 1 being realtime",
+		Label:       "Speed",
+		Unit:        "x",
+	}, d.statSpeed)
+
+	// Add CPU usage
+	if astiencoder.CPUUsageSupported() {
+		d.Stater().AddStat(astikit.StatMetadata{
+			Description: "Percentage of one core used to demux and dispatch packets. Threads created by libav are not taken into account",
+			Label:       "CPU usage",
+			Unit:        "%",
+		}, d.statCPU)
+	}
+
 	// Add dispatcher stats
 	d.d.addStats(d.Stater())
 }
@@ -248,7 +337,10 @@ func (d *Demuxer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 		// Loop
 		for {
 			// Read frame
-			if stop := d.readFrame(ctx); stop {
+			d.statCPU.Begin()
+			stop := d.readFrame(ctx)
+			d.statCPU.End()
+			if stop {
 				return
 			}
 
@@ -263,7 +355,68 @@ func (d *Demuxer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 	})
 }
 
+// Progress implements the astiencoder.Progresser interface
+func (d *Demuxer) Progress() (position, duration time.Duration) {
+	return d.progress.position(), d.duration
+}
+
+// Seek implements the astiencoder.Seeker interface
+// The demuxer seeks before reading its next packet. Looping demuxers and inputs whose duration is unknown, e.g. live
+// inputs, can't seek
+func (d *Demuxer) Seek(position time.Duration) error {
+	// Demuxer can't seek
+	if d.loop != nil {
+		return errors.New("astilibav: looping demuxers can't seek")
+	} else if d.duration <= 0 {
+		return errors.New("astilibav: input duration is unknown")
+	}
+
+	// Invalid position
+	if position < 0 || position > d.duration {
+		return fmt.Errorf("astilibav: position %s is not between 0 and %s", position, d.duration)
+	}
+
+	// Store position
+	d.seekM.Lock()
+	d.seekTo = &position
+	d.seekM.Unlock()
+	return nil
+}
+
+func (d *Demuxer) handleSeek() {
+	// Get position
+	d.seekM.Lock()
+	p := d.seekTo
+	d.seekTo = nil
+	d.seekM.Unlock()
+
+	// Nothing to do
+	if p == nil {
+		return
+	}
+
+	// Get timestamp, which is in AV_TIME_BASE units
+	ts := int64(*p / time.Microsecond)
+	if v := d.ctxFormat.StartTime(); v != avutil.AV_NOPTS_VALUE {
+		ts += v
+	}
+
+	// Seek
+	if ret := d.ctxFormat.AvSeekFrame(-1, ts, avformat.AVSEEK_FLAG_BACKWARD); ret < 0 {
+		emitAvError(d, d.eh, ret, "ctxFormat.AvSeekFrame on %s failed", d.ctxFormat.Filename())
+		return
+	}
+
+	// Reset rate emulation
+	for _, s := range d.ss {
+		s.emulateRateNextAt = time.Time{}
+	}
+}
+
 func (d *Demuxer) readFrame(ctx context.Context) (stop bool) {
+	// Seek
+	d.handleSeek()
+
 	// Get pkt from pool
 	pkt := d.d.p.get()
 	defer d.d.p.put(pkt)
@@ -272,7 +425,7 @@ func (d *Demuxer) readFrame(ctx context.Context) (stop bool) {
 	d.statWorkRatio.Begin()
 	if ret := d.ctxFormat.AvReadFrame(pkt); ret < 0 {
 		d.statWorkRatio.End()
-		if ret != avutil.AVERROR_EOF || !d.loop {
+		if ret != avutil.AVERROR_EOF || d.loop == nil || !d.loop.next() {
 			if ret != avutil.AVERROR_EOF {
 				emitAvError(d, d.eh, ret, "ctxFormat.AvReadFrame on %s failed", d.ctxFormat.Filename())
 			}
@@ -282,7 +435,15 @@ func (d *Demuxer) readFrame(ctx context.Context) (stop bool) {
 			if ret = d.ctxFormat.AvSeekFrame(-1, d.ctxFormat.StartTime(), avformat.AVSEEK_FLAG_BACKWARD); ret < 0 {
 				emitAvError(d, d.eh, ret, "ctxFormat.AvSeekFrame on %s failed", d.ctxFormat.Filename())
 				stop = true
+				return
 			}
+
+			// Emit event
+			d.eh.Emit(astiencoder.Event{
+				Name:    DemuxerLooped,
+				Payload: d.loop.count,
+				Target:  d,
+			})
 		}
 		return
 	}
@@ -305,10 +466,13 @@ func (d *Demuxer) readFrame(ctx context.Context) (stop bool) {
 	}
 
 	// Restamp
-	if d.restamper != nil {
-		d.restamper.Restamp(pkt)
+	if d.loop != nil {
+		d.loop.restamp(pkt, s.s.TimeBase())
 	}
 
+	// Get pkt duration
+	pktDuration := time.Duration(avutil.AvRescaleQ(d.emulateRatePktDuration(pkt, s.ctx), s.s.TimeBase(), nanosecondRational))
+
 	// Emulate rate
 	if d.emulateRate {
 		// Sleep until next at
@@ -321,14 +485,79 @@ func (d *Demuxer) readFrame(ctx context.Context) (stop bool) {
 		}
 
 		// Compute next at
-		s.emulateRateNextAt = s.emulateRateNextAt.Add(time.Duration(avutil.AvRescaleQ(d.emulateRatePktDuration(pkt, s.ctx), s.s.TimeBase(), nanosecondRational)))
+		s.emulateRateNextAt = s.emulateRateNextAt.Add(pktDuration)
 	}
 
+	// Update speed and progress
+	d.statSpeed.add(pkt.StreamIndex(), pktDuration)
+	d.progress.update(pkt.StreamIndex(), pkt.Pts(), pkt.Duration(), s.s.TimeBase())
+
+	// Trace GOP
+	traceCtx, endSpan := d.traceGOP(pkt, s)
+	defer endSpan()
+
+	// Probe latency
+	traceCtx = d.probeLatency(traceCtx, s)
+
 	// Dispatch pkt
-	d.d.dispatch(pkt, s.s)
+	d.d.dispatchWithTrace(traceCtx, pkt, s.s)
+	return
+}
+
+func (d *Demuxer) traceGOP(pkt *avcodec.Packet, s *demuxerStream) (traceCtx context.Context, endSpan func()) {
+	// Only video GOPs are traced
+	endSpan = func() {}
+	if s.ctx.CodecType != avutil.AVMEDIA_TYPE_VIDEO || pkt.Flags()&avcodec.AV_PKT_FLAG_KEY == 0 {
+		return
+	}
+
+	// No tracing
+	tr := astiencoder.TracingFromContext(d.Context())
+	if tr == nil {
+		return
+	}
+
+	// GOP is not sampled
+	idx := s.gopCount
+	s.gopCount++
+	if !tr.SampleGOP(idx) {
+		return
+	}
+
+	// Start span
+	var span astiencoder.Span
+	traceCtx, span = tr.Start(d.Context(), SpanNameDemux,
+		astiencoder.SpanAttribute{Key: astiencoder.SpanAttributeNode, Value: d.Metadata().Name},
+		astiencoder.SpanAttribute{Key: SpanAttributePts, Value: pkt.Pts()},
+		astiencoder.SpanAttribute{Key: SpanAttributeStreamIndex, Value: pkt.StreamIndex()},
+	)
+	endSpan = span.End
+
+	// Mark the GOP as traced since the context may also be used to probe latency
+	traceCtx = context.WithValue(traceCtx, tracedContextKey{}, true)
 	return
 }
 
+func (d *Demuxer) probeLatency(traceCtx context.Context, s *demuxerStream) context.Context {
+	// Latency measurement is disabled
+	if d.latencyPeriod < 0 {
+		return traceCtx
+	}
+
+	// Pkt is not a probe
+	now := time.Now()
+	if now.Before(s.latencyProbeAt) {
+		return traceCtx
+	}
+	s.latencyProbeAt = now.Add(d.latencyPeriod)
+
+	// Stamp pkt
+	if traceCtx == nil {
+		traceCtx = d.Context()
+	}
+	return withDemuxedAt(traceCtx, now)
+}
+
 func (d *Demuxer) emulateRatePktDuration(pkt *avcodec.Packet, ctx Context) int64 {
 	switch ctx.CodecType {
 	case avutil.AVMEDIA_TYPE_AUDIO:
@@ -345,3 +574,206 @@ func (d *Demuxer) emulateRatePktDuration(pkt *avcodec.Packet, ctx Context) int64
 		return pkt.Duration()
 	}
 }
+
+// demuxerSpeedStat computes the speed of the demuxer compared to realtime
+// Streams are demuxed in parallel, therefore the speed is based on the stream that progressed the most
+type demuxerSpeedStat struct {
+	m  *sync.Mutex
+	ss map[int]time.Duration
+}
+
+func newDemuxerSpeedStat() *demuxerSpeedStat {
+	return &demuxerSpeedStat{
+		m:  &sync.Mutex{},
+		ss: make(map[int]time.Duration),
+	}
+}
+
+func (s *demuxerSpeedStat) add(idx int, d time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.ss[idx] += d
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *demuxerSpeedStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *demuxerSpeedStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *demuxerSpeedStat) Value(delta time.Duration) interface{} {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Get max duration
+	var max time.Duration
+	for idx, d := range s.ss {
+		if d > max {
+			max = d
+		}
+		delete(s.ss, idx)
+	}
+
+	// Invalid delta
+	if delta <= 0 {
+		return 0.0
+	}
+	return float64(max) / float64(delta)
+}
+
+// demuxerProgress computes the media duration demuxed so far
+// Streams are demuxed in parallel, therefore the position is based on the stream that progressed the most
+type demuxerProgress struct {
+	m  *sync.Mutex
+	ss map[int]*demuxerProgressStream
+}
+
+type demuxerProgressStream struct {
+	end   time.Duration
+	start time.Duration
+}
+
+func newDemuxerProgress() *demuxerProgress {
+	return &demuxerProgress{
+		m:  &sync.Mutex{},
+		ss: make(map[int]*demuxerProgressStream),
+	}
+}
+
+func (p *demuxerProgress) update(idx int, pts, duration int64, timeBase avutil.Rational) {
+	// Invalid pts
+	if pts == avutil.AV_NOPTS_VALUE {
+		return
+	}
+
+	// Lock
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// Get end
+	// Pkt duration is not always filled in which case the position lags by one pkt
+	if duration < 0 {
+		duration = 0
+	}
+	end := time.Duration(avutil.AvRescaleQ(pts+duration, timeBase, nanosecondRational))
+
+	// Get stream
+	s, ok := p.ss[idx]
+	if !ok {
+		s = &demuxerProgressStream{
+			end:   end,
+			start: time.Duration(avutil.AvRescaleQ(pts, timeBase, nanosecondRational)),
+		}
+		p.ss[idx] = s
+	}
+
+	// Update end
+	if end > s.end {
+		s.end = end
+	}
+}
+
+func (p *demuxerProgress) position() (max time.Duration) {
+	// Lock
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// Loop through streams
+	for _, s := range p.ss {
+		if d := s.end - s.start; d > max {
+			max = d
+		}
+	}
+	return
+}
+
+type demuxerLoop struct {
+	count  int
+	max    int
+	offset time.Duration
+	ss     map[int]*demuxerLoopStream
+}
+
+type demuxerLoopStream struct {
+	end     *time.Duration
+	lastDts *int64
+	start   *time.Duration
+}
+
+func newDemuxerLoop(max int) *demuxerLoop {
+	return &demuxerLoop{
+		max: max,
+		ss:  make(map[int]*demuxerLoopStream),
+	}
+}
+
+func (l *demuxerLoop) restamp(pkt *avcodec.Packet, timeBase avutil.Rational) {
+	// Get offset
+	offset := l.update(pkt.StreamIndex(), pkt.Dts(), pkt.Duration(), timeBase)
+
+	// Restamp
+	if pkt.Dts() != avutil.AV_NOPTS_VALUE {
+		pkt.SetDts(pkt.Dts() + offset)
+	}
+	if pkt.Pts() != avutil.AV_NOPTS_VALUE {
+		pkt.SetPts(pkt.Pts() + offset)
+	}
+}
+
+// update keeps track of the boundaries of the input during the first loop and returns the offset to apply in the
+// stream time base
+func (l *demuxerLoop) update(idx int, dts, duration int64, timeBase avutil.Rational) int64 {
+	// Get stream
+	s, ok := l.ss[idx]
+	if !ok {
+		s = &demuxerLoopStream{}
+		l.ss[idx] = s
+	}
+
+	// Update boundaries
+	if l.count == 0 && dts != avutil.AV_NOPTS_VALUE {
+		// Pkt duration is not always filled therefore we fall back to <current pkt dts> - <previous pkt dts>
+		if duration <= 0 && s.lastDts != nil {
+			duration = dts - *s.lastDts
+		}
+		s.lastDts = astikit.Int64Ptr(dts)
+
+		// Update start and end
+		if start := time.Duration(avutil.AvRescaleQ(dts, timeBase, nanosecondRational)); s.start == nil || start < *s.start {
+			s.start = astikit.DurationPtr(start)
+		}
+		if end := time.Duration(avutil.AvRescaleQ(dts+duration, timeBase, nanosecondRational)); s.end == nil || end > *s.end {
+			s.end = astikit.DurationPtr(end)
+		}
+	}
+	return avutil.AvRescaleQ(int64(l.offset), nanosecondRational, timeBase)
+}
+
+// next returns false if the max number of loops has been reached, otherwise it increments the offset of the duration
+// of the input
+func (l *demuxerLoop) next() bool {
+	// Max number of loops has been reached
+	if l.max > 0 && l.count+1 >= l.max {
+		return false
+	}
+
+	// Get boundaries
+	var start, end *time.Duration
+	for _, s := range l.ss {
+		if s.start != nil && (start == nil || *s.start < *start) {
+			start = s.start
+		}
+		if s.end != nil && (end == nil || *s.end > *end) {
+			end = s.end
+		}
+	}
+
+	// Update offset
+	if start != nil && end != nil {
+		l.offset += *end - *start
+	}
+	l.count++
+	return true
+}
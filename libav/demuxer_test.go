@@ -0,0 +1,54 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemuxerLoop(t *testing.T) {
+	l := newDemuxerLoop(3)
+	v, a := avutil.NewRational(1, 25), avutil.NewRational(1, 1000)
+
+	// First loop
+	assert.Equal(t, int64(0), l.update(0, 0, 1, v))
+	assert.Equal(t, int64(0), l.update(1, 0, 0, a))
+	assert.Equal(t, int64(0), l.update(0, 1, 0, v))
+	assert.Equal(t, int64(0), l.update(1, 40, 0, a))
+
+	// Second loop
+	assert.True(t, l.next())
+	assert.Equal(t, int64(2), l.update(0, 0, 1, v))
+	assert.Equal(t, int64(80), l.update(1, 0, 0, a))
+
+	// Max number of loops
+	assert.True(t, l.next())
+	assert.Equal(t, int64(4), l.update(0, 0, 1, v))
+	assert.False(t, l.next())
+}
+
+func TestDemuxerSpeedStat(t *testing.T) {
+	s := newDemuxerSpeedStat()
+	s.add(0, time.Second)
+	s.add(0, time.Second)
+	s.add(1, 3*time.Second)
+	assert.Equal(t, 1.5, s.Value(2*time.Second))
+	assert.Equal(t, 0.0, s.Value(2*time.Second))
+}
+
+func TestDemuxerProgress(t *testing.T) {
+	p := newDemuxerProgress()
+	v, a := avutil.NewRational(1, 25), avutil.NewRational(1, 1000)
+	assert.Equal(t, time.Duration(0), p.position())
+	p.update(0, 10, 1, v)
+	p.update(1, 400, 40, a)
+	assert.Equal(t, 40*time.Millisecond, p.position())
+	p.update(0, avutil.AV_NOPTS_VALUE, 1, v)
+	p.update(0, 12, 0, v)
+	p.update(1, 440, 40, a)
+	assert.Equal(t, 80*time.Millisecond, p.position())
+	p.update(0, 11, 1, v)
+	assert.Equal(t, 80*time.Millisecond, p.position())
+}
@@ -0,0 +1,184 @@
+package astilibav
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// Video denoise algorithms
+const (
+	VideoDenoiseAlgorithmHQDN3D  = "hqdn3d"
+	VideoDenoiseAlgorithmNLMeans = "nlmeans"
+)
+
+// VideoDenoiseFilterer represents an object capable of denoising video frames
+// Strength can be updated while the filterer is running
+type VideoDenoiseFilterer struct {
+	*Filterer
+	algorithm string
+}
+
+// VideoDenoiseFiltererOptions represents video denoise filterer options
+type VideoDenoiseFiltererOptions struct {
+	// Default is hqdn3d which is much faster than nlmeans
+	Algorithm string
+	Input     astiencoder.Node
+	Node      astiencoder.NodeOptions
+	OutputCtx Context
+	Restamper FrameRestamper
+	// Between 0 and 1
+	Strength float64
+}
+
+// NewVideoDenoiseFilterer creates a new video denoise filterer
+func NewVideoDenoiseFilterer(o VideoDenoiseFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *VideoDenoiseFilterer, err error) {
+	// Default algorithm
+	if o.Algorithm == "" {
+		o.Algorithm = VideoDenoiseAlgorithmHQDN3D
+	}
+
+	// Create filterer
+	f = &VideoDenoiseFilterer{algorithm: o.Algorithm}
+
+	// Get content
+	var content string
+	if content, err = f.content(o.Strength); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   content,
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *VideoDenoiseFilterer) content(strength float64) (string, error) {
+	// Invalid strength
+	if err := validateDenoiseStrength(strength); err != nil {
+		return "", err
+	}
+
+	// Switch on algorithm
+	switch f.algorithm {
+	case VideoDenoiseAlgorithmHQDN3D:
+		// Temporal and chroma strengths are derived from the luma spatial strength
+		return "hqdn3d=luma_spatial=" + formatFilterFloat(20*strength), nil
+	case VideoDenoiseAlgorithmNLMeans:
+		return "nlmeans=s=" + formatFilterFloat(1+29*strength), nil
+	default:
+		return "", fmt.Errorf("astilibav: invalid algorithm %s", f.algorithm)
+	}
+}
+
+// SetStrength updates the strength which must be between 0 and 1
+func (f *VideoDenoiseFilterer) SetStrength(strength float64) (err error) {
+	// Get content
+	var content string
+	if content, err = f.content(strength); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Set content
+	if err = f.SetContent(content); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+	return
+}
+
+// AudioDenoiseFilterer represents an object capable of denoising audio frames using ffmpeg's afftdn filter
+// Strength can be updated while the filterer is running
+type AudioDenoiseFilterer struct {
+	*Filterer
+	noiseFloor float64
+}
+
+// AudioDenoiseFiltererOptions represents audio denoise filterer options
+type AudioDenoiseFiltererOptions struct {
+	Input astiencoder.Node
+	Node  astiencoder.NodeOptions
+	// Noise floor in dB. Default is -50
+	NoiseFloor float64
+	OutputCtx  Context
+	Restamper  FrameRestamper
+	// Between 0 and 1
+	Strength float64
+}
+
+// NewAudioDenoiseFilterer creates a new audio denoise filterer
+func NewAudioDenoiseFilterer(o AudioDenoiseFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *AudioDenoiseFilterer, err error) {
+	// Default noise floor
+	if o.NoiseFloor == 0 {
+		o.NoiseFloor = -50
+	}
+
+	// Create filterer
+	f = &AudioDenoiseFilterer{noiseFloor: o.NoiseFloor}
+
+	// Get content
+	var content string
+	if content, err = f.content(o.Strength); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   content,
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *AudioDenoiseFilterer) content(strength float64) (string, error) {
+	// Invalid strength
+	if err := validateDenoiseStrength(strength); err != nil {
+		return "", err
+	}
+
+	// Noise reduction must be at least 0.01dB
+	return "afftdn=nr=" + formatFilterFloat(math.Max(0.01, 40*strength)) + ":nf=" + formatFilterFloat(f.noiseFloor), nil
+}
+
+// SetStrength updates the strength which must be between 0 and 1
+func (f *AudioDenoiseFilterer) SetStrength(strength float64) (err error) {
+	// Get content
+	var content string
+	if content, err = f.content(strength); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Set content
+	if err = f.SetContent(content); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+	return
+}
+
+func validateDenoiseStrength(strength float64) error {
+	if strength < 0 || strength > 1 {
+		return fmt.Errorf("astilibav: invalid strength %v", strength)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDenoiseContent(t *testing.T) {
+	v := &VideoDenoiseFilterer{algorithm: VideoDenoiseAlgorithmHQDN3D}
+	c, err := v.content(0.25)
+	assert.NoError(t, err)
+	assert.Equal(t, "hqdn3d=luma_spatial=5", c)
+	v.algorithm = VideoDenoiseAlgorithmNLMeans
+	c, err = v.content(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "nlmeans=s=30", c)
+	_, err = v.content(2)
+	assert.Error(t, err)
+
+	a := &AudioDenoiseFilterer{noiseFloor: -50}
+	c, err = a.content(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "afftdn=nr=0.01:nf=-50", c)
+}
@@ -0,0 +1,110 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// DeviceManager represents an object capable of assigning new NVIDIA hardware nodes to the least loaded GPU
+// The load of a GPU is the number of NVDEC and NVENC sessions opened on it by all nodes
+type DeviceManager struct {
+	eh *astiencoder.EventHandler
+	o  DeviceManagerOptions
+}
+
+// DeviceManagerOptions represents device manager options
+type DeviceManagerOptions struct {
+	// Indexes of the GPUs nodes can be assigned to
+	GPUs []int
+	// Period at which loads are reported. Default is 5s
+	Period time.Duration
+}
+
+// DeviceLoad represents the load of a GPU
+type DeviceLoad struct {
+	// Number of NVDEC sessions
+	Decoders int
+	// Number of NVENC sessions
+	Encoders int
+	GPU      int
+}
+
+// Sessions returns the total number of sessions opened on the GPU
+func (l DeviceLoad) Sessions() int {
+	return l.Decoders + l.Encoders
+}
+
+// NewDeviceManager creates a new device manager
+func NewDeviceManager(o DeviceManagerOptions, eh *astiencoder.EventHandler) (m *DeviceManager, err error) {
+	// No GPUs
+	if len(o.GPUs) == 0 {
+		err = errors.New("astilibav: no gpus provided")
+		return
+	}
+
+	// Default options
+	if o.Period <= 0 {
+		o.Period = 5 * time.Second
+	}
+
+	// Create manager
+	m = &DeviceManager{
+		eh: eh,
+		o:  o,
+	}
+	return
+}
+
+// Loads returns the load of each GPU ordered by GPU index
+func (m *DeviceManager) Loads() (ls []DeviceLoad) {
+	// Get sessions
+	ds := nvdecSessions.count()
+	es := nvencSessions.count()
+
+	// Loop through GPUs
+	for _, gpu := range m.o.GPUs {
+		ls = append(ls, DeviceLoad{
+			Decoders: ds[gpu],
+			Encoders: es[gpu],
+			GPU:      gpu,
+		})
+	}
+
+	// Sort
+	sort.Slice(ls, func(i, j int) bool { return ls[i].GPU < ls[j].GPU })
+	return
+}
+
+// LeastLoadedGPU returns the GPU with the fewest sessions. Ties are broken by the lowest index
+func (m *DeviceManager) LeastLoadedGPU() int {
+	ls := m.Loads()
+	l := ls[0]
+	for _, v := range ls[1:] {
+		if v.Sessions() < l.Sessions() {
+			l = v
+		}
+	}
+	return l.GPU
+}
+
+// Start reports loads until the context is done
+func (m *DeviceManager) Start(ctx context.Context) {
+	t := time.NewTicker(m.o.Period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			m.eh.Emit(astiencoder.Event{
+				Name:    DeviceLoadsReported,
+				Payload: m.Loads(),
+				Target:  m,
+			})
+		}
+	}
+}
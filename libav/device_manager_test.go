@@ -0,0 +1,33 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceManager(t *testing.T) {
+	_, err := NewDeviceManager(DeviceManagerOptions{}, nil)
+	assert.Error(t, err)
+
+	m, err := NewDeviceManager(DeviceManagerOptions{GPUs: []int{2, 0, 1}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, m.LeastLoadedGPU())
+
+	nvdecSessions.acquire(0, 0)
+	defer nvdecSessions.release(0)
+	nvencSessions.acquire(0, 0)
+	defer nvencSessions.release(0)
+	nvencSessions.acquire(1, 0)
+	defer nvencSessions.release(1)
+	assert.Equal(t, []DeviceLoad{
+		{Decoders: 1, Encoders: 1, GPU: 0},
+		{Encoders: 1, GPU: 1},
+		{GPU: 2},
+	}, m.Loads())
+	assert.Equal(t, 2, m.LeastLoadedGPU())
+
+	nvdecSessions.acquire(2, 0)
+	defer nvdecSessions.release(2)
+	assert.Equal(t, 1, m.LeastLoadedGPU())
+}
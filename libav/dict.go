@@ -2,6 +2,7 @@ package astilibav
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/asticode/goav/avutil"
 )
@@ -30,6 +31,38 @@ func NewDefaultDictf(format string, args ...interface{}) *Dict {
 	return NewDict(fmt.Sprintf(format, args...), "=", ",", 0)
 }
 
+// String returns the content of the dict
+func (d *Dict) String() string {
+	if d == nil {
+		return ""
+	}
+	return d.i
+}
+
+// pairs returns the key/value pairs of the dict
+func (d *Dict) pairs() (ps [][2]string) {
+	if d == nil || d.i == "" {
+		return
+	}
+	for _, p := range strings.Split(d.i, d.pairsSep) {
+		kv := strings.SplitN(p, d.keyValSep, 2)
+		if len(kv) == 2 {
+			ps = append(ps, [2]string{kv[0], kv[1]})
+		} else {
+			ps = append(ps, [2]string{kv[0], ""})
+		}
+	}
+	return
+}
+
+// withPair returns a copy of the dict with an additional key/value pair
+func (d *Dict) withPair(k, v string) *Dict {
+	if d == nil || d.i == "" {
+		return NewDefaultDict(k + "=" + v)
+	}
+	return NewDict(d.i+d.pairsSep+k+d.keyValSep+v, d.keyValSep, d.pairsSep, d.flags)
+}
+
 func (d *Dict) Parse(i **avutil.Dictionary) (err error) {
 	if ret := avutil.AvDictParseString(i, d.i, d.keyValSep, d.pairsSep, d.flags); ret < 0 {
 		err = fmt.Errorf("astilibav: avutil.AvDictParseString on %s failed: %w", d.i, NewAvError(ret))
@@ -0,0 +1,13 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictWithPair(t *testing.T) {
+	var d *Dict
+	assert.Equal(t, NewDefaultDict("gpu=1"), d.withPair("gpu", "1"))
+	assert.Equal(t, NewDict("preset:p4&gpu:1", ":", "&", 0), NewDict("preset:p4", ":", "&", 0).withPair("gpu", "1"))
+}
@@ -0,0 +1,175 @@
+package astilibav
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// DiskSpaceMonitor represents an object capable of monitoring the free space of the filesystems written to by
+// recorders, DVRs and other file outputs, and of applying a policy before writes start failing
+type DiskSpaceMonitor struct {
+	eh     *astiencoder.EventHandler
+	m      *sync.Mutex
+	o      DiskSpaceMonitorOptions
+	statFS func(dir string) (DiskSpace, error)
+	ts     []*diskSpaceTarget
+}
+
+// DiskSpaceMonitorOptions represents disk space monitor options
+type DiskSpaceMonitorOptions struct {
+	// Period at which free space is checked. Default is 10s
+	Period time.Duration
+	// Free space in bytes below which a filesystem is considered as running out of space
+	Threshold uint64
+}
+
+// DiskSpace represents the space of the filesystem of a directory
+// It is the payload of the DiskSpaceLow and DiskSpaceRecovered events
+type DiskSpace struct {
+	Dir string
+	// In bytes, available to unprivileged users
+	Free uint64
+	// In bytes
+	Total uint64
+}
+
+type diskSpaceTarget struct {
+	dir string
+	low bool
+	n   astiencoder.Node
+	// Executed at each check while space is low
+	onLow       func(s DiskSpace)
+	onRecovered func()
+}
+
+// NewDiskSpaceMonitor creates a new disk space monitor
+func NewDiskSpaceMonitor(o DiskSpaceMonitorOptions, eh *astiencoder.EventHandler) *DiskSpaceMonitor {
+	// Default options
+	if o.Period <= 0 {
+		o.Period = 10 * time.Second
+	}
+
+	// Create monitor
+	return &DiskSpaceMonitor{
+		eh:     eh,
+		m:      &sync.Mutex{},
+		o:      o,
+		statFS: diskSpace,
+	}
+}
+
+// WatchNode watches the filesystem of a directory the node writes to. Only events are emitted
+func (m *DiskSpaceMonitor) WatchNode(dir string, n astiencoder.Node) {
+	m.add(&diskSpaceTarget{
+		dir: dir,
+		n:   n,
+	})
+}
+
+// WatchRecorder watches the filesystem of a directory the recorder writes to
+// When space is low, the recording in progress is stopped and no recording can start until space has recovered
+func (m *DiskSpaceMonitor) WatchRecorder(dir string, r *Recorder) {
+	m.add(&diskSpaceTarget{
+		dir:         dir,
+		n:           r,
+		onLow:       func(DiskSpace) { r.suspend(true) },
+		onRecovered: func() { r.suspend(false) },
+	})
+}
+
+// WatchDVR watches the filesystem of a directory the DVR's muxer writes to
+// When space is low, the oldest files of the DVR window are removed until space has recovered
+func (m *DiskSpaceMonitor) WatchDVR(dir string, d *DVR) {
+	m.add(&diskSpaceTarget{
+		dir: dir,
+		n:   d.mx,
+		onLow: func(s DiskSpace) {
+			for s.Free < m.o.Threshold && d.removeOldest() {
+				var err error
+				if s, err = m.statFS(dir); err != nil {
+					m.eh.Emit(astiencoder.EventError(d.mx, fmt.Errorf("astilibav: getting disk space of %s failed: %w", dir, err)))
+					return
+				}
+			}
+		},
+	})
+}
+
+func (m *DiskSpaceMonitor) add(t *diskSpaceTarget) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.ts = append(m.ts, t)
+}
+
+// Start checks free space periodically until the context is done
+func (m *DiskSpaceMonitor) Start(ctx context.Context) {
+	// Check right away
+	m.check()
+
+	// Create ticker
+	t := time.NewTicker(m.o.Period)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			m.check()
+		}
+	}
+}
+
+func (m *DiskSpaceMonitor) check() {
+	// Get targets
+	m.m.Lock()
+	ts := append([]*diskSpaceTarget{}, m.ts...)
+	m.m.Unlock()
+
+	// Loop through targets
+	for _, t := range ts {
+		// Get disk space
+		s, err := m.statFS(t.dir)
+		if err != nil {
+			m.eh.Emit(astiencoder.EventError(t.n, fmt.Errorf("astilibav: getting disk space of %s failed: %w", t.dir, err)))
+			continue
+		}
+
+		// Space is low
+		if s.Free < m.o.Threshold {
+			// Emit event
+			if !t.low {
+				t.low = true
+				m.eh.Emit(astiencoder.Event{
+					Name:    DiskSpaceLow,
+					Payload: s,
+					Target:  t.n,
+				})
+			}
+
+			// Apply policy
+			if t.onLow != nil {
+				t.onLow(s)
+			}
+			continue
+		}
+
+		// Space has recovered
+		if t.low {
+			t.low = false
+			if t.onRecovered != nil {
+				t.onRecovered()
+			}
+			m.eh.Emit(astiencoder.Event{
+				Name:    DiskSpaceRecovered,
+				Payload: s,
+				Target:  t.n,
+			})
+		}
+	}
+}
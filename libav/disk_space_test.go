@@ -0,0 +1,87 @@
+package astilibav
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskSpaceMonitor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "astilibav-disk-space-test-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Create monitor
+	eh := astiencoder.NewEventHandler()
+	m := NewDiskSpaceMonitor(DiskSpaceMonitorOptions{Threshold: 10}, eh)
+	free := map[string]uint64{"dvr": 5, "node": 20, "recorder": 5}
+	m.statFS = func(dir string) (DiskSpace, error) {
+		if dir == "error" {
+			return DiskSpace{}, errors.New("test")
+		}
+		return DiskSpace{Dir: dir, Free: free[dir], Total: 100}, nil
+	}
+	var es []astiencoder.Event
+	eh.AddForAll(func(e astiencoder.Event) bool {
+		es = append(es, e)
+		return false
+	})
+
+	// Watch
+	n := &Decoder{}
+	m.WatchNode("node", n)
+	m.WatchNode("error", n)
+	r := &Recorder{m: &sync.Mutex{}, manual: true}
+	m.WatchRecorder("recorder", r)
+	d := &DVR{
+		eh:      eh,
+		m:       &sync.Mutex{},
+		mx:      &Muxer{},
+		pins:    make(map[string]int),
+		removed: make(map[string]bool),
+	}
+	for i := 0; i < 3; i++ {
+		f := MuxerFile{Duration: time.Second, URL: filepath.Join(dir, "f"+string(rune('1'+i))+".ts")}
+		assert.NoError(t, ioutil.WriteFile(f.URL, []byte("x"), 0666))
+		d.files = append(d.files, f)
+	}
+	m.WatchDVR("dvr", d)
+	url := d.files[0].URL
+
+	// Space is low
+	m.check()
+	assert.Len(t, es, 3)
+	assert.Equal(t, astiencoder.EventNameError, es[0].Name)
+	assert.Equal(t, DiskSpaceLow, es[1].Name)
+	assert.Equal(t, DiskSpace{Dir: "recorder", Free: 5, Total: 100}, es[1].Payload)
+	assert.Equal(t, r, es[1].Target)
+	assert.False(t, r.shouldRecord(time.Now()))
+	assert.Equal(t, DiskSpaceLow, es[2].Name)
+	assert.Equal(t, d.mx, es[2].Target)
+	assert.Len(t, d.files, 1)
+	_, err = os.Stat(url)
+	assert.True(t, os.IsNotExist(err))
+
+	// Events are only emitted on transitions
+	free["node"] = 5
+	m.check()
+	assert.Len(t, es, 5)
+	assert.Equal(t, DiskSpaceLow, es[3].Name)
+	assert.Equal(t, n, es[3].Target)
+	assert.Equal(t, astiencoder.EventNameError, es[4].Name)
+
+	// Space has recovered
+	free["recorder"] = 50
+	m.check()
+	assert.Len(t, es, 7)
+	assert.Equal(t, DiskSpaceRecovered, es[6].Name)
+	assert.Equal(t, r, es[6].Target)
+	assert.True(t, r.shouldRecord(time.Now()))
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+// +build !windows
+
+package astilibav
+
+import "syscall"
+
+func diskSpace(dir string) (s DiskSpace, err error) {
+	// Stat
+	var st syscall.Statfs_t
+	if err = syscall.Statfs(dir, &st); err != nil {
+		return
+	}
+
+	// Create disk space
+	s = DiskSpace{
+		Dir:   dir,
+		Free:  uint64(st.Bavail) * uint64(st.Bsize),
+		Total: uint64(st.Blocks) * uint64(st.Bsize),
+	}
+	return
+}
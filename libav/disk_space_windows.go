@@ -0,0 +1,7 @@
+package astilibav
+
+import "errors"
+
+func diskSpace(dir string) (DiskSpace, error) {
+	return DiskSpace{}, errors.New("astilibav: getting disk space is not supported on windows")
+}
@@ -0,0 +1,356 @@
+package astilibav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avformat"
+)
+
+// DVR represents an object capable of maintaining a rolling on-disk buffer of the files rotated by a muxer
+// The buffer can be played back through HTTP or exported as clips through demuxers that can be used in other
+// workflows
+type DVR struct {
+	eh    *astiencoder.EventHandler
+	files []MuxerFile
+	m     *sync.Mutex
+	mx    *Muxer
+	o     DVROptions
+	pins  map[string]int
+	// Files that have left the window while pinned
+	removed map[string]bool
+}
+
+// DVROptions represents DVR options
+type DVROptions struct {
+	// If true, files leaving the window are not removed from disk
+	KeepFiles bool
+	// Prefix added to file names in playlists. Default is ""
+	SegmentBaseURL string
+	// Duration of the window
+	Window time.Duration
+}
+
+// NewDVR creates a new DVR
+// The muxer must have rotation enabled and should use a format whose files can be concatenated (e.g. mpegts)
+func NewDVR(o DVROptions, m *Muxer, eh *astiencoder.EventHandler) (d *DVR, err error) {
+	// No rotation
+	if m.rotation == nil {
+		err = errors.New("astilibav: muxer rotation is disabled")
+		return
+	}
+
+	// Files are played back to back
+	m.rotation.o.KeepTimestamps = true
+
+	// No window
+	if o.Window <= 0 {
+		err = fmt.Errorf("astilibav: invalid window %s", o.Window)
+		return
+	}
+
+	// Create dvr
+	d = &DVR{
+		eh:      eh,
+		m:       &sync.Mutex{},
+		mx:      m,
+		o:       o,
+		pins:    make(map[string]int),
+		removed: make(map[string]bool),
+	}
+
+	// Handle muxer files
+	eh.Add(m, MuxerFileCompleted, func(e astiencoder.Event) bool {
+		d.handleFile(e.Payload.(MuxerFile))
+		return false
+	})
+	return
+}
+
+func (d *DVR) handleFile(f MuxerFile) {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	// Append
+	d.files = append(d.files, f)
+
+	// Slide window
+	var total time.Duration
+	for _, f := range d.files {
+		total += f.Duration
+	}
+	for len(d.files) > 1 && total-d.files[0].Duration >= d.o.Window {
+		total -= d.files[0].Duration
+		d.remove(d.files[0].URL)
+		d.files = d.files[1:]
+	}
+}
+
+// removeOldest removes the oldest file of the window, unless it is the only one left or files are kept, and returns
+// whether a file has been removed
+func (d *DVR) removeOldest() bool {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	// Files are kept or only one file is left
+	if d.o.KeepFiles || len(d.files) <= 1 {
+		return false
+	}
+
+	// Remove
+	d.remove(d.files[0].URL)
+	d.files = d.files[1:]
+	return true
+}
+
+func (d *DVR) remove(url string) {
+	// Files are kept
+	if d.o.KeepFiles {
+		return
+	}
+
+	// File is pinned
+	if d.pins[url] > 0 {
+		d.removed[url] = true
+		return
+	}
+
+	// Remove
+	if err := os.Remove(url); err != nil && !os.IsNotExist(err) {
+		d.eh.Emit(astiencoder.EventError(d.mx, fmt.Errorf("astilibav: removing %s failed: %w", url, err)))
+	}
+	delete(d.removed, url)
+}
+
+// Window returns the time range currently available
+func (d *DVR) Window() (from, to time.Time) {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	// No files
+	if len(d.files) == 0 {
+		return
+	}
+	return d.files[0].StartedAt, d.files[len(d.files)-1].EndedAt
+}
+
+// Files returns the files overlapping the time range. A zero to means up to the live edge
+func (d *DVR) Files(from, to time.Time) []MuxerFile {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+	return d.filesUnlocked(from, to)
+}
+
+func (d *DVR) filesUnlocked(from, to time.Time) (fs []MuxerFile) {
+	for _, f := range d.files {
+		if f.EndedAt.After(from) && (to.IsZero() || f.StartedAt.Before(to)) {
+			fs = append(fs, f)
+		}
+	}
+	return
+}
+
+// Playlist returns an HLS playlist of the time range. A zero to means up to the live edge in which case the playlist
+// is an event playlist
+func (d *DVR) Playlist(from, to time.Time) []byte {
+	// Get files
+	fs := d.Files(from, to)
+
+	// Get target duration
+	var targetDuration time.Duration
+	for _, f := range fs {
+		if f.Duration > targetDuration {
+			targetDuration = f.Duration
+		}
+	}
+
+	// Header
+	buf := &bytes.Buffer{}
+	buf.WriteString("#EXTM3U\n")
+	buf.WriteString("#EXT-X-VERSION:3\n")
+	buf.WriteString("#EXT-X-TARGETDURATION:" + strconv.Itoa(int(math.Ceil(targetDuration.Seconds()))) + "\n")
+	if len(fs) > 0 {
+		buf.WriteString("#EXT-X-MEDIA-SEQUENCE:" + strconv.Itoa(fs[0].Sequence) + "\n")
+	}
+	if to.IsZero() {
+		buf.WriteString("#EXT-X-PLAYLIST-TYPE:EVENT\n")
+	} else {
+		buf.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	}
+
+	// Loop through files
+	for _, f := range fs {
+		buf.WriteString("#EXT-X-PROGRAM-DATE-TIME:" + f.StartedAt.UTC().Format("2006-01-02T15:04:05.000Z07:00") + "\n")
+		buf.WriteString("#EXTINF:" + hlsSeconds(f.Duration) + ",\n")
+		buf.WriteString(d.o.SegmentBaseURL + filepath.Base(f.URL) + "\n")
+	}
+
+	// End list
+	if !to.IsZero() {
+		buf.WriteString("#EXT-X-ENDLIST\n")
+	}
+	return buf.Bytes()
+}
+
+// ServeHTTP implements the http.Handler interface
+// Requests ending with ".m3u8" are served a playlist of the time range provided through the "from" and "to" RFC3339
+// query parameters. A missing "from" means the beginning of the window and a missing "to" means the live edge.
+// Other requests are served the file of the window whose name matches the last element of the path
+func (d *DVR) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	// Playlist
+	if strings.HasSuffix(r.URL.Path, ".m3u8") {
+		// Parse query
+		var from, to time.Time
+		for k, t := range map[string]*time.Time{"from": &from, "to": &to} {
+			if v := r.URL.Query().Get(k); v != "" {
+				var err error
+				if *t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+					rw.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		// Write
+		rw.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		rw.Write(d.Playlist(from, to))
+		return
+	}
+
+	// Get file
+	var url string
+	name := filepath.Base(r.URL.Path)
+	d.m.Lock()
+	for _, f := range d.files {
+		if filepath.Base(f.URL) == name {
+			url = f.URL
+			break
+		}
+	}
+	d.m.Unlock()
+
+	// File not found
+	if url == "" {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	http.ServeFile(rw, r, url)
+}
+
+// NewClipDemuxer creates a demuxer reading the time range which can be used to export a clip in another workflow
+// Files of the time range are not removed from disk until the closer is closed
+func (d *DVR) NewClipDemuxer(from, to time.Time, o DemuxerOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (dm *Demuxer, err error) {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	// Get files
+	fs := d.filesUnlocked(from, to)
+	if len(fs) == 0 {
+		err = errors.New("astilibav: no files in time range")
+		return
+	}
+
+	// Write concat script
+	var url string
+	if url, err = writeDVRConcatScript(fs, from, to); err != nil {
+		err = fmt.Errorf("astilibav: writing concat script failed: %w", err)
+		return
+	}
+
+	// Pin files
+	for _, f := range fs {
+		d.pins[f.URL]++
+	}
+
+	// Make sure files are unpinned and the script is removed
+	c.Add(func() error {
+		// Remove script
+		os.Remove(url)
+
+		// Lock
+		d.m.Lock()
+		defer d.m.Unlock()
+
+		// Unpin files
+		for _, f := range fs {
+			if d.pins[f.URL]--; d.pins[f.URL] <= 0 {
+				delete(d.pins, f.URL)
+				if d.removed[f.URL] {
+					d.remove(f.URL)
+				}
+			}
+		}
+		return nil
+	})
+
+	// Find format
+	if o.Format = avformat.AvFindInputFormat("concat"); o.Format == nil {
+		err = errors.New("astilibav: concat input format not found")
+		return
+	}
+
+	// Create demuxer
+	o.Dict = NewDict("safe=0", "=", ",", 0)
+	o.URL = url
+	if dm, err = NewDemuxer(o, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+	return
+}
+
+func writeDVRConcatScript(fs []MuxerFile, from, to time.Time) (url string, err error) {
+	// Create script
+	buf := &bytes.Buffer{}
+	buf.WriteString("ffconcat version 1.0\n")
+	for idx, f := range fs {
+		// Paths must be absolute since the script is written in the temp dir
+		var p string
+		if p, err = filepath.Abs(f.URL); err != nil {
+			err = fmt.Errorf("astilibav: getting absolute path of %s failed: %w", f.URL, err)
+			return
+		}
+		buf.WriteString("file '" + strings.Replace(p, "'", `'\''`, -1) + "'\n")
+
+		// Trim first and last files
+		if idx == 0 && from.After(f.StartedAt) {
+			buf.WriteString("inpoint " + hlsSeconds(from.Sub(f.StartedAt)) + "\n")
+		}
+		if idx == len(fs)-1 && !to.IsZero() && to.Before(f.EndedAt) {
+			buf.WriteString("outpoint " + hlsSeconds(to.Sub(f.StartedAt)) + "\n")
+		}
+	}
+
+	// Write
+	var t *os.File
+	if t, err = ioutil.TempFile("", "astilibav-dvr-*.ffconcat"); err != nil {
+		err = fmt.Errorf("astilibav: creating temp file failed: %w", err)
+		return
+	}
+	_, err = t.Write(buf.Bytes())
+	t.Close()
+	if err != nil {
+		os.Remove(t.Name())
+		err = fmt.Errorf("astilibav: writing to %s failed: %w", t.Name(), err)
+		return
+	}
+	url = t.Name()
+	return
+}
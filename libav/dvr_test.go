@@ -0,0 +1,76 @@
+package astilibav
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDVR(t *testing.T) {
+	dir, err := ioutil.TempDir("", "astilibav-dvr-test-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	d := &DVR{
+		eh:      astiencoder.NewEventHandler(),
+		m:       &sync.Mutex{},
+		o:       DVROptions{Window: 4 * time.Second},
+		pins:    make(map[string]int),
+		removed: make(map[string]bool),
+	}
+	n := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var fs []MuxerFile
+	for i := 0; i < 4; i++ {
+		f := MuxerFile{
+			Duration:  2 * time.Second,
+			EndedAt:   n.Add(time.Duration(i+1) * 2 * time.Second),
+			Sequence:  i + 1,
+			StartedAt: n.Add(time.Duration(i) * 2 * time.Second),
+			URL:       filepath.Join(dir, "f"+string(rune('1'+i))+".ts"),
+		}
+		assert.NoError(t, ioutil.WriteFile(f.URL, []byte("x"), 0666))
+		fs = append(fs, f)
+	}
+
+	d.handleFile(fs[0])
+	d.handleFile(fs[1])
+	d.pins[fs[0].URL] = 1
+	d.handleFile(fs[2])
+	_, err = os.Stat(fs[0].URL)
+	assert.NoError(t, err)
+	from, to := d.Window()
+	assert.Equal(t, fs[1].StartedAt, from)
+	assert.Equal(t, fs[2].EndedAt, to)
+
+	delete(d.pins, fs[0].URL)
+	d.remove(fs[0].URL)
+	_, err = os.Stat(fs[0].URL)
+	assert.True(t, os.IsNotExist(err))
+
+	d.handleFile(fs[3])
+	_, err = os.Stat(fs[1].URL)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:2
+#EXT-X-MEDIA-SEQUENCE:3
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXT-X-PROGRAM-DATE-TIME:2020-01-01T00:00:04.000Z
+#EXTINF:2.00000,
+f3.ts
+#EXT-X-ENDLIST
+`, string(d.Playlist(n.Add(5*time.Second), n.Add(6*time.Second))))
+
+	url, err := writeDVRConcatScript(d.Files(time.Time{}, time.Time{}), n.Add(5*time.Second), n.Add(7*time.Second))
+	assert.NoError(t, err)
+	defer os.Remove(url)
+	b, err := ioutil.ReadFile(url)
+	assert.NoError(t, err)
+	assert.Equal(t, "ffconcat version 1.0\nfile '"+fs[2].URL+"'\ninpoint 1.00000\nfile '"+fs[3].URL+"'\noutpoint 1.00000\n", string(b))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync/atomic"
+	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -18,18 +19,34 @@ var countEncoder uint64
 // Encoder represents an object capable of encoding frames
 type Encoder struct {
 	*astiencoder.BaseNode
-	c                  *astikit.Chan
+	c                  *astiencoder.Queue
 	ctxCodec           *avcodec.Context
 	d                  *pktDispatcher
+	definition         EncoderDefinitionOptions
 	eh                 *astiencoder.EventHandler
+	hardwareFrames     *HardwareFramesContext
+	lazyOpen           func() error
+	opened             bool
+	pendingBitRate     int64
 	previousDescriptor Descriptor
+	spans              *pendingSpans
 	statIncomingRate   *astikit.CounterRateStat
 	statWorkRatio      *astikit.DurationPercentageStat
+	streams            []*avformat.Stream
 }
 
 // EncoderOptions represents encoder options
 type EncoderOptions struct {
-	Ctx  Context
+	// If set, the codec context is taken from the cache when possible and given back to it once the encoder is closed
+	// Encoders using hardware frames or a hardware device are not cached
+	Cache *EncoderCache
+	Ctx   Context
+	// If set, the encoder uses the device when the codec supports it
+	HardwareDevice *HardwareDeviceContext
+	// If true, the codec is only opened once the first frame is received so that branches that never receive data
+	// don't pay for it. Since stream parameters are only final once the codec is opened, streams added with AddStream
+	// must belong to lazy muxers and FrameSize is only valid once the first frame has been received
+	Lazy bool
 	Node astiencoder.NodeOptions
 }
 
@@ -41,12 +58,11 @@ func NewEncoder(o EncoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 
 	// Create encoder
 	e = &Encoder{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
-		d:                newPktDispatcher(c),
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		d:                newPktDispatcher(),
+		definition:       newEncoderDefinitionOptions(o),
 		eh:               eh,
+		spans:            newPendingSpans(),
 		statIncomingRate: astikit.NewCounterRateStat(),
 		statWorkRatio:    astikit.NewDurationPercentageStat(),
 	}
@@ -76,12 +92,36 @@ func NewEncoder(o EncoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 		return
 	}
 
+	// Get cache key
+	var cacheKey string
+	if o.Cache != nil && o.HardwareDevice == nil && o.Ctx.HardwareFramesCtx == nil && codecSupportsEncoderFlush(cdc) {
+		cacheKey = o.Ctx.encoderCacheKey()
+	}
+
+	// Make sure the codec is closed or given back to the cache
+	c.Add(func() error {
+		e.close(o.Cache, cacheKey, o.Ctx.BitRate)
+		return nil
+	})
+
+	// Reuse cached codec context
+	if cacheKey != "" {
+		if e.ctxCodec = o.Cache.get(cacheKey); e.ctxCodec != nil {
+			e.opened = true
+			registerLogOwner(unsafe.Pointer(e.ctxCodec), e)
+			return
+		}
+	}
+
 	// Alloc context
 	if e.ctxCodec = cdc.AvcodecAllocContext3(); e.ctxCodec == nil {
 		err = errors.New("astilibav: no context allocated")
 		return
 	}
 
+	// Attribute libav logs to the encoder
+	registerLogOwner(unsafe.Pointer(e.ctxCodec), e)
+
 	// Set shared context parameters
 	if o.Ctx.GlobalHeader {
 		e.ctxCodec.SetFlags(e.ctxCodec.Flags() | avcodec.AV_CODEC_FLAG_GLOBAL_HEADER)
@@ -89,6 +129,10 @@ func NewEncoder(o EncoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 	if o.Ctx.ThreadCount != nil {
 		e.ctxCodec.SetThreadCount(*o.Ctx.ThreadCount)
 	}
+	if err = setCodecThreading(e.ctxCodec, cdc, o.Ctx); err != nil {
+		err = fmt.Errorf("astilibav: setting codec threading failed: %w", err)
+		return
+	}
 
 	// Set media type-specific context parameters
 	switch o.Ctx.CodecType {
@@ -112,6 +156,36 @@ func NewEncoder(o EncoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 		return
 	}
 
+	// Encode hardware frames
+	// Frames living in system memory are uploaded before being encoded
+	if o.Ctx.HardwareFramesCtx != nil {
+		if err = setEncoderHardwareFrames(e.ctxCodec, o.Ctx.HardwareFramesCtx); err != nil {
+			err = fmt.Errorf("astilibav: setting encoder hardware frames failed: %w", err)
+			return
+		}
+		e.hardwareFrames = o.Ctx.HardwareFramesCtx
+	} else if o.HardwareDevice != nil {
+		if err = setEncoderHardwareDevice(e.ctxCodec, o.HardwareDevice); err != nil {
+			err = fmt.Errorf("astilibav: setting encoder hardware device failed: %w", err)
+			return
+		}
+	}
+
+	// Open lazily
+	if o.Lazy {
+		e.lazyOpen = func() error { return e.open(cdc, o) }
+		return
+	}
+
+	// Open
+	if err = e.open(cdc, o); err != nil {
+		err = fmt.Errorf("astilibav: opening encoder failed: %w", err)
+		return
+	}
+	return
+}
+
+func (e *Encoder) open(cdc *avcodec.Codec, o EncoderOptions) (err error) {
 	// Dict
 	var dict *avutil.Dictionary
 	if o.Ctx.Dict != nil {
@@ -125,22 +199,43 @@ func NewEncoder(o EncoderOptions, eh *astiencoder.EventHandler, c *astikit.Close
 		defer avutil.AvDictFree(&dict)
 	}
 
+	// Pin codec threads
+	var unlock func()
+	if unlock, err = lockOSThreadToNodeCPUs(o.Node); err != nil {
+		err = fmt.Errorf("astilibav: locking os thread to node cpus failed: %w", err)
+		return
+	}
+	defer unlock()
+
 	// Open codec
 	if ret := e.ctxCodec.AvcodecOpen2(cdc, &dict); ret < 0 {
 		err = fmt.Errorf("astilibav: d.e.ctxCodec.AvcodecOpen2 failed: %w", NewAvError(ret))
 		return
 	}
-
-	// Make sure the codec is closed
-	c.Add(func() error {
-		if ret := e.ctxCodec.AvcodecClose(); ret < 0 {
-			emitAvError(nil, eh, ret, "d.e.ctxCodec.AvcodecClose failed")
-		}
-		return nil
-	})
+	e.opened = true
 	return
 }
 
+func (e *Encoder) close(cache *EncoderCache, cacheKey string, bitRate int) {
+	// No codec context
+	if e.ctxCodec == nil {
+		return
+	}
+
+	// Stop attributing libav logs to the encoder
+	unregisterLogOwner(unsafe.Pointer(e.ctxCodec))
+
+	// Give the codec context back to the cache
+	if cacheKey != "" && e.opened && cache.put(cacheKey, e.ctxCodec, bitRate) {
+		return
+	}
+
+	// Close codec
+	if ret := e.ctxCodec.AvcodecClose(); ret < 0 {
+		emitAvError(nil, e.eh, ret, "d.e.ctxCodec.AvcodecClose failed")
+	}
+}
+
 func (e *Encoder) addStats() {
 	// Add incoming rate
 	e.Stater().AddStat(astikit.StatMetadata{
@@ -187,8 +282,17 @@ func (e *Encoder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
 		defer e.d.wait()
 
+		// Make sure to end pending spans
+		defer e.spans.close()
+
 		// Make sure to flush the encoder
-		defer e.flush()
+		defer func() {
+			// Codec has never been opened
+			if e.lazyOpen != nil {
+				return
+			}
+			e.flush()
+		}()
 
 		// Make sure to stop the chan properly
 		defer e.c.Stop()
@@ -204,31 +308,75 @@ func (e *Encoder) flush() {
 
 // HandleFrame implements the FrameHandler interface
 func (e *Encoder) HandleFrame(p *FrameHandlerPayload) {
-	e.c.Add(func() {
+	// Trace
+	if p.traceCtx != nil {
+		e.spans.start(p.traceCtx, e, SpanNameEncode, p.Frame.Pts())
+	}
+
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be encoded
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, e); p == nil {
+		return
+	}
+	e.c.AddWithRelease(func() {
 		// Handle pause
 		defer e.HandlePause()
 
 		// Increment incoming rate
 		e.statIncomingRate.Add(1)
 
+		// Open lazily
+		if e.lazyOpen != nil {
+			if err := e.openLazily(); err != nil {
+				e.eh.Emit(astiencoder.EventError(e, fmt.Errorf("astilibav: opening encoder lazily failed: %w", err)))
+				e.Stop()
+				return
+			}
+		}
+
 		// Encode
 		e.encode(p)
-	})
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
 }
 
 func (e *Encoder) encode(p *FrameHandlerPayload) {
+	// Update bit rate
+	if b := atomic.SwapInt64(&e.pendingBitRate, 0); b > 0 {
+		e.ctxCodec.SetBitRate(b)
+	}
+
+	// Upload frame to hardware memory
+	f := p.Frame
+	if f != nil && e.hardwareFrames != nil {
+		// Get frame
+		hf := sharedFramePool.get()
+		defer sharedFramePool.put(hf)
+
+		// Upload
+		e.statWorkRatio.Begin()
+		uploaded, err := uploadHardwareFrame(hf, f, e.hardwareFrames)
+		e.statWorkRatio.End()
+		if err != nil {
+			e.eh.Emit(astiencoder.EventError(e, fmt.Errorf("astilibav: uploading hardware frame failed: %w", err)))
+			return
+		} else if uploaded {
+			f = hf
+		}
+	}
+
 	// Reset frame attributes
-	if p.Frame != nil {
+	if f != nil {
 		switch e.ctxCodec.CodecType() {
 		case avutil.AVMEDIA_TYPE_VIDEO:
-			p.Frame.SetKeyFrame(0)
-			p.Frame.SetPictType(avutil.AvPictureType(avutil.AV_PICTURE_TYPE_NONE))
+			f.SetKeyFrame(0)
+			f.SetPictType(avutil.AvPictureType(avutil.AV_PICTURE_TYPE_NONE))
 		}
 	}
 
 	// Send frame to encoder
 	e.statWorkRatio.Begin()
-	if ret := avcodec.AvcodecSendFrame(e.ctxCodec, p.Frame); ret < 0 {
+	if ret := avcodec.AvcodecSendFrame(e.ctxCodec, f); ret < 0 {
 		e.statWorkRatio.End()
 		emitAvError(e, e.eh, ret, "avcodec.AvcodecSendFrame failed")
 		return
@@ -277,11 +425,34 @@ func (e *Encoder) receivePkt(p *FrameHandlerPayload) (stop bool) {
 		pkt.SetDuration(avutil.AvRescaleQ(int64(1e9/f.ToDouble()), nanosecondRational, d.TimeBase()))
 	}
 
+	// Get trace
+	traceCtx, endSpan := e.spans.take(pkt.Pts())
+	defer endSpan()
+
 	// Rescale timestamps
 	pkt.AvPacketRescaleTs(d.TimeBase(), e.ctxCodec.TimeBase())
 
 	// Dispatch pkt
-	e.d.dispatch(pkt, newEncoderDescriptor(e.ctxCodec))
+	e.d.dispatchWithTrace(traceCtx, pkt, newEncoderDescriptor(e.ctxCodec))
+	return
+}
+
+func (e *Encoder) openLazily() (err error) {
+	// Open
+	open := e.lazyOpen
+	e.lazyOpen = nil
+	if err = open(); err != nil {
+		err = fmt.Errorf("astilibav: opening failed: %w", err)
+		return
+	}
+
+	// Update streams now that the codec parameters are final
+	for _, s := range e.streams {
+		if err = e.updateStream(s); err != nil {
+			err = fmt.Errorf("astilibav: updating stream %d failed: %w", s.Index(), err)
+			return
+		}
+	}
 	return
 }
 
@@ -290,6 +461,20 @@ func (e *Encoder) AddStream(ctxFormat *avformat.Context) (o *avformat.Stream, er
 	// Add stream
 	o = AddStream(ctxFormat)
 
+	// Update stream
+	if err = e.updateStream(o); err != nil {
+		err = fmt.Errorf("astilibav: updating stream failed: %w", err)
+		return
+	}
+
+	// Store stream so that it can be updated once the codec is opened lazily
+	if e.lazyOpen != nil {
+		e.streams = append(e.streams, o)
+	}
+	return
+}
+
+func (e *Encoder) updateStream(o *avformat.Stream) (err error) {
 	// Set codec parameters
 	if ret := avcodec.AvcodecParametersFromContext(o.CodecParameters(), e.ctxCodec); ret < 0 {
 		err = fmt.Errorf("astilibav: avcodec.AvcodecParametersFromContext from %+v to %+v failed: %w", e.ctxCodec, o.CodecParameters(), NewAvError(ret))
@@ -301,6 +486,40 @@ func (e *Encoder) AddStream(ctxFormat *avformat.Context) (o *avformat.Stream, er
 	return
 }
 
+// SetBitRate updates the bit rate of the encoder. It is applied before the next frame is encoded and only taken into
+// account by codecs supporting reconfiguration (e.g. libx264)
+func (e *Encoder) SetBitRate(bitRate int) {
+	atomic.StoreInt64(&e.pendingBitRate, int64(bitRate))
+}
+
+// EncoderReconfigureOptions represents the encoder options that can be changed while it's running
+type EncoderReconfigureOptions struct {
+	BitRate int `json:"bit_rate,omitempty"`
+}
+
+// Reconfigure implements the astiencoder.Reconfigurer interface
+func (e *Encoder) Reconfigure(opts interface{}) (err error) {
+	// Decode options
+	var o EncoderReconfigureOptions
+	if err = astiencoder.DecodeReconfigureOptions(opts, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Set bit rate
+	if o.BitRate > 0 {
+		e.SetBitRate(o.BitRate)
+	}
+	return
+}
+
+// AcceptsHardwareFrames implements the HardwareFrameHandler interface
+// Frames are encoded without being copied when they live in hardware memory of the same type as the encoder's
+// hardware frames
+func (e *Encoder) AcceptsHardwareFrames(_ astiencoder.Node, hardwarePixelFormat avutil.PixelFormat) bool {
+	return e.hardwareFrames != nil && e.hardwareFrames.PixelFormat() == hardwarePixelFormat
+}
+
 // FrameSize returns the encoder frame size
 func (e *Encoder) FrameSize() int {
 	return e.ctxCodec.FrameSize()
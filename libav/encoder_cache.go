@@ -0,0 +1,159 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec
+#include <libavcodec/avcodec.h>
+
+static int astilibavCodecSupportsEncoderFlush(AVCodec *c) {
+#ifdef AV_CODEC_CAP_ENCODER_FLUSH
+	return (c->capabilities & AV_CODEC_CAP_ENCODER_FLUSH) != 0;
+#else
+	return 0;
+#endif
+}
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/asticode/goav/avcodec"
+)
+
+const encoderCacheMaxIdleDefault = 4
+
+// EncoderCache represents a cache of opened encoder codec contexts
+// Workflows that are frequently restarted (e.g. short clips at scale) reuse the codec contexts of previous encoders
+// with the same context instead of paying the codec init cost (e.g. libx264 or NVENC) every time
+// Only codecs that can be flushed and reused (e.g. libx264 or NVENC) are cached. Once an encoder is closed, its codec
+// context is flushed, its bit rate is restored and it becomes available to the next encoder with the same context
+type EncoderCache struct {
+	idle       map[string][]*avcodec.Context
+	m          *sync.Mutex
+	o          EncoderCacheOptions
+	statHits   uint64
+	statMisses uint64
+}
+
+// EncoderCacheOptions represents encoder cache options
+type EncoderCacheOptions struct {
+	// Max number of idle codec contexts kept per context. Default is 4
+	MaxIdle int
+}
+
+// EncoderCacheStats represents encoder cache stats
+type EncoderCacheStats struct {
+	Hits   uint64
+	Idle   int
+	Misses uint64
+}
+
+// NewEncoderCache creates a new encoder cache
+func NewEncoderCache(o EncoderCacheOptions) *EncoderCache {
+	// Default options
+	if o.MaxIdle <= 0 {
+		o.MaxIdle = encoderCacheMaxIdleDefault
+	}
+
+	// Create cache
+	return &EncoderCache{
+		idle: make(map[string][]*avcodec.Context),
+		m:    &sync.Mutex{},
+		o:    o,
+	}
+}
+
+// Close closes and frees all idle codec contexts
+func (c *EncoderCache) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	for k, ctxCodecs := range c.idle {
+		for _, ctxCodec := range ctxCodecs {
+			ctxCodec.AvcodecClose()
+			avcodec.AvcodecFreeContext(ctxCodec)
+		}
+		delete(c.idle, k)
+	}
+	return nil
+}
+
+// Stats returns the cache stats
+func (c *EncoderCache) Stats() (s EncoderCacheStats) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	s.Hits = c.statHits
+	s.Misses = c.statMisses
+	for _, ctxCodecs := range c.idle {
+		s.Idle += len(ctxCodecs)
+	}
+	return
+}
+
+// get returns an idle codec context or nil if there's none
+func (c *EncoderCache) get(key string) *avcodec.Context {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// No idle context
+	ctxCodecs := c.idle[key]
+	if len(ctxCodecs) == 0 {
+		c.statMisses++
+		return nil
+	}
+
+	// Pop context
+	ctxCodec := ctxCodecs[len(ctxCodecs)-1]
+	if len(ctxCodecs) == 1 {
+		delete(c.idle, key)
+	} else {
+		c.idle[key] = ctxCodecs[:len(ctxCodecs)-1]
+	}
+	c.statHits++
+	return ctxCodec
+}
+
+// put resets the codec context and makes it available to the next encoder. It returns false if the max number of
+// idle codec contexts is reached, in which case the caller remains in charge of the codec context
+func (c *EncoderCache) put(key string, ctxCodec *avcodec.Context, bitRate int) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Max idle is reached
+	if len(c.idle[key]) >= c.o.MaxIdle {
+		return false
+	}
+
+	// Reset codec context
+	ctxCodec.AvcodecFlushBuffers()
+	ctxCodec.SetBitRate(int64(bitRate))
+
+	// Store context
+	c.idle[key] = append(c.idle[key], ctxCodec)
+	return true
+}
+
+func codecSupportsEncoderFlush(cdc *avcodec.Codec) bool {
+	return C.astilibavCodecSupportsEncoderFlush((*C.AVCodec)(unsafe.Pointer(cdc))) > 0
+}
+
+// encoderCacheKey returns the key of encoders sharing the same context
+func (ctx Context) encoderCacheKey() string {
+	// Dict
+	var dict string
+	if ctx.Dict != nil {
+		dict = fmt.Sprintf("%+v", *ctx.Dict)
+	}
+
+	// Thread count
+	threadCount := -1
+	if ctx.ThreadCount != nil {
+		threadCount = *ctx.ThreadCount
+	}
+
+	// Remove pointers
+	ctx.Dict = nil
+	ctx.HardwareFramesCtx = nil
+	ctx.ThreadCount = nil
+	return fmt.Sprintf("%+v|%s|%d", ctx, dict, threadCount)
+}
@@ -0,0 +1,37 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/goav/avcodec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoderCache(t *testing.T) {
+	// Key
+	tc := 2
+	ctx := Context{CodecName: "libx264", Dict: NewDefaultDict("preset=fast"), Height: 1080, ThreadCount: &tc, Width: 1920}
+	k := ctx.encoderCacheKey()
+	assert.Equal(t, k, ctx.encoderCacheKey())
+	ctx2 := ctx
+	ctx2.Dict = NewDefaultDict("preset=slow")
+	assert.NotEqual(t, k, ctx2.encoderCacheKey())
+	ctx2 = ctx
+	tc2 := 4
+	ctx2.ThreadCount = &tc2
+	assert.NotEqual(t, k, ctx2.encoderCacheKey())
+	ctx2 = ctx
+	ctx2.Width = 1280
+	assert.NotEqual(t, k, ctx2.encoderCacheKey())
+
+	// Get
+	c := NewEncoderCache(EncoderCacheOptions{MaxIdle: 1})
+	assert.Nil(t, c.get(k))
+	ctxCodec := &avcodec.Context{}
+	c.idle[k] = []*avcodec.Context{ctxCodec}
+	assert.False(t, c.put(k, &avcodec.Context{}, 0))
+	assert.Equal(t, EncoderCacheStats{Idle: 1, Misses: 1}, c.Stats())
+	assert.Equal(t, ctxCodec, c.get(k))
+	assert.Nil(t, c.get(k))
+	assert.Equal(t, EncoderCacheStats{Hits: 1, Misses: 2}, c.Stats())
+}
@@ -2,8 +2,55 @@ package astilibav
 
 // Event names
 const (
+	// Adaptive bitrate has stepped the bit rate of its encoder. Payload is an AdaptiveBitrateStepChange
+	AdaptiveBitrateStepped = "astilibav.adaptive.bitrate.stepped"
+	// Audio levels of an interval have been computed by the audio meter. Payload is an AudioLevels
+	AudioMeterLevelsReported = "astilibav.audio.meter.levels.reported"
+	// AV sync drift stats have been computed. Payload is an AVSyncDrift
+	AVSyncDriftReported = "astilibav.av.sync.drift.reported"
+	// A CMAF segment has been written by the CMAF segmenter. Payload is a MuxerFile whose URL is the media segment
+	CMAFSegmenterSegmentCompleted = "astilibav.cmaf.segmenter.segment.completed"
+	// Loads of the GPUs handled by the device manager have been computed. Payload is a []DeviceLoad
+	DeviceLoadsReported = "astilibav.device.loads.reported"
+	// Demuxer has reached the end of its input and started over. Payload is the number of loops so far
+	DemuxerLooped = "astilibav.demuxer.looped"
+	// Free space of a filesystem watched by the disk space monitor has dropped below the threshold. Payload is a DiskSpace
+	DiskSpaceLow = "astilibav.disk.space.low"
+	// Free space of a filesystem watched by the disk space monitor is back above the threshold. Payload is a DiskSpace
+	DiskSpaceRecovered = "astilibav.disk.space.recovered"
+	// Failover muxer has switched output. Payload is a FailoverMuxerSwitch
+	FailoverMuxerSwitched = "astilibav.failover.muxer.switched"
+	// Ratio of frames dropped or duplicated by a node has exceeded its threshold. Payload is a FrameDrops
+	FrameDropsThresholdExceeded = "astilibav.frame.drops.threshold.exceeded"
+	// Ratio of frames dropped or duplicated by a node is back below its threshold. Payload is a FrameDrops
+	FrameDropsThresholdRecovered = "astilibav.frame.drops.threshold.recovered"
+	// A hardware node couldn't be used and its software counterpart has been created instead. Payload is a
+	// HardwareFallback
+	HardwareFallbackTriggered = "astilibav.hardware.fallback.triggered"
+	// Load shedder has started dropping frames. Payload is a LoadShedding
+	LoadShedderSheddingStarted = "astilibav.load.shedder.shedding.started"
+	// Load shedder has stopped dropping frames. Payload is a LoadShedding
+	LoadShedderSheddingStopped = "astilibav.load.shedder.shedding.stopped"
+	// libav has logged a message. Payload is a LogMessage
+	LogMessageEmitted = "astilibav.log.message.emitted"
+	// A file has been completed by the muxer. Payload is a MuxerFile
+	MuxerFileCompleted = "astilibav.muxer.file.completed"
+	// Stats of the shared frame and packet pools have been computed. Payload is a PoolStats
+	PoolStatsReported = "astilibav.pool.stats.reported"
 	// First packet of new node has been received by the rate enforcer
 	RateEnforcerSwitchedIn = "astilibav.rate.enforcer.switched.in"
 	// First packet of new node has been dispatched by the rate enforcer
 	RateEnforcerSwitchedOut = "astilibav.rate.enforcer.switched.out"
+	// Recorder has started writing to a new output. Payload is a MuxerFile
+	RecorderRecordingStarted = "astilibav.recorder.recording.started"
+	// Recorder has stopped writing to its output. Payload is a MuxerFile
+	RecorderRecordingStopped = "astilibav.recorder.recording.stopped"
+	// RIST link statistics have been computed. Payload is a RISTLinkStats
+	RISTLinkStatsReported = "astilibav.rist.link.stats.reported"
+	// A SCTE-35 cue has been parsed or injected. Payload is a SCTE35Cue
+	SCTE35CueReceived = "astilibav.scte35.cue.received"
+	// Speech has ended. Payload is a VoiceActivity
+	VoiceActivityDetectorSpeechEnded = "astilibav.voice.activity.detector.speech.ended"
+	// Speech has started. Payload is a VoiceActivity
+	VoiceActivityDetectorSpeechStarted = "astilibav.voice.activity.detector.speech.started"
 )
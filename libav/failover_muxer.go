@@ -0,0 +1,365 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avformat"
+)
+
+var countFailoverMuxer uint64
+
+// Failover muxer output names
+const (
+	FailoverMuxerOutputBackup  = "backup"
+	FailoverMuxerOutputPrimary = "primary"
+)
+
+// FailoverMuxer represents an object capable of muxing packets into a primary output and of switching to a backup
+// output on write errors or congestion. While the backup is active, the primary is retried periodically and switched
+// back to on a key frame
+type FailoverMuxer struct {
+	*astiencoder.BaseNode
+	active            *failoverMuxerOutput
+	c                 *astiencoder.Queue
+	ctxFormatTemplate *avformat.Context
+	eh                *astiencoder.EventHandler
+	o                 FailoverMuxerOptions
+	outputs           map[string]*failoverMuxerOutput
+	retriedAt         time.Time
+	statIncomingRate  *astikit.CounterRateStat
+	statWorkRatio     *astikit.DurationPercentageStat
+}
+
+// FailoverMuxerOptions represents failover muxer options
+type FailoverMuxerOptions struct {
+	BackupURL string
+	// Number of consecutive congested writes after which the muxer switches output. Default is 25
+	CongestionCount int
+	// Writes lasting longer than this duration are considered congested. 0 disables congestion detection
+	CongestionThreshold time.Duration
+	Format              *avformat.OutputFormat
+	FormatName          string
+	Node                astiencoder.NodeOptions
+	PrimaryURL          string
+	Restamper           PktRestamper
+	// Period at which the primary output is retried while the backup output is active. Default is 10s
+	RetryPeriod time.Duration
+}
+
+// FailoverMuxerSwitch represents a failover muxer switch
+// It is the payload of the FailoverMuxerSwitched event
+type FailoverMuxerSwitch struct {
+	From   string
+	Reason string
+	To     string
+}
+
+type failoverMuxerOutput struct {
+	congested int
+	ctxAvIO   *avformat.AvIOContext
+	ctxFormat *avformat.Context
+	name      string
+	url       string
+}
+
+// NewFailoverMuxer creates a new failover muxer
+func NewFailoverMuxer(o FailoverMuxerOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (m *FailoverMuxer, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countFailoverMuxer, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("failover_muxer_%d", count), fmt.Sprintf("Failover Muxer #%d", count), fmt.Sprintf("Muxes to %s or %s", o.PrimaryURL, o.BackupURL), "failover muxer")
+
+	// Default options
+	if o.CongestionCount <= 0 {
+		o.CongestionCount = 25
+	}
+	if o.RetryPeriod <= 0 {
+		o.RetryPeriod = 10 * time.Second
+	}
+
+	// Create muxer
+	m = &FailoverMuxer{
+		c:  astiencoder.NewQueue(o.Node.Queue),
+		eh: eh,
+		o:  o,
+		outputs: map[string]*failoverMuxerOutput{
+			FailoverMuxerOutputBackup:  {name: FailoverMuxerOutputBackup, url: o.BackupURL},
+			FailoverMuxerOutputPrimary: {name: FailoverMuxerOutputPrimary, url: o.PrimaryURL},
+		},
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	m.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(m), eh)
+	m.addStats()
+
+	// Alloc template format context
+	// Streams are added to this context and cloned every time an output is opened
+	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
+	var ctxFormat *avformat.Context
+	if ret := avformat.AvformatAllocOutputContext2(&ctxFormat, o.Format, o.FormatName, o.PrimaryURL); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvformatAllocOutputContext2 on %+v failed: %w", o, NewAvError(ret))
+		return
+	}
+	m.ctxFormatTemplate = ctxFormat
+
+	// Make sure the template format ctx is properly closed
+	c.Add(func() error {
+		m.ctxFormatTemplate.AvformatFreeContext()
+		return nil
+	})
+	return
+}
+
+func (m *FailoverMuxer) addStats() {
+	// Add incoming rate
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, m.statIncomingRate)
+
+	// Add work ratio
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, m.statWorkRatio)
+
+	// Add chan stats
+	m.c.AddStats(m.Stater())
+}
+
+// CtxFormat returns the format ctx streams must be added to
+func (m *FailoverMuxer) CtxFormat() *avformat.Context {
+	return m.ctxFormatTemplate
+}
+
+// Start starts the muxer
+func (m *FailoverMuxer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	m.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure the active output is closed once everything is done
+		defer func() {
+			if m.active == nil {
+				return
+			}
+			if err := m.close(m.active, true); err != nil {
+				m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: closing %s output failed: %w", m.active.name, err)))
+			}
+			m.active = nil
+		}()
+
+		// Make sure to stop the chan properly
+		defer m.c.Stop()
+
+		// Start chan
+		m.c.Start(m.Context())
+	})
+}
+
+func (m *FailoverMuxer) open(o *failoverMuxerOutput) (err error) {
+	// No url
+	if o.url == "" {
+		err = errors.New("astilibav: no url")
+		return
+	}
+
+	// Open
+	if o.ctxFormat, o.ctxAvIO, err = openMuxerOutput(m.o.Format, m.o.FormatName, o.url); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", o.url, err)
+		return
+	}
+
+	// Clone streams
+	if err = cloneMuxerStreams(m.ctxFormatTemplate, o.ctxFormat); err != nil {
+		m.close(o, false)
+		err = fmt.Errorf("astilibav: cloning streams failed: %w", err)
+		return
+	}
+
+	// Write header
+	if ret := o.ctxFormat.AvformatWriteHeader(nil); ret < 0 {
+		m.close(o, false)
+		err = fmt.Errorf("astilibav: o.ctxFormat.AvformatWriteHeader on %s failed: %w", o.url, NewAvError(ret))
+		return
+	}
+	o.congested = 0
+	return
+}
+
+func (m *FailoverMuxer) close(o *failoverMuxerOutput, writeTrailer bool) (err error) {
+	// Make sure the output is closed
+	defer func() {
+		if errClose := closeMuxerOutput(o.ctxFormat, o.ctxAvIO); errClose != nil && err == nil {
+			err = fmt.Errorf("astilibav: closing output failed: %w", errClose)
+		}
+		o.ctxFormat, o.ctxAvIO = nil, nil
+	}()
+
+	// Write trailer
+	if writeTrailer {
+		if ret := o.ctxFormat.AvWriteTrailer(); ret < 0 {
+			err = fmt.Errorf("astilibav: o.ctxFormat.AvWriteTrailer on %s failed: %w", o.url, NewAvError(ret))
+			return
+		}
+	}
+	return
+}
+
+func (m *FailoverMuxer) other(o *failoverMuxerOutput) *failoverMuxerOutput {
+	if o.name == FailoverMuxerOutputPrimary {
+		return m.outputs[FailoverMuxerOutputBackup]
+	}
+	return m.outputs[FailoverMuxerOutputPrimary]
+}
+
+// switchTo closes the active output once the next output has been opened
+func (m *FailoverMuxer) switchTo(next *failoverMuxerOutput, reason string, writeTrailer bool) (err error) {
+	// Open next output
+	if err = m.open(next); err != nil {
+		err = fmt.Errorf("astilibav: opening %s output failed: %w", next.name, err)
+		return
+	}
+
+	// Close active output
+	var from string
+	if m.active != nil {
+		from = m.active.name
+		if err := m.close(m.active, writeTrailer); err != nil {
+			m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: closing %s output failed: %w", m.active.name, err)))
+		}
+	}
+
+	// Update active output
+	m.active = next
+
+	// Emit event
+	m.eh.Emit(astiencoder.Event{
+		Name: FailoverMuxerSwitched,
+		Payload: FailoverMuxerSwitch{
+			From:   from,
+			Reason: reason,
+			To:     next.name,
+		},
+		Target: m,
+	})
+	return
+}
+
+// failover switches to the other output and, if it can't be opened, closes the active output so that both outputs
+// are retried on the next packet
+func (m *FailoverMuxer) failover(reason string) {
+	next := m.other(m.active)
+	if err := m.switchTo(next, reason, false); err != nil {
+		m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: switching to %s output failed: %w", next.name, err)))
+		if err = m.close(m.active, false); err != nil {
+			m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: closing %s output failed: %w", m.active.name, err)))
+		}
+		m.active = nil
+	}
+}
+
+func (m *FailoverMuxer) hasVideo() bool {
+	for _, s := range m.ctxFormatTemplate.Streams() {
+		if s.CodecParameters().CodecType() == avcodec.AVMEDIA_TYPE_VIDEO {
+			return true
+		}
+	}
+	return false
+}
+
+// FailoverMuxerPktHandler is an object that can handle a pkt for the failover muxer
+type FailoverMuxerPktHandler struct {
+	*FailoverMuxer
+	idx int
+}
+
+// NewPktHandler creates a new pkt handler for a stream that has been added to the failover muxer's format ctx
+func (m *FailoverMuxer) NewPktHandler(o *avformat.Stream) *FailoverMuxerPktHandler {
+	return &FailoverMuxerPktHandler{
+		FailoverMuxer: m,
+		idx:           o.Index(),
+	}
+}
+
+// HandlePkt implements the PktHandler interface
+func (h *FailoverMuxerPktHandler) HandlePkt(p *PktHandlerPayload) {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be written
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, h); p == nil {
+		return
+	}
+	h.c.AddWithRelease(func() {
+		// Handle pause
+		defer h.HandlePause()
+
+		// Increment incoming rate
+		h.statIncomingRate.Add(1)
+
+		// No active output
+		if h.active == nil {
+			// Try the primary output first
+			if err := h.switchTo(h.outputs[FailoverMuxerOutputPrimary], "start", false); err != nil {
+				if errBackup := h.switchTo(h.outputs[FailoverMuxerOutputBackup], err.Error(), false); errBackup != nil {
+					h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: opening outputs failed: %s, %w", err, errBackup)))
+					return
+				}
+			}
+			h.retriedAt = time.Now()
+		}
+
+		// Switch back to the primary output on a key frame
+		if h.active.name == FailoverMuxerOutputBackup && time.Since(h.retriedAt) >= h.o.RetryPeriod && p.Pkt.Flags()&avcodec.AV_PKT_FLAG_KEY > 0 &&
+			(h.ctxFormatTemplate.Streams()[h.idx].CodecParameters().CodecType() == avcodec.AVMEDIA_TYPE_VIDEO || !h.hasVideo()) {
+			h.retriedAt = time.Now()
+			h.switchTo(h.outputs[FailoverMuxerOutputPrimary], "primary is back", true)
+		}
+
+		// Get stream
+		o := h.active.ctxFormat.Streams()[h.idx]
+
+		// Rescale timestamps
+		p.Pkt.AvPacketRescaleTs(p.Descriptor.TimeBase(), o.TimeBase())
+
+		// Set stream index
+		p.Pkt.SetStreamIndex(o.Index())
+
+		// Restamp
+		if h.o.Restamper != nil {
+			h.o.Restamper.Restamp(p.Pkt)
+		}
+
+		// Write frame
+		h.statWorkRatio.Begin()
+		start := time.Now()
+		ret := h.active.ctxFormat.AvInterleavedWriteFrame((*avformat.Packet)(unsafe.Pointer(p.Pkt)))
+		d := time.Since(start)
+		h.statWorkRatio.End()
+
+		// Write has failed
+		if ret < 0 {
+			emitAvError(h, h.eh, ret, "h.active.ctxFormat.AvInterleavedWriteFrame on %s output failed", h.active.name)
+			h.failover(fmt.Sprintf("write error: %s", NewAvError(ret)))
+			return
+		}
+
+		// Check congestion
+		if h.o.CongestionThreshold > 0 {
+			if d > h.o.CongestionThreshold {
+				h.active.congested++
+			} else {
+				h.active.congested = 0
+			}
+			if h.active.congested >= h.o.CongestionCount {
+				h.failover("congestion")
+			}
+		}
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
+}
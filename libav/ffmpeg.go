@@ -0,0 +1,696 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avformat"
+)
+
+// Media types of streams
+const (
+	MediaTypeAttachment = "attachment"
+	MediaTypeAudio      = "audio"
+	MediaTypeData       = "data"
+	MediaTypeSubtitle   = "subtitle"
+	MediaTypeVideo      = "video"
+)
+
+// FFmpegImportOptions represents ffmpeg import options
+type FFmpegImportOptions struct {
+	// Name of the workflow
+	Name string
+	// Returns the media types of the streams of an input. Default opens the input
+	Probe func(url string) ([]string, error)
+}
+
+// ffmpegBoolOptions are the options without value
+var ffmpegBoolOptions = map[string]bool{
+	"an":          true,
+	"dn":          true,
+	"hide_banner": true,
+	"n":           true,
+	"nostats":     true,
+	"nostdin":     true,
+	"re":          true,
+	"sn":          true,
+	"vn":          true,
+	"y":           true,
+}
+
+// ffmpegAliases are the options having a shorter or canonical name
+var ffmpegAliases = map[string]string{
+	"acodec":   "c:a",
+	"b":        "b:v",
+	"codec":    "c",
+	"codec:a":  "c:a",
+	"codec:v":  "c:v",
+	"filter:a": "af",
+	"filter:v": "vf",
+	"vcodec":   "c:v",
+}
+
+// ffmpegGlobalOptions are the options having no effect on the workflow
+var ffmpegGlobalOptions = map[string]bool{
+	"hide_banner": true,
+	"loglevel":    true,
+	"n":           true,
+	"nostats":     true,
+	"nostdin":     true,
+	"v":           true,
+	"y":           true,
+}
+
+var ffmpegInputOptions = map[string]bool{
+	"f":           true,
+	"re":          true,
+	"stream_loop": true,
+}
+
+var ffmpegOutputOptions = map[string]bool{
+	"ac":        true,
+	"af":        true,
+	"an":        true,
+	"ar":        true,
+	"b:a":       true,
+	"b:v":       true,
+	"c":         true,
+	"c:a":       true,
+	"c:v":       true,
+	"crf":       true,
+	"dn":        true,
+	"f":         true,
+	"g":         true,
+	"map":       true,
+	"pix_fmt":   true,
+	"preset":    true,
+	"profile:v": true,
+	"r":         true,
+	"s":         true,
+	"sn":        true,
+	"tune":      true,
+	"vf":        true,
+	"vn":        true,
+}
+
+// ffmpegGlobalHeaderFormats are the output formats needing encoders to put their headers in the stream's extradata
+var ffmpegGlobalHeaderFormats = map[string]bool{
+	"3gp":      true,
+	"flv":      true,
+	"ipod":     true,
+	"ismv":     true,
+	"matroska": true,
+	"mov":      true,
+	"mp4":      true,
+}
+
+var ffmpegExtensionFormats = map[string]string{
+	".3gp":  "3gp",
+	".flv":  "flv",
+	".ismv": "ismv",
+	".m4a":  "ipod",
+	".mkv":  "matroska",
+	".mov":  "mov",
+	".mp4":  "mp4",
+}
+
+type ffmpegFile struct {
+	maps    []string
+	options map[string]string
+	url     string
+}
+
+type ffmpegStream struct {
+	input     int
+	index     int
+	mediaType string
+}
+
+// ImportFFmpegCommand converts ffmpeg arguments, with or without the leading "ffmpeg", into an equivalent workflow
+// definition using the libav node types
+// The supported subset is:
+//   - inputs: -i, -f, -re and -stream_loop
+//   - outputs: -map (without negative maps), -c, -c:v, -c:a, -b:v, -b:a, -vf, -af, -s, -r, -g, -pix_fmt, -ar, -ac,
+//     -preset, -tune, -crf, -profile:v, -f, -vn, -an, -sn and -dn. Aliases such as -vcodec or -filter:v are supported
+//     as well
+//
+// Unlike ffmpeg, outputs without -map get the first video and the first audio streams of all inputs, and streams
+// are encoded with libx264 or aac when no codec is provided. -s, -r, -pix_fmt, -ar and -ac add the corresponding
+// filters. Other options, filter graphs with several inputs and outputs as well as ffmpeg's own defaults for
+// unsupported options are not handled
+func ImportFFmpegCommand(args []string, o FFmpegImportOptions) (d astiencoder.WorkflowDefinition, err error) {
+	// Default options
+	if o.Probe == nil {
+		o.Probe = probeFFmpegInput
+	}
+
+	// Parse
+	var ins, outs []ffmpegFile
+	if ins, outs, err = parseFFmpegArgs(args); err != nil {
+		return
+	}
+
+	// Create definition
+	d = astiencoder.WorkflowDefinition{
+		Name:    o.Name,
+		Version: astiencoder.DefinitionVersion,
+	}
+
+	// Loop through inputs
+	for idx, in := range ins {
+		// Get options
+		do := DemuxerDefinitionOptions{
+			EmulateRate: in.options["re"] != "",
+			FormatName:  in.options["f"],
+			URL:         in.url,
+		}
+		if v, ok := in.options["stream_loop"]; ok {
+			var n int
+			if n, err = strconv.Atoi(v); err != nil {
+				err = fmt.Errorf("astilibav: invalid -stream_loop %s: %w", v, err)
+				return
+			}
+			if n != 0 {
+				do.Loop = true
+			}
+			if n > 0 {
+				do.LoopCount = n + 1
+			}
+		}
+
+		// Add node
+		if err = addFFmpegNode(&d, fmt.Sprintf("demuxer_%d", idx), NodeTypeDemuxer, do); err != nil {
+			return
+		}
+	}
+
+	// Loop through outputs
+	types := make(map[int][]string)
+	decoders := make(map[string]bool)
+	for oIdx, out := range outs {
+		// Get streams
+		var ss []ffmpegStream
+		if ss, err = ffmpegOutputStreams(ins, out, types, o.Probe); err != nil {
+			err = fmt.Errorf("astilibav: getting streams of output %s failed: %w", out.url, err)
+			return
+		}
+		if len(ss) == 0 {
+			err = fmt.Errorf("astilibav: output %s has no streams", out.url)
+			return
+		}
+
+		// Add muxer
+		muxer := fmt.Sprintf("muxer_%d", oIdx)
+		if err = addFFmpegNode(&d, muxer, NodeTypeMuxer, MuxerDefinitionOptions{
+			FormatName: out.options["f"],
+			URL:        out.url,
+		}); err != nil {
+			return
+		}
+
+		// Loop through streams
+		for sIdx, s := range ss {
+			if err = addFFmpegStream(&d, out, s, muxer, fmt.Sprintf("%d_%d", oIdx, sIdx), decoders); err != nil {
+				err = fmt.Errorf("astilibav: adding stream #%d:%d to output %s failed: %w", s.input, s.index, out.url, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+func parseFFmpegArgs(args []string) (ins, outs []ffmpegFile, err error) {
+	// Strip program name
+	if len(args) > 0 && filepath.Base(args[0]) == "ffmpeg" {
+		args = args[1:]
+	}
+
+	// Loop through args
+	f := ffmpegFile{options: make(map[string]string)}
+	for idx := 0; idx < len(args); idx++ {
+		// Output
+		a := args[idx]
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			f.url = a
+			outs = append(outs, f)
+			f = ffmpegFile{options: make(map[string]string)}
+			continue
+		}
+
+		// Get name
+		name := a[1:]
+		if v, ok := ffmpegAliases[name]; ok {
+			name = v
+		}
+
+		// Get value
+		v := "1"
+		if !ffmpegBoolOptions[name] {
+			if idx+1 >= len(args) {
+				err = fmt.Errorf("astilibav: option -%s needs a value", name)
+				return
+			}
+			idx++
+			v = args[idx]
+		}
+
+		// Switch on name
+		switch {
+		case name == "i":
+			f.url = v
+			for k := range f.options {
+				if !ffmpegInputOptions[k] {
+					err = fmt.Errorf("astilibav: option -%s is not supported for inputs", k)
+					return
+				}
+			}
+			if len(f.maps) > 0 {
+				err = errors.New("astilibav: option -map is not supported for inputs")
+				return
+			}
+			ins = append(ins, f)
+			f = ffmpegFile{options: make(map[string]string)}
+		case ffmpegGlobalOptions[name]:
+		case name == "map":
+			f.maps = append(f.maps, v)
+		case ffmpegInputOptions[name] || ffmpegOutputOptions[name]:
+			f.options[name] = v
+		default:
+			err = fmt.Errorf("astilibav: option -%s is not supported", name)
+			return
+		}
+	}
+
+	// Trailing options
+	if len(f.options) > 0 || len(f.maps) > 0 {
+		err = errors.New("astilibav: trailing options found after the last output")
+		return
+	}
+
+	// Check outputs
+	if len(ins) == 0 || len(outs) == 0 {
+		err = errors.New("astilibav: at least 1 input and 1 output are needed")
+		return
+	}
+	for _, out := range outs {
+		for k := range out.options {
+			if !ffmpegOutputOptions[k] {
+				err = fmt.Errorf("astilibav: option -%s is not supported for outputs", k)
+				return
+			}
+		}
+	}
+	return
+}
+
+func ffmpegOutputStreams(ins []ffmpegFile, out ffmpegFile, types map[int][]string, probe func(url string) ([]string, error)) (ss []ffmpegStream, err error) {
+	// Create types getter
+	getTypes := func(input int) ([]string, error) {
+		if input < 0 || input >= len(ins) {
+			return nil, fmt.Errorf("astilibav: input %d doesn't exist", input)
+		}
+		if _, ok := types[input]; !ok {
+			ts, err := probe(ins[input].url)
+			if err != nil {
+				return nil, fmt.Errorf("astilibav: probing %s failed: %w", ins[input].url, err)
+			}
+			types[input] = ts
+		}
+		return types[input], nil
+	}
+
+	// Get disabled media types
+	disabled := make(map[string]bool)
+	for k, t := range map[string]string{"an": MediaTypeAudio, "dn": MediaTypeData, "sn": MediaTypeSubtitle, "vn": MediaTypeVideo} {
+		if out.options[k] != "" {
+			disabled[t] = true
+		}
+	}
+
+	// No maps
+	if len(out.maps) == 0 {
+		for _, t := range []string{MediaTypeVideo, MediaTypeAudio} {
+			if disabled[t] {
+				continue
+			}
+			for i := range ins {
+				ts, err := getTypes(i)
+				if err != nil {
+					return nil, err
+				}
+				if idx := indexOfString(ts, t); idx >= 0 {
+					ss = append(ss, ffmpegStream{input: i, index: idx, mediaType: t})
+					break
+				}
+			}
+		}
+		return
+	}
+
+	// Loop through maps
+	for _, m := range out.maps {
+		// Parse
+		optional := strings.HasSuffix(m, "?")
+		m = strings.TrimSuffix(m, "?")
+		if strings.HasPrefix(m, "-") {
+			return nil, fmt.Errorf("astilibav: negative map %s is not supported", m)
+		}
+		ps := strings.Split(m, ":")
+		var input int
+		if input, err = strconv.Atoi(ps[0]); err != nil {
+			return nil, fmt.Errorf("astilibav: invalid map %s", m)
+		}
+
+		// Get types
+		var ts []string
+		if ts, err = getTypes(input); err != nil {
+			return
+		}
+
+		// Get media type and index
+		t, idx := "", -1
+		switch len(ps) {
+		case 1:
+		case 2:
+			if idx, err = strconv.Atoi(ps[1]); err != nil {
+				t, idx, err = ffmpegMediaType(ps[1]), -1, nil
+			}
+		case 3:
+			t = ffmpegMediaType(ps[1])
+			if idx, err = strconv.Atoi(ps[2]); err != nil {
+				return nil, fmt.Errorf("astilibav: invalid map %s", m)
+			}
+		default:
+			return nil, fmt.Errorf("astilibav: invalid map %s", m)
+		}
+		if len(ps) > 1 && idx < 0 && t == "" {
+			return nil, fmt.Errorf("astilibav: invalid map %s", m)
+		}
+
+		// Loop through streams
+		var count, found int
+		for i, st := range ts {
+			// Filter on media type
+			if t != "" && st != t {
+				continue
+			}
+
+			// Filter on index, which is relative to the media type if any
+			k := i
+			if t != "" {
+				k = count
+				count++
+			}
+			if idx >= 0 && k != idx {
+				continue
+			}
+
+			// Append
+			found++
+			if !disabled[st] {
+				ss = append(ss, ffmpegStream{input: input, index: i, mediaType: st})
+			}
+		}
+		if found == 0 && !optional {
+			return nil, fmt.Errorf("astilibav: map %s matches no streams", m)
+		}
+	}
+	return
+}
+
+func ffmpegMediaType(s string) string {
+	switch s {
+	case "a":
+		return MediaTypeAudio
+	case "d":
+		return MediaTypeData
+	case "s":
+		return MediaTypeSubtitle
+	case "t":
+		return MediaTypeAttachment
+	case "v", "V":
+		return MediaTypeVideo
+	}
+	return ""
+}
+
+func addFFmpegStream(d *astiencoder.WorkflowDefinition, out ffmpegFile, s ffmpegStream, muxer, suffix string, decoders map[string]bool) (err error) {
+	// Get options
+	var letter string
+	switch s.mediaType {
+	case MediaTypeAudio:
+		letter = "a"
+	case MediaTypeVideo:
+		letter = "v"
+	}
+	codec, ok := out.options["c:"+letter]
+	if !ok {
+		codec = out.options["c"]
+	}
+
+	// Copy
+	demuxer := fmt.Sprintf("demuxer_%d", s.input)
+	if codec == "copy" {
+		for _, k := range []string{"ac", "af", "ar", "pix_fmt", "r", "s", "vf"} {
+			if _, ok := out.options[k]; ok && ffmpegFilterOptionMediaTypes[k] == letter {
+				return fmt.Errorf("astilibav: option -%s can't be used with stream copy", k)
+			}
+		}
+		addFFmpegConnection(d, demuxer, muxer, map[string]interface{}{"stream": float64(s.index)})
+		return
+	}
+
+	// Only audio and video can be encoded
+	if letter == "" {
+		return fmt.Errorf("astilibav: %s streams can only be copied", s.mediaType)
+	}
+
+	// Default codec
+	if codec == "" {
+		if letter == "v" {
+			codec = "libx264"
+		} else {
+			codec = "aac"
+		}
+	}
+
+	// Add decoder
+	parent := fmt.Sprintf("decoder_%d_%d", s.input, s.index)
+	if !decoders[parent] {
+		if err = addFFmpegNode(d, parent, NodeTypeDecoder, DecoderDefinitionOptions{}); err != nil {
+			return
+		}
+		addFFmpegConnection(d, demuxer, parent, map[string]interface{}{"stream": float64(s.index)})
+		decoders[parent] = true
+	}
+
+	// Get filters
+	var fs []string
+	var fo Preset
+	if letter == "v" {
+		if v := out.options["vf"]; v != "" {
+			fs = append(fs, v)
+		}
+		if v := out.options["s"]; v != "" {
+			ps := strings.Split(v, "x")
+			if len(ps) != 2 {
+				return fmt.Errorf("astilibav: invalid -s %s", v)
+			}
+			if fo.Width, err = strconv.Atoi(ps[0]); err != nil {
+				return fmt.Errorf("astilibav: invalid -s %s: %w", v, err)
+			}
+			if fo.Height, err = strconv.Atoi(ps[1]); err != nil {
+				return fmt.Errorf("astilibav: invalid -s %s: %w", v, err)
+			}
+			fs = append(fs, fmt.Sprintf("scale=%d:%d", fo.Width, fo.Height))
+		}
+		if v := out.options["r"]; v != "" {
+			fo.FrameRate = v
+			fs = append(fs, "fps="+v)
+		}
+		if v := out.options["pix_fmt"]; v != "" {
+			fo.PixelFormat = v
+			fs = append(fs, "format="+v)
+		}
+	} else {
+		if v := out.options["af"]; v != "" {
+			fs = append(fs, v)
+		}
+		if v := out.options["ar"]; v != "" {
+			if fo.SampleRate, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("astilibav: invalid -ar %s: %w", v, err)
+			}
+			fs = append(fs, "aresample="+v)
+		}
+		if v := out.options["ac"]; v != "" {
+			switch v {
+			case "1":
+				fo.ChannelLayout = "mono"
+			case "2":
+				fo.ChannelLayout = "stereo"
+			case "6":
+				fo.ChannelLayout = "5.1"
+			default:
+				return fmt.Errorf("astilibav: -ac %s is not supported", v)
+			}
+			fs = append(fs, "aformat=channel_layouts="+fo.ChannelLayout)
+		}
+	}
+
+	// Add filterer
+	if len(fs) > 0 {
+		filterer := "filterer_" + suffix
+		if err = addFFmpegNode(d, filterer, NodeTypeFilterer, FiltererDefinitionOptions{
+			Content: strings.Join(fs, ","),
+			Output:  fo,
+		}); err != nil {
+			return
+		}
+		addFFmpegConnection(d, parent, filterer, nil)
+		parent = filterer
+	}
+
+	// Get encoder options
+	eo := EncoderDefinitionOptions{Preset: Preset{CodecName: codec}}
+	if v := out.options["b:"+letter]; v != "" {
+		if eo.BitRate, err = parseFFmpegBitRate(v); err != nil {
+			return fmt.Errorf("astilibav: invalid -b:%s %s: %w", letter, v, err)
+		}
+	}
+	if letter == "v" {
+		if v := out.options["g"]; v != "" {
+			if eo.GopSize, err = strconv.Atoi(v); err != nil {
+				return fmt.Errorf("astilibav: invalid -g %s: %w", v, err)
+			}
+		}
+		var ps []string
+		for _, k := range []string{"crf", "preset", "profile:v", "tune"} {
+			if v := out.options[k]; v != "" {
+				ps = append(ps, strings.TrimSuffix(k, ":v")+"="+v)
+			}
+		}
+		eo.Dict = strings.Join(ps, ",")
+	}
+
+	// Get global header
+	format := out.options["f"]
+	if format == "" {
+		format = ffmpegExtensionFormats[strings.ToLower(filepath.Ext(out.url))]
+	}
+	eo.GlobalHeader = ffmpegGlobalHeaderFormats[format]
+
+	// Add encoder
+	encoder := "encoder_" + suffix
+	if err = addFFmpegNode(d, encoder, NodeTypeEncoder, eo); err != nil {
+		return
+	}
+	addFFmpegConnection(d, parent, encoder, nil)
+	addFFmpegConnection(d, encoder, muxer, nil)
+	return
+}
+
+// ffmpegFilterOptionMediaTypes indicates the media types the output options adding filters apply to
+var ffmpegFilterOptionMediaTypes = map[string]string{
+	"ac":      "a",
+	"af":      "a",
+	"ar":      "a",
+	"pix_fmt": "v",
+	"r":       "v",
+	"s":       "v",
+	"vf":      "v",
+}
+
+func addFFmpegNode(d *astiencoder.WorkflowDefinition, name, typ string, options interface{}) (err error) {
+	// Encode options
+	var o map[string]interface{}
+	if o, err = astiencoder.EncodeDefinitionOptions(options); err != nil {
+		err = fmt.Errorf("astilibav: encoding options of %s failed: %w", name, err)
+		return
+	}
+
+	// Append
+	d.Nodes = append(d.Nodes, astiencoder.NodeDefinition{
+		Name:    name,
+		Options: o,
+		Type:    typ,
+	})
+	return
+}
+
+func addFFmpegConnection(d *astiencoder.WorkflowDefinition, from, to string, options map[string]interface{}) {
+	d.Connections = append(d.Connections, astiencoder.ConnectionDefinition{
+		From:    from,
+		Options: options,
+		To:      to,
+	})
+}
+
+func parseFFmpegBitRate(s string) (int, error) {
+	// Get multiplier
+	m := 1.0
+	switch strings.ToLower(s[len(s)-1:]) {
+	case "k":
+		m = 1e3
+	case "m":
+		m = 1e6
+	case "g":
+		m = 1e9
+	}
+	if m > 1 {
+		s = s[:len(s)-1]
+	}
+
+	// Parse
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("astilibav: parsing float failed: %w", err)
+	}
+	return int(f * m), nil
+}
+
+func indexOfString(ss []string, s string) int {
+	for idx, v := range ss {
+		if v == s {
+			return idx
+		}
+	}
+	return -1
+}
+
+func probeFFmpegInput(url string) (ts []string, err error) {
+	// Open input
+	var ctxFormat *avformat.Context
+	if ret := avformat.AvformatOpenInput(&ctxFormat, url, nil, nil); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvformatOpenInput on %s failed: %w", url, NewAvError(ret))
+		return
+	}
+	defer avformat.AvformatCloseInput(ctxFormat)
+
+	// Find stream info
+	if ret := ctxFormat.AvformatFindStreamInfo(nil); ret < 0 {
+		err = fmt.Errorf("astilibav: ctxFormat.AvformatFindStreamInfo on %s failed: %w", url, NewAvError(ret))
+		return
+	}
+
+	// Loop through streams
+	for _, s := range ctxFormat.Streams() {
+		switch s.CodecParameters().CodecType() {
+		case avcodec.AVMEDIA_TYPE_ATTACHMENT:
+			ts = append(ts, MediaTypeAttachment)
+		case avcodec.AVMEDIA_TYPE_AUDIO:
+			ts = append(ts, MediaTypeAudio)
+		case avcodec.AVMEDIA_TYPE_SUBTITLE:
+			ts = append(ts, MediaTypeSubtitle)
+		case avcodec.AVMEDIA_TYPE_VIDEO:
+			ts = append(ts, MediaTypeVideo)
+		default:
+			ts = append(ts, MediaTypeData)
+		}
+	}
+	return
+}
@@ -0,0 +1,119 @@
+package astilibav
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+func ffmpegDictOptions(d *Dict) (os []astiencoder.FFmpegOption) {
+	for _, p := range d.pairs() {
+		os = append(os, astiencoder.FFmpegOption{Name: p[0], Value: p[1]})
+	}
+	return
+}
+
+// FFmpegNode implements the astiencoder.FFmpegDescriber interface
+func (d *Demuxer) FFmpegNode() (n astiencoder.FFmpegNode, err error) {
+	// Get options
+	n.Kind = astiencoder.FFmpegNodeKindInput
+	n.URL = d.definition.URL
+	if d.definition.EmulateRate {
+		n.Options = append(n.Options, astiencoder.FFmpegOption{Name: "re"})
+	}
+	if d.definition.Loop {
+		loop := -1
+		if d.definition.LoopCount > 0 {
+			loop = d.definition.LoopCount - 1
+		}
+		n.Options = append(n.Options, astiencoder.FFmpegOption{Name: "stream_loop", Value: strconv.Itoa(loop)})
+	}
+	if d.definition.FormatName != "" {
+		n.Options = append(n.Options, astiencoder.FFmpegOption{Name: "f", Value: d.definition.FormatName})
+	}
+	n.Options = append(n.Options, ffmpegDictOptions(NewDefaultDict(d.definition.Dict))...)
+
+	// Get streams
+	d.d.m.Lock()
+	defer d.d.m.Unlock()
+	n.Streams = make(map[string][]int)
+	for _, h := range d.d.hs {
+		if c, ok := h.(*pktCond); ok {
+			n.Streams[c.PktHandler.Metadata().Name] = append(n.Streams[c.PktHandler.Metadata().Name], c.i.Index())
+		} else {
+			for _, s := range d.ctxFormat.Streams() {
+				n.Streams[h.Metadata().Name] = append(n.Streams[h.Metadata().Name], s.Index())
+			}
+		}
+	}
+	for _, is := range n.Streams {
+		sort.Ints(is)
+	}
+	return
+}
+
+// FFmpegNode implements the astiencoder.FFmpegDescriber interface
+func (d *Decoder) FFmpegNode() (astiencoder.FFmpegNode, error) {
+	return astiencoder.FFmpegNode{Kind: astiencoder.FFmpegNodeKindDecoder}, nil
+}
+
+// FFmpegNode implements the astiencoder.FFmpegDescriber interface
+// Only filterers created from a definition can describe it since their content is unknown otherwise
+func (f *Filterer) FFmpegNode() (n astiencoder.FFmpegNode, err error) {
+	if f.definition == nil {
+		err = errors.New("astilibav: filterer has not been created from a definition")
+		return
+	}
+	n.Filter = f.definition.Content
+	n.Kind = astiencoder.FFmpegNodeKindFilter
+	return
+}
+
+// FFmpegNode implements the astiencoder.FFmpegDescriber interface
+func (e *Encoder) FFmpegNode() (n astiencoder.FFmpegNode, err error) {
+	// Get options
+	n.Kind = astiencoder.FFmpegNodeKindEncoder
+	o := e.definition
+	for _, v := range []struct {
+		name  string
+		value string
+	}{
+		{name: "c", value: o.CodecName},
+		{name: "b", value: ffmpegInt(o.BitRate)},
+		{name: "g", value: ffmpegInt(o.GopSize)},
+		{name: "r", value: o.FrameRate},
+		{name: "pix_fmt", value: o.PixelFormat},
+		{name: "ar", value: ffmpegInt(o.SampleRate)},
+		{name: "sample_fmt", value: o.SampleFmt},
+		{name: "channel_layout", value: o.ChannelLayout},
+	} {
+		if v.value != "" {
+			n.Options = append(n.Options, astiencoder.FFmpegOption{Name: v.name, Value: v.value})
+		}
+	}
+	if o.Width > 0 && o.Height > 0 {
+		n.Options = append(n.Options, astiencoder.FFmpegOption{Name: "s", Value: strconv.Itoa(o.Width) + "x" + strconv.Itoa(o.Height)})
+	}
+	n.Options = append(n.Options, ffmpegDictOptions(NewDefaultDict(o.Dict))...)
+	return
+}
+
+// FFmpegNode implements the astiencoder.FFmpegDescriber interface
+func (m *Muxer) FFmpegNode() (n astiencoder.FFmpegNode, err error) {
+	n.Kind = astiencoder.FFmpegNodeKindOutput
+	n.URL = m.url()
+	if m.options.FormatName != "" {
+		n.Options = append(n.Options, astiencoder.FFmpegOption{Name: "f", Value: m.options.FormatName})
+	}
+	n.Options = append(n.Options, ffmpegDictOptions(m.options.Dict)...)
+	return
+}
+
+func ffmpegInt(i int) string {
+	if i <= 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
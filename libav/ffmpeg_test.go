@@ -0,0 +1,85 @@
+package astilibav
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportFFmpegCommand(t *testing.T) {
+	// Create options
+	var probed []string
+	o := FFmpegImportOptions{
+		Name: "w",
+		Probe: func(url string) ([]string, error) {
+			probed = append(probed, url)
+			switch url {
+			case "in.mp4":
+				return []string{MediaTypeVideo, MediaTypeAudio, MediaTypeAudio, MediaTypeSubtitle}, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+
+	// Copy
+	d, err := ImportFFmpegCommand([]string{"ffmpeg", "-y", "-re", "-stream_loop", "-1", "-i", "in.mp4", "-c", "copy", "-map", "0:a:1", "-map", "0:s?", "-f", "mpegts", "out.ts"}, o)
+	assert.NoError(t, err)
+	assert.Equal(t, astiencoder.WorkflowDefinition{
+		Connections: []astiencoder.ConnectionDefinition{
+			{From: "demuxer_0", Options: map[string]interface{}{"stream": 2.0}, To: "muxer_0"},
+			{From: "demuxer_0", Options: map[string]interface{}{"stream": 3.0}, To: "muxer_0"},
+		},
+		Name: "w",
+		Nodes: []astiencoder.NodeDefinition{
+			{Name: "demuxer_0", Options: map[string]interface{}{"emulate_rate": true, "loop": true, "url": "in.mp4"}, Type: NodeTypeDemuxer},
+			{Name: "muxer_0", Options: map[string]interface{}{"format_name": "mpegts", "url": "out.ts"}, Type: NodeTypeMuxer},
+		},
+		Version: astiencoder.DefinitionVersion,
+	}, d)
+
+	// Encode
+	probed = []string{}
+	d, err = ImportFFmpegCommand([]string{"-i", "in.mp4", "-vcodec", "libx264", "-b:v", "2.5M", "-preset", "veryfast", "-vf", "yadif", "-s", "1280x720", "-c:a", "copy", "out.mp4", "-map", "0:0", "-an", "-b", "500k", "out.flv"}, o)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"in.mp4"}, probed)
+	assert.Equal(t, []astiencoder.NodeDefinition{
+		{Name: "demuxer_0", Options: map[string]interface{}{"url": "in.mp4"}, Type: NodeTypeDemuxer},
+		{Name: "muxer_0", Options: map[string]interface{}{"url": "out.mp4"}, Type: NodeTypeMuxer},
+		{Name: "decoder_0_0", Options: map[string]interface{}{}, Type: NodeTypeDecoder},
+		{Name: "filterer_0_0", Options: map[string]interface{}{"content": "yadif,scale=1280:720", "output": map[string]interface{}{"height": 720.0, "width": 1280.0}}, Type: NodeTypeFilterer},
+		{Name: "encoder_0_0", Options: map[string]interface{}{"bit_rate": 2.5e6, "codec_name": "libx264", "dict": "preset=veryfast", "global_header": true}, Type: NodeTypeEncoder},
+		{Name: "muxer_1", Options: map[string]interface{}{"url": "out.flv"}, Type: NodeTypeMuxer},
+		{Name: "encoder_1_0", Options: map[string]interface{}{"bit_rate": 5e5, "codec_name": "libx264", "global_header": true}, Type: NodeTypeEncoder},
+	}, d.Nodes)
+	assert.Equal(t, []astiencoder.ConnectionDefinition{
+		{From: "demuxer_0", Options: map[string]interface{}{"stream": 0.0}, To: "decoder_0_0"},
+		{From: "decoder_0_0", To: "filterer_0_0"},
+		{From: "filterer_0_0", To: "encoder_0_0"},
+		{From: "encoder_0_0", To: "muxer_0"},
+		{From: "demuxer_0", Options: map[string]interface{}{"stream": 1.0}, To: "muxer_0"},
+		{From: "decoder_0_0", To: "encoder_1_0"},
+		{From: "encoder_1_0", To: "muxer_1"},
+	}, d.Connections)
+
+	// Errors
+	for _, v := range []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"-i", "in.mp4"}, err: "astilibav: at least 1 input and 1 output are needed"},
+		{args: []string{"-i", "in.mp4", "-c:v", "copy", "out.mp4", "-an"}, err: "astilibav: trailing options found after the last output"},
+		{args: []string{"-i", "in.mp4", "-ss", "10", "out.mp4"}, err: "astilibav: option -ss is not supported"},
+		{args: []string{"-c", "copy", "-i", "in.mp4", "out.mp4"}, err: "astilibav: option -c is not supported for inputs"},
+		{args: []string{"-i", "in.mp4", "-re", "out.mp4"}, err: "astilibav: option -re is not supported for outputs"},
+		{args: []string{"-i", "in.mp4", "-map", "0:d", "out.mp4"}, err: "astilibav: getting streams of output out.mp4 failed: astilibav: map 0:d matches no streams"},
+		{args: []string{"-i", "in.mp4", "-map", "1", "out.mp4"}, err: "astilibav: getting streams of output out.mp4 failed: astilibav: input 1 doesn't exist"},
+		{args: []string{"-i", "in.mp4", "-c", "copy", "-vf", "yadif", "out.mp4"}, err: "astilibav: adding stream #0:0 to output out.mp4 failed: astilibav: option -vf can't be used with stream copy"},
+		{args: []string{"-i", "in.mp4", "-map", "0:s", "out.mp4"}, err: "astilibav: adding stream #0:3 to output out.mp4 failed: astilibav: subtitle streams can only be copied"},
+		{args: []string{"-i", "unknown.mp4", "out.mp4"}, err: "astilibav: getting streams of output out.mp4 failed: astilibav: probing unknown.mp4 failed: not found"},
+	} {
+		_, err = ImportFFmpegCommand(v.args, o)
+		assert.EqualError(t, err, v.err, "%v", v.args)
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -21,14 +25,18 @@ type Filterer struct {
 	*astiencoder.BaseNode
 	bufferSinkCtx    *avfilter.Context
 	bufferSrcCtxs    map[astiencoder.Node][]*avfilter.Context
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	cl               *astikit.Closer
 	d                *frameDispatcher
+	definition       *FiltererDefinitionOptions
 	eh               *astiencoder.EventHandler
 	emulatePeriod    time.Duration
 	g                *avfilter.Graph
+	inputs           map[string]astiencoder.Node
+	m                *sync.Mutex
 	outputCtx        Context
 	restamper        FrameRestamper
+	spans            *pendingSpans
 	statIncomingRate *astikit.CounterRateStat
 	statWorkRatio    *astikit.DurationPercentageStat
 }
@@ -51,21 +59,20 @@ func NewFilterer(o FiltererOptions, eh *astiencoder.EventHandler, c *astikit.Clo
 
 	// Create filterer
 	f = &Filterer{
-		bufferSrcCtxs: make(map[astiencoder.Node][]*avfilter.Context),
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c:                astiencoder.NewQueue(o.Node.Queue),
 		cl:               c.NewChild(),
 		eh:               eh,
 		g:                avfilter.AvfilterGraphAlloc(),
+		inputs:           o.Inputs,
+		m:                &sync.Mutex{},
 		outputCtx:        o.OutputCtx,
 		restamper:        o.Restamper,
+		spans:            newPendingSpans(),
 		statIncomingRate: astikit.NewCounterRateStat(),
 		statWorkRatio:    astikit.NewDurationPercentageStat(),
 	}
 	f.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(f), eh)
-	f.d = newFrameDispatcher(f, eh, f.cl)
+	f.d = newFrameDispatcher(f, eh)
 	f.addStats()
 
 	// No inputs
@@ -80,16 +87,36 @@ func NewFilterer(o FiltererOptions, eh *astiencoder.EventHandler, c *astikit.Clo
 		f.emulatePeriod = time.Duration(o.EmulateRate.Den() * 1e9 / o.EmulateRate.Num())
 	}
 
-	// Create graph
+	// Attribute libav logs to the filterer
+	registerLogOwner(unsafe.Pointer(f.g), f)
+
+	// Make sure the graph is properly freed
 	f.cl.Add(func() error {
+		f.m.Lock()
+		defer f.m.Unlock()
+		unregisterLogOwner(unsafe.Pointer(f.g))
 		f.g.AvfilterGraphFree()
 		return nil
 	})
 
+	// Create graph
+	if f.bufferSinkCtx, f.bufferSrcCtxs, err = createFiltererGraph(f.g, o.Content, o.Inputs, o.OutputCtx); err != nil {
+		err = fmt.Errorf("astilibav: creating graph failed: %w", err)
+		return
+	}
+
+	// Frames are output in hardware memory
+	if h := newBufferSinkHardwareFrames(f.bufferSinkCtx, f.cl); h != nil {
+		f.outputCtx.HardwareFramesCtx = h
+	}
+	return
+}
+
+func createFiltererGraph(g *avfilter.Graph, content string, ins map[string]astiencoder.Node, outputCtx Context) (bufferSinkCtx *avfilter.Context, bufferSrcCtxs map[astiencoder.Node][]*avfilter.Context, err error) {
 	// Create buffer func and buffer sink
 	var bufferFunc func() *avfilter.Filter
 	var bufferSink *avfilter.Filter
-	switch o.OutputCtx.CodecType {
+	switch outputCtx.CodecType {
 	case avcodec.AVMEDIA_TYPE_AUDIO:
 		bufferFunc = func() *avfilter.Filter { return avfilter.AvfilterGetByName("abuffer") }
 		bufferSink = avfilter.AvfilterGetByName("abuffersink")
@@ -97,29 +124,28 @@ func NewFilterer(o FiltererOptions, eh *astiencoder.EventHandler, c *astikit.Clo
 		bufferFunc = func() *avfilter.Filter { return avfilter.AvfilterGetByName("buffer") }
 		bufferSink = avfilter.AvfilterGetByName("buffersink")
 	default:
-		err = fmt.Errorf("astilibav: codec type %v is not handled by filterer", o.OutputCtx.CodecType)
+		err = fmt.Errorf("astilibav: codec type %v is not handled by filterer", outputCtx.CodecType)
 		return
 	}
 
 	// Create buffer sink ctx
-	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
-	var bufferSinkCtx *avfilter.Context
-	if ret := avfilter.AvfilterGraphCreateFilter(&bufferSinkCtx, bufferSink, "out", "", nil, f.g); ret < 0 {
+	if ret := avfilter.AvfilterGraphCreateFilter(&bufferSinkCtx, bufferSink, "out", "", nil, g); ret < 0 {
 		err = fmt.Errorf("astilibav: avfilter.AvfilterGraphCreateFilter on empty args failed: %w", NewAvError(ret))
 		return
 	}
-	f.bufferSinkCtx = bufferSinkCtx
 
 	// Create inputs
 	inputs := avfilter.AvfilterInoutAlloc()
 	inputs.SetName("out")
-	inputs.SetFilterCtx(f.bufferSinkCtx)
+	inputs.SetFilterCtx(bufferSinkCtx)
 	inputs.SetPadIdx(0)
 	inputs.SetNext(nil)
 
 	// Loop through options inputs
+	var hardwareFramesCtx *HardwareFramesContext
 	var previousOutput *avfilter.Input
-	for n, i := range o.Inputs {
+	bufferSrcCtxs = make(map[astiencoder.Node][]*avfilter.Context)
+	for n, i := range ins {
 		// Get context
 		v, ok := i.(OutputContexter)
 		if !ok {
@@ -147,12 +173,22 @@ func NewFilterer(o FiltererOptions, eh *astiencoder.EventHandler, c *astikit.Clo
 		}
 
 		// Create ctx
+		// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
 		var bufferSrcCtx *avfilter.Context
-		if ret := avfilter.AvfilterGraphCreateFilter(&bufferSrcCtx, bufferSrc, "in", args, nil, f.g); ret < 0 {
+		if ret := avfilter.AvfilterGraphCreateFilter(&bufferSrcCtx, bufferSrc, "in", args, nil, g); ret < 0 {
 			err = fmt.Errorf("astilibav: avfilter.AvfilterGraphCreateFilter on args %s failed: %w", args, NewAvError(ret))
 			return
 		}
 
+		// Frames live in hardware memory
+		if ctx.HardwareFramesCtx != nil {
+			if err = setBufferSrcHardwareFrames(bufferSrcCtx, ctx.HardwareFramesCtx); err != nil {
+				err = fmt.Errorf("astilibav: setting buffer src hardware frames failed: %w", err)
+				return
+			}
+			hardwareFramesCtx = ctx.HardwareFramesCtx
+		}
+
 		// Create outputs
 		outputs := avfilter.AvfilterInoutAlloc()
 		outputs.SetName(n)
@@ -161,26 +197,58 @@ func NewFilterer(o FiltererOptions, eh *astiencoder.EventHandler, c *astikit.Clo
 		outputs.SetNext(previousOutput)
 
 		// Store ctx
-		f.bufferSrcCtxs[i] = append(f.bufferSrcCtxs[i], bufferSrcCtx)
+		bufferSrcCtxs[i] = append(bufferSrcCtxs[i], bufferSrcCtx)
 
 		// Set previous output
 		previousOutput = outputs
 	}
 
 	// Parse content
-	if ret := f.g.AvfilterGraphParsePtr(o.Content, &inputs, &previousOutput, nil); ret < 0 {
-		err = fmt.Errorf("astilibav: g.AvfilterGraphParsePtr on content %s failed: %w", o.Content, NewAvError(ret))
+	if ret := g.AvfilterGraphParsePtr(content, &inputs, &previousOutput, nil); ret < 0 {
+		err = fmt.Errorf("astilibav: g.AvfilterGraphParsePtr on content %s failed: %w", content, NewAvError(ret))
 		return
 	}
 
+	// Make the hardware device available to filters
+	if hardwareFramesCtx != nil {
+		if err = setGraphHardwareDevice(g, hardwareFramesCtx); err != nil {
+			err = fmt.Errorf("astilibav: setting graph hardware device failed: %w", err)
+			return
+		}
+	}
+
 	// Configure
-	if ret := f.g.AvfilterGraphConfig(nil); ret < 0 {
+	if ret := g.AvfilterGraphConfig(nil); ret < 0 {
 		err = fmt.Errorf("astilibav: g.AvfilterGraphConfig failed: %w", NewAvError(ret))
 		return
 	}
 	return
 }
 
+// SetContent replaces the filter graph with a new one created from the content while the filterer is running, which
+// allows updating filters that don't support commands. Inputs and output ctx stay the same, therefore the new graph
+// must output frames with the same properties. Frames buffered in the previous graph are dropped
+func (f *Filterer) SetContent(content string) (err error) {
+	// Create graph
+	g := avfilter.AvfilterGraphAlloc()
+	var bufferSinkCtx *avfilter.Context
+	var bufferSrcCtxs map[astiencoder.Node][]*avfilter.Context
+	if bufferSinkCtx, bufferSrcCtxs, err = createFiltererGraph(g, content, f.inputs, f.outputCtx); err != nil {
+		g.AvfilterGraphFree()
+		err = fmt.Errorf("astilibav: creating graph failed: %w", err)
+		return
+	}
+
+	// Lock
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	// Swap graphs
+	f.g.AvfilterGraphFree()
+	f.g, f.bufferSinkCtx, f.bufferSrcCtxs = g, bufferSinkCtx, bufferSrcCtxs
+	return
+}
+
 func (f *Filterer) Close() error {
 	return f.cl.Close()
 }
@@ -207,6 +275,17 @@ func (f *Filterer) addStats() {
 	f.c.AddStats(f.Stater())
 }
 
+// AcceptsHardwareFrames implements the HardwareFrameHandler interface
+// Buffer srcs of inputs whose output ctx has hardware frames are configured to receive them without copies
+func (f *Filterer) AcceptsHardwareFrames(from astiencoder.Node, hardwarePixelFormat avutil.PixelFormat) bool {
+	v, ok := from.(OutputContexter)
+	if !ok {
+		return false
+	}
+	h := v.OutputCtx().HardwareFramesCtx
+	return h != nil && h.PixelFormat() == hardwarePixelFormat
+}
+
 // OutputCtx returns the output ctx
 func (f *Filterer) OutputCtx() Context {
 	return f.outputCtx
@@ -236,10 +315,15 @@ func (f *Filterer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
 		defer f.d.wait()
 
+		// Make sure to end pending spans
+		defer f.spans.close()
+
 		// In case there are no inputs, we emulate frames coming in
-		if len(f.bufferSrcCtxs) == 0 {
+		if len(f.inputs) == 0 {
 			nextAt := time.Now()
+			f.m.Lock()
 			desc := newFiltererDescriptor(f.bufferSinkCtx, nil)
+			f.m.Unlock()
 			for {
 				if stop := f.tickFunc(&nextAt, desc); stop {
 					break
@@ -272,19 +356,36 @@ func (f *Filterer) tickFunc(nextAt *time.Time, desc Descriptor) (stop bool) {
 	}
 
 	// Pull filtered frame
+	f.m.Lock()
 	f.pullFilteredFrame(desc)
+	f.m.Unlock()
 	return
 }
 
 // HandleFrame implements the FrameHandler interface
 func (f *Filterer) HandleFrame(p *FrameHandlerPayload) {
-	f.c.Add(func() {
+	// Trace
+	if p.traceCtx != nil {
+		f.spans.start(p.traceCtx, f, SpanNameFilter, p.Frame.Pts())
+	}
+
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be filtered
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, f); p == nil {
+		return
+	}
+	f.c.AddWithRelease(func() {
 		// Handle pause
 		defer f.HandlePause()
 
 		// Increment incoming rate
 		f.statIncomingRate.Add(1)
 
+		// Lock
+		f.m.Lock()
+		defer f.m.Unlock()
+
 		// Retrieve buffer ctxs
 		bufferSrcCtxs, ok := f.bufferSrcCtxs[p.Node]
 		if !ok {
@@ -310,7 +411,7 @@ func (f *Filterer) HandleFrame(p *FrameHandlerPayload) {
 				return
 			}
 		}
-	})
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
 }
 
 func (f *Filterer) pullFilteredFrame(descriptor Descriptor) (stop bool) {
@@ -330,6 +431,11 @@ func (f *Filterer) pullFilteredFrame(descriptor Descriptor) (stop bool) {
 	}
 	f.statWorkRatio.End()
 
+	// Get trace
+	// Frames whose pts is modified by the graph are not traced any further
+	traceCtx, endSpan := f.spans.take(fm.Pts())
+	defer endSpan()
+
 	// Restamp
 	if f.restamper != nil {
 		f.statWorkRatio.Begin()
@@ -338,12 +444,17 @@ func (f *Filterer) pullFilteredFrame(descriptor Descriptor) (stop bool) {
 	}
 
 	// Dispatch frame
-	f.d.dispatch(fm, newFiltererDescriptor(f.bufferSinkCtx, descriptor))
+	f.d.dispatchWithTrace(traceCtx, fm, newFiltererDescriptor(f.bufferSinkCtx, descriptor))
 	return
 }
 
 // SendCommand sends a command to the filterer
 func (f *Filterer) SendCommand(target, cmd, arg string, flags int) (err error) {
+	// Lock
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	// Send command
 	var res string
 	if ret := f.g.AvfilterGraphSendCommand(target, cmd, arg, res, 255, flags); ret < 0 {
 		err = fmt.Errorf("astilibav: f.g.AvfilterGraphSendCommand for target %s, cmd %s, arg %s and flag %d failed with res %s: %w", target, cmd, arg, flags, res, NewAvError(ret))
@@ -370,3 +481,29 @@ func newFiltererDescriptor(bufferSinkCtx *avfilter.Context, prev Descriptor) (d
 func (d *filtererDescriptor) TimeBase() avutil.Rational {
 	return d.timeBase
 }
+
+// escapeFilterOption escapes a filter option value so that it can be used in a filter graph content
+// Values are escaped once for the filter options parser and once for the filter graph parser
+func escapeFilterOption(v string) string {
+	return escapeFilterChars(escapeFilterCommandArg(v), `\'[],;`)
+}
+
+// escapeFilterCommandArg escapes a filter option value so that it can be used in a command arg
+func escapeFilterCommandArg(v string) string {
+	return escapeFilterChars(v, `\':=`)
+}
+
+func escapeFilterChars(v, chars string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if strings.ContainsRune(chars, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func formatFilterFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
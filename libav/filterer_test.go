@@ -0,0 +1,12 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeFilterOption(t *testing.T) {
+	assert.Equal(t, `/tmp/a\\:b.cube`, escapeFilterOption("/tmp/a:b.cube"))
+	assert.Equal(t, `it\\\'s\,ok`, escapeFilterOption("it's,ok"))
+}
@@ -14,7 +14,7 @@ var countForwarder uint64
 // Forwarder represents an object capable of forwarding frames
 type Forwarder struct {
 	*astiencoder.BaseNode
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	d                *frameDispatcher
 	outputCtx        Context
 	restamper        FrameRestamper
@@ -37,17 +37,14 @@ func NewForwarder(o ForwarderOptions, eh *astiencoder.EventHandler, c *astikit.C
 
 	// Create forwarder
 	f = &Forwarder{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c:                astiencoder.NewQueue(o.Node.Queue),
 		outputCtx:        o.OutputCtx,
 		restamper:        o.Restamper,
 		statIncomingRate: astikit.NewCounterRateStat(),
 		statWorkRatio:    astikit.NewDurationPercentageStat(),
 	}
 	f.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(f), eh)
-	f.d = newFrameDispatcher(f, eh, c)
+	f.d = newFrameDispatcher(f, eh)
 	f.addStats()
 	return
 }
@@ -113,7 +110,13 @@ func (f *Forwarder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 
 // HandleFrame implements the FrameHandler interface
 func (f *Forwarder) HandleFrame(p *FrameHandlerPayload) {
-	f.c.Add(func() {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, f); p == nil {
+		return
+	}
+	f.c.AddWithRelease(func() {
 		// Handle pause
 		defer f.HandlePause()
 
@@ -129,5 +132,5 @@ func (f *Forwarder) HandleFrame(p *FrameHandlerPayload) {
 
 		// Dispatch frame
 		f.d.dispatch(p.Frame, p.Descriptor)
-	})
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
 }
@@ -1,6 +1,8 @@
 package astilibav
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/asticode/go-astiencoder"
@@ -9,11 +11,21 @@ import (
 )
 
 // FrameHandler represents a node that can handle a frame
+// The payload and its frame are released once HandleFrame returns, therefore handlers needing the frame afterwards
+// must reference it
 type FrameHandler interface {
 	astiencoder.Node
 	HandleFrame(p *FrameHandlerPayload)
 }
 
+// HardwareFrameHandler represents a frame handler that can handle frames living in hardware memory
+// Frames living in hardware memory are downloaded to system memory before being sent to frame handlers that don't
+// implement it or don't accept them, which allows adjacent hardware nodes to exchange frames without copies
+type HardwareFrameHandler interface {
+	FrameHandler
+	AcceptsHardwareFrames(from astiencoder.Node, hardwarePixelFormat avutil.PixelFormat) bool
+}
+
 // FrameHandlerConnector represents an object that can connect/disconnect with a frame handler
 type FrameHandlerConnector interface {
 	Connect(next FrameHandler)
@@ -25,27 +37,71 @@ type FrameHandlerPayload struct {
 	Descriptor Descriptor
 	Frame      *avutil.Frame
 	Node       astiencoder.Node
+	// Only set when the frame belongs to a traced GOP or is a latency probe
+	traceCtx context.Context
+}
+
+var frameHandlerPayloadPool = sync.Pool{New: func() interface{} { return &FrameHandlerPayload{} }}
+
+func newFrameHandlerPayload(f *avutil.Frame, descriptor Descriptor, n astiencoder.Node) *FrameHandlerPayload {
+	p := frameHandlerPayloadPool.Get().(*FrameHandlerPayload)
+	p.Descriptor, p.Frame, p.Node = descriptor, f, n
+	return p
+}
+
+// releaseFrameHandlerPayload resets the payload so that handlers using it after being done fail fast instead of
+// using a recycled frame
+func releaseFrameHandlerPayload(p *FrameHandlerPayload) {
+	*p = FrameHandlerPayload{}
+	frameHandlerPayloadPool.Put(p)
+}
+
+// refFrameHandlerPayload returns a copy of the payload referencing its frame so that it can be used once HandleFrame
+// has returned, e.g. when handing it over to a queue. It must be released with unrefFrameHandlerPayload
+// The frame is registered with the memory budget of the node's workflow and nil is returned if it should be dropped
+func refFrameHandlerPayload(p *FrameHandlerPayload, n astiencoder.Node) *FrameHandlerPayload {
+	var f *avutil.Frame
+	if p.Frame != nil {
+		f = sharedFramePool.get()
+		avutil.AvFrameRef(f, p.Frame)
+		if !trackFrame(f, nodeMemoryAccountant(n)) {
+			sharedFramePool.put(f)
+			return nil
+		}
+	}
+	r := newFrameHandlerPayload(f, p.Descriptor, p.Node)
+	r.traceCtx = p.traceCtx
+	return r
+}
+
+func unrefFrameHandlerPayload(p *FrameHandlerPayload) {
+	if p.Frame != nil {
+		sharedFramePool.put(p.Frame)
+	}
+	releaseFrameHandlerPayload(p)
 }
 
 type frameDispatcher struct {
-	c            *astikit.Closer
 	eh           *astiencoder.EventHandler
 	hs           map[string]FrameHandler
+	hsBuf        []FrameHandler
 	m            *sync.Mutex
 	n            astiencoder.Node
 	p            *framePool
 	statDispatch *astikit.DurationPercentageStat
+	statRate     *astikit.CounterRateStat
 	wg           *sync.WaitGroup
 }
 
-func newFrameDispatcher(n astiencoder.Node, eh *astiencoder.EventHandler, c *astikit.Closer) *frameDispatcher {
+func newFrameDispatcher(n astiencoder.Node, eh *astiencoder.EventHandler) *frameDispatcher {
 	return &frameDispatcher{
 		eh:           eh,
 		hs:           make(map[string]FrameHandler),
 		m:            &sync.Mutex{},
 		n:            n,
-		p:            newFramePool(c),
+		p:            sharedFramePool,
 		statDispatch: astikit.NewDurationPercentageStat(),
+		statRate:     astikit.NewCounterRateStat(),
 		wg:           &sync.WaitGroup{},
 	}
 }
@@ -63,12 +119,22 @@ func (d *frameDispatcher) delHandler(h FrameHandler) {
 }
 
 func (d *frameDispatcher) dispatch(f *avutil.Frame, descriptor Descriptor) {
+	d.dispatchWithTrace(nil, f, descriptor)
+}
+
+// dispatchWithTrace dispatches a frame belonging to a traced GOP or being a latency probe when traceCtx is not nil
+func (d *frameDispatcher) dispatchWithTrace(traceCtx context.Context, f *avutil.Frame, descriptor Descriptor) {
+	// Increment outgoing rate
+	d.statRate.Add(1)
+
 	// Copy handlers
+	// The buffer can be reused since dispatches are sequential and handlers are passed by value to subprocesses
 	d.m.Lock()
-	var hs []FrameHandler
+	hs := d.hsBuf[:0]
 	for _, h := range d.hs {
 		hs = append(hs, h)
 	}
+	d.hsBuf = hs
 	d.m.Unlock()
 
 	// No handlers
@@ -86,29 +152,67 @@ func (d *frameDispatcher) dispatch(f *avutil.Frame, descriptor Descriptor) {
 	// Add subprocesses
 	d.wg.Add(len(hs))
 
+	// Frame lives in hardware memory
+	hardware := isHardwareFrame(f)
+
+	// Frames are only downloaded once for all handlers that need it
+	var sf *avutil.Frame
+	var downloadErr error
+	defer func() {
+		if sf != nil {
+			d.p.put(sf)
+		}
+	}()
+
 	// Loop through handlers
 	for _, h := range hs {
+		// Get source frame
+		src := f
+		if hardware && !acceptsHardwareFrames(h, d.n, f) {
+			// Download
+			if sf == nil && downloadErr == nil {
+				sf = d.p.get()
+				if _, downloadErr = downloadHardwareFrame(sf, f); downloadErr != nil {
+					d.eh.Emit(astiencoder.EventError(d.n, fmt.Errorf("astilibav: downloading hardware frame failed: %w", downloadErr)))
+				}
+			}
+
+			// Download failed
+			if downloadErr != nil {
+				d.wg.Done()
+				continue
+			}
+			src = sf
+		}
+
 		// Copy frame
 		hF := d.p.get()
-		if ret := avutil.AvFrameRef(hF, f); ret < 0 {
+		if ret := avutil.AvFrameRef(hF, src); ret < 0 {
 			emitAvError(d, d.eh, ret, "avutil.AvFrameRef failed")
+			d.p.put(hF)
 			d.wg.Done()
 			continue
 		}
 
+		// Create payload
+		p := newFrameHandlerPayload(hF, descriptor, d.n)
+		p.traceCtx = traceCtx
+
 		// Handle frame
-		go func(h FrameHandler) {
+		go func(h FrameHandler, p *FrameHandlerPayload) {
 			defer d.wg.Done()
-			defer d.p.put(hF)
-			h.HandleFrame(&FrameHandlerPayload{
-				Descriptor: descriptor,
-				Frame:      hF,
-				Node:       d.n,
-			})
-		}(h)
+			defer d.p.put(p.Frame)
+			defer releaseFrameHandlerPayload(p)
+			h.HandleFrame(p)
+		}(h, p)
 	}
 }
 
+func acceptsHardwareFrames(h FrameHandler, from astiencoder.Node, f *avutil.Frame) bool {
+	v, ok := h.(HardwareFrameHandler)
+	return ok && v.AcceptsHardwareFrames(from, avutil.PixelFormat(f.Format()))
+}
+
 func (d *frameDispatcher) wait() {
 	d.wg.Wait()
 }
@@ -120,40 +224,11 @@ func (d *frameDispatcher) addStats(s *astikit.Stater) {
 		Label:       "Dispatch ratio",
 		Unit:        "%",
 	}, d.statDispatch)
-}
-
-type framePool struct {
-	c *astikit.Closer
-	m *sync.Mutex
-	p []*avutil.Frame
-}
 
-func newFramePool(c *astikit.Closer) *framePool {
-	return &framePool{
-		c: c,
-		m: &sync.Mutex{},
-	}
-}
-
-func (p *framePool) get() (f *avutil.Frame) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	if len(p.p) == 0 {
-		f = avutil.AvFrameAlloc()
-		p.c.Add(func() error {
-			avutil.AvFrameFree(f)
-			return nil
-		})
-		return
-	}
-	f = p.p[0]
-	p.p = p.p[1:]
-	return
-}
-
-func (p *framePool) put(f *avutil.Frame) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	avutil.AvFrameUnref(f)
-	p.p = append(p.p, f)
+	// Add outgoing rate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of frames going out per second",
+		Label:       "Outgoing rate",
+		Unit:        "fps",
+	}, d.statRate)
 }
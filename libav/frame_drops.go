@@ -0,0 +1,244 @@
+package astilibav
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// Frame drop reasons
+const (
+	// Frame is too late compared to realtime or to the slot it should fill
+	FrameDropReasonLate = "late"
+	// Frame is a non-reference frame dropped to catch up with realtime
+	FrameDropReasonNonReference = "non_reference"
+	// Frame falls in a slot that has already been filled
+	FrameDropReasonSameSlot = "same_slot"
+	// Frame comes from a source that is not used anymore
+	FrameDropReasonUnusedSource = "unused_source"
+)
+
+// Frame duplication reasons
+const (
+	// No frame has been received for the slot
+	FrameDuplicateReasonMissing = "missing"
+)
+
+var frameDropReasonDescriptions = map[string]string{
+	FrameDropReasonLate:         "being too late",
+	FrameDropReasonNonReference: "being non-reference frames while the node was catching up",
+	FrameDropReasonSameSlot:     "falling in an already filled slot",
+	FrameDropReasonUnusedSource: "coming from an unused source",
+	FrameDuplicateReasonMissing: "missing frames",
+}
+
+// FrameDropsOptions represents options of nodes dropping or duplicating frames
+type FrameDropsOptions struct {
+	// Ratio of frames dropped or duplicated over frames coming in during a window above which a
+	// FrameDropsThresholdExceeded event is emitted. Default is 0.05
+	Threshold float64
+	// Default is 10s
+	Window time.Duration
+}
+
+// FrameDrops represents the payload of frame drops events
+// Counts are those of the window that triggered the event
+type FrameDrops struct {
+	Dropped    map[string]uint64
+	Duplicated map[string]uint64
+	Incoming   uint64
+	Ratio      float64
+}
+
+// frameDrops keeps track of the frames dropped or duplicated by a node
+// Counts are cumulative in stats whereas the threshold is checked on windows
+type frameDrops struct {
+	eh           *astiencoder.EventHandler
+	exceeded     bool
+	m            *sync.Mutex
+	n            astiencoder.Node
+	o            FrameDropsOptions
+	statsDropped map[string]*frameCountStat
+	statsDup     map[string]*frameCountStat
+	w            frameDropsWindow
+}
+
+type frameDropsWindow struct {
+	at         time.Time
+	dropped    map[string]uint64
+	duplicated map[string]uint64
+	incoming   uint64
+}
+
+func newFrameDrops(n astiencoder.Node, o FrameDropsOptions, eh *astiencoder.EventHandler, dropReasons, dupReasons []string) *frameDrops {
+	// Default options
+	if o.Threshold <= 0 {
+		o.Threshold = 0.05
+	}
+	if o.Window <= 0 {
+		o.Window = 10 * time.Second
+	}
+
+	// Create frame drops
+	d := &frameDrops{
+		eh:           eh,
+		m:            &sync.Mutex{},
+		n:            n,
+		o:            o,
+		statsDropped: make(map[string]*frameCountStat),
+		statsDup:     make(map[string]*frameCountStat),
+	}
+	for _, r := range dropReasons {
+		d.statsDropped[r] = &frameCountStat{}
+	}
+	for _, r := range dupReasons {
+		d.statsDup[r] = &frameCountStat{}
+	}
+	d.resetWindow(time.Time{})
+	return d
+}
+
+func (d *frameDrops) addStats(s *astikit.Stater) {
+	// Add dropped frames
+	for _, r := range sortedFrameDropReasons(d.statsDropped) {
+		s.AddStat(astikit.StatMetadata{
+			Description: "Total number of frames dropped because of " + frameDropReasonDescriptions[r],
+			Label:       "Dropped frames (" + r + ")",
+			Unit:        "frames",
+		}, d.statsDropped[r])
+	}
+
+	// Add duplicated frames
+	for _, r := range sortedFrameDropReasons(d.statsDup) {
+		s.AddStat(astikit.StatMetadata{
+			Description: "Total number of frames duplicated because of " + frameDropReasonDescriptions[r],
+			Label:       "Duplicated frames (" + r + ")",
+			Unit:        "frames",
+		}, d.statsDup[r])
+	}
+}
+
+func sortedFrameDropReasons(m map[string]*frameCountStat) (rs []string) {
+	for _, r := range []string{FrameDropReasonLate, FrameDropReasonNonReference, FrameDropReasonSameSlot, FrameDropReasonUnusedSource, FrameDuplicateReasonMissing} {
+		if _, ok := m[r]; ok {
+			rs = append(rs, r)
+		}
+	}
+	return
+}
+
+// incoming must be called for every frame coming in and checks the threshold once the window is over
+func (d *frameDrops) incoming(now time.Time) {
+	// Lock
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	// First window
+	if d.w.at.IsZero() {
+		d.w.at = now
+	}
+
+	// Window is over
+	if now.Sub(d.w.at) >= d.o.Window {
+		d.checkThreshold()
+		d.resetWindow(now)
+	}
+
+	// Increment
+	d.w.incoming++
+}
+
+func (d *frameDrops) drop(reason string) {
+	// Update stat
+	if s, ok := d.statsDropped[reason]; ok {
+		s.add(1)
+	}
+
+	// Update window
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.w.dropped[reason]++
+}
+
+func (d *frameDrops) duplicate(reason string, count int) {
+	// Update stat
+	if s, ok := d.statsDup[reason]; ok {
+		s.add(uint64(count))
+	}
+
+	// Update window
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.w.duplicated[reason] += uint64(count)
+}
+
+// checkThreshold emits an event when the ratio of the window crosses the threshold
+// It must be called while holding the lock
+func (d *frameDrops) checkThreshold() {
+	// Get ratio
+	var count uint64
+	for _, v := range d.w.dropped {
+		count += v
+	}
+	for _, v := range d.w.duplicated {
+		count += v
+	}
+	var ratio float64
+	if d.w.incoming > 0 {
+		ratio = float64(count) / float64(d.w.incoming)
+	}
+
+	// Get status
+	exceeded := ratio > d.o.Threshold
+	if exceeded == d.exceeded {
+		return
+	}
+	d.exceeded = exceeded
+
+	// Emit
+	n := FrameDropsThresholdRecovered
+	if exceeded {
+		n = FrameDropsThresholdExceeded
+	}
+	d.eh.Emit(astiencoder.Event{
+		Name: n,
+		Payload: FrameDrops{
+			Dropped:    d.w.dropped,
+			Duplicated: d.w.duplicated,
+			Incoming:   d.w.incoming,
+			Ratio:      ratio,
+		},
+		Target: d.n,
+	})
+}
+
+func (d *frameDrops) resetWindow(at time.Time) {
+	d.w = frameDropsWindow{
+		at:         at,
+		dropped:    make(map[string]uint64),
+		duplicated: make(map[string]uint64),
+	}
+}
+
+// frameCountStat is a cumulative count of frames
+type frameCountStat struct {
+	v uint64
+}
+
+func (s *frameCountStat) add(delta uint64) {
+	atomic.AddUint64(&s.v, delta)
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *frameCountStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *frameCountStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *frameCountStat) Value(delta time.Duration) interface{} {
+	return float64(atomic.LoadUint64(&s.v))
+}
@@ -0,0 +1,45 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameDrops(t *testing.T) {
+	eh := astiencoder.NewEventHandler()
+	var es []astiencoder.Event
+	eh.AddForAll(func(e astiencoder.Event) bool {
+		es = append(es, e)
+		return false
+	})
+	d := newFrameDrops(nil, FrameDropsOptions{Threshold: 0.1, Window: time.Second}, eh, []string{FrameDropReasonSameSlot}, []string{FrameDuplicateReasonMissing})
+
+	now := time.Unix(0, 0)
+	for i := 0; i < 10; i++ {
+		d.incoming(now)
+	}
+	d.drop(FrameDropReasonSameSlot)
+	d.duplicate(FrameDuplicateReasonMissing, 2)
+	d.incoming(now.Add(time.Second))
+	assert.Equal(t, []astiencoder.Event{{
+		Name: FrameDropsThresholdExceeded,
+		Payload: FrameDrops{
+			Dropped:    map[string]uint64{FrameDropReasonSameSlot: 1},
+			Duplicated: map[string]uint64{FrameDuplicateReasonMissing: 2},
+			Incoming:   10,
+			Ratio:      0.3,
+		},
+	}}, es)
+
+	d.incoming(now.Add(2 * time.Second))
+	assert.Len(t, es, 2)
+	assert.Equal(t, FrameDropsThresholdRecovered, es[1].Name)
+	d.incoming(now.Add(3 * time.Second))
+	assert.Len(t, es, 2)
+
+	assert.Equal(t, 1.0, d.statsDropped[FrameDropReasonSameSlot].Value(time.Second))
+	assert.Equal(t, 2.0, d.statsDup[FrameDuplicateReasonMissing].Value(time.Second))
+}
@@ -0,0 +1,344 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/frame.h>
+#include <libavutil/pixdesc.h>
+#include <libavutil/samplefmt.h>
+
+static int astilibavFrameNbPlanes(AVFrame *f) {
+	if (f->nb_samples > 0) return av_sample_fmt_is_planar(f->format) ? f->channels : 1;
+	const AVPixFmtDescriptor *desc = av_pix_fmt_desc_get(f->format);
+	if (!desc || desc->flags & AV_PIX_FMT_FLAG_HWACCEL) return -1;
+	return av_pix_fmt_count_planes(f->format);
+}
+
+static uint8_t *astilibavFramePlane(AVFrame *f, int idx, int *stride, int *size) {
+	if (f->nb_samples > 0) {
+		*stride = f->linesize[0];
+		*size = f->linesize[0];
+		return f->extended_data[idx];
+	}
+	const AVPixFmtDescriptor *desc = av_pix_fmt_desc_get(f->format);
+	int h = f->height;
+	if (idx == 1 || idx == 2) h = -((-h) >> desc->log2_chroma_h);
+	*stride = f->linesize[idx];
+	*size = f->linesize[idx] * h;
+	return f->data[idx];
+}
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+// Frame represents a frame whose planes can be read and written in Go
+// Planes point to the frame buffers, therefore they must not be used once the filter has returned
+type Frame struct {
+	// Time at which the frame has been demuxed. Only set when the frame is a latency probe
+	DemuxedAt time.Time
+	Height    int
+	// Number of samples per channel for audio frames
+	NbSamples int
+	// Only set for video frames
+	PixelFormat avutil.PixelFormat
+	// Video frames have one plane per component group (e.g. 3 for yuv420p). Planar audio frames have one plane per
+	// channel whereas packed audio frames have one plane
+	Planes [][]byte
+	Pts    int64
+	// Only set for audio frames
+	SampleFmt avcodec.AvSampleFormat
+	// Number of bytes per line of each plane
+	Strides  []int
+	TimeBase avutil.Rational
+	Width    int
+}
+
+// FrameFilter represents an object capable of processing frames in Go
+// Planes can be modified in place and the same frame returned. If the returned frame has different planes, they
+// are copied into the frame buffers and must therefore have the same layout. If the returned frame has no planes,
+// the frame is dropped
+type FrameFilter interface {
+	Filter(f Frame) (Frame, error)
+}
+
+// FrameFilterFunc is an adapter to allow using a function as a FrameFilter
+type FrameFilterFunc func(f Frame) (Frame, error)
+
+// Filter implements the FrameFilter interface
+func (fn FrameFilterFunc) Filter(f Frame) (Frame, error) {
+	return fn(f)
+}
+
+func newFrame(f *avutil.Frame, timeBase avutil.Rational) (o Frame, err error) {
+	// Get frame
+	cf := (*C.AVFrame)(unsafe.Pointer(f))
+
+	// Get number of planes
+	n := int(C.astilibavFrameNbPlanes(cf))
+	if n < 0 {
+		err = errors.New("astilibav: frame format is not supported")
+		return
+	}
+
+	// Create frame
+	o = Frame{
+		Height:    int(cf.height),
+		NbSamples: int(cf.nb_samples),
+		Planes:    make([][]byte, n),
+		Pts:       f.Pts(),
+		Strides:   make([]int, n),
+		TimeBase:  timeBase,
+		Width:     int(cf.width),
+	}
+	if o.NbSamples > 0 {
+		o.SampleFmt = avcodec.AvSampleFormat(cf.format)
+	} else {
+		o.PixelFormat = avutil.PixelFormat(cf.format)
+	}
+
+	// Loop through planes
+	for i := 0; i < n; i++ {
+		var stride, size C.int
+		p := C.astilibavFramePlane(cf, C.int(i), &stride, &size)
+		if p == nil || size <= 0 {
+			continue
+		}
+		o.Planes[i] = (*[1 << 30]byte)(unsafe.Pointer(p))[:int(size):int(size)]
+		o.Strides[i] = int(stride)
+	}
+	return
+}
+
+// copyFramePlanes copies the planes of src into the planes of dst line by line
+func copyFramePlanes(dst, src Frame) error {
+	// Invalid number of planes
+	if len(dst.Planes) != len(src.Planes) || len(src.Strides) != len(src.Planes) {
+		return fmt.Errorf("astilibav: frame has %d planes, expected %d", len(src.Planes), len(dst.Planes))
+	}
+
+	// Loop through planes
+	for i := range dst.Planes {
+		// Same buffer
+		d, s := dst.Planes[i], src.Planes[i]
+		if len(d) == 0 || len(s) == 0 || &d[0] == &s[0] {
+			continue
+		}
+
+		// Same stride
+		ds, ss := dst.Strides[i], src.Strides[i]
+		if ds == ss {
+			copy(d, s)
+			continue
+		}
+
+		// Copy line by line
+		l := ss
+		if ds < l {
+			l = ds
+		}
+		for do, so := 0, 0; do < len(d) && so < len(s); do, so = do+ds, so+ss {
+			e := so + l
+			if e > len(s) {
+				e = len(s)
+			}
+			copy(d[do:], s[so:e])
+		}
+	}
+	return nil
+}
+
+var countFrameFilterer uint64
+
+// FrameFilterer represents an object capable of applying a FrameFilter to frames
+// Frames are made writable before being filtered, therefore other handlers of the same parent are not impacted
+type FrameFilterer struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *frameDispatcher
+	eh               *astiencoder.EventHandler
+	o                FrameFiltererOptions
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// FrameFiltererOptions represents frame filterer options
+type FrameFiltererOptions struct {
+	Filter FrameFilter
+	Node   astiencoder.NodeOptions
+	// Filters can't change frame properties, therefore this should be the parent's output ctx
+	OutputCtx Context
+}
+
+// NewFrameFilterer creates a new frame filterer
+func NewFrameFilterer(o FrameFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *FrameFilterer, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countFrameFilterer, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("frame_filterer_%d", count), fmt.Sprintf("Frame Filterer #%d", count), "Filters frames in Go", "frame filterer")
+
+	// No filter
+	if o.Filter == nil {
+		err = errors.New("astilibav: no filter")
+		return
+	}
+
+	// Create filterer
+	f = &FrameFilterer{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               eh,
+		o:                o,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	f.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(f), eh)
+	f.d = newFrameDispatcher(f, eh)
+	f.addStats()
+	return
+}
+
+func (f *FrameFilterer) addStats() {
+	// Add incoming rate
+	f.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, f.statIncomingRate)
+
+	// Add work ratio
+	f.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, f.statWorkRatio)
+
+	// Add dispatcher stats
+	f.d.addStats(f.Stater())
+
+	// Add chan stats
+	f.c.AddStats(f.Stater())
+}
+
+// OutputCtx returns the output ctx
+func (f *FrameFilterer) OutputCtx() Context {
+	return f.o.OutputCtx
+}
+
+// Connect implements the FrameHandlerConnector interface
+func (f *FrameFilterer) Connect(h FrameHandler) {
+	// Add handler
+	f.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(f, h)
+}
+
+// Disconnect implements the FrameHandlerConnector interface
+func (f *FrameFilterer) Disconnect(h FrameHandler) {
+	// Delete handler
+	f.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(f, h)
+}
+
+// Start starts the filterer
+func (f *FrameFilterer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	f.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer f.d.wait()
+
+		// Make sure to stop the chan properly
+		defer f.c.Stop()
+
+		// Start chan
+		f.c.Start(f.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (f *FrameFilterer) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be filtered
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, f); p == nil {
+		return
+	}
+	f.c.AddWithRelease(func() {
+		// Handle pause
+		defer f.HandlePause()
+
+		// Increment incoming rate
+		f.statIncomingRate.Add(1)
+
+		// Copy frame
+		fm := f.d.p.get()
+		defer f.d.p.put(fm)
+		if ret := avutil.AvFrameRef(fm, p.Frame); ret < 0 {
+			emitAvError(f, f.eh, ret, "avutil.AvFrameRef failed")
+			return
+		}
+
+		// Filter
+		f.statWorkRatio.Begin()
+		keep, err := f.filter(fm, p.Descriptor.TimeBase(), p.traceCtx)
+		f.statWorkRatio.End()
+		if err != nil {
+			f.eh.Emit(astiencoder.EventError(f, fmt.Errorf("astilibav: filtering frame failed: %w", err)))
+			return
+		}
+
+		// Dispatch
+		if keep {
+			f.d.dispatchWithTrace(p.traceCtx, fm, p.Descriptor)
+		}
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+func (f *FrameFilterer) filter(fm *avutil.Frame, timeBase avutil.Rational, traceCtx context.Context) (keep bool, err error) {
+	// Make frame writable
+	if ret := C.av_frame_make_writable((*C.AVFrame)(unsafe.Pointer(fm))); ret < 0 {
+		err = fmt.Errorf("astilibav: av_frame_make_writable failed: %w", NewAvError(int(ret)))
+		return
+	}
+
+	// Create frame
+	var in Frame
+	if in, err = newFrame(fm, timeBase); err != nil {
+		err = fmt.Errorf("astilibav: creating frame failed: %w", err)
+		return
+	}
+	in.DemuxedAt, _ = demuxedAtFromContext(traceCtx)
+
+	// Filter
+	var out Frame
+	if out, err = f.o.Filter.Filter(in); err != nil {
+		err = fmt.Errorf("astilibav: filter failed: %w", err)
+		return
+	}
+
+	// Frame is dropped
+	if len(out.Planes) == 0 {
+		return
+	}
+
+	// Copy planes
+	if err = copyFramePlanes(in, out); err != nil {
+		err = fmt.Errorf("astilibav: copying planes failed: %w", err)
+		return
+	}
+
+	// Update pts
+	fm.SetPts(out.Pts)
+	keep = true
+	return
+}
@@ -0,0 +1,18 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyFramePlanes(t *testing.T) {
+	dst := Frame{Planes: [][]byte{make([]byte, 8)}, Strides: []int{4}}
+	err := copyFramePlanes(dst, Frame{Planes: [][]byte{{1, 2, 3, 4, 5, 6}}, Strides: []int{3}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 0, 4, 5, 6, 0}, dst.Planes[0])
+	err = copyFramePlanes(dst, Frame{Planes: [][]byte{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, Strides: []int{5}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4, 6, 7, 8, 9}, dst.Planes[0])
+	assert.Error(t, copyFramePlanes(dst, Frame{}))
+}
@@ -0,0 +1,353 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec libavfilter libavutil
+#include <libavcodec/avcodec.h>
+#include <libavfilter/avfilter.h>
+#include <libavfilter/buffersink.h>
+#include <libavfilter/buffersrc.h>
+#include <libavutil/error.h>
+#include <libavutil/frame.h>
+#include <libavutil/hwcontext.h>
+#include <libavutil/mem.h>
+#include <stdlib.h>
+
+static enum AVPixelFormat astilibavGetHardwareFormat(AVCodecContext *c, const enum AVPixelFormat *fmts) {
+	AVHWFramesContext *f = (AVHWFramesContext *)((AVBufferRef *)c->opaque)->data;
+	for (const enum AVPixelFormat *p = fmts; *p != AV_PIX_FMT_NONE; p++) {
+		if (*p != f->format) continue;
+		if (!c->hw_frames_ctx) c->hw_frames_ctx = av_buffer_ref((AVBufferRef *)c->opaque);
+		return *p;
+	}
+	return AV_PIX_FMT_NONE;
+}
+
+static int astilibavSetDecoderHardwareFrames(AVCodecContext *c, AVBufferRef *frames) {
+	AVHWFramesContext *f = (AVHWFramesContext *)frames->data;
+	if (!(c->hw_device_ctx = av_buffer_ref(f->device_ref))) return AVERROR(ENOMEM);
+	c->opaque = frames;
+	c->get_format = astilibavGetHardwareFormat;
+	return 0;
+}
+
+static int astilibavSetEncoderHardwareFrames(AVCodecContext *c, AVBufferRef *frames) {
+	if (!(c->hw_frames_ctx = av_buffer_ref(frames))) return AVERROR(ENOMEM);
+	c->pix_fmt = ((AVHWFramesContext *)frames->data)->format;
+	return 0;
+}
+
+static int astilibavSetBufferSrcHardwareFrames(AVFilterContext *c, AVBufferRef *frames) {
+	AVBufferSrcParameters *p = av_buffersrc_parameters_alloc();
+	if (!p) return AVERROR(ENOMEM);
+	p->format = ((AVHWFramesContext *)frames->data)->format;
+	p->hw_frames_ctx = frames;
+	int ret = av_buffersrc_parameters_set(c, p);
+	av_free(p);
+	return ret;
+}
+
+static int astilibavSetGraphHardwareDevice(AVFilterGraph *g, AVBufferRef *frames) {
+	AVHWFramesContext *f = (AVHWFramesContext *)frames->data;
+	for (unsigned i = 0; i < g->nb_filters; i++) {
+		if (g->filters[i]->hw_device_ctx) continue;
+		if (!(g->filters[i]->hw_device_ctx = av_buffer_ref(f->device_ref))) return AVERROR(ENOMEM);
+	}
+	return 0;
+}
+
+static AVBufferRef *astilibavBufferSinkHardwareFrames(AVFilterContext *c) {
+	AVBufferRef *r = av_buffersink_get_hw_frames_ctx(c);
+	if (!r) return NULL;
+	return av_buffer_ref(r);
+}
+
+static int astilibavSetDecoderHardwareDevice(AVCodecContext *c, AVBufferRef *device) {
+	if (!(c->hw_device_ctx = av_buffer_ref(device))) return AVERROR(ENOMEM);
+	return 0;
+}
+
+static int astilibavIsHardwareFrame(AVFrame *f) {
+	return f->hw_frames_ctx != NULL;
+}
+
+static int astilibavDownloadHardwareFrame(AVFrame *dst, AVFrame *src) {
+	if (!src->hw_frames_ctx) return 0;
+	int ret = av_hwframe_transfer_data(dst, src, 0);
+	if (ret < 0) return ret;
+	ret = av_frame_copy_props(dst, src);
+	if (ret < 0) return ret;
+	return 1;
+}
+
+static int astilibavUploadHardwareFrame(AVFrame *dst, AVFrame *src, AVBufferRef *frames) {
+	if (src->hw_frames_ctx) return 0;
+	int ret = av_hwframe_get_buffer(frames, dst, 0);
+	if (ret < 0) return ret;
+	ret = av_hwframe_transfer_data(dst, src, 0);
+	if (ret < 0) return ret;
+	ret = av_frame_copy_props(dst, src);
+	if (ret < 0) return ret;
+	return 1;
+}
+
+static int astilibavSetEncoderHardwareDevice(AVCodecContext *c, AVBufferRef *device) {
+	if (!(c->hw_device_ctx = av_buffer_ref(device))) return AVERROR(ENOMEM);
+	return 0;
+}
+
+static enum AVPixelFormat astilibavHardwareFramesFormat(AVBufferRef *frames) {
+	return ((AVHWFramesContext *)frames->data)->format;
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avfilter"
+	"github.com/asticode/goav/avutil"
+)
+
+// hardwareSessions counts the sessions opened on each GPU
+type hardwareSessions struct {
+	m *sync.Mutex
+	s map[int]int
+}
+
+func newHardwareSessions() *hardwareSessions {
+	return &hardwareSessions{
+		m: &sync.Mutex{},
+		s: make(map[int]int),
+	}
+}
+
+// acquire returns false if max sessions are already opened on the gpu. If max <= 0, there's no limit
+func (s *hardwareSessions) acquire(gpu, max int) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if max > 0 && s.s[gpu] >= max {
+		return false
+	}
+	s.s[gpu]++
+	return true
+}
+
+func (s *hardwareSessions) release(gpu int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.s[gpu] <= 1 {
+		delete(s.s, gpu)
+		return
+	}
+	s.s[gpu]--
+}
+
+func (s *hardwareSessions) count() (o map[int]int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	o = make(map[int]int)
+	for gpu, n := range s.s {
+		o[gpu] = n
+	}
+	return
+}
+
+// HardwareDeviceContext represents a hardware device
+type HardwareDeviceContext struct {
+	r *C.AVBufferRef
+}
+
+// NewHardwareDeviceContext opens a hardware device of the provided type (e.g. "qsv"). If device is empty, ffmpeg's
+// default device for the type is used
+func NewHardwareDeviceContext(typ, device string, c *astikit.Closer) (d *HardwareDeviceContext, err error) {
+	// Find type
+	ctyp := C.CString(typ)
+	defer C.free(unsafe.Pointer(ctyp))
+	t := C.av_hwdevice_find_type_by_name(ctyp)
+	if t == C.AV_HWDEVICE_TYPE_NONE {
+		err = fmt.Errorf("astilibav: hardware device type %s not found", typ)
+		return
+	}
+
+	// Device
+	var cdevice *C.char
+	if device != "" {
+		cdevice = C.CString(device)
+		defer C.free(unsafe.Pointer(cdevice))
+	}
+
+	// Create context
+	d = &HardwareDeviceContext{}
+	if ret := C.av_hwdevice_ctx_create(&d.r, t, cdevice, nil, 0); ret < 0 {
+		err = fmt.Errorf("astilibav: av_hwdevice_ctx_create failed: %w", NewAvError(int(ret)))
+		return
+	}
+
+	// Make sure the context is freed
+	c.Add(func() error {
+		C.av_buffer_unref(&d.r)
+		return nil
+	})
+	return
+}
+
+// HardwareFramesContext represents a pool of frames living in hardware memory
+// Decoders, filterers and encoders sharing it exchange frames without copying them to system memory
+type HardwareFramesContext struct {
+	r *C.AVBufferRef
+}
+
+// HardwareFramesContextOptions represents hardware frames context options
+type HardwareFramesContextOptions struct {
+	Height int
+	// Number of frames allocated upfront. Some hardware (e.g. QSV) requires a fixed size pool
+	InitialPoolSize int
+	// Hardware pixel format (e.g. AV_PIX_FMT_QSV)
+	PixelFormat avutil.PixelFormat
+	// Pixel format of the frames in hardware memory (e.g. AV_PIX_FMT_NV12)
+	SoftwarePixelFormat avutil.PixelFormat
+	Width               int
+}
+
+// NewHardwareFramesContext creates a new hardware frames context
+func NewHardwareFramesContext(d *HardwareDeviceContext, o HardwareFramesContextOptions, c *astikit.Closer) (*HardwareFramesContext, error) {
+	return newHardwareFramesContext(d, o, nil, c)
+}
+
+func newHardwareFramesContext(d *HardwareDeviceContext, o HardwareFramesContextOptions, fn func(f *C.AVHWFramesContext), c *astikit.Closer) (f *HardwareFramesContext, err error) {
+	// No device
+	if d == nil {
+		err = errors.New("astilibav: no hardware device")
+		return
+	}
+
+	// Alloc context
+	f = &HardwareFramesContext{}
+	if f.r = C.av_hwframe_ctx_alloc(d.r); f.r == nil {
+		err = errors.New("astilibav: av_hwframe_ctx_alloc failed")
+		return
+	}
+
+	// Make sure the context is freed
+	c.Add(func() error {
+		C.av_buffer_unref(&f.r)
+		return nil
+	})
+
+	// Set parameters
+	cf := (*C.AVHWFramesContext)(unsafe.Pointer(f.r.data))
+	cf.format = C.enum_AVPixelFormat(o.PixelFormat)
+	cf.sw_format = C.enum_AVPixelFormat(o.SoftwarePixelFormat)
+	cf.width = C.int(o.Width)
+	cf.height = C.int(o.Height)
+	cf.initial_pool_size = C.int(o.InitialPoolSize)
+	if fn != nil {
+		fn(cf)
+	}
+
+	// Init
+	if ret := C.av_hwframe_ctx_init(f.r); ret < 0 {
+		err = fmt.Errorf("astilibav: av_hwframe_ctx_init failed: %w", NewAvError(int(ret)))
+		return
+	}
+	return
+}
+
+// PixelFormat returns the hardware pixel format of the frames
+func (f *HardwareFramesContext) PixelFormat() avutil.PixelFormat {
+	return avutil.PixelFormat(C.astilibavHardwareFramesFormat(f.r))
+}
+
+func setDecoderHardwareFrames(ctxCodec *avcodec.Context, f *HardwareFramesContext) error {
+	if ret := C.astilibavSetDecoderHardwareFrames((*C.AVCodecContext)(unsafe.Pointer(ctxCodec)), f.r); ret < 0 {
+		return fmt.Errorf("astilibav: setting decoder hardware frames failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+// setDecoderHardwareDevice makes the decoder use the hardware device. Since no hardware frames are provided, the
+// decoder allocates its own
+func setDecoderHardwareDevice(ctxCodec *avcodec.Context, d *HardwareDeviceContext) error {
+	if ret := C.astilibavSetDecoderHardwareDevice((*C.AVCodecContext)(unsafe.Pointer(ctxCodec)), d.r); ret < 0 {
+		return fmt.Errorf("astilibav: setting decoder hardware device failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+// isHardwareFrame returns true if the frame lives in hardware memory
+func isHardwareFrame(f *avutil.Frame) bool {
+	return C.astilibavIsHardwareFrame((*C.AVFrame)(unsafe.Pointer(f))) > 0
+}
+
+// downloadHardwareFrame copies a frame living in hardware memory to system memory. It returns false if the frame
+// already lives in system memory
+func downloadHardwareFrame(dst, src *avutil.Frame) (downloaded bool, err error) {
+	ret := C.astilibavDownloadHardwareFrame((*C.AVFrame)(unsafe.Pointer(dst)), (*C.AVFrame)(unsafe.Pointer(src)))
+	if ret < 0 {
+		err = fmt.Errorf("astilibav: downloading hardware frame failed: %w", NewAvError(int(ret)))
+		return
+	}
+	downloaded = ret > 0
+	return
+}
+
+// uploadHardwareFrame copies a frame living in system memory to the hardware frames. It returns false if the frame
+// already lives in hardware memory
+func uploadHardwareFrame(dst, src *avutil.Frame, f *HardwareFramesContext) (uploaded bool, err error) {
+	ret := C.astilibavUploadHardwareFrame((*C.AVFrame)(unsafe.Pointer(dst)), (*C.AVFrame)(unsafe.Pointer(src)), f.r)
+	if ret < 0 {
+		err = fmt.Errorf("astilibav: uploading hardware frame failed: %w", NewAvError(int(ret)))
+		return
+	}
+	uploaded = ret > 0
+	return
+}
+
+func setEncoderHardwareDevice(ctxCodec *avcodec.Context, d *HardwareDeviceContext) error {
+	if ret := C.astilibavSetEncoderHardwareDevice((*C.AVCodecContext)(unsafe.Pointer(ctxCodec)), d.r); ret < 0 {
+		return fmt.Errorf("astilibav: setting encoder hardware device failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+func setEncoderHardwareFrames(ctxCodec *avcodec.Context, f *HardwareFramesContext) error {
+	if ret := C.astilibavSetEncoderHardwareFrames((*C.AVCodecContext)(unsafe.Pointer(ctxCodec)), f.r); ret < 0 {
+		return fmt.Errorf("astilibav: setting encoder hardware frames failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+func setBufferSrcHardwareFrames(bufferSrcCtx *avfilter.Context, f *HardwareFramesContext) error {
+	if ret := C.astilibavSetBufferSrcHardwareFrames((*C.AVFilterContext)(unsafe.Pointer(bufferSrcCtx)), f.r); ret < 0 {
+		return fmt.Errorf("astilibav: av_buffersrc_parameters_set failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+// setGraphHardwareDevice makes the device of the hardware frames available to all filters of the graph, which is
+// required by filters uploading frames or creating their own hardware frames
+func setGraphHardwareDevice(g *avfilter.Graph, f *HardwareFramesContext) error {
+	if ret := C.astilibavSetGraphHardwareDevice((*C.AVFilterGraph)(unsafe.Pointer(g)), f.r); ret < 0 {
+		return fmt.Errorf("astilibav: setting graph hardware device failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+// newBufferSinkHardwareFrames returns the hardware frames output by the buffer sink or nil if frames are output in
+// system memory
+func newBufferSinkHardwareFrames(bufferSinkCtx *avfilter.Context, c *astikit.Closer) (f *HardwareFramesContext) {
+	r := C.astilibavBufferSinkHardwareFrames((*C.AVFilterContext)(unsafe.Pointer(bufferSinkCtx)))
+	if r == nil {
+		return
+	}
+	f = &HardwareFramesContext{r: r}
+	c.Add(func() error {
+		C.av_buffer_unref(&f.r)
+		return nil
+	})
+	return
+}
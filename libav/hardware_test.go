@@ -0,0 +1,48 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardwareSessions(t *testing.T) {
+	s := newHardwareSessions()
+	assert.True(t, s.acquire(0, 2))
+	assert.True(t, s.acquire(0, 2))
+	assert.False(t, s.acquire(0, 2))
+	assert.True(t, s.acquire(1, 0))
+	assert.Equal(t, map[int]int{0: 2, 1: 1}, s.count())
+	s.release(0)
+	s.release(1)
+	assert.Equal(t, map[int]int{0: 1}, s.count())
+	assert.True(t, s.acquire(0, 2))
+}
+
+type mockedHardwareFrameHandler struct {
+	FrameHandler
+	pixelFormat avutil.PixelFormat
+}
+
+func (h *mockedHardwareFrameHandler) AcceptsHardwareFrames(_ astiencoder.Node, hardwarePixelFormat avutil.PixelFormat) bool {
+	return h.pixelFormat == hardwarePixelFormat
+}
+
+func TestAcceptsHardwareFrames(t *testing.T) {
+	f := avutil.AvFrameAlloc()
+	defer avutil.AvFrameFree(f)
+	f.SetFormat(int(avutil.AV_PIX_FMT_YUV420P))
+
+	// Handler doesn't implement the interface
+	assert.False(t, acceptsHardwareFrames(&LoadShedder{}, nil, f))
+	assert.False(t, acceptsHardwareFrames(&Encoder{}, nil, f))
+
+	// Handler implements the interface
+	assert.True(t, acceptsHardwareFrames(&mockedHardwareFrameHandler{pixelFormat: avutil.AV_PIX_FMT_YUV420P}, nil, f))
+	assert.False(t, acceptsHardwareFrames(&mockedHardwareFrameHandler{pixelFormat: qsvNV12}, nil, f))
+
+	// Frame lives in system memory
+	assert.False(t, isHardwareFrame(f))
+}
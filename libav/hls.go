@@ -0,0 +1,588 @@
+package astilibav
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// HLSPlaylist represents an object capable of writing an HLS media playlist based on the files rotated by a muxer
+// When a part duration is provided, muxer files are considered as low-latency parts that are concatenated into
+// segments
+type HLSPlaylist struct {
+	// Remaining duration of the current break
+	cueOutRemaining time.Duration
+	e               *hlsEncrypter
+	eh              *astiencoder.EventHandler
+	initURL         string
+	m               *sync.Mutex
+	mx              *Muxer
+	o               HLSPlaylistOptions
+	// Cue tags added before the next segment
+	pendingCues []string
+	preloadHint string
+	segments    []*hlsSegment
+	t           *template.Template
+	updated     chan struct{}
+}
+
+// HLSPlaylistOptions represents HLS playlist options
+type HLSPlaylistOptions struct {
+	// If set, segments are encrypted in place. It's not supported in low-latency mode
+	Encryption *HLSEncryptionOptions
+	// If > 0, low-latency mode is enabled and muxer files are considered as parts of segments.
+	// It should match the muxer's rotation duration
+	PartDuration time.Duration
+	// If true, a preload hint is added in low-latency mode. The muxer's url template must only depend on {{.sequence}}
+	PreloadHint bool
+	// Segments duration. In low-latency mode, a segment is created whenever this duration has been reached and an
+	// independent part comes in. Otherwise it should match the muxer's rotation duration
+	SegmentDuration time.Duration
+	// In low-latency mode, the segment url is parsed as a template where {{.sequence}} is the segment media sequence
+	// number. Parts are concatenated into segments.
+	SegmentURL string
+	// Max number of segments in the playlist. 0 means no limit
+	Size int
+	// Path of the playlist
+	URL string
+}
+
+type hlsSegment struct {
+	complete    bool
+	cues        []string
+	duration    time.Duration
+	f           *os.File
+	independent bool
+	key         *HLSKey
+	parts       []hlsPart
+	sequence    int
+	url         string
+}
+
+type hlsPart struct {
+	duration    time.Duration
+	independent bool
+	url         string
+}
+
+// NewHLSPlaylist creates a new HLS playlist
+// The muxer must have rotation enabled
+func NewHLSPlaylist(o HLSPlaylistOptions, m *Muxer, eh *astiencoder.EventHandler) (p *HLSPlaylist, err error) {
+	// Create playlist
+	if p, err = newHLSPlaylist(o, m, eh); err != nil {
+		return
+	}
+
+	// Handle muxer files
+	p.handleFiles(m, MuxerFileCompleted)
+	return
+}
+
+// NewCMAFHLSPlaylist creates a new HLS playlist referencing the segments of a CMAF segmenter
+// Preload hints are not supported since muxer files are only valid segments once they've been split
+func NewCMAFHLSPlaylist(o HLSPlaylistOptions, s *CMAFSegmenter, eh *astiencoder.EventHandler) (p *HLSPlaylist, err error) {
+	// Preload hint is not supported
+	if o.PreloadHint {
+		err = errors.New("astilibav: preload hint is not supported with cmaf")
+		return
+	}
+
+	// Encryption is not supported since segments are shared with other packagers
+	if o.Encryption != nil {
+		err = errors.New("astilibav: encryption is not supported with cmaf")
+		return
+	}
+
+	// Create playlist
+	if p, err = newHLSPlaylist(o, s.mx, eh); err != nil {
+		return
+	}
+	p.initURL = s.InitURL()
+
+	// Handle segments
+	p.handleFiles(s, CMAFSegmenterSegmentCompleted)
+	return
+}
+
+func newHLSPlaylist(o HLSPlaylistOptions, m *Muxer, eh *astiencoder.EventHandler) (p *HLSPlaylist, err error) {
+	// Create playlist
+	p = &HLSPlaylist{
+		eh:      eh,
+		m:       &sync.Mutex{},
+		mx:      m,
+		o:       o,
+		updated: make(chan struct{}),
+	}
+
+	// No rotation
+	if m.rotation == nil {
+		err = errors.New("astilibav: muxer rotation is disabled")
+		return
+	}
+
+	// Files are played back to back
+	m.rotation.o.KeepTimestamps = true
+
+	// No segment duration
+	if o.SegmentDuration <= 0 {
+		err = fmt.Errorf("astilibav: invalid segment duration %s", o.SegmentDuration)
+		return
+	}
+
+	// Low latency
+	if o.PartDuration > 0 {
+		// Parse segment url
+		if p.t, err = template.New("").Parse(o.SegmentURL); err != nil {
+			err = fmt.Errorf("astilibav: parsing segment url %s as template failed: %w", o.SegmentURL, err)
+			return
+		}
+	}
+
+	// Encryption
+	if o.Encryption != nil {
+		// Encryption is not supported in low-latency mode
+		if p.lowLatency() {
+			err = errors.New("astilibav: encryption is not supported in low-latency mode")
+			return
+		}
+
+		// Create encrypter
+		if p.e, err = newHLSEncrypter(*o.Encryption); err != nil {
+			err = fmt.Errorf("astilibav: creating encrypter failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+func (p *HLSPlaylist) handleFiles(target interface{}, eventName string) {
+	p.eh.Add(target, eventName, func(e astiencoder.Event) bool {
+		if err := p.handleFile(e.Payload.(MuxerFile)); err != nil {
+			p.eh.Emit(astiencoder.EventError(p.mx, fmt.Errorf("astilibav: handling file in hls playlist failed: %w", err)))
+		}
+		return false
+	})
+}
+
+// HandleSCTE35Cues adds the cues emitted by the target (e.g. a SCTE35Parser) to the playlist
+func (p *HLSPlaylist) HandleSCTE35Cues(target interface{}) {
+	p.eh.Add(target, SCTE35CueReceived, func(e astiencoder.Event) bool {
+		p.AddCue(e.Payload.(SCTE35Cue))
+		return false
+	})
+}
+
+// AddCue adds an EXT-X-CUE-OUT or EXT-X-CUE-IN tag before the next segment based on a splice insert cue.
+// Other cues are ignored. If a cue-out has a duration and auto return is enabled, the EXT-X-CUE-IN tag is added
+// automatically once the duration has elapsed
+func (p *HLSPlaylist) AddCue(c SCTE35Cue) {
+	// Only splice inserts are translated
+	if c.CommandType != SCTE35CommandTypeSpliceInsert || c.Cancel {
+		return
+	}
+
+	// Lock
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// Add tag
+	if c.OutOfNetwork {
+		if c.Duration > 0 {
+			p.pendingCues = append(p.pendingCues, "#EXT-X-CUE-OUT:DURATION="+hlsSeconds(c.Duration))
+		} else {
+			p.pendingCues = append(p.pendingCues, "#EXT-X-CUE-OUT")
+		}
+		p.cueOutRemaining = 0
+		if c.AutoReturn {
+			p.cueOutRemaining = c.Duration
+		}
+	} else {
+		p.pendingCues = append(p.pendingCues, "#EXT-X-CUE-IN")
+		p.cueOutRemaining = 0
+	}
+}
+
+// updateCueOut adds the EXT-X-CUE-IN tag once the current break has elapsed
+func (p *HLSPlaylist) updateCueOut(d time.Duration) {
+	if p.cueOutRemaining <= 0 {
+		return
+	}
+	if p.cueOutRemaining -= d; p.cueOutRemaining <= 0 {
+		p.pendingCues = append(p.pendingCues, "#EXT-X-CUE-IN")
+	}
+}
+
+func (p *HLSPlaylist) lowLatency() bool {
+	return p.o.PartDuration > 0
+}
+
+func (p *HLSPlaylist) handleFile(f MuxerFile) (err error) {
+	// Lock
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	// Handle file
+	if p.lowLatency() {
+		err = p.addPart(f)
+	} else {
+		// Encrypt
+		var k *HLSKey
+		if p.e != nil {
+			if k, err = p.e.encrypt(f.URL, p.nextSequence()); err != nil {
+				err = fmt.Errorf("astilibav: encrypting %s failed: %w", f.URL, err)
+				return
+			}
+		}
+
+		// Add segment
+		p.addSegment(&hlsSegment{
+			complete:    true,
+			duration:    f.Duration,
+			independent: f.Independent,
+			key:         k,
+			url:         f.URL,
+		})
+		p.updateCueOut(f.Duration)
+	}
+	if err != nil {
+		return
+	}
+
+	// Get preload hint
+	if p.lowLatency() && p.o.PreloadHint {
+		if p.preloadHint, err = p.mx.rotation.url(f.Sequence+1, time.Now()); err != nil {
+			err = fmt.Errorf("astilibav: getting preload hint url failed: %w", err)
+			return
+		}
+	}
+
+	// Write
+	if err = p.write(); err != nil {
+		err = fmt.Errorf("astilibav: writing playlist failed: %w", err)
+		return
+	}
+
+	// Notify blocked requests
+	close(p.updated)
+	p.updated = make(chan struct{})
+	return
+}
+
+func (p *HLSPlaylist) nextSequence() int {
+	if len(p.segments) > 0 {
+		return p.segments[len(p.segments)-1].sequence + 1
+	}
+	return 0
+}
+
+func (p *HLSPlaylist) addSegment(s *hlsSegment) {
+	// Set sequence
+	s.sequence = p.nextSequence()
+
+	// Add pending cues
+	s.cues, p.pendingCues = p.pendingCues, nil
+
+	// Append
+	p.segments = append(p.segments, s)
+
+	// Slide window
+	if p.o.Size > 0 {
+		// Incomplete segments don't count
+		size := p.o.Size
+		if !s.complete {
+			size++
+		}
+		if len(p.segments) > size {
+			p.segments = p.segments[len(p.segments)-size:]
+		}
+	}
+}
+
+func (p *HLSPlaylist) currentSegment() *hlsSegment {
+	if len(p.segments) == 0 || p.segments[len(p.segments)-1].complete {
+		return nil
+	}
+	return p.segments[len(p.segments)-1]
+}
+
+func (p *HLSPlaylist) addPart(f MuxerFile) (err error) {
+	// Segment is long enough and part is independent
+	s := p.currentSegment()
+	if s != nil && s.duration >= p.o.SegmentDuration && f.Independent {
+		if err = p.completeSegment(s); err != nil {
+			err = fmt.Errorf("astilibav: completing segment failed: %w", err)
+			return
+		}
+		s = nil
+	}
+
+	// Create segment
+	if s == nil {
+		// Add segment
+		s = &hlsSegment{independent: f.Independent}
+		p.addSegment(s)
+
+		// Execute template
+		buf := &bytes.Buffer{}
+		if err = p.t.Execute(buf, map[string]interface{}{"sequence": s.sequence}); err != nil {
+			err = fmt.Errorf("astilibav: executing template failed: %w", err)
+			return
+		}
+		s.url = buf.String()
+
+		// Create file
+		if s.f, err = os.Create(s.url); err != nil {
+			err = fmt.Errorf("astilibav: creating %s failed: %w", s.url, err)
+			return
+		}
+	}
+
+	// Add part
+	s.parts = append(s.parts, hlsPart{
+		duration:    f.Duration,
+		independent: f.Independent,
+		url:         f.URL,
+	})
+	s.duration += f.Duration
+
+	// Concatenate part
+	if err = appendFile(s.f, f.URL); err != nil {
+		err = fmt.Errorf("astilibav: appending %s to %s failed: %w", f.URL, s.url, err)
+		return
+	}
+	return
+}
+
+func (p *HLSPlaylist) completeSegment(s *hlsSegment) (err error) {
+	s.complete = true
+	p.updateCueOut(s.duration)
+	if s.f != nil {
+		if err = s.f.Close(); err != nil {
+			err = fmt.Errorf("astilibav: closing %s failed: %w", s.url, err)
+			return
+		}
+		s.f = nil
+	}
+	return
+}
+
+func appendFile(dst *os.File, src string) (err error) {
+	// Open
+	var f *os.File
+	if f, err = os.Open(src); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", src, err)
+		return
+	}
+	defer f.Close()
+
+	// Copy
+	if _, err = io.Copy(dst, f); err != nil {
+		err = fmt.Errorf("astilibav: copying failed: %w", err)
+		return
+	}
+	return
+}
+
+func (p *HLSPlaylist) write() error {
+	return writeFileAtomically(p.o.URL, p.bytes())
+}
+
+// writeFileAtomically writes to a temp file in the same dir before renaming it so that readers never see a partial
+// file
+func writeFileAtomically(url string, b []byte) (err error) {
+	// Create temp file
+	var f *os.File
+	if f, err = ioutil.TempFile(filepath.Dir(url), filepath.Base(url)+".*"); err != nil {
+		err = fmt.Errorf("astilibav: creating temp file failed: %w", err)
+		return
+	}
+
+	// Write
+	_, err = f.Write(b)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		err = fmt.Errorf("astilibav: writing to %s failed: %w", f.Name(), err)
+		return
+	}
+
+	// Rename
+	if err = os.Rename(f.Name(), url); err != nil {
+		os.Remove(f.Name())
+		err = fmt.Errorf("astilibav: renaming %s to %s failed: %w", f.Name(), url, err)
+		return
+	}
+	return
+}
+
+func (p *HLSPlaylist) uri(url string) string {
+	return relativeURI(p.o.URL, url)
+}
+
+// relativeURI returns the uri of url relative to the manifest located at base
+func relativeURI(base, url string) string {
+	if v, err := filepath.Rel(filepath.Dir(base), url); err == nil {
+		return filepath.ToSlash(v)
+	}
+	return filepath.Base(url)
+}
+
+func hlsSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 5, 64)
+}
+
+func (p *HLSPlaylist) bytes() []byte {
+	// Get target duration
+	targetDuration := p.o.SegmentDuration
+	for _, s := range p.segments {
+		if s.complete && s.duration > targetDuration {
+			targetDuration = s.duration
+		}
+	}
+
+	// Header
+	buf := &bytes.Buffer{}
+	buf.WriteString("#EXTM3U\n")
+	buf.WriteString("#EXT-X-VERSION:6\n")
+	buf.WriteString("#EXT-X-TARGETDURATION:" + strconv.Itoa(int(math.Ceil(targetDuration.Seconds()))) + "\n")
+	if len(p.segments) > 0 {
+		buf.WriteString("#EXT-X-MEDIA-SEQUENCE:" + strconv.Itoa(p.segments[0].sequence) + "\n")
+	}
+	if p.lowLatency() {
+		buf.WriteString("#EXT-X-SERVER-CONTROL:CAN-BLOCK-RELOAD=YES,PART-HOLD-BACK=" + hlsSeconds(3*p.o.PartDuration) + "\n")
+		buf.WriteString("#EXT-X-PART-INF:PART-TARGET=" + hlsSeconds(p.o.PartDuration) + "\n")
+	}
+	if p.initURL != "" {
+		buf.WriteString("#EXT-X-MAP:URI=\"" + p.uri(p.initURL) + "\"\n")
+	}
+
+	// Loop through segments
+	var k *HLSKey
+	for idx, s := range p.segments {
+		// Add cues
+		for _, c := range s.cues {
+			buf.WriteString(c + "\n")
+		}
+
+		// Parts are only listed for the last segments
+		if p.lowLatency() && idx >= len(p.segments)-4 {
+			for _, pt := range s.parts {
+				buf.WriteString("#EXT-X-PART:DURATION=" + hlsSeconds(pt.duration) + ",URI=\"" + p.uri(pt.url) + "\"")
+				if pt.independent {
+					buf.WriteString(",INDEPENDENT=YES")
+				}
+				buf.WriteString("\n")
+			}
+		}
+
+		// Segment is not complete
+		if !s.complete {
+			continue
+		}
+
+		// Add key
+		if s.key != nil && s.key != k {
+			buf.WriteString(p.keyLine(s.key))
+			k = s.key
+		}
+
+		// Add segment
+		buf.WriteString("#EXTINF:" + hlsSeconds(s.duration) + ",\n")
+		buf.WriteString(p.uri(s.url) + "\n")
+	}
+
+	// Add preload hint
+	if p.preloadHint != "" {
+		buf.WriteString("#EXT-X-PRELOAD-HINT:TYPE=PART,URI=\"" + p.uri(p.preloadHint) + "\"\n")
+	}
+	return buf.Bytes()
+}
+
+// hasPart checks whether the playlist contains the specified media sequence number and part index. A negative part
+// index means the segment must be complete
+func (p *HLSPlaylist) hasPart(msn, part int) bool {
+	// No segments
+	if len(p.segments) == 0 {
+		return false
+	}
+
+	// Get last segment
+	s := p.segments[len(p.segments)-1]
+
+	// Check segment
+	if part < 0 {
+		if !s.complete {
+			return s.sequence-1 >= msn
+		}
+		return s.sequence >= msn
+	}
+
+	// Check part
+	if s.sequence != msn {
+		return s.sequence > msn
+	}
+	return len(s.parts)-1 >= part
+}
+
+// ServeHTTP implements the http.Handler interface
+// It supports blocking playlist reload through the _HLS_msn and _HLS_part query parameters
+func (p *HLSPlaylist) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	// Parse query
+	msn, part := -1, -1
+	if v := r.URL.Query().Get("_HLS_msn"); v != "" {
+		var err error
+		if msn, err = strconv.Atoi(v); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if v = r.URL.Query().Get("_HLS_part"); v != "" {
+			if part, err = strconv.Atoi(v); err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	// Wait for the requested part
+	timeout := time.NewTimer(3 * p.o.SegmentDuration)
+	defer timeout.Stop()
+	for {
+		// Lock
+		p.m.Lock()
+
+		// Playlist is ready
+		if msn < 0 || p.hasPart(msn, part) {
+			b := p.bytes()
+			p.m.Unlock()
+			rw.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			rw.Write(b)
+			return
+		}
+
+		// Get updated chan
+		c := p.updated
+		p.m.Unlock()
+
+		// Wait
+		select {
+		case <-c:
+		case <-r.Context().Done():
+			return
+		case <-timeout.C:
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package astilibav
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// HLS encryption methods
+const (
+	HLSEncryptionMethodAES128 = "AES-128"
+)
+
+// HLSEncryptionOptions represents HLS encryption options
+type HLSEncryptionOptions struct {
+	// Provides keys. Use NewHLSKeyGenerator to generate keys locally
+	KeyProvider HLSKeyProvider
+	// Only AES-128 is supported. SAMPLE-AES requires encrypting samples inside the container and is not supported.
+	// Default is AES-128
+	Method string
+	// Number of segments encrypted with the same key before rotating it. 0 means the key never rotates
+	RotationInterval int
+}
+
+// HLSKey represents an HLS key
+type HLSKey struct {
+	// If empty, the media sequence number of the segment is used as IV
+	IV []byte
+	// 16 bytes key
+	Key []byte
+	// URI clients can retrieve the key from. Paths are made relative to the playlist
+	URI string
+}
+
+// HLSKeyProvider represents an object capable of providing HLS keys
+// The index is incremented every time the key rotates
+type HLSKeyProvider interface {
+	HLSKey(index int) (HLSKey, error)
+}
+
+// SecretHLSKeyProvider represents an object capable of providing HLS keys stored as hex strings by a secret provider,
+// e.g. a DRM key server
+// The secret is read every time the key rotates so that keys can be rotated by updating the secret
+type SecretHLSKeyProvider struct {
+	p   astiencoder.SecretProvider
+	ref string
+	uri string
+}
+
+// NewSecretHLSKeyProvider creates a new secret HLS key provider. Clients retrieve the key from the URI
+func NewSecretHLSKeyProvider(p astiencoder.SecretProvider, ref, uri string) *SecretHLSKeyProvider {
+	return &SecretHLSKeyProvider{
+		p:   p,
+		ref: ref,
+		uri: uri,
+	}
+}
+
+// HLSKey implements the HLSKeyProvider interface
+func (p *SecretHLSKeyProvider) HLSKey(index int) (k HLSKey, err error) {
+	// Get secret
+	var s string
+	if s, err = p.p.Secret(p.ref); err != nil {
+		err = fmt.Errorf("astilibav: getting secret %s failed: %w", p.ref, err)
+		return
+	}
+
+	// Decode key
+	if k.Key, err = hex.DecodeString(strings.TrimSpace(s)); err != nil {
+		err = fmt.Errorf("astilibav: decoding secret %s failed: %w", p.ref, err)
+		return
+	} else if len(k.Key) != 16 {
+		err = fmt.Errorf("astilibav: secret %s is a %d bytes key, expected 16 bytes", p.ref, len(k.Key))
+		return
+	}
+	k.URI = p.uri
+	return
+}
+
+// HLSKeyGenerator represents an object capable of generating random HLS keys and of writing them to disk
+type HLSKeyGenerator struct {
+	keys map[int]HLSKey
+	m    *sync.Mutex
+	o    HLSKeyGeneratorOptions
+	t    *template.Template
+}
+
+// HLSKeyGeneratorOptions represents HLS key generator options
+type HLSKeyGeneratorOptions struct {
+	// Path the key is written to. It's parsed as a template where {{.index}} is the key index
+	URL string
+	// URI clients can retrieve the key from. It's parsed as a template where {{.index}} is the key index.
+	// Default is the path
+	URI string
+}
+
+// NewHLSKeyGenerator creates a new HLS key generator
+func NewHLSKeyGenerator(o HLSKeyGeneratorOptions) (g *HLSKeyGenerator, err error) {
+	// Create generator
+	g = &HLSKeyGenerator{
+		keys: make(map[int]HLSKey),
+		m:    &sync.Mutex{},
+		o:    o,
+	}
+
+	// Parse template
+	if g.t, err = template.New("url").Parse(o.URL); err != nil {
+		err = fmt.Errorf("astilibav: parsing url %s as template failed: %w", o.URL, err)
+		return
+	}
+	if o.URI != "" {
+		if _, err = g.t.New("uri").Parse(o.URI); err != nil {
+			err = fmt.Errorf("astilibav: parsing uri %s as template failed: %w", o.URI, err)
+			return
+		}
+	}
+	return
+}
+
+func (g *HLSKeyGenerator) execute(name string, index int) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := g.t.ExecuteTemplate(buf, name, map[string]interface{}{"index": index}); err != nil {
+		return "", fmt.Errorf("astilibav: executing template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// HLSKey implements the HLSKeyProvider interface
+func (g *HLSKeyGenerator) HLSKey(index int) (k HLSKey, err error) {
+	// Lock
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	// Key has already been generated
+	var ok bool
+	if k, ok = g.keys[index]; ok {
+		return
+	}
+
+	// Generate key
+	k.Key = make([]byte, 16)
+	if _, err = rand.Read(k.Key); err != nil {
+		err = fmt.Errorf("astilibav: generating key failed: %w", err)
+		return
+	}
+
+	// Get url
+	var url string
+	if url, err = g.execute("url", index); err != nil {
+		err = fmt.Errorf("astilibav: getting url failed: %w", err)
+		return
+	}
+
+	// Get uri
+	if g.o.URI != "" {
+		if k.URI, err = g.execute("uri", index); err != nil {
+			err = fmt.Errorf("astilibav: getting uri failed: %w", err)
+			return
+		}
+	} else {
+		k.URI = url
+	}
+
+	// Write key
+	if err = writeFileAtomically(url, k.Key); err != nil {
+		err = fmt.Errorf("astilibav: writing key failed: %w", err)
+		return
+	}
+
+	// Only the current key is kept in memory
+	g.keys = map[int]HLSKey{index: k}
+	return
+}
+
+type hlsEncrypter struct {
+	index int
+	key   *HLSKey
+	o     HLSEncryptionOptions
+}
+
+func newHLSEncrypter(o HLSEncryptionOptions) (e *hlsEncrypter, err error) {
+	// Default method
+	if o.Method == "" {
+		o.Method = HLSEncryptionMethodAES128
+	}
+
+	// Invalid method
+	if o.Method != HLSEncryptionMethodAES128 {
+		err = fmt.Errorf("astilibav: encryption method %s is not supported", o.Method)
+		return
+	}
+
+	// No key provider
+	if o.KeyProvider == nil {
+		err = errors.New("astilibav: no key provider")
+		return
+	}
+
+	// Create encrypter
+	e = &hlsEncrypter{
+		index: -1,
+		o:     o,
+	}
+	return
+}
+
+// encrypt encrypts the file in place and returns the key it has been encrypted with
+func (e *hlsEncrypter) encrypt(url string, sequence int) (k *HLSKey, err error) {
+	// Get key index
+	idx := 0
+	if e.o.RotationInterval > 0 {
+		idx = sequence / e.o.RotationInterval
+	}
+
+	// Get key
+	if e.key == nil || idx != e.index {
+		var v HLSKey
+		if v, err = e.o.KeyProvider.HLSKey(idx); err != nil {
+			err = fmt.Errorf("astilibav: getting key %d failed: %w", idx, err)
+			return
+		}
+		e.index, e.key = idx, &v
+	}
+	k = e.key
+
+	// Read file
+	var b []byte
+	if b, err = ioutil.ReadFile(url); err != nil {
+		err = fmt.Errorf("astilibav: reading %s failed: %w", url, err)
+		return
+	}
+
+	// Encrypt
+	if b, err = hlsEncryptAES128(b, k.Key, hlsIV(k, sequence)); err != nil {
+		err = fmt.Errorf("astilibav: encrypting %s failed: %w", url, err)
+		return
+	}
+
+	// Write file
+	if err = ioutil.WriteFile(url, b, 0666); err != nil {
+		err = fmt.Errorf("astilibav: writing %s failed: %w", url, err)
+		return
+	}
+	return
+}
+
+func hlsIV(k *HLSKey, sequence int) []byte {
+	if len(k.IV) > 0 {
+		return k.IV
+	}
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], uint64(sequence))
+	return iv
+}
+
+// hlsEncryptAES128 encrypts the data in AES-128-CBC with PKCS7 padding
+func hlsEncryptAES128(src, key, iv []byte) (dst []byte, err error) {
+	// Create cipher
+	var b cipher.Block
+	if b, err = aes.NewCipher(key); err != nil {
+		err = fmt.Errorf("astilibav: creating cipher failed: %w", err)
+		return
+	}
+
+	// Invalid iv
+	if len(iv) != aes.BlockSize {
+		err = fmt.Errorf("astilibav: invalid iv length %d", len(iv))
+		return
+	}
+
+	// Pad
+	n := aes.BlockSize - len(src)%aes.BlockSize
+	dst = make([]byte, len(src)+n)
+	copy(dst, src)
+	copy(dst[len(src):], bytes.Repeat([]byte{byte(n)}, n))
+
+	// Encrypt
+	cipher.NewCBCEncrypter(b, iv).CryptBlocks(dst, dst)
+	return
+}
+
+func (p *HLSPlaylist) keyLine(k *HLSKey) string {
+	// Absolute uris are kept as is
+	uri := k.URI
+	if !strings.Contains(uri, "://") {
+		uri = p.uri(uri)
+	}
+
+	// Create line
+	l := "#EXT-X-KEY:METHOD=" + HLSEncryptionMethodAES128 + ",URI=\"" + uri + "\""
+	if len(k.IV) > 0 {
+		l += ",IV=0x" + hex.EncodeToString(k.IV)
+	}
+	return l + "\n"
+}
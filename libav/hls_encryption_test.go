@@ -0,0 +1,84 @@
+package astilibav
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLSEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "astilibav-hls-encryption-test-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	g, err := NewHLSKeyGenerator(HLSKeyGeneratorOptions{URL: filepath.Join(dir, "k{{.index}}.key")})
+	assert.NoError(t, err)
+	e, err := newHLSEncrypter(HLSEncryptionOptions{KeyProvider: g, RotationInterval: 2})
+	assert.NoError(t, err)
+	p := &HLSPlaylist{
+		e: e,
+		m: &sync.Mutex{},
+		o: HLSPlaylistOptions{
+			SegmentDuration: 2 * time.Second,
+			URL:             filepath.Join(dir, "index.m3u8"),
+		},
+	}
+
+	src := []byte("segment content")
+	for i := 0; i < 3; i++ {
+		url := filepath.Join(dir, "s"+string(rune('0'+i))+".ts")
+		assert.NoError(t, ioutil.WriteFile(url, src, 0666))
+		k, err := p.e.encrypt(url, p.nextSequence())
+		assert.NoError(t, err)
+		p.addSegment(&hlsSegment{complete: true, duration: 2 * time.Second, key: k, url: url})
+	}
+
+	k, err := ioutil.ReadFile(filepath.Join(dir, "k1.key"))
+	assert.NoError(t, err)
+	b, err := ioutil.ReadFile(filepath.Join(dir, "s2.ts"))
+	assert.NoError(t, err)
+	c, err := aes.NewCipher(k)
+	assert.NoError(t, err)
+	iv := make([]byte, 16)
+	iv[15] = 2
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(b, b)
+	assert.Equal(t, src, b[:len(b)-int(b[len(b)-1])])
+
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-TARGETDURATION:2
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-KEY:METHOD=AES-128,URI="k0.key"
+#EXTINF:2.00000,
+s0.ts
+#EXTINF:2.00000,
+s1.ts
+#EXT-X-KEY:METHOD=AES-128,URI="k1.key"
+#EXTINF:2.00000,
+s2.ts
+`, string(p.bytes()))
+
+	_, err = newHLSEncrypter(HLSEncryptionOptions{KeyProvider: g, Method: "SAMPLE-AES"})
+	assert.Error(t, err)
+}
+
+func TestSecretHLSKeyProvider(t *testing.T) {
+	secret := "000102030405060708090a0b0c0d0e0f"
+	p := NewSecretHLSKeyProvider(astiencoder.SecretProviderFunc(func(ref string) (string, error) { return secret, nil }), "key", "https://host/key")
+	k, err := p.HLSKey(0)
+	assert.NoError(t, err)
+	assert.Equal(t, HLSKey{Key: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, URI: "https://host/key"}, k)
+
+	// Rotated secrets are taken into account
+	secret = "0001"
+	_, err = p.HLSKey(1)
+	assert.EqualError(t, err, "astilibav: secret key is a 2 bytes key, expected 16 bytes")
+}
@@ -0,0 +1,74 @@
+package astilibav
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLSPlaylist(t *testing.T) {
+	p := &HLSPlaylist{
+		m: &sync.Mutex{},
+		o: HLSPlaylistOptions{
+			PartDuration:    time.Second,
+			SegmentDuration: 2 * time.Second,
+			Size:            2,
+			URL:             "/tmp/hls/index.m3u8",
+		},
+	}
+	p.addSegment(&hlsSegment{complete: true, duration: 2 * time.Second, parts: []hlsPart{
+		{duration: time.Second, independent: true, url: "/tmp/hls/p1.ts"},
+		{duration: time.Second, url: "/tmp/hls/p2.ts"},
+	}, url: "/tmp/hls/s0.ts"})
+	p.addSegment(&hlsSegment{parts: []hlsPart{
+		{duration: time.Second, independent: true, url: "/tmp/hls/p3.ts"},
+	}, url: "/tmp/hls/s1.ts"})
+	p.preloadHint = "/tmp/hls/p4.ts"
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-TARGETDURATION:2
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-SERVER-CONTROL:CAN-BLOCK-RELOAD=YES,PART-HOLD-BACK=3.00000
+#EXT-X-PART-INF:PART-TARGET=1.00000
+#EXT-X-PART:DURATION=1.00000,URI="p1.ts",INDEPENDENT=YES
+#EXT-X-PART:DURATION=1.00000,URI="p2.ts"
+#EXTINF:2.00000,
+s0.ts
+#EXT-X-PART:DURATION=1.00000,URI="p3.ts",INDEPENDENT=YES
+#EXT-X-PRELOAD-HINT:TYPE=PART,URI="p4.ts"
+`, string(p.bytes()))
+	assert.True(t, p.hasPart(0, -1))
+	assert.False(t, p.hasPart(1, -1))
+	assert.True(t, p.hasPart(1, 0))
+	assert.False(t, p.hasPart(1, 1))
+}
+
+func TestHLSPlaylistCues(t *testing.T) {
+	p := &HLSPlaylist{
+		m: &sync.Mutex{},
+		o: HLSPlaylistOptions{
+			SegmentDuration: 2 * time.Second,
+			URL:             "/tmp/hls/index.m3u8",
+		},
+	}
+	p.AddCue(NewSCTE35CueOut(1, 4*time.Second))
+	for i := 0; i < 3; i++ {
+		p.addSegment(&hlsSegment{complete: true, duration: 2 * time.Second, url: "/tmp/hls/s" + string(rune('0'+i)) + ".ts"})
+		p.updateCueOut(2 * time.Second)
+	}
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-TARGETDURATION:2
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-CUE-OUT:DURATION=4.00000
+#EXTINF:2.00000,
+s0.ts
+#EXTINF:2.00000,
+s1.ts
+#EXT-X-CUE-IN
+#EXTINF:2.00000,
+s2.ts
+`, string(p.bytes()))
+}
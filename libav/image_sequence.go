@@ -0,0 +1,76 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avformat"
+	"github.com/asticode/goav/avutil"
+)
+
+// ImageSequenceInput represents a demuxer reading a numbered image sequence (e.g. PNG, JPEG or EXR files) at a
+// target frame rate through ffmpeg's image2 input format
+// Each packet holds one image and is turned into a frame by connecting a decoder
+type ImageSequenceInput struct {
+	*Demuxer
+}
+
+// ImageSequenceInputOptions represents image sequence input options
+type ImageSequenceInputOptions struct {
+	// Format, Dict and URL are set by the input
+	Demuxer DemuxerOptions
+	// Default is 25
+	FrameRate avutil.Rational
+	// Either a printf-like pattern (e.g. "render/frame_%04d.exr") or a glob pattern (e.g. "render/*.png")
+	Pattern string
+	// Number of the first image. If 0, the first image is searched between 0 and 4
+	StartNumber int
+}
+
+// NewImageSequenceInput creates a new image sequence input
+func NewImageSequenceInput(o ImageSequenceInputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (i *ImageSequenceInput, err error) {
+	// No pattern
+	if o.Pattern == "" {
+		err = errors.New("astilibav: no pattern")
+		return
+	}
+
+	// Find format
+	if o.Demuxer.Format = avformat.AvFindInputFormat("image2"); o.Demuxer.Format == nil {
+		err = errors.New("astilibav: image2 input format not found")
+		return
+	}
+
+	// Create demuxer
+	i = &ImageSequenceInput{}
+	o.Demuxer.Dict = NewDict(imageSequenceDict(o), "=", "&", 0)
+	o.Demuxer.URL = o.Pattern
+	if i.Demuxer, err = NewDemuxer(o.Demuxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+	return
+}
+
+func imageSequenceDict(o ImageSequenceInputOptions) string {
+	// Frame rate
+	r := o.FrameRate
+	if r.Num() <= 0 || r.Den() <= 0 {
+		r = avutil.NewRational(25, 1)
+	}
+	d := []string{fmt.Sprintf("framerate=%d/%d", r.Num(), r.Den())}
+
+	// Pattern type
+	if !strings.Contains(o.Pattern, "%") && strings.ContainsAny(o.Pattern, "*?[") {
+		d = append(d, "pattern_type=glob")
+	} else {
+		d = append(d, "pattern_type=sequence")
+		if o.StartNumber > 0 {
+			d = append(d, fmt.Sprintf("start_number=%d", o.StartNumber))
+		}
+	}
+	return strings.Join(d, "&")
+}
@@ -0,0 +1,14 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageSequenceDict(t *testing.T) {
+	assert.Equal(t, "framerate=25/1&pattern_type=sequence", imageSequenceDict(ImageSequenceInputOptions{Pattern: "frame_%04d.png"}))
+	assert.Equal(t, "framerate=30000/1001&pattern_type=sequence&start_number=1001", imageSequenceDict(ImageSequenceInputOptions{FrameRate: avutil.NewRational(30000, 1001), Pattern: "frame_%04d.exr", StartNumber: 1001}))
+	assert.Equal(t, "framerate=25/1&pattern_type=glob", imageSequenceDict(ImageSequenceInputOptions{Pattern: "render/*.jpg"}))
+}
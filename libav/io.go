@@ -0,0 +1,120 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavformat libavutil
+#include <libavformat/avformat.h>
+#include <libavutil/mem.h>
+#include <stdlib.h>
+
+extern int goAstilibavIORead(void *opaque, uint8_t *buf, int size);
+extern int goAstilibavIOWrite(void *opaque, uint8_t *buf, int size);
+extern int64_t goAstilibavIOSeek(void *opaque, int64_t offset, int whence);
+
+static AVIOContext* astilibavAllocIOContext(int size, int writable, int seekable, void *opaque) {
+	unsigned char *buffer = av_malloc(size);
+	if (!buffer) return NULL;
+	AVIOContext *ctx = avio_alloc_context(buffer, size, writable, opaque, writable ? NULL : goAstilibavIORead, writable ? goAstilibavIOWrite : NULL, seekable ? goAstilibavIOSeek : NULL);
+	if (!ctx) av_free(buffer);
+	return ctx;
+}
+
+static void astilibavFreeIOContext(AVIOContext *ctx) {
+	if (ctx->write_flag) avio_flush(ctx);
+	av_freep(&ctx->buffer);
+	avio_context_free(&ctx);
+}
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+
+	"github.com/asticode/goav/avformat"
+)
+
+const ioBufferSize = 32768
+
+var ioContexts = struct {
+	cs map[int]*ioContext
+	id int
+	m  *sync.Mutex
+}{
+	cs: make(map[int]*ioContext),
+	m:  &sync.Mutex{},
+}
+
+// ioContext represents a custom AVIO context reading from an io.Reader or writing to an io.Writer
+// Seeking is supported when the reader or the writer implements io.Seeker
+type ioContext struct {
+	c      *C.AVIOContext
+	id     int
+	opaque unsafe.Pointer
+	r      io.Reader
+	s      io.Seeker
+	w      io.Writer
+}
+
+func newIOContext(r io.Reader, w io.Writer) (i *ioContext, err error) {
+	// Create io context
+	i = &ioContext{
+		r: r,
+		w: w,
+	}
+
+	// Get seeker
+	var writable int
+	if w != nil {
+		writable = 1
+		i.s, _ = w.(io.Seeker)
+	} else {
+		i.s, _ = r.(io.Seeker)
+	}
+	var seekable int
+	if i.s != nil {
+		seekable = 1
+	}
+
+	// Register
+	// Go pointers can't be stored in C memory, therefore C only knows about the id
+	ioContexts.m.Lock()
+	ioContexts.id++
+	i.id = ioContexts.id
+	ioContexts.cs[i.id] = i
+	ioContexts.m.Unlock()
+	i.opaque = C.malloc(C.sizeof_int)
+	*(*C.int)(i.opaque) = C.int(i.id)
+
+	// Alloc
+	if i.c = C.astilibavAllocIOContext(ioBufferSize, C.int(writable), C.int(seekable), i.opaque); i.c == nil {
+		i.close()
+		err = errors.New("astilibav: allocating io context failed")
+		return
+	}
+	return
+}
+
+func ioContextFromOpaque(opaque unsafe.Pointer) *ioContext {
+	ioContexts.m.Lock()
+	defer ioContexts.m.Unlock()
+	return ioContexts.cs[int(*(*C.int)(opaque))]
+}
+
+func (i *ioContext) avIOContext() *avformat.AvIOContext {
+	return (*avformat.AvIOContext)(unsafe.Pointer(i.c))
+}
+
+func (i *ioContext) close() {
+	// Free
+	if i.c != nil {
+		C.astilibavFreeIOContext(i.c)
+		i.c = nil
+	}
+	C.free(i.opaque)
+
+	// Unregister
+	ioContexts.m.Lock()
+	delete(ioContexts.cs, i.id)
+	ioContexts.m.Unlock()
+}
@@ -0,0 +1,86 @@
+package astilibav
+
+//#include <libavformat/avio.h>
+import "C"
+import (
+	"io"
+	"unsafe"
+
+	"github.com/asticode/goav/avutil"
+)
+
+//export goAstilibavIORead
+func goAstilibavIORead(opaque unsafe.Pointer, buf *C.uint8_t, size C.int) C.int {
+	// Get io context
+	i := ioContextFromOpaque(opaque)
+	if i == nil || i.r == nil {
+		return C.int(avutil.AVERROR_EIO)
+	}
+
+	// Read
+	// libav considers 0 as the end of the input
+	b := (*[1 << 30]byte)(unsafe.Pointer(buf))[:size:size]
+	var n int
+	var err error
+	for n == 0 && err == nil {
+		n, err = i.r.Read(b)
+	}
+	if n > 0 {
+		return C.int(n)
+	} else if err == io.EOF {
+		return C.int(avutil.AVERROR_EOF)
+	}
+	return C.int(avutil.AVERROR_EIO)
+}
+
+//export goAstilibavIOWrite
+func goAstilibavIOWrite(opaque unsafe.Pointer, buf *C.uint8_t, size C.int) C.int {
+	// Get io context
+	i := ioContextFromOpaque(opaque)
+	if i == nil || i.w == nil {
+		return C.int(avutil.AVERROR_EIO)
+	}
+
+	// Write
+	if _, err := i.w.Write((*[1 << 30]byte)(unsafe.Pointer(buf))[:size:size]); err != nil {
+		return C.int(avutil.AVERROR_EIO)
+	}
+	return size
+}
+
+//export goAstilibavIOSeek
+func goAstilibavIOSeek(opaque unsafe.Pointer, offset C.int64_t, whence C.int) C.int64_t {
+	// Get io context
+	i := ioContextFromOpaque(opaque)
+	if i == nil || i.s == nil {
+		return C.int64_t(avutil.AVERROR_EIO)
+	}
+
+	// Size is requested
+	if whence&C.AVSEEK_SIZE > 0 {
+		// Get current position
+		cur, err := i.s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+
+		// Get end position
+		end, err := i.s.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+
+		// Go back to current position
+		if _, err = i.s.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return C.int64_t(end)
+	}
+
+	// Seek
+	n, err := i.s.Seek(int64(offset), int(whence&^C.AVSEEK_FORCE))
+	if err != nil {
+		return C.int64_t(avutil.AVERROR_EIO)
+	}
+	return C.int64_t(n)
+}
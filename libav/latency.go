@@ -0,0 +1,68 @@
+package astilibav
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Latency is measured with probes: the demuxer stamps 1 pkt per stream and per probe period with the time at which
+// it has been demuxed, the stamp follows the pkt through the media path the same way traced GOPs do and muxers
+// compare it to the time at which the pkt has been written
+
+type latencyContextKey struct{}
+
+// withDemuxedAt marks an object as a latency probe demuxed at t
+func withDemuxedAt(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, latencyContextKey{}, t)
+}
+
+// demuxedAtFromContext returns the time at which the object has been demuxed if it is a latency probe
+func demuxedAtFromContext(ctx context.Context) (t time.Time, ok bool) {
+	if ctx == nil {
+		return
+	}
+	t, ok = ctx.Value(latencyContextKey{}).(time.Time)
+	return
+}
+
+// latencyStat computes the average latency of the probes received since the last time its value has been retrieved
+// If no probe has been received in the meantime, the previous value is kept
+type latencyStat struct {
+	last float64
+	m    *sync.Mutex
+	n    int
+	sum  time.Duration
+}
+
+func newLatencyStat() *latencyStat {
+	return &latencyStat{m: &sync.Mutex{}}
+}
+
+func (s *latencyStat) add(d time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.n++
+	s.sum += d
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *latencyStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *latencyStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *latencyStat) Value(delta time.Duration) interface{} {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Compute average
+	if s.n > 0 {
+		s.last = float64(s.sum) / float64(s.n) / float64(time.Millisecond)
+		s.n = 0
+		s.sum = 0
+	}
+	return s.last
+}
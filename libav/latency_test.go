@@ -0,0 +1,43 @@
+package astilibav
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatency(t *testing.T) {
+	// Context
+	_, ok := demuxedAtFromContext(nil)
+	assert.False(t, ok)
+	_, ok = demuxedAtFromContext(context.Background())
+	assert.False(t, ok)
+	now := time.Now()
+	ctx := withDemuxedAt(context.Background(), now)
+	v, ok := demuxedAtFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, now, v)
+
+	// Stat
+	s := newLatencyStat()
+	assert.Equal(t, 0.0, s.Value(time.Second))
+	s.add(10 * time.Millisecond)
+	s.add(20 * time.Millisecond)
+	assert.Equal(t, 15.0, s.Value(time.Second))
+	assert.Equal(t, 15.0, s.Value(time.Second))
+
+	// Pending spans
+	ps := newPendingSpans()
+	ps.start(context.Background(), nil, SpanNameDecode, 1)
+	ps.start(ctx, nil, SpanNameDecode, 2)
+	c, end := ps.take(1)
+	assert.Nil(t, c)
+	end()
+	c, end = ps.take(2)
+	v, ok = demuxedAtFromContext(c)
+	assert.True(t, ok)
+	assert.Equal(t, now, v)
+	end()
+}
@@ -0,0 +1,261 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/frame.h>
+
+static void astilibavFramePictureInfo(AVFrame *f, int *keyFrame, int *pictType) {
+	*keyFrame = f->key_frame;
+	*pictType = f->pict_type;
+}
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+var countLoadShedder uint64
+
+// LoadShedder represents an object capable of dropping video frames when the pipeline falls behind realtime so that
+// live outputs stay on-air instead of drifting ever later
+// Lateness is the difference between the wall clock time and the media time elapsed since the first frame. Audio
+// frames are never dropped
+type LoadShedder struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *frameDispatcher
+	drops            *frameDrops
+	eh               *astiencoder.EventHandler
+	o                LoadShedderOptions
+	ref              *loadShedderRef
+	shedding         bool
+	statDroppedRate  *astikit.CounterRateStat
+	statIncomingRate *astikit.CounterRateStat
+	statLateness     *astikit.CounterAvgStat
+}
+
+type loadShedderRef struct {
+	at  time.Time
+	pts time.Duration
+}
+
+// LoadShedderOptions represents load shedder options
+type LoadShedderOptions struct {
+	Drops FrameDropsOptions
+	// Lateness above which all video frames but key frames are dropped. Default is twice Threshold
+	LateThreshold time.Duration
+	Node          astiencoder.NodeOptions
+	OutputCtx     Context
+	// Lateness above which non-reference video frames (i.e. B-frames) are dropped. Default is 500ms
+	Threshold time.Duration
+}
+
+// LoadShedding represents the payload of load shedder events
+type LoadShedding struct {
+	Lateness time.Duration
+}
+
+// NewLoadShedder creates a new load shedder
+func NewLoadShedder(o LoadShedderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (s *LoadShedder) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countLoadShedder, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("load_shedder_%d", count), fmt.Sprintf("Load Shedder #%d", count), "Sheds load", "load shedder")
+
+	// Default options
+	if o.Threshold <= 0 {
+		o.Threshold = 500 * time.Millisecond
+	}
+	if o.LateThreshold <= 0 {
+		o.LateThreshold = 2 * o.Threshold
+	}
+
+	// Create load shedder
+	s = &LoadShedder{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               eh,
+		o:                o,
+		statDroppedRate:  astikit.NewCounterRateStat(),
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statLateness:     astikit.NewCounterAvgStat(),
+	}
+	s.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(s), eh)
+	s.d = newFrameDispatcher(s, eh)
+	s.drops = newFrameDrops(s, o.Drops, eh, []string{FrameDropReasonLate, FrameDropReasonNonReference}, nil)
+	s.addStats()
+	return
+}
+
+func (s *LoadShedder) addStats() {
+	// Add incoming rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, s.statIncomingRate)
+
+	// Add dropped rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames dropped per second",
+		Label:       "Dropped rate",
+		Unit:        "fps",
+	}, s.statDroppedRate)
+
+	// Add lateness
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Average lateness compared to realtime",
+		Label:       "Lateness",
+		Unit:        "ms",
+	}, s.statLateness)
+
+	// Add frame drops stats
+	s.drops.addStats(s.Stater())
+
+	// Add dispatcher stats
+	s.d.addStats(s.Stater())
+
+	// Add chan stats
+	s.c.AddStats(s.Stater())
+}
+
+// OutputCtx returns the output ctx
+func (s *LoadShedder) OutputCtx() Context {
+	return s.o.OutputCtx
+}
+
+// Connect implements the FrameHandlerConnector interface
+func (s *LoadShedder) Connect(h FrameHandler) {
+	// Add handler
+	s.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(s, h)
+}
+
+// Disconnect implements the FrameHandlerConnector interface
+func (s *LoadShedder) Disconnect(h FrameHandler) {
+	// Delete handler
+	s.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(s, h)
+}
+
+// Start starts the load shedder
+func (s *LoadShedder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	s.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer s.d.wait()
+
+		// Make sure to stop the chan properly
+		defer s.c.Stop()
+
+		// Start chan
+		s.c.Start(s.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (s *LoadShedder) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, s); p == nil {
+		return
+	}
+	s.c.AddWithRelease(func() {
+		// Handle pause
+		defer s.HandlePause()
+
+		// Increment incoming rate
+		now := time.Now()
+		s.statIncomingRate.Add(1)
+		s.drops.incoming(now)
+
+		// Audio frames are never dropped
+		if p.Frame.NbSamples() > 0 {
+			s.d.dispatch(p.Frame, p.Descriptor)
+			return
+		}
+
+		// Get lateness
+		lateness := s.lateness(now, time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational)))
+		s.statLateness.Add(float64(lateness) / float64(time.Millisecond))
+
+		// Update status
+		s.updateShedding(lateness)
+
+		// Get picture info
+		var keyFrame, pictType C.int
+		C.astilibavFramePictureInfo((*C.AVFrame)(unsafe.Pointer(p.Frame)), &keyFrame, &pictType)
+
+		// Drop
+		if s.shouldDrop(lateness, keyFrame > 0, avutil.AvPictureType(pictType)) {
+			s.statDroppedRate.Add(1)
+			s.drops.drop(s.dropReason(lateness))
+			return
+		}
+
+		// Dispatch frame
+		s.d.dispatch(p.Frame, p.Descriptor)
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+func (s *LoadShedder) lateness(now time.Time, pts time.Duration) (lateness time.Duration) {
+	// Frames ahead of realtime, the first one included, become the new reference
+	if s.ref != nil {
+		lateness = now.Sub(s.ref.at) - (pts - s.ref.pts)
+	}
+	if s.ref == nil || lateness < 0 {
+		s.ref = &loadShedderRef{
+			at:  now,
+			pts: pts,
+		}
+		lateness = 0
+	}
+	return
+}
+
+func (s *LoadShedder) updateShedding(lateness time.Duration) {
+	// Get status
+	shedding := lateness > s.o.Threshold
+	if shedding == s.shedding {
+		return
+	}
+	s.shedding = shedding
+
+	// Emit
+	n := LoadShedderSheddingStopped
+	if shedding {
+		n = LoadShedderSheddingStarted
+	}
+	s.eh.Emit(astiencoder.Event{
+		Name:    n,
+		Payload: LoadShedding{Lateness: lateness},
+		Target:  s,
+	})
+}
+
+func (s *LoadShedder) dropReason(lateness time.Duration) string {
+	if lateness > s.o.LateThreshold {
+		return FrameDropReasonLate
+	}
+	return FrameDropReasonNonReference
+}
+
+func (s *LoadShedder) shouldDrop(lateness time.Duration, keyFrame bool, pictType avutil.AvPictureType) bool {
+	switch {
+	case lateness > s.o.LateThreshold:
+		return !keyFrame
+	case lateness > s.o.Threshold:
+		return pictType == avutil.AvPictureType(avutil.AV_PICTURE_TYPE_B)
+	}
+	return false
+}
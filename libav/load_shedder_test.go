@@ -0,0 +1,31 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShedder(t *testing.T) {
+	s := NewLoadShedder(LoadShedderOptions{Threshold: time.Second}, astiencoder.NewEventHandler(), astikit.NewCloser())
+	n := time.Unix(0, 0)
+
+	// Lateness
+	assert.Equal(t, time.Duration(0), s.lateness(n, 10*time.Second))
+	assert.Equal(t, 500*time.Millisecond, s.lateness(n.Add(time.Second), 10500*time.Millisecond))
+	assert.Equal(t, time.Duration(0), s.lateness(n.Add(time.Second), 12*time.Second))
+	assert.Equal(t, time.Second, s.lateness(n.Add(3*time.Second), 13*time.Second))
+
+	// Drop
+	b := avutil.AvPictureType(avutil.AV_PICTURE_TYPE_B)
+	p := avutil.AvPictureType(avutil.AV_PICTURE_TYPE_P)
+	assert.False(t, s.shouldDrop(time.Second, false, b))
+	assert.True(t, s.shouldDrop(1500*time.Millisecond, false, b))
+	assert.False(t, s.shouldDrop(1500*time.Millisecond, false, p))
+	assert.True(t, s.shouldDrop(3*time.Second, false, p))
+	assert.False(t, s.shouldDrop(3*time.Second, true, p))
+}
@@ -0,0 +1,210 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavfilter libavutil
+#include <stdint.h>
+#include <stdio.h>
+#include <libavfilter/avfilter.h>
+#include <libavutil/log.h>
+
+extern void goAstilibavLog(void *avcl, void *parent, void *graph, int level, char *msg);
+
+static int astilibavLogLevel = AV_LOG_WARNING;
+
+static void astilibavLogCallback(void *avcl, int level, const char *fmt, va_list vl) {
+	if (level > astilibavLogLevel) return;
+	char msg[1024];
+	vsnprintf(msg, sizeof(msg), fmt, vl);
+	void *parent = NULL, *graph = NULL;
+	if (avcl) {
+		const AVClass *c = *(const AVClass **)avcl;
+		if (c && c->parent_log_context_offset) parent = *(void **)((uint8_t *)avcl + c->parent_log_context_offset);
+		if (c && c == avfilter_get_class()) graph = ((AVFilterContext *)avcl)->graph;
+	}
+	goAstilibavLog(avcl, parent, graph, level, msg);
+}
+
+static void astilibavSetLogCallback(int level) {
+	astilibavLogLevel = level;
+	av_log_set_callback(astilibavLogCallback);
+}
+
+static void astilibavResetLogCallback() {
+	av_log_set_callback(av_log_default_callback);
+}
+*/
+import "C"
+import (
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avutil"
+)
+
+// LogOptions represents libav log options
+type LogOptions struct {
+	// Messages whose level is above this one are ignored. Default is avutil.AV_LOG_WARNING
+	Level int
+	// Max number of messages per node and per second. Excess messages are dropped and counted in the next message
+	// of the same node. 0 means no limit
+	RateLimit int
+}
+
+// LogMessage represents a libav log message
+// It is the payload of the LogMessageEmitted event whose target is the node owning the libav context that logged the
+// message, or nil if it couldn't be found
+type LogMessage struct {
+	// Number of messages of the same target dropped because of the rate limit since the previous message
+	Dropped uint64
+	// One of the avutil.AV_LOG_* constants
+	Level   int
+	Message string
+}
+
+var logs = newLogRouter()
+
+type logRouter struct {
+	eh  *astiencoder.EventHandler
+	ls  map[interface{}]*logLimiter
+	m   *sync.Mutex
+	now func() time.Time
+	ns  map[uintptr]astiencoder.Node
+	o   LogOptions
+}
+
+type logLimiter struct {
+	count   int
+	dropped uint64
+	start   time.Time
+}
+
+func newLogRouter() *logRouter {
+	return &logRouter{
+		ls:  make(map[interface{}]*logLimiter),
+		m:   &sync.Mutex{},
+		now: time.Now,
+		ns:  make(map[uintptr]astiencoder.Node),
+	}
+}
+
+// RouteLogs routes libav's log output into the event handler instead of stderr
+// libav's log callback is global, therefore messages of all workflows are routed to this event handler
+func RouteLogs(o LogOptions, eh *astiencoder.EventHandler) {
+	// Default options
+	if o.Level == 0 {
+		o.Level = avutil.AV_LOG_WARNING
+	}
+
+	// Update router
+	logs.m.Lock()
+	logs.eh = eh
+	logs.o = o
+	logs.m.Unlock()
+
+	// Set callback
+	C.astilibavSetLogCallback(C.int(o.Level))
+}
+
+// ResetLogs restores libav's default log output
+func ResetLogs() {
+	// Reset callback
+	C.astilibavResetLogCallback()
+
+	// Update router
+	logs.m.Lock()
+	logs.eh = nil
+	logs.m.Unlock()
+}
+
+// registerLogOwner makes messages logged by the libav context be attributed to the node
+func registerLogOwner(ctx unsafe.Pointer, n astiencoder.Node) {
+	if ctx == nil {
+		return
+	}
+	logs.m.Lock()
+	defer logs.m.Unlock()
+	logs.ns[uintptr(ctx)] = n
+}
+
+// unregisterLogOwner must be called before the libav context is freed since its address may be reused
+func unregisterLogOwner(ctx unsafe.Pointer) {
+	logs.m.Lock()
+	defer logs.m.Unlock()
+	if n, ok := logs.ns[uintptr(ctx)]; ok {
+		delete(logs.ns, uintptr(ctx))
+		delete(logs.ls, n)
+	}
+}
+
+func (r *logRouter) handle(ctxs []uintptr, level int, msg string) {
+	// Empty message
+	if msg = strings.TrimSpace(msg); msg == "" {
+		return
+	}
+
+	// Lock
+	r.m.Lock()
+
+	// Logs are not routed
+	if r.eh == nil {
+		r.m.Unlock()
+		return
+	}
+	eh := r.eh
+
+	// Get target
+	var target interface{}
+	for _, ctx := range ctxs {
+		if n, ok := r.ns[ctx]; ok {
+			target = n
+			break
+		}
+	}
+
+	// Create message
+	m := LogMessage{
+		Level:   level,
+		Message: msg,
+	}
+
+	// Rate limit
+	if r.o.RateLimit > 0 {
+		// Get limiter
+		l, ok := r.ls[target]
+		if !ok {
+			l = &logLimiter{}
+			r.ls[target] = l
+		}
+
+		// New window
+		if now := r.now(); now.Sub(l.start) >= time.Second {
+			l.count = 0
+			l.start = now
+		}
+
+		// Too many messages
+		if l.count >= r.o.RateLimit {
+			l.dropped++
+			r.m.Unlock()
+			return
+		}
+
+		// Update limiter
+		l.count++
+		m.Dropped = l.dropped
+		l.dropped = 0
+	}
+
+	// Unlock
+	r.m.Unlock()
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name:    LogMessageEmitted,
+		Payload: m,
+		Target:  target,
+	})
+}
@@ -0,0 +1,12 @@
+package astilibav
+
+// #include <stdlib.h>
+import "C"
+import "unsafe"
+
+// goAstilibavLog is called by libav's log callback, possibly from threads created by libav
+//
+//export goAstilibavLog
+func goAstilibavLog(avcl, parent, graph unsafe.Pointer, level C.int, msg *C.char) {
+	logs.handle([]uintptr{uintptr(avcl), uintptr(parent), uintptr(graph)}, int(level), C.GoString(msg))
+}
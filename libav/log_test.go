@@ -0,0 +1,52 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRouter(t *testing.T) {
+	r := newLogRouter()
+	now := time.Unix(100, 0)
+	r.now = func() time.Time { return now }
+	n := &Decoder{}
+	r.ns[2] = n
+
+	// Logs are not routed
+	eh := astiencoder.NewEventHandler()
+	var es []astiencoder.Event
+	eh.AddForEventName(LogMessageEmitted, func(e astiencoder.Event) bool {
+		es = append(es, e)
+		return false
+	})
+	r.handle([]uintptr{1, 0, 0}, avutil.AV_LOG_WARNING, "test")
+	assert.Len(t, es, 0)
+
+	// Messages are attributed using the context, its parent or its graph
+	r.eh = eh
+	r.o.RateLimit = 2
+	r.handle([]uintptr{1, 0, 0}, avutil.AV_LOG_WARNING, "  \n")
+	r.handle([]uintptr{1, 0, 0}, avutil.AV_LOG_WARNING, "unknown\n")
+	r.handle([]uintptr{1, 2, 0}, avutil.AV_LOG_ERROR, "1\n")
+	r.handle([]uintptr{1, 0, 2}, avutil.AV_LOG_WARNING, "2")
+	assert.Len(t, es, 3)
+	assert.Nil(t, es[0].Target)
+	assert.Equal(t, LogMessage{Level: avutil.AV_LOG_WARNING, Message: "unknown"}, es[0].Payload)
+	assert.Equal(t, n, es[1].Target)
+	assert.Equal(t, LogMessage{Level: avutil.AV_LOG_ERROR, Message: "1"}, es[1].Payload)
+	assert.Equal(t, n, es[2].Target)
+
+	// Messages are rate limited per target
+	r.handle([]uintptr{2, 0, 0}, avutil.AV_LOG_WARNING, "3")
+	r.handle([]uintptr{2, 0, 0}, avutil.AV_LOG_WARNING, "4")
+	r.handle([]uintptr{1, 0, 0}, avutil.AV_LOG_WARNING, "unknown")
+	assert.Len(t, es, 4)
+	now = now.Add(time.Second)
+	r.handle([]uintptr{2, 0, 0}, avutil.AV_LOG_WARNING, "5")
+	assert.Len(t, es, 5)
+	assert.Equal(t, LogMessage{Dropped: 2, Level: avutil.AV_LOG_WARNING, Message: "5"}, es[4].Payload)
+}
@@ -0,0 +1,69 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// LUT3DFilterer represents an object capable of applying a 3D LUT (e.g. a .cube file) to video frames
+// The LUT can be swapped while the filterer is running
+type LUT3DFilterer struct {
+	*Filterer
+	interpolation string
+}
+
+// LUT3DFiltererOptions represents 3D LUT filterer options
+type LUT3DFiltererOptions struct {
+	Input astiencoder.Node
+	// Possible values are "nearest", "trilinear" and "tetrahedral". Default is "tetrahedral"
+	Interpolation string
+	Node          astiencoder.NodeOptions
+	OutputCtx     Context
+	// Path of the LUT
+	Path      string
+	Restamper FrameRestamper
+}
+
+// NewLUT3DFilterer creates a new 3D LUT filterer
+func NewLUT3DFilterer(o LUT3DFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *LUT3DFilterer, err error) {
+	// No path
+	if o.Path == "" {
+		err = errors.New("astilibav: no path")
+		return
+	}
+
+	// Default interpolation
+	if o.Interpolation == "" {
+		o.Interpolation = "tetrahedral"
+	}
+
+	// Create filterer
+	f = &LUT3DFilterer{interpolation: o.Interpolation}
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   f.content(o.Path),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *LUT3DFilterer) content(path string) string {
+	return fmt.Sprintf("lut3d=file=%s:interp=%s", escapeFilterOption(path), f.interpolation)
+}
+
+// SetLUT swaps the LUT. If the LUT can't be loaded, the previous LUT is kept
+func (f *LUT3DFilterer) SetLUT(path string) (err error) {
+	if err = f.SetContent(f.content(path)); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec libavutil
+#include <libavcodec/avcodec.h>
+#include <libavutil/frame.h>
+#include <stdint.h>
+
+static int astilibavFrameBufferSize(AVFrame *f) {
+	int s = 0;
+	for (int i = 0; i < AV_NUM_DATA_POINTERS && f->buf[i]; i++) s += f->buf[i]->size;
+	for (int i = 0; i < f->nb_extended_buf; i++) s += f->extended_buf[i]->size;
+	return s;
+}
+
+static uintptr_t astilibavFrameBuffer(AVFrame *f) {
+	return f->buf[0] ? (uintptr_t)f->buf[0]->buffer : 0;
+}
+
+static uintptr_t astilibavPktBuffer(AVPacket *pkt) {
+	return pkt->buf ? (uintptr_t)pkt->buf->buffer : 0;
+}
+*/
+import "C"
+import (
+	"context"
+	"sync"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+// frameBufferSize returns the number of bytes referenced by the frame buffers
+// Buffers shared with other frames are counted as well since the frame keeps them alive
+func frameBufferSize(f *avutil.Frame) int {
+	if f == nil {
+		return 0
+	}
+	return int(C.astilibavFrameBufferSize((*C.AVFrame)(unsafe.Pointer(f))))
+}
+
+// pktBufferSize returns the number of bytes of the packet data
+func pktBufferSize(pkt *avcodec.Packet) int {
+	if pkt == nil {
+		return 0
+	}
+	return pkt.Size()
+}
+
+// Frames and packets taken from the shared pools are registered with the memory budget of the workflow buffering
+// them. Since their data is reference counted, buffers referenced by several shells are only registered once per
+// workflow, and are unregistered once the last shell referencing them is put back in its pool
+var sharedMemoryTracker = newMemoryTracker()
+
+type memoryTracker struct {
+	bs map[memoryTrackerBuffer]int
+	m  *sync.Mutex
+	ss map[unsafe.Pointer]memoryTrackerShell
+}
+
+type memoryTrackerBuffer struct {
+	a *astiencoder.MemoryAccountant
+	b uintptr
+}
+
+type memoryTrackerShell struct {
+	b    memoryTrackerBuffer
+	size int
+}
+
+func newMemoryTracker() *memoryTracker {
+	return &memoryTracker{
+		bs: make(map[memoryTrackerBuffer]int),
+		m:  &sync.Mutex{},
+		ss: make(map[unsafe.Pointer]memoryTrackerShell),
+	}
+}
+
+// track registers the buffer referenced by the shell and returns false if the shell should be dropped, in which case
+// nothing is registered
+func (t *memoryTracker) track(shell unsafe.Pointer, buf uintptr, size int, a *astiencoder.MemoryAccountant) bool {
+	// Nothing to track
+	if a == nil || buf == 0 {
+		return true
+	}
+
+	// Buffer is not registered yet
+	// The accountant is called without holding the lock since exceeding the budget may stop the workflow, which puts
+	// shells back in their pools
+	b := memoryTrackerBuffer{a: a, b: buf}
+	t.m.Lock()
+	var acquired bool
+	if t.bs[b] == 0 {
+		t.m.Unlock()
+		if !a.Acquire(size) {
+			return false
+		}
+		acquired = true
+		t.m.Lock()
+	}
+
+	// Buffer has been registered in the meantime
+	extra := acquired && t.bs[b] > 0
+
+	// Track
+	t.bs[b]++
+	t.ss[shell] = memoryTrackerShell{b: b, size: size}
+	t.m.Unlock()
+
+	// Release extra registration
+	if extra {
+		a.Release(size)
+	}
+	return true
+}
+
+// untrack unregisters the buffer referenced by the shell once no other shell references it
+func (t *memoryTracker) untrack(shell unsafe.Pointer) {
+	// Lock
+	t.m.Lock()
+
+	// Shell is not tracked
+	s, ok := t.ss[shell]
+	if !ok {
+		t.m.Unlock()
+		return
+	}
+
+	// Untrack
+	delete(t.ss, shell)
+	t.bs[s.b]--
+	release := t.bs[s.b] == 0
+	if release {
+		delete(t.bs, s.b)
+	}
+	t.m.Unlock()
+
+	// Release
+	if release {
+		s.b.a.Release(s.size)
+	}
+}
+
+// trackFrame registers a frame taken from the shared pool with the memory budget
+func trackFrame(f *avutil.Frame, a *astiencoder.MemoryAccountant) bool {
+	if f == nil {
+		return true
+	}
+	return sharedMemoryTracker.track(unsafe.Pointer(f), uintptr(C.astilibavFrameBuffer((*C.AVFrame)(unsafe.Pointer(f)))), frameBufferSize(f), a)
+}
+
+// trackPkt registers a pkt taken from the shared pool with the memory budget
+func trackPkt(pkt *avcodec.Packet, a *astiencoder.MemoryAccountant) bool {
+	if pkt == nil {
+		return true
+	}
+	return sharedMemoryTracker.track(unsafe.Pointer(pkt), uintptr(C.astilibavPktBuffer((*C.AVPacket)(unsafe.Pointer(pkt)))), pktBufferSize(pkt), a)
+}
+
+// nodeMemoryAccountant returns the memory accountant of the workflow the node has been started in, if any
+func nodeMemoryAccountant(n interface{}) *astiencoder.MemoryAccountant {
+	c, ok := n.(interface{ Context() context.Context })
+	if !ok {
+		return nil
+	}
+	return astiencoder.MemoryAccountantFromContext(c.Context())
+}
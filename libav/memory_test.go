@@ -0,0 +1,35 @@
+package astilibav
+
+import (
+	"context"
+	"testing"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTracker(t *testing.T) {
+	a := astiencoder.NewWorkflow(context.Background(), "w", astiencoder.NewEventHandler(), nil, astikit.NewCloser()).SetMemoryBudget(astiencoder.MemoryBudgetOptions{Budget: 10})
+	mt := newMemoryTracker()
+	s1, s2, s3 := new(int), new(int), new(int)
+
+	// Buffers shared by several shells are registered once
+	assert.True(t, mt.track(unsafe.Pointer(s1), 1, 6, a))
+	assert.True(t, mt.track(unsafe.Pointer(s2), 1, 6, a))
+	assert.Equal(t, int64(6), a.Usage())
+
+	// Buffers exceeding the budget are dropped
+	assert.False(t, mt.track(unsafe.Pointer(s3), 2, 6, a))
+	assert.Equal(t, int64(6), a.Usage())
+
+	// Buffers are released once no shell references them
+	mt.untrack(unsafe.Pointer(s1))
+	assert.Equal(t, int64(6), a.Usage())
+	mt.untrack(unsafe.Pointer(s2))
+	mt.untrack(unsafe.Pointer(s3))
+	assert.Equal(t, int64(0), a.Usage())
+	assert.Len(t, mt.bs, 0)
+	assert.Len(t, mt.ss, 0)
+}
@@ -1,15 +1,22 @@
 package astilibav
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
+	"text/template"
+	"time"
 	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
 	"github.com/asticode/goav/avformat"
+	"github.com/asticode/goav/avutil"
 )
 
 var countMuxer uint64
@@ -17,23 +24,88 @@ var countMuxer uint64
 // Muxer represents an object capable of muxing packets into an output
 type Muxer struct {
 	*astiencoder.BaseNode
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	cl               *astikit.Closer
+	congestion       *congestionDetector
+	ctxAvIO          *avformat.AvIOContext
 	ctxFormat        *avformat.Context
 	eh               *astiencoder.EventHandler
+	headerWritten    bool
+	io               *ioContext
 	o                *sync.Once
+	options          MuxerOptions
 	restamper        PktRestamper
+	rotation         *muxerRotation
+	statBitRate      *astikit.CounterRateStat
 	statIncomingRate *astikit.CounterRateStat
 	statWorkRatio    *astikit.DurationPercentageStat
+	switchURL        string
+	urlMutex         *sync.Mutex
 }
 
 // MuxerOptions represents muxer options
 type MuxerOptions struct {
+	// Used to report congestion to handlers added with AddCongestionHandler
+	Congestion CongestionDetectorOptions
+	// Options passed when writing the header (e.g. movflags)
+	Dict       *Dict
 	Format     *avformat.OutputFormat
 	FormatName string
-	Node       astiencoder.NodeOptions
-	Restamper  PktRestamper
-	URL        string
+	// If true, the output is only opened and its header written once the first packet is received so that outputs
+	// that never receive data are never created. Rotation is not supported in this case
+	Lazy      bool
+	Node      astiencoder.NodeOptions
+	Restamper PktRestamper
+	// If set, the output is rotated and URL is used as a template
+	Rotation *MuxerRotationOptions
+	// Used to resolve the ${secret:<ref>} references of the URL, e.g. a stream key, every time an output is opened
+	SecretProvider astiencoder.SecretProvider
+	URL            string
+	// If set, data is written to it instead of the URL which is then only used to guess the format. Rotation is not
+	// supported in this case
+	Writer io.Writer
+}
+
+// MuxerRotationOptions represents muxer rotation options
+// When rotation is enabled, the URL is parsed as a template where {{.sequence}} is the file sequence number
+// starting at 1 and {{.time}} is the time.Time at which the file has been opened
+type MuxerRotationOptions struct {
+	// Files are rotated at boundaries that are multiple of this duration on the clock of the first video stream, or of
+	// the first stream if there's no video stream
+	Duration time.Duration
+	// By default, timestamps are rebased so that every file starts at 0 and can be played on its own. If true, they're
+	// kept as is, which HLS playlists, DVRs and CMAF segmenters need since they play files back to back and set it
+	// themselves
+	KeepTimestamps bool
+	// If true, once the boundary has been reached, the muxer waits for the next video key frame before rotating
+	KeyFrame bool
+}
+
+type muxerRotation struct {
+	f MuxerFile
+	// On the clock of the reference stream. Nil until its first packet has been received
+	nextAt *time.Duration
+	o      MuxerRotationOptions
+	// Timestamps of the current file are rebased on it. Nil until its first packet has been written
+	offset   *time.Duration
+	ptsEnd   int64
+	ptsStart *int64
+	refIdx   *int
+	t        *template.Template
+	timeBase avutil.Rational
+}
+
+// MuxerFile represents a file written by the muxer
+// It is the payload of the MuxerFileCompleted event
+type MuxerFile struct {
+	// Duration based on the timestamps of the first video stream, or of the first stream if there's no video stream
+	Duration time.Duration
+	EndedAt  time.Time
+	// Whether the first packet of the reference stream is a key frame
+	Independent bool
+	Sequence    int
+	StartedAt   time.Time
+	URL         string
 }
 
 // NewMuxer creates a new muxer
@@ -44,55 +116,174 @@ func NewMuxer(o MuxerOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (
 
 	// Create muxer
 	m = &Muxer{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c:                astiencoder.NewQueue(o.Node.Queue),
 		cl:               c,
 		eh:               eh,
 		o:                &sync.Once{},
+		options:          o,
 		restamper:        o.Restamper,
+		statBitRate:      astikit.NewCounterRateStat(),
 		statIncomingRate: astikit.NewCounterRateStat(),
 		statWorkRatio:    astikit.NewDurationPercentageStat(),
+		urlMutex:         &sync.Mutex{},
 	}
 	m.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(m), eh)
+	m.congestion = newCongestionDetector(m, o.Congestion)
 	m.addStats()
 
-	// Alloc format context
-	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
-	var ctxFormat *avformat.Context
-	if ret := avformat.AvformatAllocOutputContext2(&ctxFormat, o.Format, o.FormatName, o.URL); ret < 0 {
-		err = fmt.Errorf("astilibav: avformat.AvformatAllocOutputContext2 on %+v failed: %w", o, NewAvError(ret))
+	// Get url
+	url := o.URL
+	if o.Rotation != nil {
+		// No duration
+		if o.Rotation.Duration <= 0 {
+			err = fmt.Errorf("astilibav: invalid rotation duration %s", o.Rotation.Duration)
+			return
+		}
+
+		// Create rotation
+		m.rotation = &muxerRotation{o: *o.Rotation}
+
+		// Parse url
+		if m.rotation.t, err = template.New("").Parse(o.URL); err != nil {
+			err = fmt.Errorf("astilibav: parsing url %s as template failed: %w", o.URL, err)
+			return
+		}
+
+		// Get first file
+		var f MuxerFile
+		if f, err = m.rotation.next(1); err != nil {
+			err = fmt.Errorf("astilibav: getting first file failed: %w", err)
+			return
+		}
+		m.rotation.start(f)
+		url = f.URL
+	}
+
+	// Lazy outputs can't be rotated
+	if o.Lazy && o.Rotation != nil {
+		err = errors.New("astilibav: rotation is not supported with a lazy muxer")
 		return
 	}
-	m.ctxFormat = ctxFormat
 
-	// Make sure the format ctx is properly closed
-	c.Add(func() error {
-		m.ctxFormat.AvformatFreeContext()
-		return nil
-	})
+	// Resolve secrets
+	if url, err = m.resolveURL(url); err != nil {
+		err = fmt.Errorf("astilibav: resolving url failed: %w", err)
+		return
+	}
 
-	// This is a file
-	if m.ctxFormat.Flags()&avformat.AVFMT_NOFILE == 0 {
-		// Open
-		var ctxAvIO *avformat.AvIOContext
-		if ret := avformat.AvIOOpen(&ctxAvIO, o.URL, avformat.AVIO_FLAG_WRITE); ret < 0 {
-			err = fmt.Errorf("astilibav: avformat.AvIOOpen on %+v failed: %w", o, NewAvError(ret))
+	// Open
+	if o.Writer != nil {
+		// Rotation is not supported
+		if o.Rotation != nil {
+			err = errors.New("astilibav: rotation is not supported with a writer")
+			return
+		}
+
+		// Open writer
+		if m.ctxFormat, m.io, err = openMuxerWriter(o.Format, o.FormatName, url, o.Writer); err != nil {
+			err = fmt.Errorf("astilibav: opening writer failed: %w", err)
+			return
+		}
+	} else if o.Lazy {
+		if m.ctxFormat, err = allocMuxerOutput(o.Format, o.FormatName, url); err != nil {
+			err = fmt.Errorf("astilibav: allocating %s failed: %w", url, err)
 			return
 		}
+	} else if m.ctxFormat, m.ctxAvIO, err = openMuxerOutput(o.Format, o.FormatName, url); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", url, err)
+		return
+	}
 
-		// Set pb
-		m.ctxFormat.SetPb(ctxAvIO)
+	// Attribute libav logs to the muxer
+	registerLogOwner(unsafe.Pointer(m.ctxFormat), m)
 
-		// Make sure the avio ctx is properly closed
-		c.Add(func() error {
-			if ret := avformat.AvIOClosep(&ctxAvIO); ret < 0 {
-				return fmt.Errorf("astilibav: avformat.AvIOClosep on %+v failed: %w", o, NewAvError(ret))
-			}
-			return nil
-		})
+	// Make sure the output is properly closed
+	c.Add(func() error {
+		unregisterLogOwner(unsafe.Pointer(m.ctxFormat))
+		err := closeMuxerOutput(m.ctxFormat, m.ctxAvIO)
+		if m.io != nil {
+			m.io.close()
+		}
+		return err
+	})
+	return
+}
+
+func (m *Muxer) resolveURL(url string) (string, error) {
+	return astiencoder.ResolveSecretReferences(url, m.options.SecretProvider)
+}
+
+func openMuxerWriter(format *avformat.OutputFormat, formatName, url string, w io.Writer) (ctxFormat *avformat.Context, i *ioContext, err error) {
+	// Alloc format context
+	if ret := avformat.AvformatAllocOutputContext2(&ctxFormat, format, formatName, url); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvformatAllocOutputContext2 on %s failed: %w", url, NewAvError(ret))
+		return
+	}
+
+	// Create io context
+	if i, err = newIOContext(nil, w); err != nil {
+		ctxFormat.AvformatFreeContext()
+		err = fmt.Errorf("astilibav: creating io context failed: %w", err)
+		return
+	}
+
+	// Set pb
+	ctxFormat.SetPb(i.avIOContext())
+	return
+}
+
+func openMuxerOutput(format *avformat.OutputFormat, formatName, url string) (ctxFormat *avformat.Context, ctxAvIO *avformat.AvIOContext, err error) {
+	// Alloc format context
+	if ctxFormat, err = allocMuxerOutput(format, formatName, url); err != nil {
+		err = fmt.Errorf("astilibav: allocating output failed: %w", err)
+		return
+	}
+
+	// Open io
+	if ctxAvIO, err = openMuxerOutputIO(ctxFormat, url); err != nil {
+		ctxFormat.AvformatFreeContext()
+		err = fmt.Errorf("astilibav: opening io failed: %w", err)
+		return
+	}
+	return
+}
+
+func allocMuxerOutput(format *avformat.OutputFormat, formatName, url string) (ctxFormat *avformat.Context, err error) {
+	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
+	if ret := avformat.AvformatAllocOutputContext2(&ctxFormat, format, formatName, url); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvformatAllocOutputContext2 on %s failed: %w", url, NewAvError(ret))
+		return
+	}
+	return
+}
+
+func openMuxerOutputIO(ctxFormat *avformat.Context, url string) (ctxAvIO *avformat.AvIOContext, err error) {
+	// This is not a file
+	if ctxFormat.Flags()&avformat.AVFMT_NOFILE > 0 {
+		return
 	}
+
+	// Open
+	if ret := avformat.AvIOOpen(&ctxAvIO, url, avformat.AVIO_FLAG_WRITE); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvIOOpen on %s failed: %w", url, NewAvError(ret))
+		return
+	}
+
+	// Set pb
+	ctxFormat.SetPb(ctxAvIO)
+	return
+}
+
+func closeMuxerOutput(ctxFormat *avformat.Context, ctxAvIO *avformat.AvIOContext) (err error) {
+	// Close avio ctx
+	if ctxAvIO != nil {
+		if ret := avformat.AvIOClosep(&ctxAvIO); ret < 0 {
+			err = fmt.Errorf("astilibav: avformat.AvIOClosep on %s failed: %w", ctxFormat.Filename(), NewAvError(ret))
+		}
+	}
+
+	// Free format ctx
+	ctxFormat.AvformatFreeContext()
 	return
 }
 
@@ -111,10 +302,22 @@ func (m *Muxer) addStats() {
 		Unit:        "%",
 	}, m.statWorkRatio)
 
+	// Add output bitrate
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of kilobits written per second",
+		Label:       "Output bitrate",
+		Unit:        "kbps",
+	}, m.statBitRate)
+
 	// Add chan stats
 	m.c.AddStats(m.Stater())
 }
 
+// AddCongestionHandler adds a handler to which the muxer periodically reports whether its output is congested
+func (m *Muxer) AddCongestionHandler(h CongestionHandler) {
+	m.congestion.addHandler(h)
+}
+
 // CtxFormat returns the format ctx
 func (m *Muxer) CtxFormat() *avformat.Context {
 	return m.ctxFormat
@@ -124,18 +327,28 @@ func (m *Muxer) CtxFormat() *avformat.Context {
 func (m *Muxer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 	m.BaseNode.Start(ctx, t, func(t *astikit.Task) {
 		// Make sure to write header once
-		var ret int
-		m.o.Do(func() { ret = m.ctxFormat.AvformatWriteHeader(nil) })
-		if ret < 0 {
-			emitAvError(m, m.eh, ret, "m.ctxFormat.AvformatWriteHeader on %s failed", m.ctxFormat.Filename())
-			return
+		// Lazy outputs are opened when the first packet is received
+		if !m.options.Lazy {
+			var err error
+			m.o.Do(func() { err = m.writeHeader() })
+			if err != nil {
+				m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: writing header failed: %w", err)))
+				return
+			}
 		}
 
 		// Write trailer once everything is done
 		m.cl.Add(func() error {
+			// Header has not been written, which may happen if rotation has failed
+			if !m.headerWritten {
+				return nil
+			}
+
+			// Write trailer
 			if ret := m.ctxFormat.AvWriteTrailer(); ret < 0 {
 				return fmt.Errorf("m.ctxFormat.AvWriteTrailer on %s failed: %w", m.ctxFormat.Filename(), NewAvError(ret))
 			}
+			m.emitFileCompleted()
 			return nil
 		})
 
@@ -147,47 +360,577 @@ func (m *Muxer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 	})
 }
 
+func (r *muxerRotation) next(sequence int) (f MuxerFile, err error) {
+	// Create file
+	f = MuxerFile{
+		Sequence:  sequence,
+		StartedAt: time.Now(),
+	}
+
+	// Get url
+	if f.URL, err = r.url(f.Sequence, f.StartedAt); err != nil {
+		err = fmt.Errorf("astilibav: getting url failed: %w", err)
+		return
+	}
+	return
+}
+
+// start must be called once the file has been opened
+func (r *muxerRotation) start(f MuxerFile) {
+	r.f = f
+	r.offset = nil
+	r.ptsEnd = 0
+	r.ptsStart = nil
+}
+
+// boundary returns the first boundary after the position
+func (r *muxerRotation) boundary(at time.Duration) time.Duration {
+	return at.Truncate(r.o.Duration) + r.o.Duration
+}
+
+func (r *muxerRotation) url(sequence int, t time.Time) (url string, err error) {
+	// Execute template
+	buf := &bytes.Buffer{}
+	if err = r.t.Execute(buf, map[string]interface{}{
+		"sequence": sequence,
+		"time":     t,
+	}); err != nil {
+		err = fmt.Errorf("astilibav: executing template failed: %w", err)
+		return
+	}
+	return buf.String(), nil
+}
+
+// shouldRotate must be called before the pkt's timestamps are rescaled
+func (m *Muxer) shouldRotate(pkt *avcodec.Packet, timeBase avutil.Rational, s *avformat.Stream) bool {
+	// No rotation
+	if m.rotation == nil {
+		return false
+	}
+
+	// Get reference stream
+	if m.rotation.refIdx == nil {
+		m.rotation.refIdx = astikit.IntPtr(m.referenceStreamIndex())
+	}
+
+	// Only packets of the reference stream with a timestamp are taken into account
+	if s.Index() != *m.rotation.refIdx || pkt.Pts() == avutil.AV_NOPTS_VALUE {
+		return false
+	}
+
+	// Get position on the stream clock
+	at := time.Duration(avutil.AvRescaleQ(pkt.Pts(), timeBase, nanosecondRational))
+
+	// First packet
+	if m.rotation.nextAt == nil {
+		m.rotation.nextAt = astikit.DurationPtr(m.rotation.boundary(at))
+		return false
+	}
+
+	// Boundary has not been reached
+	if at < *m.rotation.nextAt {
+		return false
+	}
+
+	// No need to wait for a key frame
+	if !m.rotation.o.KeyFrame {
+		return true
+	}
+	return pkt.Flags()&avcodec.AV_PKT_FLAG_KEY > 0
+}
+
+// rebase must be called once the pkt's timestamps have been rescaled and restamped
+func (r *muxerRotation) rebase(pkt *avcodec.Packet, s *avformat.Stream) {
+	// Timestamps are kept
+	if r.o.KeepTimestamps {
+		return
+	}
+
+	// First packet of the file
+	if r.offset == nil {
+		v := pkt.Dts()
+		if v == avutil.AV_NOPTS_VALUE {
+			v = pkt.Pts()
+		}
+		if v == avutil.AV_NOPTS_VALUE {
+			return
+		}
+		r.offset = astikit.DurationPtr(time.Duration(avutil.AvRescaleQ(v, s.TimeBase(), nanosecondRational)))
+	}
+
+	// Rebase
+	offset := avutil.AvRescaleQ(int64(*r.offset), nanosecondRational, s.TimeBase())
+	if pkt.Pts() != avutil.AV_NOPTS_VALUE {
+		pkt.SetPts(pkt.Pts() - offset)
+	}
+	if pkt.Dts() != avutil.AV_NOPTS_VALUE {
+		pkt.SetDts(pkt.Dts() - offset)
+	}
+}
+
+func (r *muxerRotation) update(pkt *avcodec.Packet, s *avformat.Stream) {
+	// Not the reference stream
+	if s.Index() != *r.refIdx {
+		return
+	}
+
+	// First packet
+	if r.ptsStart == nil {
+		r.ptsStart = astikit.Int64Ptr(pkt.Pts())
+		r.f.Independent = pkt.Flags()&avcodec.AV_PKT_FLAG_KEY > 0
+		r.timeBase = s.TimeBase()
+	}
+
+	// Update end
+	if v := pkt.Pts() + pkt.Duration(); v > r.ptsEnd {
+		r.ptsEnd = v
+	}
+}
+
+func (m *Muxer) referenceStreamIndex() int {
+	for _, s := range m.ctxFormat.Streams() {
+		if s.CodecParameters().CodecType() == avcodec.AVMEDIA_TYPE_VIDEO {
+			return s.Index()
+		}
+	}
+	return 0
+}
+
+func (m *Muxer) rotate() (err error) {
+	// Get next file
+	var f MuxerFile
+	if f, err = m.rotation.next(m.rotation.f.Sequence + 1); err != nil {
+		err = fmt.Errorf("astilibav: getting next file failed: %w", err)
+		return
+	}
+
+	// Open next output
+	// It's opened before the current one is closed so that packets keep being written to the current one if it fails
+	ctxFormat, ctxAvIO, err := m.openNext(f.URL)
+	if err != nil {
+		err = fmt.Errorf("astilibav: opening next output failed: %w", err)
+		return
+	}
+
+	// Write trailer
+	if ret := m.ctxFormat.AvWriteTrailer(); ret < 0 {
+		emitAvError(m, m.eh, ret, "m.ctxFormat.AvWriteTrailer on %s failed", m.ctxFormat.Filename())
+	} else {
+		m.emitFileCompleted()
+	}
+
+	// Replace output
+	m.replaceOutput(ctxFormat, ctxAvIO)
+
+	// Start file
+	m.rotation.start(f)
+	return
+}
+
+// openNext opens an output with the same streams as the current one and writes its header
+func (m *Muxer) openNext(url string) (ctxFormat *avformat.Context, ctxAvIO *avformat.AvIOContext, err error) {
+	// Resolve secrets
+	if url, err = m.resolveURL(url); err != nil {
+		err = fmt.Errorf("astilibav: resolving url failed: %w", err)
+		return
+	}
+
+	// Open
+	if ctxFormat, ctxAvIO, err = openMuxerOutput(m.options.Format, m.options.FormatName, url); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", url, err)
+		return
+	}
+
+	// Clone streams
+	if err = cloneMuxerStreams(m.ctxFormat, ctxFormat); err != nil {
+		closeMuxerOutput(ctxFormat, ctxAvIO)
+		err = fmt.Errorf("astilibav: cloning streams failed: %w", err)
+		return
+	}
+
+	// Write header
+	if err = m.writeHeaderTo(ctxFormat); err != nil {
+		closeMuxerOutput(ctxFormat, ctxAvIO)
+		err = fmt.Errorf("astilibav: writing header failed: %w", err)
+		return
+	}
+	return
+}
+
+// replaceOutput closes the current output, whose trailer must have been written, and replaces it
+func (m *Muxer) replaceOutput(ctxFormat *avformat.Context, ctxAvIO *avformat.AvIOContext) {
+	// Close current output
+	unregisterLogOwner(unsafe.Pointer(m.ctxFormat))
+	if err := closeMuxerOutput(m.ctxFormat, m.ctxAvIO); err != nil {
+		m.eh.Emit(astiencoder.EventError(m, fmt.Errorf("astilibav: closing previous output failed: %w", err)))
+	}
+
+	// Update output
+	m.ctxFormat, m.ctxAvIO = ctxFormat, ctxAvIO
+	registerLogOwner(unsafe.Pointer(m.ctxFormat), m)
+	m.headerWritten = true
+}
+
+func (m *Muxer) openLazily() (err error) {
+	// Open io
+	if m.options.Writer == nil {
+		// Resolve secrets
+		var url string
+		if url, err = m.resolveURL(m.options.URL); err != nil {
+			err = fmt.Errorf("astilibav: resolving url failed: %w", err)
+			return
+		}
+
+		// Open
+		if m.ctxAvIO, err = openMuxerOutputIO(m.ctxFormat, url); err != nil {
+			err = fmt.Errorf("astilibav: opening io failed: %w", err)
+			return
+		}
+	}
+
+	// Write header
+	if err = m.writeHeader(); err != nil {
+		err = fmt.Errorf("astilibav: writing header failed: %w", err)
+		return
+	}
+	return
+}
+
+func (m *Muxer) writeHeader() (err error) {
+	if err = m.writeHeaderTo(m.ctxFormat); err != nil {
+		return
+	}
+	m.headerWritten = true
+	return
+}
+
+func (m *Muxer) writeHeaderTo(ctxFormat *avformat.Context) (err error) {
+	// Dict
+	var dict *avutil.Dictionary
+	if m.options.Dict != nil {
+		// Parse dict
+		if err = m.options.Dict.Parse(&dict); err != nil {
+			err = fmt.Errorf("astilibav: parsing dict failed: %w", err)
+			return
+		}
+
+		// Make sure the dict is freed
+		defer avutil.AvDictFree(&dict)
+	}
+
+	// Write header
+	if ret := ctxFormat.AvformatWriteHeader(&dict); ret < 0 {
+		err = fmt.Errorf("astilibav: ctxFormat.AvformatWriteHeader on %s failed: %w", ctxFormat.Filename(), NewAvError(ret))
+		return
+	}
+	return
+}
+
+func cloneMuxerStreams(src, dst *avformat.Context) (err error) {
+	for _, i := range src.Streams() {
+		// Clone
+		var o *avformat.Stream
+		if o, err = CloneStream(i, dst); err != nil {
+			err = fmt.Errorf("astilibav: cloning stream %d failed: %w", i.Index(), err)
+			return
+		}
+
+		// Keep the same time base
+		o.SetTimeBase(i.TimeBase())
+	}
+	return
+}
+
+func (m *Muxer) emitFileCompleted() {
+	// No rotation
+	if m.rotation == nil {
+		return
+	}
+
+	// Update file
+	f := m.rotation.f
+	f.EndedAt = time.Now()
+	if m.rotation.ptsStart != nil {
+		f.Duration = time.Duration(avutil.AvRescaleQ(m.rotation.ptsEnd-*m.rotation.ptsStart, m.rotation.timeBase, nanosecondRational))
+	}
+
+	// Emit event
+	m.eh.Emit(astiencoder.Event{
+		Name:    MuxerFileCompleted,
+		Payload: f,
+		Target:  m,
+	})
+}
+
+// MuxerReconfigureOptions represents the muxer options that can be changed while it's running
+type MuxerReconfigureOptions struct {
+	// The current output is closed and the new one is opened before the next packet is written. Streams and their
+	// time bases are kept
+	URL string `json:"url,omitempty"`
+}
+
+// Reconfigure implements the astiencoder.Reconfigurer interface
+// Switching the output is not supported with a writer or with rotation
+func (m *Muxer) Reconfigure(opts interface{}) (err error) {
+	// Decode options
+	var o MuxerReconfigureOptions
+	if err = astiencoder.DecodeReconfigureOptions(opts, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Nothing to do
+	if o.URL == "" {
+		return
+	}
+
+	// Output can't be switched
+	if m.options.Writer != nil {
+		return errors.New("astilibav: switching the output is not supported with a writer")
+	} else if m.rotation != nil {
+		return errors.New("astilibav: switching the output is not supported with rotation")
+	}
+
+	// Store url
+	m.urlMutex.Lock()
+	defer m.urlMutex.Unlock()
+	m.switchURL = o.URL
+	return
+}
+
+func (m *Muxer) url() string {
+	m.urlMutex.Lock()
+	defer m.urlMutex.Unlock()
+	return m.options.URL
+}
+
+func (m *Muxer) switchOutput() (switched bool, err error) {
+	// Get url
+	m.urlMutex.Lock()
+	url := m.switchURL
+	m.switchURL = ""
+	if url != "" {
+		m.options.URL = url
+	}
+	m.urlMutex.Unlock()
+
+	// Nothing to do
+	if url == "" {
+		return
+	}
+
+	// Lazy output has not been opened yet, it will be opened with the new url
+	if !m.headerWritten {
+		return
+	}
+
+	// Open next output
+	ctxFormat, ctxAvIO, err := m.openNext(url)
+	if err != nil {
+		err = fmt.Errorf("astilibav: opening next output failed: %w", err)
+		return
+	}
+
+	// Write trailer
+	if ret := m.ctxFormat.AvWriteTrailer(); ret < 0 {
+		emitAvError(m, m.eh, ret, "m.ctxFormat.AvWriteTrailer on %s failed", m.ctxFormat.Filename())
+	}
+
+	// Replace output
+	m.replaceOutput(ctxFormat, ctxAvIO)
+	switched = true
+	return
+}
+
 // MuxerPktHandler is an object that can handle a pkt for the muxer
 type MuxerPktHandler struct {
 	*Muxer
-	o *avformat.Stream
+	idx         int
+	statLatency *latencyStat
 }
 
 // NewHandler creates
 func (m *Muxer) NewPktHandler(o *avformat.Stream) *MuxerPktHandler {
-	return &MuxerPktHandler{
-		Muxer: m,
-		o:     o,
+	// Create handler
+	h := &MuxerPktHandler{
+		Muxer:       m,
+		idx:         o.Index(),
+		statLatency: newLatencyStat(),
 	}
+
+	// Add latency stat
+	m.Stater().AddStat(astikit.StatMetadata{
+		Description: fmt.Sprintf("Average time between the moment packets of stream #%d have been demuxed and the moment they have been written", h.idx),
+		Label:       fmt.Sprintf("Stream %d latency", h.idx),
+		Unit:        "ms",
+	}, h.statLatency)
+	return h
 }
 
 // HandlePkt implements the PktHandler interface
 func (h *MuxerPktHandler) HandlePkt(p *PktHandlerPayload) {
-	h.c.Add(func() {
+	// Trace
+	// The span ends once the pkt is written or dropped
+	var s astiencoder.Span
+	if p.traceCtx != nil {
+		_, s = startSpan(p.traceCtx, h, SpanNameMux, p.Pkt.Pts())
+	}
+
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be written
+	// It is dropped if the memory budget is exceeded
+	size := pktBufferSize(p.Pkt)
+	if p = refPktHandlerPayload(p, h); p == nil {
+		if s != nil {
+			s.End()
+		}
+		return
+	}
+	h.c.AddWithRelease(func() {
 		// Handle pause
 		defer h.HandlePause()
 
-		// Increment incoming rate
-		h.statIncomingRate.Add(1)
+		// Write pkt
+		h.writePkt(p)
+	}, func() {
+		unrefPktHandlerPayload(p)
+		if s != nil {
+			s.End()
+		}
+	}, size)
+}
+
+// HandlePktBatch implements the PktBatchHandler interface
+func (h *MuxerPktHandler) HandlePktBatch(ps []*PktHandlerPayload) {
+	// Loop through pkts
+	var size int
+	var ss []astiencoder.Span
+	rs := make([]*PktHandlerPayload, 0, len(ps))
+	for _, p := range ps {
+		// Trace
+		if p.traceCtx != nil {
+			if _, s := startSpan(p.traceCtx, h, SpanNameMux, p.Pkt.Pts()); s != nil {
+				ss = append(ss, s)
+			}
+		}
 
-		// Rescale timestamps
-		p.Pkt.AvPacketRescaleTs(p.Descriptor.TimeBase(), h.o.TimeBase())
+		// Reference pkt
+		// It is dropped if the memory budget is exceeded
+		size += pktBufferSize(p.Pkt)
+		if r := refPktHandlerPayload(p, h); r != nil {
+			rs = append(rs, r)
+		}
+	}
 
-		// Set stream index
-		p.Pkt.SetStreamIndex(h.o.Index())
+	// Add to queue
+	h.c.AddWithRelease(func() {
+		// Handle pause
+		defer h.HandlePause()
 
-		// Restamp
-		if h.restamper != nil {
-			h.restamper.Restamp(p.Pkt)
+		// Loop through pkts
+		for _, p := range rs {
+			h.writePkt(p)
 		}
+	}, func() {
+		for _, p := range rs {
+			unrefPktHandlerPayload(p)
+		}
+		for _, s := range ss {
+			s.End()
+		}
+	}, size)
+}
 
-		// Write frame
-		h.statWorkRatio.Begin()
-		if ret := h.ctxFormat.AvInterleavedWriteFrame((*avformat.Packet)(unsafe.Pointer(p.Pkt))); ret < 0 {
-			h.statWorkRatio.End()
-			emitAvError(h, h.eh, ret, "h.ctxFormat.AvInterleavedWriteFrame failed")
+func (h *MuxerPktHandler) writePkt(p *PktHandlerPayload) {
+	// Increment incoming rate
+	h.statIncomingRate.Add(1)
+
+	// Open lazily
+	if h.options.Lazy {
+		var err error
+		h.o.Do(func() { err = h.openLazily() })
+		if err != nil {
+			h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: opening output lazily failed: %w", err)))
+			h.Stop()
+			return
+		} else if !h.headerWritten {
 			return
 		}
+	}
+
+	// Get stream
+	// Streams are retrieved every time since they change when the output is rotated
+	o := h.ctxFormat.Streams()[h.idx]
+
+	// Switch output
+	h.statWorkRatio.Begin()
+	switched, err := h.switchOutput()
+	h.statWorkRatio.End()
+	if err != nil {
+		h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: switching output failed: %w", err)))
+		h.Stop()
+		return
+	} else if switched {
+		o = h.ctxFormat.Streams()[h.idx]
+	}
+
+	// Rotate
+	if h.shouldRotate(p.Pkt, p.Descriptor.TimeBase(), o) {
+		// Next rotation happens at the next boundary
+		h.rotation.nextAt = astikit.DurationPtr(h.rotation.boundary(*h.rotation.nextAt))
+
+		// Rotate
+		// If it fails, packets keep being written to the current file until the next boundary
+		h.statWorkRatio.Begin()
+		err := h.rotate()
 		h.statWorkRatio.End()
-	})
+		if err != nil {
+			h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: rotating failed: %w", err)))
+		} else {
+			o = h.ctxFormat.Streams()[h.idx]
+		}
+	}
+
+	// Rescale timestamps
+	p.Pkt.AvPacketRescaleTs(p.Descriptor.TimeBase(), o.TimeBase())
+
+	// Set stream index
+	p.Pkt.SetStreamIndex(o.Index())
+
+	// Restamp
+	if h.restamper != nil {
+		h.restamper.Restamp(p.Pkt)
+	}
+
+	// Update rotation
+	if h.rotation != nil {
+		h.rotation.rebase(p.Pkt, o)
+		h.rotation.update(p.Pkt, o)
+	}
+
+	// Write frame
+	// Size is retrieved beforehand since writing unreferences the pkt
+	size := p.Pkt.Size()
+	h.statWorkRatio.Begin()
+	writeStart := time.Now()
+	ret := h.ctxFormat.AvInterleavedWriteFrame((*avformat.Packet)(unsafe.Pointer(p.Pkt)))
+	writeEnd := time.Now()
+	h.statWorkRatio.End()
+
+	// Detect congestion
+	h.congestion.add(writeEnd, writeEnd.Sub(writeStart))
+
+	// Process error
+	if ret < 0 {
+		emitAvError(h, h.eh, ret, "h.ctxFormat.AvInterleavedWriteFrame failed")
+		return
+	}
+
+	// Increment output bitrate
+	h.statBitRate.Add(float64(size*8) / 1000)
+
+	// Update latency
+	if t, ok := demuxedAtFromContext(p.traceCtx); ok {
+		h.statLatency.add(writeEnd.Sub(t))
+	}
 }
@@ -0,0 +1,247 @@
+package astilibav
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avdevice"
+	"github.com/asticode/goav/avformat"
+)
+
+// NDIInput represents a demuxer receiving an NDI source through ffmpeg's libndi_newtek input device
+// ffmpeg must be configured with --enable-libndi_newtek
+// Packets are uncompressed (UYVY422 video and PCM audio) and are turned into frames by connecting decoders
+type NDIInput struct {
+	*Demuxer
+}
+
+// NDIInputOptions represents NDI input options
+type NDIInputOptions struct {
+	// Format, Dict and URL are set by the input
+	Demuxer DemuxerOptions
+	// If true, fielded video is rejected
+	DisableVideoFields bool
+	// Additional IPs to search for sources, separated by commas
+	ExtraIPs string
+	// Source name as returned by DiscoverNDISources (e.g. "MACHINE (Source)")
+	Source string
+	// Duration during which the device waits for sources. If 0, ffmpeg's default is used
+	WaitSources time.Duration
+}
+
+// NewNDIInput creates a new NDI input
+func NewNDIInput(o NDIInputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (i *NDIInput, err error) {
+	// Register devices
+	avdevice.AvdeviceRegisterAll()
+
+	// Find format
+	if o.Demuxer.Format = avformat.AvFindInputFormat("libndi_newtek"); o.Demuxer.Format == nil {
+		err = errors.New("astilibav: libndi_newtek input format not found")
+		return
+	}
+
+	// Create dict
+	// Pairs are separated by "&" since extra ips are separated by commas
+	var d []string
+	if o.DisableVideoFields {
+		d = append(d, "allow_video_fields=0")
+	}
+	if o.ExtraIPs != "" {
+		d = append(d, "extra_ips="+o.ExtraIPs)
+	}
+	if o.WaitSources > 0 {
+		d = append(d, fmt.Sprintf("wait_sources=%d", o.WaitSources.Microseconds()))
+	}
+	o.Demuxer.Dict = nil
+	if len(d) > 0 {
+		o.Demuxer.Dict = NewDict(strings.Join(d, "&"), "=", "&", 0)
+	}
+
+	// Create demuxer
+	i = &NDIInput{}
+	o.Demuxer.URL = o.Source
+	if i.Demuxer, err = NewDemuxer(o.Demuxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+	return
+}
+
+const ndiServiceName = "_ndi._tcp.local."
+
+// DiscoverNDISources discovers NDI sources available on the local network through mDNS until the context is done
+func DiscoverNDISources(ctx context.Context) (sources []string, err error) {
+	// Listen
+	var conn *net.UDPConn
+	if conn, err = net.ListenUDP("udp4", &net.UDPAddr{}); err != nil {
+		err = fmt.Errorf("astilibav: listening failed: %w", err)
+		return
+	}
+	defer conn.Close()
+
+	// Unblock reads when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	// Send query
+	// Since the source port is not 5353, responders reply in unicast
+	if _, err = conn.WriteToUDP(ndiQuery(), &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}); err != nil {
+		err = fmt.Errorf("astilibav: sending mdns query failed: %w", err)
+		return
+	}
+
+	// Read responses
+	m := make(map[string]bool)
+	b := make([]byte, 9000)
+	for {
+		// Read
+		n, _, errRead := conn.ReadFromUDP(b)
+		if ctx.Err() != nil {
+			break
+		} else if errRead != nil {
+			err = fmt.Errorf("astilibav: reading failed: %w", errRead)
+			return
+		}
+
+		// Parse
+		ss, errParse := ndiParseResponse(b[:n])
+		if errParse != nil {
+			continue
+		}
+		for _, s := range ss {
+			m[s] = true
+		}
+	}
+
+	// Sort
+	for s := range m {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+	return
+}
+
+func ndiQuery() []byte {
+	// Header with one question
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint16(b[4:], 1)
+
+	// PTR question in class IN
+	for _, l := range strings.Split(strings.TrimSuffix(ndiServiceName, "."), ".") {
+		b = append(b, byte(len(l)))
+		b = append(b, l...)
+	}
+	return append(b, 0, 0, 12, 0, 1)
+}
+
+// ndiParseResponse returns the instance names of the PTR records pointing to the NDI service
+func ndiParseResponse(b []byte) (sources []string, err error) {
+	// Invalid header
+	if len(b) < 12 {
+		err = errors.New("astilibav: invalid dns header")
+		return
+	}
+	questions := int(binary.BigEndian.Uint16(b[4:]))
+	records := int(binary.BigEndian.Uint16(b[6:])) + int(binary.BigEndian.Uint16(b[8:])) + int(binary.BigEndian.Uint16(b[10:]))
+
+	// Skip questions
+	offset := 12
+	for i := 0; i < questions; i++ {
+		if _, offset, err = ndiParseName(b, offset); err != nil {
+			return
+		}
+		offset += 4
+	}
+
+	// Loop through records
+	for i := 0; i < records; i++ {
+		// Parse name
+		var name []string
+		if name, offset, err = ndiParseName(b, offset); err != nil {
+			return
+		}
+
+		// Parse record header
+		if len(b) < offset+10 {
+			err = errors.New("astilibav: invalid dns record")
+			return
+		}
+		t := binary.BigEndian.Uint16(b[offset:])
+		l := int(binary.BigEndian.Uint16(b[offset+8:]))
+		offset += 10
+		if len(b) < offset+l {
+			err = errors.New("astilibav: invalid dns record length")
+			return
+		}
+
+		// PTR record pointing to the NDI service
+		if t == 12 && strings.EqualFold(strings.Join(name, ".")+".", ndiServiceName) {
+			var target []string
+			if target, _, err = ndiParseName(b, offset); err != nil {
+				return
+			}
+			if len(target) > 0 {
+				sources = append(sources, target[0])
+			}
+		}
+		offset += l
+	}
+	return
+}
+
+// ndiParseName parses a dns name that may contain compression pointers and returns its labels and the offset right
+// after it
+func ndiParseName(b []byte, offset int) (labels []string, next int, err error) {
+	next = -1
+	for jumps := 0; ; {
+		// Invalid offset
+		if offset >= len(b) {
+			err = errors.New("astilibav: invalid dns name")
+			return
+		}
+
+		// Get length
+		l := int(b[offset])
+		switch {
+		case l == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return
+		case l&0xc0 == 0xc0:
+			// Pointer
+			if offset+1 >= len(b) || jumps > 10 {
+				err = errors.New("astilibav: invalid dns name pointer")
+				return
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(b[offset:]) & 0x3fff)
+			jumps++
+		default:
+			// Label
+			if offset+1+l > len(b) {
+				err = errors.New("astilibav: invalid dns label")
+				return
+			}
+			labels = append(labels, string(b[offset+1:offset+1+l]))
+			offset += 1 + l
+		}
+	}
+}
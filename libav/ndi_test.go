@@ -0,0 +1,19 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDIParseResponse(t *testing.T) {
+	// Response with the question and one PTR record whose name is compressed
+	b := ndiQuery()
+	b[2], b[7] = 0x84, 1
+	b = append(b, 0xc0, 12, 0, 12, 0, 1, 0, 0, 0, 120, 0, 19, 16)
+	b = append(b, "MACHINE (Source)"...)
+	b = append(b, 0xc0, 12)
+	ss, err := ndiParseResponse(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MACHINE (Source)"}, ss)
+}
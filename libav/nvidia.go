@@ -0,0 +1,214 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+)
+
+// HardwareFallback represents a hardware fallback
+type HardwareFallback struct {
+	// Name of the hardware codec that couldn't be used
+	From string
+	// Index of the GPU, if the hardware has several
+	GPU int
+	// Reason why the hardware codec couldn't be used
+	Reason string
+	// Name of the software codec used instead. Empty means the codec was found by id
+	To string
+}
+
+var (
+	nvdecSessions = newHardwareSessions()
+	nvencSessions = newHardwareSessions()
+)
+
+// NVDECSessions returns the number of NVDEC sessions opened on each GPU
+func NVDECSessions() map[int]int {
+	return nvdecSessions.count()
+}
+
+// NVENCSessions returns the number of NVENC sessions opened on each GPU
+func NVENCSessions() map[int]int {
+	return nvencSessions.count()
+}
+
+// NVENCEncoderOptions represents NVENC encoder options
+type NVENCEncoderOptions struct {
+	// Ctx.CodecName must be the name of an NVENC encoder (e.g. "h264_nvenc")
+	Encoder EncoderOptions
+	// If set, GPU is ignored and the encoder is assigned to the least loaded GPU of the manager
+	DeviceManager *DeviceManager
+	// Name of the software encoder used when NVENC can't be used. If empty, an error is returned instead
+	FallbackCodecName string
+	// Index of the GPU
+	GPU int
+	// Max number of NVENC sessions opened on the GPU by all nodes. 0 means no limit
+	// Consumer GPUs are limited by the driver, in which case opening the codec fails which triggers the fallback as well
+	MaxSessions int
+}
+
+// NewNVENCEncoder creates a new encoder using NVENC on the selected GPU and falls back to a software encoder when
+// sessions are exhausted or when NVENC fails to open
+func NewNVENCEncoder(o NVENCEncoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (e *Encoder, err error) {
+	// No codec name
+	if o.Encoder.Ctx.CodecName == "" {
+		err = errors.New("astilibav: no nvenc codec name")
+		return
+	}
+
+	// Assign GPU
+	if o.DeviceManager != nil {
+		o.GPU = o.DeviceManager.LeastLoadedGPU()
+	}
+
+	// Create hardware encoder
+	var reason string
+	if nvencSessions.acquire(o.GPU, o.MaxSessions) {
+		// Update options
+		ho := o.Encoder
+		ho.Ctx.Dict = ho.Ctx.Dict.withPair("gpu", strconv.Itoa(o.GPU))
+
+		// Create encoder
+		if e, err = NewEncoder(ho, eh, c); err == nil {
+			c.Add(func() error {
+				nvencSessions.release(o.GPU)
+				return nil
+			})
+			return
+		}
+
+		// Release session
+		nvencSessions.release(o.GPU)
+		reason = err.Error()
+	} else {
+		reason = fmt.Sprintf("max sessions %d reached on gpu %d", o.MaxSessions, o.GPU)
+	}
+
+	// No fallback
+	if o.FallbackCodecName == "" {
+		err = fmt.Errorf("astilibav: creating nvenc encoder failed: %s", reason)
+		return
+	}
+
+	// Create software encoder
+	so := o.Encoder
+	so.Ctx.CodecName = o.FallbackCodecName
+	if e, err = NewEncoder(so, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating fallback encoder failed: %w", err)
+		return
+	}
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name: HardwareFallbackTriggered,
+		Payload: HardwareFallback{
+			From:   o.Encoder.Ctx.CodecName,
+			GPU:    o.GPU,
+			Reason: reason,
+			To:     o.FallbackCodecName,
+		},
+		Target: e,
+	})
+	return
+}
+
+// NVDECDecoderOptions represents NVDEC decoder options
+type NVDECDecoderOptions struct {
+	// If CodecName is empty, the NVDEC decoder matching the codec params is used
+	Decoder DecoderOptions
+	// If set, GPU is ignored and the decoder is assigned to the least loaded GPU of the manager
+	DeviceManager *DeviceManager
+	// If true, an error is returned when NVDEC can't be used instead of falling back to a software decoder
+	DisableFallback bool
+	// Index of the GPU
+	GPU int
+	// Max number of NVDEC sessions opened on the GPU by all nodes. 0 means no limit
+	MaxSessions int
+}
+
+// nvdecCodecNames indexes NVDEC decoder names by codec names
+var nvdecCodecNames = map[string]string{
+	"av1":        "av1_cuvid",
+	"h264":       "h264_cuvid",
+	"hevc":       "hevc_cuvid",
+	"mjpeg":      "mjpeg_cuvid",
+	"mpeg1video": "mpeg1_cuvid",
+	"mpeg2video": "mpeg2_cuvid",
+	"mpeg4":      "mpeg4_cuvid",
+	"vc1":        "vc1_cuvid",
+	"vp8":        "vp8_cuvid",
+	"vp9":        "vp9_cuvid",
+}
+
+// NewNVDECDecoder creates a new decoder using NVDEC on the selected GPU and falls back to a software decoder when
+// sessions are exhausted, when the codec is not supported or when NVDEC fails to open
+// Frames are downloaded to system memory
+func NewNVDECDecoder(o NVDECDecoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (d *Decoder, err error) {
+	// Assign GPU
+	if o.DeviceManager != nil {
+		o.GPU = o.DeviceManager.LeastLoadedGPU()
+	}
+
+	// Get codec name
+	name := o.Decoder.CodecName
+	if name == "" {
+		name = nvdecCodecNames[avcodec.AvcodecGetName(o.Decoder.CodecParams.CodecId())]
+	}
+
+	// Create hardware decoder
+	var reason string
+	if name == "" {
+		reason = fmt.Sprintf("codec id %+v is not supported", o.Decoder.CodecParams.CodecId())
+	} else if nvdecSessions.acquire(o.GPU, o.MaxSessions) {
+		// Update options
+		ho := o.Decoder
+		ho.CodecName = name
+		ho.Dict = ho.Dict.withPair("gpu", strconv.Itoa(o.GPU))
+
+		// Create decoder
+		if d, err = NewDecoder(ho, eh, c); err == nil {
+			c.Add(func() error {
+				nvdecSessions.release(o.GPU)
+				return nil
+			})
+			return
+		}
+
+		// Release session
+		nvdecSessions.release(o.GPU)
+		reason = err.Error()
+	} else {
+		reason = fmt.Sprintf("max sessions %d reached on gpu %d", o.MaxSessions, o.GPU)
+	}
+
+	// No fallback
+	if o.DisableFallback {
+		err = fmt.Errorf("astilibav: creating nvdec decoder failed: %s", reason)
+		return
+	}
+
+	// Create software decoder
+	so := o.Decoder
+	so.CodecName = ""
+	if d, err = NewDecoder(so, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating fallback decoder failed: %w", err)
+		return
+	}
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name: HardwareFallbackTriggered,
+		Payload: HardwareFallback{
+			From:   name,
+			GPU:    o.GPU,
+			Reason: reason,
+		},
+		Target: d,
+	})
+	return
+}
@@ -0,0 +1,157 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// PipeInput represents a demuxer reading from a named pipe or stdin
+// Opening a named pipe blocks until a writer opens it, which can be cancelled with the demuxer's probe context, and
+// pending reads are unblocked once the node is stopped
+type PipeInput struct {
+	*Demuxer
+	f *os.File
+}
+
+// PipeInputOptions represents pipe input options
+type PipeInputOptions struct {
+	// If true and the named pipe doesn't exist, it is created
+	Create bool
+	// Reader and URL are set by the input
+	Demuxer DemuxerOptions
+	// Path of the named pipe. If empty or "-", stdin is used
+	Path string
+}
+
+// NewPipeInput creates a new pipe input
+func NewPipeInput(o PipeInputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (i *PipeInput, err error) {
+	// Get context
+	ctx := o.Demuxer.ProbeCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Open
+	i = &PipeInput{}
+	if i.f, err = openPipe(ctx, o.Path, o.Create, os.O_RDONLY); err != nil {
+		err = fmt.Errorf("astilibav: opening pipe failed: %w", err)
+		return
+	}
+
+	// Make sure the pipe is closed
+	c.Add(i.f.Close)
+
+	// Create demuxer
+	o.Demuxer.Reader = pipeFile{i.f}
+	o.Demuxer.URL = i.f.Name()
+	if i.Demuxer, err = NewDemuxer(o.Demuxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+	return
+}
+
+// Start starts the pipe input
+func (i *PipeInput) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	i.Demuxer.Start(ctx, t)
+	unblockPipeOnStop(i.Context(), i.f.SetReadDeadline)
+}
+
+// PipeOutput represents a muxer writing to a named pipe or stdout
+// Opening a named pipe blocks until a reader opens it, and pending writes are unblocked once the node is stopped
+type PipeOutput struct {
+	*Muxer
+	f *os.File
+}
+
+// PipeOutputOptions represents pipe output options
+type PipeOutputOptions struct {
+	// Context used to cancel opening the named pipe
+	Ctx context.Context
+	// If true and the named pipe doesn't exist, it is created
+	Create bool
+	// Format or FormatName must be provided. URL and Writer are set by the output
+	Muxer MuxerOptions
+	// Path of the named pipe. If empty or "-", stdout is used
+	Path string
+}
+
+// NewPipeOutput creates a new pipe output
+func NewPipeOutput(o PipeOutputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (out *PipeOutput, err error) {
+	// Format can't be guessed
+	if o.Muxer.Format == nil && o.Muxer.FormatName == "" {
+		err = errors.New("astilibav: no format provided")
+		return
+	}
+
+	// Get context
+	ctx := o.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Open
+	out = &PipeOutput{}
+	if out.f, err = openPipe(ctx, o.Path, o.Create, os.O_WRONLY); err != nil {
+		err = fmt.Errorf("astilibav: opening pipe failed: %w", err)
+		return
+	}
+
+	// Make sure the pipe is closed once the muxer is closed
+	c.Add(out.f.Close)
+
+	// Create muxer
+	o.Muxer.URL = out.f.Name()
+	o.Muxer.Writer = pipeFile{out.f}
+	if out.Muxer, err = NewMuxer(o.Muxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating muxer failed: %w", err)
+		return
+	}
+	return
+}
+
+// Start starts the pipe output
+func (o *PipeOutput) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	o.Muxer.Start(ctx, t)
+	unblockPipeOnStop(o.Context(), o.f.SetWriteDeadline)
+}
+
+func unblockPipeOnStop(ctx context.Context, setDeadline func(t time.Time) error) {
+	// Node has not been started
+	if ctx == nil {
+		return
+	}
+
+	// Deadlines are only supported when the pipe is non blocking
+	go func() {
+		<-ctx.Done()
+		setDeadline(time.Now())
+	}()
+}
+
+// pipeFile hides io.Seeker since pipes are not seekable, and converts deadline errors into io.EOF so that a stopped
+// node doesn't report an error
+type pipeFile struct {
+	f *os.File
+}
+
+func (f pipeFile) Read(p []byte) (n int, err error) {
+	if n, err = f.f.Read(p); err != nil && os.IsTimeout(err) {
+		err = io.EOF
+	}
+	return
+}
+
+func (f pipeFile) Write(p []byte) (n int, err error) {
+	if n, err = f.f.Write(p); err != nil && os.IsTimeout(err) {
+		err = io.EOF
+	}
+	return
+}
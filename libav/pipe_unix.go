@@ -0,0 +1,85 @@
+//go:build !windows
+// +build !windows
+
+package astilibav
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func openPipe(ctx context.Context, path string, create bool, flag int) (f *os.File, err error) {
+	// Stdio
+	if path == "" || path == "-" {
+		return openStdio(flag)
+	}
+
+	// Create named pipe
+	if create {
+		if _, err = os.Stat(path); os.IsNotExist(err) {
+			if err = syscall.Mkfifo(path, 0666); err != nil {
+				err = fmt.Errorf("astilibav: creating named pipe %s failed: %w", path, err)
+				return
+			}
+		} else if err != nil {
+			err = fmt.Errorf("astilibav: stating %s failed: %w", path, err)
+			return
+		}
+	}
+
+	// Opening a named pipe blocks until the other end is opened as well
+	type result struct {
+		err error
+		f   *os.File
+	}
+	ch := make(chan result, 1)
+	go func() {
+		f, err := os.OpenFile(path, flag, 0)
+		ch <- result{err: err, f: f}
+	}()
+
+	// Wait
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			err = fmt.Errorf("astilibav: opening %s failed: %w", path, r.err)
+			return
+		}
+		f = r.f
+	case <-ctx.Done():
+		// Open the other end in non blocking mode to unblock the pending open
+		other := os.O_RDONLY
+		if flag == os.O_RDONLY {
+			other = os.O_WRONLY
+		}
+		if o, errOpen := os.OpenFile(path, other|syscall.O_NONBLOCK, 0); errOpen == nil {
+			defer o.Close()
+		}
+
+		// Close the pending open
+		if r := <-ch; r.f != nil {
+			r.f.Close()
+		}
+		err = fmt.Errorf("astilibav: opening %s has been cancelled: %w", path, ctx.Err())
+	}
+	return
+}
+
+func openStdio(flag int) (f *os.File, err error) {
+	// Get file descriptor
+	fd, name := 0, "stdin"
+	if flag == os.O_WRONLY {
+		fd, name = 1, "stdout"
+	}
+
+	// Switch to non blocking mode so that the file is handled by the runtime poller and reads and writes can be
+	// unblocked with deadlines
+	if err = syscall.SetNonblock(fd, true); err != nil {
+		err = fmt.Errorf("astilibav: setting %s as non blocking failed: %w", name, err)
+		return
+	}
+	f = os.NewFile(uintptr(fd), name)
+	return
+}
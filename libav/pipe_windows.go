@@ -0,0 +1,31 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+func openPipe(ctx context.Context, path string, create bool, flag int) (f *os.File, err error) {
+	// Stdio
+	if path == "" || path == "-" {
+		if flag == os.O_WRONLY {
+			return os.Stdout, nil
+		}
+		return os.Stdin, nil
+	}
+
+	// Creating named pipes is not supported
+	if create {
+		err = errors.New("astilibav: creating named pipes is not supported on windows")
+		return
+	}
+
+	// Open
+	if f, err = os.OpenFile(path, flag, 0); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", path, err)
+		return
+	}
+	return
+}
@@ -1,8 +1,10 @@
 package astilibav
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -11,11 +13,30 @@ import (
 )
 
 // PktHandler represents a node that can handle a pkt
+// The payload and its pkt are released once HandlePkt returns, therefore handlers needing the pkt afterwards must
+// reference it
 type PktHandler interface {
 	astiencoder.Node
 	HandlePkt(p *PktHandlerPayload)
 }
 
+// PktBatchHandler represents a node that can handle several pkts at once, which reduces the per-pkt synchronization
+// overhead when the parent dispatches batches
+// Payloads and their pkts are released once HandlePktBatch returns
+type PktBatchHandler interface {
+	PktHandler
+	HandlePktBatch(ps []*PktHandlerPayload)
+}
+
+// PktBatchOptions represents pkt batch options
+// Pkts are dispatched once the batch is full or once its first pkt has waited for the max latency
+type PktBatchOptions struct {
+	// Default is 20ms
+	MaxLatency time.Duration
+	// Max number of pkts in a batch. 0 or 1 disables batching
+	MaxSize int
+}
+
 // PktHandlerConnector represents an object that can connect/disconnect with a pkt handler
 type PktHandlerConnector interface {
 	Connect(next PktHandler)
@@ -26,26 +47,105 @@ type PktHandlerConnector interface {
 type PktHandlerPayload struct {
 	Descriptor Descriptor
 	Pkt        *avcodec.Packet
+	// Only set when the pkt belongs to a traced GOP or is a latency probe
+	traceCtx context.Context
+}
+
+var pktHandlerPayloadPool = sync.Pool{New: func() interface{} { return &PktHandlerPayload{} }}
+
+func newPktHandlerPayload(pkt *avcodec.Packet, descriptor Descriptor) *PktHandlerPayload {
+	p := pktHandlerPayloadPool.Get().(*PktHandlerPayload)
+	p.Descriptor, p.Pkt = descriptor, pkt
+	return p
+}
+
+// releasePktHandlerPayload resets the payload so that handlers using it after being done fail fast instead of using a
+// recycled pkt
+func releasePktHandlerPayload(p *PktHandlerPayload) {
+	*p = PktHandlerPayload{}
+	pktHandlerPayloadPool.Put(p)
+}
+
+// refPktHandlerPayload returns a copy of the payload referencing its pkt so that it can be used once HandlePkt has
+// returned, e.g. when handing it over to a queue. It must be released with unrefPktHandlerPayload
+// The pkt is registered with the memory budget of the node's workflow and nil is returned if it should be dropped
+func refPktHandlerPayload(p *PktHandlerPayload, n astiencoder.Node) *PktHandlerPayload {
+	pkt := sharedPktPool.get()
+	pkt.AvPacketRef(p.Pkt)
+	if !trackPkt(pkt, nodeMemoryAccountant(n)) {
+		sharedPktPool.put(pkt)
+		return nil
+	}
+	r := newPktHandlerPayload(pkt, p.Descriptor)
+	r.traceCtx = p.traceCtx
+	return r
+}
+
+func unrefPktHandlerPayload(p *PktHandlerPayload) {
+	sharedPktPool.put(p.Pkt)
+	releasePktHandlerPayload(p)
 }
 
 type pktDispatcher struct {
+	b            *pktBatch
 	hs           map[string]PktHandler
+	hsBuf        []PktHandler
 	m            *sync.Mutex
 	p            *pktPool
+	statBitRate  *astikit.CounterRateStat
 	statDispatch *astikit.DurationPercentageStat
+	statRate     *astikit.CounterRateStat
 	wg           *sync.WaitGroup
 }
 
-func newPktDispatcher(c *astikit.Closer) *pktDispatcher {
+type pktBatch struct {
+	firstAt       time.Time
+	items         []pktBatchItem
+	m             *sync.Mutex
+	o             PktBatchOptions
+	statBatchSize *astikit.CounterAvgStat
+	t             *time.Timer
+}
+
+type pktBatchItem struct {
+	descriptor Descriptor
+	pkt        *avcodec.Packet
+	traceCtx   context.Context
+}
+
+func newPktDispatcher() *pktDispatcher {
 	return &pktDispatcher{
 		hs:           make(map[string]PktHandler),
 		m:            &sync.Mutex{},
-		p:            newPktPool(c),
+		p:            sharedPktPool,
+		statBitRate:  astikit.NewCounterRateStat(),
 		statDispatch: astikit.NewDurationPercentageStat(),
+		statRate:     astikit.NewCounterRateStat(),
 		wg:           &sync.WaitGroup{},
 	}
 }
 
+// setBatch makes the dispatcher send pkts to handlers in batches
+// It must be called before the first dispatch
+func (d *pktDispatcher) setBatch(o PktBatchOptions) {
+	// Batching is disabled
+	if o.MaxSize <= 1 {
+		return
+	}
+
+	// Default options
+	if o.MaxLatency <= 0 {
+		o.MaxLatency = 20 * time.Millisecond
+	}
+
+	// Create batch
+	d.b = &pktBatch{
+		m:             &sync.Mutex{},
+		o:             o,
+		statBatchSize: astikit.NewCounterAvgStat(),
+	}
+}
+
 func (d *pktDispatcher) addHandler(h PktHandler) {
 	d.m.Lock()
 	defer d.m.Unlock()
@@ -59,15 +159,32 @@ func (d *pktDispatcher) delHandler(h PktHandler) {
 }
 
 func (d *pktDispatcher) dispatch(pkt *avcodec.Packet, descriptor Descriptor) {
+	d.dispatchWithTrace(nil, pkt, descriptor)
+}
+
+// dispatchWithTrace dispatches a pkt belonging to a traced GOP or being a latency probe when traceCtx is not nil
+func (d *pktDispatcher) dispatchWithTrace(traceCtx context.Context, pkt *avcodec.Packet, descriptor Descriptor) {
+	// Increment outgoing stats
+	d.statRate.Add(1)
+	d.statBitRate.Add(float64(pkt.Size()*8) / 1000)
+
+	// Batch
+	if d.b != nil {
+		d.batch(traceCtx, pkt, descriptor)
+		return
+	}
+
 	// Copy handlers
+	// The buffer can be reused since dispatches are sequential and handlers are passed by value to subprocesses
 	d.m.Lock()
-	var hs []PktHandler
+	hs := d.hsBuf[:0]
 	for _, h := range d.hs {
 		v, ok := h.(PktCond)
 		if !ok || v.UsePkt(pkt) {
 			hs = append(hs, h)
 		}
 	}
+	d.hsBuf = hs
 	d.m.Unlock()
 
 	// No handlers
@@ -91,19 +208,152 @@ func (d *pktDispatcher) dispatch(pkt *avcodec.Packet, descriptor Descriptor) {
 		hPkt := d.p.get()
 		hPkt.AvPacketRef(pkt)
 
+		// Create payload
+		p := newPktHandlerPayload(hPkt, descriptor)
+		p.traceCtx = traceCtx
+
 		// Handle pkt
-		go func(h PktHandler) {
+		go func(h PktHandler, p *PktHandlerPayload) {
+			defer d.wg.Done()
+			defer d.p.put(p.Pkt)
+			defer releasePktHandlerPayload(p)
+			h.HandlePkt(p)
+		}(h, p)
+	}
+}
+
+func (d *pktDispatcher) batch(traceCtx context.Context, pkt *avcodec.Packet, descriptor Descriptor) {
+	// Lock
+	d.b.m.Lock()
+	defer d.b.m.Unlock()
+
+	// Copy pkt
+	bPkt := d.p.get()
+	bPkt.AvPacketRef(pkt)
+
+	// Append item
+	now := time.Now()
+	if len(d.b.items) == 0 {
+		d.b.firstAt = now
+	}
+	d.b.items = append(d.b.items, pktBatchItem{
+		descriptor: descriptor,
+		pkt:        bPkt,
+		traceCtx:   traceCtx,
+	})
+
+	// Batch is full or too late
+	if len(d.b.items) >= d.b.o.MaxSize || now.Sub(d.b.firstAt) >= d.b.o.MaxLatency {
+		d.flush()
+		return
+	}
+
+	// Make sure the batch is dispatched on time even if no other pkt comes in
+	if len(d.b.items) == 1 {
+		d.b.t = time.AfterFunc(d.b.o.MaxLatency, func() {
+			d.b.m.Lock()
+			defer d.b.m.Unlock()
+			d.flush()
+		})
+	}
+}
+
+// flush dispatches the pending batch
+// It must be called while holding the batch lock, which keeps batches in order
+func (d *pktDispatcher) flush() {
+	// Stop timer
+	if d.b.t != nil {
+		d.b.t.Stop()
+		d.b.t = nil
+	}
+
+	// Nothing to flush
+	if len(d.b.items) == 0 {
+		return
+	}
+
+	// Reset items
+	items := d.b.items
+	d.b.items = nil
+	d.b.statBatchSize.Add(float64(len(items)))
+
+	// Make sure to release items
+	defer func() {
+		for _, i := range items {
+			d.p.put(i.pkt)
+		}
+	}()
+
+	// Copy handlers
+	d.m.Lock()
+	hs := make([]PktHandler, 0, len(d.hs))
+	for _, h := range d.hs {
+		hs = append(hs, h)
+	}
+	d.m.Unlock()
+
+	// Wait for all previous subprocesses to be done
+	d.statDispatch.Begin()
+	d.wg.Wait()
+	d.statDispatch.End()
+
+	// Loop through handlers
+	for _, h := range hs {
+		// Copy pkts
+		var ps []*PktHandlerPayload
+		v, isCond := h.(PktCond)
+		for _, i := range items {
+			if !isCond || v.UsePkt(i.pkt) {
+				hPkt := d.p.get()
+				hPkt.AvPacketRef(i.pkt)
+				p := newPktHandlerPayload(hPkt, i.descriptor)
+				p.traceCtx = i.traceCtx
+				ps = append(ps, p)
+			}
+		}
+
+		// No pkts
+		if len(ps) == 0 {
+			continue
+		}
+
+		// Handle pkts
+		d.wg.Add(1)
+		go func(h PktHandler, ps []*PktHandlerPayload) {
 			defer d.wg.Done()
-			defer d.p.put(hPkt)
-			h.HandlePkt(&PktHandlerPayload{
-				Descriptor: descriptor,
-				Pkt:        hPkt,
-			})
-		}(h)
+			defer func() {
+				for _, p := range ps {
+					d.p.put(p.Pkt)
+					releasePktHandlerPayload(p)
+				}
+			}()
+			if bh, ok := pktBatchHandlerOf(h); ok {
+				bh.HandlePktBatch(ps)
+			} else {
+				for _, p := range ps {
+					h.HandlePkt(p)
+				}
+			}
+		}(h, ps)
+	}
+}
+
+// pktBatchHandlerOf returns the batch handler behind a handler, if any
+func pktBatchHandlerOf(h PktHandler) (PktBatchHandler, bool) {
+	if c, ok := h.(*pktCond); ok {
+		h = c.PktHandler
 	}
+	bh, ok := h.(PktBatchHandler)
+	return bh, ok
 }
 
 func (d *pktDispatcher) wait() {
+	// Flush pending batch
+	if d.b != nil {
+		d.b.m.Lock()
+		d.flush()
+		d.b.m.Unlock()
+	}
 	d.wg.Wait()
 }
 
@@ -114,6 +364,29 @@ func (d *pktDispatcher) addStats(s *astikit.Stater) {
 		Label:       "Dispatch ratio",
 		Unit:        "%",
 	}, d.statDispatch)
+
+	// Add outgoing rate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of pkts going out per second",
+		Label:       "Outgoing rate",
+		Unit:        "pps",
+	}, d.statRate)
+
+	// Add output bitrate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of kilobits going out per second",
+		Label:       "Output bitrate",
+		Unit:        "kbps",
+	}, d.statBitRate)
+
+	// Add batch size
+	if d.b != nil {
+		s.AddStat(astikit.StatMetadata{
+			Description: "Average number of pkts per dispatched batch",
+			Label:       "Batch size",
+			Unit:        "pkts",
+		}, d.b.statBatchSize)
+	}
 }
 
 // PktCond represents an object that can decide whether to use a pkt
@@ -144,39 +417,3 @@ func (c *pktCond) Metadata() astiencoder.NodeMetadata {
 func (c *pktCond) UsePkt(pkt *avcodec.Packet) bool {
 	return pkt.StreamIndex() == c.i.Index()
 }
-
-type pktPool struct {
-	c *astikit.Closer
-	m *sync.Mutex
-	p []*avcodec.Packet
-}
-
-func newPktPool(c *astikit.Closer) *pktPool {
-	return &pktPool{
-		c: c,
-		m: &sync.Mutex{},
-	}
-}
-
-func (p *pktPool) get() (pkt *avcodec.Packet) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	if len(p.p) == 0 {
-		pkt = avcodec.AvPacketAlloc()
-		p.c.Add(func() error {
-			avcodec.AvPacketFree(pkt)
-			return nil
-		})
-		return
-	}
-	pkt = p.p[0]
-	p.p = p.p[1:]
-	return
-}
-
-func (p *pktPool) put(pkt *avcodec.Packet) {
-	p.m.Lock()
-	defer p.m.Unlock()
-	pkt.AvPacketUnref()
-	p.p = append(p.p, pkt)
-}
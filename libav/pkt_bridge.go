@@ -0,0 +1,377 @@
+package astilibav
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+var (
+	countPktReceiver uint64
+	countPktSender   uint64
+)
+
+// PktBridge represents an in-process registry through which packets are moved between independent workflows
+// Packets sent on a channel are copied to every running receiver of that channel, so that each workflow keeps its
+// own nodes, closer and context and stopping or failing one of them doesn't impact the others
+// A channel is deleted once all its senders and receivers have been closed
+type PktBridge struct {
+	cs map[string]*pktBridgeChannel
+	m  *sync.Mutex
+}
+
+type pktBridgeChannel struct {
+	ctx Context
+	// Number of senders and receivers that have not been closed yet
+	refs int
+	// Running receivers
+	rs map[*PktReceiver]bool
+}
+
+// NewPktBridge creates a new pkt bridge
+func NewPktBridge() *PktBridge {
+	return &PktBridge{
+		cs: make(map[string]*pktBridgeChannel),
+		m:  &sync.Mutex{},
+	}
+}
+
+// ref creates the channel if needed and references it until unref is called
+func (b *PktBridge) ref(name string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	c, ok := b.cs[name]
+	if !ok {
+		c = &pktBridgeChannel{rs: make(map[*PktReceiver]bool)}
+		b.cs[name] = c
+	}
+	c.refs++
+}
+
+// unref deletes the channel once its last sender or receiver is closed
+func (b *PktBridge) unref(name string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	c, ok := b.cs[name]
+	if !ok {
+		return
+	}
+	if c.refs--; c.refs <= 0 && len(c.rs) == 0 {
+		delete(b.cs, name)
+	}
+}
+
+func (b *PktBridge) setCtx(name string, ctx Context) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if c, ok := b.cs[name]; ok {
+		c.ctx = ctx
+	}
+}
+
+func (b *PktBridge) ctx(name string) (ctx Context) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if c, ok := b.cs[name]; ok {
+		ctx = c.ctx
+	}
+	return
+}
+
+func (b *PktBridge) addReceiver(name string, r *PktReceiver) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if c, ok := b.cs[name]; ok {
+		c.rs[r] = true
+	}
+}
+
+func (b *PktBridge) delReceiver(name string, r *PktReceiver) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	c, ok := b.cs[name]
+	if !ok {
+		return
+	}
+	if delete(c.rs, r); c.refs <= 0 && len(c.rs) == 0 {
+		delete(b.cs, name)
+	}
+}
+
+func (b *PktBridge) receivers(name string) (rs []*PktReceiver) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if c, ok := b.cs[name]; ok {
+		for r := range c.rs {
+			rs = append(rs, r)
+		}
+	}
+	return
+}
+
+func (b *PktBridge) channels() (ns []string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for n := range b.cs {
+		ns = append(ns, n)
+	}
+	return
+}
+
+// PktSender represents an object capable of sending packets to the receivers of a pkt bridge channel
+type PktSender struct {
+	*astiencoder.BaseNode
+	b                *PktBridge
+	c                *astiencoder.Queue
+	name             string
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// PktSenderOptions represents pkt sender options
+type PktSenderOptions struct {
+	Bridge *PktBridge
+	// Name of the channel
+	Name string
+	Node astiencoder.NodeOptions
+	// Ctx of the packets, made available to receivers
+	OutputCtx Context
+}
+
+// NewPktSender creates a new pkt sender
+func NewPktSender(o PktSenderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (s *PktSender) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countPktSender, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("pkt_sender_%d", count), fmt.Sprintf("Pkt Sender #%d", count), fmt.Sprintf("Sends to %s", o.Name), "pkt sender")
+
+	// Create sender
+	s = &PktSender{
+		b:                o.Bridge,
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		name:             o.Name,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	s.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(s), eh)
+	s.addStats()
+
+	// Reference channel
+	s.b.ref(o.Name)
+	c.Add(func() error {
+		s.b.unref(o.Name)
+		return nil
+	})
+
+	// Store ctx
+	s.b.setCtx(o.Name, o.OutputCtx)
+	return
+}
+
+func (s *PktSender) addStats() {
+	// Add incoming rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, s.statIncomingRate)
+
+	// Add work ratio
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, s.statWorkRatio)
+
+	// Add chan stats
+	s.c.AddStats(s.Stater())
+}
+
+// Start starts the sender
+func (s *PktSender) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	s.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to stop the chan properly
+		defer s.c.Stop()
+
+		// Start chan
+		s.c.Start(s.Context())
+	})
+}
+
+// HandlePkt implements the PktHandler interface
+func (s *PktSender) HandlePkt(p *PktHandlerPayload) {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be sent
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, s); p == nil {
+		return
+	}
+	s.c.AddWithRelease(func() {
+		// Handle pause
+		defer s.HandlePause()
+
+		// Increment incoming rate
+		s.statIncomingRate.Add(1)
+
+		// Loop through receivers
+		s.statWorkRatio.Begin()
+		for _, r := range s.b.receivers(s.name) {
+			r.push(p)
+		}
+		s.statWorkRatio.End()
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
+}
+
+// PktReceiver represents an object capable of receiving packets from a pkt bridge channel and dispatching them to
+// the nodes of its own workflow
+type PktReceiver struct {
+	*astiencoder.BaseNode
+	b                *PktBridge
+	bufferSize       int64
+	c                *astikit.Chan
+	d                *pktDispatcher
+	name             string
+	p                *pktPool
+	queued           int64
+	statDropped      *astikit.CounterRateStat
+	statIncomingRate *astikit.CounterRateStat
+}
+
+// PktReceiverOptions represents pkt receiver options
+type PktReceiverOptions struct {
+	Bridge *PktBridge
+	// Max number of packets waiting to be dispatched. Additional packets are dropped so that a slow receiver doesn't
+	// slow down the sender. 0 means no limit
+	BufferSize int
+	// Name of the channel
+	Name string
+	Node astiencoder.NodeOptions
+}
+
+// NewPktReceiver creates a new pkt receiver
+func NewPktReceiver(o PktReceiverOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (r *PktReceiver) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countPktReceiver, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("pkt_receiver_%d", count), fmt.Sprintf("Pkt Receiver #%d", count), fmt.Sprintf("Receives from %s", o.Name), "pkt receiver")
+
+	// Create receiver
+	r = &PktReceiver{
+		b:          o.Bridge,
+		bufferSize: int64(o.BufferSize),
+		c: astikit.NewChan(astikit.ChanOptions{
+			AddStrategy: astikit.ChanAddStrategyNoBlock,
+			ProcessAll:  true,
+		}),
+		d:                newPktDispatcher(),
+		name:             o.Name,
+		p:                sharedPktPool,
+		statDropped:      astikit.NewCounterRateStat(),
+		statIncomingRate: astikit.NewCounterRateStat(),
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
+	r.addStats()
+
+	// Reference channel
+	r.b.ref(o.Name)
+	c.Add(func() error {
+		r.b.unref(o.Name)
+		return nil
+	})
+	return
+}
+
+func (r *PktReceiver) addStats() {
+	// Add incoming rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, r.statIncomingRate)
+
+	// Add dropped rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets dropped per second",
+		Label:       "Dropped rate",
+		Unit:        "pps",
+	}, r.statDropped)
+
+	// Add dispatcher stats
+	r.d.addStats(r.Stater())
+
+	// Add chan stats
+	r.c.AddStats(r.Stater())
+}
+
+// OutputCtx returns the ctx provided by the sender of the channel
+func (r *PktReceiver) OutputCtx() Context {
+	return r.b.ctx(r.name)
+}
+
+// Connect implements the PktHandlerConnector interface
+func (r *PktReceiver) Connect(h PktHandler) {
+	// Add handler
+	r.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(r, h)
+}
+
+// Disconnect implements the PktHandlerConnector interface
+func (r *PktReceiver) Disconnect(h PktHandler) {
+	// Delete handler
+	r.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(r, h)
+}
+
+// Start starts the receiver
+func (r *PktReceiver) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Packets are only received while the receiver is running
+		r.b.addReceiver(r.name, r)
+		defer r.b.delReceiver(r.name, r)
+
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer r.d.wait()
+
+		// Make sure to stop the chan properly
+		defer r.c.Stop()
+
+		// Start chan
+		r.c.Start(r.Context())
+	})
+}
+
+func (r *PktReceiver) push(p *PktHandlerPayload) {
+	// Increment incoming rate
+	r.statIncomingRate.Add(1)
+
+	// Buffer is full
+	if r.bufferSize > 0 && atomic.LoadInt64(&r.queued) >= r.bufferSize {
+		r.statDropped.Add(1)
+		return
+	}
+
+	// Copy pkt since the sender's pkt is released once handled
+	pkt := r.p.get()
+	pkt.AvPacketRef(p.Pkt)
+	descriptor := p.Descriptor
+
+	// Add to chan
+	atomic.AddInt64(&r.queued, 1)
+	r.c.Add(func() {
+		// Handle pause
+		defer r.HandlePause()
+
+		// Make sure the pkt is released
+		defer r.p.put(pkt)
+		atomic.AddInt64(&r.queued, -1)
+
+		// Dispatch pkt
+		r.d.dispatch(pkt, descriptor)
+	})
+}
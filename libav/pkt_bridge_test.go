@@ -0,0 +1,28 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPktBridgeChannels(t *testing.T) {
+	// Create nodes
+	b := NewPktBridge()
+	eh := astiencoder.NewEventHandler()
+	cs, cr1, cr2 := astikit.NewCloser(), astikit.NewCloser(), astikit.NewCloser()
+	NewPktSender(PktSenderOptions{Bridge: b, Name: "c", OutputCtx: Context{CodecName: "h264"}}, eh, cs)
+	r := NewPktReceiver(PktReceiverOptions{Bridge: b, Name: "c"}, eh, cr1)
+	NewPktReceiver(PktReceiverOptions{Bridge: b, Name: "c"}, eh, cr2)
+	assert.Equal(t, []string{"c"}, b.channels())
+
+	// Channel is kept while it has a sender or a receiver
+	assert.NoError(t, cs.Close())
+	assert.Equal(t, Context{CodecName: "h264"}, r.OutputCtx())
+	assert.NoError(t, cr1.Close())
+	assert.Equal(t, []string{"c"}, b.channels())
+	assert.NoError(t, cr2.Close())
+	assert.Empty(t, b.channels())
+}
@@ -20,7 +20,7 @@ var countPktDumper uint64
 // PktDumper represents an object capable of dumping packets
 type PktDumper struct {
 	*astiencoder.BaseNode
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	count            uint32
 	eh               *astiencoder.EventHandler
 	o                PktDumperOptions
@@ -50,10 +50,7 @@ func NewPktDumper(o PktDumperOptions, eh *astiencoder.EventHandler) (d *PktDumpe
 
 	// Create pkt dumper
 	d = &PktDumper{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c:                astiencoder.NewQueue(o.Node.Queue),
 		eh:               eh,
 		o:                o,
 		statIncomingRate: astikit.NewCounterRateStat(),
@@ -104,7 +101,13 @@ func (d *PktDumper) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
 
 // HandlePkt implements the PktHandler interface
 func (d *PktDumper) HandlePkt(p *PktHandlerPayload) {
-	d.c.Add(func() {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be dumped
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, d); p == nil {
+		return
+	}
+	d.c.AddWithRelease(func() {
 		// Handle pause
 		defer d.HandlePause()
 
@@ -144,7 +147,7 @@ func (d *PktDumper) HandlePkt(p *PktHandlerPayload) {
 			return
 		}
 		d.statWorkRatio.End()
-	})
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
 }
 
 // PktDumpFunc is a PktDumpFunc that dumps the packet to a file
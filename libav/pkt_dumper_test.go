@@ -0,0 +1,50 @@
+package astilibav
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPktDumperHandlesPktsQueuedBeforeStart(t *testing.T) {
+	// Create pkt dumper
+	m := &sync.Mutex{}
+	var pts []int64
+	d, err := NewPktDumper(PktDumperOptions{Handler: func(pkt *avcodec.Packet, args PktDumperHandlerArgs) error {
+		m.Lock()
+		defer m.Unlock()
+		pts = append(pts, pkt.Pts())
+		return nil
+	}}, astiencoder.NewEventHandler())
+	assert.NoError(t, err)
+
+	// Pkts are queued before the node is started, and are released once handled as the dispatcher does
+	for i := int64(1); i <= 3; i++ {
+		pkt := sharedPktPool.get()
+		pkt.SetPts(i)
+		p := newPktHandlerPayload(pkt, &filtererDescriptor{})
+		d.HandlePkt(p)
+		releasePktHandlerPayload(p)
+		sharedPktPool.put(pkt)
+	}
+
+	// Start
+	w := astikit.NewWorker(astikit.WorkerOptions{})
+	defer w.Stop()
+	d.Start(context.Background(), w.NewTask)
+	defer d.Stop()
+
+	// Queued pkts are still valid
+	assert.Eventually(t, func() bool {
+		m.Lock()
+		defer m.Unlock()
+		return len(pts) == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int64{1, 2, 3}, pts)
+}
@@ -0,0 +1,107 @@
+package astilibav
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerPayloadPool(t *testing.T) {
+	d := &filtererDescriptor{}
+
+	pkt := &avcodec.Packet{}
+	pp := newPktHandlerPayload(pkt, d)
+	assert.Equal(t, pkt, pp.Pkt)
+	assert.Equal(t, d, pp.Descriptor)
+	releasePktHandlerPayload(pp)
+	assert.Equal(t, PktHandlerPayload{}, *pp)
+
+	f := &avutil.Frame{}
+	fp := newFrameHandlerPayload(f, d, nil)
+	assert.Equal(t, f, fp.Frame)
+	assert.Equal(t, d, fp.Descriptor)
+	releaseFrameHandlerPayload(fp)
+	assert.Equal(t, FrameHandlerPayload{}, *fp)
+}
+
+type mockedPktHandler struct {
+	astiencoder.Node
+	batches []int
+	m       *sync.Mutex
+	name    string
+	pkts    int
+}
+
+func newMockedPktHandler(name string) *mockedPktHandler {
+	return &mockedPktHandler{
+		m:    &sync.Mutex{},
+		name: name,
+	}
+}
+
+func (h *mockedPktHandler) Metadata() astiencoder.NodeMetadata {
+	return astiencoder.NodeMetadata{Name: h.name}
+}
+
+func (h *mockedPktHandler) HandlePkt(p *PktHandlerPayload) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.pkts++
+}
+
+type mockedPktBatchHandler struct {
+	*mockedPktHandler
+}
+
+func (h *mockedPktBatchHandler) HandlePktBatch(ps []*PktHandlerPayload) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.batches = append(h.batches, len(ps))
+	h.pkts += len(ps)
+}
+
+func TestPktDispatcherBatch(t *testing.T) {
+	d := newPktDispatcher()
+	d.setBatch(PktBatchOptions{MaxLatency: time.Hour, MaxSize: 3})
+	h1 := newMockedPktHandler("h1")
+	h2 := &mockedPktBatchHandler{mockedPktHandler: newMockedPktHandler("h2")}
+	d.addHandler(h1)
+	d.addHandler(h2)
+
+	pkt := avcodec.AvPacketAlloc()
+	defer avcodec.AvPacketFree(pkt)
+
+	// Batch is full
+	for i := 0; i < 4; i++ {
+		d.dispatch(pkt, &filtererDescriptor{})
+	}
+	d.wg.Wait()
+	assert.Equal(t, 3, h1.pkts)
+	assert.Equal(t, []int{3}, h2.batches)
+
+	// Pending batch is flushed on wait
+	d.wait()
+	assert.Equal(t, 4, h1.pkts)
+	assert.Equal(t, []int{3, 1}, h2.batches)
+
+	// Batch is too late
+	d.b.o.MaxLatency = 10 * time.Millisecond
+	d.dispatch(pkt, &filtererDescriptor{})
+	assert.Eventually(t, func() bool {
+		h2.m.Lock()
+		defer h2.m.Unlock()
+		return len(h2.batches) == 3
+	}, time.Second, 5*time.Millisecond)
+	d.wait()
+	assert.Equal(t, []int{3, 1, 1}, h2.batches)
+
+	// Batching is disabled
+	d = newPktDispatcher()
+	d.setBatch(PktBatchOptions{MaxSize: 1})
+	assert.Nil(t, d.b)
+}
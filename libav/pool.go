@@ -0,0 +1,222 @@
+package astilibav
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+// Frames and packets are moved between nodes in shells taken from pools shared by all nodes. Since their data is
+// reference counted by ffmpeg, dispatching a frame or a packet to several handlers only references its buffers and
+// never copies them. Once released, shells are unreferenced and kept idle so that they can be reused by any node.
+// Shells buffered by nodes are registered with the memory budget of their workflow, see sharedMemoryTracker
+var (
+	sharedFramePool = newFramePool()
+	sharedPktPool   = newPktPool()
+)
+
+const defaultPoolMaxIdle = 256
+
+// SetPoolMaxIdle sets the max number of idle frames and of idle packets kept by the shared pools. Shells released
+// while the max is reached are freed. Default is 256
+func SetPoolMaxIdle(n int) {
+	sharedFramePool.setMaxIdle(n)
+	sharedPktPool.setMaxIdle(n)
+}
+
+// PoolStat represents the stats of a pool
+type PoolStat struct {
+	// Number of shells allocated since the beginning
+	Allocated uint64
+	// Number of shells freed since the beginning
+	Freed uint64
+	Idle  int
+	InUse int
+	// Number of shells taken from the pool that have been reused instead of allocated
+	Reused uint64
+}
+
+// PoolStats represents the stats of the shared pools
+// It is the payload of the PoolStatsReported event
+type PoolStats struct {
+	Frames PoolStat
+	Pkts   PoolStat
+}
+
+// GetPoolStats returns the stats of the shared pools
+func GetPoolStats() PoolStats {
+	return PoolStats{
+		Frames: sharedFramePool.stat(),
+		Pkts:   sharedPktPool.stat(),
+	}
+}
+
+// PoolStatsReporter represents an object capable of periodically emitting the stats of the shared pools
+type PoolStatsReporter struct {
+	eh *astiencoder.EventHandler
+	o  PoolStatsReporterOptions
+}
+
+// PoolStatsReporterOptions represents pool stats reporter options
+type PoolStatsReporterOptions struct {
+	// Default is 5s
+	Period time.Duration
+}
+
+// NewPoolStatsReporter creates a new pool stats reporter
+func NewPoolStatsReporter(o PoolStatsReporterOptions, eh *astiencoder.EventHandler) *PoolStatsReporter {
+	if o.Period <= 0 {
+		o.Period = 5 * time.Second
+	}
+	return &PoolStatsReporter{
+		eh: eh,
+		o:  o,
+	}
+}
+
+// Start emits stats until the context is done
+func (r *PoolStatsReporter) Start(ctx context.Context) {
+	t := time.NewTicker(r.o.Period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			r.eh.Emit(astiencoder.Event{
+				Name:    PoolStatsReported,
+				Payload: GetPoolStats(),
+				Target:  r,
+			})
+		}
+	}
+}
+
+type poolCounters struct {
+	allocated uint64
+	freed     uint64
+	inUse     int
+	maxIdle   int
+	reused    uint64
+}
+
+func (c *poolCounters) stat(idle int) PoolStat {
+	return PoolStat{
+		Allocated: c.allocated,
+		Freed:     c.freed,
+		Idle:      idle,
+		InUse:     c.inUse,
+		Reused:    c.reused,
+	}
+}
+
+type framePool struct {
+	c poolCounters
+	m *sync.Mutex
+	p []*avutil.Frame
+}
+
+func newFramePool() *framePool {
+	return &framePool{
+		c: poolCounters{maxIdle: defaultPoolMaxIdle},
+		m: &sync.Mutex{},
+	}
+}
+
+func (p *framePool) setMaxIdle(n int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.maxIdle = n
+}
+
+func (p *framePool) stat() PoolStat {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.c.stat(len(p.p))
+}
+
+func (p *framePool) get() (f *avutil.Frame) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.inUse++
+	if len(p.p) == 0 {
+		p.c.allocated++
+		return avutil.AvFrameAlloc()
+	}
+	p.c.reused++
+	f = p.p[len(p.p)-1]
+	p.p = p.p[:len(p.p)-1]
+	return
+}
+
+func (p *framePool) put(f *avutil.Frame) {
+	sharedMemoryTracker.untrack(unsafe.Pointer(f))
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.inUse--
+	if len(p.p) >= p.c.maxIdle {
+		p.c.freed++
+		avutil.AvFrameFree(f)
+		return
+	}
+	avutil.AvFrameUnref(f)
+	p.p = append(p.p, f)
+}
+
+type pktPool struct {
+	c poolCounters
+	m *sync.Mutex
+	p []*avcodec.Packet
+}
+
+func newPktPool() *pktPool {
+	return &pktPool{
+		c: poolCounters{maxIdle: defaultPoolMaxIdle},
+		m: &sync.Mutex{},
+	}
+}
+
+func (p *pktPool) setMaxIdle(n int) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.maxIdle = n
+}
+
+func (p *pktPool) stat() PoolStat {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.c.stat(len(p.p))
+}
+
+func (p *pktPool) get() (pkt *avcodec.Packet) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.inUse++
+	if len(p.p) == 0 {
+		p.c.allocated++
+		return avcodec.AvPacketAlloc()
+	}
+	p.c.reused++
+	pkt = p.p[len(p.p)-1]
+	p.p = p.p[:len(p.p)-1]
+	return
+}
+
+func (p *pktPool) put(pkt *avcodec.Packet) {
+	sharedMemoryTracker.untrack(unsafe.Pointer(pkt))
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.c.inUse--
+	if len(p.p) >= p.c.maxIdle {
+		p.c.freed++
+		avcodec.AvPacketFree(pkt)
+		return
+	}
+	pkt.AvPacketUnref()
+	p.p = append(p.p, pkt)
+}
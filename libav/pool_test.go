@@ -0,0 +1,19 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	p := newPktPool()
+	p.setMaxIdle(1)
+	pkt1, pkt2 := p.get(), p.get()
+	assert.Equal(t, PoolStat{Allocated: 2, InUse: 2}, p.stat())
+	p.put(pkt1)
+	p.put(pkt2)
+	assert.Equal(t, PoolStat{Allocated: 2, Freed: 1, Idle: 1}, p.stat())
+	p.get()
+	assert.Equal(t, PoolStat{Allocated: 2, Freed: 1, InUse: 1, Reused: 1}, p.stat())
+}
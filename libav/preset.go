@@ -0,0 +1,203 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/channel_layout.h>
+#include <libavutil/pixdesc.h>
+#include <libavutil/samplefmt.h>
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+// Preset represents named encoding settings applied on top of an encoder context
+// Zero values keep the context's values. Presets don't scale nor resample: frames must already match their
+// dimensions, pixel format, sample rate and sample format
+type Preset struct {
+	BitRate int `json:"bit_rate,omitempty"`
+	// e.g. "stereo"
+	ChannelLayout string `json:"channel_layout,omitempty"`
+	CodecName     string `json:"codec_name,omitempty"`
+	// Pairs are added to the context's dict, e.g. "preset=veryfast,tune=zerolatency"
+	Dict string `json:"dict,omitempty"`
+	// e.g. "30" or "30000/1001"
+	FrameRate string `json:"frame_rate,omitempty"`
+	GopSize   int    `json:"gop_size,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	// e.g. "yuv420p"
+	PixelFormat string `json:"pixel_format,omitempty"`
+	// e.g. "fltp"
+	SampleFmt  string `json:"sample_fmt,omitempty"`
+	SampleRate int    `json:"sample_rate,omitempty"`
+	Width      int    `json:"width,omitempty"`
+}
+
+// Apply returns the context with the preset's settings
+func (p Preset) Apply(ctx Context) (Context, error) {
+	// Shared
+	if p.BitRate > 0 {
+		ctx.BitRate = p.BitRate
+	}
+	if p.CodecName != "" {
+		ctx.CodecName = p.CodecName
+	}
+	if p.Dict != "" {
+		for _, pair := range strings.Split(p.Dict, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				return ctx, fmt.Errorf("astilibav: invalid dict pair %s", pair)
+			}
+			ctx.Dict = ctx.Dict.withPair(kv[0], kv[1])
+		}
+	}
+
+	// Audio
+	if p.ChannelLayout != "" {
+		cs := C.CString(p.ChannelLayout)
+		defer C.free(unsafe.Pointer(cs))
+		l := uint64(C.av_get_channel_layout(cs))
+		if l == 0 {
+			return ctx, fmt.Errorf("astilibav: unknown channel layout %s", p.ChannelLayout)
+		}
+		ctx.ChannelLayout = l
+		ctx.Channels = int(C.av_get_channel_layout_nb_channels(C.uint64_t(l)))
+	}
+	if p.SampleFmt != "" {
+		cs := C.CString(p.SampleFmt)
+		defer C.free(unsafe.Pointer(cs))
+		f := C.av_get_sample_fmt(cs)
+		if f == C.AV_SAMPLE_FMT_NONE {
+			return ctx, fmt.Errorf("astilibav: unknown sample format %s", p.SampleFmt)
+		}
+		ctx.SampleFmt = avcodec.AvSampleFormat(f)
+	}
+	if p.SampleRate > 0 {
+		ctx.SampleRate = p.SampleRate
+	}
+
+	// Video
+	if p.FrameRate != "" {
+		r, err := parsePresetFrameRate(p.FrameRate)
+		if err != nil {
+			return ctx, fmt.Errorf("astilibav: parsing frame rate failed: %w", err)
+		}
+		ctx.FrameRate = r
+	}
+	if p.GopSize > 0 {
+		ctx.GopSize = p.GopSize
+	}
+	if p.Height > 0 {
+		ctx.Height = p.Height
+	}
+	if p.PixelFormat != "" {
+		cs := C.CString(p.PixelFormat)
+		defer C.free(unsafe.Pointer(cs))
+		f := C.av_get_pix_fmt(cs)
+		if f == C.AV_PIX_FMT_NONE {
+			return ctx, fmt.Errorf("astilibav: unknown pixel format %s", p.PixelFormat)
+		}
+		ctx.PixelFormat = avutil.PixelFormat(f)
+	}
+	if p.Width > 0 {
+		ctx.Width = p.Width
+	}
+	return ctx, nil
+}
+
+func parsePresetFrameRate(s string) (r avutil.Rational, err error) {
+	// Split
+	ps := strings.SplitN(s, "/", 2)
+	den := 1
+	if len(ps) == 2 {
+		if den, err = strconv.Atoi(ps[1]); err != nil {
+			err = fmt.Errorf("astilibav: atoi of %s failed: %w", ps[1], err)
+			return
+		}
+	}
+
+	// Parse
+	var num int
+	if num, err = strconv.Atoi(ps[0]); err != nil {
+		err = fmt.Errorf("astilibav: atoi of %s failed: %w", ps[0], err)
+		return
+	}
+	if num <= 0 || den <= 0 {
+		err = fmt.Errorf("astilibav: invalid frame rate %s", s)
+		return
+	}
+	r = avutil.NewRational(num, den)
+	return
+}
+
+// Presets represents a preset registry
+type Presets struct {
+	m  *sync.Mutex
+	ps map[string]Preset
+}
+
+// NewPresets creates a new preset registry
+func NewPresets() *Presets {
+	return &Presets{
+		m:  &sync.Mutex{},
+		ps: make(map[string]Preset),
+	}
+}
+
+// DefaultPresets is the registry used by the encoder node type. It contains the built-in presets and applications can
+// register their own presets in it
+var DefaultPresets = newDefaultPresets()
+
+func newDefaultPresets() *Presets {
+	ps := NewPresets()
+	ps.Register("aac-mono-64k", Preset{BitRate: 64000, ChannelLayout: "mono", CodecName: "aac", SampleFmt: "fltp", SampleRate: 48000})
+	ps.Register("aac-stereo-128k", Preset{BitRate: 128000, ChannelLayout: "stereo", CodecName: "aac", SampleFmt: "fltp", SampleRate: 48000})
+	ps.Register("h264-480p30-1M", Preset{BitRate: 1e6, CodecName: "libx264", Dict: "preset=veryfast", FrameRate: "30", GopSize: 60, Height: 480, PixelFormat: "yuv420p", Width: 854})
+	ps.Register("h264-720p30-3M", Preset{BitRate: 3e6, CodecName: "libx264", Dict: "preset=veryfast", FrameRate: "30", GopSize: 60, Height: 720, PixelFormat: "yuv420p", Width: 1280})
+	ps.Register("h264-1080p30-5M", Preset{BitRate: 5e6, CodecName: "libx264", Dict: "preset=veryfast", FrameRate: "30", GopSize: 60, Height: 1080, PixelFormat: "yuv420p", Width: 1920})
+	return ps
+}
+
+// Register registers a preset, replacing the one with the same name if any
+func (ps *Presets) Register(name string, p Preset) {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	ps.ps[name] = p
+}
+
+// Get returns the preset registered with the name
+func (ps *Presets) Get(name string) (p Preset, ok bool) {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	p, ok = ps.ps[name]
+	return
+}
+
+// Names returns the sorted names of the registered presets
+func (ps *Presets) Names() (ns []string) {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	for n := range ps.ps {
+		ns = append(ns, n)
+	}
+	sort.Strings(ns)
+	return
+}
+
+// Apply returns the context with the settings of the preset registered with the name
+func (ps *Presets) Apply(name string, ctx Context) (Context, error) {
+	p, ok := ps.Get(name)
+	if !ok {
+		return ctx, fmt.Errorf("astilibav: preset %s doesn't exist", name)
+	}
+	return p.Apply(ctx)
+}
@@ -0,0 +1,55 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresets(t *testing.T) {
+	// Built-in presets
+	ps := DefaultPresets
+	assert.Contains(t, ps.Names(), "aac-stereo-128k")
+	assert.Contains(t, ps.Names(), "h264-1080p30-5M")
+
+	// Video
+	ctx, err := ps.Apply("h264-1080p30-5M", Context{CodecType: avutil.AVMEDIA_TYPE_VIDEO, Dict: NewDefaultDict("tune=zerolatency"), Height: 720, Width: 1280})
+	assert.NoError(t, err)
+	assert.Equal(t, Context{
+		BitRate:     5e6,
+		CodecName:   "libx264",
+		CodecType:   avutil.AVMEDIA_TYPE_VIDEO,
+		Dict:        NewDefaultDict("tune=zerolatency,preset=veryfast"),
+		FrameRate:   avutil.NewRational(30, 1),
+		GopSize:     60,
+		Height:      1080,
+		PixelFormat: avutil.AV_PIX_FMT_YUV420P,
+		Width:       1920,
+	}, ctx)
+
+	// Audio
+	ctx, err = ps.Apply("aac-stereo-128k", Context{CodecType: avutil.AVMEDIA_TYPE_AUDIO, SampleRate: 44100})
+	assert.NoError(t, err)
+	assert.Equal(t, 128000, ctx.BitRate)
+	assert.Equal(t, 2, ctx.Channels)
+	assert.Equal(t, 48000, ctx.SampleRate)
+	assert.NotEqual(t, avcodec.AvSampleFormat(0), ctx.SampleFmt)
+
+	// Custom presets
+	ps = NewPresets()
+	ps.Register("custom", Preset{BitRate: 1, FrameRate: "30000/1001"})
+	assert.Equal(t, []string{"custom"}, ps.Names())
+	ctx, err = ps.Apply("custom", Context{BitRate: 2, GopSize: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, Context{BitRate: 1, FrameRate: avutil.NewRational(30000, 1001), GopSize: 3}, ctx)
+
+	// Errors
+	_, err = ps.Apply("unknown", Context{})
+	assert.EqualError(t, err, "astilibav: preset unknown doesn't exist")
+	_, err = Preset{PixelFormat: "unknown"}.Apply(Context{})
+	assert.EqualError(t, err, "astilibav: unknown pixel format unknown")
+	_, err = Preset{FrameRate: "0"}.Apply(Context{})
+	assert.EqualError(t, err, "astilibav: parsing frame rate failed: astilibav: invalid frame rate 0")
+}
@@ -0,0 +1,232 @@
+package astilibav
+
+import "fmt"
+
+// QR codes are generated in version 3 with the L error correction level and the byte mode, which holds up to 53
+// bytes in a 29x29 matrix with a single error correction block
+const (
+	qrDataCodewords = 55
+	qrECCodewords   = 15
+	qrMaxBytes      = 53
+	qrSize          = 29
+)
+
+type qrCode struct {
+	function [qrSize][qrSize]bool
+	modules  [qrSize][qrSize]bool
+}
+
+// newQRCode encodes data in a QR code whose modules are indexed by row then column, true being dark
+func newQRCode(data []byte) (q *qrCode, err error) {
+	// Data is too long
+	if len(data) > qrMaxBytes {
+		err = fmt.Errorf("astilibav: data length %d is > %d", len(data), qrMaxBytes)
+		return
+	}
+
+	// Create code
+	q = &qrCode{}
+	q.drawFunctionPatterns()
+
+	// Get codewords
+	cs := qrDataCodewordsFor(data)
+	cs = append(cs, qrReedSolomonRemainder(cs, qrReedSolomonGenerator(qrECCodewords))...)
+
+	// Draw codewords and apply mask 0
+	q.drawCodewords(cs)
+	for y := 0; y < qrSize; y++ {
+		for x := 0; x < qrSize; x++ {
+			if !q.function[y][x] && (x+y)%2 == 0 {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+	q.drawFormatBits(qrFormatBits(1, 0))
+	return
+}
+
+func (q *qrCode) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *qrCode) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < qrSize; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns and separators
+	for _, c := range [][2]int{{3, 3}, {qrSize - 4, 3}, {3, qrSize - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || x >= qrSize || y < 0 || y >= qrSize {
+					continue
+				}
+				d := qrMaxAbs(dx, dy)
+				q.set(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+
+	// Alignment pattern
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.set(22+dx, 22+dy, qrMaxAbs(dx, dy) != 1)
+		}
+	}
+
+	// Reserve format areas
+	q.drawFormatBits(0)
+}
+
+func qrMaxAbs(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// qrFormatBits returns the 15 format bits for the error correction level (L being 1) and the mask
+func qrFormatBits(level, mask int) int {
+	d := level<<3 | mask
+	r := d
+	for i := 0; i < 10; i++ {
+		r = r<<1 ^ (r>>9)*0x537
+	}
+	return (d<<10 | r) ^ 0x5412
+}
+
+func (q *qrCode) drawFormatBits(bits int) {
+	bit := func(i int) bool { return bits>>uint(i)&1 != 0 }
+
+	// First copy
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+
+	// Second copy
+	for i := 0; i < 8; i++ {
+		q.set(qrSize-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, qrSize-15+i, bit(i))
+	}
+	q.set(8, qrSize-8, true)
+}
+
+func (q *qrCode) drawCodewords(cs []byte) {
+	i := 0
+	for right := qrSize - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qrSize; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qrSize - 1 - vert
+				}
+				if !q.function[y][x] && i < len(cs)*8 {
+					q.modules[y][x] = cs[i>>3]>>uint(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// qrDataCodewordsFor returns the padded data codewords in byte mode
+func qrDataCodewordsFor(data []byte) []byte {
+	// Mode, count and data
+	var bs []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bs = append(bs, v>>uint(i)&1 != 0)
+		}
+	}
+	appendBits(4, 4)
+	appendBits(len(data), 8)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	// Terminator and byte alignment
+	for i := 0; i < 4 && len(bs) < qrDataCodewords*8; i++ {
+		bs = append(bs, false)
+	}
+	for len(bs)%8 != 0 {
+		bs = append(bs, false)
+	}
+
+	// Bytes
+	cs := make([]byte, 0, qrDataCodewords)
+	for i := 0; i < len(bs); i += 8 {
+		var c byte
+		for j := 0; j < 8; j++ {
+			if bs[i+j] {
+				c |= 1 << uint(7-j)
+			}
+		}
+		cs = append(cs, c)
+	}
+
+	// Padding
+	for p := byte(0xec); len(cs) < qrDataCodewords; p ^= 0xec ^ 0x11 {
+		cs = append(cs, p)
+	}
+	return cs
+}
+
+func qrReedSolomonMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func qrReedSolomonGenerator(degree int) []byte {
+	r := make([]byte, degree)
+	r[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range r {
+			r[j] = qrReedSolomonMultiply(r[j], root)
+			if j+1 < len(r) {
+				r[j] ^= r[j+1]
+			}
+		}
+		root = qrReedSolomonMultiply(root, 2)
+	}
+	return r
+}
+
+func qrReedSolomonRemainder(data, generator []byte) []byte {
+	r := make([]byte, len(generator))
+	for _, b := range data {
+		f := b ^ r[0]
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+		for i := range r {
+			r[i] ^= qrReedSolomonMultiply(generator[i], f)
+		}
+	}
+	return r
+}
@@ -0,0 +1,29 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQRCode(t *testing.T) {
+	// Error correction codewords of "HELLO WORLD" in version 1-M
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, qrReedSolomonRemainder([]byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}, qrReedSolomonGenerator(10)))
+
+	// Format bits of level L and mask 0
+	assert.Equal(t, 0x77c4, qrFormatBits(1, 0))
+
+	// Data codewords
+	cs := qrDataCodewordsFor([]byte("a"))
+	assert.Len(t, cs, qrDataCodewords)
+	assert.Equal(t, []byte{0x40, 0x16, 0x10, 0xec, 0x11}, cs[:5])
+
+	// Code
+	q, err := newQRCode([]byte("f=1;t=1600000000000"))
+	assert.NoError(t, err)
+	assert.True(t, q.modules[0][0])
+	assert.False(t, q.modules[7][7])
+	assert.True(t, q.modules[qrSize-8][8])
+	_, err = newQRCode(make([]byte, qrMaxBytes+1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec libavutil
+#include <libavcodec/avcodec.h>
+#include <libavutil/hwcontext.h>
+
+// Mirrors AVQSVFramesContext so that libmfx headers are not required
+typedef struct {
+	void *surfaces;
+	int nb_surfaces;
+	int frame_type;
+} astilibavQSVFramesContext;
+
+// Same value as MFX_MEMTYPE_VIDEO_MEMORY_DECODER_TARGET
+#define ASTILIBAV_QSV_DECODER_TARGET 0x0010
+
+static void astilibavSetQSVDecoderFrameType(AVHWFramesContext *f) {
+	((astilibavQSVFramesContext *)f->hwctx)->frame_type = ASTILIBAV_QSV_DECODER_TARGET;
+}
+
+static void astilibavCodecParametersVideo(AVCodecParameters *p, int *width, int *height, int *format) {
+	*width = p->width;
+	*height = p->height;
+	*format = p->format;
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+const qsvPoolSizeDefault = 64
+
+var (
+	qsvNV12        = avutil.PixelFormat(C.AV_PIX_FMT_NV12)
+	qsvP010        = avutil.PixelFormat(C.AV_PIX_FMT_P010LE)
+	qsvPixelFormat = avutil.PixelFormat(C.AV_PIX_FMT_QSV)
+	qsvYUV420P10   = avutil.PixelFormat(C.AV_PIX_FMT_YUV420P10LE)
+)
+
+// NewQSVDevice opens an Intel Quick Sync Video device. If device is empty, the default device is used, otherwise it
+// is the path of the render node (e.g. "/dev/dri/renderD128")
+func NewQSVDevice(device string, c *astikit.Closer) (*HardwareDeviceContext, error) {
+	return NewHardwareDeviceContext("qsv", device, c)
+}
+
+// QSVDecoderOptions represents QSV decoder options
+type QSVDecoderOptions struct {
+	// If CodecName is empty, the QSV decoder matching the codec params is used
+	// OutputCtx.HardwareFramesCtx and OutputCtx.PixelFormat are set by the decoder
+	Decoder DecoderOptions
+	Device  *HardwareDeviceContext
+	// Number of frames in the hardware pool shared with the next nodes. Default is 64
+	PoolSize int
+}
+
+// qsvDecoderNames indexes QSV decoder names by codec names
+var qsvDecoderNames = map[string]string{
+	"av1":        "av1_qsv",
+	"h264":       "h264_qsv",
+	"hevc":       "hevc_qsv",
+	"mjpeg":      "mjpeg_qsv",
+	"mpeg2video": "mpeg2_qsv",
+	"vc1":        "vc1_qsv",
+	"vp8":        "vp8_qsv",
+	"vp9":        "vp9_qsv",
+}
+
+// NewQSVDecoder creates a new decoder outputting frames in QSV memory. Connecting it to QSV filterers and encoders
+// keeps frames on the GPU
+func NewQSVDecoder(o QSVDecoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (d *Decoder, err error) {
+	// Get codec name
+	if o.Decoder.CodecName == "" {
+		o.Decoder.CodecName = qsvDecoderNames[avcodec.AvcodecGetName(o.Decoder.CodecParams.CodecId())]
+		if o.Decoder.CodecName == "" {
+			err = fmt.Errorf("astilibav: codec id %+v is not supported by qsv", o.Decoder.CodecParams.CodecId())
+			return
+		}
+	}
+
+	// Get codec params
+	var w, h, f C.int
+	C.astilibavCodecParametersVideo((*C.AVCodecParameters)(unsafe.Pointer(o.Decoder.CodecParams)), &w, &h, &f)
+
+	// Get software pixel format
+	swPixFmt := qsvNV12
+	if avutil.PixelFormat(f) == qsvYUV420P10 {
+		swPixFmt = qsvP010
+	}
+
+	// Default pool size
+	if o.PoolSize <= 0 {
+		o.PoolSize = qsvPoolSizeDefault
+	}
+
+	// Create hardware frames
+	// Size is aligned the same way the QSV decoder aligns its surfaces
+	if o.Decoder.OutputCtx.HardwareFramesCtx, err = newHardwareFramesContext(o.Device, HardwareFramesContextOptions{
+		Height:              qsvAlign(int(h)),
+		InitialPoolSize:     o.PoolSize,
+		PixelFormat:         qsvPixelFormat,
+		SoftwarePixelFormat: swPixFmt,
+		Width:               qsvAlign(int(w)),
+	}, func(f *C.AVHWFramesContext) { C.astilibavSetQSVDecoderFrameType(f) }, c); err != nil {
+		err = fmt.Errorf("astilibav: creating hardware frames failed: %w", err)
+		return
+	}
+	o.Decoder.OutputCtx.PixelFormat = qsvPixelFormat
+
+	// Create decoder
+	if d, err = NewDecoder(o.Decoder, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating decoder failed: %w", err)
+		return
+	}
+	return
+}
+
+func qsvAlign(v int) int {
+	return (v + 31) &^ 31
+}
+
+// QSVScaler represents an object capable of scaling QSV frames with VPP without them leaving the GPU
+type QSVScaler struct {
+	*Filterer
+}
+
+// QSVScalerOptions represents QSV scaler options
+type QSVScalerOptions struct {
+	// Pixel format of the output frames in QSV memory (e.g. "nv12"). Default is the input's
+	Format string
+	Height int
+	// Input's output ctx must have hardware frames in QSV memory
+	Input     astiencoder.Node
+	Node      astiencoder.NodeOptions
+	Restamper FrameRestamper
+	Width     int
+}
+
+// NewQSVScaler creates a new QSV scaler
+func NewQSVScaler(o QSVScalerOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (s *QSVScaler, err error) {
+	// Get input ctx
+	v, ok := o.Input.(OutputContexter)
+	if !ok {
+		err = errors.New("astilibav: input is not an OutputContexter")
+		return
+	}
+	ctx := v.OutputCtx()
+
+	// Input is not in QSV memory
+	if ctx.HardwareFramesCtx == nil || ctx.HardwareFramesCtx.PixelFormat() != qsvPixelFormat {
+		err = errors.New("astilibav: input frames are not in qsv memory")
+		return
+	}
+
+	// Invalid size
+	if o.Width <= 0 || o.Height <= 0 {
+		err = fmt.Errorf("astilibav: invalid size %dx%d", o.Width, o.Height)
+		return
+	}
+
+	// Update output ctx
+	// The hardware frames are set by the filterer based on the output of the graph
+	ctx.HardwareFramesCtx = nil
+	ctx.Height = o.Height
+	ctx.Width = o.Width
+
+	// Create filterer
+	s = &QSVScaler{}
+	if s.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   qsvScalerContent(o),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: ctx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func qsvScalerContent(o QSVScalerOptions) string {
+	c := fmt.Sprintf("vpp_qsv=w=%d:h=%d", o.Width, o.Height)
+	if o.Format != "" {
+		c += ":format=" + escapeFilterOption(o.Format)
+	}
+	return c
+}
+
+// QSVEncoderOptions represents QSV encoder options
+type QSVEncoderOptions struct {
+	// Ctx.CodecName must be the name of a QSV encoder (e.g. "h264_qsv")
+	// Ctx.HardwareFramesCtx and Ctx.PixelFormat are set by the encoder
+	Encoder EncoderOptions
+	// Input's output ctx must have hardware frames in QSV memory
+	Input OutputContexter
+}
+
+// NewQSVEncoder creates a new encoder encoding frames in QSV memory
+func NewQSVEncoder(o QSVEncoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (e *Encoder, err error) {
+	// Invalid codec name
+	if !strings.HasSuffix(o.Encoder.Ctx.CodecName, "_qsv") {
+		err = fmt.Errorf("astilibav: %s is not a qsv encoder", o.Encoder.Ctx.CodecName)
+		return
+	}
+
+	// Input is not in QSV memory
+	h := o.Input.OutputCtx().HardwareFramesCtx
+	if h == nil || h.PixelFormat() != qsvPixelFormat {
+		err = errors.New("astilibav: input frames are not in qsv memory")
+		return
+	}
+
+	// Update ctx
+	o.Encoder.Ctx.HardwareFramesCtx = h
+	o.Encoder.Ctx.PixelFormat = qsvPixelFormat
+
+	// Create encoder
+	if e, err = NewEncoder(o.Encoder, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating encoder failed: %w", err)
+		return
+	}
+	return
+}
@@ -0,0 +1,17 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQSVScalerContent(t *testing.T) {
+	assert.Equal(t, "vpp_qsv=w=1280:h=720", qsvScalerContent(QSVScalerOptions{Height: 720, Width: 1280}))
+	assert.Equal(t, "vpp_qsv=w=640:h=360:format=nv12", qsvScalerContent(QSVScalerOptions{Format: "nv12", Height: 360, Width: 640}))
+}
+
+func TestQSVAlign(t *testing.T) {
+	assert.Equal(t, 1920, qsvAlign(1920))
+	assert.Equal(t, 1088, qsvAlign(1080))
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/asticode/go-astiencoder"
 	"github.com/asticode/go-astikit"
@@ -19,8 +20,9 @@ var countRateEnforcer uint64
 type RateEnforcer struct {
 	*astiencoder.BaseNode
 	buf              []*rateEnforcerItem
-	c                *astikit.Chan
+	c                *astiencoder.Queue
 	d                *frameDispatcher
+	drops            *frameDrops
 	eh               *astiencoder.EventHandler
 	m                *sync.Mutex
 	n                astiencoder.Node
@@ -55,6 +57,7 @@ type rateEnforcerItem struct {
 type RateEnforcerOptions struct {
 	// This is expressed in number of frames in the desired FrameRate
 	Delay     uint
+	Drops     FrameDropsOptions
 	FrameRate avutil.Rational
 	Node      astiencoder.NodeOptions
 	OutputCtx Context
@@ -69,14 +72,11 @@ func NewRateEnforcer(o RateEnforcerOptions, eh *astiencoder.EventHandler, c *ast
 
 	// Create rate enforcer
 	r = &RateEnforcer{
-		c: astikit.NewChan(astikit.ChanOptions{
-			AddStrategy: astikit.ChanAddStrategyBlockWhenStarted,
-			ProcessAll:  true,
-		}),
+		c:                astiencoder.NewQueue(o.Node.Queue),
 		eh:               eh,
 		m:                &sync.Mutex{},
 		outputCtx:        o.OutputCtx,
-		p:                newFramePool(c),
+		p:                sharedFramePool,
 		period:           time.Duration(float64(1e9) / o.FrameRate.ToDouble()),
 		restamper:        o.Restamper,
 		slots:            []*rateEnforcerSlot{nil},
@@ -88,7 +88,8 @@ func NewRateEnforcer(o RateEnforcerOptions, eh *astiencoder.EventHandler, c *ast
 		timeBase:         avutil.NewRational(o.FrameRate.Den(), o.FrameRate.Num()),
 	}
 	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
-	r.d = newFrameDispatcher(r, eh, c)
+	r.d = newFrameDispatcher(r, eh)
+	r.drops = newFrameDrops(r, o.Drops, eh, []string{FrameDropReasonLate, FrameDropReasonSameSlot, FrameDropReasonUnusedSource}, []string{FrameDuplicateReasonMissing})
 	r.addStats()
 	return
 }
@@ -122,6 +123,9 @@ func (r *RateEnforcer) addStats() {
 		Unit:        "%",
 	}, r.statWorkRatio)
 
+	// Add frame drops stats
+	r.drops.addStats(r.Stater())
+
 	// Add dispatcher stats
 	r.d.addStats(r.Stater())
 
@@ -182,12 +186,19 @@ func (r *RateEnforcer) Start(ctx context.Context, t astiencoder.CreateTaskFunc)
 
 // HandleFrame implements the FrameHandler interface
 func (r *RateEnforcer) HandleFrame(p *FrameHandlerPayload) {
-	r.c.Add(func() {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, r); p == nil {
+		return
+	}
+	r.c.AddWithRelease(func() {
 		// Handle pause
 		defer r.HandlePause()
 
 		// Increment incoming rate
 		r.statIncomingRate.Add(1)
+		r.drops.incoming(time.Now())
 
 		// Lock
 		r.m.Lock()
@@ -215,10 +226,17 @@ func (r *RateEnforcer) HandleFrame(p *FrameHandlerPayload) {
 
 		// Copy frame
 		if ret := avutil.AvFrameRef(i.f, p.Frame); ret < 0 {
+			r.p.put(i.f)
 			emitAvError(r, r.eh, ret, "avutil.AvFrameRef failed")
 			return
 		}
 
+		// Register frame with the memory budget
+		if !trackFrame(i.f, nodeMemoryAccountant(r)) {
+			r.p.put(i.f)
+			return
+		}
+
 		// Append item
 		r.buf = append(r.buf, i)
 
@@ -234,7 +252,7 @@ func (r *RateEnforcer) HandleFrame(p *FrameHandlerPayload) {
 		if s != nil {
 			r.statDelayAvg.Add(float64(time.Duration(avutil.AvRescaleQ(s.ptsMax-i.f.Pts(), i.d.TimeBase(), nanosecondRational)).Milliseconds()))
 		}
-	})
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
 }
 
 func (r *RateEnforcer) newRateEnforcerSlot(p *FrameHandlerPayload) *rateEnforcerSlot {
@@ -348,6 +366,9 @@ func (r *RateEnforcer) tickFunc(ctx context.Context, nextAt *time.Time, previous
 	// Frame has been repeated
 	if previous {
 		r.statRepeatedRate.Add(1)
+		if i != nil {
+			r.drops.duplicate(FrameDuplicateReasonMissing, 1)
+		}
 	} else {
 		r.p.put(i.f)
 	}
@@ -384,6 +405,7 @@ func (r *RateEnforcer) distribute() {
 			if r.buf[idx].n != s.n {
 				// Node is useless
 				if _, ok := ns[r.buf[idx].n]; !ok {
+					r.drops.drop(FrameDropReasonUnusedSource)
 					r.p.put(r.buf[idx].f)
 					r.buf = append(r.buf[:idx], r.buf[idx+1:]...)
 					idx--
@@ -396,12 +418,14 @@ func (r *RateEnforcer) distribute() {
 				if s.i == nil {
 					s.i = r.buf[idx]
 				} else {
+					r.drops.drop(FrameDropReasonSameSlot)
 					r.p.put(r.buf[idx].f)
 				}
 				r.buf = append(r.buf[:idx], r.buf[idx+1:]...)
 				idx--
 				continue
 			} else if s.ptsMin > r.buf[idx].f.Pts() {
+				r.drops.drop(FrameDropReasonLate)
 				r.p.put(r.buf[idx].f)
 				r.buf = append(r.buf[:idx], r.buf[idx+1:]...)
 				idx--
@@ -441,6 +465,7 @@ func (r *RateEnforcer) current() (i *rateEnforcerItem, previous bool) {
 				n: i.n,
 			}
 		} else {
+			sharedMemoryTracker.untrack(unsafe.Pointer(r.previousItem.f))
 			avutil.AvFrameUnref(r.previousItem.f)
 		}
 
@@ -449,6 +474,9 @@ func (r *RateEnforcer) current() (i *rateEnforcerItem, previous bool) {
 			emitAvError(r, r.eh, ret, "avutil.AvFrameRef failed")
 			r.p.put(r.previousItem.f)
 			r.previousItem = nil
+		} else if !trackFrame(r.previousItem.f, nodeMemoryAccountant(r)) {
+			r.p.put(r.previousItem.f)
+			r.previousItem = nil
 		}
 	} else {
 		i = r.previousItem
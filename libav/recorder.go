@@ -0,0 +1,427 @@
+package astilibav
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avformat"
+)
+
+var countRecorder uint64
+
+const recorderRetryPeriodDefault = time.Second
+
+// Recorder represents an object capable of writing packets to an output only when asked to, either manually, on
+// events or based on a schedule, while its parents keep running
+type Recorder struct {
+	*astiencoder.BaseNode
+	c                  *astiencoder.Queue
+	ctxAvIO            *avformat.AvIOContext
+	ctxFormat          *avformat.Context
+	ctxFormatTemplate  *avformat.Context
+	eh                 *astiencoder.EventHandler
+	f                  *MuxerFile
+	m                  *sync.Mutex
+	manual             bool
+	o                  RecorderOptions
+	sequence           int
+	startFailedAt      time.Time
+	statIncomingRate   *astikit.CounterRateStat
+	statWorkRatio      *astikit.DurationPercentageStat
+	suspended          bool
+	t                  *template.Template
+	waitingForKeyFrame bool
+}
+
+// RecorderOptions represents recorder options
+type RecorderOptions struct {
+	Format     *avformat.OutputFormat
+	FormatName string
+	// If true, recordings start at the first video key frame
+	KeyFrame  bool
+	Node      astiencoder.NodeOptions
+	Restamper PktRestamper
+	// Min duration between 2 attempts to start a recording once one has failed. Default is 1s
+	RetryPeriod time.Duration
+	// Recording happens whenever the current time is in one of the windows
+	Schedule []RecorderWindow
+	// If set, recording starts when an event with this name is emitted
+	StartEventName string
+	// If set, recording stops when an event with this name is emitted
+	StopEventName string
+	// URL is parsed as a template where {{.sequence}} is the recording sequence number starting at 1 and {{.time}} is
+	// the time.Time at which the recording has started
+	URL string
+}
+
+// RecorderWindow represents a recorder window
+type RecorderWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+func (w RecorderWindow) contains(t time.Time) bool {
+	return !t.Before(w.From) && t.Before(w.To)
+}
+
+// NewRecorder creates a new recorder
+func NewRecorder(o RecorderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (r *Recorder, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countRecorder, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("recorder_%d", count), fmt.Sprintf("Recorder #%d", count), fmt.Sprintf("Records to %s", o.URL), "recorder")
+
+	// Default options
+	if o.RetryPeriod <= 0 {
+		o.RetryPeriod = recorderRetryPeriodDefault
+	}
+
+	// Create recorder
+	r = &Recorder{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		eh:               eh,
+		m:                &sync.Mutex{},
+		o:                o,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
+	r.addStats()
+
+	// Parse url
+	if r.t, err = template.New("").Parse(o.URL); err != nil {
+		err = fmt.Errorf("astilibav: parsing url %s as template failed: %w", o.URL, err)
+		return
+	}
+
+	// Alloc template format context
+	// Streams are added to this context and cloned every time a recording starts
+	// We need to create an intermediate variable to avoid "cgo argument has Go pointer to Go pointer" errors
+	var ctxFormat *avformat.Context
+	if ret := avformat.AvformatAllocOutputContext2(&ctxFormat, o.Format, o.FormatName, o.URL); ret < 0 {
+		err = fmt.Errorf("astilibav: avformat.AvformatAllocOutputContext2 on %+v failed: %w", o, NewAvError(ret))
+		return
+	}
+	r.ctxFormatTemplate = ctxFormat
+
+	// Make sure the template format ctx is properly closed
+	c.Add(func() error {
+		r.ctxFormatTemplate.AvformatFreeContext()
+		return nil
+	})
+
+	// Handle events
+	if o.StartEventName != "" {
+		eh.AddForEventName(o.StartEventName, func(astiencoder.Event) bool {
+			r.StartRecording()
+			return false
+		})
+	}
+	if o.StopEventName != "" {
+		eh.AddForEventName(o.StopEventName, func(astiencoder.Event) bool {
+			r.StopRecording()
+			return false
+		})
+	}
+	return
+}
+
+func (r *Recorder) addStats() {
+	// Add incoming rate
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, r.statIncomingRate)
+
+	// Add work ratio
+	r.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, r.statWorkRatio)
+
+	// Add chan stats
+	r.c.AddStats(r.Stater())
+}
+
+// CtxFormat returns the format ctx streams must be added to
+func (r *Recorder) CtxFormat() *avformat.Context {
+	return r.ctxFormatTemplate
+}
+
+// StartRecording starts recording manually
+// Recording will actually start when the next packet comes in
+func (r *Recorder) StartRecording() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.manual = true
+}
+
+// StopRecording stops a manual recording
+// If the current time is in a scheduled window, recording goes on until the end of the window
+func (r *Recorder) StopRecording() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.manual = false
+}
+
+// Recording returns whether the recorder is currently writing to an output
+func (r *Recorder) Recording() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.f != nil
+}
+
+// suspend prevents the recorder from recording whether it has been asked to or not, until it is unsuspended
+func (r *Recorder) suspend(suspended bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.suspended = suspended
+}
+
+func (r *Recorder) shouldRecord(t time.Time) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.suspended {
+		return false
+	}
+	if r.manual {
+		return true
+	}
+	for _, w := range r.o.Schedule {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start starts the recorder
+func (r *Recorder) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure the recording is stopped once everything is done
+		defer func() {
+			if err := r.stopRecording(); err != nil {
+				r.eh.Emit(astiencoder.EventError(r, fmt.Errorf("astilibav: stopping recording failed: %w", err)))
+			}
+		}()
+
+		// Make sure to stop the chan properly
+		defer r.c.Stop()
+
+		// Start chan
+		r.c.Start(r.Context())
+	})
+}
+
+var errRecorderRetryPeriod = errors.New("astilibav: retry period has not elapsed")
+
+func (r *Recorder) startRecording() (err error) {
+	// Retry period has not elapsed
+	if time.Since(r.startFailedAt) < r.o.RetryPeriod {
+		err = errRecorderRetryPeriod
+		return
+	}
+
+	// Make sure the next attempt waits for the retry period if this one fails
+	defer func() {
+		if err != nil {
+			r.startFailedAt = time.Now()
+		}
+	}()
+
+	// Create file
+	// The sequence is only incremented once the output has been opened so that failed attempts don't create gaps
+	f := &MuxerFile{
+		Sequence:  r.sequence + 1,
+		StartedAt: time.Now(),
+	}
+
+	// Execute template
+	buf := &bytes.Buffer{}
+	if err = r.t.Execute(buf, map[string]interface{}{
+		"sequence": f.Sequence,
+		"time":     f.StartedAt,
+	}); err != nil {
+		err = fmt.Errorf("astilibav: executing template failed: %w", err)
+		return
+	}
+	f.URL = buf.String()
+
+	// Open
+	if r.ctxFormat, r.ctxAvIO, err = openMuxerOutput(r.o.Format, r.o.FormatName, f.URL); err != nil {
+		err = fmt.Errorf("astilibav: opening %s failed: %w", f.URL, err)
+		return
+	}
+
+	// Clone streams
+	if err = cloneMuxerStreams(r.ctxFormatTemplate, r.ctxFormat); err != nil {
+		r.closeOutput()
+		err = fmt.Errorf("astilibav: cloning streams failed: %w", err)
+		return
+	}
+
+	// Write header
+	if ret := r.ctxFormat.AvformatWriteHeader(nil); ret < 0 {
+		r.closeOutput()
+		err = fmt.Errorf("astilibav: r.ctxFormat.AvformatWriteHeader on %s failed: %w", f.URL, NewAvError(ret))
+		return
+	}
+
+	// Update sequence and file
+	r.sequence = f.Sequence
+	r.m.Lock()
+	r.f = f
+	r.m.Unlock()
+
+	// Wait for key frame
+	r.waitingForKeyFrame = r.o.KeyFrame
+
+	// Emit event
+	r.eh.Emit(astiencoder.Event{
+		Name:    RecorderRecordingStarted,
+		Payload: *f,
+		Target:  r,
+	})
+	return
+}
+
+func (r *Recorder) stopRecording() (err error) {
+	// Not recording
+	r.m.Lock()
+	f := r.f
+	r.f = nil
+	r.m.Unlock()
+	if f == nil {
+		return
+	}
+
+	// Make sure the output is closed
+	defer r.closeOutput()
+
+	// Write trailer
+	if ret := r.ctxFormat.AvWriteTrailer(); ret < 0 {
+		err = fmt.Errorf("astilibav: r.ctxFormat.AvWriteTrailer on %s failed: %w", f.URL, NewAvError(ret))
+		return
+	}
+
+	// Emit event
+	f.EndedAt = time.Now()
+	r.eh.Emit(astiencoder.Event{
+		Name:    RecorderRecordingStopped,
+		Payload: *f,
+		Target:  r,
+	})
+	return
+}
+
+func (r *Recorder) closeOutput() {
+	if err := closeMuxerOutput(r.ctxFormat, r.ctxAvIO); err != nil {
+		r.eh.Emit(astiencoder.EventError(r, fmt.Errorf("astilibav: closing output failed: %w", err)))
+	}
+	r.ctxFormat, r.ctxAvIO = nil, nil
+}
+
+func (r *Recorder) hasVideo() bool {
+	for _, s := range r.ctxFormatTemplate.Streams() {
+		if s.CodecParameters().CodecType() == avcodec.AVMEDIA_TYPE_VIDEO {
+			return true
+		}
+	}
+	return false
+}
+
+// RecorderPktHandler is an object that can handle a pkt for the recorder
+type RecorderPktHandler struct {
+	*Recorder
+	idx int
+}
+
+// NewPktHandler creates a new pkt handler for a stream that has been added to the recorder's format ctx
+func (r *Recorder) NewPktHandler(o *avformat.Stream) *RecorderPktHandler {
+	return &RecorderPktHandler{
+		Recorder: r,
+		idx:      o.Index(),
+	}
+}
+
+// HandlePkt implements the PktHandler interface
+func (h *RecorderPktHandler) HandlePkt(p *PktHandlerPayload) {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be written
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, h); p == nil {
+		return
+	}
+	h.c.AddWithRelease(func() {
+		// Handle pause
+		defer h.HandlePause()
+
+		// Increment incoming rate
+		h.statIncomingRate.Add(1)
+
+		// Start or stop recording
+		h.statWorkRatio.Begin()
+		if record := h.shouldRecord(time.Now()); record && h.ctxFormat == nil {
+			if err := h.startRecording(); err != nil {
+				h.statWorkRatio.End()
+				if err == errRecorderRetryPeriod {
+					return
+				}
+				h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: starting recording failed: %w", err)))
+				return
+			}
+		} else if !record && h.ctxFormat != nil {
+			if err := h.stopRecording(); err != nil {
+				h.eh.Emit(astiencoder.EventError(h, fmt.Errorf("astilibav: stopping recording failed: %w", err)))
+			}
+		}
+		h.statWorkRatio.End()
+
+		// Not recording
+		if h.ctxFormat == nil {
+			return
+		}
+
+		// Get stream
+		o := h.ctxFormat.Streams()[h.idx]
+
+		// Wait for key frame
+		if h.waitingForKeyFrame {
+			if (o.CodecParameters().CodecType() != avcodec.AVMEDIA_TYPE_VIDEO && h.hasVideo()) || p.Pkt.Flags()&avcodec.AV_PKT_FLAG_KEY == 0 {
+				return
+			}
+			h.waitingForKeyFrame = false
+		}
+
+		// Rescale timestamps
+		p.Pkt.AvPacketRescaleTs(p.Descriptor.TimeBase(), o.TimeBase())
+
+		// Set stream index
+		p.Pkt.SetStreamIndex(o.Index())
+
+		// Restamp
+		if h.o.Restamper != nil {
+			h.o.Restamper.Restamp(p.Pkt)
+		}
+
+		// Write frame
+		h.statWorkRatio.Begin()
+		if ret := h.ctxFormat.AvInterleavedWriteFrame((*avformat.Packet)(unsafe.Pointer(p.Pkt))); ret < 0 {
+			h.statWorkRatio.End()
+			emitAvError(h, h.eh, ret, "h.ctxFormat.AvInterleavedWriteFrame failed")
+			return
+		}
+		h.statWorkRatio.End()
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
+}
@@ -0,0 +1,710 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec libavutil
+#include <libavcodec/avcodec.h>
+#include <libavutil/error.h>
+#include <libavutil/frame.h>
+#include <libavutil/imgutils.h>
+#include <libavutil/samplefmt.h>
+#include <errno.h>
+#include <string.h>
+
+#define ASTILIBAV_REMOTE_MAX_CHANNELS 64
+
+typedef struct {
+	int64_t dts;
+	int64_t duration;
+	int flags;
+	int64_t pts;
+} astilibavRemotePktHeader;
+
+static void astilibavGetRemotePktHeader(AVPacket *pkt, astilibavRemotePktHeader *h) {
+	h->dts = pkt->dts;
+	h->duration = pkt->duration;
+	h->flags = pkt->flags;
+	h->pts = pkt->pts;
+}
+
+static int astilibavSetRemotePkt(AVPacket *pkt, astilibavRemotePktHeader *h, uint8_t *data, int size) {
+	int ret = av_new_packet(pkt, size);
+	if (ret < 0) return ret;
+	if (size > 0) memcpy(pkt->data, data, size);
+	pkt->dts = h->dts;
+	pkt->duration = h->duration;
+	pkt->flags = h->flags;
+	pkt->pts = h->pts;
+	return 0;
+}
+
+typedef struct {
+	uint64_t channel_layout;
+	int channels;
+	int format;
+	int height;
+	int key_frame;
+	int nb_samples;
+	int pict_type;
+	int64_t pkt_dts;
+	int64_t pkt_duration;
+	int64_t pts;
+	int sample_aspect_ratio_den;
+	int sample_aspect_ratio_num;
+	int sample_rate;
+	int width;
+} astilibavRemoteFrameHeader;
+
+static void astilibavGetRemoteFrameHeader(AVFrame *f, astilibavRemoteFrameHeader *h) {
+	h->channel_layout = f->channel_layout;
+	h->channels = f->channels;
+	h->format = f->format;
+	h->height = f->height;
+	h->key_frame = f->key_frame;
+	h->nb_samples = f->nb_samples;
+	h->pict_type = f->pict_type;
+	h->pkt_dts = f->pkt_dts;
+	h->pkt_duration = f->pkt_duration;
+	h->pts = f->pts;
+	h->sample_aspect_ratio_den = f->sample_aspect_ratio.den;
+	h->sample_aspect_ratio_num = f->sample_aspect_ratio.num;
+	h->sample_rate = f->sample_rate;
+	h->width = f->width;
+}
+
+static int astilibavRemoteFrameDataSize(astilibavRemoteFrameHeader *h) {
+	if (h->width > 0) return av_image_get_buffer_size(h->format, h->width, h->height, 1);
+	if (h->channels > ASTILIBAV_REMOTE_MAX_CHANNELS) return AVERROR(EINVAL);
+	return av_samples_get_buffer_size(NULL, h->channels, h->nb_samples, h->format, 1);
+}
+
+static int astilibavCopyRemoteFrameData(AVFrame *f, uint8_t *data, int size) {
+	if (f->width > 0) return av_image_copy_to_buffer(data, size, (const uint8_t * const *)f->data, f->linesize, f->format, f->width, f->height, 1);
+	uint8_t *dst[ASTILIBAV_REMOTE_MAX_CHANNELS];
+	int linesize;
+	int ret = av_samples_fill_arrays(dst, &linesize, data, f->channels, f->nb_samples, f->format, 1);
+	if (ret < 0) return ret;
+	return av_samples_copy(dst, f->extended_data, 0, 0, f->nb_samples, f->channels, f->format);
+}
+
+static int astilibavSetRemoteFrame(AVFrame *f, astilibavRemoteFrameHeader *h, uint8_t *data, int size) {
+	int expected = astilibavRemoteFrameDataSize(h);
+	if (expected < 0) return expected;
+	if (expected != size) return AVERROR(EINVAL);
+	f->channel_layout = h->channel_layout;
+	f->channels = h->channels;
+	f->format = h->format;
+	f->height = h->height;
+	f->key_frame = h->key_frame;
+	f->nb_samples = h->nb_samples;
+	f->pict_type = h->pict_type;
+	f->pkt_dts = h->pkt_dts;
+	f->pkt_duration = h->pkt_duration;
+	f->pts = h->pts;
+	f->sample_aspect_ratio.den = h->sample_aspect_ratio_den;
+	f->sample_aspect_ratio.num = h->sample_aspect_ratio_num;
+	f->sample_rate = h->sample_rate;
+	f->width = h->width;
+	int ret = av_frame_get_buffer(f, 0);
+	if (ret < 0) return ret;
+	if (f->width > 0) {
+		uint8_t *src[4];
+		int linesize[4];
+		ret = av_image_fill_arrays(src, linesize, data, f->format, f->width, f->height, 1);
+		if (ret < 0) return ret;
+		av_image_copy(f->data, f->linesize, (const uint8_t **)src, linesize, f->format, f->width, f->height);
+		return 0;
+	}
+	uint8_t *src[ASTILIBAV_REMOTE_MAX_CHANNELS];
+	int linesize;
+	ret = av_samples_fill_arrays(src, &linesize, data, f->channels, f->nb_samples, f->format, 1);
+	if (ret < 0) return ret;
+	return av_samples_copy(f->extended_data, src, 0, 0, f->nb_samples, f->channels, f->format);
+}
+*/
+import "C"
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+// Remote messages are made of their kind (1 byte), the size of their payload (4 bytes, big endian) and their payload
+// A connection starts with a hello message describing the stream, followed by pkt or frame messages whose payload is
+// a fixed size header followed by the data
+const (
+	remoteMessageKindFrame byte = iota + 1
+	remoteMessageKindHello
+	remoteMessageKindPkt
+)
+
+const (
+	remoteDialTimeoutDefault  = time.Second
+	remoteMaxMessageSize      = 1 << 28
+	remoteRetryPeriodDefault  = time.Second
+	remoteWriteTimeoutDefault = 5 * time.Second
+)
+
+var remoteMessageKindNames = map[byte]string{
+	remoteMessageKindFrame: "frame",
+	remoteMessageKindHello: "hello",
+	remoteMessageKindPkt:   "pkt",
+}
+
+var countRemoteSender uint64
+
+type remoteHello struct {
+	ChannelLayout uint64 `json:"channel_layout"`
+	CodecType     int    `json:"codec_type"`
+	Height        int    `json:"height"`
+	Kind          string `json:"kind"`
+	SampleRate    int    `json:"sample_rate"`
+	TimeBase      [2]int `json:"time_base"`
+	Width         int    `json:"width"`
+}
+
+func newRemoteHello(kind byte, ctx Context) remoteHello {
+	return remoteHello{
+		ChannelLayout: ctx.ChannelLayout,
+		CodecType:     int(ctx.CodecType),
+		Height:        ctx.Height,
+		Kind:          remoteMessageKindNames[kind],
+		SampleRate:    ctx.SampleRate,
+		TimeBase:      [2]int{ctx.TimeBase.Num(), ctx.TimeBase.Den()},
+		Width:         ctx.Width,
+	}
+}
+
+// compatible checks whether what the sender sends can be dispatched as is by the receiver. Settings that are not set
+// on the receiver side are not checked
+func (h remoteHello) compatible(r remoteHello) error {
+	if h.Kind != r.Kind {
+		return fmt.Errorf("astilibav: sender sends %ss, receiver expects %ss", h.Kind, r.Kind)
+	}
+	if h.CodecType != r.CodecType {
+		return fmt.Errorf("astilibav: sender's codec type %d doesn't match receiver's %d", h.CodecType, r.CodecType)
+	}
+	if h.TimeBase != r.TimeBase {
+		return fmt.Errorf("astilibav: sender's time base %d/%d doesn't match receiver's %d/%d", h.TimeBase[0], h.TimeBase[1], r.TimeBase[0], r.TimeBase[1])
+	}
+	if r.Kind != remoteMessageKindNames[remoteMessageKindFrame] {
+		return nil
+	}
+	if r.Width > 0 && r.Height > 0 && (h.Width != r.Width || h.Height != r.Height) {
+		return fmt.Errorf("astilibav: sender's size %dx%d doesn't match receiver's %dx%d", h.Width, h.Height, r.Width, r.Height)
+	}
+	if r.SampleRate > 0 && h.SampleRate != r.SampleRate {
+		return fmt.Errorf("astilibav: sender's sample rate %d doesn't match receiver's %d", h.SampleRate, r.SampleRate)
+	}
+	if r.ChannelLayout > 0 && h.ChannelLayout != r.ChannelLayout {
+		return fmt.Errorf("astilibav: sender's channel layout %d doesn't match receiver's %d", h.ChannelLayout, r.ChannelLayout)
+	}
+	return nil
+}
+
+type remotePktHeader struct {
+	DTS      int64
+	Duration int64
+	Flags    int32
+	PTS      int64
+}
+
+var remotePktHeaderSize = binary.Size(remotePktHeader{})
+
+type remoteFrameHeader struct {
+	ChannelLayout        uint64
+	Channels             int32
+	Format               int32
+	Height               int32
+	KeyFrame             int32
+	NbSamples            int32
+	PictType             int32
+	PktDTS               int64
+	PktDuration          int64
+	PTS                  int64
+	SampleAspectRatioDen int32
+	SampleAspectRatioNum int32
+	SampleRate           int32
+	Width                int32
+}
+
+var remoteFrameHeaderSize = binary.Size(remoteFrameHeader{})
+
+func writeRemoteMessage(w io.Writer, kind byte, payload []byte) (err error) {
+	// Write header
+	var h [5]byte
+	h[0] = kind
+	binary.BigEndian.PutUint32(h[1:], uint32(len(payload)))
+	if _, err = w.Write(h[:]); err != nil {
+		err = fmt.Errorf("astilibav: writing header failed: %w", err)
+		return
+	}
+
+	// Write payload
+	if _, err = w.Write(payload); err != nil {
+		err = fmt.Errorf("astilibav: writing payload failed: %w", err)
+		return
+	}
+	return
+}
+
+// readRemoteMessage reads a message whose payload is stored in buf if it's big enough
+func readRemoteMessage(r io.Reader, buf []byte) (kind byte, payload []byte, err error) {
+	// Read header
+	var h [5]byte
+	if _, err = io.ReadFull(r, h[:]); err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("astilibav: reading header failed: %w", err)
+		}
+		return
+	}
+	kind = h[0]
+
+	// Payload is too big
+	size := binary.BigEndian.Uint32(h[1:])
+	if size > remoteMaxMessageSize {
+		err = fmt.Errorf("astilibav: payload size %d exceeds %d", size, remoteMaxMessageSize)
+		return
+	}
+
+	// Read payload
+	if int(size) > cap(buf) {
+		buf = make([]byte, size)
+	}
+	payload = buf[:size]
+	if _, err = io.ReadFull(r, payload); err != nil {
+		err = fmt.Errorf("astilibav: reading payload failed: %w", err)
+		return
+	}
+	return
+}
+
+type remoteDescriptor struct {
+	timeBase avutil.Rational
+}
+
+func (d remoteDescriptor) TimeBase() avutil.Rational {
+	return d.timeBase
+}
+
+// RemoteSender represents an object capable of sending packets or frames to a remote receiver over TCP so that a
+// branch of a graph can run on a different machine, e.g. to decode near the source and encode in the cloud
+// Packets and frames handled while the sender is not connected are dropped, and it reconnects automatically.
+// Frames living in hardware memory are downloaded first, and side data is not sent
+type RemoteSender struct {
+	*astiencoder.BaseNode
+	buf                 []byte
+	c                   *astiencoder.Queue
+	conn                net.Conn
+	dialedAt            time.Time
+	eh                  *astiencoder.EventHandler
+	kind                byte
+	o                   RemoteSenderOptions
+	p                   *framePool
+	statDropped         *astikit.CounterRateStat
+	statIncomingRate    *astikit.CounterRateStat
+	statOutgoingBitRate *astikit.CounterRateStat
+	statWorkRatio       *astikit.DurationPercentageStat
+	w                   *bufio.Writer
+}
+
+// RemoteSenderOptions represents remote sender options
+type RemoteSenderOptions struct {
+	// Address of the remote receiver, e.g. "encoder-1:4001"
+	Addr string
+	// Ctx of the packets or frames, checked by the receiver when connecting
+	Ctx Context
+	// Default is 1s
+	DialTimeout time.Duration
+	Node        astiencoder.NodeOptions
+	// Min duration between 2 connection attempts. Default is 1s
+	RetryPeriod time.Duration
+	// If set, the connection to the remote receiver uses TLS
+	TLS *tls.Config
+	// Default is 5s
+	WriteTimeout time.Duration
+}
+
+// NewRemoteSender creates a new remote sender
+func NewRemoteSender(o RemoteSenderOptions, eh *astiencoder.EventHandler) (s *RemoteSender) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countRemoteSender, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("remote_sender_%d", count), fmt.Sprintf("Remote Sender #%d", count), fmt.Sprintf("Sends to %s", o.Addr), "remote sender")
+
+	// Default options
+	if o.DialTimeout <= 0 {
+		o.DialTimeout = remoteDialTimeoutDefault
+	}
+	if o.RetryPeriod <= 0 {
+		o.RetryPeriod = remoteRetryPeriodDefault
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = remoteWriteTimeoutDefault
+	}
+
+	// Create sender
+	s = &RemoteSender{
+		c:                   astiencoder.NewQueue(o.Node.Queue),
+		eh:                  eh,
+		o:                   o,
+		p:                   sharedFramePool,
+		statDropped:         astikit.NewCounterRateStat(),
+		statIncomingRate:    astikit.NewCounterRateStat(),
+		statOutgoingBitRate: astikit.NewCounterRateStat(),
+		statWorkRatio:       astikit.NewDurationPercentageStat(),
+	}
+	s.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(s), eh)
+	s.addStats()
+	return
+}
+
+func (s *RemoteSender) addStats() {
+	// Add incoming rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets or frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "ps",
+	}, s.statIncomingRate)
+
+	// Add dropped rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets or frames dropped per second",
+		Label:       "Dropped rate",
+		Unit:        "ps",
+	}, s.statDropped)
+
+	// Add outgoing bit rate
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of kilobits going out per second",
+		Label:       "Outgoing bit rate",
+		Unit:        "kbps",
+	}, s.statOutgoingBitRate)
+
+	// Add work ratio
+	s.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, s.statWorkRatio)
+
+	// Add chan stats
+	s.c.AddStats(s.Stater())
+}
+
+// Start starts the sender
+func (s *RemoteSender) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	s.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to close the connection
+		defer s.close()
+
+		// Make sure to stop the chan properly
+		defer s.c.Stop()
+
+		// Start chan
+		s.c.Start(s.Context())
+	})
+}
+
+// HandlePkt implements the PktHandler interface
+func (s *RemoteSender) HandlePkt(p *PktHandlerPayload) {
+	// Copy pkt since the payload is released once handled
+	pkt := sharedPktPool.get()
+	pkt.AvPacketRef(p.Pkt)
+
+	s.c.AddWithRelease(func() {
+		// Handle pause
+		defer s.HandlePause()
+
+		// Increment incoming rate
+		s.statIncomingRate.Add(1)
+
+		// Get header
+		var h C.astilibavRemotePktHeader
+		C.astilibavGetRemotePktHeader((*C.AVPacket)(unsafe.Pointer(pkt)), &h)
+
+		// Marshal
+		s.statWorkRatio.Begin()
+		b := bytes.NewBuffer(s.buf[:0])
+		binary.Write(b, binary.BigEndian, remotePktHeader{
+			DTS:      int64(h.dts),
+			Duration: int64(h.duration),
+			Flags:    int32(h.flags),
+			PTS:      int64(h.pts),
+		})
+		if pkt.Size() > 0 {
+			b.Write(C.GoBytes(unsafe.Pointer(pkt.Data()), C.int(pkt.Size())))
+		}
+		s.buf = b.Bytes()
+		s.statWorkRatio.End()
+
+		// Send
+		s.send(remoteMessageKindPkt, s.buf)
+	}, func() { sharedPktPool.put(pkt) }, pktBufferSize(pkt))
+}
+
+// HandleFrame implements the FrameHandler interface
+func (s *RemoteSender) HandleFrame(p *FrameHandlerPayload) {
+	// Copy frame since the payload is released once handled
+	f := s.p.get()
+	if ret := avutil.AvFrameRef(f, p.Frame); ret < 0 {
+		emitAvError(s, s.eh, ret, "avutil.AvFrameRef failed")
+		s.p.put(f)
+		return
+	}
+
+	s.c.AddWithRelease(func() {
+		// Handle pause
+		defer s.HandlePause()
+
+		// Increment incoming rate
+		s.statIncomingRate.Add(1)
+
+		// Marshal
+		s.statWorkRatio.Begin()
+		err := s.marshalFrame(f)
+		s.statWorkRatio.End()
+		if err != nil {
+			s.eh.Emit(astiencoder.EventError(s, fmt.Errorf("astilibav: marshaling frame failed: %w", err)))
+			s.statDropped.Add(1)
+			return
+		}
+
+		// Send
+		s.send(remoteMessageKindFrame, s.buf)
+	}, func() { s.p.put(f) }, frameBufferSize(f))
+}
+
+func (s *RemoteSender) marshalFrame(f *avutil.Frame) (err error) {
+	// Download hardware frame
+	if isHardwareFrame(f) {
+		sf := s.p.get()
+		defer s.p.put(sf)
+		if _, err = downloadHardwareFrame(sf, f); err != nil {
+			err = fmt.Errorf("astilibav: downloading hardware frame failed: %w", err)
+			return
+		}
+		f = sf
+	}
+
+	// Get header
+	var h C.astilibavRemoteFrameHeader
+	C.astilibavGetRemoteFrameHeader((*C.AVFrame)(unsafe.Pointer(f)), &h)
+
+	// Get data size
+	size := int(C.astilibavRemoteFrameDataSize(&h))
+	if size < 0 {
+		err = fmt.Errorf("astilibav: getting data size failed: %w", NewAvError(size))
+		return
+	}
+
+	// Write header
+	b := bytes.NewBuffer(s.buf[:0])
+	binary.Write(b, binary.BigEndian, remoteFrameHeader{
+		ChannelLayout:        uint64(h.channel_layout),
+		Channels:             int32(h.channels),
+		Format:               int32(h.format),
+		Height:               int32(h.height),
+		KeyFrame:             int32(h.key_frame),
+		NbSamples:            int32(h.nb_samples),
+		PictType:             int32(h.pict_type),
+		PktDTS:               int64(h.pkt_dts),
+		PktDuration:          int64(h.pkt_duration),
+		PTS:                  int64(h.pts),
+		SampleAspectRatioDen: int32(h.sample_aspect_ratio_den),
+		SampleAspectRatioNum: int32(h.sample_aspect_ratio_num),
+		SampleRate:           int32(h.sample_rate),
+		Width:                int32(h.width),
+	})
+	b.Grow(size)
+	s.buf = b.Bytes()[:remoteFrameHeaderSize+size]
+
+	// Copy data
+	if size > 0 {
+		if ret := C.astilibavCopyRemoteFrameData((*C.AVFrame)(unsafe.Pointer(f)), (*C.uint8_t)(unsafe.Pointer(&s.buf[remoteFrameHeaderSize])), C.int(size)); ret < 0 {
+			err = fmt.Errorf("astilibav: copying data failed: %w", NewAvError(int(ret)))
+			return
+		}
+	}
+	return
+}
+
+func (s *RemoteSender) send(kind byte, payload []byte) {
+	// Connect
+	if s.conn == nil || s.kind != kind {
+		if err := s.connect(kind); err != nil {
+			if err != errRemoteRetryPeriod {
+				s.eh.Emit(astiencoder.EventError(s, fmt.Errorf("astilibav: connecting to %s failed: %w", s.o.Addr, err)))
+			}
+			s.statDropped.Add(1)
+			return
+		}
+	}
+
+	// Write
+	if err := s.write(kind, payload); err != nil {
+		s.close()
+		s.eh.Emit(astiencoder.EventError(s, fmt.Errorf("astilibav: sending to %s failed: %w", s.o.Addr, err)))
+		s.statDropped.Add(1)
+		return
+	}
+	s.statOutgoingBitRate.Add(float64(len(payload)*8) / 1000)
+}
+
+var errRemoteRetryPeriod = errors.New("astilibav: retry period has not elapsed")
+
+func (s *RemoteSender) connect(kind byte) (err error) {
+	// Close previous connection
+	s.close()
+
+	// Retry period has not elapsed
+	if time.Since(s.dialedAt) < s.o.RetryPeriod {
+		err = errRemoteRetryPeriod
+		return
+	}
+	s.dialedAt = time.Now()
+
+	// Dial
+	var conn net.Conn
+	if s.o.TLS != nil {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: s.o.DialTimeout}, "tcp", s.o.Addr, s.o.TLS)
+	} else {
+		conn, err = net.DialTimeout("tcp", s.o.Addr, s.o.DialTimeout)
+	}
+	if err != nil {
+		err = fmt.Errorf("astilibav: dialing failed: %w", err)
+		return
+	}
+	s.conn = conn
+	s.kind = kind
+	s.w = bufio.NewWriter(conn)
+
+	// Marshal hello
+	var b []byte
+	if b, err = json.Marshal(newRemoteHello(kind, s.o.Ctx)); err != nil {
+		s.close()
+		err = fmt.Errorf("astilibav: marshaling hello failed: %w", err)
+		return
+	}
+
+	// Write hello
+	if err = s.write(remoteMessageKindHello, b); err != nil {
+		s.close()
+		err = fmt.Errorf("astilibav: writing hello failed: %w", err)
+		return
+	}
+	return
+}
+
+func (s *RemoteSender) write(kind byte, payload []byte) (err error) {
+	// Set deadline
+	if err = s.conn.SetWriteDeadline(time.Now().Add(s.o.WriteTimeout)); err != nil {
+		err = fmt.Errorf("astilibav: setting write deadline failed: %w", err)
+		return
+	}
+
+	// Write
+	if err = writeRemoteMessage(s.w, kind, payload); err != nil {
+		err = fmt.Errorf("astilibav: writing message failed: %w", err)
+		return
+	}
+
+	// Flush
+	if err = s.w.Flush(); err != nil {
+		err = fmt.Errorf("astilibav: flushing failed: %w", err)
+		return
+	}
+	return
+}
+
+func (s *RemoteSender) close() {
+	if s.conn == nil {
+		return
+	}
+	s.conn.Close()
+	s.conn = nil
+	s.w = nil
+}
+
+func newRemotePkt(pkt *avcodec.Packet, payload []byte) error {
+	// Payload is too small
+	if len(payload) < remotePktHeaderSize {
+		return fmt.Errorf("astilibav: payload size %d is smaller than header size %d", len(payload), remotePktHeaderSize)
+	}
+
+	// Read header
+	var h remotePktHeader
+	if err := binary.Read(bytes.NewReader(payload), binary.BigEndian, &h); err != nil {
+		return fmt.Errorf("astilibav: reading header failed: %w", err)
+	}
+	ch := C.astilibavRemotePktHeader{
+		dts:      C.int64_t(h.DTS),
+		duration: C.int64_t(h.Duration),
+		flags:    C.int(h.Flags),
+		pts:      C.int64_t(h.PTS),
+	}
+
+	// Set pkt
+	data := payload[remotePktHeaderSize:]
+	var ptr *C.uint8_t
+	if len(data) > 0 {
+		ptr = (*C.uint8_t)(unsafe.Pointer(&data[0]))
+	}
+	if ret := C.astilibavSetRemotePkt((*C.AVPacket)(unsafe.Pointer(pkt)), &ch, ptr, C.int(len(data))); ret < 0 {
+		return fmt.Errorf("astilibav: setting pkt failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
+
+func newRemoteFrame(f *avutil.Frame, payload []byte) error {
+	// Payload is too small
+	if len(payload) < remoteFrameHeaderSize {
+		return fmt.Errorf("astilibav: payload size %d is smaller than header size %d", len(payload), remoteFrameHeaderSize)
+	}
+
+	// Read header
+	var h remoteFrameHeader
+	if err := binary.Read(bytes.NewReader(payload), binary.BigEndian, &h); err != nil {
+		return fmt.Errorf("astilibav: reading header failed: %w", err)
+	}
+	ch := C.astilibavRemoteFrameHeader{
+		channel_layout:          C.uint64_t(h.ChannelLayout),
+		channels:                C.int(h.Channels),
+		format:                  C.int(h.Format),
+		height:                  C.int(h.Height),
+		key_frame:               C.int(h.KeyFrame),
+		nb_samples:              C.int(h.NbSamples),
+		pict_type:               C.int(h.PictType),
+		pkt_dts:                 C.int64_t(h.PktDTS),
+		pkt_duration:            C.int64_t(h.PktDuration),
+		pts:                     C.int64_t(h.PTS),
+		sample_aspect_ratio_den: C.int(h.SampleAspectRatioDen),
+		sample_aspect_ratio_num: C.int(h.SampleAspectRatioNum),
+		sample_rate:             C.int(h.SampleRate),
+		width:                   C.int(h.Width),
+	}
+
+	// Set frame
+	data := payload[remoteFrameHeaderSize:]
+	var ptr *C.uint8_t
+	if len(data) > 0 {
+		ptr = (*C.uint8_t)(unsafe.Pointer(&data[0]))
+	}
+	if ret := C.astilibavSetRemoteFrame((*C.AVFrame)(unsafe.Pointer(f)), &ch, ptr, C.int(len(data))); ret < 0 {
+		return fmt.Errorf("astilibav: setting frame failed: %w", NewAvError(int(ret)))
+	}
+	return nil
+}
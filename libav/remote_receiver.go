@@ -0,0 +1,433 @@
+package astilibav
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+var (
+	countRemoteFrameReceiver uint64
+	countRemotePktReceiver   uint64
+)
+
+// RemoteReceiverOptions represents remote receiver options
+type RemoteReceiverOptions struct {
+	// Address to listen on, e.g. ":4001"
+	Addr string
+	Node astiencoder.NodeOptions
+	// Ctx of the packets or frames. Senders whose ctx doesn't match it are disconnected
+	OutputCtx Context
+	// If set, senders must connect using TLS
+	TLS *tls.Config
+}
+
+// remoteReceiver listens for the connections of remote senders. Only the last sender that has connected is read
+// from, which allows senders to reconnect after a network failure without waiting for the previous connection to
+// time out
+type remoteReceiver struct {
+	conn                net.Conn
+	eh                  *astiencoder.EventHandler
+	hello               remoteHello
+	kind                byte
+	l                   *net.TCPListener
+	m                   *sync.Mutex // Locks conn
+	mh                  *sync.Mutex // Makes sure messages are handled one at a time
+	n                   astiencoder.Node
+	statIncomingBitRate *astikit.CounterRateStat
+	statIncomingRate    *astikit.CounterRateStat
+	tls                 *tls.Config
+	wg                  *sync.WaitGroup
+}
+
+func newRemoteReceiver(kind byte, o RemoteReceiverOptions, n astiencoder.Node, eh *astiencoder.EventHandler, c *astikit.Closer) (r *remoteReceiver, err error) {
+	// Listen
+	var l net.Listener
+	if l, err = net.Listen("tcp", o.Addr); err != nil {
+		err = fmt.Errorf("astilibav: listening on %s failed: %w", o.Addr, err)
+		return
+	}
+
+	// Make sure the listener is closed
+	c.Add(l.Close)
+
+	// Create receiver
+	r = &remoteReceiver{
+		eh:                  eh,
+		hello:               newRemoteHello(kind, o.OutputCtx),
+		kind:                kind,
+		l:                   l.(*net.TCPListener),
+		m:                   &sync.Mutex{},
+		mh:                  &sync.Mutex{},
+		n:                   n,
+		statIncomingBitRate: astikit.NewCounterRateStat(),
+		statIncomingRate:    astikit.NewCounterRateStat(),
+		tls:                 o.TLS,
+		wg:                  &sync.WaitGroup{},
+	}
+	return
+}
+
+func (r *remoteReceiver) addStats(s *astikit.Stater) {
+	// Add incoming rate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of packets or frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "ps",
+	}, r.statIncomingRate)
+
+	// Add incoming bit rate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of kilobits coming in per second",
+		Label:       "Incoming bit rate",
+		Unit:        "kbps",
+	}, r.statIncomingBitRate)
+}
+
+// serve accepts connections until the context is done
+func (r *remoteReceiver) serve(ctx context.Context, handle func(payload []byte) error) {
+	// Make sure to close the connection and wait for its reader to be done
+	defer r.wg.Wait()
+	defer r.setConn(nil)
+
+	// Make sure accepting is unblocked once the context is done
+	r.l.SetDeadline(time.Time{})
+	go func() {
+		<-ctx.Done()
+		r.l.SetDeadline(time.Now())
+	}()
+
+	// Loop
+	for {
+		// Accept
+		conn, err := r.l.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				r.eh.Emit(astiencoder.EventError(r.n, fmt.Errorf("astilibav: accepting failed: %w", err)))
+			}
+			return
+		}
+
+		// Wrap connection, the handshake is done on the first read
+		if r.tls != nil {
+			conn = tls.Server(conn, r.tls)
+		}
+
+		// Replace connection
+		r.setConn(conn)
+
+		// Read
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.read(ctx, conn, handle)
+		}()
+	}
+}
+
+func (r *remoteReceiver) setConn(conn net.Conn) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	r.conn = conn
+}
+
+func (r *remoteReceiver) isConn(conn net.Conn) bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.conn == conn
+}
+
+func (r *remoteReceiver) read(ctx context.Context, conn net.Conn, handle func(payload []byte) error) {
+	// Make sure the connection is closed
+	defer conn.Close()
+
+	// Read hello
+	br := bufio.NewReader(conn)
+	if err := r.readHello(br); err != nil {
+		if r.isConn(conn) && ctx.Err() == nil {
+			r.eh.Emit(astiencoder.EventError(r.n, fmt.Errorf("astilibav: handshake with %s failed: %w", conn.RemoteAddr(), err)))
+		}
+		return
+	}
+
+	// Loop
+	var buf []byte
+	for {
+		// Read message
+		kind, payload, err := readRemoteMessage(br, buf)
+		if err != nil {
+			if err != io.EOF && r.isConn(conn) && ctx.Err() == nil {
+				r.eh.Emit(astiencoder.EventError(r.n, fmt.Errorf("astilibav: reading from %s failed: %w", conn.RemoteAddr(), err)))
+			}
+			return
+		}
+		buf = payload
+
+		// Invalid kind
+		if kind != r.kind {
+			r.eh.Emit(astiencoder.EventError(r.n, fmt.Errorf("astilibav: %s sent a message of kind %d, expected %d", conn.RemoteAddr(), kind, r.kind)))
+			return
+		}
+
+		// Increment incoming stats
+		r.statIncomingRate.Add(1)
+		r.statIncomingBitRate.Add(float64(len(payload)*8) / 1000)
+
+		// Handle
+		r.mh.Lock()
+		err = handle(payload)
+		r.mh.Unlock()
+		if err != nil {
+			r.eh.Emit(astiencoder.EventError(r.n, fmt.Errorf("astilibav: handling message from %s failed: %w", conn.RemoteAddr(), err)))
+		}
+	}
+}
+
+func (r *remoteReceiver) readHello(br *bufio.Reader) (err error) {
+	// Read message
+	var kind byte
+	var payload []byte
+	if kind, payload, err = readRemoteMessage(br, nil); err != nil {
+		err = fmt.Errorf("astilibav: reading message failed: %w", err)
+		return
+	}
+
+	// Invalid kind
+	if kind != remoteMessageKindHello {
+		err = fmt.Errorf("astilibav: first message is of kind %d, expected %d", kind, remoteMessageKindHello)
+		return
+	}
+
+	// Unmarshal
+	var h remoteHello
+	if err = json.Unmarshal(payload, &h); err != nil {
+		err = fmt.Errorf("astilibav: unmarshaling hello failed: %w", err)
+		return
+	}
+
+	// Check compatibility
+	if err = h.compatible(r.hello); err != nil {
+		err = fmt.Errorf("astilibav: sender is not compatible: %w", err)
+		return
+	}
+	return
+}
+
+// RemoteFrameReceiver represents an object capable of receiving frames from a RemoteSender running on another machine
+// and dispatching them to the nodes of its own workflow
+// It starts listening when it's created so that senders can connect as soon as its workflow is started
+type RemoteFrameReceiver struct {
+	*astiencoder.BaseNode
+	d          *frameDispatcher
+	descriptor Descriptor
+	outputCtx  Context
+	p          *framePool
+	r          *remoteReceiver
+}
+
+// NewRemoteFrameReceiver creates a new remote frame receiver
+func NewRemoteFrameReceiver(o RemoteReceiverOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (r *RemoteFrameReceiver, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countRemoteFrameReceiver, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("remote_frame_receiver_%d", count), fmt.Sprintf("Remote Frame Receiver #%d", count), fmt.Sprintf("Receives frames on %s", o.Addr), "remote frame receiver")
+
+	// Create receiver
+	r = &RemoteFrameReceiver{
+		descriptor: remoteDescriptor{timeBase: o.OutputCtx.TimeBase},
+		outputCtx:  o.OutputCtx,
+		p:          sharedFramePool,
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
+	r.d = newFrameDispatcher(r, eh)
+
+	// Listen
+	if r.r, err = newRemoteReceiver(remoteMessageKindFrame, o, r, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating remote receiver failed: %w", err)
+		return
+	}
+	r.addStats()
+	return
+}
+
+func (r *RemoteFrameReceiver) addStats() {
+	// Add receiver stats
+	r.r.addStats(r.Stater())
+
+	// Add dispatcher stats
+	r.d.addStats(r.Stater())
+}
+
+// Addr returns the address the receiver listens on
+func (r *RemoteFrameReceiver) Addr() net.Addr {
+	return r.r.l.Addr()
+}
+
+// OutputCtx returns the output ctx
+func (r *RemoteFrameReceiver) OutputCtx() Context {
+	return r.outputCtx
+}
+
+// Connect implements the FrameHandlerConnector interface
+func (r *RemoteFrameReceiver) Connect(h FrameHandler) {
+	// Add handler
+	r.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(r, h)
+}
+
+// Disconnect implements the FrameHandlerConnector interface
+func (r *RemoteFrameReceiver) Disconnect(h FrameHandler) {
+	// Delete handler
+	r.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(r, h)
+}
+
+// Start starts the receiver
+func (r *RemoteFrameReceiver) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer r.d.wait()
+
+		// Serve
+		r.r.serve(r.Context(), r.handle)
+	})
+}
+
+func (r *RemoteFrameReceiver) handle(payload []byte) (err error) {
+	// Handle pause
+	defer r.HandlePause()
+
+	// Get frame
+	f := r.p.get()
+	defer r.p.put(f)
+
+	// Unmarshal
+	if err = newRemoteFrame(f, payload); err != nil {
+		err = fmt.Errorf("astilibav: unmarshaling frame failed: %w", err)
+		return
+	}
+
+	// Dispatch
+	r.d.dispatch(f, r.descriptor)
+	return
+}
+
+// RemotePktReceiver represents an object capable of receiving packets from a RemoteSender running on another machine
+// and dispatching them to the nodes of its own workflow
+// Codec parameters are not sent, therefore its children must be able to process packets without them, e.g. decoders
+// created with a codec name for codecs repeating their parameters in band
+// It starts listening when it's created so that senders can connect as soon as its workflow is started
+type RemotePktReceiver struct {
+	*astiencoder.BaseNode
+	d          *pktDispatcher
+	descriptor Descriptor
+	outputCtx  Context
+	p          *pktPool
+	r          *remoteReceiver
+}
+
+// NewRemotePktReceiver creates a new remote pkt receiver
+func NewRemotePktReceiver(o RemoteReceiverOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (r *RemotePktReceiver, err error) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countRemotePktReceiver, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("remote_pkt_receiver_%d", count), fmt.Sprintf("Remote Pkt Receiver #%d", count), fmt.Sprintf("Receives packets on %s", o.Addr), "remote pkt receiver")
+
+	// Create receiver
+	r = &RemotePktReceiver{
+		d:          newPktDispatcher(),
+		descriptor: remoteDescriptor{timeBase: o.OutputCtx.TimeBase},
+		outputCtx:  o.OutputCtx,
+		p:          sharedPktPool,
+	}
+	r.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(r), eh)
+
+	// Listen
+	if r.r, err = newRemoteReceiver(remoteMessageKindPkt, o, r, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating remote receiver failed: %w", err)
+		return
+	}
+	r.addStats()
+	return
+}
+
+func (r *RemotePktReceiver) addStats() {
+	// Add receiver stats
+	r.r.addStats(r.Stater())
+
+	// Add dispatcher stats
+	r.d.addStats(r.Stater())
+}
+
+// Addr returns the address the receiver listens on
+func (r *RemotePktReceiver) Addr() net.Addr {
+	return r.r.l.Addr()
+}
+
+// OutputCtx returns the output ctx
+func (r *RemotePktReceiver) OutputCtx() Context {
+	return r.outputCtx
+}
+
+// Connect implements the PktHandlerConnector interface
+func (r *RemotePktReceiver) Connect(h PktHandler) {
+	// Add handler
+	r.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(r, h)
+}
+
+// Disconnect implements the PktHandlerConnector interface
+func (r *RemotePktReceiver) Disconnect(h PktHandler) {
+	// Delete handler
+	r.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(r, h)
+}
+
+// Start starts the receiver
+func (r *RemotePktReceiver) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	r.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer r.d.wait()
+
+		// Serve
+		r.r.serve(r.Context(), r.handle)
+	})
+}
+
+func (r *RemotePktReceiver) handle(payload []byte) (err error) {
+	// Handle pause
+	defer r.HandlePause()
+
+	// Get pkt
+	pkt := r.p.get()
+	defer r.p.put(pkt)
+
+	// Unmarshal
+	if err = newRemotePkt(pkt, payload); err != nil {
+		err = fmt.Errorf("astilibav: unmarshaling pkt failed: %w", err)
+		return
+	}
+
+	// Dispatch
+	r.d.dispatch(pkt, r.descriptor)
+	return
+}
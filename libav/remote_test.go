@@ -0,0 +1,231 @@
+package astilibav
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteMessage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, writeRemoteMessage(buf, remoteMessageKindPkt, []byte("test")))
+	assert.NoError(t, writeRemoteMessage(buf, remoteMessageKindFrame, []byte("te")))
+	assert.Equal(t, []byte{remoteMessageKindPkt, 0, 0, 0, 4, 't', 'e', 's', 't', remoteMessageKindFrame, 0, 0, 0, 2, 't', 'e'}, buf.Bytes())
+
+	kind, payload, err := readRemoteMessage(buf, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, remoteMessageKindPkt, kind)
+	assert.Equal(t, []byte("test"), payload)
+	kind, payload2, err := readRemoteMessage(buf, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, remoteMessageKindFrame, kind)
+	assert.Equal(t, []byte("te"), payload2)
+	assert.Equal(t, &payload[0], &payload2[0])
+
+	_, _, err = readRemoteMessage(bytes.NewReader([]byte{remoteMessageKindPkt, 0xff, 0xff, 0xff, 0xff}), nil)
+	assert.Error(t, err)
+	_, _, err = readRemoteMessage(bytes.NewReader([]byte{remoteMessageKindPkt, 0, 0, 0, 4, 't'}), nil)
+	assert.Error(t, err)
+}
+
+func TestRemoteHello(t *testing.T) {
+	r := newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    720,
+		TimeBase:  avutil.NewRational(1, 25),
+		Width:     1280,
+	})
+	assert.NoError(t, newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    720,
+		TimeBase:  avutil.NewRational(1, 25),
+		Width:     1280,
+	}).compatible(r))
+	assert.Error(t, newRemoteHello(remoteMessageKindPkt, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    720,
+		TimeBase:  avutil.NewRational(1, 25),
+		Width:     1280,
+	}).compatible(r))
+	assert.Error(t, newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    720,
+		TimeBase:  avutil.NewRational(1, 90000),
+		Width:     1280,
+	}).compatible(r))
+	assert.Error(t, newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    1080,
+		TimeBase:  avutil.NewRational(1, 25),
+		Width:     1920,
+	}).compatible(r))
+	assert.NoError(t, newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		Height:    1080,
+		TimeBase:  avutil.NewRational(1, 25),
+		Width:     1920,
+	}).compatible(newRemoteHello(remoteMessageKindFrame, Context{
+		CodecType: avutil.AVMEDIA_TYPE_VIDEO,
+		TimeBase:  avutil.NewRational(1, 25),
+	})))
+}
+
+func TestRemoteReceiver(t *testing.T) {
+	// Create receiver
+	eh := astiencoder.NewEventHandler()
+	errs := make(chan bool, 10)
+	eh.AddForEventName(astiencoder.EventNameError, func(e astiencoder.Event) bool {
+		errs <- true
+		return false
+	})
+	c := astikit.NewCloser()
+	defer c.Close()
+	ctx := Context{CodecType: avutil.AVMEDIA_TYPE_VIDEO, TimeBase: avutil.NewRational(1, 25)}
+	r, err := newRemoteReceiver(remoteMessageKindPkt, RemoteReceiverOptions{Addr: "127.0.0.1:0", OutputCtx: ctx}, nil, eh, c)
+	assert.NoError(t, err)
+
+	// Serve
+	m := &sync.Mutex{}
+	var ps []string
+	handled := make(chan bool, 10)
+	serveCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		r.serve(serveCtx, func(payload []byte) error {
+			m.Lock()
+			ps = append(ps, string(payload))
+			m.Unlock()
+			handled <- true
+			return nil
+		})
+		close(done)
+	}()
+
+	// Connect
+	connect := func(h remoteHello) net.Conn {
+		conn, err := net.Dial("tcp", r.l.Addr().String())
+		assert.NoError(t, err)
+		b, err := json.Marshal(h)
+		assert.NoError(t, err)
+		assert.NoError(t, writeRemoteMessage(conn, remoteMessageKindHello, b))
+		return conn
+	}
+	wait := func(ch chan bool) {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	// Incompatible sender
+	conn := connect(newRemoteHello(remoteMessageKindFrame, ctx))
+	wait(errs)
+	conn.Close()
+
+	// Compatible sender
+	conn1 := connect(newRemoteHello(remoteMessageKindPkt, ctx))
+	defer conn1.Close()
+	assert.NoError(t, writeRemoteMessage(conn1, remoteMessageKindPkt, []byte("1")))
+	wait(handled)
+
+	// Last sender wins
+	conn2 := connect(newRemoteHello(remoteMessageKindPkt, ctx))
+	defer conn2.Close()
+	assert.NoError(t, writeRemoteMessage(conn2, remoteMessageKindPkt, []byte("2")))
+	wait(handled)
+	conn1.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn1.Read(make([]byte, 1))
+	assert.Error(t, err)
+
+	// Invalid kind
+	assert.NoError(t, writeRemoteMessage(conn2, remoteMessageKindFrame, []byte("3")))
+	wait(errs)
+
+	// Stop
+	cancel()
+	wait(done)
+	m.Lock()
+	assert.Equal(t, []string{"1", "2"}, ps)
+	m.Unlock()
+	assert.Len(t, errs, 0)
+}
+
+func TestRemoteReceiverTLS(t *testing.T) {
+	// Get certificates
+	s := httptest.NewTLSServer(http.NotFoundHandler())
+	s.Close()
+	sc := &tls.Config{Certificates: s.TLS.Certificates}
+	cc := s.Client().Transport.(*http.Transport).TLSClientConfig
+
+	// Create receiver
+	eh := astiencoder.NewEventHandler()
+	errs := make(chan bool, 10)
+	eh.AddForEventName(astiencoder.EventNameError, func(e astiencoder.Event) bool {
+		errs <- true
+		return false
+	})
+	c := astikit.NewCloser()
+	defer c.Close()
+	ctx := Context{CodecType: avutil.AVMEDIA_TYPE_VIDEO, TimeBase: avutil.NewRational(1, 25)}
+	r, err := newRemoteReceiver(remoteMessageKindPkt, RemoteReceiverOptions{Addr: "127.0.0.1:0", OutputCtx: ctx, TLS: sc}, nil, eh, c)
+	assert.NoError(t, err)
+
+	// Serve
+	handled := make(chan string, 10)
+	serveCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		r.serve(serveCtx, func(payload []byte) error {
+			handled <- string(payload)
+			return nil
+		})
+		close(done)
+	}()
+	wait := func(ch chan bool) {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+	b, err := json.Marshal(newRemoteHello(remoteMessageKindPkt, ctx))
+	assert.NoError(t, err)
+
+	// Plain sender
+	conn, err := net.Dial("tcp", r.l.Addr().String())
+	assert.NoError(t, err)
+	assert.NoError(t, writeRemoteMessage(conn, remoteMessageKindHello, b))
+	wait(errs)
+	conn.Close()
+
+	// TLS sender
+	conn, err = tls.Dial("tcp", r.l.Addr().String(), cc)
+	assert.NoError(t, err)
+	defer conn.Close()
+	assert.NoError(t, writeRemoteMessage(conn, remoteMessageKindHello, b))
+	assert.NoError(t, writeRemoteMessage(conn, remoteMessageKindPkt, []byte("1")))
+	select {
+	case p := <-handled:
+		assert.Equal(t, "1", p)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	// Stop
+	cancel()
+	wait(done)
+	assert.Len(t, errs, 0)
+}
@@ -0,0 +1,162 @@
+package astilibav
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// RISTInput represents a demuxer reading a RIST simple profile stream
+// RIST packets are handled in GO and MPEG-TS payloads are relayed to the demuxer through a local UDP socket
+type RISTInput struct {
+	*Demuxer
+	r *ristReceiver
+}
+
+// RISTInputOptions represents RIST input options
+type RISTInputOptions struct {
+	// Local address RTP packets are received on (e.g. ":5000"). Its port must be even since RTCP packets are received
+	// on the next port
+	Addr string
+	// URL is set by the input
+	Demuxer DemuxerOptions
+	RIST    RISTOptions
+}
+
+// NewRISTInput creates a new RIST input
+func NewRISTInput(o RISTInputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (i *RISTInput, err error) {
+	// Default options
+	o.RIST.defaults()
+
+	// Get relay port
+	var port int
+	if port, err = ristRelayPort(); err != nil {
+		err = fmt.Errorf("astilibav: getting relay port failed: %w", err)
+		return
+	}
+
+	// Dial relay
+	var relay *net.UDPConn
+	if relay, err = net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}); err != nil {
+		err = fmt.Errorf("astilibav: dialing relay failed: %w", err)
+		return
+	}
+
+	// Create receiver
+	i = &RISTInput{}
+	if i.r, err = newRISTReceiver(o.Addr, o.RIST, relay); err != nil {
+		relay.Close()
+		err = fmt.Errorf("astilibav: creating receiver failed: %w", err)
+		return
+	}
+
+	// Start receiver since the demuxer probes the input when created
+	i.r.start()
+
+	// Make sure the receiver is properly closed
+	c.Add(func() error {
+		defer relay.Close()
+		return i.r.close()
+	})
+
+	// Create demuxer
+	o.Demuxer.URL = fmt.Sprintf("udp://127.0.0.1:%d", port)
+	if i.Demuxer, err = NewDemuxer(o.Demuxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating demuxer failed: %w", err)
+		return
+	}
+
+	// Report stats
+	i.r.reportStats(i, eh)
+	return
+}
+
+// LinkStats returns the link stats
+func (i *RISTInput) LinkStats() RISTLinkStats {
+	return i.r.linkStats()
+}
+
+// RISTOutput represents a muxer writing a RIST simple profile stream
+// The muxer writes MPEG-TS to a local UDP socket and RIST packets are handled in GO
+type RISTOutput struct {
+	*Muxer
+	s *ristSender
+}
+
+// RISTOutputOptions represents RIST output options
+type RISTOutputOptions struct {
+	// Remote address RTP packets are sent to (e.g. "10.0.0.1:5000"). Its port must be even since RTCP packets are sent
+	// to the next port
+	Addr string
+	// Format, FormatName, Rotation and URL are set by the output
+	Muxer MuxerOptions
+	RIST  RISTOptions
+}
+
+// NewRISTOutput creates a new RIST output
+func NewRISTOutput(o RISTOutputOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (out *RISTOutput, err error) {
+	// Default options
+	o.RIST.defaults()
+
+	// Listen relay
+	var relay *net.UDPConn
+	if relay, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}); err != nil {
+		err = fmt.Errorf("astilibav: listening relay failed: %w", err)
+		return
+	}
+
+	// Create sender
+	out = &RISTOutput{}
+	if out.s, err = newRISTSender(o.Addr, o.RIST); err != nil {
+		relay.Close()
+		err = fmt.Errorf("astilibav: creating sender failed: %w", err)
+		return
+	}
+
+	// Start sender
+	out.s.start()
+	out.s.read(relay, func(b []byte, _ *net.UDPAddr) {
+		if err := out.s.write(b); err != nil {
+			eh.Emit(astiencoder.EventError(out, fmt.Errorf("astilibav: writing to rist sender failed: %w", err)))
+		}
+	})
+
+	// Make sure the sender is properly closed
+	c.Add(func() error { return out.s.close(relay) })
+
+	// Create muxer
+	// Each datagram contains 7 MPEG-TS packets
+	o.Muxer.Format = nil
+	o.Muxer.FormatName = "mpegts"
+	o.Muxer.Rotation = nil
+	o.Muxer.URL = fmt.Sprintf("udp://%s?pkt_size=1316", relay.LocalAddr())
+	if out.Muxer, err = NewMuxer(o.Muxer, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating muxer failed: %w", err)
+		return
+	}
+
+	// Report stats
+	out.s.reportStats(out, eh)
+	return
+}
+
+// LinkStats returns the link stats
+func (o *RISTOutput) LinkStats() RISTLinkStats {
+	return o.s.linkStats()
+}
+
+func ristRelayPort() (port int, err error) {
+	// Listen on a random port
+	var conn *net.UDPConn
+	if conn, err = net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}); err != nil {
+		err = fmt.Errorf("astilibav: listening failed: %w", err)
+		return
+	}
+
+	// Free the port so that the demuxer can listen on it
+	port = conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
+	return
+}
@@ -0,0 +1,751 @@
+package astilibav
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// RIST simple profile as defined in VSF TR-06-1: MPEG-TS over RTP on an even port, RTCP on the next port and
+// retransmissions requested through RTCP NACKs
+const (
+	ristAppName                = "RIST"
+	ristAppSubtypeEchoRequest  = 2
+	ristAppSubtypeEchoResponse = 3
+	ristAppSubtypeRangeNACK    = 0
+	ristMinNACKInterval        = 20 * time.Millisecond
+	ristNACKFormat             = 1
+	ristReadBufferSize         = 2048
+	ristRTCPTypeApp            = 204
+	ristRTCPTypeRR             = 201
+	ristRTCPTypeRTPFB          = 205
+	ristRTCPTypeSDES           = 202
+	ristRTCPTypeSR             = 200
+	ristRTPClockRate           = 90000
+	ristRTPHeaderSize          = 12
+	ristRTPPayloadTypeMP2T     = 33
+	ristTickPeriod             = 10 * time.Millisecond
+)
+
+// RISTOptions represents RIST options shared by inputs and outputs
+type RISTOptions struct {
+	// On the receiver side, max duration a missing packet is waited for before being considered lost.
+	// On the sender side, duration during which sent packets can be retransmitted.
+	// Default is 1s
+	Buffer time.Duration
+	// Canonical name sent in RTCP SDES packets. Default is the hostname
+	CName string
+	// Max number of retransmission requests per missing packet. Default is 7
+	MaxRetries int
+	// Period at which RTCP keepalive packets are sent. Default is 100ms
+	RTCPPeriod time.Duration
+	// Period at which link statistics events are emitted. Default is 1s
+	StatsPeriod time.Duration
+}
+
+func (o *RISTOptions) defaults() {
+	if o.Buffer <= 0 {
+		o.Buffer = time.Second
+	}
+	if o.CName == "" {
+		o.CName, _ = os.Hostname()
+		if o.CName == "" {
+			o.CName = "astiencoder"
+		}
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 7
+	}
+	if o.RTCPPeriod <= 0 {
+		o.RTCPPeriod = 100 * time.Millisecond
+	}
+	if o.StatsPeriod <= 0 {
+		o.StatsPeriod = time.Second
+	}
+}
+
+// RISTLinkStats represents RIST link statistics
+// It is the payload of the RISTLinkStatsReported event
+type RISTLinkStats struct {
+	// Number of packets the receiver has given up on
+	Lost uint64
+	// Number of packets received, retransmissions included
+	Received uint64
+	// Number of missing packets that have been recovered through retransmissions
+	Recovered uint64
+	// Number of retransmissions requested by the receiver
+	Requested uint64
+	// Number of packets retransmitted by the sender
+	Retransmitted uint64
+	// Round trip time measured by the receiver
+	RTT time.Duration
+	// Number of packets sent, retransmissions excluded
+	Sent uint64
+}
+
+type ristLink struct {
+	cancel context.CancelFunc
+	ctx    context.Context
+	m      *sync.Mutex
+	o      RISTOptions
+	ssrc   uint32
+	stats  RISTLinkStats
+	wg     *sync.WaitGroup
+}
+
+func newRISTLink(o RISTOptions) *ristLink {
+	l := &ristLink{
+		m: &sync.Mutex{},
+		o: o,
+		// Least significant bit is reserved to retransmissions
+		ssrc: rand.Uint32() &^ 1,
+		wg:   &sync.WaitGroup{},
+	}
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	return l
+}
+
+func (l *ristLink) goFunc(fn func()) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		fn()
+	}()
+}
+
+func (l *ristLink) every(period time.Duration, fn func(now time.Time)) {
+	l.goFunc(func() {
+		t := time.NewTicker(period)
+		defer t.Stop()
+		for {
+			select {
+			case now := <-t.C:
+				fn(now)
+			case <-l.ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+func (l *ristLink) linkStats() RISTLinkStats {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.stats
+}
+
+func (l *ristLink) reportStats(target interface{}, eh *astiencoder.EventHandler) {
+	l.every(l.o.StatsPeriod, func(time.Time) {
+		eh.Emit(astiencoder.Event{
+			Name:    RISTLinkStatsReported,
+			Payload: l.linkStats(),
+			Target:  target,
+		})
+	})
+}
+
+// read reads packets until the link is closed. Errors such as ICMP port unreachable are ignored
+func (l *ristLink) read(conn *net.UDPConn, fn func(b []byte, addr *net.UDPAddr)) {
+	l.goFunc(func() {
+		b := make([]byte, ristReadBufferSize)
+		for {
+			n, addr, err := conn.ReadFromUDP(b)
+			if l.ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				continue
+			}
+			fn(b[:n], addr)
+		}
+	})
+}
+
+func (l *ristLink) close(conns ...*net.UDPConn) (err error) {
+	l.cancel()
+	for _, conn := range conns {
+		if errClose := conn.Close(); errClose != nil && err == nil {
+			err = errClose
+		}
+	}
+	l.wg.Wait()
+	return
+}
+
+// ristSender sends RTP packets and retransmits them upon request
+type ristSender struct {
+	*ristLink
+	connRTCP  *net.UDPConn
+	connRTP   *net.UDPConn
+	octets    uint32
+	packets   [1 << 16]*ristSentPacket
+	seq       uint16
+	startedAt time.Time
+}
+
+type ristSentPacket struct {
+	b      []byte
+	sentAt time.Time
+}
+
+func newRISTSender(addr string, o RISTOptions) (s *ristSender, err error) {
+	// Create sender
+	s = &ristSender{
+		ristLink:  newRISTLink(o),
+		seq:       uint16(rand.Uint32()),
+		startedAt: time.Now(),
+	}
+
+	// Resolve addr
+	var a *net.UDPAddr
+	if a, err = ristResolveAddr(addr); err != nil {
+		err = fmt.Errorf("astilibav: resolving %s failed: %w", addr, err)
+		return
+	}
+
+	// Dial rtp
+	if s.connRTP, err = net.DialUDP("udp", nil, a); err != nil {
+		err = fmt.Errorf("astilibav: dialing %s failed: %w", a, err)
+		return
+	}
+
+	// Dial rtcp
+	ar := &net.UDPAddr{IP: a.IP, Port: a.Port + 1, Zone: a.Zone}
+	if s.connRTCP, err = net.DialUDP("udp", nil, ar); err != nil {
+		s.connRTP.Close()
+		err = fmt.Errorf("astilibav: dialing %s failed: %w", ar, err)
+		return
+	}
+	return
+}
+
+func (s *ristSender) start() {
+	s.read(s.connRTCP, func(b []byte, _ *net.UDPAddr) { s.handleRTCP(b, time.Now()) })
+	s.every(s.o.RTCPPeriod, s.sendReport)
+}
+
+func (s *ristSender) close(conns ...*net.UDPConn) error {
+	return s.ristLink.close(append([]*net.UDPConn{s.connRTP, s.connRTCP}, conns...)...)
+}
+
+func (s *ristSender) write(payload []byte) (err error) {
+	// Create packet
+	now := time.Now()
+	s.m.Lock()
+	b := ristRTPPacket(s.seq, s.rtpTimestamp(now), s.ssrc, payload)
+	s.packets[s.seq] = &ristSentPacket{b: b, sentAt: now}
+	s.seq++
+	s.octets += uint32(len(payload))
+	s.stats.Sent++
+	s.m.Unlock()
+
+	// Write
+	if _, err = s.connRTP.Write(b); err != nil {
+		err = fmt.Errorf("astilibav: writing rtp packet failed: %w", err)
+		return
+	}
+	return
+}
+
+func (s *ristSender) rtpTimestamp(t time.Time) uint32 {
+	return uint32(t.Sub(s.startedAt) * ristRTPClockRate / time.Second)
+}
+
+func (s *ristSender) retransmit(seq uint16, now time.Time) {
+	// Get packet
+	s.m.Lock()
+	s.stats.Requested++
+	p := s.packets[seq]
+	if p == nil || now.Sub(p.sentAt) > s.o.Buffer {
+		s.m.Unlock()
+		return
+	}
+	s.stats.Retransmitted++
+	s.m.Unlock()
+
+	// Retransmissions use the ssrc with its least significant bit set
+	b := make([]byte, len(p.b))
+	copy(b, p.b)
+	binary.BigEndian.PutUint32(b[8:], s.ssrc|1)
+
+	// Write
+	s.connRTP.Write(b)
+}
+
+func (s *ristSender) sendReport(now time.Time) {
+	// Create sender report
+	s.m.Lock()
+	sr := make([]byte, 24)
+	binary.BigEndian.PutUint32(sr, s.ssrc)
+	binary.BigEndian.PutUint64(sr[4:], ristNTPTime(now))
+	binary.BigEndian.PutUint32(sr[12:], s.rtpTimestamp(now))
+	binary.BigEndian.PutUint32(sr[16:], uint32(s.stats.Sent))
+	binary.BigEndian.PutUint32(sr[20:], s.octets)
+	s.m.Unlock()
+
+	// Write
+	b := append(ristRTCPPacket(0, ristRTCPTypeSR, sr), ristSDESPacket(s.ssrc, s.o.CName)...)
+	s.connRTCP.Write(b)
+}
+
+func (s *ristSender) handleRTCP(b []byte, now time.Time) {
+	// Parse
+	ps, err := ristParseRTCP(b)
+	if err != nil {
+		return
+	}
+
+	// Loop through packets
+	for _, p := range ps {
+		switch {
+		case p.pt == ristRTCPTypeRTPFB && p.count == ristNACKFormat && len(p.body) >= 8:
+			for _, seq := range ristParseNACKFCIs(p.body[8:]) {
+				s.retransmit(seq, now)
+			}
+		case p.pt == ristRTCPTypeApp && len(p.body) >= 8 && string(p.body[4:8]) == ristAppName:
+			switch p.count {
+			case ristAppSubtypeRangeNACK:
+				for d := p.body[8:]; len(d) >= 4; d = d[4:] {
+					start, extra := binary.BigEndian.Uint16(d), binary.BigEndian.Uint16(d[2:])
+					for i := 0; i <= int(extra); i++ {
+						s.retransmit(start+uint16(i), now)
+					}
+				}
+			case ristAppSubtypeEchoRequest:
+				if len(p.body) < 16 {
+					continue
+				}
+				data := make([]byte, 12)
+				copy(data, p.body[8:16])
+				binary.BigEndian.PutUint32(data[8:], uint32(time.Since(now)/time.Microsecond))
+				s.connRTCP.Write(ristAppPacket(s.ssrc, ristAppSubtypeEchoResponse, data))
+			}
+		}
+	}
+}
+
+// ristReceiver receives RTP packets, requests missing ones and writes payloads in order
+type ristReceiver struct {
+	*ristLink
+	connRTCP   *net.UDPConn
+	connRTP    *net.UDPConn
+	highest    int64
+	mediaSSRC  uint32
+	missing    map[int64]*ristMissingPacket
+	next       int64
+	packets    map[int64][]byte
+	remoteRTCP *net.UDPAddr
+	started    bool
+	w          io.Writer
+}
+
+type ristMissingPacket struct {
+	detectedAt  time.Time
+	requestedAt time.Time
+	retries     int
+}
+
+func newRISTReceiver(addr string, o RISTOptions, w io.Writer) (r *ristReceiver, err error) {
+	// Create receiver
+	r = &ristReceiver{
+		ristLink: newRISTLink(o),
+		missing:  make(map[int64]*ristMissingPacket),
+		packets:  make(map[int64][]byte),
+		w:        w,
+	}
+
+	// Resolve addr
+	var a *net.UDPAddr
+	if a, err = ristResolveAddr(addr); err != nil {
+		err = fmt.Errorf("astilibav: resolving %s failed: %w", addr, err)
+		return
+	}
+
+	// Listen rtp
+	if r.connRTP, err = net.ListenUDP("udp", a); err != nil {
+		err = fmt.Errorf("astilibav: listening on %s failed: %w", a, err)
+		return
+	}
+
+	// Listen rtcp
+	ar := &net.UDPAddr{IP: a.IP, Port: a.Port + 1, Zone: a.Zone}
+	if r.connRTCP, err = net.ListenUDP("udp", ar); err != nil {
+		r.connRTP.Close()
+		err = fmt.Errorf("astilibav: listening on %s failed: %w", ar, err)
+		return
+	}
+	return
+}
+
+func (r *ristReceiver) start() {
+	r.read(r.connRTP, func(b []byte, _ *net.UDPAddr) { r.handleRTP(b, time.Now()) })
+	r.read(r.connRTCP, func(b []byte, addr *net.UDPAddr) { r.handleRTCP(b, addr, time.Now()) })
+	r.every(ristTickPeriod, r.tick)
+	r.every(r.o.RTCPPeriod, r.sendReport)
+}
+
+func (r *ristReceiver) close() error {
+	return r.ristLink.close(r.connRTP, r.connRTCP)
+}
+
+func (r *ristReceiver) reset() {
+	r.missing = make(map[int64]*ristMissingPacket)
+	r.packets = make(map[int64][]byte)
+	r.started = false
+}
+
+func (r *ristReceiver) handleRTP(b []byte, now time.Time) {
+	// Parse
+	seq, ssrc, payload, err := ristParseRTP(b)
+	if err != nil {
+		return
+	}
+
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Update stats
+	r.stats.Received++
+
+	// Sender has restarted
+	if r.started && ssrc&^1 != r.mediaSSRC {
+		r.reset()
+	}
+
+	// Extend sequence number
+	ext := int64(seq)
+	if r.started {
+		ext = r.highest + int64(int16(seq-uint16(r.highest)))
+	} else {
+		r.highest = ext - 1
+		r.mediaSSRC = ssrc &^ 1
+		r.next = ext
+		r.started = true
+	}
+
+	// Packet is too late or duplicated
+	if _, ok := r.packets[ext]; ok || ext < r.next {
+		return
+	}
+
+	// Packet was missing
+	if _, ok := r.missing[ext]; ok {
+		delete(r.missing, ext)
+		if ssrc&1 == 1 {
+			r.stats.Recovered++
+		}
+	}
+
+	// Detect gap
+	for s := r.highest + 1; s < ext; s++ {
+		r.missing[s] = &ristMissingPacket{detectedAt: now}
+	}
+	if ext > r.highest {
+		r.highest = ext
+	}
+
+	// Store packet
+	p := make([]byte, len(payload))
+	copy(p, payload)
+	r.packets[ext] = p
+
+	// Flush
+	r.flush()
+}
+
+// flush writes packets in order until a packet is missing. Packets that have been given up on are skipped
+func (r *ristReceiver) flush() {
+	for r.next <= r.highest {
+		if p, ok := r.packets[r.next]; ok {
+			r.w.Write(p)
+			delete(r.packets, r.next)
+		} else if _, ok := r.missing[r.next]; ok {
+			return
+		}
+		r.next++
+	}
+}
+
+func (r *ristReceiver) tick(now time.Time) {
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Get nack interval
+	interval := r.stats.RTT
+	if interval < ristMinNACKInterval {
+		interval = ristMinNACKInterval
+	}
+
+	// Loop through missing packets
+	var seqs []int64
+	for s, p := range r.missing {
+		// Give up
+		if now.Sub(p.detectedAt) > r.o.Buffer {
+			delete(r.missing, s)
+			r.stats.Lost++
+			continue
+		}
+
+		// Packet can't be requested yet
+		if r.remoteRTCP == nil || p.retries >= r.o.MaxRetries || (!p.requestedAt.IsZero() && now.Sub(p.requestedAt) < interval) {
+			continue
+		}
+
+		// Request packet
+		p.requestedAt = now
+		p.retries++
+		seqs = append(seqs, s)
+	}
+
+	// Flush
+	r.flush()
+
+	// Nothing to request
+	if len(seqs) == 0 {
+		return
+	}
+
+	// Create nack
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	us := make([]uint16, 0, len(seqs))
+	for _, s := range seqs {
+		us = append(us, uint16(s))
+	}
+	body := make([]byte, 8)
+	binary.BigEndian.PutUint32(body, r.ssrc)
+	binary.BigEndian.PutUint32(body[4:], r.mediaSSRC)
+	body = append(body, ristNACKFCIs(us)...)
+	r.stats.Requested += uint64(len(seqs))
+
+	// Write
+	b := append(ristRTCPPacket(0, ristRTCPTypeRR, ristUint32(r.ssrc)), ristSDESPacket(r.ssrc, r.o.CName)...)
+	b = append(b, ristRTCPPacket(ristNACKFormat, ristRTCPTypeRTPFB, body)...)
+	r.connRTCP.WriteToUDP(b, r.remoteRTCP)
+}
+
+func (r *ristReceiver) sendReport(now time.Time) {
+	// Get remote addr
+	r.m.Lock()
+	addr := r.remoteRTCP
+	r.m.Unlock()
+	if addr == nil {
+		return
+	}
+
+	// Write receiver report with an echo request to measure rtt
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, ristNTPTime(now))
+	b := append(ristRTCPPacket(0, ristRTCPTypeRR, ristUint32(r.ssrc)), ristSDESPacket(r.ssrc, r.o.CName)...)
+	b = append(b, ristAppPacket(r.ssrc, ristAppSubtypeEchoRequest, data)...)
+	r.connRTCP.WriteToUDP(b, addr)
+}
+
+func (r *ristReceiver) handleRTCP(b []byte, addr *net.UDPAddr, now time.Time) {
+	// Parse
+	ps, err := ristParseRTCP(b)
+	if err != nil {
+		return
+	}
+
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Update remote addr
+	r.remoteRTCP = addr
+
+	// Loop through packets
+	for _, p := range ps {
+		if p.pt == ristRTCPTypeApp && p.count == ristAppSubtypeEchoResponse && len(p.body) >= 20 && string(p.body[4:8]) == ristAppName {
+			sentAt := ristTimeFromNTP(binary.BigEndian.Uint64(p.body[8:]))
+			delay := time.Duration(binary.BigEndian.Uint32(p.body[16:])) * time.Microsecond
+			if rtt := now.Sub(sentAt) - delay; rtt >= 0 {
+				r.stats.RTT = rtt
+			}
+		}
+	}
+}
+
+func ristResolveAddr(addr string) (a *net.UDPAddr, err error) {
+	// Resolve
+	if a, err = net.ResolveUDPAddr("udp", addr); err != nil {
+		return
+	}
+
+	// RTCP uses the next port
+	if a.Port%2 != 0 {
+		err = fmt.Errorf("astilibav: port %d is not even", a.Port)
+		return
+	}
+	return
+}
+
+func ristRTPPacket(seq uint16, timestamp, ssrc uint32, payload []byte) []byte {
+	b := make([]byte, ristRTPHeaderSize+len(payload))
+	b[0] = 0x80
+	b[1] = ristRTPPayloadTypeMP2T
+	binary.BigEndian.PutUint16(b[2:], seq)
+	binary.BigEndian.PutUint32(b[4:], timestamp)
+	binary.BigEndian.PutUint32(b[8:], ssrc)
+	copy(b[ristRTPHeaderSize:], payload)
+	return b
+}
+
+func ristParseRTP(b []byte) (seq uint16, ssrc uint32, payload []byte, err error) {
+	// Invalid header
+	if len(b) < ristRTPHeaderSize || b[0]>>6 != 2 {
+		err = errors.New("astilibav: invalid rtp header")
+		return
+	}
+	seq = binary.BigEndian.Uint16(b[2:])
+	ssrc = binary.BigEndian.Uint32(b[8:])
+
+	// Skip csrcs
+	offset := ristRTPHeaderSize + 4*int(b[0]&0xf)
+
+	// Skip extension
+	if b[0]&0x10 > 0 {
+		if len(b) < offset+4 {
+			err = errors.New("astilibav: invalid rtp extension")
+			return
+		}
+		offset += 4 + 4*int(binary.BigEndian.Uint16(b[offset+2:]))
+	}
+
+	// Remove padding
+	end := len(b)
+	if b[0]&0x20 > 0 && end > 0 {
+		end -= int(b[end-1])
+	}
+
+	// Invalid payload
+	if offset > end {
+		err = errors.New("astilibav: invalid rtp payload")
+		return
+	}
+	payload = b[offset:end]
+	return
+}
+
+type ristRTCP struct {
+	body  []byte
+	count uint8
+	pt    uint8
+}
+
+// ristRTCPPacket creates an rtcp packet. Body length must be a multiple of 4
+func ristRTCPPacket(count, pt uint8, body []byte) []byte {
+	b := make([]byte, 4+len(body))
+	b[0] = 0x80 | count&0x1f
+	b[1] = pt
+	binary.BigEndian.PutUint16(b[2:], uint16(len(b)/4-1))
+	copy(b[4:], body)
+	return b
+}
+
+func ristParseRTCP(b []byte) (ps []ristRTCP, err error) {
+	for len(b) > 0 {
+		// Invalid header
+		if len(b) < 4 || b[0]>>6 != 2 {
+			err = errors.New("astilibav: invalid rtcp header")
+			return
+		}
+
+		// Invalid length
+		l := (int(binary.BigEndian.Uint16(b[2:])) + 1) * 4
+		if l > len(b) {
+			err = fmt.Errorf("astilibav: invalid rtcp length %d", l)
+			return
+		}
+
+		// Append
+		ps = append(ps, ristRTCP{
+			body:  b[4:l],
+			count: b[0] & 0x1f,
+			pt:    b[1],
+		})
+		b = b[l:]
+	}
+	return
+}
+
+func ristUint32(i uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, i)
+	return b
+}
+
+func ristSDESPacket(ssrc uint32, cname string) []byte {
+	// CNAME item followed by at least one null byte and padded to 32 bits
+	body := append(ristUint32(ssrc), 1, byte(len(cname)))
+	body = append(body, cname...)
+	body = append(body, 0)
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	return ristRTCPPacket(1, ristRTCPTypeSDES, body)
+}
+
+func ristAppPacket(ssrc uint32, subtype uint8, data []byte) []byte {
+	body := append(ristUint32(ssrc), ristAppName...)
+	return ristRTCPPacket(subtype, ristRTCPTypeApp, append(body, data...))
+}
+
+// ristNACKFCIs packs sorted sequence numbers into generic nack fcis made of a packet id and a bitmask of the 16
+// following lost packets
+func ristNACKFCIs(seqs []uint16) (b []byte) {
+	for i := 0; i < len(seqs); {
+		pid := seqs[i]
+		var blp uint16
+		j := i + 1
+		for ; j < len(seqs); j++ {
+			d := seqs[j] - pid
+			if d == 0 || d > 16 {
+				break
+			}
+			blp |= 1 << (d - 1)
+		}
+		b = append(b, byte(pid>>8), byte(pid), byte(blp>>8), byte(blp))
+		i = j
+	}
+	return
+}
+
+func ristParseNACKFCIs(b []byte) (seqs []uint16) {
+	for ; len(b) >= 4; b = b[4:] {
+		pid, blp := binary.BigEndian.Uint16(b), binary.BigEndian.Uint16(b[2:])
+		seqs = append(seqs, pid)
+		for i := uint16(0); i < 16; i++ {
+			if blp&(1<<i) > 0 {
+				seqs = append(seqs, pid+i+1)
+			}
+		}
+	}
+	return
+}
+
+const ristNTPEpochOffset = 2208988800
+
+func ristNTPTime(t time.Time) uint64 {
+	s := uint64(t.Unix() + ristNTPEpochOffset)
+	f := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return s<<32 | f
+}
+
+func ristTimeFromNTP(v uint64) time.Time {
+	s := int64(v>>32) - ristNTPEpochOffset
+	ns := int64((v & 0xffffffff) * uint64(time.Second) >> 32)
+	return time.Unix(s, ns)
+}
@@ -0,0 +1,114 @@
+package astilibav
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRISTNACKFCIs(t *testing.T) {
+	seqs := []uint16{65534, 65535, 1, 17, 18, 40}
+	b := ristNACKFCIs(seqs)
+	assert.Len(t, b, 12)
+	assert.Equal(t, seqs, ristParseNACKFCIs(b))
+}
+
+func TestRISTReceiver(t *testing.T) {
+	buf := &bytes.Buffer{}
+	o := RISTOptions{}
+	o.defaults()
+	r := &ristReceiver{
+		ristLink: newRISTLink(o),
+		missing:  make(map[int64]*ristMissingPacket),
+		packets:  make(map[int64][]byte),
+		w:        buf,
+	}
+	now := time.Now()
+
+	// Sequence numbers wrap and packet 1 is missing
+	r.handleRTP(ristRTPPacket(65535, 0, 2, []byte("a")), now)
+	r.handleRTP(ristRTPPacket(0, 0, 2, []byte("b")), now)
+	r.handleRTP(ristRTPPacket(2, 0, 2, []byte("d")), now)
+	assert.Equal(t, "ab", buf.String())
+	assert.Len(t, r.missing, 1)
+
+	// Packet 1 is retransmitted
+	r.handleRTP(ristRTPPacket(1, 0, 3, []byte("c")), now)
+	assert.Equal(t, "abcd", buf.String())
+	assert.Equal(t, uint64(1), r.stats.Recovered)
+
+	// Packet 4 is lost
+	r.handleRTP(ristRTPPacket(5, 0, 2, []byte("f")), now)
+	r.handleRTP(ristRTPPacket(3, 0, 2, []byte("e")), now)
+	assert.Equal(t, "abcde", buf.String())
+	r.tick(now.Add(o.Buffer + time.Millisecond))
+	assert.Equal(t, "abcdef", buf.String())
+	assert.Equal(t, uint64(1), r.stats.Lost)
+	assert.Equal(t, uint64(6), r.stats.Received)
+}
+
+func TestRISTLink(t *testing.T) {
+	// Get even port
+	var r *ristReceiver
+	var addr string
+	buf := &bytes.Buffer{}
+	m := &sync.Mutex{}
+	w := writerFunc(func(p []byte) (int, error) {
+		m.Lock()
+		defer m.Unlock()
+		return buf.Write(p)
+	})
+	o := RISTOptions{RTCPPeriod: 10 * time.Millisecond}
+	o.defaults()
+	for i := 0; i < 10 && r == nil; i++ {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.NoError(t, err)
+		a := conn.LocalAddr().(*net.UDPAddr)
+		conn.Close()
+		addr = (&net.UDPAddr{IP: a.IP, Port: a.Port &^ 1}).String()
+		r, _ = newRISTReceiver(addr, o, w)
+	}
+	if !assert.NotNil(t, r) {
+		return
+	}
+	r.start()
+	defer r.close()
+
+	// Create sender
+	s, err := newRISTSender(addr, o)
+	assert.NoError(t, err)
+	s.start()
+	defer s.close()
+
+	// Wait for the receiver to know the sender
+	assert.Eventually(t, func() bool {
+		r.m.Lock()
+		defer r.m.Unlock()
+		return r.remoteRTCP != nil
+	}, time.Second, time.Millisecond)
+
+	// Packet "b" is not sent but kept for retransmission
+	assert.NoError(t, s.write([]byte("a")))
+	s.m.Lock()
+	s.packets[s.seq] = &ristSentPacket{b: ristRTPPacket(s.seq, 0, s.ssrc, []byte("b")), sentAt: time.Now()}
+	s.seq++
+	s.m.Unlock()
+	assert.NoError(t, s.write([]byte("c")))
+
+	// Packet "b" is recovered
+	assert.Eventually(t, func() bool {
+		m.Lock()
+		defer m.Unlock()
+		return buf.String() == "abc"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, uint64(1), s.linkStats().Retransmitted)
+	assert.Equal(t, uint64(1), r.linkStats().Recovered)
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
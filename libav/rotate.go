@@ -0,0 +1,131 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavformat libavutil
+#include <libavformat/avformat.h>
+#include <libavutil/display.h>
+#include <math.h>
+
+static int astilibavStreamRotation(AVStream *s, double *rotation, int strip) {
+	int size = 0;
+	uint8_t *m = av_stream_get_side_data(s, AV_PKT_DATA_DISPLAYMATRIX, &size);
+	if (!m || size < 9 * 4) return 0;
+	*rotation = -av_display_rotation_get((int32_t *)m);
+	if (isnan(*rotation)) *rotation = 0;
+	if (strip) av_display_rotation_set((int32_t *)m, 0);
+	return 1;
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avformat"
+	"github.com/asticode/goav/avutil"
+)
+
+// StreamRotation returns the clockwise rotation in degrees stored in the stream's display matrix, rounded to the
+// closest multiple of 90 between 0 and 270. If strip is true, the display matrix is reset so that the rotation is
+// not propagated to outputs cloning the stream
+func StreamRotation(s *avformat.Stream, strip bool) int {
+	var r C.double
+	var st C.int
+	if strip {
+		st = 1
+	}
+	if C.astilibavStreamRotation((*C.AVStream)(unsafe.Pointer(s)), &r, st) == 0 {
+		return 0
+	}
+	return normalizeRotation(float64(r))
+}
+
+func normalizeRotation(r float64) int {
+	v := int(math.Round(r/90)) * 90 % 360
+	if v < 0 {
+		v += 360
+	}
+	return v
+}
+
+// RotateFilterer represents an object capable of physically rotating video frames, e.g. to fix sideways phone videos
+type RotateFilterer struct {
+	*Filterer
+}
+
+// RotateFiltererOptions represents rotate filterer options
+type RotateFiltererOptions struct {
+	Input     astiencoder.Node
+	Node      astiencoder.NodeOptions
+	Restamper FrameRestamper
+	// Clockwise rotation in degrees. If nil, it is read from the stream's display matrix
+	Rotation *int
+	// Stream the input frames come from
+	Stream *avformat.Stream
+	// If true, the stream's display matrix is reset
+	StripMetadata bool
+}
+
+// NewRotateFilterer creates a new rotate filterer
+// Its output ctx is the input's output ctx with width and height swapped if needed
+func NewRotateFilterer(o RotateFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *RotateFilterer, err error) {
+	// Get input ctx
+	v, ok := o.Input.(OutputContexter)
+	if !ok {
+		err = errors.New("astilibav: input is not an OutputContexter")
+		return
+	}
+	ctx := v.OutputCtx()
+
+	// Get rotation
+	var r int
+	if o.Rotation != nil {
+		r = normalizeRotation(float64(*o.Rotation))
+	}
+	if o.Stream != nil {
+		if sr := StreamRotation(o.Stream, o.StripMetadata); o.Rotation == nil {
+			r = sr
+		}
+	}
+
+	// Create filterer
+	f = &RotateFilterer{}
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   rotateContent(r),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: rotateContext(ctx, r),
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func rotateContent(r int) string {
+	switch r {
+	case 90:
+		return "transpose=clock"
+	case 180:
+		return "hflip,vflip"
+	case 270:
+		return "transpose=cclock"
+	default:
+		return "null"
+	}
+}
+
+func rotateContext(ctx Context, r int) Context {
+	if r == 90 || r == 270 {
+		ctx.Height, ctx.Width = ctx.Width, ctx.Height
+		if ctx.SampleAspectRatio.Num() > 0 && ctx.SampleAspectRatio.Den() > 0 {
+			ctx.SampleAspectRatio = avutil.NewRational(ctx.SampleAspectRatio.Den(), ctx.SampleAspectRatio.Num())
+		}
+	}
+	return ctx
+}
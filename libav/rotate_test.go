@@ -0,0 +1,18 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotate(t *testing.T) {
+	assert.Equal(t, 90, normalizeRotation(90.2))
+	assert.Equal(t, 270, normalizeRotation(-90))
+	assert.Equal(t, 0, normalizeRotation(360))
+	assert.Equal(t, "transpose=clock", rotateContent(90))
+	assert.Equal(t, "null", rotateContent(0))
+	ctx := rotateContext(Context{Height: 1080, Width: 1920}, 270)
+	assert.Equal(t, 1920, ctx.Height)
+	assert.Equal(t, 1080, ctx.Width)
+}
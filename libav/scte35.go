@@ -0,0 +1,431 @@
+package astilibav
+
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+var countSCTE35Parser uint64
+
+// SCTE-35 splice command types
+const (
+	SCTE35CommandTypeSpliceNull   = 0x00
+	SCTE35CommandTypeSpliceInsert = 0x05
+	SCTE35CommandTypeTimeSignal   = 0x06
+)
+
+// SCTE35Cue represents a SCTE-35 splice info section
+// Timestamps are expressed in a 90kHz timebase
+type SCTE35Cue struct {
+	AutoReturn     bool
+	AvailNum       uint8
+	AvailsExpected uint8
+	// Cancels a previously sent splice insert with the same event id
+	Cancel      bool
+	CommandType uint8
+	// Break duration. 0 means no duration was provided
+	Duration time.Duration
+	EventID  uint32
+	// Whether the cue has been injected through the API instead of being parsed from a packet
+	Injected  bool
+	Immediate bool
+	// True for a cue-out, false for a cue-in
+	OutOfNetwork    bool
+	PTS             int64
+	PTSSpecified    bool
+	UniqueProgramID uint16
+}
+
+// NewSCTE35CueOut creates a new splice insert cue-out
+// If duration is > 0, the break automatically returns once it has elapsed
+func NewSCTE35CueOut(eventID uint32, duration time.Duration) SCTE35Cue {
+	return SCTE35Cue{
+		AutoReturn:   duration > 0,
+		CommandType:  SCTE35CommandTypeSpliceInsert,
+		Duration:     duration,
+		EventID:      eventID,
+		Immediate:    true,
+		OutOfNetwork: true,
+	}
+}
+
+// NewSCTE35CueIn creates a new splice insert cue-in
+func NewSCTE35CueIn(eventID uint32) SCTE35Cue {
+	return SCTE35Cue{
+		CommandType: SCTE35CommandTypeSpliceInsert,
+		EventID:     eventID,
+		Immediate:   true,
+	}
+}
+
+// ParseSCTE35Cue parses a SCTE-35 splice info section
+func ParseSCTE35Cue(b []byte) (c SCTE35Cue, err error) {
+	// Invalid length
+	if len(b) < 18 {
+		err = fmt.Errorf("astilibav: invalid splice info section length %d", len(b))
+		return
+	}
+
+	// Invalid table id
+	if b[0] != 0xfc {
+		err = fmt.Errorf("astilibav: invalid table id %#x", b[0])
+		return
+	}
+
+	// Invalid section length
+	l := int(b[1]&0xf)<<8 | int(b[2])
+	if len(b) < 3+l || l < 15 {
+		err = fmt.Errorf("astilibav: invalid section length %d", l)
+		return
+	}
+	b = b[:3+l]
+
+	// Invalid crc
+	if crc := scte35CRC32(b[:len(b)-4]); crc != uint32(b[len(b)-4])<<24|uint32(b[len(b)-3])<<16|uint32(b[len(b)-2])<<8|uint32(b[len(b)-1]) {
+		err = errors.New("astilibav: invalid crc")
+		return
+	}
+
+	// Parse header
+	r := &scte35BitReader{b: b[:len(b)-4], offset: 3 * 8}
+	r.skip(8)
+	if r.read(1) == 1 {
+		err = errors.New("astilibav: encrypted packets are not supported")
+		return
+	}
+	r.skip(6)
+	ptsAdjustment := int64(r.read(33))
+	r.skip(8 + 12 + 12)
+	c.CommandType = uint8(r.read(8))
+
+	// Parse command
+	switch c.CommandType {
+	case SCTE35CommandTypeSpliceInsert:
+		c.EventID = uint32(r.read(32))
+		c.Cancel = r.read(1) == 1
+		r.skip(7)
+		if !c.Cancel {
+			c.OutOfNetwork = r.read(1) == 1
+			programSplice := r.read(1) == 1
+			durationFlag := r.read(1) == 1
+			c.Immediate = r.read(1) == 1
+			r.skip(4)
+			if programSplice && !c.Immediate {
+				c.PTS, c.PTSSpecified = r.readSpliceTime()
+			}
+			if !programSplice {
+				n := int(r.read(8))
+				for i := 0; i < n; i++ {
+					r.skip(8)
+					if !c.Immediate {
+						c.PTS, c.PTSSpecified = r.readSpliceTime()
+					}
+				}
+			}
+			if durationFlag {
+				c.AutoReturn = r.read(1) == 1
+				r.skip(6)
+				c.Duration = scte35Duration(int64(r.read(33)))
+			}
+			c.UniqueProgramID = uint16(r.read(16))
+			c.AvailNum = uint8(r.read(8))
+			c.AvailsExpected = uint8(r.read(8))
+		}
+	case SCTE35CommandTypeTimeSignal:
+		c.PTS, c.PTSSpecified = r.readSpliceTime()
+	case SCTE35CommandTypeSpliceNull:
+	default:
+		err = fmt.Errorf("astilibav: splice command type %#x is not supported", c.CommandType)
+		return
+	}
+
+	// Invalid command
+	if r.err != nil {
+		err = fmt.Errorf("astilibav: parsing command failed: %w", r.err)
+		return
+	}
+
+	// Adjust pts
+	if c.PTSSpecified {
+		c.PTS = (c.PTS + ptsAdjustment) & (1<<33 - 1)
+	}
+	return
+}
+
+func scte35Duration(v int64) time.Duration {
+	return time.Duration(v) * time.Second / 90000
+}
+
+// Bytes returns the splice info section of the cue
+func (c SCTE35Cue) Bytes() []byte {
+	// Write command
+	cmd := &scte35BitWriter{}
+	switch c.CommandType {
+	case SCTE35CommandTypeSpliceInsert:
+		cmd.write(32, uint64(c.EventID))
+		cmd.writeBool(c.Cancel)
+		cmd.write(7, 0x7f)
+		if !c.Cancel {
+			cmd.writeBool(c.OutOfNetwork)
+			cmd.writeBool(true)
+			cmd.writeBool(c.Duration > 0)
+			cmd.writeBool(c.Immediate)
+			cmd.write(4, 0xf)
+			if !c.Immediate {
+				cmd.writeSpliceTime(c.PTS, c.PTSSpecified)
+			}
+			if c.Duration > 0 {
+				cmd.writeBool(c.AutoReturn)
+				cmd.write(6, 0x3f)
+				cmd.write(33, uint64(c.Duration*90000/time.Second))
+			}
+			cmd.write(16, uint64(c.UniqueProgramID))
+			cmd.write(8, uint64(c.AvailNum))
+			cmd.write(8, uint64(c.AvailsExpected))
+		}
+	case SCTE35CommandTypeTimeSignal:
+		cmd.writeSpliceTime(c.PTS, c.PTSSpecified)
+	}
+
+	// Write section
+	w := &scte35BitWriter{}
+	w.write(8, 0xfc)
+	w.write(4, 0x3)
+	w.write(12, uint64(11+len(cmd.b)+2+4))
+	w.write(8, 0)
+	w.write(7, 0)
+	w.write(33, 0)
+	w.write(8, 0)
+	w.write(12, 0xfff)
+	w.write(12, uint64(len(cmd.b)))
+	w.write(8, uint64(c.CommandType))
+	w.b = append(w.b, cmd.b...)
+	w.write(16, 0)
+
+	// Write crc
+	crc := scte35CRC32(w.b)
+	return append(w.b, byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc))
+}
+
+type scte35BitReader struct {
+	b      []byte
+	err    error
+	offset int
+}
+
+func (r *scte35BitReader) read(n int) (v uint64) {
+	for i := 0; i < n; i++ {
+		if r.offset/8 >= len(r.b) {
+			r.err = errors.New("astilibav: unexpected end of data")
+			return
+		}
+		v = v<<1 | uint64(r.b[r.offset/8]>>(7-uint(r.offset%8))&1)
+		r.offset++
+	}
+	return
+}
+
+func (r *scte35BitReader) skip(n int) {
+	r.read(n)
+}
+
+func (r *scte35BitReader) readSpliceTime() (pts int64, specified bool) {
+	if specified = r.read(1) == 1; specified {
+		r.skip(6)
+		pts = int64(r.read(33))
+	} else {
+		r.skip(7)
+	}
+	return
+}
+
+type scte35BitWriter struct {
+	b      []byte
+	offset int
+}
+
+func (w *scte35BitWriter) write(n int, v uint64) {
+	for i := n - 1; i >= 0; i-- {
+		if w.offset%8 == 0 {
+			w.b = append(w.b, 0)
+		}
+		w.b[len(w.b)-1] |= byte(v>>uint(i)&1) << (7 - uint(w.offset%8))
+		w.offset++
+	}
+}
+
+func (w *scte35BitWriter) writeBool(v bool) {
+	if v {
+		w.write(1, 1)
+	} else {
+		w.write(1, 0)
+	}
+}
+
+func (w *scte35BitWriter) writeSpliceTime(pts int64, specified bool) {
+	w.writeBool(specified)
+	if specified {
+		w.write(6, 0x3f)
+		w.write(33, uint64(pts))
+	} else {
+		w.write(7, 0x7f)
+	}
+}
+
+// scte35CRC32 computes the MPEG-2 crc
+func scte35CRC32(b []byte) uint32 {
+	crc := uint32(0xffffffff)
+	for _, v := range b {
+		crc ^= uint32(v) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// SCTE35Parser represents an object capable of parsing SCTE-35 packets and of surfacing them as events
+// Packets are dispatched as is to connected handlers so that they can be re-emitted on outputs
+type SCTE35Parser struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *pktDispatcher
+	eh               *astiencoder.EventHandler
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// SCTE35ParserOptions represents SCTE-35 parser options
+type SCTE35ParserOptions struct {
+	Node astiencoder.NodeOptions
+}
+
+// NewSCTE35Parser creates a new SCTE-35 parser
+// It should be connected to the data stream of a MPEG-TS demuxer whose codec id is AV_CODEC_ID_SCTE_35
+func NewSCTE35Parser(o SCTE35ParserOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (p *SCTE35Parser) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countSCTE35Parser, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("scte35_parser_%d", count), fmt.Sprintf("SCTE-35 Parser #%d", count), "Parses SCTE-35", "scte35 parser")
+
+	// Create parser
+	p = &SCTE35Parser{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		d:                newPktDispatcher(),
+		eh:               eh,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	p.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(p), eh)
+	p.addStats()
+	return
+}
+
+func (p *SCTE35Parser) addStats() {
+	// Add incoming rate
+	p.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, p.statIncomingRate)
+
+	// Add work ratio
+	p.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, p.statWorkRatio)
+
+	// Add dispatcher stats
+	p.d.addStats(p.Stater())
+
+	// Add chan stats
+	p.c.AddStats(p.Stater())
+}
+
+// Connect implements the PktHandlerConnector interface
+func (p *SCTE35Parser) Connect(h PktHandler) {
+	// Add handler
+	p.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(p, h)
+}
+
+// Disconnect implements the PktHandlerConnector interface
+func (p *SCTE35Parser) Disconnect(h PktHandler) {
+	// Delete handler
+	p.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(p, h)
+}
+
+// Start starts the parser
+func (p *SCTE35Parser) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	p.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer p.d.wait()
+
+		// Make sure to stop the chan properly
+		defer p.c.Stop()
+
+		// Start chan
+		p.c.Start(p.Context())
+	})
+}
+
+// HandlePkt implements the PktHandler interface
+func (p *SCTE35Parser) HandlePkt(pl *PktHandlerPayload) {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be parsed
+	// It is dropped if the memory budget is exceeded
+	if pl = refPktHandlerPayload(pl, p); pl == nil {
+		return
+	}
+	p.c.AddWithRelease(func() {
+		// Handle pause
+		defer p.HandlePause()
+
+		// Increment incoming rate
+		p.statIncomingRate.Add(1)
+
+		// Parse
+		p.statWorkRatio.Begin()
+		c, err := ParseSCTE35Cue(C.GoBytes(unsafe.Pointer(pl.Pkt.Data()), (C.int)(pl.Pkt.Size())))
+		p.statWorkRatio.End()
+		if err != nil {
+			p.eh.Emit(astiencoder.EventError(p, fmt.Errorf("astilibav: parsing scte35 cue failed: %w", err)))
+		} else {
+			p.emit(c)
+		}
+
+		// Dispatch pkt
+		p.d.dispatch(pl.Pkt, pl.Descriptor)
+	}, func() { unrefPktHandlerPayload(pl) }, pktBufferSize(pl.Pkt))
+}
+
+// InjectCue emits the cue as if it had been parsed from a packet
+func (p *SCTE35Parser) InjectCue(c SCTE35Cue) {
+	c.Injected = true
+	p.emit(c)
+}
+
+func (p *SCTE35Parser) emit(c SCTE35Cue) {
+	p.eh.Emit(astiencoder.Event{
+		Name:    SCTE35CueReceived,
+		Payload: c,
+		Target:  p,
+	})
+}
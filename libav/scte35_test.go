@@ -0,0 +1,39 @@
+package astilibav
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCTE35Cue(t *testing.T) {
+	b, err := base64.StdEncoding.DecodeString("/DAvAAAAAAAA///wFAVIAACPf+/+c2nALv4AUsz1AAAAAAAKAAhDVUVJAAABNWLbowo=")
+	assert.NoError(t, err)
+	c, err := ParseSCTE35Cue(b)
+	assert.NoError(t, err)
+	assert.Equal(t, SCTE35Cue{
+		AutoReturn:   true,
+		CommandType:  SCTE35CommandTypeSpliceInsert,
+		Duration:     scte35Duration(0x52ccf5),
+		EventID:      0x4800008f,
+		OutOfNetwork: true,
+		PTS:          0x07369c02e,
+		PTSSpecified: true,
+	}, c)
+
+	b[10]++
+	_, err = ParseSCTE35Cue(b)
+	assert.Error(t, err)
+
+	for _, c := range []SCTE35Cue{
+		NewSCTE35CueOut(1, 30*time.Second),
+		NewSCTE35CueIn(1),
+		{CommandType: SCTE35CommandTypeTimeSignal, PTS: 1234, PTSSpecified: true},
+	} {
+		v, err := ParseSCTE35Cue(c.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, c, v)
+	}
+}
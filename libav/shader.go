@@ -0,0 +1,105 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+// ShaderFilterer represents an object capable of applying a GLSL fragment shader to video frames on the GPU, for
+// effects that are too slow on CPU
+// OpenGL ES 2 support requires building with the "opengl" tag and linking against EGL and GLESv2. Since nodes
+// exchange frames in system memory, frames are uploaded to the GPU, rendered and downloaded back for every frame
+// On headless servers using Mesa, the EGL_PLATFORM environment variable may need to be set to "surfaceless"
+// Frames must be in the rgba pixel format, which can be done with a Filterer using "format=rgba"
+// The shader receives:
+//   - uniform sampler2D u_texture: the frame
+//   - uniform vec2 u_resolution: the frame size in pixels
+//   - uniform float u_time: the frame position in seconds
+//   - varying vec2 v_texcoord: the texture coordinates where (0, 0) is the top left corner
+//
+// The shader can be swapped while the filterer is running
+type ShaderFilterer struct {
+	*FrameFilterer
+	r shaderRenderer
+}
+
+// ShaderFiltererOptions represents shader filterer options
+type ShaderFiltererOptions struct {
+	Node astiencoder.NodeOptions
+	// Pixel format must be rgba
+	OutputCtx Context
+	// GLSL ES 1.00 fragment shader source
+	Source string
+}
+
+type shaderRenderer interface {
+	close()
+	render(f Frame) error
+	setSource(src string) error
+}
+
+// NewShaderFilterer creates a new shader filterer
+func NewShaderFilterer(o ShaderFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *ShaderFilterer, err error) {
+	// No source
+	if o.Source == "" {
+		err = errors.New("astilibav: no source")
+		return
+	}
+
+	// Create renderer
+	f = &ShaderFilterer{}
+	if f.r, err = newShaderRenderer(o.Source); err != nil {
+		err = fmt.Errorf("astilibav: creating shader renderer failed: %w", err)
+		return
+	}
+
+	// Make sure the renderer is properly closed
+	c.Add(func() error {
+		f.r.close()
+		return nil
+	})
+
+	// Create frame filterer
+	if f.FrameFilterer, err = NewFrameFilterer(FrameFiltererOptions{
+		Filter:    FrameFilterFunc(f.filter),
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating frame filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *ShaderFilterer) filter(fm Frame) (Frame, error) {
+	// Invalid pixel format
+	if fm.PixelFormat != avutil.PixelFormatFromString("rgba") || len(fm.Planes) != 1 {
+		return Frame{}, errors.New("astilibav: pixel format is not rgba")
+	}
+
+	// Render
+	if err := f.r.render(fm); err != nil {
+		return Frame{}, fmt.Errorf("astilibav: rendering failed: %w", err)
+	}
+	return fm, nil
+}
+
+// SetSource swaps the shader. If the shader can't be compiled, the previous shader is kept
+func (f *ShaderFilterer) SetSource(src string) (err error) {
+	if err = f.r.setSource(src); err != nil {
+		err = fmt.Errorf("astilibav: setting source failed: %w", err)
+		return
+	}
+	return
+}
+
+func shaderTime(f Frame) float64 {
+	if f.TimeBase.Den() == 0 {
+		return 0
+	}
+	return float64(f.Pts) * float64(f.TimeBase.Num()) / float64(f.TimeBase.Den())
+}
@@ -0,0 +1,10 @@
+//go:build !opengl
+// +build !opengl
+
+package astilibav
+
+import "errors"
+
+func newShaderRenderer(src string) (shaderRenderer, error) {
+	return nil, errors.New("astilibav: opengl support is disabled, build with the opengl tag")
+}
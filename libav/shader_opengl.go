@@ -0,0 +1,289 @@
+//go:build opengl
+// +build opengl
+
+package astilibav
+
+/*
+#cgo LDFLAGS: -lEGL -lGLESv2
+#include <EGL/egl.h>
+#include <GLES2/gl2.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+typedef struct {
+	EGLDisplay display;
+	EGLSurface surface;
+	EGLContext context;
+	GLuint fbo;
+	GLuint program;
+	GLuint source;
+	GLuint target;
+	GLuint vbo;
+	int width;
+	int height;
+} astilibavShader;
+
+static const char *astilibavShaderVertex =
+	"attribute vec2 a_position;\n"
+	"varying vec2 v_texcoord;\n"
+	"void main() {\n"
+	"	v_texcoord = a_position * 0.5 + 0.5;\n"
+	"	gl_Position = vec4(a_position, 0.0, 1.0);\n"
+	"}\n";
+
+static int astilibavShaderMakeCurrent(astilibavShader *s) {
+	return eglMakeCurrent(s->display, s->surface, s->surface, s->context) == EGL_TRUE ? 0 : -1;
+}
+
+static void astilibavShaderRelease(astilibavShader *s) {
+	eglMakeCurrent(s->display, EGL_NO_SURFACE, EGL_NO_SURFACE, EGL_NO_CONTEXT);
+}
+
+static void astilibavShaderDestroy(astilibavShader *s) {
+	if (s->context != EGL_NO_CONTEXT && astilibavShaderMakeCurrent(s) == 0) {
+		if (s->program) glDeleteProgram(s->program);
+		if (s->source) glDeleteTextures(1, &s->source);
+		if (s->target) glDeleteTextures(1, &s->target);
+		if (s->fbo) glDeleteFramebuffers(1, &s->fbo);
+		if (s->vbo) glDeleteBuffers(1, &s->vbo);
+		astilibavShaderRelease(s);
+		eglDestroyContext(s->display, s->context);
+	}
+	if (s->surface != EGL_NO_SURFACE) eglDestroySurface(s->display, s->surface);
+	if (s->display != EGL_NO_DISPLAY) eglTerminate(s->display);
+	free(s);
+}
+
+static astilibavShader *astilibavShaderInit() {
+	astilibavShader *s = calloc(1, sizeof(astilibavShader));
+	if (!s) return NULL;
+	s->display = EGL_NO_DISPLAY;
+	s->surface = EGL_NO_SURFACE;
+	s->context = EGL_NO_CONTEXT;
+
+	// Create headless context
+	if ((s->display = eglGetDisplay(EGL_DEFAULT_DISPLAY)) == EGL_NO_DISPLAY) goto fail;
+	if (eglInitialize(s->display, NULL, NULL) != EGL_TRUE) goto fail;
+	if (eglBindAPI(EGL_OPENGL_ES_API) != EGL_TRUE) goto fail;
+	EGLint configAttrs[] = {EGL_SURFACE_TYPE, EGL_PBUFFER_BIT, EGL_RENDERABLE_TYPE, EGL_OPENGL_ES2_BIT, EGL_RED_SIZE, 8, EGL_GREEN_SIZE, 8, EGL_BLUE_SIZE, 8, EGL_ALPHA_SIZE, 8, EGL_NONE};
+	EGLConfig config;
+	EGLint n = 0;
+	if (eglChooseConfig(s->display, configAttrs, &config, 1, &n) != EGL_TRUE || n < 1) goto fail;
+	EGLint surfaceAttrs[] = {EGL_WIDTH, 1, EGL_HEIGHT, 1, EGL_NONE};
+	if ((s->surface = eglCreatePbufferSurface(s->display, config, surfaceAttrs)) == EGL_NO_SURFACE) goto fail;
+	EGLint contextAttrs[] = {EGL_CONTEXT_CLIENT_VERSION, 2, EGL_NONE};
+	if ((s->context = eglCreateContext(s->display, config, EGL_NO_CONTEXT, contextAttrs)) == EGL_NO_CONTEXT) goto fail;
+	if (astilibavShaderMakeCurrent(s) < 0) goto fail;
+
+	// Create fullscreen quad
+	GLfloat quad[] = {-1, -1, 1, -1, -1, 1, 1, 1};
+	glGenBuffers(1, &s->vbo);
+	glBindBuffer(GL_ARRAY_BUFFER, s->vbo);
+	glBufferData(GL_ARRAY_BUFFER, sizeof(quad), quad, GL_STATIC_DRAW);
+
+	// Create textures and framebuffer
+	GLuint textures[2];
+	glGenTextures(2, textures);
+	s->source = textures[0];
+	s->target = textures[1];
+	for (int i = 0; i < 2; i++) {
+		glBindTexture(GL_TEXTURE_2D, textures[i]);
+		glTexParameteri(GL_TEXTURE_2D, GL_TEXTURE_MIN_FILTER, GL_LINEAR);
+		glTexParameteri(GL_TEXTURE_2D, GL_TEXTURE_MAG_FILTER, GL_LINEAR);
+		glTexParameteri(GL_TEXTURE_2D, GL_TEXTURE_WRAP_S, GL_CLAMP_TO_EDGE);
+		glTexParameteri(GL_TEXTURE_2D, GL_TEXTURE_WRAP_T, GL_CLAMP_TO_EDGE);
+	}
+	glGenFramebuffers(1, &s->fbo);
+	glPixelStorei(GL_UNPACK_ALIGNMENT, 1);
+	glPixelStorei(GL_PACK_ALIGNMENT, 1);
+	if (glGetError() != GL_NO_ERROR) goto fail;
+	astilibavShaderRelease(s);
+	return s;
+
+fail:
+	astilibavShaderDestroy(s);
+	return NULL;
+}
+
+static GLuint astilibavShaderCompileStage(GLenum type, const char *src, char **log) {
+	GLuint sh = glCreateShader(type);
+	glShaderSource(sh, 1, &src, NULL);
+	glCompileShader(sh);
+	GLint ok = 0;
+	glGetShaderiv(sh, GL_COMPILE_STATUS, &ok);
+	if (!ok) {
+		*log = calloc(1024, 1);
+		if (*log) glGetShaderInfoLog(sh, 1023, NULL, *log);
+		glDeleteShader(sh);
+		return 0;
+	}
+	return sh;
+}
+
+// Must be called with the context current. On failure, log must be freed by the caller
+static GLuint astilibavShaderCompile(const char *src, char **log) {
+	GLuint vs = astilibavShaderCompileStage(GL_VERTEX_SHADER, astilibavShaderVertex, log);
+	if (!vs) return 0;
+	GLuint fs = astilibavShaderCompileStage(GL_FRAGMENT_SHADER, src, log);
+	if (!fs) {
+		glDeleteShader(vs);
+		return 0;
+	}
+	GLuint p = glCreateProgram();
+	glAttachShader(p, vs);
+	glAttachShader(p, fs);
+	glBindAttribLocation(p, 0, "a_position");
+	glLinkProgram(p);
+	glDeleteShader(vs);
+	glDeleteShader(fs);
+	GLint ok = 0;
+	glGetProgramiv(p, GL_LINK_STATUS, &ok);
+	if (!ok) {
+		*log = calloc(1024, 1);
+		if (*log) glGetProgramInfoLog(p, 1023, NULL, *log);
+		glDeleteProgram(p);
+		return 0;
+	}
+	return p;
+}
+
+// Must be called with the context current
+static void astilibavShaderSetProgram(astilibavShader *s, GLuint p) {
+	if (s->program) glDeleteProgram(s->program);
+	s->program = p;
+}
+
+// Must be called with the context current. Frame is rendered in place
+static int astilibavShaderRender(astilibavShader *s, uint8_t *data, int stride, int width, int height, float t) {
+	// Resize textures
+	if (s->width != width || s->height != height) {
+		glBindTexture(GL_TEXTURE_2D, s->source);
+		glTexImage2D(GL_TEXTURE_2D, 0, GL_RGBA, width, height, 0, GL_RGBA, GL_UNSIGNED_BYTE, NULL);
+		glBindTexture(GL_TEXTURE_2D, s->target);
+		glTexImage2D(GL_TEXTURE_2D, 0, GL_RGBA, width, height, 0, GL_RGBA, GL_UNSIGNED_BYTE, NULL);
+		glBindFramebuffer(GL_FRAMEBUFFER, s->fbo);
+		glFramebufferTexture2D(GL_FRAMEBUFFER, GL_COLOR_ATTACHMENT0, GL_TEXTURE_2D, s->target, 0);
+		if (glCheckFramebufferStatus(GL_FRAMEBUFFER) != GL_FRAMEBUFFER_COMPLETE) return -1;
+		s->width = width;
+		s->height = height;
+	}
+
+	// Upload
+	// GLES 2 has no row length, therefore padded lines are uploaded one by one
+	glBindTexture(GL_TEXTURE_2D, s->source);
+	if (stride == width * 4) {
+		glTexSubImage2D(GL_TEXTURE_2D, 0, 0, 0, width, height, GL_RGBA, GL_UNSIGNED_BYTE, data);
+	} else {
+		for (int y = 0; y < height; y++) glTexSubImage2D(GL_TEXTURE_2D, 0, 0, y, width, 1, GL_RGBA, GL_UNSIGNED_BYTE, data + y * stride);
+	}
+
+	// Render
+	glBindFramebuffer(GL_FRAMEBUFFER, s->fbo);
+	glViewport(0, 0, width, height);
+	glUseProgram(s->program);
+	glActiveTexture(GL_TEXTURE0);
+	glUniform1i(glGetUniformLocation(s->program, "u_texture"), 0);
+	glUniform2f(glGetUniformLocation(s->program, "u_resolution"), (GLfloat)width, (GLfloat)height);
+	glUniform1f(glGetUniformLocation(s->program, "u_time"), t);
+	glBindBuffer(GL_ARRAY_BUFFER, s->vbo);
+	glEnableVertexAttribArray(0);
+	glVertexAttribPointer(0, 2, GL_FLOAT, GL_FALSE, 0, 0);
+	glDrawArrays(GL_TRIANGLE_STRIP, 0, 4);
+
+	// Download
+	if (stride == width * 4) {
+		glReadPixels(0, 0, width, height, GL_RGBA, GL_UNSIGNED_BYTE, data);
+	} else {
+		for (int y = 0; y < height; y++) glReadPixels(0, y, width, 1, GL_RGBA, GL_UNSIGNED_BYTE, data + y * stride);
+	}
+	return glGetError() == GL_NO_ERROR ? 0 : -2;
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+type glShaderRenderer struct {
+	m *sync.Mutex
+	s *C.astilibavShader
+}
+
+func newShaderRenderer(src string) (shaderRenderer, error) {
+	// Create renderer
+	r := &glShaderRenderer{m: &sync.Mutex{}}
+	runtime.LockOSThread()
+	r.s = C.astilibavShaderInit()
+	runtime.UnlockOSThread()
+	if r.s == nil {
+		return nil, errors.New("astilibav: initializing egl context failed")
+	}
+
+	// Set source
+	if err := r.setSource(src); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// do executes a function with the context current on the calling thread
+func (r *glShaderRenderer) do(fn func() error) error {
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// The context is bound to the os thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// Make context current
+	if C.astilibavShaderMakeCurrent(r.s) < 0 {
+		return errors.New("astilibav: making egl context current failed")
+	}
+	defer C.astilibavShaderRelease(r.s)
+	return fn()
+}
+
+func (r *glShaderRenderer) close() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.s == nil {
+		return
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.astilibavShaderDestroy(r.s)
+	r.s = nil
+}
+
+func (r *glShaderRenderer) setSource(src string) error {
+	return r.do(func() error {
+		// Compile
+		csrc := C.CString(src)
+		defer C.free(unsafe.Pointer(csrc))
+		var log *C.char
+		p := C.astilibavShaderCompile(csrc, &log)
+		if p == 0 {
+			defer C.free(unsafe.Pointer(log))
+			return fmt.Errorf("astilibav: compiling shader failed: %s", C.GoString(log))
+		}
+
+		// Swap programs
+		C.astilibavShaderSetProgram(r.s, p)
+		return nil
+	})
+}
+
+func (r *glShaderRenderer) render(f Frame) error {
+	return r.do(func() error {
+		if ret := C.astilibavShaderRender(r.s, (*C.uint8_t)(unsafe.Pointer(&f.Planes[0][0])), C.int(f.Strides[0]), C.int(f.Width), C.int(f.Height), C.float(shaderTime(f))); ret < 0 {
+			return fmt.Errorf("astilibav: rendering failed with code %d", ret)
+		}
+		return nil
+	})
+}
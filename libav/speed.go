@@ -0,0 +1,87 @@
+package astilibav
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+)
+
+// SpeedFilterer represents an object capable of changing the speed of video frames or the tempo of audio frames
+// while preserving pitch
+// Timestamps are made relative to the first frame before being scaled so that audio and video renditions of the same
+// input stay in sync
+type SpeedFilterer struct {
+	*Filterer
+}
+
+// SpeedFiltererOptions represents speed filterer options
+type SpeedFiltererOptions struct {
+	// > 1 speeds up (e.g. time-lapse) whereas < 1 slows down (e.g. slow motion)
+	Factor float64
+	Input  astiencoder.Node
+	Node   astiencoder.NodeOptions
+	// For video, if the frame rate is set, frames are dropped or duplicated to match it
+	OutputCtx Context
+	Restamper FrameRestamper
+}
+
+// NewSpeedFilterer creates a new speed filterer
+func NewSpeedFilterer(o SpeedFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *SpeedFilterer, err error) {
+	// Get content
+	var content string
+	if content, err = speedContent(o.OutputCtx, o.Factor); err != nil {
+		err = fmt.Errorf("astilibav: getting content failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	f = &SpeedFilterer{}
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   content,
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func speedContent(ctx Context, factor float64) (string, error) {
+	// Invalid factor
+	if factor <= 0 {
+		return "", fmt.Errorf("astilibav: invalid factor %v", factor)
+	}
+
+	// Switch on codec type
+	switch ctx.CodecType {
+	case avcodec.AVMEDIA_TYPE_AUDIO:
+		// atempo only accepts factors between 0.5 and 2, therefore we need to chain several instances
+		fs := []string{"asetpts=PTS-STARTPTS"}
+		for factor > 2 {
+			fs = append(fs, "atempo=2")
+			factor /= 2
+		}
+		for factor < 0.5 {
+			fs = append(fs, "atempo=0.5")
+			factor /= 0.5
+		}
+		if factor != 1 {
+			fs = append(fs, "atempo="+formatFilterFloat(factor))
+		}
+		return strings.Join(fs, ","), nil
+	case avcodec.AVMEDIA_TYPE_VIDEO:
+		fs := []string{"setpts=(PTS-STARTPTS)/" + formatFilterFloat(factor)}
+		if ctx.FrameRate.Num() > 0 && ctx.FrameRate.Den() > 0 {
+			fs = append(fs, fmt.Sprintf("fps=%d/%d", ctx.FrameRate.Num(), ctx.FrameRate.Den()))
+		}
+		return strings.Join(fs, ","), nil
+	default:
+		return "", fmt.Errorf("astilibav: codec type %v is not handled by speed filterer", ctx.CodecType)
+	}
+}
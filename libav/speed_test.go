@@ -0,0 +1,22 @@
+package astilibav
+
+import (
+	"testing"
+
+	"github.com/asticode/goav/avcodec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpeedContent(t *testing.T) {
+	c, err := speedContent(Context{CodecType: avcodec.AVMEDIA_TYPE_AUDIO}, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "asetpts=PTS-STARTPTS,atempo=2,atempo=2,atempo=1.25", c)
+	c, err = speedContent(Context{CodecType: avcodec.AVMEDIA_TYPE_AUDIO}, 0.2)
+	assert.NoError(t, err)
+	assert.Equal(t, "asetpts=PTS-STARTPTS,atempo=0.5,atempo=0.5,atempo=0.8", c)
+	c, err = speedContent(Context{CodecType: avcodec.AVMEDIA_TYPE_VIDEO}, 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, "setpts=(PTS-STARTPTS)/0.5", c)
+	_, err = speedContent(Context{CodecType: avcodec.AVMEDIA_TYPE_VIDEO}, 0)
+	assert.Error(t, err)
+}
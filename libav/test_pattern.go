@@ -0,0 +1,270 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/pixdesc.h>
+
+typedef struct {
+	int alpha;
+	int log2_chroma_h;
+	int log2_chroma_w;
+	int nb_components;
+	int offset[4];
+	int plane[4];
+	int rgb;
+	int step[4];
+} astilibavPixFmtInfo;
+
+static int astilibavGetPixFmtInfo(int fmt, astilibavPixFmtInfo *i) {
+	const AVPixFmtDescriptor *desc = av_pix_fmt_desc_get(fmt);
+	if (!desc || desc->flags & (AV_PIX_FMT_FLAG_HWACCEL | AV_PIX_FMT_FLAG_PAL | AV_PIX_FMT_FLAG_BITSTREAM)) return -1;
+	i->alpha = desc->flags & AV_PIX_FMT_FLAG_ALPHA ? 1 : 0;
+	i->log2_chroma_h = desc->log2_chroma_h;
+	i->log2_chroma_w = desc->log2_chroma_w;
+	i->nb_components = desc->nb_components;
+	i->rgb = desc->flags & AV_PIX_FMT_FLAG_RGB ? 1 : 0;
+	for (int c = 0; c < desc->nb_components; c++) {
+		if (desc->comp[c].depth != 8 || desc->comp[c].shift != 0) return -1;
+		i->offset[c] = desc->comp[c].offset;
+		i->plane[c] = desc->comp[c].plane;
+		i->step[c] = desc->comp[c].step;
+	}
+	return 0;
+}
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+// TestPatternBurner represents an object capable of burning a running timecode and/or a per-frame QR code into video
+// frames, which allows automated end-to-end latency and frame accuracy measurements in integration tests
+// The QR code contains "f=<frame index>;p=<pts in ms>;t=<unix time in ms at which the frame was burnt>" followed by
+// ";l=<ms elapsed between the moment the frame was demuxed and the moment it was burnt>" when the frame is a latency
+// probe, which allows measuring the glass-to-glass latency of the whole chain
+// Only 8-bit non-paletted pixel formats are supported
+type TestPatternBurner struct {
+	*FrameFilterer
+	count uint64
+	o     TestPatternBurnerOptions
+}
+
+// TestPatternBurnerOptions represents test pattern burner options
+type TestPatternBurnerOptions struct {
+	Node astiencoder.NodeOptions
+	// Frame rate is used by the timecode
+	OutputCtx Context
+	// If true, a QR code is burnt
+	QR bool
+	// Size in pixels of a QR code module and of a timecode pixel. Default is 4
+	Scale int
+	// If true, a timecode is burnt below the QR code
+	Timecode bool
+	// Position of the top left corner. Default is 16
+	X, Y int
+}
+
+// NewTestPatternBurner creates a new test pattern burner
+func NewTestPatternBurner(o TestPatternBurnerOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (b *TestPatternBurner, err error) {
+	// Nothing to burn
+	if !o.QR && !o.Timecode {
+		err = errors.New("astilibav: neither qr nor timecode is enabled")
+		return
+	}
+
+	// No frame rate
+	if o.Timecode && (o.OutputCtx.FrameRate.Num() <= 0 || o.OutputCtx.FrameRate.Den() <= 0) {
+		err = errors.New("astilibav: timecode requires a frame rate")
+		return
+	}
+
+	// Default options
+	if o.Scale <= 0 {
+		o.Scale = 4
+	}
+	if o.X == 0 {
+		o.X = 16
+	}
+	if o.Y == 0 {
+		o.Y = 16
+	}
+
+	// Create frame filterer
+	b = &TestPatternBurner{o: o}
+	if b.FrameFilterer, err = NewFrameFilterer(FrameFiltererOptions{
+		Filter:    FrameFilterFunc(b.filter),
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating frame filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (b *TestPatternBurner) filter(f Frame) (Frame, error) {
+	// Get canvas
+	c, err := newTestPatternCanvas(f)
+	if err != nil {
+		return Frame{}, fmt.Errorf("astilibav: creating canvas failed: %w", err)
+	}
+
+	// Get position
+	var pos time.Duration
+	if f.TimeBase.Den() > 0 {
+		pos = time.Duration(float64(f.Pts) * float64(f.TimeBase.Num()) / float64(f.TimeBase.Den()) * 1e9)
+	}
+
+	// Burn qr code
+	y := b.o.Y
+	if b.o.QR {
+		// Create code
+		now := time.Now()
+		content := fmt.Sprintf("f=%d;p=%d;t=%d", b.count, pos.Milliseconds(), now.UnixNano()/1e6)
+		if !f.DemuxedAt.IsZero() {
+			content += fmt.Sprintf(";l=%d", now.Sub(f.DemuxedAt).Milliseconds())
+		}
+		q, err := newQRCode([]byte(content))
+		if err != nil {
+			return Frame{}, fmt.Errorf("astilibav: creating qr code failed: %w", err)
+		}
+
+		// Draw with a 4 modules quiet zone
+		c.fill(b.o.X, y, (qrSize+8)*b.o.Scale, (qrSize+8)*b.o.Scale, false)
+		for my := 0; my < qrSize; my++ {
+			for mx := 0; mx < qrSize; mx++ {
+				if q.modules[my][mx] {
+					c.fill(b.o.X+(mx+4)*b.o.Scale, y+(my+4)*b.o.Scale, b.o.Scale, b.o.Scale, true)
+				}
+			}
+		}
+		y += (qrSize + 10) * b.o.Scale
+	}
+
+	// Burn timecode
+	if b.o.Timecode {
+		c.text(b.o.X, y, b.o.Scale, testPatternTimecode(pos, b.o.OutputCtx.FrameRate))
+	}
+
+	// Increment count
+	b.count++
+	return f, nil
+}
+
+// testPatternTimecode returns a non drop frame timecode
+func testPatternTimecode(pos time.Duration, frameRate avutil.Rational) string {
+	fps := int64(math.Round(float64(frameRate.Num()) / float64(frameRate.Den())))
+	if fps <= 0 {
+		fps = 1
+	}
+	frames := int64(math.Round(pos.Seconds() * float64(frameRate.Num()) / float64(frameRate.Den())))
+	s := frames / fps
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", s/3600, s/60%60, s%60, frames%fps)
+}
+
+type testPatternCanvas struct {
+	f    Frame
+	info C.astilibavPixFmtInfo
+}
+
+func newTestPatternCanvas(f Frame) (c *testPatternCanvas, err error) {
+	c = &testPatternCanvas{f: f}
+	if C.astilibavGetPixFmtInfo(C.int(f.PixelFormat), &c.info) < 0 {
+		err = errors.New("astilibav: pixel format is not supported")
+		return
+	}
+	return
+}
+
+// fill fills a rectangle with black or white, clipping it to the frame
+func (c *testPatternCanvas) fill(x, y, w, h int, dark bool) {
+	if x < 0 {
+		w, x = w+x, 0
+	}
+	if y < 0 {
+		h, y = h+y, 0
+	}
+	for py := y; py < y+h && py < c.f.Height; py++ {
+		for px := x; px < x+w && px < c.f.Width; px++ {
+			c.set(px, py, dark)
+		}
+	}
+}
+
+func (c *testPatternCanvas) set(x, y int, dark bool) {
+	for i := 0; i < int(c.info.nb_components); i++ {
+		// Get value
+		var v byte
+		switch {
+		case c.info.alpha == 1 && i == int(c.info.nb_components)-1:
+			v = 255
+		case c.info.rgb == 1:
+			v = 255
+			if dark {
+				v = 0
+			}
+		case i == 0:
+			v = 235
+			if dark {
+				v = 16
+			}
+		default:
+			v = 128
+		}
+
+		// Get coordinates
+		cx, cy := x, y
+		if c.info.rgb == 0 && (i == 1 || i == 2) {
+			cx, cy = x>>uint(c.info.log2_chroma_w), y>>uint(c.info.log2_chroma_h)
+		}
+
+		// Set
+		p := int(c.info.plane[i])
+		if p >= len(c.f.Planes) {
+			continue
+		}
+		if o := cy*c.f.Strides[p] + cx*int(c.info.step[i]) + int(c.info.offset[i]); o < len(c.f.Planes[p]) {
+			c.f.Planes[p][o] = v
+		}
+	}
+}
+
+// 3x5 glyphs where each row is a 3 bits mask
+var testPatternGlyphs = map[rune][5]byte{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	':': {0, 2, 0, 2, 0},
+}
+
+// text draws black text on a white box
+func (c *testPatternCanvas) text(x, y, scale int, s string) {
+	// Draw box with a 1 pixel margin
+	c.fill(x, y, (len(s)*4+1)*scale, 7*scale, false)
+
+	// Loop through glyphs
+	for i, r := range s {
+		g := testPatternGlyphs[r]
+		for gy := 0; gy < 5; gy++ {
+			for gx := 0; gx < 3; gx++ {
+				if g[gy]>>uint(2-gx)&1 == 1 {
+					c.fill(x+(1+i*4+gx)*scale, y+(1+gy)*scale, scale, scale, true)
+				}
+			}
+		}
+	}
+}
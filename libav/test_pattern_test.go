@@ -0,0 +1,14 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asticode/goav/avutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestPatternTimecode(t *testing.T) {
+	assert.Equal(t, "01:02:03:12", testPatternTimecode(time.Hour+2*time.Minute+3*time.Second+480*time.Millisecond, avutil.NewRational(25, 1)))
+	assert.Equal(t, "00:00:01:00", testPatternTimecode(time.Second+time.Millisecond, avutil.NewRational(30000, 1001)))
+}
@@ -0,0 +1,258 @@
+package astilibav
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+)
+
+// TextOverlayFilterer represents an object capable of drawing a text on video frames
+// The text is a template rendered periodically, which allows displaying clocks, scoreboards or tickers
+type TextOverlayFilterer struct {
+	*Filterer
+	m    *sync.Mutex
+	o    TextOverlayFiltererOptions
+	t    *template.Template
+	text string
+	vars map[string]string
+}
+
+// TextOverlayFiltererOptions represents text overlay filterer options
+type TextOverlayFiltererOptions struct {
+	// If true, a box is drawn behind the text
+	Box bool
+	// Default is 0
+	BoxBorderWidth int
+	// Default is "black@0.5"
+	BoxColor string
+	// Its result is available in the template through {{.data}}
+	Data func() interface{}
+	// Default is "white"
+	FontColor string
+	// If empty, the default font is used
+	FontFile string
+	// Default is 24
+	FontSize int
+	Input    astiencoder.Node
+	Node     astiencoder.NodeOptions
+	// Period at which the template is rendered. Default is 1s
+	Period    time.Duration
+	OutputCtx Context
+	Restamper FrameRestamper
+	// Go template where {{.time}} is the current time.Time, {{.vars}} are the variables set through SetVar and
+	// {{.data}} is the result of the data callback
+	Template string
+	// Expressions as used in ffmpeg's drawtext filter. Default is "10"
+	X string
+	Y string
+}
+
+// NewTextOverlayFilterer creates a new text overlay filterer
+func NewTextOverlayFilterer(o TextOverlayFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *TextOverlayFilterer, err error) {
+	// Default options
+	if o.BoxColor == "" {
+		o.BoxColor = "black@0.5"
+	}
+	if o.FontColor == "" {
+		o.FontColor = "white"
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 24
+	}
+	if o.Period <= 0 {
+		o.Period = time.Second
+	}
+	if o.X == "" {
+		o.X = "10"
+	}
+	if o.Y == "" {
+		o.Y = "10"
+	}
+
+	// No template
+	if o.Template == "" {
+		err = errors.New("astilibav: no template")
+		return
+	}
+
+	// Create filterer
+	f = &TextOverlayFilterer{
+		m:    &sync.Mutex{},
+		o:    o,
+		vars: make(map[string]string),
+	}
+
+	// Parse template
+	if f.t, err = template.New("").Parse(o.Template); err != nil {
+		err = fmt.Errorf("astilibav: parsing template %s failed: %w", o.Template, err)
+		return
+	}
+
+	// Render
+	if f.text, err = f.render(time.Now()); err != nil {
+		err = fmt.Errorf("astilibav: rendering failed: %w", err)
+		return
+	}
+
+	// Create filterer
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content:   f.content(),
+		Inputs:    map[string]astiencoder.Node{"in": o.Input},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+func (f *TextOverlayFilterer) content() string {
+	// Expansion is disabled so that the rendered text is drawn as is
+	opts := []string{
+		"expansion=none",
+		"fontcolor=" + escapeFilterOption(f.o.FontColor),
+		"fontsize=" + strconv.Itoa(f.o.FontSize),
+		"text=" + escapeFilterOption(f.text),
+		"x=" + escapeFilterOption(f.o.X),
+		"y=" + escapeFilterOption(f.o.Y),
+	}
+	if f.o.FontFile != "" {
+		opts = append(opts, "fontfile="+escapeFilterOption(f.o.FontFile))
+	}
+	if f.o.Box {
+		opts = append(opts, "box=1", "boxborderw="+strconv.Itoa(f.o.BoxBorderWidth), "boxcolor="+escapeFilterOption(f.o.BoxColor))
+	}
+	return "drawtext=" + strings.Join(opts, ":")
+}
+
+func (f *TextOverlayFilterer) render(now time.Time) (string, error) {
+	// Create data
+	f.m.Lock()
+	t := f.t
+	vars := make(map[string]string, len(f.vars))
+	for k, v := range f.vars {
+		vars[k] = v
+	}
+	f.m.Unlock()
+	data := map[string]interface{}{
+		"time": now,
+		"vars": vars,
+	}
+	if f.o.Data != nil {
+		data["data"] = f.o.Data()
+	}
+
+	// Execute template
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("astilibav: executing template failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// SetVar sets a variable available in the template through {{.vars.<key>}}
+// It's taken into account the next time the template is rendered
+func (f *TextOverlayFilterer) SetVar(k, v string) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.vars[k] = v
+}
+
+// TextOverlayReconfigureOptions represents the text overlay options that can be changed while it's running
+type TextOverlayReconfigureOptions struct {
+	Template string `json:"template,omitempty"`
+	// Set as if SetVar was called for each of them
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// Reconfigure implements the astiencoder.Reconfigurer interface
+// Changes are taken into account the next time the template is rendered
+func (f *TextOverlayFilterer) Reconfigure(opts interface{}) (err error) {
+	// Decode options
+	var o TextOverlayReconfigureOptions
+	if err = astiencoder.DecodeReconfigureOptions(opts, &o); err != nil {
+		err = fmt.Errorf("astilibav: decoding options failed: %w", err)
+		return
+	}
+
+	// Parse template
+	var t *template.Template
+	if o.Template != "" {
+		if t, err = template.New("").Parse(o.Template); err != nil {
+			err = fmt.Errorf("astilibav: parsing template %s failed: %w", o.Template, err)
+			return
+		}
+	}
+
+	// Update
+	f.m.Lock()
+	defer f.m.Unlock()
+	if t != nil {
+		f.t = t
+	}
+	for k, v := range o.Vars {
+		f.vars[k] = v
+	}
+	return
+}
+
+// Start starts the filterer and renders the template periodically
+func (f *TextOverlayFilterer) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	// Start filterer
+	f.Filterer.Start(ctx, t)
+
+	// Node has not been started
+	ctx = f.Context()
+	if ctx == nil {
+		return
+	}
+
+	// Render periodically
+	go func() {
+		tk := time.NewTicker(f.o.Period)
+		defer tk.Stop()
+		for {
+			select {
+			case n := <-tk.C:
+				if err := f.update(n); err != nil {
+					f.eh.Emit(astiencoder.EventError(f, fmt.Errorf("astilibav: updating text failed: %w", err)))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (f *TextOverlayFilterer) update(now time.Time) (err error) {
+	// Render
+	var text string
+	if text, err = f.render(now); err != nil {
+		err = fmt.Errorf("astilibav: rendering failed: %w", err)
+		return
+	}
+
+	// Text has not changed
+	if text == f.text {
+		return
+	}
+
+	// Send command
+	if err = f.SendCommand("drawtext", "reinit", "text="+escapeFilterCommandArg(text), 0); err != nil {
+		err = fmt.Errorf("astilibav: sending command failed: %w", err)
+		return
+	}
+	f.text = text
+	return
+}
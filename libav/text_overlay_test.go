@@ -0,0 +1,31 @@
+package astilibav
+
+import (
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextOverlayFilterer(t *testing.T) {
+	f := &TextOverlayFilterer{
+		m: &sync.Mutex{},
+		o: TextOverlayFiltererOptions{
+			Data:      func() interface{} { return 3 },
+			FontColor: "white",
+			FontSize:  24,
+			X:         "10",
+			Y:         "h-th-10",
+		},
+		t:    template.Must(template.New("").Parse(`{{ .time.Format "15:04:05" }} {{ .vars.home }}: {{ .data }}`)),
+		vars: make(map[string]string),
+	}
+	f.SetVar("home", "PSG")
+	text, err := f.render(time.Date(2020, 1, 1, 12, 30, 45, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "12:30:45 PSG: 3", text)
+	f.text = text
+	assert.Equal(t, `drawtext=expansion=none:fontcolor=white:fontsize=24:text=12\\:30\\:45 PSG\\: 3:x=10:y=h-th-10`, f.content())
+}
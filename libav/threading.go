@@ -0,0 +1,90 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec
+#include <libavcodec/avcodec.h>
+
+static int astilibavCodecCapabilities(AVCodec *c) {
+	return c->capabilities;
+}
+
+static void astilibavSetCodecThreading(AVCodecContext *c, int threadType, int slices) {
+	if (threadType > 0) c->thread_type = threadType;
+	if (slices > 0) c->slices = slices;
+}
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/goav/avcodec"
+)
+
+// ThreadType represents the way a codec splits its work among threads
+type ThreadType int
+
+// Thread types
+// They can be combined in which case the codec picks the one it supports
+const (
+	// Several frames are encoded in parallel which adds a delay of one frame per thread
+	ThreadTypeFrame = ThreadType(C.FF_THREAD_FRAME)
+	// Each frame is split into slices encoded in parallel which doesn't add any delay
+	ThreadTypeSlice = ThreadType(C.FF_THREAD_SLICE)
+)
+
+func (t ThreadType) String() string {
+	switch t {
+	case ThreadTypeFrame:
+		return "frame"
+	case ThreadTypeSlice:
+		return "slice"
+	case ThreadTypeFrame | ThreadTypeSlice:
+		return "frame+slice"
+	}
+	return fmt.Sprintf("%d", int(t))
+}
+
+// setCodecThreading sets the thread type and the number of slices of the codec context and makes sure the codec
+// supports at least one of the requested thread types
+func setCodecThreading(ctxCodec *avcodec.Context, cdc *avcodec.Codec, ctx Context) (err error) {
+	// Nothing to do
+	if ctx.ThreadType == 0 && ctx.Slices <= 0 {
+		return
+	}
+
+	// Check capabilities
+	if ctx.ThreadType > 0 {
+		cs := int(C.astilibavCodecCapabilities((*C.AVCodec)(unsafe.Pointer(cdc))))
+		if !threadTypeSupported(ctx.ThreadType, cs) {
+			err = fmt.Errorf("astilibav: thread type %s is not supported by codec %s", ctx.ThreadType, avcodec.AvcodecGetName(ctxCodec.CodecId()))
+			return
+		}
+
+		// Use as many threads as there are cores unless specified otherwise
+		if ctx.ThreadCount == nil {
+			ctxCodec.SetThreadCount(0)
+		}
+	}
+
+	// Set threading
+	C.astilibavSetCodecThreading((*C.AVCodecContext)(unsafe.Pointer(ctxCodec)), C.int(ctx.ThreadType), C.int(ctx.Slices))
+	return
+}
+
+func threadTypeSupported(t ThreadType, capabilities int) bool {
+	return (t&ThreadTypeFrame > 0 && capabilities&C.AV_CODEC_CAP_FRAME_THREADS > 0) ||
+		(t&ThreadTypeSlice > 0 && capabilities&C.AV_CODEC_CAP_SLICE_THREADS > 0)
+}
+
+// lockOSThreadToNodeCPUs pins the current thread to the node CPUs so that threads created by the codec while it's
+// opened inherit them
+func lockOSThreadToNodeCPUs(o astiencoder.NodeOptions) (unlock func(), err error) {
+	// Nothing to do
+	if len(o.CPUs) == 0 {
+		unlock = func() {}
+		return
+	}
+	return astiencoder.LockOSThreadToCPUs(o.CPUs)
+}
@@ -0,0 +1,332 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavcodec libavformat
+#include <libavcodec/avcodec.h>
+#include <libavformat/avformat.h>
+#include <string.h>
+
+static void astilibavSetTimedID3Stream(AVStream *s) {
+	s->codecpar->codec_type = AVMEDIA_TYPE_DATA;
+	s->codecpar->codec_id = AV_CODEC_ID_TIMED_ID3;
+	s->time_base = (AVRational){1, 90000};
+}
+
+static int astilibavSetPacketData(AVPacket *pkt, uint8_t *data, int size) {
+	int ret = av_new_packet(pkt, size);
+	if (ret < 0) return ret;
+	memcpy(pkt->data, data, size);
+	return 0;
+}
+*/
+import "C"
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avformat"
+	"github.com/asticode/goav/avutil"
+)
+
+// TimedMetadata represents metadata attached to a position in a stream, e.g. for interactive overlays or ad signaling
+// on the player side
+type TimedMetadata struct {
+	// ID3 tag (see ID3Tag) when injected in MPEG-TS, message data when injected in emsg boxes
+	Data []byte
+	// Only used by emsg boxes
+	Duration    time.Duration
+	ID          uint32
+	SchemeIDURI string
+	Value       string
+	// Position in the stream, 0 being its first packet
+	Position time.Duration
+}
+
+type timedMetadataQueue struct {
+	m  *sync.Mutex
+	ms []TimedMetadata
+}
+
+func newTimedMetadataQueue() *timedMetadataQueue {
+	return &timedMetadataQueue{m: &sync.Mutex{}}
+}
+
+func (q *timedMetadataQueue) add(m TimedMetadata) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	q.ms = append(q.ms, m)
+	sort.SliceStable(q.ms, func(i, j int) bool { return q.ms[i].Position < q.ms[j].Position })
+}
+
+// pop removes and returns metadata whose position is before the provided position
+func (q *timedMetadataQueue) pop(before time.Duration) (ms []TimedMetadata) {
+	q.m.Lock()
+	defer q.m.Unlock()
+	idx := sort.Search(len(q.ms), func(i int) bool { return q.ms[i].Position >= before })
+	ms = append(ms, q.ms[:idx]...)
+	q.ms = q.ms[idx:]
+	return
+}
+
+// ID3Frame represents an ID3v2.4 frame
+type ID3Frame struct {
+	Data []byte
+	// 4 characters, e.g. "TXXX" or "PRIV"
+	ID string
+}
+
+// NewID3TXXXFrame creates a new user defined text frame
+func NewID3TXXXFrame(description, value string) ID3Frame {
+	// UTF-8 encoding
+	b := []byte{3}
+	b = append(b, description...)
+	b = append(b, 0)
+	b = append(b, value...)
+	return ID3Frame{Data: b, ID: "TXXX"}
+}
+
+// NewID3PRIVFrame creates a new private frame
+func NewID3PRIVFrame(owner string, data []byte) ID3Frame {
+	b := append([]byte(owner), 0)
+	return ID3Frame{Data: append(b, data...), ID: "PRIV"}
+}
+
+// ID3Tag creates an ID3v2.4 tag
+func ID3Tag(fs ...ID3Frame) []byte {
+	// Frames
+	var fb []byte
+	for _, f := range fs {
+		fb = append(fb, f.ID...)
+		fb = append(fb, id3SyncSafe(len(f.Data))...)
+		fb = append(fb, 0, 0)
+		fb = append(fb, f.Data...)
+	}
+
+	// Header
+	b := []byte{'I', 'D', '3', 4, 0, 0}
+	b = append(b, id3SyncSafe(len(fb))...)
+	return append(b, fb...)
+}
+
+func id3SyncSafe(v int) []byte {
+	return []byte{byte(v>>21) & 0x7f, byte(v>>14) & 0x7f, byte(v>>7) & 0x7f, byte(v) & 0x7f}
+}
+
+// emsgBox creates a version 1 emsg box whose times use a 1000 timescale
+func emsgBox(m TimedMetadata) []byte {
+	// Payload
+	b := make([]byte, 8, 64)
+	b = append(b, 1, 0, 0, 0)
+	b = appendUint32(b, 1000)
+	b = appendUint64(b, uint64(m.Position.Milliseconds()))
+	b = appendUint32(b, uint32(m.Duration.Milliseconds()))
+	b = appendUint32(b, m.ID)
+	b = append(b, m.SchemeIDURI...)
+	b = append(b, 0)
+	b = append(b, m.Value...)
+	b = append(b, 0)
+	b = append(b, m.Data...)
+
+	// Header
+	binary.BigEndian.PutUint32(b, uint32(len(b)))
+	copy(b[4:], "emsg")
+	return b
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+var countTimedMetadataInjector uint64
+
+// TimedMetadataInjector represents an object capable of injecting timed ID3 packets in a MPEG-TS output (e.g. HLS)
+// It should be connected to a reference stream of the output from which positions are computed, and the muxer pkt
+// handler of the stream added through AddStream should be connected to it
+// Metadata whose position is already reached when it's scheduled is injected with the next packet
+type TimedMetadataInjector struct {
+	*astiencoder.BaseNode
+	c                *astiencoder.Queue
+	d                *pktDispatcher
+	eh               *astiencoder.EventHandler
+	firstPts         *int64
+	lastPts          *int64
+	q                *timedMetadataQueue
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// TimedMetadataInjectorOptions represents timed metadata injector options
+type TimedMetadataInjectorOptions struct {
+	Node astiencoder.NodeOptions
+}
+
+// NewTimedMetadataInjector creates a new timed metadata injector
+func NewTimedMetadataInjector(o TimedMetadataInjectorOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (i *TimedMetadataInjector) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countTimedMetadataInjector, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("timed_metadata_injector_%d", count), fmt.Sprintf("Timed Metadata Injector #%d", count), "Injects timed metadata", "timed metadata injector")
+
+	// Create injector
+	i = &TimedMetadataInjector{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		d:                newPktDispatcher(),
+		eh:               eh,
+		q:                newTimedMetadataQueue(),
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	i.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(i), eh)
+	i.addStats()
+	return
+}
+
+func (i *TimedMetadataInjector) addStats() {
+	// Add incoming rate
+	i.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of packets coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "pps",
+	}, i.statIncomingRate)
+
+	// Add work ratio
+	i.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, i.statWorkRatio)
+
+	// Add dispatcher stats
+	i.d.addStats(i.Stater())
+
+	// Add chan stats
+	i.c.AddStats(i.Stater())
+}
+
+// AddStream adds a timed ID3 stream to the format ctx
+func (i *TimedMetadataInjector) AddStream(ctxFormat *avformat.Context) *avformat.Stream {
+	s := AddStream(ctxFormat)
+	C.astilibavSetTimedID3Stream((*C.AVStream)(unsafe.Pointer(s)))
+	return s
+}
+
+// Connect implements the PktHandlerConnector interface
+func (i *TimedMetadataInjector) Connect(h PktHandler) {
+	// Add handler
+	i.d.addHandler(h)
+
+	// Connect nodes
+	astiencoder.ConnectNodes(i, h)
+}
+
+// Disconnect implements the PktHandlerConnector interface
+func (i *TimedMetadataInjector) Disconnect(h PktHandler) {
+	// Delete handler
+	i.d.delHandler(h)
+
+	// Disconnect nodes
+	astiencoder.DisconnectNodes(i, h)
+}
+
+// Start starts the injector
+func (i *TimedMetadataInjector) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	i.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to wait for all dispatcher subprocesses to be done so that they are properly closed
+		defer i.d.wait()
+
+		// Make sure to stop the chan properly
+		defer i.c.Stop()
+
+		// Start chan
+		i.c.Start(i.Context())
+	})
+}
+
+// Schedule schedules metadata whose data must be an ID3 tag
+func (i *TimedMetadataInjector) Schedule(m TimedMetadata) (err error) {
+	// Invalid data
+	if len(m.Data) < 10 || string(m.Data[:3]) != "ID3" {
+		err = errors.New("astilibav: data is not an id3 tag")
+		return
+	}
+
+	// Add
+	i.q.add(m)
+	return
+}
+
+// HandlePkt implements the PktHandler interface
+func (i *TimedMetadataInjector) HandlePkt(p *PktHandlerPayload) {
+	// Add to queue
+	// The pkt is referenced so that HandlePkt doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refPktHandlerPayload(p, i); p == nil {
+		return
+	}
+	i.c.AddWithRelease(func() {
+		// Handle pause
+		defer i.HandlePause()
+
+		// Increment incoming rate
+		i.statIncomingRate.Add(1)
+
+		// Get pts
+		pts := p.Pkt.Pts()
+		if pts == avutil.AV_NOPTS_VALUE {
+			pts = p.Pkt.Dts()
+		}
+		if pts == avutil.AV_NOPTS_VALUE {
+			return
+		}
+		if i.firstPts == nil {
+			i.firstPts = astikit.Int64Ptr(pts)
+		}
+
+		// Loop through metadata whose position is reached
+		pos := time.Duration(avutil.AvRescaleQ(pts-*i.firstPts, p.Descriptor.TimeBase(), nanosecondRational))
+		for _, m := range i.q.pop(pos + 1) {
+			// Timestamps must increase
+			mPts := *i.firstPts + avutil.AvRescaleQ(int64(m.Position), nanosecondRational, p.Descriptor.TimeBase())
+			if i.lastPts != nil && mPts <= *i.lastPts {
+				mPts = *i.lastPts + 1
+			}
+			i.lastPts = astikit.Int64Ptr(mPts)
+
+			// Inject
+			i.statWorkRatio.Begin()
+			err := i.inject(m, mPts, p.Descriptor)
+			i.statWorkRatio.End()
+			if err != nil {
+				i.eh.Emit(astiencoder.EventError(i, fmt.Errorf("astilibav: injecting timed metadata failed: %w", err)))
+			}
+		}
+	}, func() { unrefPktHandlerPayload(p) }, pktBufferSize(p.Pkt))
+}
+
+func (i *TimedMetadataInjector) inject(m TimedMetadata, pts int64, d Descriptor) (err error) {
+	// Create pkt
+	pkt := i.d.p.get()
+	defer i.d.p.put(pkt)
+	if ret := C.astilibavSetPacketData((*C.AVPacket)(unsafe.Pointer(pkt)), (*C.uint8_t)(unsafe.Pointer(&m.Data[0])), C.int(len(m.Data))); ret < 0 {
+		err = fmt.Errorf("astilibav: setting packet data failed: %w", NewAvError(int(ret)))
+		return
+	}
+	pkt.SetPts(pts)
+	pkt.SetDts(pts)
+
+	// Dispatch
+	i.d.dispatch(pkt, d)
+	return
+}
@@ -0,0 +1,26 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestID3Tag(t *testing.T) {
+	assert.Equal(t, []byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 17, 'T', 'X', 'X', 'X', 0, 0, 0, 7, 0, 0, 3, 'k', 'e', 'y', 0, 'v', 'a'}, ID3Tag(NewID3TXXXFrame("key", "va")))
+	assert.Equal(t, []byte{0, 0, 1, 0x7f}, id3SyncSafe(255))
+}
+
+func TestEmsgBox(t *testing.T) {
+	b := emsgBox(TimedMetadata{Data: []byte{1}, Duration: 2 * time.Second, ID: 3, Position: 1500 * time.Millisecond, SchemeIDURI: "urn:a", Value: "v"})
+	assert.Equal(t, []byte{0, 0, 0, 0x29, 'e', 'm', 's', 'g', 1, 0, 0, 0, 0, 0, 0x03, 0xe8, 0, 0, 0, 0, 0, 0, 0x05, 0xdc, 0, 0, 0x07, 0xd0, 0, 0, 0, 3, 'u', 'r', 'n', ':', 'a', 0, 'v', 0, 1}, b)
+}
+
+func TestTimedMetadataQueue(t *testing.T) {
+	q := newTimedMetadataQueue()
+	q.add(TimedMetadata{ID: 2, Position: 2 * time.Second})
+	q.add(TimedMetadata{ID: 1, Position: time.Second})
+	assert.Equal(t, []TimedMetadata{{ID: 1, Position: time.Second}}, q.pop(2*time.Second))
+	assert.Equal(t, []TimedMetadata{{ID: 2, Position: 2 * time.Second}}, q.pop(3*time.Second))
+}
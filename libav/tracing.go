@@ -0,0 +1,131 @@
+package astilibav
+
+import (
+	"context"
+	"sync"
+
+	"github.com/asticode/go-astiencoder"
+)
+
+// Span names of the media path
+// Sampled GOPs are traced through the media path: the demuxer starts a trace on their first video pkt and the
+// following nodes add a child span covering the time between the moment they receive the traced object and the
+// moment they dispatch the object it has become
+const (
+	SpanNameDecode = "astilibav.decode"
+	SpanNameDemux  = "astilibav.demux"
+	SpanNameEncode = "astilibav.encode"
+	SpanNameFilter = "astilibav.filter"
+	SpanNameMux    = "astilibav.mux"
+)
+
+// Span attribute keys of the media path
+const (
+	SpanAttributePts         = "astilibav.pts"
+	SpanAttributeStreamIndex = "astilibav.stream.index"
+)
+
+const pendingSpansMaxSize = 8
+
+type tracedContextKey struct{}
+
+// pendingSpans keeps track of the spans of traced objects and of the latency probes being processed asynchronously
+// (e.g. by codecs) which are indexed by pts since pts is the only thing that links an input to its output
+type pendingSpans struct {
+	m  *sync.Mutex
+	ss map[int64]pendingSpan
+}
+
+type pendingSpan struct {
+	ctx context.Context
+	// Nil when the object is only a latency probe
+	s astiencoder.Span
+}
+
+func (p pendingSpan) end() {
+	if p.s != nil {
+		p.s.End()
+	}
+}
+
+func newPendingSpans() *pendingSpans {
+	return &pendingSpans{
+		m:  &sync.Mutex{},
+		ss: make(map[int64]pendingSpan),
+	}
+}
+
+// start starts a span for a traced object and keeps track of latency probes
+func (ps *pendingSpans) start(traceCtx context.Context, n astiencoder.Node, spanName string, pts int64) {
+	// Start span
+	ctx, s := startSpan(traceCtx, n, spanName, pts)
+	if s == nil {
+		// Object is neither traced nor a latency probe
+		if _, ok := demuxedAtFromContext(traceCtx); !ok {
+			return
+		}
+		ctx = traceCtx
+	}
+
+	// Lock
+	ps.m.Lock()
+	defer ps.m.Unlock()
+
+	// Outputs of previous objects have never come out, which may happen when their pts has been modified
+	if len(ps.ss) >= pendingSpansMaxSize {
+		for k, p := range ps.ss {
+			p.end()
+			delete(ps.ss, k)
+		}
+	}
+
+	// Store span
+	ps.ss[pts] = pendingSpan{ctx: ctx, s: s}
+}
+
+// take removes the span of the object that has become an output with this pts, if any, and returns its context so
+// that it can be dispatched with the output
+// The returned func ends the span and must be called once the output has been dispatched
+func (ps *pendingSpans) take(pts int64) (ctx context.Context, end func()) {
+	// Lock
+	ps.m.Lock()
+	defer ps.m.Unlock()
+
+	// Get span
+	p, ok := ps.ss[pts]
+	if !ok {
+		return nil, func() {}
+	}
+	delete(ps.ss, pts)
+	return p.ctx, p.end
+}
+
+// close ends all pending spans
+func (ps *pendingSpans) close() {
+	ps.m.Lock()
+	defer ps.m.Unlock()
+	for k, p := range ps.ss {
+		p.end()
+		delete(ps.ss, k)
+	}
+}
+
+// startSpan starts a span for a traced object, if the workflow is traced
+func startSpan(traceCtx context.Context, n astiencoder.Node, spanName string, pts int64) (context.Context, astiencoder.Span) {
+	// Object is not traced
+	if traceCtx == nil || traceCtx.Value(tracedContextKey{}) == nil {
+		return nil, nil
+	}
+
+	// No tracing
+	tr := astiencoder.TracingFromContext(traceCtx)
+	if tr == nil {
+		return nil, nil
+	}
+
+	// Start span
+	return tr.Start(traceCtx, spanName,
+		astiencoder.SpanAttribute{Key: astiencoder.SpanAttributeNode, Value: n.Metadata().Name},
+		astiencoder.SpanAttribute{Key: SpanAttributePts, Value: pts},
+	)
+}
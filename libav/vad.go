@@ -0,0 +1,260 @@
+package astilibav
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+var countVoiceActivityDetector uint64
+
+// VoiceActivityDetector represents an object capable of detecting when speech starts and ends in audio frames
+// Detection is based on the energy of 10ms windows compared to an adaptive noise floor and on their zero crossing
+// rate
+type VoiceActivityDetector struct {
+	*astiencoder.BaseNode
+	buf              []float64
+	c                *astiencoder.Queue
+	d                *vadDetector
+	eh               *astiencoder.EventHandler
+	statIncomingRate *astikit.CounterRateStat
+	statWorkRatio    *astikit.DurationPercentageStat
+}
+
+// VoiceActivityDetectorOptions represents voice activity detector options
+type VoiceActivityDetectorOptions struct {
+	// Duration of silence after which speech is considered as ended. Default is 500ms
+	EndDuration time.Duration
+	Node        astiencoder.NodeOptions
+	// Number of dB above the noise floor from which a window may contain speech. Default is 10
+	Sensitivity float64
+	// Duration of speech after which speech is considered as started. Default is 100ms
+	StartDuration time.Duration
+	// Confidence above which a window is considered as containing speech. Default is 0.5
+	Threshold float64
+}
+
+// VoiceActivity represents a voice activity
+// It is the payload of the VoiceActivityDetectorSpeechStarted and VoiceActivityDetectorSpeechEnded events
+type VoiceActivity struct {
+	// Average confidence between 0 and 1 of the windows that triggered the event
+	Confidence float64
+	// Position based on frame timestamps
+	Position time.Duration
+}
+
+// NewVoiceActivityDetector creates a new voice activity detector
+func NewVoiceActivityDetector(o VoiceActivityDetectorOptions, eh *astiencoder.EventHandler) (d *VoiceActivityDetector) {
+	// Extend node metadata
+	count := atomic.AddUint64(&countVoiceActivityDetector, uint64(1))
+	o.Node.Metadata = o.Node.Metadata.Extend(fmt.Sprintf("voice_activity_detector_%d", count), fmt.Sprintf("Voice Activity Detector #%d", count), "Detects voice activity", "voice activity detector")
+
+	// Create detector
+	d = &VoiceActivityDetector{
+		c:                astiencoder.NewQueue(o.Node.Queue),
+		d:                newVADDetector(o),
+		eh:               eh,
+		statIncomingRate: astikit.NewCounterRateStat(),
+		statWorkRatio:    astikit.NewDurationPercentageStat(),
+	}
+	d.BaseNode = astiencoder.NewBaseNode(o.Node, astiencoder.NewEventGeneratorNode(d), eh)
+	d.addStats()
+	return
+}
+
+func (d *VoiceActivityDetector) addStats() {
+	// Add incoming rate
+	d.Stater().AddStat(astikit.StatMetadata{
+		Description: "Number of frames coming in per second",
+		Label:       "Incoming rate",
+		Unit:        "fps",
+	}, d.statIncomingRate)
+
+	// Add work ratio
+	d.Stater().AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent doing some actual work",
+		Label:       "Work ratio",
+		Unit:        "%",
+	}, d.statWorkRatio)
+
+	// Add chan stats
+	d.c.AddStats(d.Stater())
+}
+
+// Start starts the detector
+func (d *VoiceActivityDetector) Start(ctx context.Context, t astiencoder.CreateTaskFunc) {
+	d.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		// Make sure to stop the chan properly
+		defer d.c.Stop()
+
+		// Start chan
+		d.c.Start(d.Context())
+	})
+}
+
+// HandleFrame implements the FrameHandler interface
+func (d *VoiceActivityDetector) HandleFrame(p *FrameHandlerPayload) {
+	// Add to queue
+	// The frame is referenced so that HandleFrame doesn't wait for it to be processed
+	// It is dropped if the memory budget is exceeded
+	if p = refFrameHandlerPayload(p, d); p == nil {
+		return
+	}
+	d.c.AddWithRelease(func() {
+		// Handle pause
+		defer d.HandlePause()
+
+		// Increment incoming rate
+		d.statIncomingRate.Add(1)
+
+		// Get samples
+		d.statWorkRatio.Begin()
+		var err error
+		if d.buf, err = frameMonoSamples(p.Frame, d.buf); err != nil {
+			d.statWorkRatio.End()
+			d.eh.Emit(astiencoder.EventError(d, fmt.Errorf("astilibav: getting samples failed: %w", err)))
+			return
+		}
+
+		// Detect
+		es := d.d.process(d.buf, frameSampleRate(p.Frame), time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational)))
+		d.statWorkRatio.End()
+
+		// Emit events
+		for _, e := range es {
+			e.Target = d
+			d.eh.Emit(e)
+		}
+	}, func() { unrefFrameHandlerPayload(p) }, frameBufferSize(p.Frame))
+}
+
+const vadWindowDuration = 10 * time.Millisecond
+
+type vadDetector struct {
+	active     time.Duration
+	confidence float64
+	count      int
+	noiseFloor float64
+	o          VoiceActivityDetectorOptions
+	silence    time.Duration
+	speaking   bool
+	started    bool
+}
+
+func newVADDetector(o VoiceActivityDetectorOptions) *vadDetector {
+	// Default options
+	if o.EndDuration <= 0 {
+		o.EndDuration = 500 * time.Millisecond
+	}
+	if o.Sensitivity <= 0 {
+		o.Sensitivity = 10
+	}
+	if o.StartDuration <= 0 {
+		o.StartDuration = 100 * time.Millisecond
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 0.5
+	}
+	return &vadDetector{o: o}
+}
+
+// process processes samples starting at position and returns the events that should be emitted
+func (d *vadDetector) process(samples []float64, sampleRate int, position time.Duration) (es []astiencoder.Event) {
+	// Invalid sample rate
+	size := int(int64(sampleRate) * int64(vadWindowDuration) / int64(time.Second))
+	if size <= 0 {
+		return
+	}
+
+	// Loop through windows
+	for start := 0; start < len(samples); start += size {
+		// Get window
+		end := start + size
+		if end > len(samples) {
+			end = len(samples)
+		}
+		w := samples[start:end]
+		wd := time.Duration(len(w)) * time.Second / time.Duration(sampleRate)
+		wp := position + time.Duration(start)*time.Second/time.Duration(sampleRate)
+
+		// Get confidence
+		c := d.windowConfidence(w)
+
+		// Update state
+		if !d.speaking {
+			if c >= d.o.Threshold {
+				d.active += wd
+				d.confidence += c
+				d.count++
+				if d.active >= d.o.StartDuration {
+					es = append(es, astiencoder.Event{
+						Name:    VoiceActivityDetectorSpeechStarted,
+						Payload: VoiceActivity{Confidence: d.confidence / float64(d.count), Position: wp + wd - d.active},
+					})
+					d.speaking, d.silence, d.confidence, d.count = true, 0, 0, 0
+				}
+			} else {
+				d.active, d.confidence, d.count = 0, 0, 0
+			}
+		} else {
+			if c < d.o.Threshold {
+				d.silence += wd
+				d.confidence += 1 - c
+				d.count++
+				if d.silence >= d.o.EndDuration {
+					es = append(es, astiencoder.Event{
+						Name:    VoiceActivityDetectorSpeechEnded,
+						Payload: VoiceActivity{Confidence: d.confidence / float64(d.count), Position: wp + wd - d.silence},
+					})
+					d.speaking, d.active, d.confidence, d.count = false, 0, 0, 0
+				}
+			} else {
+				d.silence, d.confidence, d.count = 0, 0, 0
+			}
+		}
+	}
+	return
+}
+
+func (d *vadDetector) windowConfidence(w []float64) float64 {
+	// Get energy and zero crossing rate
+	var sum float64
+	var crossings int
+	for idx, v := range w {
+		sum += v * v
+		if idx > 0 && (v >= 0) != (w[idx-1] >= 0) {
+			crossings++
+		}
+	}
+	db := 10 * math.Log10(sum/float64(len(w))+1e-12)
+	zcr := float64(crossings) / float64(len(w))
+
+	// Update noise floor
+	// It follows decreases immediately and increases slowly so that it tracks the background noise
+	if !d.started || db < d.noiseFloor {
+		d.noiseFloor = db
+		d.started = true
+	} else {
+		d.noiseFloor += (db - d.noiseFloor) * 0.002
+	}
+
+	// Window is silent
+	if db < -60 {
+		return 0
+	}
+
+	// Energy above the noise floor
+	c := 1 / (1 + math.Exp(-(db-d.noiseFloor-d.o.Sensitivity)/3))
+
+	// Speech has a lower zero crossing rate than most noises
+	if zcr > 0.25 {
+		c *= 0.5
+	}
+	return c
+}
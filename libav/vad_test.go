@@ -0,0 +1,35 @@
+package astilibav
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVADDetector(t *testing.T) {
+	d := newVADDetector(VoiceActivityDetectorOptions{})
+	r := rand.New(rand.NewSource(1))
+	var es []astiencoder.Event
+	for i := 0; i < 300; i++ {
+		s := make([]float64, 480)
+		for j := range s {
+			if i >= 100 && i < 200 {
+				s[j] = 0.3 * math.Sin(2*math.Pi*200*float64(i*480+j)/48000)
+			} else {
+				s[j] = 0.001 * (2*r.Float64() - 1)
+			}
+		}
+		es = append(es, d.process(s, 48000, time.Duration(i)*10*time.Millisecond)...)
+	}
+	assert.Equal(t, 2, len(es))
+	assert.Equal(t, VoiceActivityDetectorSpeechStarted, es[0].Name)
+	assert.Equal(t, time.Second, es[0].Payload.(VoiceActivity).Position)
+	assert.True(t, es[0].Payload.(VoiceActivity).Confidence > 0.9)
+	assert.Equal(t, VoiceActivityDetectorSpeechEnded, es[1].Name)
+	assert.Equal(t, 2*time.Second, es[1].Payload.(VoiceActivity).Position)
+	assert.True(t, es[1].Payload.(VoiceActivity).Confidence > 0.9)
+}
@@ -0,0 +1,171 @@
+package astilibav
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avutil"
+)
+
+// Video transition types
+const (
+	VideoTransitionTypeCut       = "cut"
+	VideoTransitionTypeCrossfade = "crossfade"
+	// From left to right
+	VideoTransitionTypeWipe = "wipe"
+)
+
+// VideoTransition represents a video transition
+type VideoTransition struct {
+	// Ignored for cuts
+	Duration time.Duration
+	Type     string
+}
+
+// VideoTransitionFilterer represents an object capable of mixing two video inputs where only one of them is visible
+// at a time, and transitioning from one to the other, which is the visual counterpart of the audio crossfade filterer
+// Both inputs must be live and have the same size and pixel format. Starting a transition while another one is in
+// progress completes the previous one immediately
+type VideoTransitionFilterer struct {
+	*Filterer
+	a        astiencoder.Node
+	active   string
+	b        astiencoder.Node
+	m        *sync.Mutex
+	position time.Duration
+}
+
+// VideoTransitionFiltererOptions represents video transition filterer options
+type VideoTransitionFiltererOptions struct {
+	// Visible input at start
+	A         astiencoder.Node
+	B         astiencoder.Node
+	Node      astiencoder.NodeOptions
+	OutputCtx Context
+	Restamper FrameRestamper
+}
+
+// NewVideoTransitionFilterer creates a new video transition filterer
+func NewVideoTransitionFilterer(o VideoTransitionFiltererOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (f *VideoTransitionFilterer, err error) {
+	// No inputs
+	if o.A == nil || o.B == nil {
+		err = errors.New("astilibav: a and b are mandatory")
+		return
+	}
+
+	// Create filterer
+	f = &VideoTransitionFilterer{
+		a:      o.A,
+		active: "A",
+		b:      o.B,
+		m:      &sync.Mutex{},
+	}
+	if f.Filterer, err = NewFilterer(FiltererOptions{
+		Content: videoTransitionContent("A"),
+		Inputs: map[string]astiencoder.Node{
+			"a": o.A,
+			"b": o.B,
+		},
+		Node:      o.Node,
+		OutputCtx: o.OutputCtx,
+		Restamper: o.Restamper,
+	}, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating filterer failed: %w", err)
+		return
+	}
+	return
+}
+
+// Output frames are timestamped based on frames of the first input
+func videoTransitionContent(expr string) string {
+	return "[a][b]blend=all_expr=" + escapeFilterOption(expr)
+}
+
+// HandleFrame implements the FrameHandler interface
+func (f *VideoTransitionFilterer) HandleFrame(p *FrameHandlerPayload) {
+	// Store position
+	if p.Node == f.a {
+		f.m.Lock()
+		f.position = time.Duration(avutil.AvRescaleQ(p.Frame.Pts(), p.Descriptor.TimeBase(), nanosecondRational))
+		f.m.Unlock()
+	}
+
+	// Handle frame
+	f.Filterer.HandleFrame(p)
+}
+
+// Transition transitions to the input, starting with the last frame received
+func (f *VideoTransitionFilterer) Transition(to astiencoder.Node, t VideoTransition) (err error) {
+	// Get input name
+	var n string
+	switch to {
+	case f.a:
+		n = "A"
+	case f.b:
+		n = "B"
+	default:
+		err = errors.New("astilibav: input is neither a nor b")
+		return
+	}
+
+	// Lock
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	// Input is already visible
+	if n == f.active {
+		return
+	}
+
+	// Get expression
+	var expr string
+	if expr, err = videoTransitionExpr(f.active, n, f.position, t); err != nil {
+		err = fmt.Errorf("astilibav: getting expression failed: %w", err)
+		return
+	}
+
+	// Set content
+	if err = f.SetContent(videoTransitionContent(expr)); err != nil {
+		err = fmt.Errorf("astilibav: setting content failed: %w", err)
+		return
+	}
+
+	// Update active input
+	f.active = n
+	return
+}
+
+// TransitionOnEvent transitions to the input every time the event is emitted by the target
+func (f *VideoTransitionFilterer) TransitionOnEvent(target interface{}, eventName string, to astiencoder.Node, t VideoTransition) {
+	f.eh.Add(target, eventName, func(e astiencoder.Event) bool {
+		if err := f.Transition(to, t); err != nil {
+			f.eh.Emit(astiencoder.EventError(f, fmt.Errorf("astilibav: transitioning on event %s failed: %w", eventName, err)))
+		}
+		return false
+	})
+}
+
+func videoTransitionExpr(from, to string, start time.Duration, t VideoTransition) (string, error) {
+	// Cut
+	s := formatFilterFloat(start.Seconds())
+	if t.Type == VideoTransitionTypeCut || t.Duration <= 0 {
+		return fmt.Sprintf("if(gte(T,%s),%s,%s)", s, to, from), nil
+	}
+
+	// Progress
+	p := fmt.Sprintf("clip((T-%s)/%s,0,1)", s, formatFilterFloat(t.Duration.Seconds()))
+
+	// Switch on type
+	switch t.Type {
+	case VideoTransitionTypeCrossfade:
+		return fmt.Sprintf("%s*(1-%s)+%s*%s", from, p, to, p), nil
+	case VideoTransitionTypeWipe:
+		return fmt.Sprintf("if(lt(X,W*%s),%s,%s)", p, to, from), nil
+	default:
+		return "", fmt.Errorf("astilibav: invalid transition type %s", t.Type)
+	}
+}
@@ -0,0 +1,23 @@
+package astilibav
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVideoTransitionExpr(t *testing.T) {
+	e, err := videoTransitionExpr("A", "B", 10*time.Second, VideoTransition{Type: VideoTransitionTypeCut})
+	assert.NoError(t, err)
+	assert.Equal(t, "if(gte(T,10),B,A)", e)
+	e, err = videoTransitionExpr("B", "A", 10*time.Second, VideoTransition{Duration: time.Second, Type: VideoTransitionTypeCrossfade})
+	assert.NoError(t, err)
+	assert.Equal(t, "B*(1-clip((T-10)/1,0,1))+A*clip((T-10)/1,0,1)", e)
+	e, err = videoTransitionExpr("A", "B", 0, VideoTransition{Duration: 500 * time.Millisecond, Type: VideoTransitionTypeWipe})
+	assert.NoError(t, err)
+	assert.Equal(t, "if(lt(X,W*clip((T-0)/0.5,0,1)),B,A)", e)
+	assert.Equal(t, `[a][b]blend=all_expr=if(gte(T\,10)\,B\,A)`, videoTransitionContent("if(gte(T,10),B,A)"))
+	_, err = videoTransitionExpr("A", "B", 0, VideoTransition{Duration: time.Second})
+	assert.Error(t, err)
+}
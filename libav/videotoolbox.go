@@ -0,0 +1,151 @@
+package astilibav
+
+/*
+#cgo pkg-config: libavutil
+#include <libavutil/pixfmt.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asticode/go-astiencoder"
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/goav/avcodec"
+	"github.com/asticode/goav/avutil"
+)
+
+var (
+	videoToolboxNV12        = avutil.PixelFormat(C.AV_PIX_FMT_NV12)
+	videoToolboxP010        = avutil.PixelFormat(C.AV_PIX_FMT_P010LE)
+	videoToolboxPixelFormat = avutil.PixelFormat(C.AV_PIX_FMT_VIDEOTOOLBOX)
+	videoToolboxYUV420P10   = avutil.PixelFormat(C.AV_PIX_FMT_YUV420P10LE)
+)
+
+// videoToolboxDecoderNames lists the codecs VideoToolbox can accelerate
+var videoToolboxDecoderNames = map[string]bool{
+	"h263":       true,
+	"h264":       true,
+	"hevc":       true,
+	"mpeg1video": true,
+	"mpeg2video": true,
+	"mpeg4":      true,
+}
+
+// VideoToolboxDecoderOptions represents VideoToolbox decoder options
+type VideoToolboxDecoderOptions struct {
+	// HardwareDevice, DownloadHardwareFrames and OutputCtx.PixelFormat are set by the decoder
+	Decoder DecoderOptions
+	// If true, an error is returned when VideoToolbox can't be used instead of falling back to software decoding
+	DisableFallback bool
+	// If true, frames are dispatched in VideoToolbox memory which only VideoToolbox encoders can handle. Otherwise
+	// they are copied to system memory
+	KeepHardwareFrames bool
+}
+
+// NewVideoToolboxDecoder creates a new decoder using VideoToolbox on macOS and falls back to software decoding when
+// the codec is not supported or when VideoToolbox is not available
+func NewVideoToolboxDecoder(o VideoToolboxDecoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (d *Decoder, err error) {
+	// Get codec name
+	name := avcodec.AvcodecGetName(o.Decoder.CodecParams.CodecId())
+
+	// Create hardware decoder
+	var reason string
+	if !videoToolboxDecoderNames[name] {
+		reason = fmt.Sprintf("codec id %+v is not supported", o.Decoder.CodecParams.CodecId())
+	} else if dc, errDevice := NewHardwareDeviceContext("videotoolbox", "", c); errDevice != nil {
+		reason = errDevice.Error()
+	} else {
+		// Update options
+		ho := o.Decoder
+		ho.DownloadHardwareFrames = !o.KeepHardwareFrames
+		ho.HardwareDevice = dc
+		if o.KeepHardwareFrames {
+			ho.OutputCtx.PixelFormat = videoToolboxPixelFormat
+		} else if ho.OutputCtx.PixelFormat == videoToolboxYUV420P10 {
+			ho.OutputCtx.PixelFormat = videoToolboxP010
+		} else {
+			ho.OutputCtx.PixelFormat = videoToolboxNV12
+		}
+
+		// Create decoder
+		if d, err = NewDecoder(ho, eh, c); err == nil {
+			return
+		}
+		reason = err.Error()
+	}
+
+	// No fallback
+	if o.DisableFallback {
+		err = fmt.Errorf("astilibav: creating videotoolbox decoder failed: %s", reason)
+		return
+	}
+
+	// Create software decoder
+	if d, err = NewDecoder(o.Decoder, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating fallback decoder failed: %w", err)
+		return
+	}
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name: HardwareFallbackTriggered,
+		Payload: HardwareFallback{
+			From:   name,
+			Reason: reason,
+		},
+		Target: d,
+	})
+	return
+}
+
+// VideoToolboxEncoderOptions represents VideoToolbox encoder options
+type VideoToolboxEncoderOptions struct {
+	// Ctx.CodecName must be the name of a VideoToolbox encoder (e.g. "h264_videotoolbox" or "hevc_videotoolbox")
+	Encoder EncoderOptions
+	// Name of the software encoder used when VideoToolbox can't be used. If empty, an error is returned instead
+	// Fallback is not possible when frames are in VideoToolbox memory
+	FallbackCodecName string
+}
+
+// NewVideoToolboxEncoder creates a new encoder using VideoToolbox on macOS and falls back to a software encoder when
+// VideoToolbox fails to open
+func NewVideoToolboxEncoder(o VideoToolboxEncoderOptions, eh *astiencoder.EventHandler, c *astikit.Closer) (e *Encoder, err error) {
+	// Invalid codec name
+	if !strings.HasSuffix(o.Encoder.Ctx.CodecName, "_videotoolbox") {
+		err = fmt.Errorf("astilibav: %s is not a videotoolbox encoder", o.Encoder.Ctx.CodecName)
+		return
+	}
+
+	// Create hardware encoder
+	if e, err = NewEncoder(o.Encoder, eh, c); err == nil {
+		return
+	}
+	reason := err.Error()
+
+	// No fallback
+	if o.FallbackCodecName == "" || o.Encoder.Ctx.PixelFormat == videoToolboxPixelFormat {
+		err = fmt.Errorf("astilibav: creating videotoolbox encoder failed: %s", reason)
+		return
+	}
+
+	// Create software encoder
+	so := o.Encoder
+	so.Ctx.CodecName = o.FallbackCodecName
+	if e, err = NewEncoder(so, eh, c); err != nil {
+		err = fmt.Errorf("astilibav: creating fallback encoder failed: %w", err)
+		return
+	}
+
+	// Emit
+	eh.Emit(astiencoder.Event{
+		Name: HardwareFallbackTriggered,
+		Payload: HardwareFallback{
+			From:   o.Encoder.Ctx.CodecName,
+			Reason: reason,
+			To:     o.FallbackCodecName,
+		},
+		Target: e,
+	})
+	return
+}
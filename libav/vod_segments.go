@@ -0,0 +1,102 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// VODSegment represents a range of a VOD input that can be encoded independently from the other ranges
+type VODSegment struct {
+	Duration time.Duration
+	Index    int
+	Start    time.Duration
+}
+
+// SplitVODSegments splits a VOD input of the provided duration into segments of the provided duration. The last
+// segment may be shorter
+// Segment boundaries should match keyframes of the input so that segments can be concatenated seamlessly
+func SplitVODSegments(duration, segmentDuration time.Duration) (ss []VODSegment) {
+	if duration <= 0 || segmentDuration <= 0 {
+		return
+	}
+	for start := time.Duration(0); start < duration; start += segmentDuration {
+		d := segmentDuration
+		if start+d > duration {
+			d = duration - start
+		}
+		ss = append(ss, VODSegment{
+			Duration: d,
+			Index:    len(ss),
+			Start:    start,
+		})
+	}
+	return
+}
+
+// VODSegmentEncodeFunc represents a function encoding a VOD segment, usually by running a workflow reading the
+// segment's range of the input and writing it to its own output
+type VODSegmentEncodeFunc func(ctx context.Context, s VODSegment) error
+
+// EncodeVODSegments encodes segments in parallel on a pool of workers. If workers <= 0, there are as many workers as
+// there are cores
+// If a segment fails, the context of the other segments is cancelled and the first error is returned
+func EncodeVODSegments(ctx context.Context, ss []VODSegment, workers int, fn VODSegmentEncodeFunc) (err error) {
+	// No function
+	if fn == nil {
+		err = errors.New("astilibav: no encode func")
+		return
+	}
+
+	// Default workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Create context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Feed segments
+	ch := make(chan VODSegment)
+	go func() {
+		defer close(ch)
+		for _, s := range ss {
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Start workers
+	var o sync.Once
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range ch {
+				if errFn := fn(ctx, s); errFn != nil {
+					o.Do(func() {
+						err = fmt.Errorf("astilibav: encoding segment %d failed: %w", s.Index, errFn)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	// Wait
+	wg.Wait()
+
+	// Context error
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package astilibav
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitVODSegments(t *testing.T) {
+	assert.Nil(t, SplitVODSegments(0, time.Second))
+	assert.Equal(t, []VODSegment{
+		{Duration: 4 * time.Second, Index: 0, Start: 0},
+		{Duration: 4 * time.Second, Index: 1, Start: 4 * time.Second},
+		{Duration: 2 * time.Second, Index: 2, Start: 8 * time.Second},
+	}, SplitVODSegments(10*time.Second, 4*time.Second))
+}
+
+func TestEncodeVODSegments(t *testing.T) {
+	ss := SplitVODSegments(10*time.Second, time.Second)
+
+	// Success
+	m := &sync.Mutex{}
+	var is []int
+	err := EncodeVODSegments(context.Background(), ss, 3, func(ctx context.Context, s VODSegment) error {
+		m.Lock()
+		defer m.Unlock()
+		is = append(is, s.Index)
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Ints(is)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, is)
+
+	// Failure
+	e := errors.New("test")
+	err = EncodeVODSegments(context.Background(), ss, 2, func(ctx context.Context, s VODSegment) error {
+		if s.Index == 1 {
+			return e
+		}
+		<-ctx.Done()
+		return nil
+	})
+	assert.True(t, errors.Is(err, e))
+}
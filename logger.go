@@ -0,0 +1,139 @@
+package astiencoder
+
+import (
+	"context"
+	"fmt"
+	runtimepprof "runtime/pprof"
+	"strings"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Log field keys
+const (
+	LogFieldError    = "error"
+	LogFieldLabel    = "label"
+	LogFieldNode     = "node"
+	LogFieldWorkflow = "workflow"
+)
+
+type loggerContextKey struct{}
+
+// Logger represents a leveled and structured logger
+// Adapting zap, zerolog or slog only takes a few lines
+type Logger interface {
+	Debug(msg string, fields ...LogField)
+	Error(msg string, fields ...LogField)
+	Info(msg string, fields ...LogField)
+	Warn(msg string, fields ...LogField)
+}
+
+// LogField represents a log field
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, fields ...LogField) {}
+func (nopLogger) Error(msg string, fields ...LogField) {}
+func (nopLogger) Info(msg string, fields ...LogField)  {}
+func (nopLogger) Warn(msg string, fields ...LogField)  {}
+
+type stdLogger struct {
+	l astikit.StdLogger
+}
+
+// AdaptStdLogger adapts a std logger so that it can be used as a logger
+// Fields are appended to the message as key=value pairs
+func AdaptStdLogger(l astikit.StdLogger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+	return stdLogger{l: l}
+}
+
+func (l stdLogger) Debug(msg string, fields ...LogField) { l.print(msg, fields) }
+func (l stdLogger) Error(msg string, fields ...LogField) { l.print(msg, fields) }
+func (l stdLogger) Info(msg string, fields ...LogField)  { l.print(msg, fields) }
+func (l stdLogger) Warn(msg string, fields ...LogField)  { l.print(msg, fields) }
+
+func (l stdLogger) print(msg string, fields []LogField) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	l.l.Print(b.String())
+}
+
+type fieldsLogger struct {
+	fs []LogField
+	l  Logger
+}
+
+// LoggerWithFields returns a logger adding fields to all logs
+func LoggerWithFields(l Logger, fields ...LogField) Logger {
+	// Merge fields
+	if v, ok := l.(fieldsLogger); ok {
+		return fieldsLogger{
+			fs: append(append([]LogField{}, v.fs...), fields...),
+			l:  v.l,
+		}
+	}
+	return fieldsLogger{
+		fs: fields,
+		l:  l,
+	}
+}
+
+func (l fieldsLogger) Debug(msg string, fields ...LogField) { l.l.Debug(msg, l.fields(fields)...) }
+func (l fieldsLogger) Error(msg string, fields ...LogField) { l.l.Error(msg, l.fields(fields)...) }
+func (l fieldsLogger) Info(msg string, fields ...LogField)  { l.l.Info(msg, l.fields(fields)...) }
+func (l fieldsLogger) Warn(msg string, fields ...LogField)  { l.l.Warn(msg, l.fields(fields)...) }
+
+func (l fieldsLogger) fields(fields []LogField) []LogField {
+	return append(append([]LogField{}, l.fs...), fields...)
+}
+
+// logger returns a nop logger if l is nil
+func logger(l Logger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+	return l
+}
+
+type loggerStdLogger struct {
+	l Logger
+}
+
+// Print implements the astikit.StdLogger interface
+func (l loggerStdLogger) Print(v ...interface{}) { l.l.Info(fmt.Sprint(v...)) }
+
+// Printf implements the astikit.StdLogger interface
+func (l loggerStdLogger) Printf(format string, v ...interface{}) { l.l.Info(fmt.Sprintf(format, v...)) }
+
+// SetLogger sets the logger of the workflow, which is also the logger of its nodes unless they have their own
+// It must be called before the workflow is started
+func (w *Workflow) SetLogger(l Logger) {
+	w.l = l
+}
+
+// LoggerFromContext returns the logger of the workflow the context belongs to, if any
+func LoggerFromContext(ctx context.Context) Logger {
+	if ctx == nil {
+		return nil
+	}
+	l, _ := ctx.Value(loggerContextKey{}).(Logger)
+	return l
+}
+
+// nodeWorkflowName returns the name of the workflow a started node belongs to
+func nodeWorkflowName(n Node) (name string) {
+	if v, ok := n.(interface{ Context() context.Context }); ok && v.Context() != nil {
+		name, _ = runtimepprof.Label(v.Context(), ProfileLabelWorkflow)
+	}
+	return
+}
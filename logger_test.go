@@ -0,0 +1,86 @@
+package astiencoder
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedLogger struct {
+	logs []mockedLog
+	m    *sync.Mutex
+}
+
+type mockedLog struct {
+	fields []LogField
+	level  string
+	msg    string
+}
+
+func newMockedLogger() *mockedLogger {
+	return &mockedLogger{m: &sync.Mutex{}}
+}
+
+func (l *mockedLogger) log(level, msg string, fields []LogField) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.logs = append(l.logs, mockedLog{fields: fields, level: level, msg: msg})
+}
+
+func (l *mockedLogger) Debug(msg string, fields ...LogField) { l.log("debug", msg, fields) }
+func (l *mockedLogger) Error(msg string, fields ...LogField) { l.log("error", msg, fields) }
+func (l *mockedLogger) Info(msg string, fields ...LogField)  { l.log("info", msg, fields) }
+func (l *mockedLogger) Warn(msg string, fields ...LogField)  { l.log("warn", msg, fields) }
+
+func TestLogger(t *testing.T) {
+	// Std logger
+	b := &bytes.Buffer{}
+	l := LoggerWithFields(LoggerWithFields(AdaptStdLogger(log.New(b, "", 0)), LogField{Key: "k1", Value: 1}), LogField{Key: "k2", Value: "v2"})
+	l.Warn("msg", LogField{Key: LogFieldError, Value: errors.New("test")})
+	assert.Equal(t, "msg k1=1 k2=v2 error=test\n", b.String())
+	AdaptStdLogger(nil).Info("msg")
+
+	// Node logger
+	eh := NewEventHandler()
+	ml := newMockedLogger()
+	n := newMockedStatsNode("n", eh)
+	n.Logger().Info("msg")
+	n.o.Logger = ml
+	n.BaseNode = NewBaseNode(n.o, NewEventGeneratorNode(n), eh)
+	n.Logger().Info("msg")
+	assert.Equal(t, []mockedLog{{fields: []LogField{{Key: LogFieldNode, Value: "n"}}, level: "info", msg: "msg"}}, ml.logs)
+
+	// Workflow logger
+	ml = newMockedLogger()
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	w := NewWorkflow(wk.Context(), "w", eh, wk.NewTask, astikit.NewCloser())
+	w.SetLogger(ml)
+	tn := &mockedTracedNode{
+		mockedStatsNode: newMockedStatsNode("n", eh),
+		started:         make(chan struct{}),
+	}
+	w.AddChild(tn)
+	w.Start()
+	<-tn.started
+	tn.Logger().Debug("msg")
+	w.Stop()
+	wk.Stop()
+	wk.Wait()
+	assert.Equal(t, []mockedLog{{fields: []LogField{{Key: LogFieldWorkflow, Value: "w"}, {Key: LogFieldNode, Value: "n"}}, level: "debug", msg: "msg"}}, ml.logs)
+
+	// Event handler adapter
+	ml = newMockedLogger()
+	eh = NewEventHandler()
+	StructuredLoggerEventHandlerAdapter(ml, eh)
+	eh.Emit(EventError(w, errors.New("test")))
+	eh.Emit(Event{Name: EventNameNodeStarted, Target: tn})
+	assert.Equal(t, []mockedLog{
+		{fields: []LogField{{Key: LogFieldError, Value: errors.New("test")}, {Key: LogFieldWorkflow, Value: "w"}}, level: "error", msg: "astiencoder: an error occurred"},
+		{fields: []LogField{{Key: LogFieldNode, Value: "n"}, {Key: LogFieldLabel, Value: ""}, {Key: LogFieldWorkflow, Value: "w"}}, level: "debug", msg: "astiencoder: node is started"},
+	}, ml.logs)
+}
@@ -0,0 +1,233 @@
+package astiencoder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Memory budget policies
+const (
+	// Incoming objects are dropped until the usage is back under the low-water mark
+	MemoryBudgetPolicyDrop = "drop"
+	// Workflow inputs are paused until the usage is back under the low-water mark
+	MemoryBudgetPolicyPause = "pause"
+	// The workflow is stopped
+	MemoryBudgetPolicyStop = "stop"
+)
+
+type memoryAccountantContextKey struct{}
+
+// MemoryBudgetOptions represents memory budget options
+type MemoryBudgetOptions struct {
+	// Max number of bytes buffered by the workflow's nodes
+	Budget int64
+	// Once the budget has been exceeded, it's only restored when the number of bytes buffered is back under this
+	// value so that the policy doesn't kick in and out with every object. Default is 90% of the budget
+	LowWaterMark int64
+	// What happens when the budget is exceeded. Default is MemoryBudgetPolicyDrop
+	Policy string
+}
+
+// MemoryBudget represents the payload of memory budget events
+type MemoryBudget struct {
+	Budget int64
+	Policy string
+	Usage  int64
+}
+
+// MemoryAccountant keeps track of the memory buffered by nodes and enforces a budget
+// Nodes register the size of the objects they buffer with Acquire and unregister it with Release once they're done
+type MemoryAccountant struct {
+	exceeded  bool
+	m         *sync.Mutex
+	o         MemoryBudgetOptions
+	onExceed  func()
+	onRestore func()
+	statUsage *memoryUsageStat
+	usage     int64
+}
+
+func newMemoryAccountant(o MemoryBudgetOptions) *MemoryAccountant {
+	// Default options
+	if o.Policy == "" {
+		o.Policy = MemoryBudgetPolicyDrop
+	}
+	if o.LowWaterMark <= 0 || o.LowWaterMark > o.Budget {
+		o.LowWaterMark = o.Budget * 9 / 10
+	}
+
+	// Create accountant
+	return &MemoryAccountant{
+		m:         &sync.Mutex{},
+		o:         o,
+		statUsage: &memoryUsageStat{},
+	}
+}
+
+// MemoryAccountantFromContext returns the memory accountant of the workflow the context belongs to, if any
+func MemoryAccountantFromContext(ctx context.Context) *MemoryAccountant {
+	if ctx == nil {
+		return nil
+	}
+	a, _ := ctx.Value(memoryAccountantContextKey{}).(*MemoryAccountant)
+	return a
+}
+
+// Acquire registers size bytes and returns false if the object should be dropped, in which case nothing is
+// registered
+func (a *MemoryAccountant) Acquire(size int) (ok bool) {
+	// Lock
+	a.m.Lock()
+
+	// Budget is exceeded or has not been restored yet
+	var exceeded bool
+	if a.o.Budget > 0 && (a.exceeded || a.usage+int64(size) > a.o.Budget) {
+		// Update status
+		if !a.exceeded {
+			a.exceeded = true
+			exceeded = true
+		}
+
+		// Drop
+		if a.o.Policy != MemoryBudgetPolicyPause {
+			a.m.Unlock()
+			a.exceed(exceeded)
+			return false
+		}
+	}
+
+	// Update usage
+	a.usage += int64(size)
+	a.statUsage.set(a.usage)
+	a.m.Unlock()
+
+	// Exceed
+	a.exceed(exceeded)
+	return true
+}
+
+func (a *MemoryAccountant) exceed(exceeded bool) {
+	if exceeded && a.onExceed != nil {
+		a.onExceed()
+	}
+}
+
+// Release unregisters size bytes
+func (a *MemoryAccountant) Release(size int) {
+	// Lock
+	a.m.Lock()
+
+	// Update usage
+	a.usage -= int64(size)
+	a.statUsage.set(a.usage)
+
+	// Usage is back under the low-water mark
+	var restored bool
+	if a.exceeded && a.usage <= a.o.LowWaterMark {
+		a.exceeded = false
+		restored = true
+	}
+	a.m.Unlock()
+
+	// Restore
+	if restored && a.onRestore != nil {
+		a.onRestore()
+	}
+}
+
+// Usage returns the number of bytes currently registered
+func (a *MemoryAccountant) Usage() int64 {
+	a.m.Lock()
+	defer a.m.Unlock()
+	return a.usage
+}
+
+func (a *MemoryAccountant) payload() MemoryBudget {
+	return MemoryBudget{
+		Budget: a.o.Budget,
+		Policy: a.o.Policy,
+		Usage:  a.Usage(),
+	}
+}
+
+type memoryUsageStat struct {
+	v int64
+}
+
+func (s *memoryUsageStat) set(v int64) {
+	atomic.StoreInt64(&s.v, v)
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *memoryUsageStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *memoryUsageStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *memoryUsageStat) Value(_ time.Duration) interface{} {
+	return float64(atomic.LoadInt64(&s.v)) / 1e6
+}
+
+// SetMemoryBudget makes the workflow enforce a memory budget on the objects buffered by its nodes
+// It must be called before the workflow is started
+func (w *Workflow) SetMemoryBudget(o MemoryBudgetOptions) *MemoryAccountant {
+	// Create accountant
+	a := newMemoryAccountant(o)
+
+	// Handle exceeded budget
+	a.onExceed = func() {
+		// Emit
+		w.e.Emit(Event{Name: EventNameWorkflowMemoryBudgetExceeded, Payload: a.payload(), Target: w})
+
+		// Switch on policy
+		switch a.o.Policy {
+		case MemoryBudgetPolicyPause:
+			for _, n := range w.inputs() {
+				n.Pause()
+			}
+		case MemoryBudgetPolicyStop:
+			w.Stop()
+		}
+	}
+
+	// Handle restored budget
+	a.onRestore = func() {
+		// Emit
+		w.e.Emit(Event{Name: EventNameWorkflowMemoryBudgetRestored, Payload: a.payload(), Target: w})
+
+		// Continue inputs
+		if a.o.Policy == MemoryBudgetPolicyPause {
+			for _, n := range w.inputs() {
+				n.Continue()
+			}
+		}
+	}
+
+	// Add stat
+	w.bn.Stater().AddStat(astikit.StatMetadata{
+		Description: "Memory buffered by the workflow's nodes",
+		Label:       "Memory usage",
+		Unit:        "MB",
+	}, a.statUsage)
+
+	// Store accountant
+	w.ma = a
+	return a
+}
+
+// inputs returns the nodes of the workflow without parents, wherever they're attached
+// Other nodes are not paused so that they keep processing, and releasing, what inputs have already produced, otherwise
+// the usage would never be back under the budget
+func (w *Workflow) inputs() (ns []Node) {
+	for _, n := range w.nodes() {
+		if len(n.Parents()) == 0 {
+			ns = append(ns, n)
+		}
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryAccountant(t *testing.T) {
+	// Drop
+	a := newMemoryAccountant(MemoryBudgetOptions{Budget: 10})
+	var exceeded, restored int
+	a.onExceed = func() { exceeded++ }
+	a.onRestore = func() { restored++ }
+	assert.True(t, a.Acquire(6))
+	assert.False(t, a.Acquire(6))
+	assert.False(t, a.Acquire(6))
+	assert.Equal(t, int64(6), a.Usage())
+	assert.Equal(t, 1, exceeded)
+	assert.False(t, a.Acquire(1))
+	a.Release(6)
+	assert.Equal(t, int64(0), a.Usage())
+	assert.Equal(t, 1, restored)
+	assert.True(t, a.Acquire(1))
+
+	// Pause
+	a = newMemoryAccountant(MemoryBudgetOptions{Budget: 10, Policy: MemoryBudgetPolicyPause})
+	exceeded, restored = 0, 0
+	a.onExceed = func() { exceeded++ }
+	a.onRestore = func() { restored++ }
+	assert.True(t, a.Acquire(6))
+	assert.True(t, a.Acquire(6))
+	assert.Equal(t, int64(12), a.Usage())
+	assert.Equal(t, 1, exceeded)
+	a.Release(2)
+	assert.Equal(t, 0, restored)
+	a.Release(1)
+	assert.Equal(t, 1, restored)
+
+	// Low-water mark
+	a = newMemoryAccountant(MemoryBudgetOptions{Budget: 10, LowWaterMark: 5, Policy: MemoryBudgetPolicyPause})
+	exceeded, restored = 0, 0
+	a.onExceed = func() { exceeded++ }
+	a.onRestore = func() { restored++ }
+	assert.True(t, a.Acquire(12))
+	a.Release(6)
+	assert.Equal(t, 0, restored)
+	a.Release(1)
+	assert.Equal(t, 1, restored)
+	assert.Equal(t, 1, exceeded)
+
+	// Context
+	assert.Nil(t, MemoryAccountantFromContext(context.Background()))
+	assert.Equal(t, a, MemoryAccountantFromContext(context.WithValue(context.Background(), memoryAccountantContextKey{}, a)))
+}
+
+type mockedPauseNode struct {
+	*mockedStatsNode
+	paused bool
+}
+
+func (n *mockedPauseNode) Pause() { n.paused = true }
+
+func (n *mockedPauseNode) Continue() { n.paused = false }
+
+func TestWorkflowMemoryBudgetPause(t *testing.T) {
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	i := &mockedPauseNode{mockedStatsNode: newMockedStatsNode("i", eh)}
+	o := &mockedPauseNode{mockedStatsNode: newMockedStatsNode("o", eh)}
+	w.AddChild(i)
+	w.AddChild(o)
+	ConnectNodes(i, o)
+	a := w.SetMemoryBudget(MemoryBudgetOptions{Budget: 10, Policy: MemoryBudgetPolicyPause})
+
+	// Only inputs are paused so that other nodes keep releasing memory
+	a.Acquire(12)
+	assert.True(t, i.paused)
+	assert.False(t, o.paused)
+	a.Release(12)
+	assert.False(t, i.paused)
+}
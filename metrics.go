@@ -0,0 +1,244 @@
+package astiencoder
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Metrics represents an object capable of publishing workflows and nodes statuses, stats, errors and restarts as
+// Prometheus metrics
+// Metrics are labeled with their workflow and node names. Stats are published as gauges whose name is derived from
+// the stat label (e.g. "Queue length" becomes astiencoder_node_queue_length)
+// Series of a workflow and of its nodes are dropped once the workflow is stopped so that they don't pile up in a
+// long-running server creating and deleting workflows
+type Metrics struct {
+	m  *sync.Mutex
+	ns map[metricsNodeKey]*metricsObject
+	ws map[string]*metricsObject
+}
+
+type metricsNodeKey struct {
+	node     string
+	workflow string
+}
+
+type metricsObject struct {
+	errors uint64
+	starts uint64
+	stats  []EventStat
+	status string
+}
+
+// NewMetrics creates new metrics fed by the events of the event handler
+func NewMetrics(eh *EventHandler) (m *Metrics) {
+	// Create metrics
+	m = &Metrics{
+		m:  &sync.Mutex{},
+		ns: make(map[metricsNodeKey]*metricsObject),
+		ws: make(map[string]*metricsObject),
+	}
+
+	// Handle events
+	eh.AddForAll(func(e Event) bool {
+		m.handleEvent(e)
+		return false
+	})
+	return
+}
+
+func (m *Metrics) handleEvent(e Event) {
+	// Lock
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	// Only some events are published, other events must not create objects
+	switch e.Name {
+	case EventNameError, EventNameNodeContinued, EventNameNodePaused, EventNameNodeStarted, EventNameNodeStats,
+		EventNameNodeStopped, EventNameWorkflowContinued, EventNameWorkflowPaused, EventNameWorkflowStarted,
+		EventNameWorkflowStats:
+	case EventNameWorkflowStopped:
+		if w, ok := e.Target.(*Workflow); ok {
+			m.del(w.Name())
+		}
+		return
+	default:
+		return
+	}
+
+	// Get object
+	var o *metricsObject
+	switch t := e.Target.(type) {
+	case *Workflow:
+		o = m.workflow(t.Name())
+	case Node:
+		o = m.node(t)
+	default:
+		return
+	}
+
+	// Switch on event name
+	switch e.Name {
+	case EventNameError:
+		o.errors++
+	case EventNameNodeContinued, EventNameWorkflowContinued:
+		o.status = StatusRunning
+	case EventNameNodePaused, EventNameWorkflowPaused:
+		o.status = StatusPaused
+	case EventNameNodeStarted, EventNameWorkflowStarted:
+		o.starts++
+		o.status = StatusRunning
+	case EventNameNodeStats, EventNameWorkflowStats:
+		o.stats = e.Payload.([]EventStat)
+	case EventNameNodeStopped:
+		o.status = StatusStopped
+	}
+}
+
+// del must be called with the lock held. It drops the series of the workflow and of its nodes
+func (m *Metrics) del(workflow string) {
+	delete(m.ws, workflow)
+	for k := range m.ns {
+		if k.workflow == workflow {
+			delete(m.ns, k)
+		}
+	}
+}
+
+func (m *Metrics) workflow(name string) *metricsObject {
+	o, ok := m.ws[name]
+	if !ok {
+		o = &metricsObject{status: StatusStopped}
+		m.ws[name] = o
+	}
+	return o
+}
+
+func (m *Metrics) node(n Node) *metricsObject {
+	// Get key
+	k := metricsNodeKey{
+		node:     n.Metadata().Name,
+		workflow: nodeWorkflowName(n),
+	}
+
+	// Get object
+	o, ok := m.ns[k]
+	if !ok {
+		o = &metricsObject{status: StatusStopped}
+		m.ns[k] = o
+	}
+	return o
+}
+
+// Handler returns the handler serving the metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.write(rw)
+	})
+}
+
+type metricsFamily struct {
+	help    string
+	samples []string
+	typ     string
+}
+
+func (m *Metrics) write(w io.Writer) {
+	// Lock
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	// Add workflows
+	fs := make(map[string]*metricsFamily)
+	for name, o := range m.ws {
+		o.add(fs, "workflow", fmt.Sprintf(`workflow="%s"`, escapeMetricsLabelValue(name)))
+	}
+
+	// Add nodes
+	for k, o := range m.ns {
+		o.add(fs, "node", fmt.Sprintf(`node="%s",workflow="%s"`, escapeMetricsLabelValue(k.node), escapeMetricsLabelValue(k.workflow)))
+	}
+
+	// Sort families
+	var names []string
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Write
+	for _, name := range names {
+		f := fs[name]
+		sort.Strings(f.samples)
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.typ)
+		for _, s := range f.samples {
+			fmt.Fprintf(w, "%s%s\n", name, s)
+		}
+	}
+}
+
+func (o *metricsObject) add(fs map[string]*metricsFamily, prefix, labels string) {
+	// Status
+	for _, s := range []string{StatusPaused, StatusRunning, StatusStopped} {
+		var v int
+		if o.status == s {
+			v = 1
+		}
+		addMetricsSample(fs, "astiencoder_"+prefix+"_status", "gauge", "Whether the "+prefix+" has this status", fmt.Sprintf(`{%s,status="%s"} %d`, labels, s, v))
+	}
+
+	// Errors
+	addMetricsSample(fs, "astiencoder_"+prefix+"_errors_total", "counter", "Number of errors emitted by the "+prefix, fmt.Sprintf("{%s} %d", labels, o.errors))
+
+	// Restarts
+	var restarts uint64
+	if o.starts > 1 {
+		restarts = o.starts - 1
+	}
+	addMetricsSample(fs, "astiencoder_"+prefix+"_restarts_total", "counter", "Number of times the "+prefix+" has been started again after its first start", fmt.Sprintf("{%s} %d", labels, restarts))
+
+	// Stats
+	for _, s := range o.stats {
+		// Only numeric stats can be published
+		v, ok := s.Value.(float64)
+		if !ok {
+			continue
+		}
+
+		// Get help
+		h := s.Description
+		if s.Unit != "" {
+			h += " (" + s.Unit + ")"
+		}
+
+		// Add
+		addMetricsSample(fs, "astiencoder_"+prefix+"_"+metricsName(s.Label), "gauge", h, fmt.Sprintf("{%s} %g", labels, v))
+	}
+}
+
+func addMetricsSample(fs map[string]*metricsFamily, name, typ, help, sample string) {
+	f, ok := fs[name]
+	if !ok {
+		f = &metricsFamily{
+			help: strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help),
+			typ:  typ,
+		}
+		fs[name] = f
+	}
+	f.samples = append(f.samples, sample)
+}
+
+func metricsName(label string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return r >= unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r))
+	}), "_")
+}
+
+func escapeMetricsLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,72 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	eh := NewEventHandler()
+	m := NewMetrics(eh)
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	n := newMockedStatsNode("n", eh)
+	n.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+
+	m.handleEvent(Event{Name: EventNameWorkflowStarted, Target: w})
+	m.handleEvent(Event{Name: EventNameNodeStarted, Target: n})
+	m.handleEvent(Event{Name: EventNameNodeStopped, Target: n})
+	m.handleEvent(Event{Name: EventNameNodeStarted, Target: n})
+	m.handleEvent(EventError(n, errors.New("test")))
+	m.handleEvent(Event{Name: EventNameNodeStats, Payload: []EventStat{
+		{Description: "Number of frames going out per second", Label: "Outgoing rate", Unit: "fps", Value: 25.0},
+		{Label: "Not numeric", Value: "test"},
+	}, Target: n})
+	m.handleEvent(Event{Name: EventNameError, Payload: errors.New("test"), Target: "unknown"})
+
+	b := &bytes.Buffer{}
+	m.write(b)
+	assert.Equal(t, `# HELP astiencoder_node_errors_total Number of errors emitted by the node
+# TYPE astiencoder_node_errors_total counter
+astiencoder_node_errors_total{node="n",workflow="w"} 1
+# HELP astiencoder_node_outgoing_rate Number of frames going out per second (fps)
+# TYPE astiencoder_node_outgoing_rate gauge
+astiencoder_node_outgoing_rate{node="n",workflow="w"} 25
+# HELP astiencoder_node_restarts_total Number of times the node has been started again after its first start
+# TYPE astiencoder_node_restarts_total counter
+astiencoder_node_restarts_total{node="n",workflow="w"} 1
+# HELP astiencoder_node_status Whether the node has this status
+# TYPE astiencoder_node_status gauge
+astiencoder_node_status{node="n",workflow="w",status="paused"} 0
+astiencoder_node_status{node="n",workflow="w",status="running"} 1
+astiencoder_node_status{node="n",workflow="w",status="stopped"} 0
+# HELP astiencoder_workflow_errors_total Number of errors emitted by the workflow
+# TYPE astiencoder_workflow_errors_total counter
+astiencoder_workflow_errors_total{workflow="w"} 0
+# HELP astiencoder_workflow_restarts_total Number of times the workflow has been started again after its first start
+# TYPE astiencoder_workflow_restarts_total counter
+astiencoder_workflow_restarts_total{workflow="w"} 0
+# HELP astiencoder_workflow_status Whether the workflow has this status
+# TYPE astiencoder_workflow_status gauge
+astiencoder_workflow_status{workflow="w",status="paused"} 0
+astiencoder_workflow_status{workflow="w",status="running"} 1
+astiencoder_workflow_status{workflow="w",status="stopped"} 0
+`, b.String())
+
+	// Series are dropped once the workflow is stopped
+	m.handleEvent(Event{Name: EventNameWorkflowStopped, Target: w})
+	m.handleEvent(Event{Name: EventNameAudit, Payload: AuditEntry{}, Target: w})
+	b.Reset()
+	m.write(b)
+	assert.Equal(t, "", b.String())
+	assert.Len(t, m.ns, 0)
+	assert.Len(t, m.ws, 0)
+
+	assert.Equal(t, "queue_dropped_rate", metricsName("Queue dropped rate"))
+	assert.Equal(t, "dropped_frames_late", metricsName("Dropped frames (late)"))
+	assert.Equal(t, `a\"b\\c`, escapeMetricsLabelValue(`a"b\c`))
+}
@@ -2,6 +2,8 @@ package astiencoder
 
 import (
 	"context"
+	"fmt"
+	runtimepprof "runtime/pprof"
 	"sort"
 	"sync"
 	"time"
@@ -120,8 +122,17 @@ func DisconnectNodes(parent, child Node) {
 
 // NodeOptions represents node options
 type NodeOptions struct {
+	// CPUs the node's goroutine is pinned to. Threads created by the node while processing (e.g. codec threads) are
+	// pinned to them as well. Empty means no pinning. Only available on Linux
+	CPUs []int
+	// Default is the logger of the workflow the node belongs to
+	Logger         Logger
 	Metadata       NodeMetadata
 	NoIndirectStop bool
+	// Options of the input queue of nodes handling incoming objects
+	Queue QueueOptions
+	// Period at which the node computes and emits its stats. Default is 2s
+	StatsPeriod time.Duration
 }
 
 // BaseNode represents a base node
@@ -134,6 +145,7 @@ type BaseNode struct {
 	ctxPause        context.Context
 	eh              *EventHandler
 	eg              EventGenerator
+	l               Logger
 	o               NodeOptions
 	m               *sync.Mutex
 	oStart          *sync.Once
@@ -141,11 +153,18 @@ type BaseNode struct {
 	parents         map[string]Node
 	parentsStarted  map[string]bool
 	s               *astikit.Stater
+	stats           []EventStat
 	status          string
 }
 
 // NewBaseNode creates a new base node
 func NewBaseNode(o NodeOptions, eg EventGenerator, eh *EventHandler) (n *BaseNode) {
+	// Default options
+	if o.StatsPeriod <= 0 {
+		o.StatsPeriod = 2 * time.Second
+	}
+
+	// Create node
 	n = &BaseNode{
 		children:        make(map[string]Node),
 		childrenStarted: make(map[string]bool),
@@ -161,8 +180,11 @@ func NewBaseNode(o NodeOptions, eg EventGenerator, eh *EventHandler) (n *BaseNod
 	}
 	n.s = astikit.NewStater(astikit.StaterOptions{
 		HandleFunc: n.statsHandleFunc,
-		Period:     2 * time.Second,
+		Period:     o.StatsPeriod,
 	})
+
+	// Create logger
+	n.l = LoggerWithFields(logger(o.Logger), LogField{Key: LogFieldNode, Value: o.Metadata.Name})
 	return
 }
 
@@ -199,9 +221,25 @@ func (n *BaseNode) Start(ctx context.Context, tc CreateTaskFunc, execFunc BaseNo
 		// Create task
 		t := tc()
 
+		// Label the node's goroutines
+		ctx = withProfileLabels(ctx, ProfileLabelNode, n.o.Metadata.Name)
+
+		// Trace the node
+		var span Span
+		if tr := TracingFromContext(ctx); tr != nil {
+			ctx, span = tr.Start(ctx, SpanNameNode, SpanAttribute{Key: SpanAttributeNode, Value: n.o.Metadata.Name})
+		}
+
 		// Reset context
 		n.ctx, n.cancel = context.WithCancel(ctx)
 
+		// Use the workflow logger
+		if l := LoggerFromContext(ctx); l != nil && n.o.Logger == nil {
+			n.m.Lock()
+			n.l = LoggerWithFields(l, LogField{Key: LogFieldNode, Value: n.o.Metadata.Name})
+			n.m.Unlock()
+		}
+
 		// Reset once
 		n.oStop = &sync.Once{}
 
@@ -228,6 +266,11 @@ func (n *BaseNode) Start(ctx context.Context, tc CreateTaskFunc, execFunc BaseNo
 			// Task is done
 			defer t.Done()
 
+			// End span
+			if span != nil {
+				defer span.End()
+			}
+
 			// Send stopped event
 			defer n.eh.Emit(n.eg.Event(EventTypeStopped, nil))
 
@@ -254,13 +297,33 @@ func (n *BaseNode) Start(ctx context.Context, tc CreateTaskFunc, execFunc BaseNo
 			// Make sure the node is stopped properly
 			defer n.Stop()
 
+			// Label goroutine
+			runtimepprof.SetGoroutineLabels(n.ctx)
+
+			// Pin CPUs
+			if len(n.o.CPUs) > 0 {
+				if unlock, err := LockOSThreadToCPUs(n.o.CPUs); err != nil {
+					n.eh.Emit(EventError(n, fmt.Errorf("astiencoder: locking os thread to cpus %v failed: %w", n.o.CPUs, err)))
+				} else {
+					defer unlock()
+				}
+			}
+
 			// Handle the stater
 			if n.s != nil {
-				// Make sure the stater is stopped properly
-				defer n.s.Stop()
-
 				// Start stater
-				go n.s.Start(n.ctx)
+				// It's stopped by cancelling the node's context rather than with Stop, which would race with Start
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					n.s.Start(n.ctx)
+				}()
+
+				// Make sure the stater is stopped properly before moving on
+				defer func() {
+					n.Stop()
+					<-done
+				}()
 			}
 
 			// Exec func
@@ -362,6 +425,7 @@ func (n *BaseNode) DelChild(i Node) {
 	n.m.Lock()
 	defer n.m.Unlock()
 	delete(n.children, i.Metadata().Name)
+	delete(n.childrenStarted, i.Metadata().Name)
 }
 
 // ChildIsStarted implements the NodeParent interface
@@ -417,6 +481,7 @@ func (n *BaseNode) DelParent(i Node) {
 	n.m.Lock()
 	defer n.m.Unlock()
 	delete(n.parents, i.Metadata().Name)
+	delete(n.parentsStarted, i.Metadata().Name)
 }
 
 // ParentIsStarted implements the NodeChild interface
@@ -462,6 +527,14 @@ func (n *BaseNode) Metadata() NodeMetadata {
 	return n.o.Metadata
 }
 
+// Logger returns the node logger
+// Logs have the node name as field, and the workflow name as well once the node has been started by its workflow
+func (n *BaseNode) Logger() Logger {
+	n.m.Lock()
+	defer n.m.Unlock()
+	return n.l
+}
+
 // Stater returns the node stater
 func (n *BaseNode) Stater() *astikit.Stater {
 	return n.s
@@ -492,6 +565,18 @@ func (n *BaseNode) statsHandleFunc(stats []astikit.Stat) {
 		})
 	}
 
+	// Store stats
+	n.m.Lock()
+	n.stats = ss
+	n.m.Unlock()
+
 	// Send event
 	n.eh.Emit(n.eg.Event(EventTypeStats, ss))
 }
+
+// Stats returns the last stats emitted by the node
+func (n *BaseNode) Stats() []EventStat {
+	n.m.Lock()
+	defer n.m.Unlock()
+	return n.stats
+}
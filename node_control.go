@@ -0,0 +1,101 @@
+package astiencoder
+
+import (
+	"fmt"
+)
+
+// BitRateSetter represents a node whose bit rate can be changed while it's running, e.g. an encoder
+type BitRateSetter interface {
+	SetBitRate(bitRate int)
+}
+
+// Switcher represents a node forwarding the data of only one of its parents at a time, e.g. a rate enforcer
+type Switcher interface {
+	// Switch switches the source to one of the node's parents
+	Switch(n Node)
+}
+
+// PauseNode pauses a node of the workflow without pausing the other nodes
+func (c *WorkflowController) PauseNode(nodeName string) error {
+	return c.do(nodeName, AuditActionPause, nil, func(n Node) error {
+		n.Pause()
+		return nil
+	})
+}
+
+// ContinueNode continues a paused node of the workflow
+func (c *WorkflowController) ContinueNode(nodeName string) error {
+	return c.do(nodeName, AuditActionContinue, nil, func(n Node) error {
+		n.Continue()
+		return nil
+	})
+}
+
+// SetBitRate changes the bit rate of a node of the workflow. The node must implement the BitRateSetter interface
+func (c *WorkflowController) SetBitRate(nodeName string, bitRate int) error {
+	return c.do(nodeName, AuditActionSetBitRate, map[string]interface{}{"bit_rate": bitRate}, func(n Node) error {
+		// Invalid bit rate
+		if bitRate <= 0 {
+			return fmt.Errorf("astiencoder: invalid bit rate %d", bitRate)
+		}
+
+		// Node can't change its bit rate
+		s, ok := n.(BitRateSetter)
+		if !ok {
+			return fmt.Errorf("astiencoder: node %s can't change its bit rate", nodeName)
+		}
+
+		// Set bit rate
+		s.SetBitRate(bitRate)
+		return nil
+	})
+}
+
+// Switch switches the source of a node of the workflow to one of its parents. The node must implement the Switcher
+// interface
+func (c *WorkflowController) Switch(nodeName, sourceName string) error {
+	return c.do(nodeName, AuditActionSwap, map[string]interface{}{"source": sourceName}, func(n Node) error {
+		// Node can't switch
+		s, ok := n.(Switcher)
+		if !ok {
+			return fmt.Errorf("astiencoder: node %s can't switch its source", nodeName)
+		}
+
+		// Get source
+		var src Node
+		for _, p := range n.Parents() {
+			if p.Metadata().Name == sourceName {
+				src = p
+				break
+			}
+		}
+
+		// Source is not a parent
+		if src == nil {
+			return fmt.Errorf("astiencoder: node %s is not a parent of node %s", sourceName, nodeName)
+		}
+
+		// Switch
+		s.Switch(src)
+		return nil
+	})
+}
+
+func (c *WorkflowController) do(nodeName, action string, params map[string]interface{}, fn func(n Node) error) (err error) {
+	// Get params
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+	params["node"] = nodeName
+
+	// Get node
+	n, ok := c.w.indexedNodes()[nodeName]
+	if !ok {
+		err = nodeNotFoundError(nodeName)
+		c.w.audit(nil, c.actor, action, params, err)
+		return
+	}
+
+	// Do
+	return c.Do(n, action, params, func() error { return fn(n) })
+}
@@ -0,0 +1,81 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedBitRateNode struct {
+	*mockedStatsNode
+	bitRate int
+}
+
+func (n *mockedBitRateNode) SetBitRate(bitRate int) {
+	n.bitRate = bitRate
+}
+
+type mockedSwitcherNode struct {
+	*mockedStatsNode
+	source Node
+}
+
+func (n *mockedSwitcherNode) Switch(source Node) {
+	n.source = source
+}
+
+func TestWorkflowControllerNodeControls(t *testing.T) {
+	// Create workflow
+	eh := NewEventHandler()
+	var as []AuditEntry
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		as = append(as, e.Payload.(AuditEntry))
+		return false
+	})
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	c := w.Controller("alice")
+
+	// Create nodes
+	n := newMockedStatsNode("n", eh)
+	b := &mockedBitRateNode{mockedStatsNode: newMockedStatsNode("b", eh)}
+	s := &mockedSwitcherNode{mockedStatsNode: newMockedStatsNode("s", eh)}
+	w.AddChild(n)
+	w.AddChild(b)
+	ConnectNodes(n, s)
+	ConnectNodes(b, s)
+
+	// Pause and continue
+	assert.NoError(t, c.PauseNode("n"))
+	assert.NoError(t, c.ContinueNode("n"))
+	err := c.PauseNode("unknown")
+	assert.True(t, errors.Is(err, ErrNodeNotFound))
+	assert.Len(t, as, 3)
+	assert.Equal(t, AuditEntry{Action: AuditActionPause, Actor: "alice", At: as[0].At, Node: "n", Params: map[string]interface{}{"node": "n"}, Workflow: "w"}, as[0])
+	assert.Equal(t, AuditEntry{Action: AuditActionPause, Actor: "alice", At: as[2].At, Error: "astiencoder: node unknown doesn't exist", Params: map[string]interface{}{"node": "unknown"}, Workflow: "w"}, as[2])
+
+	// Set bit rate
+	as = []AuditEntry{}
+	assert.NoError(t, c.SetBitRate("b", 2))
+	assert.Equal(t, 2, b.bitRate)
+	assert.EqualError(t, c.SetBitRate("b", 0), "astiencoder: invalid bit rate 0")
+	assert.EqualError(t, c.SetBitRate("n", 2), "astiencoder: node n can't change its bit rate")
+	assert.Len(t, as, 3)
+	assert.Equal(t, AuditActionSetBitRate, as[0].Action)
+	assert.Equal(t, map[string]interface{}{"bit_rate": 2, "node": "b"}, as[0].Params)
+	assert.Equal(t, "astiencoder: node n can't change its bit rate", as[2].Error)
+
+	// Switch
+	as = []AuditEntry{}
+	assert.NoError(t, c.Switch("s", "b"))
+	assert.Equal(t, b, s.source)
+	assert.NoError(t, c.Switch("s", "n"))
+	assert.Equal(t, n, s.source)
+	assert.EqualError(t, c.Switch("s", "s"), "astiencoder: node s is not a parent of node s")
+	assert.EqualError(t, c.Switch("n", "b"), "astiencoder: node n can't switch its source")
+	assert.Len(t, as, 4)
+	assert.Equal(t, AuditActionSwap, as[0].Action)
+	assert.Equal(t, map[string]interface{}{"node": "s", "source": "b"}, as[0].Params)
+}
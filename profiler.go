@@ -0,0 +1,230 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Profile types
+const (
+	ProfileTypeBlock     = "block"
+	ProfileTypeCPU       = "cpu"
+	ProfileTypeGoroutine = "goroutine"
+	ProfileTypeHeap      = "heap"
+	ProfileTypeMutex     = "mutex"
+	ProfileTypeTrace     = "trace"
+)
+
+// Profile labels
+// Goroutines of nodes are labeled so that CPU and goroutine profiles can be filtered by workflow or node (e.g.
+// "go tool pprof -tagfocus workflow=my-workflow")
+const (
+	ProfileLabelNode     = "node"
+	ProfileLabelWorkflow = "workflow"
+)
+
+const profilerDurationDefault = 30 * time.Second
+
+// Profiler represents an object capable of capturing profiles and execution traces on demand
+// Captures can be requested through its HTTP handler, by emitting an EventNameProfileRequested event or by calling
+// Capture directly
+type Profiler struct {
+	eh *EventHandler
+	m  *sync.Mutex
+	o  ProfilerOptions
+	// Indexed by profile type
+	running map[string]bool
+}
+
+// ProfilerOptions represents profiler options
+type ProfilerOptions struct {
+	// Rate of the block profile. 0 disables it. See runtime.SetBlockProfileRate
+	BlockProfileRate int
+	// Directory in which captures requested through events are written. Default is the temp dir
+	Dir string
+	// Fraction of the mutex profile. 0 disables it. See runtime.SetMutexProfileFraction
+	MutexProfileFraction int
+}
+
+// ProfileRequest represents a profile request
+// It is the payload of the EventNameProfileRequested event
+type ProfileRequest struct {
+	// Only used by CPU profiles and traces. Default is 30s
+	Duration time.Duration
+	Type     string
+}
+
+// Profile represents a captured profile
+// It is the payload of the EventNameProfileCaptured event
+type Profile struct {
+	Path    string
+	Request ProfileRequest
+}
+
+// NewProfiler creates a new profiler
+func NewProfiler(o ProfilerOptions, eh *EventHandler) (p *Profiler) {
+	// Create profiler
+	p = &Profiler{
+		eh:      eh,
+		m:       &sync.Mutex{},
+		o:       o,
+		running: make(map[string]bool),
+	}
+
+	// Update rates
+	runtime.SetBlockProfileRate(o.BlockProfileRate)
+	runtime.SetMutexProfileFraction(o.MutexProfileFraction)
+
+	// Handle requests
+	eh.AddForEventName(EventNameProfileRequested, func(e Event) bool {
+		r, ok := e.Payload.(ProfileRequest)
+		if !ok {
+			return false
+		}
+		go func() {
+			// Capture
+			path, err := p.CaptureToFile(context.Background(), r)
+			if err != nil {
+				eh.Emit(EventError(p, fmt.Errorf("astiencoder: capturing %s profile failed: %w", r.Type, err)))
+				return
+			}
+
+			// Emit
+			eh.Emit(Event{
+				Name: EventNameProfileCaptured,
+				Payload: Profile{
+					Path:    path,
+					Request: r,
+				},
+				Target: p,
+			})
+		}()
+		return false
+	})
+	return
+}
+
+// CaptureToFile captures a profile into a new file of the profiler's directory and returns its path
+func (p *Profiler) CaptureToFile(ctx context.Context, r ProfileRequest) (path string, err error) {
+	// Get extension
+	ext := ".pprof"
+	if r.Type == ProfileTypeTrace {
+		ext = ".trace"
+	}
+
+	// Get directory
+	dir := p.o.Dir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	// Create file
+	path = filepath.Join(dir, fmt.Sprintf("astiencoder-%s-%s%s", r.Type, time.Now().Format("20060102150405.000"), ext))
+	var f *os.File
+	if f, err = os.Create(path); err != nil {
+		err = fmt.Errorf("astiencoder: creating %s failed: %w", path, err)
+		return
+	}
+	defer f.Close()
+
+	// Capture
+	if err = p.Capture(ctx, r, f); err != nil {
+		err = fmt.Errorf("astiencoder: capturing failed: %w", err)
+		return
+	}
+	return
+}
+
+// Capture captures a profile and writes it to w
+// CPU profiles and traces last for the requested duration unless the context is done before
+func (p *Profiler) Capture(ctx context.Context, r ProfileRequest, w io.Writer) (err error) {
+	// Default duration
+	if r.Duration <= 0 {
+		r.Duration = profilerDurationDefault
+	}
+
+	// Switch on type
+	switch r.Type {
+	case ProfileTypeCPU, ProfileTypeTrace:
+		// Make sure only one capture of this type is running
+		if !p.start(r.Type) {
+			err = fmt.Errorf("astiencoder: a %s capture is already running", r.Type)
+			return
+		}
+		defer p.stop(r.Type)
+
+		// Start
+		if r.Type == ProfileTypeCPU {
+			err = runtimepprof.StartCPUProfile(w)
+		} else {
+			err = trace.Start(w)
+		}
+		if err != nil {
+			err = fmt.Errorf("astiencoder: starting %s capture failed: %w", r.Type, err)
+			return
+		}
+
+		// Wait
+		astikit.Sleep(ctx, r.Duration)
+
+		// Stop
+		if r.Type == ProfileTypeCPU {
+			runtimepprof.StopCPUProfile()
+		} else {
+			trace.Stop()
+		}
+	case ProfileTypeBlock, ProfileTypeGoroutine, ProfileTypeHeap, ProfileTypeMutex:
+		if err = runtimepprof.Lookup(r.Type).WriteTo(w, 0); err != nil {
+			err = fmt.Errorf("astiencoder: writing %s profile failed: %w", r.Type, err)
+			return
+		}
+	default:
+		err = errors.New("astiencoder: invalid profile type " + r.Type)
+	}
+	return
+}
+
+func (p *Profiler) start(typ string) bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.running[typ] {
+		return false
+	}
+	p.running[typ] = true
+	return true
+}
+
+func (p *Profiler) stop(typ string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	delete(p.running, typ)
+}
+
+// Handler returns the net/http/pprof handler which must be served under /debug/pprof/
+func (p *Profiler) Handler() http.Handler {
+	m := http.NewServeMux()
+	m.HandleFunc("/debug/pprof/", pprof.Index)
+	m.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	m.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	m.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	m.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return m
+}
+
+// withProfileLabels adds a profile label to the context
+func withProfileLabels(ctx context.Context, k, v string) context.Context {
+	return runtimepprof.WithLabels(ctx, runtimepprof.Labels(k, v))
+}
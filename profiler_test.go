@@ -0,0 +1,56 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler(t *testing.T) {
+	eh := NewEventHandler()
+	defer eh.Close()
+	dir, err := ioutil.TempDir("", "astiencoder-profiler-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := NewProfiler(ProfilerOptions{Dir: dir}, eh)
+
+	// Capture
+	buf := &bytes.Buffer{}
+	assert.NoError(t, p.Capture(context.Background(), ProfileRequest{Type: ProfileTypeHeap}, buf))
+	assert.True(t, buf.Len() > 0)
+	assert.Error(t, p.Capture(context.Background(), ProfileRequest{Type: "invalid"}, buf))
+
+	// Concurrent captures
+	assert.True(t, p.start(ProfileTypeCPU))
+	assert.Error(t, p.Capture(context.Background(), ProfileRequest{Type: ProfileTypeCPU}, buf))
+	p.stop(ProfileTypeCPU)
+
+	// Capture is cut short when the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	assert.NoError(t, p.Capture(ctx, ProfileRequest{Duration: time.Hour, Type: ProfileTypeTrace}, buf))
+	assert.True(t, buf.Len() > 0)
+
+	// Event
+	c := make(chan Profile, 1)
+	eh.AddForEventName(EventNameProfileCaptured, func(e Event) bool {
+		c <- e.Payload.(Profile)
+		return false
+	})
+	eh.Emit(Event{Name: EventNameProfileRequested, Payload: ProfileRequest{Type: ProfileTypeGoroutine}})
+	select {
+	case pr := <-c:
+		assert.Equal(t, ProfileTypeGoroutine, pr.Request.Type)
+		fi, err := os.Stat(pr.Path)
+		assert.NoError(t, err)
+		assert.True(t, fi.Size() > 0)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no profile captured")
+	}
+}
@@ -0,0 +1,138 @@
+package astiencoder
+
+import (
+	"sync"
+	"time"
+)
+
+// Progresser represents a node that knows how much of its input it has processed, such as a demuxer reading a file
+type Progresser interface {
+	// Progress returns the media duration processed so far and the total media duration of the input. A total duration
+	// <= 0 means it is unknown
+	Progress() (position, duration time.Duration)
+}
+
+// WorkflowProgress represents the progress of a workflow whose inputs have a known duration
+// It is the payload of the EventNameWorkflowProgress event
+type WorkflowProgress struct {
+	// Total media duration of the inputs
+	Duration time.Duration
+	// Estimated remaining duration before the inputs are fully processed. 0 if it can't be estimated yet
+	ETA time.Duration
+	// Media duration of the inputs processed so far
+	Position time.Duration
+	// Between 0 and 1
+	Ratio float64
+	// Media duration processed per second of running time, 1 being realtime
+	Throughput float64
+}
+
+// workflowClock measures the running time of a workflow, pauses excluded
+type workflowClock struct {
+	m         *sync.Mutex
+	now       func() time.Time
+	pausedAt  time.Time
+	pausedFor time.Duration
+	startedAt time.Time
+}
+
+func newWorkflowClock() *workflowClock {
+	return &workflowClock{
+		m:   &sync.Mutex{},
+		now: time.Now,
+	}
+}
+
+func (c *workflowClock) start() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.startedAt = c.now()
+	c.pausedAt = time.Time{}
+	c.pausedFor = 0
+}
+
+func (c *workflowClock) pause() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.pausedAt.IsZero() {
+		c.pausedAt = c.now()
+	}
+}
+
+func (c *workflowClock) resume() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if !c.pausedAt.IsZero() {
+		c.pausedFor += c.now().Sub(c.pausedAt)
+		c.pausedAt = time.Time{}
+	}
+}
+
+func (c *workflowClock) started() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.startedAt
+}
+
+func (c *workflowClock) elapsed() time.Duration {
+	// Lock
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	// Not started
+	if c.startedAt.IsZero() {
+		return 0
+	}
+
+	// Get end
+	end := c.now()
+	if !c.pausedAt.IsZero() {
+		end = c.pausedAt
+	}
+	return end.Sub(c.startedAt) - c.pausedFor
+}
+
+// Progress returns the aggregated progress of the workflow's nodes implementing the Progresser interface, as well
+// as the throughput and ETA based on the workflow running time
+// It returns false if no node knows the duration of its input, which is the case with live inputs
+func (w *Workflow) Progress() (p WorkflowProgress, ok bool) {
+	// Loop through nodes
+	for _, n := range w.nodes() {
+		// Node doesn't report its progress
+		v, isProgresser := n.(Progresser)
+		if !isProgresser {
+			continue
+		}
+
+		// Duration is unknown
+		position, duration := v.Progress()
+		if duration <= 0 {
+			continue
+		}
+
+		// Aggregate
+		if position > duration {
+			position = duration
+		}
+		p.Duration += duration
+		p.Position += position
+		ok = true
+	}
+
+	// No progress
+	if !ok {
+		return
+	}
+
+	// Compute ratio
+	p.Ratio = float64(p.Position) / float64(p.Duration)
+
+	// Compute throughput and ETA
+	if elapsed := w.ck.elapsed(); elapsed > 0 {
+		p.Throughput = float64(p.Position) / float64(elapsed)
+		if p.Throughput > 0 {
+			p.ETA = time.Duration(float64(p.Duration-p.Position) / p.Throughput)
+		}
+	}
+	return
+}
@@ -0,0 +1,73 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedProgressNode struct {
+	*mockedStatsNode
+	duration time.Duration
+	position time.Duration
+}
+
+func (n *mockedProgressNode) Progress() (time.Duration, time.Duration) {
+	return n.position, n.duration
+}
+
+func TestWorkflowProgress(t *testing.T) {
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	now := time.Unix(100, 0)
+	w.ck.now = func() time.Time { return now }
+
+	// No progresser
+	w.AddChild(newMockedStatsNode("n1", eh))
+	_, ok := w.Progress()
+	assert.False(t, ok)
+
+	// Unknown duration
+	n2 := &mockedProgressNode{mockedStatsNode: newMockedStatsNode("n2", eh)}
+	w.AddChild(n2)
+	_, ok = w.Progress()
+	assert.False(t, ok)
+
+	// Not started
+	n2.duration = time.Minute
+	n2.position = 10 * time.Second
+	p, ok := w.Progress()
+	assert.True(t, ok)
+	assert.Equal(t, WorkflowProgress{Duration: time.Minute, Position: 10 * time.Second, Ratio: 1.0 / 6}, p)
+
+	// Started
+	w.ck.start()
+	now = now.Add(5 * time.Second)
+	p, _ = w.Progress()
+	assert.Equal(t, 2.0, p.Throughput)
+	assert.Equal(t, 25*time.Second, p.ETA)
+
+	// Pauses are excluded
+	w.ck.pause()
+	now = now.Add(time.Hour)
+	p, _ = w.Progress()
+	assert.Equal(t, 2.0, p.Throughput)
+	w.ck.resume()
+	now = now.Add(5 * time.Second)
+	n2.position = 20 * time.Second
+	p, _ = w.Progress()
+	assert.Equal(t, 2.0, p.Throughput)
+	assert.Equal(t, 20*time.Second, p.ETA)
+
+	// Several inputs
+	n3 := &mockedProgressNode{duration: time.Minute, mockedStatsNode: newMockedStatsNode("n3", eh), position: 2 * time.Minute}
+	w.AddChild(n3)
+	p, _ = w.Progress()
+	assert.Equal(t, 2*time.Minute, p.Duration)
+	assert.Equal(t, 80*time.Second, p.Position)
+	assert.Equal(t, 8.0, p.Throughput)
+	assert.Equal(t, 5*time.Second, p.ETA)
+}
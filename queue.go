@@ -0,0 +1,359 @@
+package astiencoder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Queue policies
+const (
+	// Producers block until there's room in the queue
+	QueuePolicyBlock = "block"
+	// The oldest waiting item is dropped to make room for the new one
+	QueuePolicyDropOldest = "drop.oldest"
+	// The new item is dropped
+	QueuePolicyDropNewest = "drop.newest"
+)
+
+const queueSizeDefault = 8
+
+// QueueOptions represents queue options
+type QueueOptions struct {
+	// What happens when the queue is full. Default is QueuePolicyBlock
+	Policy string
+	// Max number of items waiting to be processed. Default is 8. Negative means no limit
+	Size int
+}
+
+// Queue represents the bounded input queue of a node
+// Adding an item returns as soon as it has been enqueued, which only blocks while the queue is started and full with
+// QueuePolicyBlock. Since items are processed after their producer has moved on, they must not capture anything their
+// producer reuses: items added with AddWithRelease are released once they're processed or dropped
+// Before the queue is started, adding an item never blocks
+// Items not yet processed when the queue is stopped are still processed but no item can be added anymore
+type Queue struct {
+	bytes       int64
+	c           *sync.Cond
+	ctx         context.Context
+	cancel      context.CancelFunc
+	items       []*queueItem
+	ma          *MemoryAccountant
+	o           QueueOptions
+	running     uint32
+	started     bool
+	statCPU     *CPUUsageStat
+	statDropped *astikit.CounterRateStat
+	statLength  *queueLengthStat
+	statMemory  *memoryUsageStat
+	statWait    *astikit.DurationPercentageStat
+}
+
+type queueItem struct {
+	fn      func()
+	ma      *MemoryAccountant
+	release func()
+	size    int
+}
+
+// finish must be called without the lock held, once the item has been processed or dropped
+func (i *queueItem) finish() {
+	if i.ma != nil {
+		i.ma.Release(i.size)
+	}
+	if i.release != nil {
+		i.release()
+	}
+}
+
+// NewQueue creates a new queue
+func NewQueue(o QueueOptions) *Queue {
+	// Default options
+	if o.Policy == "" {
+		o.Policy = QueuePolicyBlock
+	}
+	if o.Size == 0 {
+		o.Size = queueSizeDefault
+	}
+
+	// Create queue
+	return &Queue{
+		c:           sync.NewCond(&sync.Mutex{}),
+		o:           o,
+		statCPU:     NewCPUUsageStat(),
+		statDropped: astikit.NewCounterRateStat(),
+		statLength:  &queueLengthStat{},
+		statMemory:  &memoryUsageStat{},
+		statWait:    astikit.NewDurationPercentageStat(),
+	}
+}
+
+// Start processes items until the context is done and all items have been processed
+func (q *Queue) Start(ctx context.Context) {
+	// Make sure to start only once
+	if !atomic.CompareAndSwapUint32(&q.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreUint32(&q.running, 0)
+
+	// Create context
+	q.c.L.Lock()
+	q.ctx, q.cancel = context.WithCancel(ctx)
+	q.ma = MemoryAccountantFromContext(ctx)
+	q.started = true
+	d := q.ctx.Done()
+	q.c.L.Unlock()
+
+	// Make sure to reset status
+	defer func() {
+		q.c.L.Lock()
+		q.ma = nil
+		q.started = false
+		q.c.L.Unlock()
+	}()
+
+	// Wake up when context is done
+	go func() {
+		<-d
+		q.c.L.Lock()
+		q.c.Broadcast()
+		q.c.L.Unlock()
+	}()
+
+	// Loop
+	for {
+		// Wait for an item
+		q.c.L.Lock()
+		for len(q.items) == 0 && q.ctx.Err() == nil {
+			q.statWait.Begin()
+			q.c.Wait()
+			q.statWait.End()
+		}
+
+		// Context is done and all items have been processed
+		if len(q.items) == 0 {
+			q.c.L.Unlock()
+			return
+		}
+
+		// Shift item
+		i := q.items[0]
+		q.items = q.items[1:]
+		q.updateStats(-i.size)
+		q.c.Broadcast()
+		q.c.L.Unlock()
+
+		// Process item
+		q.statCPU.Begin()
+		i.fn()
+		q.statCPU.End()
+		i.finish()
+	}
+}
+
+// Stop stops the queue
+func (q *Queue) Stop() {
+	q.c.L.Lock()
+	defer q.c.L.Unlock()
+	if q.cancel != nil {
+		q.cancel()
+	}
+}
+
+// Add adds a new item to the queue
+func (q *Queue) Add(fn func()) {
+	q.add(fn, nil, 0)
+}
+
+// AddWithSize adds a new item buffering size bytes to the queue
+// If the workflow has a memory budget, the size is registered until the item is processed or dropped
+func (q *Queue) AddWithSize(fn func(), size int) {
+	q.add(fn, nil, size)
+}
+
+// AddWithRelease adds a new item buffering size bytes to the queue and makes sure release is called once the item has
+// been processed or dropped
+// The size is not registered with the workflow's memory budget since the item's memory is expected to be accounted
+// by whoever it's released to, e.g. the shared frame and pkt pools of astilibav
+func (q *Queue) AddWithRelease(fn, release func(), size int) {
+	q.add(fn, release, size)
+}
+
+func (q *Queue) add(fn, release func(), size int) {
+	// Create item
+	i := &queueItem{
+		fn:      fn,
+		release: release,
+		size:    size,
+	}
+
+	// Acquire memory
+	q.c.L.Lock()
+	ma := q.ma
+	q.c.L.Unlock()
+	if ma != nil && size > 0 && release == nil {
+		if !ma.Acquire(size) {
+			q.statDropped.Add(1)
+			i.finish()
+			return
+		}
+		i.ma = ma
+	}
+
+	// Lock
+	q.c.L.Lock()
+
+	// Context is done
+	if q.ctx != nil && q.ctx.Err() != nil {
+		q.c.L.Unlock()
+		i.finish()
+		return
+	}
+
+	// Queue is full
+	var dropped []*queueItem
+	for q.started && q.o.Size > 0 && len(q.items) >= q.o.Size {
+		switch q.o.Policy {
+		case QueuePolicyDropNewest:
+			q.statDropped.Add(1)
+			q.c.L.Unlock()
+			i.finish()
+			return
+		case QueuePolicyDropOldest:
+			q.statDropped.Add(1)
+			dropped = append(dropped, q.items[0])
+			q.updateStats(-q.items[0].size)
+			q.items = q.items[1:]
+		default:
+			q.c.Wait()
+			if q.ctx.Err() != nil {
+				q.c.L.Unlock()
+				i.finish()
+				return
+			}
+		}
+	}
+
+	// Append item
+	q.items = append(q.items, i)
+	q.updateStats(size)
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Finish dropped items
+	for _, d := range dropped {
+		d.finish()
+	}
+}
+
+// Reset drops all waiting items
+func (q *Queue) Reset() {
+	// Remove items
+	q.c.L.Lock()
+	is := q.items
+	q.items = []*queueItem{}
+	q.bytes = 0
+	q.statLength.set(0)
+	q.statMemory.set(0)
+	q.c.Broadcast()
+	q.c.L.Unlock()
+
+	// Finish items
+	for _, i := range is {
+		i.finish()
+	}
+}
+
+// updateStats must be called with the lock held, after items have been modified
+func (q *Queue) updateStats(delta int) {
+	q.bytes += int64(delta)
+	q.statLength.set(len(q.items))
+	q.statMemory.set(q.bytes)
+}
+
+// AddStats adds queue stats to the stater
+func (q *Queue) AddStats(s *astikit.Stater) {
+	// Add wait ratio
+	s.AddStat(astikit.StatMetadata{
+		Description: "Percentage of time spent listening and waiting for new object",
+		Label:       "Wait ratio",
+		Unit:        "%",
+	}, q.statWait)
+
+	// Add queue length
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of objects waiting to be processed",
+		Label:       "Queue length",
+		Unit:        "",
+	}, q.statLength)
+
+	// Add queue occupancy
+	if q.o.Size > 0 {
+		s.AddStat(astikit.StatMetadata{
+			Description: "Percentage of the queue size used by objects waiting to be processed",
+			Label:       "Queue occupancy",
+			Unit:        "%",
+		}, &queueOccupancyStat{l: q.statLength, size: q.o.Size})
+	}
+
+	// Add dropped rate
+	s.AddStat(astikit.StatMetadata{
+		Description: "Number of objects dropped per second because the queue was full or the memory budget was exceeded",
+		Label:       "Queue dropped rate",
+		Unit:        "ops",
+	}, q.statDropped)
+
+	// Add memory
+	s.AddStat(astikit.StatMetadata{
+		Description: "Size of the objects waiting to be processed, as declared by their producers",
+		Label:       "Queue memory",
+		Unit:        "MB",
+	}, q.statMemory)
+
+	// Add CPU usage
+	if CPUUsageSupported() {
+		s.AddStat(astikit.StatMetadata{
+			Description: "Percentage of one core used to process objects. Threads created by libav are not taken into account",
+			Label:       "CPU usage",
+			Unit:        "%",
+		}, q.statCPU)
+	}
+}
+
+type queueLengthStat struct {
+	v int64
+}
+
+func (s *queueLengthStat) set(v int) {
+	atomic.StoreInt64(&s.v, int64(v))
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *queueLengthStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *queueLengthStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *queueLengthStat) Value(_ time.Duration) interface{} {
+	return float64(atomic.LoadInt64(&s.v))
+}
+
+type queueOccupancyStat struct {
+	l    *queueLengthStat
+	size int
+}
+
+// Start implements the astikit.StatHandler interface
+func (s *queueOccupancyStat) Start() {}
+
+// Stop implements the astikit.StatHandler interface
+func (s *queueOccupancyStat) Stop() {}
+
+// Value implements the astikit.StatHandler interface
+func (s *queueOccupancyStat) Value(_ time.Duration) interface{} {
+	return float64(atomic.LoadInt64(&s.l.v)) / float64(s.size) * 100
+}
@@ -0,0 +1,198 @@
+package astiencoder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	for _, c := range []struct {
+		expected []int
+		policy   string
+	}{
+		{expected: []int{0, 1, 2, 3}, policy: QueuePolicyBlock},
+		{expected: []int{0, 1, 2}, policy: QueuePolicyDropNewest},
+		{expected: []int{0, 2, 3}, policy: QueuePolicyDropOldest},
+	} {
+		// Start queue
+		q := NewQueue(QueueOptions{Policy: c.policy, Size: 2})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			q.Start(ctx)
+		}()
+		for !queueStarted(q) {
+			time.Sleep(time.Millisecond)
+		}
+
+		// A single producer adds items while the first one is being processed
+		m := &sync.Mutex{}
+		var is []int
+		processing := make(chan struct{})
+		unblock := make(chan struct{})
+		added := make(chan struct{})
+		go func() {
+			defer close(added)
+			for i := 0; i < 4; i++ {
+				i := i
+				q.Add(func() {
+					if i == 0 {
+						close(processing)
+						<-unblock
+					}
+					m.Lock()
+					is = append(is, i)
+					m.Unlock()
+				})
+				if i == 0 {
+					<-processing
+				}
+			}
+		}()
+
+		// Producer doesn't wait for items to be processed unless the queue is full and blocking
+		if c.policy == QueuePolicyBlock {
+			for queueLength(q) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+		} else {
+			<-added
+			assert.Equal(t, 2, queueLength(q), c.policy)
+		}
+
+		// Process
+		close(unblock)
+		<-added
+		cancel()
+		<-done
+		assert.Equal(t, c.expected, is, c.policy)
+	}
+}
+
+func TestQueueAddWithRelease(t *testing.T) {
+	for _, c := range []struct {
+		expected []int
+		policy   string
+	}{
+		{expected: []int{0, 1, 2, 3}, policy: QueuePolicyBlock},
+		{expected: []int{0, 1, 2}, policy: QueuePolicyDropNewest},
+		{expected: []int{0, 2, 3}, policy: QueuePolicyDropOldest},
+	} {
+		// Start queue
+		q := NewQueue(QueueOptions{Policy: c.policy, Size: 2})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			q.Start(ctx)
+		}()
+		for !queueStarted(q) {
+			time.Sleep(time.Millisecond)
+		}
+
+		// A single producer adds items while the first one is being processed
+		m := &sync.Mutex{}
+		var is []int
+		released := make(map[int]int)
+		processing := make(chan struct{})
+		unblock := make(chan struct{})
+		added := make(chan struct{})
+		go func() {
+			defer close(added)
+			for i := 0; i < 4; i++ {
+				i := i
+				q.AddWithRelease(func() {
+					if i == 0 {
+						close(processing)
+						<-unblock
+					}
+					m.Lock()
+					is = append(is, i)
+					m.Unlock()
+				}, func() {
+					m.Lock()
+					released[i]++
+					m.Unlock()
+				}, 0)
+				if i == 0 {
+					<-processing
+				}
+			}
+		}()
+
+		// Producer doesn't wait for items to be processed unless the queue is full and blocking
+		if c.policy == QueuePolicyBlock {
+			for queueLength(q) < 2 {
+				time.Sleep(time.Millisecond)
+			}
+		} else {
+			<-added
+			assert.Equal(t, 2, queueLength(q), c.policy)
+		}
+
+		// Process
+		close(unblock)
+		<-added
+		cancel()
+		<-done
+		assert.Equal(t, c.expected, is, c.policy)
+		assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, released, c.policy)
+	}
+}
+
+func TestQueueMemory(t *testing.T) {
+	// Items added before the queue is started are buffered
+	q := NewQueue(QueueOptions{Policy: QueuePolicyDropOldest, Size: 2})
+	q.AddWithSize(func() {}, 1e6)
+	q.AddWithSize(func() {}, 2e6)
+	assert.Equal(t, 3.0, q.statMemory.Value(time.Second))
+
+	// Start queue
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		q.Start(ctx)
+	}()
+	cancel()
+	<-done
+	assert.Equal(t, 0.0, q.statMemory.Value(time.Second))
+
+	// Reset
+	q = NewQueue(QueueOptions{})
+	q.AddWithSize(func() {}, 1e6)
+	q.Reset()
+	assert.Equal(t, 0.0, q.statMemory.Value(time.Second))
+}
+
+func TestCPUUsageStat(t *testing.T) {
+	if !CPUUsageSupported() {
+		t.Skip("CPU usage is not supported on this platform")
+	}
+	s := NewCPUUsageStat()
+	start := time.Now()
+	s.Begin()
+	for time.Since(start) < 20*time.Millisecond {
+	}
+	s.End()
+	v := s.Value(40 * time.Millisecond).(float64)
+	assert.True(t, v > 25 && v <= 100)
+	assert.Equal(t, 0.0, s.Value(time.Second))
+}
+
+func queueLength(q *Queue) int {
+	q.c.L.Lock()
+	defer q.c.L.Unlock()
+	return len(q.items)
+}
+
+func queueStarted(q *Queue) bool {
+	q.c.L.Lock()
+	defer q.c.L.Unlock()
+	return q.started
+}
@@ -0,0 +1,91 @@
+package astiencoder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reconfigurer represents a node whose options can be changed while it's running
+type Reconfigurer interface {
+	// Reconfigure applies the options, which are either the node's reconfigure options, a pointer to them, or their
+	// map[string]interface{} representation. Options with a zero value are left unchanged
+	Reconfigure(opts interface{}) error
+}
+
+// DecodeReconfigureOptions decodes opts into dst, which must be a pointer to the reconfigure options of the node
+// Maps are decoded with DecodeDefinitionOptions so that options can be provided through an API
+func DecodeReconfigureOptions(opts interface{}, dst interface{}) error {
+	// Map
+	if m, ok := opts.(map[string]interface{}); ok {
+		return DecodeDefinitionOptions(m, dst)
+	}
+
+	// Get destination
+	d := reflect.ValueOf(dst)
+	if d.Kind() != reflect.Ptr || d.IsNil() {
+		return fmt.Errorf("astiencoder: destination %T is not a pointer", dst)
+	}
+	d = d.Elem()
+
+	// Same type
+	v := reflect.ValueOf(opts)
+	if v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Type() != d.Type() {
+		return fmt.Errorf("astiencoder: options of type %T are not of type %s", opts, d.Type())
+	}
+	d.Set(v)
+	return nil
+}
+
+// Reconfigure applies changed options to a node of the workflow without restarting it
+// The node must implement the Reconfigurer interface. Once applied, the EventNameNodeReconfigured event is emitted
+func (w *Workflow) Reconfigure(nodeName string, opts interface{}) error {
+	return w.reconfigure(nodeName, opts, "")
+}
+
+// Reconfigure applies changed options to a node of the workflow without restarting it
+func (c *WorkflowController) Reconfigure(nodeName string, opts interface{}) error {
+	return c.w.reconfigure(nodeName, opts, c.actor)
+}
+
+func (w *Workflow) reconfigure(nodeName string, opts interface{}, actor string) (err error) {
+	// Get params
+	params := map[string]interface{}{"node": nodeName}
+	if m, errEncode := EncodeDefinitionOptions(opts); errEncode == nil {
+		params["options"] = m
+	}
+
+	// Get node
+	n, ok := w.indexedNodes()[nodeName]
+	if !ok {
+		err = nodeNotFoundError(nodeName)
+		w.audit(nil, actor, AuditActionReconfigure, params, err)
+		return
+	}
+
+	// Node can't be reconfigured
+	r, ok := n.(Reconfigurer)
+	if !ok {
+		err = fmt.Errorf("astiencoder: node %s can't be reconfigured", nodeName)
+		w.audit(n, actor, AuditActionReconfigure, params, err)
+		return
+	}
+
+	// Reconfigure
+	if err = r.Reconfigure(opts); err != nil {
+		err = fmt.Errorf("astiencoder: reconfiguring node %s failed: %w", nodeName, err)
+		w.audit(n, actor, AuditActionReconfigure, params, err)
+		return
+	}
+	w.audit(n, actor, AuditActionReconfigure, params, nil)
+
+	// Emit
+	w.e.Emit(Event{
+		Name:    EventNameNodeReconfigured,
+		Payload: opts,
+		Target:  n,
+	})
+	return
+}
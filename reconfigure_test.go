@@ -0,0 +1,72 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedReconfigureOptions struct {
+	BitRate int `json:"bit_rate,omitempty"`
+}
+
+type mockedReconfigurerNode struct {
+	*mockedStatsNode
+	o mockedReconfigureOptions
+}
+
+func (n *mockedReconfigurerNode) Reconfigure(opts interface{}) error {
+	return DecodeReconfigureOptions(opts, &n.o)
+}
+
+func TestWorkflowReconfigure(t *testing.T) {
+	// Create workflow
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	n := &mockedReconfigurerNode{mockedStatsNode: newMockedStatsNode("n", eh)}
+	w.AddChild(n)
+	ConnectNodes(n, newMockedStatsNode("c", eh))
+	var es []Event
+	eh.AddForEventName(EventNameNodeReconfigured, func(e Event) bool {
+		es = append(es, e)
+		return false
+	})
+	var as []AuditEntry
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		as = append(as, e.Payload.(AuditEntry))
+		return false
+	})
+
+	// Options can be provided with their type, a pointer to them or a map
+	err := w.Reconfigure("n", mockedReconfigureOptions{BitRate: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n.o.BitRate)
+	err = w.Reconfigure("n", &mockedReconfigureOptions{BitRate: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n.o.BitRate)
+	err = w.Controller("alice").Reconfigure("n", map[string]interface{}{"bit_rate": 3.0})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n.o.BitRate)
+	assert.Len(t, es, 3)
+	assert.Equal(t, n, es[2].Target)
+	assert.Equal(t, map[string]interface{}{"bit_rate": 3.0}, es[2].Payload)
+	assert.Len(t, as, 3)
+	assert.Equal(t, "alice", as[2].Actor)
+	assert.Equal(t, AuditActionReconfigure, as[2].Action)
+	assert.Equal(t, map[string]interface{}{"node": "n", "options": map[string]interface{}{"bit_rate": 3.0}}, as[2].Params)
+
+	// Errors
+	err = w.Reconfigure("unknown", nil)
+	assert.EqualError(t, err, "astiencoder: node unknown doesn't exist")
+	err = w.Reconfigure("c", nil)
+	assert.EqualError(t, err, "astiencoder: node c can't be reconfigured")
+	err = w.Reconfigure("n", 1)
+	assert.EqualError(t, err, "astiencoder: reconfiguring node n failed: astiencoder: options of type int are not of type astiencoder.mockedReconfigureOptions")
+	err = w.Reconfigure("n", map[string]interface{}{"bitrate": 1.0})
+	assert.EqualError(t, err, `astiencoder: reconfiguring node n failed: astiencoder: options.bitrate: unknown option bitrate, did you mean "bit_rate"?`)
+	assert.Len(t, es, 3)
+	assert.Len(t, as, 7)
+	assert.Equal(t, "astiencoder: node unknown doesn't exist", as[3].Error)
+}
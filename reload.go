@@ -0,0 +1,230 @@
+package astiencoder
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefinitionSource represents an object capable of providing the definitions of the workflows a control service must
+// run, e.g. the files of a directory. It is read every time the service is reloaded
+type DefinitionSource interface {
+	Definitions() ([]WorkflowDefinition, error)
+}
+
+// DefinitionSourceFunc is an adapter allowing a function to be used as a DefinitionSource
+type DefinitionSourceFunc func() ([]WorkflowDefinition, error)
+
+// Definitions implements the DefinitionSource interface
+func (f DefinitionSourceFunc) Definitions() ([]WorkflowDefinition, error) {
+	return f()
+}
+
+// NewFileDefinitionSource creates a source loading, with LoadWorkflowDefinitionFile, the definition files matching a
+// glob pattern, e.g. "/etc/astiencoder/workflows/*.json". Definitions without name are named after their file. The
+// pattern must not match the files that are only included by other definitions
+func NewFileDefinitionSource(pattern string) DefinitionSource {
+	return DefinitionSourceFunc(func() (ds []WorkflowDefinition, err error) {
+		// Glob
+		var paths []string
+		if paths, err = filepath.Glob(pattern); err != nil {
+			err = fmt.Errorf("astiencoder: globbing %s failed: %w", pattern, err)
+			return
+		}
+		sort.Strings(paths)
+
+		// Loop through paths
+		for _, path := range paths {
+			// Load definition
+			var d WorkflowDefinition
+			if d, err = LoadWorkflowDefinitionFile(path); err != nil {
+				err = fmt.Errorf("astiencoder: loading %s failed: %w", path, err)
+				return
+			}
+
+			// Default name
+			if d.Name == "" {
+				d.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+			ds = append(ds, d)
+		}
+		return
+	})
+}
+
+// Reload applies the changes between a new version of the definition the workflow has been built from and its
+// current definition, with the patches applied so far, without restarting it. The new definition is prepared with
+// the options the workflow has been built with, including the values of its variables, and only the nodes affected
+// by the changes are touched (see ApplyPatch) so that unchanged streams keep flowing
+// No patch is applied if the definitions are the same, in which case the returned changes are empty
+func (w *Workflow) Reload(d WorkflowDefinition) (DefinitionChanges, error) {
+	return w.reload(d, "")
+}
+
+// Reload reloads the definition of the workflow without restarting it
+func (c *WorkflowController) Reload(d WorkflowDefinition) (DefinitionChanges, error) {
+	return c.w.reload(d, c.actor)
+}
+
+func (w *Workflow) reload(d WorkflowDefinition, actor string) (cs DefinitionChanges, err error) {
+	// Workflow has not been built from a definition
+	if w.d == nil {
+		err = errors.New("astiencoder: workflow has not been built from a definition")
+		return
+	}
+
+	// Prepare definition
+	w.d.m.Lock()
+	o := w.d.o
+	w.d.m.Unlock()
+	if d, _, err = prepareDefinition(d, o); err != nil {
+		err = fmt.Errorf("astiencoder: preparing definition failed: %w", err)
+		return
+	}
+
+	// Diff
+	w.d.m.Lock()
+	cs = DiffDefinitions(w.d.d, d)
+	w.d.m.Unlock()
+
+	// Nothing has changed
+	if len(cs) == 0 {
+		return
+	}
+
+	// Apply patch
+	err = w.applyPatch(cs, actor)
+	return
+}
+
+// ControlReloadResult represents the result of a control service reload
+type ControlReloadResult struct {
+	// Workflows that have been added to the source
+	Created []string `json:"created,omitempty"`
+	// Workflows that have been removed from the source
+	Deleted []string `json:"deleted,omitempty"`
+	// Errors of the workflows that couldn't be reloaded, indexed by workflow
+	Errors map[string]string `json:"errors,omitempty"`
+	// Changes applied to the workflows whose definition has changed, indexed by workflow
+	Patched map[string]DefinitionChanges `json:"patched,omitempty"`
+	// Workflows whose definition hasn't changed
+	Unchanged []string `json:"unchanged,omitempty"`
+}
+
+// Reload re-reads the definitions of the Source option and applies them to the service's workflows:
+//   - workflows missing from the service are created and started
+//   - existing workflows are reloaded (see Workflow.Reload): only the nodes whose definition has changed are touched
+//   - workflows created by a previous reload that are missing from the source are deleted. Other workflows, e.g. the
+//     ones created through the API, are left alone
+//
+// Workflows are reloaded independently: the ones that fail are listed in the result's errors while the others are
+// still reloaded. An error is only returned if the source can't be read, in which case no workflow is touched
+func (s *ControlService) Reload(actor string) (r ControlReloadResult, err error) {
+	// No source
+	if s.o.Source == nil {
+		err = errors.New("astiencoder: no definition source")
+		return
+	}
+
+	// Make sure reloads are performed one at a time
+	s.mr.Lock()
+	defer s.mr.Unlock()
+
+	// Get definitions
+	var ds []WorkflowDefinition
+	if ds, err = s.o.Source.Definitions(); err != nil {
+		err = fmt.Errorf("astiencoder: getting definitions failed: %w", err)
+		return
+	}
+
+	// Check names
+	names := make(map[string]bool)
+	for _, d := range ds {
+		if names[d.Name] {
+			err = fmt.Errorf("astiencoder: workflow %s is defined several times", d.Name)
+			return
+		}
+		names[d.Name] = true
+	}
+
+	// Loop through definitions
+	fail := func(name string, err error) {
+		if r.Errors == nil {
+			r.Errors = make(map[string]string)
+		}
+		r.Errors[name] = err.Error()
+	}
+	for _, d := range ds {
+		// Get workflow
+		w, errWorkflow := s.Workflow(d.Name)
+		if errWorkflow != nil {
+			// Create workflow
+			if w, errWorkflow = s.CreateWorkflow(actor, d, nil); errWorkflow != nil {
+				fail(d.Name, errWorkflow)
+				continue
+			}
+			s.setSourced(d.Name)
+
+			// Start workflow
+			if errWorkflow = s.StartWorkflow(actor, d.Name); errWorkflow != nil {
+				fail(d.Name, errWorkflow)
+				continue
+			}
+			r.Created = append(r.Created, d.Name)
+			continue
+		}
+
+		// Reload workflow
+		cs, errWorkflow := w.Controller(actor).Reload(d)
+		if errWorkflow != nil {
+			fail(d.Name, fmt.Errorf("astiencoder: reloading workflow %s failed: %w", d.Name, errWorkflow))
+			continue
+		}
+		s.setSourced(d.Name)
+
+		// Update result
+		if len(cs) == 0 {
+			r.Unchanged = append(r.Unchanged, d.Name)
+			continue
+		}
+		if r.Patched == nil {
+			r.Patched = make(map[string]DefinitionChanges)
+		}
+		r.Patched[d.Name] = cs
+	}
+
+	// Loop through workflows removed from the source
+	for _, name := range s.sourcedWorkflowNames() {
+		if names[name] {
+			continue
+		}
+		if errWorkflow := s.DeleteWorkflow(actor, name); errWorkflow != nil && !errors.Is(errWorkflow, ErrWorkflowNotFound) {
+			fail(name, errWorkflow)
+			continue
+		}
+		r.Deleted = append(r.Deleted, name)
+	}
+	return
+}
+
+func (s *ControlService) setSourced(name string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if cw, ok := s.ws[name]; ok {
+		cw.sourced = true
+	}
+}
+
+func (s *ControlService) sourcedWorkflowNames() (names []string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for name, cw := range s.ws {
+		if cw.sourced {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
@@ -0,0 +1,142 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFileDefinitionSource(t *testing.T) {
+	// Create files
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"name":"w1","nodes":[{"name":"n","type":"t"}]}`), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"nodes":[{"name":"n","type":"t"}]}`), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "c.txt"), []byte("invalid"), 0600))
+
+	// Get definitions
+	ds, err := NewFileDefinitionSource(filepath.Join(dir, "*.json")).Definitions()
+	assert.NoError(t, err)
+	assert.Equal(t, []WorkflowDefinition{
+		{Name: "w1", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}},
+		{Name: "b", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}},
+	}, ds)
+	_, err = NewFileDefinitionSource(filepath.Join(dir, "*.txt")).Definitions()
+	assert.Error(t, err)
+}
+
+func TestControlServiceReload(t *testing.T) {
+	// Register types
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		n := &mockedPatchNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler())}
+		if err := DecodeDefinitionOptions(b.Definition.Options, &n.o); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}})
+
+	// Create service
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	d := func(name string, bitRate int) WorkflowDefinition {
+		return WorkflowDefinition{
+			Name:    name,
+			Nodes:   []NodeDefinition{{Name: "n", Options: map[string]interface{}{"bit_rate": bitRate}, Type: "t"}},
+			Version: DefinitionVersion,
+		}
+	}
+	var ds []WorkflowDefinition
+	var errSource error
+	s := NewControlService(ControlServiceOptions{
+		Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		Source: DefinitionSourceFunc(func() ([]WorkflowDefinition, error) {
+			return ds, errSource
+		}),
+	})
+	_, err := s.CreateWorkflow("alice", d("w3", 1), nil)
+	assert.NoError(t, err)
+
+	// Workflows are created and started
+	ds = []WorkflowDefinition{d("w1", 1), d("w2", 1)}
+	r, err := s.Reload("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, ControlReloadResult{Created: []string{"w1", "w2"}}, r)
+	assert.Equal(t, []ControlWorkflow{
+		{Name: "w1", Status: StatusRunning},
+		{Name: "w2", Status: StatusRunning},
+		{Name: "w3", Status: StatusStopped},
+	}, s.Workflows())
+	w1, err := s.Workflow("w1")
+	assert.NoError(t, err)
+	n := w1.indexedNodes()["n"].(*mockedPatchNode)
+
+	// Changes are applied without touching the other nodes, removed workflows are deleted and invalid ones are reported
+	ds = []WorkflowDefinition{d("w1", 2), d("w3", 1), {Name: "w4", Nodes: []NodeDefinition{{Name: "n", Type: "unknown"}}, Version: DefinitionVersion}}
+	r, err = s.Reload("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"w2"}, r.Deleted)
+	assert.Len(t, r.Errors, 1)
+	assert.Contains(t, r.Errors, "w4")
+	assert.Len(t, r.Patched, 1)
+	assert.Len(t, r.Patched["w1"], 1)
+	assert.Equal(t, []string{"w3"}, r.Unchanged)
+	assert.Equal(t, n, w1.indexedNodes()["n"])
+	assert.Equal(t, 2, n.o.BitRate)
+	assert.Equal(t, []ControlWorkflow{
+		{Name: "w1", Status: StatusRunning},
+		{Name: "w3", Status: StatusStopped},
+	}, s.Workflows())
+
+	// Nothing is touched if the source fails
+	ds = nil
+	errSource = errors.New("test")
+	_, err = s.Reload("alice")
+	assert.Error(t, err)
+	assert.Len(t, s.Workflows(), 2)
+
+	// Workflows reloaded from the source are deleted once removed from it
+	errSource = nil
+	r, err = s.Reload("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, ControlReloadResult{Deleted: []string{"w1", "w3"}}, r)
+	assert.Equal(t, []ControlWorkflow{}, s.Workflows())
+}
+
+func TestControlServiceHandlerReload(t *testing.T) {
+	// Create server
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler()), nil
+	}})
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{
+		Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts},
+		Source: DefinitionSourceFunc(func() ([]WorkflowDefinition, error) {
+			return []WorkflowDefinition{{Name: "w", Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion}}, nil
+		}),
+	})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	// Reload
+	r, err := NewControlClient(ControlClientOptions{Addr: srv.URL}).Reload(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ControlReloadResult{Created: []string{"w"}}, r)
+	r, err = NewControlClient(ControlClientOptions{Addr: srv.URL}).Reload(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ControlReloadResult{Unchanged: []string{"w"}}, r)
+
+	// Namespaces can't reload
+	_, err = NewControlClient(ControlClientOptions{Addr: srv.URL, Namespace: "ns"}).Reload(context.Background())
+	assert.True(t, errors.Is(err, ErrForbidden))
+}
@@ -0,0 +1,257 @@
+package astiencoder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SecretProvider represents an object capable of providing secrets such as stream keys or credentials
+type SecretProvider interface {
+	// Secret returns the secret the reference points to, e.g. a path
+	Secret(ref string) (string, error)
+}
+
+// SecretProviderFunc is an adapter allowing to use a function as a secret provider
+type SecretProviderFunc func(ref string) (string, error)
+
+// Secret implements the SecretProvider interface
+func (f SecretProviderFunc) Secret(ref string) (string, error) {
+	return f(ref)
+}
+
+// EnvSecretProvider provides the value of environment variables
+type EnvSecretProvider struct{}
+
+// Secret implements the SecretProvider interface
+func (p EnvSecretProvider) Secret(ref string) (string, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("astiencoder: environment variable %s is not set", ref)
+	}
+	return v, nil
+}
+
+// FileSecretProvider provides the content of files, without trailing new lines, which is how secrets are usually
+// mounted in containers
+type FileSecretProvider struct {
+	// Relative references are joined to it
+	Dir string
+}
+
+// Secret implements the SecretProvider interface
+func (p FileSecretProvider) Secret(ref string) (string, error) {
+	// Get path
+	path := ref
+	if p.Dir != "" && !filepath.IsAbs(ref) {
+		path = filepath.Join(p.Dir, ref)
+	}
+
+	// Read
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("astiencoder: reading %s failed: %w", path, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// VaultReader represents a Vault client, e.g. the Logical() of the official client wrapped to return the secret's
+// data
+type VaultReader interface {
+	// ReadSecret returns the data of the secret stored at path
+	ReadSecret(path string) (map[string]interface{}, error)
+}
+
+// VaultSecretProvider provides the secrets stored in Vault. References are formatted as "<path>#<key>", e.g.
+// "secret/data/rtmp#key". Keys of KV version 2 secrets are looked up in their "data" as well
+type VaultSecretProvider struct {
+	r VaultReader
+}
+
+// NewVaultSecretProvider creates a new Vault secret provider
+func NewVaultSecretProvider(r VaultReader) *VaultSecretProvider {
+	return &VaultSecretProvider{r: r}
+}
+
+// Secret implements the SecretProvider interface
+func (p *VaultSecretProvider) Secret(ref string) (string, error) {
+	// Parse reference
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return "", fmt.Errorf("astiencoder: vault reference %s has no key", ref)
+	}
+	path, key := ref[:i], ref[i+1:]
+
+	// Read
+	data, err := p.r.ReadSecret(path)
+	if err != nil {
+		return "", fmt.Errorf("astiencoder: reading vault secret %s failed: %w", path, err)
+	}
+
+	// Get value
+	v, ok := data[key]
+	if !ok {
+		if d, okData := data["data"].(map[string]interface{}); okData {
+			v, ok = d[key]
+		}
+	}
+	if !ok {
+		return "", fmt.Errorf("astiencoder: vault secret %s has no key %s", path, key)
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// Secret provider names
+const (
+	SecretProviderNameEnv   = "env"
+	SecretProviderNameFile  = "file"
+	SecretProviderNameVault = "vault"
+)
+
+// SecretProviders represents secret providers indexed by name. It provides secrets whose references are formatted as
+// "<name>:<ref>", e.g. "env:RTMP_KEY" or "file:/run/secrets/rtmp_key"
+type SecretProviders map[string]SecretProvider
+
+// NewDefaultSecretProviders creates secret providers with the built-in env and file providers
+func NewDefaultSecretProviders() SecretProviders {
+	return SecretProviders{
+		SecretProviderNameEnv:  EnvSecretProvider{},
+		SecretProviderNameFile: FileSecretProvider{},
+	}
+}
+
+// Secret implements the SecretProvider interface
+func (ps SecretProviders) Secret(ref string) (string, error) {
+	// Parse reference
+	i := strings.Index(ref, ":")
+	if i < 0 {
+		return "", fmt.Errorf("astiencoder: secret reference %s has no provider", ref)
+	}
+
+	// Get provider
+	p, ok := ps[ref[:i]]
+	if !ok {
+		return "", fmt.Errorf("astiencoder: unknown secret provider %s", ref[:i])
+	}
+	return p.Secret(ref[i+1:])
+}
+
+var secretReferenceRegexp = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// ResolveSecretReferences replaces the ${secret:<ref>} references of s with the secrets returned by the provider
+// Nodes call it when they need their credentials, e.g. when they open their output, so that rotated secrets are
+// taken into account without changing any configuration
+func ResolveSecretReferences(s string, p SecretProvider) (string, error) {
+	// No reference
+	if !strings.Contains(s, "${secret:") {
+		return s, nil
+	}
+
+	// No provider
+	if p == nil {
+		return "", errors.New("astiencoder: no secret provider")
+	}
+
+	// Replace
+	var err error
+	r := secretReferenceRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		// Previous error
+		if err != nil {
+			return ""
+		}
+
+		// Get secret
+		ref := m[9 : len(m)-1]
+		v, errSecret := p.Secret(ref)
+		if errSecret != nil {
+			err = fmt.Errorf("astiencoder: getting secret %s failed: %w", ref, errSecret)
+			return ""
+		}
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	return r, nil
+}
+
+// SubstitutionOptions represents substitution options
+type SubstitutionOptions struct {
+	// Used to resolve ${NAME}. Default is os.LookupEnv
+	LookupEnv func(name string) (string, bool)
+	// Used to resolve ${<name>:<ref>}, indexed by name. A FileSecretProvider is used for the "file" name if none is
+	// provided
+	SecretProviders map[string]SecretProvider
+}
+
+var definitionSubstitutionRegexp = regexp.MustCompile(`\$\$\{|\$\{([^}]*)\}`)
+
+// Substitute returns a copy of the definition whose references to environment variables, e.g. ${RTMP_KEY}, and to
+// secrets, e.g. ${file:/run/secrets/rtmp_key} or ${vault:secret/data/rtmp#key}, have been replaced with their values
+// "$${" is replaced with "${". ${secret:<ref>} references are kept as is since they're resolved by nodes when they
+// need them. Errors are returned as DefinitionErrors and never contain the values
+func (d WorkflowDefinition) Substitute(o SubstitutionOptions) (WorkflowDefinition, error) {
+	// Default options
+	if o.LookupEnv == nil {
+		o.LookupEnv = os.LookupEnv
+	}
+	ps := map[string]SecretProvider{SecretProviderNameFile: FileSecretProvider{}}
+	for k, v := range o.SecretProviders {
+		ps[k] = v
+	}
+
+	// Substitute
+	return d.mapStrings(func(path, s string) (interface{}, error) { return substituteDefinitionString(s, o.LookupEnv, ps) })
+}
+
+func substituteDefinitionString(s string, lookupEnv func(string) (string, bool), ps map[string]SecretProvider) (interface{}, error) {
+	// No reference
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	// Replace
+	var err error
+	r := definitionSubstitutionRegexp.ReplaceAllStringFunc(s, func(m string) string {
+		// Escaped, resolved by nodes or previous error
+		if m == "$${" {
+			return "${"
+		} else if strings.HasPrefix(m, "${secret:") {
+			return m
+		} else if err != nil {
+			return ""
+		}
+
+		// Secret
+		ref := m[2 : len(m)-1]
+		if i := strings.Index(ref, ":"); i >= 0 {
+			p, ok := ps[ref[:i]]
+			if !ok {
+				err = fmt.Errorf("unknown secret provider %s", ref[:i])
+				return ""
+			}
+			v, errSecret := p.Secret(ref[i+1:])
+			if errSecret != nil {
+				err = fmt.Errorf("getting secret %s failed: %w", ref, errSecret)
+				return ""
+			}
+			return v
+		}
+
+		// Environment variable
+		v, ok := lookupEnv(ref)
+		if !ok {
+			err = fmt.Errorf("environment variable %s is not set", ref)
+			return ""
+		}
+		return v
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
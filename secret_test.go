@@ -0,0 +1,103 @@
+package astiencoder
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedVaultReader map[string]map[string]interface{}
+
+func (r mockedVaultReader) ReadSecret(path string) (map[string]interface{}, error) {
+	d, ok := r[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return d, nil
+}
+
+func TestWorkflowDefinitionSubstitute(t *testing.T) {
+	// Create file
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "key"), []byte("file-key\n"), 0600)
+	assert.NoError(t, err)
+
+	// Substitute
+	o := SubstitutionOptions{
+		LookupEnv: func(name string) (string, bool) {
+			if name == "HOST" {
+				return "host", true
+			}
+			return "", false
+		},
+		SecretProviders: map[string]SecretProvider{
+			SecretProviderNameVault: NewVaultSecretProvider(mockedVaultReader{
+				"secret/data/rtmp": {"data": map[string]interface{}{"key": "vault-key"}},
+			}),
+		},
+	}
+	d := WorkflowDefinition{Nodes: []NodeDefinition{{Name: "n", Options: map[string]interface{}{
+		"escaped": "$${HOST}",
+		"file":    "${file:" + filepath.Join(dir, "key") + "}",
+		"url":     "rtmp://${HOST}/live/${vault:secret/data/rtmp#key}",
+	}}}}
+	r, err := d.Substitute(o)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"escaped": "${HOST}",
+		"file":    "file-key",
+		"url":     "rtmp://host/live/vault-key",
+	}, r.Nodes[0].Options)
+	assert.Equal(t, "rtmp://${HOST}/live/${vault:secret/data/rtmp#key}", d.Nodes[0].Options["url"])
+
+	// Errors
+	for _, c := range []struct {
+		err string
+		s   string
+	}{
+		{err: "astiencoder: nodes[0].options.url: environment variable PORT is not set", s: "${PORT}"},
+		{err: "astiencoder: nodes[0].options.url: unknown secret provider env", s: "${env:HOST}"},
+		{err: "astiencoder: nodes[0].options.url: getting secret vault:secret/data/rtmp#unknown failed: astiencoder: vault secret secret/data/rtmp has no key unknown", s: "${vault:secret/data/rtmp#unknown}"},
+	} {
+		d.Nodes[0].Options = map[string]interface{}{"url": c.s}
+		_, err = d.Substitute(o)
+		assert.EqualError(t, err, c.err)
+	}
+}
+
+func TestResolveSecretReferences(t *testing.T) {
+	// Providers
+	os.Setenv("ASTIENCODER_TEST_SECRET", "env-key")
+	defer os.Unsetenv("ASTIENCODER_TEST_SECRET")
+	ps := NewDefaultSecretProviders()
+	v, err := ps.Secret("env:ASTIENCODER_TEST_SECRET")
+	assert.NoError(t, err)
+	assert.Equal(t, "env-key", v)
+	_, err = ps.Secret("env")
+	assert.EqualError(t, err, "astiencoder: secret reference env has no provider")
+	_, err = ps.Secret("unknown:key")
+	assert.EqualError(t, err, "astiencoder: unknown secret provider unknown")
+
+	// Resolve
+	v, err = ResolveSecretReferences("rtmp://host/live/${secret:env:ASTIENCODER_TEST_SECRET}", ps)
+	assert.NoError(t, err)
+	assert.Equal(t, "rtmp://host/live/env-key", v)
+	v, err = ResolveSecretReferences("rtmp://host/live", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "rtmp://host/live", v)
+	_, err = ResolveSecretReferences("${secret:env:ASTIENCODER_TEST_UNKNOWN}", ps)
+	assert.EqualError(t, err, "astiencoder: getting secret env:ASTIENCODER_TEST_UNKNOWN failed: astiencoder: environment variable ASTIENCODER_TEST_UNKNOWN is not set")
+	_, err = ResolveSecretReferences("${secret:env:ASTIENCODER_TEST_SECRET}", nil)
+	assert.EqualError(t, err, "astiencoder: no secret provider")
+
+	// References are kept when substituting definitions
+	r, err := WorkflowDefinition{Name: "${secret:env:KEY}"}.Substitute(SubstitutionOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "${secret:env:KEY}", r.Name)
+}
@@ -0,0 +1,80 @@
+package astiencoder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Seeker represents a node capable of seeking its input, e.g. a demuxer reading a file
+type Seeker interface {
+	// Seek seeks the input to a position relative to its start
+	Seek(position time.Duration) error
+}
+
+// Seek seeks the inputs of the workflow to a position relative to their start. If a node name is provided, only this
+// node is seeked, otherwise every node implementing the Seeker interface is
+// Nodes downstream receive the data following the new position, with timestamps going backward or forward
+func (w *Workflow) Seek(nodeName string, position time.Duration) error {
+	return w.seek(nodeName, position, "")
+}
+
+// Seek seeks the inputs of the workflow to a position relative to their start
+func (c *WorkflowController) Seek(nodeName string, position time.Duration) error {
+	return c.w.seek(nodeName, position, c.actor)
+}
+
+func (w *Workflow) seek(nodeName string, position time.Duration, actor string) (err error) {
+	// Get params
+	params := map[string]interface{}{"position": position.Seconds()}
+	if nodeName != "" {
+		params["node"] = nodeName
+	}
+
+	// Get nodes
+	var ns []Node
+	if nodeName != "" {
+		n, ok := w.indexedNodes()[nodeName]
+		if !ok {
+			err = nodeNotFoundError(nodeName)
+			w.audit(nil, actor, AuditActionSeek, params, err)
+			return
+		}
+		ns = append(ns, n)
+	} else {
+		for _, n := range w.nodes() {
+			if _, ok := n.(Seeker); ok {
+				ns = append(ns, n)
+			}
+		}
+		sort.Slice(ns, func(i, j int) bool { return ns[i].Metadata().Name < ns[j].Metadata().Name })
+	}
+
+	// No seekable nodes
+	if len(ns) == 0 {
+		err = errors.New("astiencoder: workflow has no node that can seek")
+		w.audit(nil, actor, AuditActionSeek, params, err)
+		return
+	}
+
+	// Loop through nodes
+	for _, n := range ns {
+		// Node can't seek
+		s, ok := n.(Seeker)
+		if !ok {
+			err = fmt.Errorf("astiencoder: node %s can't seek", n.Metadata().Name)
+			w.audit(n, actor, AuditActionSeek, params, err)
+			return
+		}
+
+		// Seek
+		if err = s.Seek(position); err != nil {
+			err = fmt.Errorf("astiencoder: seeking node %s failed: %w", n.Metadata().Name, err)
+			w.audit(n, actor, AuditActionSeek, params, err)
+			return
+		}
+		w.audit(n, actor, AuditActionSeek, params, nil)
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedSeekerNode struct {
+	*mockedStatsNode
+	err      error
+	position time.Duration
+}
+
+func (n *mockedSeekerNode) Seek(position time.Duration) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.position = position
+	return nil
+}
+
+func TestWorkflowSeek(t *testing.T) {
+	// Create workflow
+	eh := NewEventHandler()
+	var as []AuditEntry
+	eh.AddForEventName(EventNameAudit, func(e Event) bool {
+		as = append(as, e.Payload.(AuditEntry))
+		return false
+	})
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+
+	// No node can seek
+	n := newMockedStatsNode("n", eh)
+	w.AddChild(n)
+	assert.EqualError(t, w.Seek("", time.Second), "astiencoder: workflow has no node that can seek")
+	assert.EqualError(t, w.Seek("n", time.Second), "astiencoder: node n can't seek")
+	err := w.Seek("unknown", time.Second)
+	assert.True(t, errors.Is(err, ErrNodeNotFound))
+	assert.EqualError(t, err, "astiencoder: node unknown doesn't exist")
+
+	// Seek
+	s1 := &mockedSeekerNode{mockedStatsNode: newMockedStatsNode("s1", eh)}
+	s2 := &mockedSeekerNode{mockedStatsNode: newMockedStatsNode("s2", eh)}
+	w.AddChild(s1)
+	w.AddChild(s2)
+	as = []AuditEntry{}
+	assert.NoError(t, w.Controller("alice").Seek("", 2*time.Second))
+	assert.Equal(t, 2*time.Second, s1.position)
+	assert.Equal(t, 2*time.Second, s2.position)
+	assert.Len(t, as, 2)
+	assert.Equal(t, AuditActionSeek, as[0].Action)
+	assert.Equal(t, "alice", as[0].Actor)
+	assert.Equal(t, "s1", as[0].Node)
+	assert.Equal(t, map[string]interface{}{"position": 2.0}, as[0].Params)
+	s2.err = errors.New("test")
+	assert.EqualError(t, w.Seek("s2", time.Second), "astiencoder: seeking node s2 failed: test")
+	assert.Equal(t, "astiencoder: seeking node s2 failed: test", as[len(as)-1].Error)
+}
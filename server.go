@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/asticode/go-astikit"
 	"github.com/asticode/go-astiws"
@@ -13,19 +15,44 @@ import (
 )
 
 type Server struct {
-	l  astikit.SeverityLogger
+	a  *Alerter
+	al *AuditLog
+	h  *Health
+	jq *JobQueue
+	l  Logger
+	m  *Metrics
+	p  *Profiler
 	w  *Workflow
 	ws *astiws.Manager
 }
 
 type ServerOptions struct {
-	Logger astikit.StdLogger
+	// If set, the alerts currently firing are served under /alerts
+	Alerter *Alerter
+	// If set, the control operations performed on the workflow are served under /audit
+	AuditLog *AuditLog
+	// If set, the health report is served under /health
+	Health *Health
+	// If set, jobs are served under /jobs. Since the server is not authenticated, jobs can only be added and cancelled
+	// through the JobQueue option of the ControlService
+	JobQueue *JobQueue
+	Logger   Logger
+	// If set, Prometheus metrics are served under /metrics
+	Metrics *Metrics
+	// If set, pprof endpoints are served under /debug/pprof/
+	Profiler *Profiler
 }
 
 func NewServer(o ServerOptions) *Server {
 	return &Server{
-		l:  astikit.AdaptStdLogger(o.Logger),
-		ws: astiws.NewManager(astiws.ManagerConfiguration{MaxMessageSize: 8192}, o.Logger),
+		a:  o.Alerter,
+		al: o.AuditLog,
+		h:  o.Health,
+		jq: o.JobQueue,
+		l:  logger(o.Logger),
+		m:  o.Metrics,
+		p:  o.Profiler,
+		ws: astiws.NewManager(astiws.ManagerConfiguration{MaxMessageSize: 8192}, loggerStdLogger{l: logger(o.Logger)}),
 	}
 }
 
@@ -39,9 +66,45 @@ func (s *Server) Handler() http.Handler {
 
 	// Add routes
 	r.Handler(http.MethodGet, "/", s.serveHomepage())
+	r.Handler(http.MethodGet, "/definition", s.serveDefinition())
 	r.Handler(http.MethodGet, "/ok", s.serveOK())
+	r.Handler(http.MethodGet, "/progress", s.serveProgress())
+	r.Handler(http.MethodGet, "/snapshot", s.serveSnapshot())
+	r.Handler(http.MethodGet, "/stats/history", s.serveStatsHistory())
 	r.Handler(http.MethodGet, "/websocket", s.serveWebSocket())
 	r.Handler(http.MethodGet, "/welcome", s.serveWelcome())
+
+	// Add alerts route
+	if s.a != nil {
+		r.Handler(http.MethodGet, "/alerts", s.serveAlerts())
+	}
+
+	// Add audit route
+	if s.al != nil {
+		r.Handler(http.MethodGet, "/audit", s.serveAudit())
+	}
+
+	// Add health route
+	if s.h != nil {
+		r.Handler(http.MethodGet, "/health", s.h.Handler())
+	}
+
+	// Add jobs routes
+	if s.jq != nil {
+		r.Handler(http.MethodGet, "/jobs", s.serveJobs())
+	}
+
+	// Add metrics route
+	if s.m != nil {
+		r.Handler(http.MethodGet, "/metrics", s.m.Handler())
+	}
+
+	// Add profiling routes
+	if s.p != nil {
+		h := s.p.Handler()
+		r.Handler(http.MethodGet, "/debug/pprof/*name", h)
+		r.Handler(http.MethodPost, "/debug/pprof/*name", h)
+	}
 	return r
 }
 
@@ -55,7 +118,7 @@ func (s *Server) serveWebSocket() http.Handler {
 			var e *websocket.CloseError
 			if ok := errors.As(err, &e); !ok ||
 				(e.Code != websocket.CloseNoStatusReceived && e.Code != websocket.CloseNormalClosure) {
-				s.l.Error(fmt.Errorf("astiencoder: handling websocket failed: %w", err))
+				s.l.Error("astiencoder: handling websocket failed", LogField{Key: LogFieldError, Value: err})
 			}
 			return
 		}
@@ -79,7 +142,7 @@ func (s *Server) webSocketDisconnected(c *astiws.Client, eventName string, paylo
 
 func (s *Server) webSocketPing(c *astiws.Client, eventName string, payload json.RawMessage) error {
 	if err := c.ExtendConnection(); err != nil {
-		s.l.Error(fmt.Errorf("astiencoder: extending ws connection failed: %w", err))
+		s.l.Error("astiencoder: extending ws connection failed", LogField{Key: LogFieldError, Value: err})
 	}
 	return nil
 }
@@ -88,34 +151,54 @@ func (s *Server) sendWebSocket(eventName string, payload interface{}) {
 	// Loop through clients
 	s.ws.Loop(func(_ interface{}, c *astiws.Client) {
 		if err := c.Write(eventName, payload); err != nil {
-			s.l.Error(fmt.Errorf("astiencoder: writing event %s with payload %+v to websocket client %p failed: %w", eventName, payload, c, err))
+			s.l.Error(fmt.Sprintf("astiencoder: writing event %s with payload %+v to websocket client %p failed", eventName, payload, c), LogField{Key: LogFieldError, Value: err})
 			return
 		}
 	})
 }
 
+const serverEventBufferSize = 1000
+
 func serverEventHandlerAdapter(eh *EventHandler, fn func(name string, payload interface{})) {
 	// Register catch all handler
-	eh.AddForAll(func(e Event) bool {
-		// Get payload
-		var p interface{}
-		switch e.Name {
-		case EventNameError:
-			p = astikit.ErrorCause(e.Payload.(error))
-		case EventNameNodeStats, EventNameWorkflowStats:
-			p = newServerStats(e)
-		case EventNameNodeContinued, EventNameNodePaused, EventNameNodeStopped:
-			p = e.Target.(Node).Metadata().Name
-		case EventNameNodeStarted:
-			p = newServerNode(e.Target.(Node))
-		}
-
-		// Custom
-		fn(e.Name, p)
+	// It is buffered so that slow websocket clients don't slow down the workflows
+	eh.AddForAllBuffered(serverEventBufferSize, func(e Event) bool {
+		fn(e.Name, serverEventPayload(e))
 		return false
 	})
 }
 
+// serverEventPayload returns the JSON friendly payload of an event
+func serverEventPayload(e Event) (p interface{}) {
+	switch e.Name {
+	case EventNameAlertCleared, EventNameAlertFired:
+		p = newServerAlert(e.Payload.(Alert))
+	case EventNameAudit:
+		p = newServerAuditEntry(e.Payload.(AuditEntry))
+	case EventNameClusterInstanceLost, EventNameClusterWorkflowPlaced:
+		p = e.Payload
+	case EventNameError:
+		p = astikit.ErrorCause(e.Payload.(error))
+	case EventNameJobUpdated:
+		p = newServerJob(e.Payload.(JobState))
+	case EventNameNodeStats, EventNameWorkflowStats:
+		p = newServerStats(e)
+	case EventNameWorkflowHeartbeat:
+		p = newServerHeartbeat(e.Target.(*Workflow).Name(), e.Payload.(WorkflowHeartbeat))
+	case EventNameWebhookDeliveryFailed, EventNameWorkflowPatched:
+		p = e.Payload
+	case EventNameWorkflowProgress:
+		p = newServerProgress(e.Target.(*Workflow).Name(), e.Payload.(WorkflowProgress))
+	case EventNameWorkflowStatsAggregated:
+		p = newServerAggregatedStats(e)
+	case EventNameNodeContinued, EventNameNodePaused, EventNameNodeReconfigured, EventNameNodeStopped:
+		p = e.Target.(Node).Metadata().Name
+	case EventNameNodeStarted:
+		p = newServerNode(e.Target.(Node))
+	}
+	return
+}
+
 func (s *Server) EventHandlerAdapter(eh *EventHandler) {
 	serverEventHandlerAdapter(eh, s.sendWebSocket)
 }
@@ -213,6 +296,28 @@ func newServerStats(e Event) (s ServerStats) {
 	return
 }
 
+type ServerAggregatedStats struct {
+	Name  string        `json:"name"`
+	Nodes []ServerStats `json:"nodes"`
+	Stats []ServerStat  `json:"stats"`
+}
+
+func newServerAggregatedStats(e Event) (s ServerAggregatedStats) {
+	s.Name = e.Target.(*Workflow).Name()
+	p := e.Payload.(WorkflowAggregatedStats)
+	for _, n := range p.Nodes {
+		ns := ServerStats{Name: n.Name}
+		for _, es := range n.Stats {
+			ns.Stats = append(ns.Stats, newServerStat(es))
+		}
+		s.Nodes = append(s.Nodes, ns)
+	}
+	for _, es := range p.Workflow {
+		s.Stats = append(s.Stats, newServerStat(es))
+	}
+	return
+}
+
 type ServerStat struct {
 	Description string      `json:"description"`
 	Label       string      `json:"label"`
@@ -229,10 +334,346 @@ func newServerStat(e EventStat) ServerStat {
 	}
 }
 
+type ServerHeartbeat struct {
+	// Unix timestamp in milliseconds
+	At     int64  `json:"at"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// In seconds
+	Uptime float64 `json:"uptime"`
+}
+
+func newServerHeartbeat(name string, h WorkflowHeartbeat) ServerHeartbeat {
+	return ServerHeartbeat{
+		At:     h.At.UnixNano() / int64(time.Millisecond),
+		Name:   name,
+		Status: h.Status,
+		Uptime: h.Uptime.Seconds(),
+	}
+}
+
+type ServerProgress struct {
+	// In seconds
+	Duration float64 `json:"duration"`
+	// In seconds
+	ETA  float64 `json:"eta"`
+	Name string  `json:"name"`
+	// In seconds
+	Position   float64 `json:"position"`
+	Ratio      float64 `json:"ratio"`
+	Throughput float64 `json:"throughput"`
+}
+
+func newServerProgress(name string, p WorkflowProgress) ServerProgress {
+	return ServerProgress{
+		Duration:   p.Duration.Seconds(),
+		ETA:        p.ETA.Seconds(),
+		Name:       name,
+		Position:   p.Position.Seconds(),
+		Ratio:      p.Ratio,
+		Throughput: p.Throughput,
+	}
+}
+
+// serveProgress serves the progress of the workflow, or a 404 if the duration of its inputs is unknown
+func (s *Server) serveProgress() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get progress
+		if s.w == nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		p, ok := s.w.Progress()
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(newServerProgress(s.w.Name(), p)); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+type ServerSnapshot struct {
+	// Unix timestamp in milliseconds
+	At       int64                `json:"at"`
+	Name     string               `json:"name"`
+	Nodes    []ServerSnapshotNode `json:"nodes"`
+	Progress *ServerProgress      `json:"progress,omitempty"`
+	Stats    []ServerStat         `json:"stats"`
+	Status   string               `json:"status"`
+}
+
+type ServerSnapshotNode struct {
+	ServerNode
+	Stats []ServerStat `json:"stats"`
+}
+
+func newServerSnapshot(s WorkflowSnapshot) (ss ServerSnapshot) {
+	// Create snapshot
+	ss = ServerSnapshot{
+		At:     s.At.UnixNano() / int64(time.Millisecond),
+		Name:   s.Name,
+		Nodes:  []ServerSnapshotNode{},
+		Stats:  newServerStatsList(s.Stats),
+		Status: s.Status,
+	}
+
+	// Add progress
+	if s.Progress != nil {
+		p := newServerProgress(s.Name, *s.Progress)
+		ss.Progress = &p
+	}
+
+	// Loop through nodes
+	for _, n := range s.Nodes {
+		ss.Nodes = append(ss.Nodes, ServerSnapshotNode{
+			ServerNode: ServerNode{
+				Children:    n.Children,
+				Description: n.Metadata.Description,
+				Label:       n.Metadata.Label,
+				Name:        n.Metadata.Name,
+				Parents:     n.Parents,
+				Status:      n.Status,
+				Tags:        n.Metadata.Tags,
+			},
+			Stats: newServerStatsList(n.Stats),
+		})
+	}
+	return
+}
+
+func newServerStatsList(es []EventStat) (ss []ServerStat) {
+	ss = []ServerStat{}
+	for _, e := range es {
+		ss = append(ss, newServerStat(e))
+	}
+	return
+}
+
+// serveDefinition serves the definition of the workflow
+func (s *Server) serveDefinition() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// No workflow
+		if s.w == nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Export definition
+		d, err := s.w.ExportDefinition()
+		if err != nil {
+			s.l.Error("astiencoder: exporting definition failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(d); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+// serveSnapshot serves the topology, status and last stats of the workflow and of all its nodes
+func (s *Server) serveSnapshot() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// No workflow
+		if s.w == nil {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(newServerSnapshot(s.w.Snapshot())); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+type ServerAlert struct {
+	Node string `json:"node"`
+	Rule string `json:"rule"`
+	// Unix timestamp in milliseconds
+	Since    int64   `json:"since"`
+	Value    float64 `json:"value"`
+	Workflow string  `json:"workflow,omitempty"`
+}
+
+func newServerAlert(a Alert) ServerAlert {
+	return ServerAlert{
+		Node:     a.Node,
+		Rule:     a.Rule.Name,
+		Since:    a.Since.UnixNano() / int64(time.Millisecond),
+		Value:    a.Value,
+		Workflow: a.Workflow,
+	}
+}
+
+// serveAlerts serves the alerts currently firing
+func (s *Server) serveAlerts() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get alerts
+		as := []ServerAlert{}
+		for _, a := range s.a.Alerts() {
+			as = append(as, newServerAlert(a))
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(as); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+type ServerAuditEntry struct {
+	Action string `json:"action"`
+	Actor  string `json:"actor,omitempty"`
+	// Unix timestamp in milliseconds
+	At       int64                  `json:"at"`
+	Error    string                 `json:"error,omitempty"`
+	Node     string                 `json:"node,omitempty"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Workflow string                 `json:"workflow"`
+}
+
+func newServerAuditEntry(e AuditEntry) ServerAuditEntry {
+	return ServerAuditEntry{
+		Action:   e.Action,
+		Actor:    e.Actor,
+		At:       e.At.UnixNano() / int64(time.Millisecond),
+		Error:    e.Error,
+		Node:     e.Node,
+		Params:   e.Params,
+		Workflow: e.Workflow,
+	}
+}
+
+// serveAudit serves the control operations performed on the workflow whose name is provided in the "workflow" query
+// param (the server's workflow if empty), oldest first
+func (s *Server) serveAudit() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get workflow name
+		name := r.URL.Query().Get("workflow")
+		if name == "" {
+			if s.w == nil {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			name = s.w.Name()
+		}
+
+		// Get entries
+		es := []ServerAuditEntry{}
+		for _, e := range s.al.Entries(name) {
+			es = append(es, newServerAuditEntry(e))
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(es); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
+type ServerJob struct {
+	// Unix timestamp in milliseconds
+	AddedAt  int64  `json:"added_at"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	// Unix timestamp in milliseconds. 0 if the job has not been run yet
+	StartedAt int64  `json:"started_at,omitempty"`
+	Status    string `json:"status"`
+	// Unix timestamp in milliseconds. 0 if the job is not done yet
+	StoppedAt int64 `json:"stopped_at,omitempty"`
+}
+
+func newServerJob(s JobState) (j ServerJob) {
+	j = ServerJob{
+		AddedAt:  s.AddedAt.UnixNano() / int64(time.Millisecond),
+		Attempts: s.Attempts,
+		Error:    s.Error,
+		ID:       s.ID,
+		Name:     s.Name,
+		Priority: s.Priority,
+		Status:   s.Status,
+	}
+	if !s.StartedAt.IsZero() {
+		j.StartedAt = s.StartedAt.UnixNano() / int64(time.Millisecond)
+	}
+	if !s.StoppedAt.IsZero() {
+		j.StoppedAt = s.StoppedAt.UnixNano() / int64(time.Millisecond)
+	}
+	return
+}
+
+// serveJobs serves the state of the jobs
+func (s *Server) serveJobs() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Get jobs
+		js := []ServerJob{}
+		for _, j := range s.jq.Jobs() {
+			js = append(js, newServerJob(j))
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(js); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
 type ServerWelcome struct {
 	Workflow *ServerWorkflow `json:"workflow,omitempty"`
 }
 
+// serveStatsHistory serves the stats history of the node whose name is provided in the "node" query param (the
+// workflow's own stats if empty) since the unix timestamp in milliseconds provided in the "since" query param
+func (s *Server) serveStatsHistory() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Parse since
+		var since time.Time
+		if v := r.URL.Query().Get("since"); v != "" {
+			ms, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			since = time.Unix(0, ms*int64(time.Millisecond))
+		}
+
+		// Get history
+		ss := []StatsSample{}
+		if s.w != nil {
+			ss = append(ss, s.w.StatsHistory(r.URL.Query().Get("node"), since)...)
+		}
+
+		// Write
+		if err := json.NewEncoder(rw).Encode(ss); err != nil {
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	})
+}
+
 func (s *Server) serveWelcome() http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		// Create body
@@ -243,7 +684,7 @@ func (s *Server) serveWelcome() http.Handler {
 
 		// Write
 		if err := json.NewEncoder(rw).Encode(b); err != nil {
-			s.l.Error(fmt.Errorf("astiencoder: writing failed: %w", err))
+			s.l.Error("astiencoder: writing failed", LogField{Key: LogFieldError, Value: err})
 			rw.WriteHeader(http.StatusInternalServerError)
 			return
 		}
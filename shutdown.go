@@ -0,0 +1,108 @@
+package astiencoder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Shutdowner represents an object capable of shutting down gracefully within the context's deadline
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ShutdownerFunc is an adapter allowing a function to be used as a Shutdowner
+type ShutdownerFunc func(ctx context.Context) error
+
+// Shutdown implements the Shutdowner interface
+func (f ShutdownerFunc) Shutdown(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Shutdown shuts down the shutdowners one after the other, in order, within the context's deadline, e.g. when the
+// process receives a SIGTERM during a rolling deploy. Producers must be provided first so that the events they emit
+// while draining are flushed by the event sinks provided after them:
+//
+//	astiencoder.Shutdown(ctx, controlService, jobQueue, webhookDispatcher, errorReporter)
+//
+// Every shutdowner is shut down, even if a previous one has failed, and the first error is returned
+func Shutdown(ctx context.Context, ss ...Shutdowner) (err error) {
+	for _, s := range ss {
+		if errShutdown := s.Shutdown(ctx); errShutdown != nil && err == nil {
+			err = errShutdown
+		}
+	}
+	return
+}
+
+// Shutdown stops the workflow, which lets its nodes flush what they're processing, and waits for it to be stopped
+// within the context's deadline
+func (w *Workflow) Shutdown(ctx context.Context) error {
+	return w.shutdown(ctx, "")
+}
+
+func (w *Workflow) shutdown(ctx context.Context, actor string) (err error) {
+	// Wait for the workflow to be stopped. The listener is deleted on every exit path since the stopped event may
+	// never be received
+	stopped := make(chan struct{})
+	o := &sync.Once{}
+	l := w.e.add(eventHandlerKey{eventName: EventNameWorkflowStopped, target: eventDefaultTarget}, func(e Event) bool {
+		if e.Target != w {
+			return false
+		}
+		o.Do(func() { close(stopped) })
+		return true
+	}, 0)
+	defer w.e.del(l)
+
+	// Workflow is already stopped
+	if w.Status() == StatusStopped {
+		return
+	}
+
+	// Stop
+	w.stop(actor)
+
+	// Wait
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = fmt.Errorf("astiencoder: waiting for workflow %s to be stopped failed: %w", w.name, ctx.Err())
+	}
+	return
+}
+
+// Shutdown stops accepting new workflows, stops the running and paused workflows, which lets their nodes flush
+// what they're processing, and waits for them to be stopped within the context's deadline. Once called, creating or
+// starting a workflow returns an error wrapping ErrShuttingDown
+func (s *ControlService) Shutdown(ctx context.Context) (err error) {
+	// Stop accepting new workflows
+	s.m.Lock()
+	s.closing = true
+	var ws []*Workflow
+	for _, cw := range s.ws {
+		ws = append(ws, cw.w)
+	}
+	s.m.Unlock()
+
+	// Loop through workflows
+	var wg sync.WaitGroup
+	var m sync.Mutex
+	for _, w := range ws {
+		wg.Add(1)
+		go func(w *Workflow) {
+			defer wg.Done()
+			if errShutdown := w.shutdown(ctx, ""); errShutdown != nil {
+				m.Lock()
+				if err == nil {
+					err = errShutdown
+				}
+				m.Unlock()
+			}
+		}(w)
+	}
+
+	// Wait
+	wg.Wait()
+	return
+}
@@ -0,0 +1,77 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockedShutdownNode runs until it's stopped or, if set, until done is closed
+type mockedShutdownNode struct {
+	*mockedStatsNode
+	done <-chan struct{}
+}
+
+func (n *mockedShutdownNode) Start(ctx context.Context, t CreateTaskFunc) {
+	n.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		select {
+		case <-n.Context().Done():
+		case <-n.done:
+		}
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	var calls []string
+	s := func(name string, err error) Shutdowner {
+		return ShutdownerFunc(func(ctx context.Context) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+	err := Shutdown(context.Background(), s("1", nil), s("2", errors.New("2")), s("3", errors.New("3")))
+	assert.EqualError(t, err, "2")
+	assert.Equal(t, []string{"1", "2", "3"}, calls)
+}
+
+func TestControlServiceShutdown(t *testing.T) {
+	// Create service
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return &mockedShutdownNode{mockedStatsNode: newMockedStatsNode(b.Node.Metadata.Name, NewEventHandler())}, nil
+	}})
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	s := NewControlService(ControlServiceOptions{Build: BuildWorkflowOptions{TaskFunc: wk.NewTask, Types: ts}})
+	d := func(name string) WorkflowDefinition {
+		return WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: "n", Type: "t"}}, Version: DefinitionVersion}
+	}
+	_, err := s.CreateWorkflow("alice", d("w1"), nil)
+	assert.NoError(t, err)
+	_, err = s.CreateWorkflow("alice", d("w2"), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, s.StartWorkflow("alice", "w1"))
+
+	// Shut down
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Shutdown(ctx))
+	assert.Equal(t, []ControlWorkflow{{Name: "w1", Status: StatusStopped}, {Name: "w2", Status: StatusStopped}}, s.Workflows())
+
+	// New workflows are not accepted anymore
+	_, err = s.CreateWorkflow("alice", d("w3"), nil)
+	assert.True(t, errors.Is(err, ErrShuttingDown))
+	assert.True(t, errors.Is(s.StartWorkflow("alice", "w2"), ErrShuttingDown))
+}
+
+func TestWorkflowShutdown(t *testing.T) {
+	// Workflow is already stopped and its listener is deleted
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	assert.NoError(t, w.Shutdown(context.Background()))
+	assert.Len(t, eh.snapshot().ls[eventHandlerKey{eventName: EventNameWorkflowStopped, target: eventDefaultTarget}], 0)
+}
@@ -0,0 +1,80 @@
+package astiencoder
+
+import (
+	"sort"
+	"time"
+)
+
+// WorkflowSnapshot represents the topology, status and last stats of a workflow and of all its nodes at a point in
+// time
+type WorkflowSnapshot struct {
+	At    time.Time
+	Name  string
+	Nodes []NodeSnapshot
+	// Only set if the duration of the workflow's inputs is known
+	Progress *WorkflowProgress
+	Stats    []EventStat
+	Status   string
+}
+
+// NodeSnapshot represents the topology, status and last stats of a node at a point in time
+type NodeSnapshot struct {
+	// Names of the node's children
+	Children []string
+	Metadata NodeMetadata
+	// Names of the node's parents
+	Parents []string
+	Stats   []EventStat
+	Status  string
+}
+
+// Snapshot returns the state of the whole workflow in one structure so that UIs can render it in one call
+// Nodes are sorted by name
+func (w *Workflow) Snapshot() (s WorkflowSnapshot) {
+	// Create snapshot
+	s = WorkflowSnapshot{
+		At:     time.Now(),
+		Name:   w.name,
+		Nodes:  []NodeSnapshot{},
+		Stats:  w.bn.Stats(),
+		Status: w.Status(),
+	}
+
+	// Add progress
+	if p, ok := w.Progress(); ok {
+		s.Progress = &p
+	}
+
+	// Loop through nodes
+	for _, n := range w.nodes() {
+		// Create node snapshot
+		ns := NodeSnapshot{
+			Children: []string{},
+			Metadata: n.Metadata(),
+			Parents:  []string{},
+			Status:   n.Status(),
+		}
+
+		// Add stats
+		if v, ok := n.(nodeStatser); ok {
+			ns.Stats = v.Stats()
+		}
+
+		// Add children
+		for _, c := range n.Children() {
+			ns.Children = append(ns.Children, c.Metadata().Name)
+		}
+
+		// Add parents
+		for _, p := range n.Parents() {
+			ns.Parents = append(ns.Parents, p.Metadata().Name)
+		}
+
+		// Append
+		s.Nodes = append(s.Nodes, ns)
+	}
+
+	// Sort nodes
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Metadata.Name < s.Nodes[j].Metadata.Name })
+	return
+}
@@ -0,0 +1,44 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowSnapshot(t *testing.T) {
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+
+	n1 := newMockedStatsNode("n1", eh)
+	n2 := newMockedStatsNode("n2", eh)
+	n2.o.Metadata.Tags = []string{"t"}
+	w.AddChild(n1)
+	ConnectNodes(n1, n2)
+	n2.statsHandleFunc([]astikit.Stat{{StatMetadata: astikit.StatMetadata{Label: "Outgoing rate", Unit: "fps"}, Value: 25.0}})
+	w.bn.statsHandleFunc([]astikit.Stat{{StatMetadata: astikit.StatMetadata{Label: "Memory usage"}, Value: 1.0}})
+
+	s := w.Snapshot()
+	assert.False(t, s.At.IsZero())
+	assert.Equal(t, "w", s.Name)
+	assert.Nil(t, s.Progress)
+	assert.Equal(t, []EventStat{{Label: "Memory usage", Value: 1.0}}, s.Stats)
+	assert.Equal(t, StatusStopped, s.Status)
+	assert.Equal(t, []NodeSnapshot{
+		{
+			Children: []string{"n2"},
+			Metadata: NodeMetadata{Name: "n1"},
+			Parents:  []string{},
+			Status:   StatusStopped,
+		},
+		{
+			Children: []string{},
+			Metadata: NodeMetadata{Name: "n2", Tags: []string{"t"}},
+			Parents:  []string{"n1"},
+			Stats:    []EventStat{{Label: "Outgoing rate", Unit: "fps", Value: 25.0}},
+			Status:   StatusStopped,
+		},
+	}, s.Nodes)
+}
@@ -0,0 +1,66 @@
+package astiencoder
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// WorkflowAggregatedStats represents the last stats of a workflow and of all its nodes
+type WorkflowAggregatedStats struct {
+	Nodes    []NodeStats
+	Workflow []EventStat
+}
+
+// NodeStats represents the last stats of a node
+type NodeStats struct {
+	Name  string
+	Stats []EventStat
+}
+
+type nodeStatser interface {
+	Stats() []EventStat
+}
+
+func (w *Workflow) startStats(ctx context.Context, period time.Duration) {
+	// Create ticker
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			w.e.Emit(Event{Name: EventNameWorkflowStatsAggregated, Payload: w.aggregatedStats(), Target: w})
+			if p, ok := w.Progress(); ok {
+				w.e.Emit(Event{Name: EventNameWorkflowProgress, Payload: p, Target: w})
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Workflow) aggregatedStats() (s WorkflowAggregatedStats) {
+	// Add workflow stats
+	s.Workflow = w.bn.Stats()
+
+	// Loop through nodes
+	for _, n := range w.nodes() {
+		// Node doesn't store its stats
+		v, ok := n.(nodeStatser)
+		if !ok {
+			continue
+		}
+
+		// Append
+		s.Nodes = append(s.Nodes, NodeStats{
+			Name:  n.Metadata().Name,
+			Stats: v.Stats(),
+		})
+	}
+
+	// Sort nodes
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Name < s.Nodes[j].Name })
+	return
+}
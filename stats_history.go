@@ -0,0 +1,120 @@
+package astiencoder
+
+import (
+	"sync"
+	"time"
+)
+
+const statsHistorySizeDefault = 300
+
+// StatsHistoryOptions represents stats history options
+type StatsHistoryOptions struct {
+	// Max number of samples kept per node. Default is 300, which is 10 minutes with the default stats period
+	Size int
+}
+
+// StatsSample represents the stats of a node at a point in time
+type StatsSample struct {
+	At    time.Time   `json:"at"`
+	Stats []EventStat `json:"stats"`
+}
+
+type statsHistory struct {
+	m  *sync.Mutex
+	o  StatsHistoryOptions
+	rs map[string]*statsRing
+}
+
+// statsRing is a ring buffer of samples, oldest first
+type statsRing struct {
+	ss    []StatsSample
+	start int
+}
+
+// SetStatsHistory makes the workflow keep an in-memory history of the stats of its nodes and of its own stats, so
+// that sparklines can be drawn without external time-series storage
+// It must be called before the workflow is started
+func (w *Workflow) SetStatsHistory(o StatsHistoryOptions) {
+	// Default options
+	if o.Size <= 0 {
+		o.Size = statsHistorySizeDefault
+	}
+
+	// Create history
+	h := &statsHistory{
+		m:  &sync.Mutex{},
+		o:  o,
+		rs: make(map[string]*statsRing),
+	}
+	w.sh = h
+
+	// Handle node stats
+	w.e.AddForEventName(EventNameNodeStats, func(e Event) bool {
+		// Node doesn't belong to the workflow
+		n, ok := e.Target.(Node)
+		if !ok || nodeWorkflowName(n) != w.name {
+			return false
+		}
+
+		// Add
+		h.add(n.Metadata().Name, StatsSample{At: time.Now(), Stats: e.Payload.([]EventStat)})
+		return false
+	})
+
+	// Handle workflow stats
+	w.e.Add(w, EventNameWorkflowStats, func(e Event) bool {
+		h.add("", StatsSample{At: time.Now(), Stats: e.Payload.([]EventStat)})
+		return false
+	})
+}
+
+func (h *statsHistory) add(name string, s StatsSample) {
+	// Lock
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	// Get ring
+	r, ok := h.rs[name]
+	if !ok {
+		r = &statsRing{}
+		h.rs[name] = r
+	}
+
+	// Ring is not full yet
+	if len(r.ss) < h.o.Size {
+		r.ss = append(r.ss, s)
+		return
+	}
+
+	// Overwrite oldest sample
+	r.ss[r.start] = s
+	r.start = (r.start + 1) % len(r.ss)
+}
+
+// StatsHistory returns the samples of the node taken after since, oldest first
+// An empty node name returns the samples of the workflow's own stats. Nothing is returned if SetStatsHistory has not
+// been called
+func (w *Workflow) StatsHistory(node string, since time.Time) (ss []StatsSample) {
+	// No history
+	if w.sh == nil {
+		return
+	}
+
+	// Lock
+	w.sh.m.Lock()
+	defer w.sh.m.Unlock()
+
+	// Get ring
+	r, ok := w.sh.rs[node]
+	if !ok {
+		return
+	}
+
+	// Loop through samples
+	for i := 0; i < len(r.ss); i++ {
+		if s := r.ss[(r.start+i)%len(r.ss)]; s.At.After(since) {
+			ss = append(ss, s)
+		}
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package astiencoder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedStatsNode struct {
+	*BaseNode
+}
+
+func newMockedStatsNode(name string, eh *EventHandler) (n *mockedStatsNode) {
+	n = &mockedStatsNode{}
+	n.BaseNode = NewBaseNode(NodeOptions{Metadata: NodeMetadata{Name: name}}, NewEventGeneratorNode(n), eh)
+	return
+}
+
+func (n *mockedStatsNode) Start(ctx context.Context, t CreateTaskFunc) {}
+
+func TestWorkflowStats(t *testing.T) {
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+
+	n1 := newMockedStatsNode("n1", eh)
+	n2 := newMockedStatsNode("n2", eh)
+	w.AddChild(n2)
+	w.AddChild(n1)
+	n1.statsHandleFunc([]astikit.Stat{{StatMetadata: astikit.StatMetadata{Label: "Outgoing rate", Unit: "fps"}, Value: 25.0}})
+	w.bn.statsHandleFunc([]astikit.Stat{{StatMetadata: astikit.StatMetadata{Label: "Memory usage"}, Value: 1.0}})
+
+	assert.Equal(t, WorkflowAggregatedStats{
+		Nodes: []NodeStats{
+			{Name: "n1", Stats: []EventStat{{Label: "Outgoing rate", Unit: "fps", Value: 25.0}}},
+			{Name: "n2"},
+		},
+		Workflow: []EventStat{{Label: "Memory usage", Value: 1.0}},
+	}, w.aggregatedStats())
+}
+
+func TestWorkflowStatsPeriod(t *testing.T) {
+	eh := NewEventHandler()
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	w := NewWorkflow(wk.Context(), "w", eh, wk.NewTask, astikit.NewCloser())
+	n := &mockedTracedNode{
+		mockedStatsNode: newMockedStatsNode("n", eh),
+		started:         make(chan struct{}),
+	}
+	w.AddChild(n)
+	ch := make(chan WorkflowAggregatedStats, 1)
+	eh.AddForEventName(EventNameWorkflowStatsAggregated, func(e Event) bool {
+		select {
+		case ch <- e.Payload.(WorkflowAggregatedStats):
+		default:
+		}
+		return false
+	})
+	w.StartWithOptions(WorkflowStartOptions{StatsPeriod: time.Millisecond})
+	select {
+	case s := <-ch:
+		assert.Equal(t, []NodeStats{{Name: "n"}}, s.Nodes)
+	case <-time.After(time.Second):
+		t.Error("no aggregated stats emitted")
+	}
+	w.Stop()
+	wk.Stop()
+	wk.Wait()
+}
+
+func TestWorkflowStatsHistory(t *testing.T) {
+	eh := NewEventHandler()
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	assert.Nil(t, w.StatsHistory("n", time.Time{}))
+	w.SetStatsHistory(StatsHistoryOptions{Size: 2})
+	n1 := newMockedStatsNode("n", eh)
+	n1.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+	n2 := newMockedStatsNode("n", eh)
+	n2.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w2")
+
+	for i := 0; i < 3; i++ {
+		eh.Emit(Event{Name: EventNameNodeStats, Payload: []EventStat{{Label: "l", Value: float64(i)}}, Target: n1})
+	}
+	eh.Emit(Event{Name: EventNameNodeStats, Payload: []EventStat{{Label: "l", Value: 10.0}}, Target: n2})
+	eh.Emit(Event{Name: EventNameWorkflowStats, Payload: []EventStat{{Label: "l", Value: 20.0}}, Target: w})
+
+	ss := w.StatsHistory("n", time.Time{})
+	assert.Len(t, ss, 2)
+	assert.Equal(t, []EventStat{{Label: "l", Value: 1.0}}, ss[0].Stats)
+	assert.Equal(t, []EventStat{{Label: "l", Value: 2.0}}, ss[1].Stats)
+	assert.Len(t, w.StatsHistory("n", ss[1].At), 0)
+	ss = w.StatsHistory("", time.Time{})
+	assert.Len(t, ss, 1)
+	assert.Equal(t, []EventStat{{Label: "l", Value: 20.0}}, ss[0].Stats)
+}
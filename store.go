@@ -0,0 +1,451 @@
+package astiencoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoredJob represents a job persisted by a store
+type StoredJob struct {
+	Job   Job
+	State JobState
+}
+
+// JobCheckpoint represents the progress of a job, saved so that it can be resumed after a restart
+// Data is opaque to the job queue
+type JobCheckpoint struct {
+	Data  map[string]interface{}
+	JobID string
+	// Set by the job queue
+	SavedAt time.Time
+}
+
+// Store represents an object capable of persisting jobs, their checkpoints and the history of done jobs so that a
+// restarted process can recover its job list
+// Jobs are stored every time their state changes. Done jobs are kept until they exceed the history size of the job
+// queue, in which case they're deleted, as well as their checkpoint
+type Store interface {
+	DelJob(id string) error
+	// ok is false if the job has no checkpoint
+	GetCheckpoint(jobID string) (c JobCheckpoint, ok bool, err error)
+	// ok is false if the job doesn't exist
+	GetJob(id string) (j StoredJob, ok bool, err error)
+	// Jobs must be returned in the order they've been added
+	Jobs() ([]StoredJob, error)
+	PutCheckpoint(c JobCheckpoint) error
+	PutJob(j StoredJob) error
+}
+
+func sortStoredJobs(js []StoredJob) {
+	sort.SliceStable(js, func(i, j int) bool {
+		if !js[i].State.AddedAt.Equal(js[j].State.AddedAt) {
+			return js[i].State.AddedAt.Before(js[j].State.AddedAt)
+		}
+		a, _ := strconv.Atoi(js[i].State.ID)
+		b, _ := strconv.Atoi(js[j].State.ID)
+		return a < b
+	})
+}
+
+// MemoryStore is a store keeping everything in memory. It is useful for tests and for processes that don't need
+// to recover their job list
+type MemoryStore struct {
+	cs map[string]JobCheckpoint
+	js map[string]StoredJob
+	m  *sync.Mutex
+}
+
+// NewMemoryStore creates a new memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		cs: make(map[string]JobCheckpoint),
+		js: make(map[string]StoredJob),
+		m:  &sync.Mutex{},
+	}
+}
+
+// DelJob implements the Store interface
+func (s *MemoryStore) DelJob(id string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	delete(s.cs, id)
+	delete(s.js, id)
+	return nil
+}
+
+// GetCheckpoint implements the Store interface
+func (s *MemoryStore) GetCheckpoint(jobID string) (c JobCheckpoint, ok bool, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	c, ok = s.cs[jobID]
+	return
+}
+
+// GetJob implements the Store interface
+func (s *MemoryStore) GetJob(id string) (j StoredJob, ok bool, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	j, ok = s.js[id]
+	return
+}
+
+// Jobs implements the Store interface
+func (s *MemoryStore) Jobs() (js []StoredJob, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, j := range s.js {
+		js = append(js, j)
+	}
+	sortStoredJobs(js)
+	return
+}
+
+// PutCheckpoint implements the Store interface
+func (s *MemoryStore) PutCheckpoint(c JobCheckpoint) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.cs[c.JobID] = c
+	return nil
+}
+
+// PutJob implements the Store interface
+func (s *MemoryStore) PutJob(j StoredJob) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.js[j.State.ID] = j
+	return nil
+}
+
+// FileStore is a store persisting jobs and checkpoints as JSON files in a directory: jobs in <dir>/jobs/<id>.json
+// and checkpoints in <dir>/checkpoints/<id>.json
+// Files are written atomically so that a crash can't leave a partially written file behind
+type FileStore struct {
+	dir string
+	m   *sync.Mutex
+}
+
+// NewFileStore creates a new file store, creating its directories if needed
+func NewFileStore(dir string) (s *FileStore, err error) {
+	// Create directories
+	for _, d := range []string{"checkpoints", "jobs"} {
+		if err = os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			err = fmt.Errorf("astiencoder: creating %s failed: %w", filepath.Join(dir, d), err)
+			return
+		}
+	}
+
+	// Create store
+	s = &FileStore{
+		dir: dir,
+		m:   &sync.Mutex{},
+	}
+	return
+}
+
+func (s *FileStore) path(kind, id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("astiencoder: invalid id %s", id)
+	}
+	return filepath.Join(s.dir, kind, id+".json"), nil
+}
+
+func (s *FileStore) read(kind, id string, v interface{}) (ok bool, err error) {
+	// Get path
+	var p string
+	if p, err = s.path(kind, id); err != nil {
+		return
+	}
+
+	// Read
+	var b []byte
+	if b, err = ioutil.ReadFile(p); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("astiencoder: reading %s failed: %w", p, err)
+		return
+	}
+
+	// Unmarshal
+	if err = json.Unmarshal(b, v); err != nil {
+		err = fmt.Errorf("astiencoder: unmarshaling %s failed: %w", p, err)
+		return
+	}
+	ok = true
+	return
+}
+
+func (s *FileStore) write(kind, id string, v interface{}) (err error) {
+	// Get path
+	var p string
+	if p, err = s.path(kind, id); err != nil {
+		return
+	}
+
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(v); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling %s failed: %w", p, err)
+		return
+	}
+
+	// Write to a temporary file
+	var f *os.File
+	if f, err = ioutil.TempFile(filepath.Dir(p), ".tmp-"); err != nil {
+		err = fmt.Errorf("astiencoder: creating temporary file failed: %w", err)
+		return
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		err = fmt.Errorf("astiencoder: writing to %s failed: %w", f.Name(), err)
+		return
+	}
+	if err = f.Close(); err != nil {
+		err = fmt.Errorf("astiencoder: closing %s failed: %w", f.Name(), err)
+		return
+	}
+
+	// Rename
+	if err = os.Rename(f.Name(), p); err != nil {
+		err = fmt.Errorf("astiencoder: renaming %s to %s failed: %w", f.Name(), p, err)
+		return
+	}
+	return
+}
+
+// DelJob implements the Store interface
+func (s *FileStore) DelJob(id string) error {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Loop through kinds
+	for _, kind := range []string{"checkpoints", "jobs"} {
+		p, err := s.path(kind, id)
+		if err != nil {
+			return err
+		}
+		if err = os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("astiencoder: removing %s failed: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// GetCheckpoint implements the Store interface
+func (s *FileStore) GetCheckpoint(jobID string) (c JobCheckpoint, ok bool, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	ok, err = s.read("checkpoints", jobID, &c)
+	return
+}
+
+// GetJob implements the Store interface
+func (s *FileStore) GetJob(id string) (j StoredJob, ok bool, err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	ok, err = s.read("jobs", id, &j)
+	return
+}
+
+// Jobs implements the Store interface
+func (s *FileStore) Jobs() (js []StoredJob, err error) {
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Read dir
+	var fs []os.FileInfo
+	if fs, err = ioutil.ReadDir(filepath.Join(s.dir, "jobs")); err != nil {
+		err = fmt.Errorf("astiencoder: reading %s failed: %w", filepath.Join(s.dir, "jobs"), err)
+		return
+	}
+
+	// Loop through files
+	for _, f := range fs {
+		// Invalid file
+		id := strings.TrimSuffix(f.Name(), ".json")
+		if f.IsDir() || id == f.Name() || strings.HasPrefix(id, ".") {
+			continue
+		}
+
+		// Read
+		var j StoredJob
+		if _, err = s.read("jobs", id, &j); err != nil {
+			return
+		}
+		js = append(js, j)
+	}
+
+	// Sort
+	sortStoredJobs(js)
+	return
+}
+
+// PutCheckpoint implements the Store interface
+func (s *FileStore) PutCheckpoint(c JobCheckpoint) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.write("checkpoints", c.JobID, c)
+}
+
+// PutJob implements the Store interface
+func (s *FileStore) PutJob(j StoredJob) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.write("jobs", j.State.ID, j)
+}
+
+// BoltStore is a store persisting jobs and checkpoints as JSON values in an embedded BoltDB database: jobs in the
+// "jobs" bucket and checkpoints in the "checkpoints" bucket, both keyed by job id
+// Every operation runs in its own transaction so that a crash can't leave a partially written job behind
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var (
+	boltStoreBucketCheckpoints = []byte("checkpoints")
+	boltStoreBucketJobs        = []byte("jobs")
+)
+
+// NewBoltStore opens the database at path, creating it and its buckets if needed
+// The database is locked by the process until the store is closed
+func NewBoltStore(path string) (s *BoltStore, err error) {
+	// Open database
+	var db *bolt.DB
+	if db, err = bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second}); err != nil {
+		err = fmt.Errorf("astiencoder: opening %s failed: %w", path, err)
+		return
+	}
+
+	// Create buckets
+	if err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{boltStoreBucketCheckpoints, boltStoreBucketJobs} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return fmt.Errorf("astiencoder: creating bucket %s failed: %w", b, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return
+	}
+
+	// Create store
+	s = &BoltStore{db: db}
+	return
+}
+
+// Close closes the database
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltStore) read(bucket []byte, id string, v interface{}) (ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		// Get value
+		b := tx.Bucket(bucket).Get([]byte(id))
+		if b == nil {
+			return nil
+		}
+
+		// Unmarshal
+		if err := json.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("astiencoder: unmarshaling %s/%s failed: %w", bucket, id, err)
+		}
+		ok = true
+		return nil
+	})
+	return
+}
+
+func (s *BoltStore) write(bucket []byte, id string, v interface{}) (err error) {
+	// Invalid id
+	if id == "" {
+		return fmt.Errorf("astiencoder: invalid id %s", id)
+	}
+
+	// Marshal
+	var b []byte
+	if b, err = json.Marshal(v); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling %s/%s failed: %w", bucket, id, err)
+		return
+	}
+
+	// Put
+	if err = s.db.Update(func(tx *bolt.Tx) error { return tx.Bucket(bucket).Put([]byte(id), b) }); err != nil {
+		err = fmt.Errorf("astiencoder: putting %s/%s failed: %w", bucket, id, err)
+		return
+	}
+	return
+}
+
+// DelJob implements the Store interface
+func (s *BoltStore) DelJob(id string) error {
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{boltStoreBucketCheckpoints, boltStoreBucketJobs} {
+			if err := tx.Bucket(b).Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("astiencoder: deleting job %s failed: %w", id, err)
+	}
+	return nil
+}
+
+// GetCheckpoint implements the Store interface
+func (s *BoltStore) GetCheckpoint(jobID string) (c JobCheckpoint, ok bool, err error) {
+	ok, err = s.read(boltStoreBucketCheckpoints, jobID, &c)
+	return
+}
+
+// GetJob implements the Store interface
+func (s *BoltStore) GetJob(id string) (j StoredJob, ok bool, err error) {
+	ok, err = s.read(boltStoreBucketJobs, id, &j)
+	return
+}
+
+// Jobs implements the Store interface
+func (s *BoltStore) Jobs() (js []StoredJob, err error) {
+	// Loop through jobs
+	if err = s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltStoreBucketJobs).ForEach(func(k, v []byte) error {
+			var j StoredJob
+			if err := json.Unmarshal(v, &j); err != nil {
+				return fmt.Errorf("astiencoder: unmarshaling %s/%s failed: %w", boltStoreBucketJobs, k, err)
+			}
+			js = append(js, j)
+			return nil
+		})
+	}); err != nil {
+		return
+	}
+
+	// Sort
+	sortStoredJobs(js)
+	return
+}
+
+// PutCheckpoint implements the Store interface
+func (s *BoltStore) PutCheckpoint(c JobCheckpoint) error {
+	return s.write(boltStoreBucketCheckpoints, c.JobID, c)
+}
+
+// PutJob implements the Store interface
+func (s *BoltStore) PutJob(j StoredJob) error {
+	return s.write(boltStoreBucketJobs, j.State.ID, j)
+}
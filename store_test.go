@@ -0,0 +1,230 @@
+package astiencoder
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	// Create store
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s, err := NewFileStore(dir)
+	assert.NoError(t, err)
+
+	// Jobs
+	t1 := time.Unix(1, 0).UTC()
+	j1 := StoredJob{Job: Job{Definition: WorkflowDefinition{Name: "w1", Nodes: []NodeDefinition{{Name: "n", Options: map[string]interface{}{"a": 1.0}, Type: "t"}}}, Priority: 1}, State: JobState{AddedAt: t1, ID: "10", Status: JobStatusPending}}
+	j2 := StoredJob{Job: Job{Values: map[string]interface{}{"v": "v"}}, State: JobState{AddedAt: t1, ID: "9", Status: JobStatusRunning}}
+	assert.NoError(t, s.PutJob(j1))
+	assert.NoError(t, s.PutJob(j2))
+	js, err := s.Jobs()
+	assert.NoError(t, err)
+	assert.Equal(t, []StoredJob{j2, j1}, js)
+	j, ok, err := s.GetJob("10")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, j1, j)
+	_, ok, err = s.GetJob("11")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.EqualError(t, s.PutJob(StoredJob{State: JobState{ID: "../10"}}), "astiencoder: invalid id ../10")
+
+	// Checkpoints
+	c := JobCheckpoint{Data: map[string]interface{}{"position": 10.0}, JobID: "10", SavedAt: t1}
+	assert.NoError(t, s.PutCheckpoint(c))
+	c2, ok, err := s.GetCheckpoint("10")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, c, c2)
+
+	// Delete
+	assert.NoError(t, s.DelJob("10"))
+	assert.NoError(t, s.DelJob("10"))
+	_, ok, err = s.GetCheckpoint("10")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	js, err = s.Jobs()
+	assert.NoError(t, err)
+	assert.Equal(t, []StoredJob{j2}, js)
+}
+
+func TestBoltStore(t *testing.T) {
+	// Create store
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "astiencoder.db")
+	s, err := NewBoltStore(p)
+	assert.NoError(t, err)
+
+	// Jobs
+	t1 := time.Unix(1, 0).UTC()
+	j1 := StoredJob{Job: Job{Definition: WorkflowDefinition{Name: "w1", Nodes: []NodeDefinition{{Name: "n", Options: map[string]interface{}{"a": 1.0}, Type: "t"}}}, Priority: 1}, State: JobState{AddedAt: t1, ID: "10", Status: JobStatusPending}}
+	j2 := StoredJob{Job: Job{Values: map[string]interface{}{"v": "v"}}, State: JobState{AddedAt: t1, ID: "9", Status: JobStatusRunning}}
+	assert.NoError(t, s.PutJob(j1))
+	assert.NoError(t, s.PutJob(j2))
+	assert.EqualError(t, s.PutJob(StoredJob{}), "astiencoder: invalid id ")
+	_, ok, err := s.GetJob("11")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Checkpoints
+	c := JobCheckpoint{Data: map[string]interface{}{"position": 10.0}, JobID: "10", SavedAt: t1}
+	assert.NoError(t, s.PutCheckpoint(c))
+
+	// Everything is persisted
+	assert.NoError(t, s.Close())
+	s, err = NewBoltStore(p)
+	assert.NoError(t, err)
+	defer s.Close()
+	js, err := s.Jobs()
+	assert.NoError(t, err)
+	assert.Equal(t, []StoredJob{j2, j1}, js)
+	j, ok, err := s.GetJob("10")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, j1, j)
+	c2, ok, err := s.GetCheckpoint("10")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, c, c2)
+
+	// Delete
+	assert.NoError(t, s.DelJob("10"))
+	assert.NoError(t, s.DelJob("10"))
+	_, ok, err = s.GetCheckpoint("10")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	js, err = s.Jobs()
+	assert.NoError(t, err)
+	assert.Equal(t, []StoredJob{j2}, js)
+}
+
+func TestJobQueueStore(t *testing.T) {
+	// Register types
+	eh := NewEventHandler()
+	ts := NewNodeTypes()
+	ts.Register("t", NodeType{New: func(b NodeBuild) (Node, error) {
+		return newMockedStatsNode(b.Definition.Name, eh), nil
+	}})
+
+	// Fill store as if the process had stopped while running job 2
+	s := NewMemoryStore()
+	t1 := time.Unix(1, 0)
+	job := func(name string) Job {
+		return Job{Definition: WorkflowDefinition{Name: name, Nodes: []NodeDefinition{{Name: name, Type: "t"}}}}
+	}
+	assert.NoError(t, s.PutJob(StoredJob{Job: job("1"), State: JobState{AddedAt: t1, Attempts: 1, ID: "1", Name: "1", Status: JobStatusSucceeded, StoppedAt: t1}}))
+	assert.NoError(t, s.PutJob(StoredJob{Job: job("2"), State: JobState{AddedAt: t1, Attempts: 1, ID: "2", Name: "2", Status: JobStatusRunning}}))
+	assert.NoError(t, s.PutCheckpoint(JobCheckpoint{Data: map[string]interface{}{"position": 10}, JobID: "2"}))
+
+	// Restore
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	defer wk.Stop()
+	q := NewJobQueue(JobQueueOptions{
+		HistorySize: 2,
+		Store:       s,
+		TaskFunc:    wk.NewTask,
+		Types:       ts,
+	}, eh)
+	assert.NoError(t, q.Restore())
+	assert.EqualError(t, q.Restore(), "astiencoder: job queue is not empty")
+	ss := q.Jobs()
+	assert.Len(t, ss, 2)
+	assert.Equal(t, JobStatusSucceeded, ss[0].Status)
+	assert.Equal(t, JobStatusPending, ss[1].Status)
+	c, ok, err := q.Checkpoint("2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 10, c.Data["position"])
+
+	// Ids keep increasing and checkpoints are saved
+	assert.Equal(t, "3", q.Add(job("3")))
+	assert.NoError(t, q.SaveCheckpoint("3", map[string]interface{}{"position": 1}))
+	assert.EqualError(t, q.SaveCheckpoint("1", nil), "astiencoder: job 1 is succeeded")
+	j, ok, err := s.GetJob("3")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, JobStatusPending, j.State.Status)
+
+	// Run jobs
+	done := make(chan struct{})
+	eh.AddForEventName(EventNameJobUpdated, func(e Event) bool {
+		if s := e.Payload.(JobState); s.ID == "3" && s.Status == JobStatusSucceeded {
+			close(done)
+		}
+		return false
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Start(ctx)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("jobs are not done")
+	}
+	cancel()
+	<-stopped
+
+	// Store is up to date and done jobs exceeding the history size have been deleted
+	js, err := s.Jobs()
+	assert.NoError(t, err)
+	assert.Len(t, js, 2)
+	assert.Equal(t, "2", js[0].State.ID)
+	assert.Equal(t, 2, js[0].State.Attempts)
+	assert.Equal(t, JobStatusSucceeded, js[0].State.Status)
+	assert.Equal(t, "3", js[1].State.ID)
+	assert.Equal(t, 1, js[1].State.Attempts)
+	assert.Equal(t, JobStatusSucceeded, js[1].State.Status)
+	_, ok, err = s.GetCheckpoint("1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+type blockingStore struct {
+	Store
+	unblock chan struct{}
+}
+
+func (s *blockingStore) PutJob(j StoredJob) error {
+	<-s.unblock
+	return s.Store.PutJob(j)
+}
+
+func TestJobQueueSlowStore(t *testing.T) {
+	// Create queue
+	s := &blockingStore{Store: NewMemoryStore(), unblock: make(chan struct{})}
+	q := NewJobQueue(JobQueueOptions{Store: s}, NewEventHandler())
+
+	// Add job while the store is blocked
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		q.Add(Job{Definition: WorkflowDefinition{Name: "1"}})
+	}()
+
+	// The queue is not stalled by the store
+	assert.Eventually(t, func() bool { return len(q.Jobs()) == 1 }, time.Second, time.Millisecond)
+	assert.True(t, errors.Is(q.Cancel("2"), ErrJobNotFound))
+
+	// Job is persisted once the store is unblocked
+	close(s.unblock)
+	<-added
+	j, ok, err := s.GetJob("1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, JobStatusPending, j.State.Status)
+}
@@ -0,0 +1,207 @@
+package astiencoder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSServerOptions represents the TLS options of a server, e.g. the one serving the control API or a remote receiver
+type TLSServerOptions struct {
+	// PEM encoded certificate chain
+	CertFile string `json:"cert_file"`
+	// If set, clients must present a certificate signed by one of the PEM encoded CAs it contains (mTLS)
+	ClientCAFile string `json:"client_ca_file,omitempty"`
+	// PEM encoded private key
+	KeyFile string `json:"key_file"`
+}
+
+// Config returns the TLS configuration of the server
+// Files are read once to make sure they're valid, and read again during handshakes whenever they've been modified,
+// which allows rotating certificates without restarting the server. If they can't be read again, e.g. while they're
+// being written, the previous ones are used
+func (o TLSServerOptions) Config() (c *tls.Config, err error) {
+	// Check options
+	if o.CertFile == "" || o.KeyFile == "" {
+		err = errors.New("astiencoder: cert and key files are mandatory")
+		return
+	}
+
+	// Create reloader
+	r := &tlsServerReloader{
+		m: &sync.Mutex{},
+		o: o,
+	}
+
+	// Load files
+	if err = r.reload(); err != nil {
+		return
+	}
+
+	// Create config
+	c = &tls.Config{
+		GetCertificate: r.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	// Verify client certificates
+	if o.ClientCAFile != "" {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = r.clientCAs
+		c.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) { return r.configForClient(c) }
+	}
+	return
+}
+
+type tlsServerReloader struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	m         *sync.Mutex
+	modTimes  map[string]time.Time
+	o         TLSServerOptions
+}
+
+func (r *tlsServerReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	// Reload
+	// Previous files are used if it fails
+	r.reload()
+
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.cert, nil
+}
+
+func (r *tlsServerReloader) configForClient(base *tls.Config) (*tls.Config, error) {
+	// Reload
+	// Previous files are used if it fails
+	r.reload()
+
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Clone the base config with the latest client CAs
+	c := base.Clone()
+	c.ClientCAs = r.clientCAs
+	c.GetConfigForClient = nil
+	return c, nil
+}
+
+// reload reads the files again if any of them has been modified since they were last read
+func (r *tlsServerReloader) reload() (err error) {
+	// Get modification times
+	ps := []string{r.o.CertFile, r.o.KeyFile}
+	if r.o.ClientCAFile != "" {
+		ps = append(ps, r.o.ClientCAFile)
+	}
+	mts := make(map[string]time.Time)
+	for _, p := range ps {
+		var fi os.FileInfo
+		if fi, err = os.Stat(p); err != nil {
+			err = fmt.Errorf("astiencoder: stating %s failed: %w", p, err)
+			return
+		}
+		mts[p] = fi.ModTime()
+	}
+
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// Nothing has been modified
+	modified := r.modTimes == nil
+	for p, mt := range mts {
+		if !r.modTimes[p].Equal(mt) {
+			modified = true
+		}
+	}
+	if !modified {
+		return
+	}
+
+	// Load certificate
+	var cert tls.Certificate
+	if cert, err = tls.LoadX509KeyPair(r.o.CertFile, r.o.KeyFile); err != nil {
+		err = fmt.Errorf("astiencoder: loading key pair %s/%s failed: %w", r.o.CertFile, r.o.KeyFile, err)
+		return
+	}
+
+	// Load client CAs
+	var clientCAs *x509.CertPool
+	if r.o.ClientCAFile != "" {
+		if clientCAs, err = loadTLSCertPool(r.o.ClientCAFile); err != nil {
+			return
+		}
+	}
+
+	// Update
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = mts
+	return
+}
+
+// TLSClientOptions represents the TLS options of a client, e.g. a ControlClient or a remote sender
+type TLSClientOptions struct {
+	// If set, the server's certificate must be signed by one of the PEM encoded CAs it contains. Otherwise the
+	// system's CAs are used
+	CAFile string `json:"ca_file,omitempty"`
+	// If set with KeyFile, the PEM encoded certificate chain presented to servers verifying client certificates (mTLS)
+	CertFile string `json:"cert_file,omitempty"`
+	// Disables the verification of the server's certificate. Only use it for tests
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	// If set, the name the server's certificate is verified against instead of the host of the address
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// Config returns the TLS configuration of the client
+func (o TLSClientOptions) Config() (c *tls.Config, err error) {
+	// Create config
+	c = &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         o.ServerName,
+	}
+
+	// Load CAs
+	if o.CAFile != "" {
+		if c.RootCAs, err = loadTLSCertPool(o.CAFile); err != nil {
+			return
+		}
+	}
+
+	// Load certificate
+	if o.CertFile != "" || o.KeyFile != "" {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(o.CertFile, o.KeyFile); err != nil {
+			err = fmt.Errorf("astiencoder: loading key pair %s/%s failed: %w", o.CertFile, o.KeyFile, err)
+			return
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return
+}
+
+func loadTLSCertPool(path string) (p *x509.CertPool, err error) {
+	// Read
+	var b []byte
+	if b, err = ioutil.ReadFile(path); err != nil {
+		err = fmt.Errorf("astiencoder: reading %s failed: %w", path, err)
+		return
+	}
+
+	// Append
+	p = x509.NewCertPool()
+	if !p.AppendCertsFromPEM(b) {
+		err = fmt.Errorf("astiencoder: no pem encoded certificate found in %s", path)
+		return
+	}
+	return
+}
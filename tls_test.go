@@ -0,0 +1,148 @@
+package astiencoder
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTLSCertificate struct {
+	c        *x509.Certificate
+	certFile string
+	k        *ecdsa.PrivateKey
+	keyFile  string
+}
+
+// newTestTLSCertificate creates a certificate signed by parent, or a CA if parent is nil
+func newTestTLSCertificate(t *testing.T, dir, name string, parent *testTLSCertificate) testTLSCertificate {
+	// Create key
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	// Create template
+	tpl := &x509.Certificate{
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		NotAfter:     time.Now().Add(time.Hour),
+		NotBefore:    time.Now().Add(-time.Hour),
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+	}
+	signer, signerKey := tpl, k
+	if parent == nil {
+		tpl.BasicConstraintsValid = true
+		tpl.IsCA = true
+		tpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.c, parent.k
+	}
+
+	// Create certificate
+	b, err := x509.CreateCertificate(rand.Reader, tpl, signer, &k.PublicKey, signerKey)
+	assert.NoError(t, err)
+	c, err := x509.ParseCertificate(b)
+	assert.NoError(t, err)
+
+	// Write files
+	kb, err := x509.MarshalECPrivateKey(k)
+	assert.NoError(t, err)
+	tc := testTLSCertificate{
+		c:        c,
+		certFile: filepath.Join(dir, name+".crt"),
+		k:        k,
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	assert.NoError(t, ioutil.WriteFile(tc.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}), 0600))
+	assert.NoError(t, ioutil.WriteFile(tc.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0600))
+	return tc
+}
+
+func TestTLS(t *testing.T) {
+	// Create certificates
+	dir, err := ioutil.TempDir("", "astiencoder")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ca := newTestTLSCertificate(t, dir, "ca", nil)
+	server := newTestTLSCertificate(t, dir, "server", &ca)
+	client := newTestTLSCertificate(t, dir, "client", &ca)
+	other := newTestTLSCertificate(t, dir, "other", nil)
+
+	// Invalid options
+	_, err = TLSServerOptions{CertFile: server.certFile}.Config()
+	assert.Error(t, err)
+	_, err = TLSServerOptions{CertFile: server.certFile, ClientCAFile: filepath.Join(dir, "server.key"), KeyFile: server.keyFile}.Config()
+	assert.Error(t, err)
+	_, err = TLSClientOptions{CAFile: filepath.Join(dir, "missing.crt")}.Config()
+	assert.Error(t, err)
+
+	// Create server
+	sc, err := TLSServerOptions{
+		CertFile:     server.certFile,
+		ClientCAFile: ca.certFile,
+		KeyFile:      server.keyFile,
+	}.Config()
+	assert.NoError(t, err)
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	s.TLS = sc
+	s.StartTLS()
+	defer s.Close()
+
+	// Loop through clients
+	get := func(o TLSClientOptions, ok bool) {
+		cc, err := o.Config()
+		assert.NoError(t, err)
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: cc}}
+		resp, err := c.Get(s.URL)
+		if ok {
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			resp.Body.Close()
+		} else {
+			assert.Error(t, err)
+		}
+	}
+	for _, v := range []struct {
+		o  TLSClientOptions
+		ok bool
+	}{
+		{o: TLSClientOptions{CAFile: ca.certFile, CertFile: client.certFile, KeyFile: client.keyFile}, ok: true},
+		{o: TLSClientOptions{CAFile: ca.certFile}},
+		{o: TLSClientOptions{CAFile: ca.certFile, CertFile: other.certFile, KeyFile: other.keyFile}},
+		{o: TLSClientOptions{CAFile: other.certFile, CertFile: client.certFile, KeyFile: client.keyFile}},
+	} {
+		get(v.o, v.ok)
+	}
+
+	// Rotate the server certificate and the client CAs without restarting the server
+	rotate := func(src, dst string) {
+		b, err := ioutil.ReadFile(src)
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(dst, b, 0600))
+		assert.NoError(t, os.Chtimes(dst, time.Now(), time.Now().Add(time.Minute)))
+	}
+	otherServer := newTestTLSCertificate(t, dir, "other-server", &other)
+	otherClient := newTestTLSCertificate(t, dir, "other-client", &other)
+	rotate(otherServer.certFile, server.certFile)
+	rotate(otherServer.keyFile, server.keyFile)
+	rotate(other.certFile, ca.certFile)
+	get(TLSClientOptions{CAFile: other.certFile, CertFile: otherClient.certFile, KeyFile: otherClient.keyFile}, true)
+	get(TLSClientOptions{CAFile: other.certFile, CertFile: client.certFile, KeyFile: client.keyFile}, false)
+}
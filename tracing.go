@@ -0,0 +1,81 @@
+package astiencoder
+
+import (
+	"context"
+)
+
+// Span names
+const (
+	SpanNameNode     = "astiencoder.node"
+	SpanNameWorkflow = "astiencoder.workflow"
+)
+
+// Span attribute keys
+const (
+	SpanAttributeNode     = "astiencoder.node"
+	SpanAttributeWorkflow = "astiencoder.workflow"
+)
+
+const tracingGOPSamplingPeriodDefault = 100
+
+type tracingContextKey struct{}
+
+// Tracer represents an object capable of starting spans
+// It mirrors the OpenTelemetry tracer API so that an OpenTelemetry tracer can be adapted in a few lines
+type Tracer interface {
+	Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span)
+}
+
+// Span represents a span started by a tracer
+type Span interface {
+	End()
+}
+
+// SpanAttribute represents a span attribute
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// TracingOptions represents tracing options
+type TracingOptions struct {
+	// Only 1 GOP out of GOPSamplingPeriod is traced through the media path. Default is 100
+	GOPSamplingPeriod int
+	Tracer            Tracer
+}
+
+// Tracing represents the tracing of a workflow
+// Workflows and nodes are traced from start to stop, and nodes of the media path trace sampled GOPs so that the
+// latency contribution of each node is visible
+type Tracing struct {
+	o TracingOptions
+}
+
+// SetTracing makes the workflow and its nodes create spans
+// It must be called before the workflow is started
+func (w *Workflow) SetTracing(o TracingOptions) {
+	// Default options
+	if o.GOPSamplingPeriod <= 0 {
+		o.GOPSamplingPeriod = tracingGOPSamplingPeriodDefault
+	}
+	w.tr = &Tracing{o: o}
+}
+
+// TracingFromContext returns the tracing of the workflow the context belongs to, if any
+func TracingFromContext(ctx context.Context) *Tracing {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(tracingContextKey{}).(*Tracing)
+	return t
+}
+
+// SampleGOP indicates whether the GOP with this 0-based index should be traced
+func (t *Tracing) SampleGOP(idx uint64) bool {
+	return idx%uint64(t.o.GOPSamplingPeriod) == 0
+}
+
+// Start starts a span
+func (t *Tracing) Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span) {
+	return t.o.Tracer.Start(ctx, spanName, attributes...)
+}
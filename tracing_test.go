@@ -0,0 +1,79 @@
+package astiencoder
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedTracer struct {
+	ended   []string
+	m       *sync.Mutex
+	started []string
+}
+
+func newMockedTracer() *mockedTracer {
+	return &mockedTracer{m: &sync.Mutex{}}
+}
+
+func (t *mockedTracer) Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	n := spanName + ":" + attributes[0].Value.(string)
+	t.started = append(t.started, n)
+	return ctx, &mockedSpan{n: n, t: t}
+}
+
+type mockedSpan struct {
+	n string
+	t *mockedTracer
+}
+
+func (s *mockedSpan) End() {
+	s.t.m.Lock()
+	defer s.t.m.Unlock()
+	s.t.ended = append(s.t.ended, s.n)
+}
+
+type mockedTracedNode struct {
+	*mockedStatsNode
+	started chan struct{}
+}
+
+func (n *mockedTracedNode) Start(ctx context.Context, t CreateTaskFunc) {
+	n.BaseNode.Start(ctx, t, func(t *astikit.Task) {
+		close(n.started)
+		<-n.Context().Done()
+	})
+}
+
+func TestTracing(t *testing.T) {
+	eh := NewEventHandler()
+	wk := astikit.NewWorker(astikit.WorkerOptions{})
+	w := NewWorkflow(wk.Context(), "w", eh, wk.NewTask, astikit.NewCloser())
+	tr := newMockedTracer()
+	w.SetTracing(TracingOptions{Tracer: tr})
+	assert.Equal(t, 100, w.tr.o.GOPSamplingPeriod)
+	assert.True(t, w.tr.SampleGOP(0))
+	assert.False(t, w.tr.SampleGOP(1))
+	assert.True(t, w.tr.SampleGOP(100))
+
+	n := &mockedTracedNode{
+		mockedStatsNode: newMockedStatsNode("n", eh),
+		started:         make(chan struct{}),
+	}
+	w.AddChild(n)
+	w.Start()
+	<-n.started
+	assert.Equal(t, w.tr, TracingFromContext(n.Context()))
+	w.Stop()
+	wk.Stop()
+	wk.Wait()
+
+	assert.Equal(t, []string{"astiencoder.workflow:w", "astiencoder.node:root", "astiencoder.node:n"}, tr.started)
+	assert.Equal(t, []string{"astiencoder.node:n", "astiencoder.workflow:w", "astiencoder.node:root"}, tr.ended)
+	assert.Nil(t, TracingFromContext(context.Background()))
+}
@@ -0,0 +1,428 @@
+var astiencoder = {
+    nodes: {},
+    selectedNode: null,
+    workflow: null,
+    workflows: {},
+    init () {
+        // Get token, which is provided in the URL since the UI doesn't handle logins
+        this.token = new URL(window.location.href).searchParams.get('access_token')
+
+        // Refresh workflows periodically since they can be created or deleted by other clients
+        this.refreshWorkflows()
+        setInterval(this.refreshWorkflows.bind(this), 5e3)
+
+        // Open websocket
+        this.openWebSocket({
+            onopen: this.onopen.bind(this),
+            onmessage: this.onmessage.bind(this)
+        })
+    },
+    onopen () {
+        // Update connection
+        document.getElementById('connection').className = 'connected'
+
+        // Subscribe
+        this.subscribe()
+    },
+    onmessage (name, payload) {
+        switch (name) {
+            case 'command.result':
+                if (payload.error) this.log(payload.command + ' failed: ' + payload.error.message, true)
+                break
+            case 'astiencoder.audit':
+                var a = payload.payload
+                this.log((a.actor || 'unknown') + ' ' + a.action + (a.node ? ' ' + a.node : '') + (a.error ? ' failed: ' + a.error : ''), !!a.error)
+                break
+            case 'astiencoder.error':
+                this.log('error' + (payload.node ? ' in ' + payload.node : ''), true)
+                break
+            case 'astiencoder.node.continued':
+                this.updateNode(payload.node, {status: 'running'})
+                break
+            case 'astiencoder.node.paused':
+                this.updateNode(payload.node, {status: 'paused'})
+                break
+            case 'astiencoder.node.started':
+                // A node may have been added while the workflow is running
+                if (!this.nodes[payload.node]) this.refreshWorkflow()
+                else this.updateNode(payload.node, {status: 'running'})
+                break
+            case 'astiencoder.node.stats':
+                this.updateNode(payload.node, {stats: payload.payload.stats})
+                break
+            case 'astiencoder.node.stopped':
+                this.updateNode(payload.node, {status: 'stopped'})
+                break
+            case 'astiencoder.workflow.continued':
+            case 'astiencoder.workflow.started':
+                this.updateWorkflow(payload.workflow, 'running')
+                break
+            case 'astiencoder.workflow.paused':
+                this.updateWorkflow(payload.workflow, 'paused')
+                break
+            case 'astiencoder.workflow.patched':
+                this.refreshWorkflow()
+                break
+            case 'astiencoder.workflow.stopped':
+                this.updateWorkflow(payload.workflow, 'stopped')
+                break
+        }
+    },
+
+    /* workflows */
+    refreshWorkflows () {
+        this.sendHttp({
+            method: 'GET',
+            url: 'workflows',
+            onsuccess: function(data) {
+                // Update workflows
+                this.workflows = {}
+                data.forEach(function(item) {
+                    this.workflows[item.name] = item
+                }.bind(this))
+
+                // Select first workflow
+                if (!this.workflow || !this.workflows[this.workflow]) {
+                    this.selectWorkflow(data.length > 0 ? data[0].name : null)
+                    return
+                }
+
+                // Render
+                this.renderWorkflows()
+            }.bind(this),
+            onerror: function(data) {
+                this.log('listing workflows failed' + (data && data.error ? ': ' + data.error.message : ''), true)
+            }.bind(this)
+        })
+    },
+    selectWorkflow (name) {
+        // Update workflow
+        this.workflow = name
+        this.nodes = {}
+        this.selectedNode = null
+
+        // Subscribe
+        this.subscribe()
+
+        // Render
+        this.renderWorkflows()
+        this.refreshWorkflow()
+    },
+    refreshWorkflow () {
+        // No workflow
+        if (!this.workflow) {
+            this.renderGraph()
+            return
+        }
+
+        // Fetch snapshot
+        var name = this.workflow
+        this.sendHttp({
+            method: 'GET',
+            url: 'workflows/' + encodeURIComponent(name),
+            onsuccess: function(data) {
+                // Workflow has changed in the meantime
+                if (name !== this.workflow) return
+
+                // Update nodes
+                this.nodes = {}
+                data.nodes.forEach(function(item) {
+                    this.nodes[item.name] = item
+                }.bind(this))
+                this.updateWorkflow(name, data.status)
+
+                // Render
+                this.renderGraph()
+            }.bind(this)
+        })
+    },
+    updateWorkflow (name, status) {
+        if (!this.workflows[name]) return
+        this.workflows[name].status = status
+        this.renderWorkflows()
+    },
+    renderWorkflows () {
+        // Render list
+        var nav = document.getElementById('workflows')
+        nav.innerHTML = ''
+        Object.keys(this.workflows).sort().forEach(function(name) {
+            var d = document.createElement('div')
+            if (name === this.workflow) d.className = 'selected'
+            d.onclick = function() { this.selectWorkflow(name) }.bind(this)
+            d.appendChild(this.createText('span', name))
+            d.appendChild(this.createText('span', this.workflows[name].status, 'status ' + this.workflows[name].status))
+            nav.appendChild(d)
+        }.bind(this))
+
+        // Render controls
+        var w = this.workflows[this.workflow]
+        document.getElementById('workflow-name').textContent = this.workflow || ''
+        document.getElementById('workflow-status').textContent = w ? w.status : ''
+        document.getElementById('workflow-status').className = 'status' + (w ? ' ' + w.status : '')
+    },
+    onWorkflowCommand (command) {
+        if (!this.workflow) return
+        this.sendWebSocket(command, {workflow: this.workflow})
+    },
+
+    /* graph */
+    updateNode (name, payload) {
+        // Get node
+        var n = this.nodes[name]
+        if (!n) return
+
+        // Update node
+        if (payload.stats) n.stats = payload.stats
+        if (payload.status) n.status = payload.status
+
+        // Render
+        this.renderNode(n)
+        if (this.selectedNode === name) this.renderInspector()
+    },
+    layout () {
+        // Get layers: a node is placed right after the deepest of its parents
+        var layers = {}
+        var layer = function(name, visited) {
+            if (typeof layers[name] !== 'undefined') return layers[name]
+            if (visited[name]) return 0
+            visited[name] = true
+            var l = 0
+            this.nodes[name].parents.forEach(function(p) {
+                if (this.nodes[p]) l = Math.max(l, layer(p, visited) + 1)
+            }.bind(this))
+            layers[name] = l
+            return l
+        }.bind(this)
+
+        // Get columns
+        var columns = []
+        Object.keys(this.nodes).sort().forEach(function(name) {
+            var l = layer(name, {})
+            if (!columns[l]) columns[l] = []
+            columns[l].push(name)
+        })
+
+        // Get positions
+        var positions = {}
+        columns.forEach(function(c, x) {
+            c.forEach(function(name, y) {
+                positions[name] = {x: 20 + x * 240, y: 20 + y * 100}
+            })
+        })
+        return positions
+    },
+    renderGraph () {
+        // Reset
+        var svg = document.querySelector('#graph svg')
+        svg.innerHTML = ''
+
+        // Get positions
+        var ps = this.layout()
+        var width = 0, height = 0
+        for (var name in ps) {
+            width = Math.max(width, ps[name].x + 220)
+            height = Math.max(height, ps[name].y + 80)
+        }
+        svg.setAttribute('width', width)
+        svg.setAttribute('height', height)
+
+        // Render edges
+        for (var name in this.nodes) {
+            this.nodes[name].children.forEach(function(c) {
+                if (!ps[c]) return
+                var p = this.createSVG('path', {
+                    'class': 'edge',
+                    d: 'M' + (ps[name].x + 200) + ',' + (ps[name].y + 30) + ' C' + (ps[name].x + 220) + ',' + (ps[name].y + 30) +
+                        ' ' + (ps[c].x - 20) + ',' + (ps[c].y + 30) + ' ' + ps[c].x + ',' + (ps[c].y + 30)
+                })
+                svg.appendChild(p)
+            }.bind(this))
+        }
+
+        // Render nodes
+        for (var name in this.nodes) {
+            var n = this.nodes[name]
+            n.dom = this.createSVG('g', {transform: 'translate(' + ps[name].x + ',' + ps[name].y + ')'})
+            n.dom.onclick = this.onNodeClick.bind(this, name)
+            n.dom.appendChild(this.createSVG('rect', {width: 200, height: 60}))
+            var t = this.createSVG('text', {'class': 'label', x: 10, y: 18})
+            t.textContent = n.label || n.name
+            n.dom.appendChild(t)
+            n.domStats = [this.createSVG('text', {'class': 'stat', x: 10, y: 36}), this.createSVG('text', {'class': 'stat', x: 10, y: 50})]
+            n.domStats.forEach(function(item) { n.dom.appendChild(item) })
+            svg.appendChild(n.dom)
+            this.renderNode(n)
+        }
+
+        // Render inspector
+        this.renderInspector()
+    },
+    renderNode (n) {
+        // Node is not rendered
+        if (!n.dom) return
+
+        // Update class
+        n.dom.setAttribute('class', 'node ' + n.status + (this.selectedNode === n.name ? ' selected' : ''))
+
+        // Update stats
+        n.domStats.forEach(function(item, idx) {
+            var s = n.stats && n.stats[idx]
+            item.textContent = s ? s.label + ': ' + this.formatValue(s) : ''
+        }.bind(this))
+    },
+
+    /* inspector */
+    onNodeClick (name) {
+        var previous = this.nodes[this.selectedNode]
+        this.selectedNode = name
+        if (previous) this.renderNode(previous)
+        this.renderNode(this.nodes[name])
+        this.renderInspector()
+    },
+    onNodeClose () {
+        var previous = this.nodes[this.selectedNode]
+        this.selectedNode = null
+        if (previous) this.renderNode(previous)
+        this.renderInspector()
+    },
+    onNodeCommand (command) {
+        if (!this.workflow || !this.selectedNode) return
+        this.sendWebSocket(command, {node: this.selectedNode, workflow: this.workflow})
+    },
+    renderInspector () {
+        // No selected node
+        var aside = document.getElementById('node')
+        var n = this.nodes[this.selectedNode]
+        if (!n) {
+            aside.className = ''
+            return
+        }
+        aside.className = 'visible'
+
+        // Update node
+        document.getElementById('node-label').textContent = n.label || n.name
+        document.getElementById('node-description').textContent = n.description
+        document.getElementById('node-status').textContent = n.status
+        document.getElementById('node-status').className = 'status ' + n.status
+
+        // Update tags
+        var tags = document.getElementById('node-tags')
+        tags.innerHTML = ''
+        n.tags.forEach(function(item) {
+            tags.appendChild(this.createText('span', item))
+        }.bind(this))
+
+        // Update stats
+        var table = document.getElementById('node-stats')
+        table.innerHTML = ''
+        ;(n.stats || []).forEach(function(item) {
+            var tr = document.createElement('tr')
+            tr.title = item.description
+            tr.appendChild(this.createText('td', item.label))
+            tr.appendChild(this.createText('td', this.formatValue(item)))
+            table.appendChild(tr)
+        }.bind(this))
+    },
+
+    /* logs */
+    log (message, error) {
+        var logs = document.getElementById('logs')
+        var d = this.createText('div', new Date().toLocaleTimeString() + ' ' + message, error ? 'error' : '')
+        logs.insertBefore(d, logs.firstChild)
+        while (logs.children.length > 100) logs.removeChild(logs.lastChild)
+    },
+
+    /* helpers */
+    formatValue (stat) {
+        var v = stat.value
+        if (typeof v === 'number') v = v.toFixed(2)
+        else if (typeof v === 'object') v = JSON.stringify(v)
+        return v + (stat.unit ? ' ' + stat.unit : '')
+    },
+    createText (tag, text, className) {
+        var e = document.createElement(tag)
+        e.textContent = text
+        if (className) e.className = className
+        return e
+    },
+    createSVG (tag, attrs) {
+        var e = document.createElementNS('http://www.w3.org/2000/svg', tag)
+        for (var k in attrs) {
+            e.setAttribute(k, attrs[k])
+        }
+        return e
+    },
+    subscribe () {
+        var p = {}
+        if (this.workflow) p.workflow = this.workflow
+        this.sendWebSocket('subscribe', p)
+    },
+    sendHttp (options) {
+        const req = new XMLHttpRequest()
+        req.onreadystatechange = function() {
+            if (this.readyState === XMLHttpRequest.DONE) {
+                var data = null
+                try {
+                    if (this.responseText !== '') data = JSON.parse(this.responseText)
+                } catch (e) {}
+                if (this.status >= 200 && this.status < 300) {
+                    if (options.onsuccess) options.onsuccess(data)
+                } else {
+                    if (options.onerror) options.onerror(data)
+                }
+            }
+        }
+        req.open(options.method, options.url, true)
+        if (this.token) req.setRequestHeader('Authorization', 'Bearer ' + this.token)
+        req.send(options.payload)
+    },
+    openWebSocket (options) {
+        // URLs are relative so that the UI works whatever the prefix it's served under
+        var u = new URL('websocket', window.location.href)
+        u.protocol = u.protocol === 'https:' ? 'wss:' : 'ws:'
+        u.search = ''
+        if (this.token) u.searchParams.set('access_token', this.token)
+        this.ws = new WebSocket(u.toString())
+
+        // Handle open
+        var pingInterval = null
+        this.ws.onopen = function() {
+            // Make sure to ping
+            pingInterval = setInterval(function() {
+                this.sendWebSocket('ping')
+            }.bind(this), 50*1e3)
+
+            // Open callback
+            options.onopen()
+        }.bind(this)
+
+        // Handle close
+        this.ws.onclose = function() {
+            // Update connection
+            document.getElementById('connection').className = 'disconnected'
+
+            // Cancel ping
+            clearInterval(pingInterval)
+            this.ws = null
+
+            // Reconnect
+            setTimeout(function() {
+                this.openWebSocket(options)
+            }.bind(this), 1000)
+        }.bind(this)
+
+        // Handle message
+        this.ws.onmessage = function(event) {
+            var data = JSON.parse(event.data)
+            options.onmessage(data.event_name, data.payload)
+        }.bind(this)
+    },
+    sendWebSocket (name, payload) {
+        if (!this.ws || this.ws.readyState !== WebSocket.OPEN) return
+        var d = {event_name: name}
+        if (payload) d.payload = payload
+        this.ws.send(JSON.stringify(d))
+    }
+}
+
+astiencoder.init()
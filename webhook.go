@@ -0,0 +1,373 @@
+package astiencoder
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookSignatureHeader is the header in which deliveries of webhooks having a secret are signed
+const WebhookSignatureHeader = "X-Astiencoder-Signature"
+
+// Webhook represents an HTTP endpoint notified of events
+type Webhook struct {
+	// If set, only events with those names are delivered
+	EventNames []string
+	Headers    http.Header
+	// If set, deliveries are signed with an HMAC-SHA256 of their body, sent as "sha256=<hex>" in the
+	// X-Astiencoder-Signature header
+	Secret string
+	URL    string
+}
+
+// WebhookDelivery represents the JSON body posted to a webhook
+// Its ID is the same for every attempt, which allows receivers to ignore duplicates
+type WebhookDelivery struct {
+	ControlEvent
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+}
+
+// WebhookDeliveryFailure represents the payload of a webhook delivery failed event
+type WebhookDeliveryFailure struct {
+	Attempts  int    `json:"attempts"`
+	Error     string `json:"error"`
+	EventName string `json:"event_name"`
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+}
+
+// WebhookRetryPolicy represents a webhook retry policy
+// Deliveries are retried when the request fails or when the status code is 408, 429 or 5xx
+type WebhookRetryPolicy struct {
+	// Delay before the first retry, doubled after every failed attempt. Default is 1s
+	Delay time.Duration
+	// Default is 1m
+	MaxDelay time.Duration
+	// Max number of times a delivery is retried. Default is 5, a negative value disables retries
+	MaxRetries int
+}
+
+// WebhookDispatcherOptions represents webhook dispatcher options
+type WebhookDispatcherOptions struct {
+	// Default client has a 10s timeout
+	Client *http.Client
+	Logger Logger
+	// Max number of deliveries waiting to be sent to a webhook. Once reached, new deliveries are dropped.
+	// Default is 1000
+	MaxPending int
+	Retry      WebhookRetryPolicy
+	Webhooks   []Webhook
+}
+
+// WebhookDispatcher represents an object capable of posting events to webhooks
+type WebhookDispatcher struct {
+	c       *http.Client
+	cp      *sync.Cond // Locks pending
+	eh      *EventHandler
+	l       Logger
+	o       WebhookDispatcherOptions
+	pending int
+	ws      []*webhook
+}
+
+type webhook struct {
+	c     chan webhookDelivery
+	names map[string]bool
+	w     Webhook
+}
+
+type webhookDelivery struct {
+	b []byte
+	d WebhookDelivery
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher fed by the events of the event handler
+// Events are only posted once Start has been called, and delivery failures emit an
+// astiencoder.webhook.delivery.failed event
+func NewWebhookDispatcher(o WebhookDispatcherOptions, eh *EventHandler) (d *WebhookDispatcher) {
+	// Default options
+	if o.MaxPending <= 0 {
+		o.MaxPending = 1000
+	}
+	if o.Retry.Delay <= 0 {
+		o.Retry.Delay = time.Second
+	}
+	if o.Retry.MaxDelay <= 0 {
+		o.Retry.MaxDelay = time.Minute
+	}
+	if o.Retry.MaxRetries == 0 {
+		o.Retry.MaxRetries = 5
+	}
+
+	// Create dispatcher
+	d = &WebhookDispatcher{
+		c:  o.Client,
+		cp: sync.NewCond(&sync.Mutex{}),
+		eh: eh,
+		l:  logger(o.Logger),
+		o:  o,
+	}
+	if d.c == nil {
+		d.c = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	// Loop through webhooks
+	for _, w := range o.Webhooks {
+		wh := &webhook{
+			c: make(chan webhookDelivery, o.MaxPending),
+			w: w,
+		}
+		if len(w.EventNames) > 0 {
+			wh.names = make(map[string]bool)
+			for _, n := range w.EventNames {
+				wh.names[n] = true
+			}
+		}
+		d.ws = append(d.ws, wh)
+	}
+
+	// Handle events
+	eh.AddForAll(func(e Event) bool {
+		d.handleEvent(e)
+		return false
+	})
+	return
+}
+
+func (d *WebhookDispatcher) handleEvent(e Event) {
+	// Events emitted by the dispatcher are not delivered to avoid failures feeding themselves
+	if e.Target == d {
+		return
+	}
+
+	// Loop through webhooks
+	var wd *webhookDelivery
+	for _, w := range d.ws {
+		// Event is not delivered to this webhook
+		if w.names != nil && !w.names[e.Name] {
+			continue
+		}
+
+		// Create delivery
+		if wd == nil {
+			var err error
+			if wd, err = newWebhookDelivery(e); err != nil {
+				d.l.Error("astiencoder: creating webhook delivery failed", LogField{Key: LogFieldError, Value: err})
+				return
+			}
+		}
+
+		// Queue
+		d.addPending(1)
+		select {
+		case w.c <- *wd:
+		default:
+			d.addPending(-1)
+			d.l.Warn("astiencoder: too many pending webhook deliveries, dropping delivery", LogField{Key: "url", Value: w.w.URL})
+		}
+	}
+}
+
+func newWebhookDelivery(e Event) (wd *webhookDelivery, err error) {
+	// Create id
+	b := make([]byte, 16)
+	if _, err = rand.Read(b); err != nil {
+		err = fmt.Errorf("astiencoder: creating id failed: %w", err)
+		return
+	}
+
+	// Create delivery
+	wd = &webhookDelivery{d: WebhookDelivery{
+		ControlEvent: ControlEvent{
+			Name:    e.Name,
+			Payload: serverEventPayload(e),
+		},
+		ID:   hex.EncodeToString(b),
+		Time: time.Now(),
+	}}
+
+	// Errors are not JSON friendly
+	if err, ok := e.Payload.(error); ok {
+		wd.d.Payload = err.Error()
+	}
+
+	// Add context
+	switch t := e.Target.(type) {
+	case *Workflow:
+		wd.d.Workflow = t.Name()
+	case Node:
+		wd.d.Node = t.Metadata().Name
+		wd.d.Workflow = nodeWorkflowName(t)
+	}
+
+	// Marshal
+	if wd.b, err = json.Marshal(wd.d); err != nil {
+		err = fmt.Errorf("astiencoder: marshaling failed: %w", err)
+		return
+	}
+	return
+}
+
+// Start posts events to the webhooks until the context is done
+// Deliveries of a webhook are posted one at a time, in the order events have been emitted
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	// Loop through webhooks
+	wg := &sync.WaitGroup{}
+	for _, w := range d.ws {
+		wg.Add(1)
+		go func(w *webhook) {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case wd := <-w.c:
+					d.deliver(ctx, w, wd)
+					d.addPending(-1)
+				}
+			}
+		}(w)
+	}
+
+	// Wait
+	wg.Wait()
+}
+
+func (d *WebhookDispatcher) addPending(delta int) {
+	d.cp.L.Lock()
+	defer d.cp.L.Unlock()
+	d.pending += delta
+	d.cp.Broadcast()
+}
+
+// Shutdown waits for the pending deliveries to be posted, retries included, within the context's deadline. Start
+// must still be running
+func (d *WebhookDispatcher) Shutdown(ctx context.Context) (err error) {
+	// Wake up when context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.cp.L.Lock()
+			d.cp.Broadcast()
+			d.cp.L.Unlock()
+		case <-done:
+		}
+	}()
+
+	// Wait for pending deliveries to be posted
+	d.cp.L.Lock()
+	defer d.cp.L.Unlock()
+	for d.pending > 0 && ctx.Err() == nil {
+		d.cp.Wait()
+	}
+
+	// Some deliveries are still pending
+	if d.pending > 0 {
+		err = fmt.Errorf("astiencoder: %d webhook deliveries are still pending: %w", d.pending, ctx.Err())
+	}
+	return
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, w *webhook, wd webhookDelivery) {
+	// Loop
+	delay := d.o.Retry.Delay
+	for attempt := 1; ; attempt++ {
+		// Send
+		retry, err := d.send(ctx, w.w, wd.b)
+		if err == nil {
+			return
+		}
+
+		// Context is done
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Delivery has failed
+		if !retry || d.o.Retry.MaxRetries < 0 || attempt > d.o.Retry.MaxRetries {
+			d.eh.Emit(Event{
+				Name: EventNameWebhookDeliveryFailed,
+				Payload: WebhookDeliveryFailure{
+					Attempts:  attempt,
+					Error:     err.Error(),
+					EventName: wd.d.Name,
+					ID:        wd.d.ID,
+					URL:       w.w.URL,
+				},
+				Target: d,
+			})
+			return
+		}
+
+		// Wait
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		// Increase delay
+		if delay *= 2; delay > d.o.Retry.MaxDelay {
+			delay = d.o.Retry.MaxDelay
+		}
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, w Webhook, b []byte) (retry bool, err error) {
+	// Create request
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(b)); err != nil {
+		err = fmt.Errorf("astiencoder: creating request failed: %w", err)
+		return
+	}
+	for k, vs := range w.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookDelivery(w.Secret, b))
+	}
+
+	// Send
+	var resp *http.Response
+	if resp, err = d.c.Do(req); err != nil {
+		retry = true
+		err = fmt.Errorf("astiencoder: sending request failed: %w", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry = resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		err = fmt.Errorf("astiencoder: invalid status code %d", resp.StatusCode)
+		return
+	}
+	return
+}
+
+// SignWebhookDelivery returns the signature of a webhook delivery's body, as sent in the X-Astiencoder-Signature
+// header
+func SignWebhookDelivery(secret string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyWebhookDelivery checks, in constant time, the signature of a webhook delivery's body
+func VerifyWebhookDelivery(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookDelivery(secret, body)), []byte(signature))
+}
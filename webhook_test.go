@@ -0,0 +1,141 @@
+package astiencoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	// Create receivers
+	m := &sync.Mutex{}
+	var ds []WebhookDelivery
+	var attempts int
+	delivered := make(chan bool, 10)
+	s1 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Read body
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		// Check signature
+		if !VerifyWebhookDelivery("secret", b, r.Header.Get(WebhookSignatureHeader)) {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// First attempt fails
+		m.Lock()
+		defer m.Unlock()
+		if attempts++; attempts == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		// Store delivery
+		var d WebhookDelivery
+		assert.NoError(t, json.Unmarshal(b, &d))
+		assert.Equal(t, "test", r.Header.Get("X-Test"))
+		ds = append(ds, d)
+		delivered <- true
+	}))
+	defer s1.Close()
+	s2 := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer s2.Close()
+
+	// Create dispatcher
+	eh := NewEventHandler()
+	d := NewWebhookDispatcher(WebhookDispatcherOptions{
+		Retry: WebhookRetryPolicy{Delay: time.Millisecond},
+		Webhooks: []Webhook{
+			{
+				EventNames: []string{EventNameError, EventNameWorkflowStarted},
+				Headers:    http.Header{"X-Test": []string{"test"}},
+				Secret:     "secret",
+				URL:        s1.URL,
+			},
+			{
+				EventNames: []string{EventNameWorkflowStopped},
+				URL:        s2.URL,
+			},
+		},
+	}, eh)
+	fs := make(chan WebhookDeliveryFailure, 10)
+	eh.AddForEventName(EventNameWebhookDeliveryFailed, func(e Event) bool {
+		fs <- e.Payload.(WebhookDeliveryFailure)
+		return false
+	})
+
+	// Start dispatcher
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	wait := func(ch chan bool) {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	// Emit events
+	w := NewWorkflow(context.Background(), "w", eh, nil, astikit.NewCloser())
+	n := newMockedStatsNode("n", eh)
+	n.ctx = withProfileLabels(context.Background(), ProfileLabelWorkflow, "w")
+	eh.Emit(Event{Name: EventNameWorkflowStarted, Target: w})
+	eh.Emit(Event{Name: EventNameWorkflowPaused, Target: w})
+	eh.Emit(EventError(n, errors.New("test")))
+	eh.Emit(Event{Name: EventNameWorkflowStopped, Target: w})
+
+	// Assert deliveries
+	wait(delivered)
+	wait(delivered)
+	m.Lock()
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, ds, 2)
+	assert.Equal(t, ControlEvent{Name: EventNameWorkflowStarted, Workflow: "w"}, ds[0].ControlEvent)
+	assert.Equal(t, ControlEvent{Name: EventNameError, Node: "n", Payload: "test", Workflow: "w"}, ds[1].ControlEvent)
+	assert.NotEqual(t, "", ds[0].ID)
+	assert.NotEqual(t, ds[0].ID, ds[1].ID)
+	assert.False(t, ds[0].Time.IsZero())
+	m.Unlock()
+
+	// Assert failures
+	select {
+	case f := <-fs:
+		assert.Equal(t, WebhookDeliveryFailure{
+			Attempts:  1,
+			Error:     "astiencoder: invalid status code 400",
+			EventName: EventNameWorkflowStopped,
+			ID:        f.ID,
+			URL:       s2.URL,
+		}, f)
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+}
+
+func TestVerifyWebhookDelivery(t *testing.T) {
+	s := SignWebhookDelivery("secret", []byte("body"))
+	assert.True(t, VerifyWebhookDelivery("secret", []byte("body"), s))
+	assert.False(t, VerifyWebhookDelivery("other", []byte("body"), s))
+	assert.False(t, VerifyWebhookDelivery("secret", []byte("other"), s))
+	assert.False(t, VerifyWebhookDelivery("secret", []byte("body"), ""))
+}
@@ -3,6 +3,7 @@ package astiencoder
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/asticode/go-astikit"
 )
@@ -11,17 +12,24 @@ import (
 type Workflow struct {
 	bn   *BaseNode
 	c    *astikit.Closer
+	ck   *workflowClock
 	ctx  context.Context
+	d    *workflowDefinition
 	e    *EventHandler
+	l    Logger
+	ma   *MemoryAccountant
 	name string
+	sh   *statsHistory
 	t    *astikit.Task
 	tf   CreateTaskFunc
+	tr   *Tracing
 }
 
 // NewWorkflow creates a new workflow
 func NewWorkflow(ctx context.Context, name string, e *EventHandler, tf CreateTaskFunc, c *astikit.Closer) (w *Workflow) {
 	w = &Workflow{
 		c:    c,
+		ck:   newWorkflowClock(),
 		ctx:  ctx,
 		e:    e,
 		name: name,
@@ -79,6 +87,13 @@ func (w *Workflow) StartNodesInSubTask(ns ...Node) (t *astikit.Task) {
 // WorkflowStartOptions represents workflow start options
 type WorkflowStartOptions struct {
 	Groups []WorkflowStartGroup
+	// If > 0, the workflow emits an EventNameWorkflowHeartbeat event at this period until it is stopped, so that
+	// supervisors can tell a wedged process (no heartbeats) from a cleanly stopped one
+	HeartbeatPeriod time.Duration
+	// If > 0, the workflow emits the last stats of all its nodes in one EventNameWorkflowStatsAggregated event at
+	// this period. Nodes still compute their stats at their own period (see NodeOptions.StatsPeriod)
+	// If the duration of the inputs is known, an EventNameWorkflowProgress event is emitted at the same period
+	StatsPeriod time.Duration
 }
 
 // WorkflowStartGroup represents a workflow start group
@@ -89,12 +104,17 @@ type WorkflowStartGroup struct {
 
 // Start starts the workflow
 func (w *Workflow) Start() {
-	w.start(w.nodes(), WorkflowStartOptions{})
+	w.startWithActor(WorkflowStartOptions{}, "")
 }
 
 // StartWithOptions starts the workflow with options
 func (w *Workflow) StartWithOptions(o WorkflowStartOptions) {
+	w.startWithActor(o, "")
+}
+
+func (w *Workflow) startWithActor(o WorkflowStartOptions, actor string) {
 	w.start(w.nodes(), o)
+	w.audit(nil, actor, AuditActionStart, nil, nil)
 }
 
 type workflowStartGroup struct {
@@ -104,10 +124,43 @@ type workflowStartGroup struct {
 }
 
 func (w *Workflow) start(ns []Node, o WorkflowStartOptions) {
-	w.bn.Start(w.ctx, w.tf, func(t *astikit.Task) {
+	// Label the workflow's goroutines
+	ctx := withProfileLabels(w.ctx, ProfileLabelWorkflow, w.name)
+
+	// Make the logger available to nodes
+	if w.l != nil {
+		ctx = context.WithValue(ctx, loggerContextKey{}, LoggerWithFields(w.l, LogField{Key: LogFieldWorkflow, Value: w.name}))
+	}
+
+	// Make the memory accountant available to nodes
+	if w.ma != nil {
+		ctx = context.WithValue(ctx, memoryAccountantContextKey{}, w.ma)
+	}
+
+	// Trace the workflow and make the tracing available to nodes
+	var span Span
+	if w.tr != nil {
+		ctx, span = w.tr.Start(context.WithValue(ctx, tracingContextKey{}, w.tr), SpanNameWorkflow, SpanAttribute{Key: SpanAttributeWorkflow, Value: w.name})
+	}
+
+	// Start
+	w.bn.Start(ctx, w.tf, func(t *astikit.Task) {
 		// Store task
 		w.t = t
 
+		// Start clock
+		w.ck.start()
+
+		// Emit aggregated stats and progress
+		if o.StatsPeriod > 0 {
+			go w.startStats(w.bn.Context(), o.StatsPeriod)
+		}
+
+		// Emit heartbeats
+		if o.HeartbeatPeriod > 0 {
+			go w.startHeartbeat(w.bn.Context(), o.HeartbeatPeriod)
+		}
+
 		// Index groups
 		var gs []*workflowStartGroup
 		ngs := make(map[Node]*workflowStartGroup)
@@ -143,6 +196,11 @@ func (w *Workflow) start(ns []Node, o WorkflowStartOptions) {
 		// Wait for task to be done
 		t.Wait()
 
+		// End span
+		if span != nil {
+			span.End()
+		}
+
 		// Close
 		if err := w.c.Close(); err != nil {
 			w.e.Emit(EventError(w, fmt.Errorf("astiencoder: closing workflow %s failed: %w", w.name, err)))
@@ -152,25 +210,42 @@ func (w *Workflow) start(ns []Node, o WorkflowStartOptions) {
 
 // Stop stops the workflow
 func (w *Workflow) Stop() {
+	w.stop("")
+}
+
+func (w *Workflow) stop(actor string) {
 	w.bn.Stop()
+	w.audit(nil, actor, AuditActionStop, nil, nil)
 }
 
 // Pause pauses the workflow
 func (w *Workflow) Pause() {
+	w.pause("")
+}
+
+func (w *Workflow) pause(actor string) {
 	w.bn.pauseFunc(func() {
+		w.ck.pause()
 		for _, n := range w.nodes() {
 			n.Pause()
 		}
 	})
+	w.audit(nil, actor, AuditActionPause, nil, nil)
 }
 
 // Continue continues the workflow
 func (w *Workflow) Continue() {
+	w.resume("")
+}
+
+func (w *Workflow) resume(actor string) {
 	w.bn.continueFunc(func() {
 		for _, n := range w.nodes() {
 			n.Continue()
 		}
+		w.ck.resume()
 	})
+	w.audit(nil, actor, AuditActionContinue, nil, nil)
 }
 
 // AddChild adds a child to the workflow
@@ -18,7 +18,7 @@ type WorkflowRecording struct {
 	c      *astikit.Chan
 	cancel context.CancelFunc
 	ctx    context.Context
-	l      astikit.SeverityLogger
+	l      Logger
 	o      WorkflowRecordingOptions
 	path   string
 	w      *csv.Writer
@@ -27,7 +27,7 @@ type WorkflowRecording struct {
 
 type WorkflowRecordingOptions struct {
 	Dst    string
-	Logger astikit.StdLogger
+	Logger Logger
 }
 
 func (w *Workflow) NewRecording(o WorkflowRecordingOptions) (r *WorkflowRecording) {
@@ -36,7 +36,7 @@ func (w *Workflow) NewRecording(o WorkflowRecordingOptions) (r *WorkflowRecordin
 		c: astikit.NewChan(astikit.ChanOptions{
 			ProcessAll: true,
 		}),
-		l:  astikit.AdaptStdLogger(o.Logger),
+		l:  logger(o.Logger),
 		o:  o,
 		wf: w,
 	}
@@ -50,7 +50,7 @@ func (w *Workflow) NewRecording(o WorkflowRecordingOptions) (r *WorkflowRecordin
 
 		// Write
 		if err := r.write(name, payload); err != nil {
-			r.l.Error(fmt.Errorf("astiencoder: writing to recording failed: %w", err))
+			r.l.Error("astiencoder: writing to recording failed", LogField{Key: LogFieldError, Value: err})
 		}
 	})
 	return